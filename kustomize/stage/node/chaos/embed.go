@@ -0,0 +1,33 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package chaos contains the chaos stages of the node for kwok.
+package chaos
+
+import (
+	_ "embed"
+)
+
+var (
+	// DefaultNodeOutage is the default node outage yaml, which flips the node to NotReady.
+	//go:embed node-outage.yaml
+	DefaultNodeOutage string
+
+	// DefaultNodeOutageRecover is the default node outage recover yaml,
+	// which flips the node back to Ready after the duration of the outage.
+	//go:embed node-outage-recover.yaml
+	DefaultNodeOutageRecover string
+)
@@ -125,6 +125,7 @@ type KwokConfigurationOptions struct {
 	EnableDebuggingHandlers *bool `json:"enableDebuggingHandlers,omitempty"`
 
 	// enableContentionProfiling enables lock contention profiling, if enableDebuggingHandlers is true.
+	// is the default value for flag --enable-contention-profiling
 	// +default=false
 	EnableContentionProfiling *bool `json:"enableContentionProfiling,omitempty"`
 
@@ -132,18 +133,197 @@ type KwokConfigurationOptions struct {
 	// +default=true
 	EnableProfilingHandler *bool `json:"enableProfilingHandler,omitempty"`
 
+	// ProfilingAddress is the address to serve the /debug/pprof on a dedicated listener,
+	// if enableDebuggingHandlers and enableProfilingHandler are true, so that it can be exposed separately from the server.
+	// is the default value for flag --profiling-address
+	ProfilingAddress string `json:"profilingAddress,omitempty"`
+
 	// PodPlayStageParallelism is the number of PodPlayStages that are allowed to run in parallel.
 	// +default=4
 	PodPlayStageParallelism uint `json:"podPlayStageParallelism,omitempty"`
 
+	// PodPlayStageParallelismRampMilliseconds is the window over which the PodPlayStages workers
+	// are started one by one, to smooth the initial load on the apiserver.
+	// If it is zero, all workers are started at once.
+	PodPlayStageParallelismRampMilliseconds int64 `json:"podPlayStageParallelismRampMilliseconds,omitempty"`
+
+	// PodPlayStageParallelismRampJitterMilliseconds is the maximum random delay
+	// added to the start of each PodPlayStages worker during the ramp.
+	PodPlayStageParallelismRampJitterMilliseconds int64 `json:"podPlayStageParallelismRampJitterMilliseconds,omitempty"`
+
+	// PodStatusUpdateParallelism is the number of pod updates, patches and deletes,
+	// that are allowed to be sent to the apiserver in parallel by the PodPlayStages workers.
+	// It caps the load on the apiserver independently of PodPlayStageParallelism.
+	// If it is zero, the updates are only limited by PodPlayStageParallelism.
+	PodStatusUpdateParallelism uint `json:"podStatusUpdateParallelism,omitempty"`
+
+	// PodPreprocessParallelism is the number of workers that match the pods to the PodPlayStages in parallel.
+	// The events of the same pod are always handled by the same worker.
+	// +default=1
+	PodPreprocessParallelism uint `json:"podPreprocessParallelism,omitempty"`
+
 	// NodePlayStageParallelism is the number of NodePlayStages that are allowed to run in parallel.
+	// is the default value for flag --manage-nodes-parallelism
 	// +default=4
 	NodePlayStageParallelism uint `json:"nodePlayStageParallelism,omitempty"`
 
+	// NodePreprocessParallelism is the number of workers that match the nodes to the NodePlayStages in parallel.
+	// The events of the same node are always handled by the same worker.
+	// +default=1
+	NodePreprocessParallelism uint `json:"nodePreprocessParallelism,omitempty"`
+
 	// NodeLeaseDurationSeconds is the duration the Kubelet will set on its corresponding Lease.
 	NodeLeaseDurationSeconds uint `json:"nodeLeaseDurationSeconds,omitempty"`
 
 	// NodeLeaseParallelism is the number of NodeLeases that are allowed to be processed in parallel.
 	// +default=4
 	NodeLeaseParallelism uint `json:"nodeLeaseParallelism,omitempty"`
+
+	// NodeLeaseRenewIntervalSeconds is the interval to renew the node leases,
+	// which must not be greater than the lease duration.
+	// If it is zero, a quarter of the lease duration is used like the kubelet.
+	// is the default value for flag --node-lease-renew-interval-seconds
+	NodeLeaseRenewIntervalSeconds uint `json:"nodeLeaseRenewIntervalSeconds,omitempty"`
+
+	// NodeLeaseRenewIntervalJitter is the factor of the random jitter added to the renew interval of the node leases,
+	// which spreads out the renewals of a large number of nodes.
+	// If it is zero, 0.04 is used like the kubelet.
+	// is the default value for flag --node-lease-renew-interval-jitter
+	NodeLeaseRenewIntervalJitter float64 `json:"nodeLeaseRenewIntervalJitter,omitempty"`
+
+	// EnableStageWebhook enables the webhook of the stage next,
+	// which sends the resource to an external HTTP endpoint.
+	// is the default value for flag --enable-stage-webhook
+	// +default=false
+	EnableStageWebhook *bool `json:"enableStageWebhook,omitempty"`
+
+	// EnableNodeVolumeStatus enables reporting the volumes used by the pods on a node
+	// in the status.volumesInUse and status.volumesAttached of the node,
+	// which is useful for testing the attach/detach controller and CSI drivers.
+	// It resolves the PersistentVolumeClaims of the pods, so it adds reconciliation cost.
+	// is the default value for flag --enable-node-volume-status
+	// +default=false
+	EnableNodeVolumeStatus *bool `json:"enableNodeVolumeStatus,omitempty"`
+
+	// EnableNodeMemoryPressureEviction enables evicting the pods on a node
+	// whose MemoryPressure condition is true, one pod each time the node is updated,
+	// in the order of QoS class and priority like kubelet does.
+	// It keeps a cache of the pods, so it adds memory cost.
+	// is the default value for flag --enable-node-memory-pressure-eviction
+	// +default=false
+	EnableNodeMemoryPressureEviction *bool `json:"enableNodeMemoryPressureEviction,omitempty"`
+
+	// DisregardFinalizers makes the stages delete the nodes and pods
+	// without waiting for the finalizers added by other controllers to be removed.
+	// By default, a resource being deleted stays terminating until only the finalizers
+	// with the kwok.x-k8s.io/ prefix are left.
+	// is the default value for flag --disregard-finalizers
+	// +default=false
+	DisregardFinalizers *bool `json:"disregardFinalizers,omitempty"`
+
+	// PodAdmissionFailurePolicy is what to do with the pods that do not fit their node,
+	// when the node does not match the node selector or required node affinity,
+	// the requests exceed the allocatable resources or a NoExecute taint is not tolerated.
+	// Ignore plays the stages for all pods, Pending leaves the pods Pending with the reason
+	// until the node or the other pods on it change to make room for them,
+	// and Fail fails the pods like the kubelet does.
+	// is the default value for flag --pod-admission-failure-policy
+	// +default="Ignore"
+	PodAdmissionFailurePolicy string `json:"podAdmissionFailurePolicy,omitempty"`
+
+	// RBACSelfCheckPolicy is what to do if the permissions required by kwok are missing,
+	// which are reviewed with SelfSubjectAccessReviews on startup.
+	// Ignore skips the check, Warn logs the missing permissions and Fail exits with them.
+	// is the default value for flag --rbac-self-check-policy
+	// +default="Warn"
+	RBACSelfCheckPolicy string `json:"rbacSelfCheckPolicy,omitempty"`
+
+	// NodeCPUOvercommit is the factor to scale the cpu allocatable of the nodes relative to the capacity,
+	// a factor greater than 1 packs more pods onto the nodes than the capacity allows.
+	// It is applied when the stages set the allocatable, and is disabled if it is zero.
+	// is the default value for flag --node-cpu-overcommit
+	NodeCPUOvercommit float64 `json:"nodeCPUOvercommit,omitempty"`
+
+	// NodeMemoryOvercommit is the factor to scale the memory allocatable of the nodes relative to the capacity,
+	// a factor greater than 1 packs more pods onto the nodes than the capacity allows.
+	// It is applied when the stages set the allocatable, and is disabled if it is zero.
+	// is the default value for flag --node-memory-overcommit
+	NodeMemoryOvercommit float64 `json:"nodeMemoryOvercommit,omitempty"`
+
+	// GlobalDelayJitterMilliseconds is the maximum random delay added to the delay of all stages,
+	// on top of the delay and jitter of each stage, to simulate a noisy cluster.
+	// If it is zero, no delay is added.
+	// is the default value for flag --global-delay-jitter-milliseconds
+	GlobalDelayJitterMilliseconds int64 `json:"globalDelayJitterMilliseconds,omitempty"`
+
+	// FaultInjectionErrorRate is the probability, between 0 and 1, that a write request
+	// from the controller to the apiserver fails with a transient error.
+	// It is used for resilience testing and is disabled if it is zero.
+	FaultInjectionErrorRate float64 `json:"faultInjectionErrorRate,omitempty"`
+
+	// FaultInjectionLatencyMilliseconds is the latency added to each write request
+	// from the controller to the apiserver.
+	// It is used for resilience testing and is disabled if it is zero.
+	FaultInjectionLatencyMilliseconds int64 `json:"faultInjectionLatencyMilliseconds,omitempty"`
+
+	// EnableClientTransportTuning replaces the transport of the client to the apiserver
+	// with one tuned by the ClientTransport options, for the large simulations
+	// where the controller sends many concurrent requests to the apiserver.
+	// The unset options default to 100 idle connections per host,
+	// a TCP keep-alive of 30 seconds and an idle connection timeout of 90 seconds.
+	// is the default value for flag --enable-client-transport-tuning
+	// +default=false
+	EnableClientTransportTuning *bool `json:"enableClientTransportTuning,omitempty"`
+
+	// ClientTransportMaxIdleConnsPerHost is the maximum number of idle connections kept to the apiserver.
+	// It is only used if EnableClientTransportTuning is true.
+	ClientTransportMaxIdleConnsPerHost int `json:"clientTransportMaxIdleConnsPerHost,omitempty"`
+
+	// ClientTransportMaxConnsPerHost is the maximum number of connections to the apiserver,
+	// the requests beyond it wait for a connection to be available.
+	// It is only used if EnableClientTransportTuning is true, and is unlimited if it is zero.
+	ClientTransportMaxConnsPerHost int `json:"clientTransportMaxConnsPerHost,omitempty"`
+
+	// ClientTransportKeepAliveSeconds is the interval of the TCP keep-alive probes of the connections to the apiserver.
+	// It is only used if EnableClientTransportTuning is true.
+	ClientTransportKeepAliveSeconds int64 `json:"clientTransportKeepAliveSeconds,omitempty"`
+
+	// ClientTransportIdleConnTimeoutSeconds is how long an idle connection to the apiserver is kept before it is closed.
+	// It is only used if EnableClientTransportTuning is true.
+	ClientTransportIdleConnTimeoutSeconds int64 `json:"clientTransportIdleConnTimeoutSeconds,omitempty"`
+
+	// ClientTransportDisableHTTP2 disables HTTP/2 to the apiserver.
+	// With HTTP/2 all the requests are multiplexed over a single connection,
+	// which is limited by the max concurrent streams of the apiserver (--http2-max-streams-per-connection),
+	// with HTTP/1.1 they are spread over up to ClientTransportMaxConnsPerHost connections.
+	// It is only used if EnableClientTransportTuning is true.
+	ClientTransportDisableHTTP2 bool `json:"clientTransportDisableHTTP2,omitempty"`
+
+	// DisableMetricsFor is a list of the metric dimensions to disable, any of node, pod or container.
+	// The metrics of the listed dimensions are neither registered nor emitted,
+	// which keeps the cardinality down in large scale tests.
+	// is the default value for flag --disable-metrics-for
+	DisableMetricsFor []string `json:"disableMetricsFor,omitempty"`
+
+	// PodDNSPolicy is the dnsPolicy set on the created pods that have the default one and no dnsConfig,
+	// by the mutating admission webhook served at /mutate/pods/dns.
+	// It is one of ClusterFirstWithHostNet, ClusterFirst, Default or None.
+	// is the default value for flag --pod-dns-policy
+	PodDNSPolicy string `json:"podDNSPolicy,omitempty"`
+
+	// PodDNSNameservers is the nameservers of the dnsConfig set on the created pods that do not have one,
+	// by the mutating admission webhook served at /mutate/pods/dns.
+	// is the default value for flag --pod-dns-nameservers
+	PodDNSNameservers []string `json:"podDNSNameservers,omitempty"`
+
+	// PodDNSSearches is the search domains of the dnsConfig set on the created pods that do not have one,
+	// by the mutating admission webhook served at /mutate/pods/dns.
+	// is the default value for flag --pod-dns-searches
+	PodDNSSearches []string `json:"podDNSSearches,omitempty"`
+
+	// PodDNSOptions is the options of the dnsConfig set on the created pods that do not have one,
+	// by the mutating admission webhook served at /mutate/pods/dns.
+	// Each option is in the form of name or name:value, e.g. ndots:5.
+	// is the default value for flag --pod-dns-options
+	PodDNSOptions []string `json:"podDNSOptions,omitempty"`
 }
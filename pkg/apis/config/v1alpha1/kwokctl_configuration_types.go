@@ -71,10 +71,19 @@ type ComponentPatches struct {
 	Name string `json:"name"`
 	// ExtraArgs is the extra args to be patched on the component.
 	ExtraArgs []ExtraArgs `json:"extraArgs,omitempty"`
-	// ExtraVolumes is the extra volumes to be patched on the component.
+	// ExtraVolumes is the extra volumes to be patched on the component,
+	// which must not shadow the volumes mounted by kwokctl.
 	ExtraVolumes []Volume `json:"extraVolumes,omitempty"`
 	// ExtraEnvs is the extra environment variables to be patched on the component.
 	ExtraEnvs []Env `json:"extraEnvs,omitempty"`
+	// Readiness overrides the readiness check of the component.
+	// It is ignored by the kind runtime, which reports the readiness of the pods of the components.
+	Readiness *ComponentReadiness `json:"readiness,omitempty"`
+	// CommandOverride replaces the command of the component, e.g. to wrap etcd with strace for debugging.
+	// It is intended for advanced debugging only, and ignored by the binary runtime and the components in the kind node.
+	CommandOverride []string `json:"commandOverride,omitempty"`
+	// RestartPolicy overrides the restart policy of the component.
+	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty"`
 }
 
 // KwokctlConfigurationOptions holds information about the options.
@@ -91,10 +100,22 @@ type KwokctlConfigurationOptions struct {
 	// is the default value for flag --kube-apiserver-insecure-port and env KWOK_KUBE_APISERVER_INSECURE_PORT
 	KubeApiserverInsecurePort uint32 `json:"kubeApiserverInsecurePort,omitempty"`
 
+	// KubeApiserverReadOnlyPort is the port to expose the legacy insecure port of the apiserver itself,
+	// which serves without authentication and authorization, for the legacy tooling that reads from it.
+	// Deprecated: it is insecure, and only available with the secure port before Kubernetes 1.20.0,
+	// the insecure port of the apiserver has been removed since then.
+	// is the default value for flag --readonly-port and env KWOK_KUBE_APISERVER_READONLY_PORT
+	KubeApiserverReadOnlyPort uint32 `json:"kubeApiserverReadOnlyPort,omitempty"`
+
 	// InsecureKubeconfig is the flag to use insecure kubeconfig.
 	// only available when KubeApiserverInsecurePort is set.
 	InsecureKubeconfig bool `json:"insecureKubeconfig,omitempty"`
 
+	// KubeApiserverUnixSocketProxy is the flag to expose apiserver over a unix socket in the workdir.
+	// is the default value for flag --apiserver-proxy and env KWOK_KUBE_APISERVER_UNIX_SOCKET_PROXY
+	// only available for binary runtime.
+	KubeApiserverUnixSocketProxy bool `json:"kubeApiserverUnixSocketProxy,omitempty"`
+
 	// Runtime is the runtime to use.
 	// is the default value for flag --runtime and env KWOK_RUNTIME
 	Runtime string `json:"runtime,omitempty"`
@@ -123,6 +144,11 @@ type KwokctlConfigurationOptions struct {
 	// is the default value for env KWOK_KUBE_VERSION
 	KubeVersion string `json:"kubeVersion,omitempty"`
 
+	// RuntimeKubeVersions is the version of Kubernetes to use for each runtime,
+	// the key is the name of the runtime.
+	// It is used when the KubeVersion is not specified.
+	RuntimeKubeVersions map[string]string `json:"runtimeKubeVersions,omitempty"`
+
 	// EtcdVersion is the version of Etcd to use.
 	// is the default value for env KWOK_ETCD_VERSION
 	EtcdVersion string `json:"etcdVersion,omitempty"`
@@ -158,6 +184,26 @@ type KwokctlConfigurationOptions struct {
 	// +default=false
 	QuietPull *bool `json:"quietPull,omitempty"`
 
+	// NetworkMTU is the MTU of the network created by the compose runtime,
+	// the runtime default is used if it is 0.
+	// is the default value for flag --network-mtu and env KWOK_NETWORK_MTU
+	NetworkMTU uint32 `json:"networkMTU,omitempty"`
+
+	// LogDriver is the logging driver of the containers created by the compose runtime,
+	// the runtime default is used if it is empty.
+	// is the default value for flag --log-driver and env KWOK_LOG_DRIVER
+	LogDriver string `json:"logDriver,omitempty"`
+
+	// LogOpts is the options of the logging driver, in the form of key=value.
+	// is the default value for flag --log-opt
+	LogOpts []string `json:"logOpts,omitempty"`
+
+	// KubeApiserverInClusterAddress is the host name or IP of kube-apiserver in the kubeconfig used by the components
+	// in the network of the compose runtime, a host name is checked to resolve within the network after the cluster starts,
+	// the name of the kube-apiserver container is used if it is empty.
+	// is the default value for flag --kube-apiserver-in-cluster-address and env KWOK_KUBE_APISERVER_IN_CLUSTER_ADDRESS
+	KubeApiserverInClusterAddress string `json:"kubeApiserverInClusterAddress,omitempty"`
+
 	// KubeSchedulerConfig is the configuration path for kube-scheduler.
 	// is the default value for flag --kube-scheduler-config and env KWOK_KUBE_SCHEDULER_CONFIG
 	KubeSchedulerConfig string `json:"kubeSchedulerConfig,omitempty"`
@@ -385,10 +431,44 @@ type KwokctlConfigurationOptions struct {
 	// is the default value for flag --kube-runtime-config and env KWOK_KUBE_RUNTIME_CONFIG
 	KubeRuntimeConfig string `json:"kubeRuntimeConfig,omitempty"`
 
+	// KubeServiceClusterIPRange is the CIDR range from which to assign service cluster IPs,
+	// a pair of CIDRs with different IP families separated by a comma is for dual-stack.
+	// is the default value for flag --kube-service-cluster-ip-range and env KWOK_KUBE_SERVICE_CLUSTER_IP_RANGE
+	KubeServiceClusterIPRange string `json:"kubeServiceClusterIPRange,omitempty"`
+
 	// KubeAuditPolicy is path to the file that defines the audit policy configuration
 	// is the default value for flag --kube-audit-policy and env KWOK_KUBE_AUDIT_POLICY
 	KubeAuditPolicy string `json:"kubeAuditPolicy,omitempty"`
 
+	// KubeAuditWebhook is the URL of the endpoint that the audit events are sent to,
+	// it requires KubeAuditPolicy.
+	// is the default value for flag --kube-audit-webhook and env KWOK_KUBE_AUDIT_WEBHOOK
+	KubeAuditWebhook string `json:"kubeAuditWebhook,omitempty"`
+
+	// KubeAuditLogMaxSize is the maximum size in megabytes of the audit log before it gets rotated,
+	// 0 means that the audit log is never rotated.
+	// is the default value for flag --kube-audit-log-maxsize and env KWOK_KUBE_AUDIT_LOG_MAXSIZE
+	// only available for binary runtime.
+	KubeAuditLogMaxSize int32 `json:"kubeAuditLogMaxSize,omitempty"`
+
+	// KubeAuditLogMaxAge is the maximum number of days to retain the rotated audit logs,
+	// 0 means that the rotated audit logs are not removed based on age.
+	// is the default value for flag --kube-audit-log-maxage and env KWOK_KUBE_AUDIT_LOG_MAXAGE
+	// only available for binary runtime.
+	KubeAuditLogMaxAge int32 `json:"kubeAuditLogMaxAge,omitempty"`
+
+	// KubeAuditLogMaxBackup is the maximum number of the rotated audit logs to retain,
+	// 0 means that the rotated audit logs are not removed based on number.
+	// is the default value for flag --kube-audit-log-maxbackup and env KWOK_KUBE_AUDIT_LOG_MAXBACKUP
+	// only available for binary runtime.
+	KubeAuditLogMaxBackup int32 `json:"kubeAuditLogMaxBackup,omitempty"`
+
+	// CABundle is path to the PEM bundle of the CAs trusted by the components for their outgoing TLS connections,
+	// e.g. the apiserver calling an OIDC provider or a webhook, it replaces the system trust store of the components.
+	// is the default value for flag --ca-bundle and env KWOK_CA_BUNDLE
+	// only available for binary and compose runtimes.
+	CABundle string `json:"caBundle,omitempty"`
+
 	// KubeAuthorization is the flag to enable authorization on secure port.
 	// is the default value for flag --kube-authorization and env KWOK_KUBE_AUTHORIZATION
 	KubeAuthorization *bool `json:"kubeAuthorization,omitempty"`
@@ -397,6 +477,54 @@ type KwokctlConfigurationOptions struct {
 	// is the default value for flag --kube-admission and env KWOK_KUBE_ADMISSION
 	KubeAdmission *bool `json:"kubeAdmission,omitempty"`
 
+	// KubeAdmissionPlugins is the comma-separated list of admission plugins to enable for kube-apiserver.
+	// If KubeAdmission is disabled, only these plugins are enabled, e.g. ResourceQuota,LimitRanger.
+	// If KubeAdmission is enabled, these plugins are enabled in addition to the default ones.
+	// is the default value for flag --kube-admission-plugins and env KWOK_KUBE_ADMISSION_PLUGINS
+	KubeAdmissionPlugins string `json:"kubeAdmissionPlugins,omitempty"`
+
+	// KubeApiserverTLSMinVersion is the minimum TLS version supported by kube-apiserver,
+	// one of VersionTLS10, VersionTLS11, VersionTLS12 or VersionTLS13.
+	// It is only valid with SecurePort.
+	// is the default value for flag --kube-apiserver-tls-min-version and env KWOK_KUBE_APISERVER_TLS_MIN_VERSION
+	KubeApiserverTLSMinVersion string `json:"kubeApiserverTLSMinVersion,omitempty"`
+
+	// KubeApiserverTLSCipherSuites is the comma-separated list of cipher suites for kube-apiserver,
+	// e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384.
+	// It is only valid with SecurePort.
+	// is the default value for flag --kube-apiserver-tls-cipher-suites and env KWOK_KUBE_APISERVER_TLS_CIPHER_SUITES
+	KubeApiserverTLSCipherSuites string `json:"kubeApiserverTLSCipherSuites,omitempty"`
+
+	// KubeApiserverRequestTimeout is the duration a handler of kube-apiserver must keep a request open before timing it out,
+	// e.g. 5m to let the long list requests finish at scale.
+	// is the default value for flag --kube-apiserver-request-timeout and env KWOK_KUBE_APISERVER_REQUEST_TIMEOUT
+	KubeApiserverRequestTimeout string `json:"kubeApiserverRequestTimeout,omitempty"`
+
+	// KubeApiserverMinRequestTimeout is the minimum duration a watch request of kube-apiserver is kept open,
+	// the actual timeout of each watch is randomized between it and twice of it, it is rounded down to seconds.
+	// is the default value for flag --kube-apiserver-min-request-timeout and env KWOK_KUBE_APISERVER_MIN_REQUEST_TIMEOUT
+	KubeApiserverMinRequestTimeout string `json:"kubeApiserverMinRequestTimeout,omitempty"`
+
+	// KubeApiserverPriorityAndFairness enables API Priority and Fairness of kube-apiserver,
+	// so the clients are throttled by the FlowSchemas and PriorityLevelConfigurations like a real cluster,
+	// it takes precedence over DisableQPSLimits for kube-apiserver and requires kube-apiserver 1.20 or later.
+	// is the default value for flag --kube-apiserver-priority-and-fairness and env KWOK_KUBE_APISERVER_PRIORITY_AND_FAIRNESS
+	// +default=false
+	KubeApiserverPriorityAndFairness *bool `json:"kubeApiserverPriorityAndFairness,omitempty"`
+
+	// KubeApiserverMaxRequestsInflight is the maximum number of non-mutating requests in flight of kube-apiserver,
+	// with API Priority and Fairness the sum of it and KubeApiserverMaxMutatingRequestsInflight is
+	// the total concurrency shared by the priority levels, 0 leaves the default of kube-apiserver.
+	// It is only valid with KubeApiserverPriorityAndFairness.
+	// is the default value for flag --kube-apiserver-max-requests-inflight and env KWOK_KUBE_APISERVER_MAX_REQUESTS_INFLIGHT
+	KubeApiserverMaxRequestsInflight uint32 `json:"kubeApiserverMaxRequestsInflight,omitempty"`
+
+	// KubeApiserverMaxMutatingRequestsInflight is the maximum number of mutating requests in flight of kube-apiserver,
+	// 0 leaves the default of kube-apiserver.
+	// It is only valid with KubeApiserverPriorityAndFairness.
+	// is the default value for flag --kube-apiserver-max-mutating-requests-inflight and env KWOK_KUBE_APISERVER_MAX_MUTATING_REQUESTS_INFLIGHT
+	KubeApiserverMaxMutatingRequestsInflight uint32 `json:"kubeApiserverMaxMutatingRequestsInflight,omitempty"`
+
 	// EtcdPeerPort is etcd peer port in the binary runtime
 	EtcdPeerPort uint32 `json:"etcdPeerPort,omitempty"`
 
@@ -416,6 +544,11 @@ type KwokctlConfigurationOptions struct {
 	// is the default value for flag --controller-port and env KWOK_CONTROLLER_PORT
 	KwokControllerPort uint32 `json:"kwokControllerPort,omitempty"`
 
+	// KwokControllerProfilingPort is kwok-controller profiling port that is exposed to the host,
+	// the /debug/pprof of kwok-controller is served on it if it is not zero.
+	// is the default value for flag --controller-profiling-port and env KWOK_CONTROLLER_PROFILING_PORT
+	KwokControllerProfilingPort uint32 `json:"kwokControllerProfilingPort,omitempty"`
+
 	// MetricsServerPort is metrics-server port that is exposed to the host.
 	MetricsServerPort uint32 `json:"metricsServerPort,omitempty"`
 
@@ -438,10 +571,27 @@ type KwokctlConfigurationOptions struct {
 	// +default=40
 	NodeLeaseDurationSeconds uint `json:"nodeLeaseDurationSeconds,omitempty"`
 
+	// ManageNodesParallelism is the number of the node stages that the kwok-controller plays in parallel,
+	// the default of the kwok-controller is used if it is zero.
+	// is the default value for flag --manage-nodes-parallelism and env KWOK_MANAGE_NODES_PARALLELISM
+	ManageNodesParallelism uint `json:"manageNodesParallelism,omitempty"`
+
 	// HeartbeatFactor is the scale factor for all about heartbeat.
 	// +default=5
 	HeartbeatFactor *float64 `json:"heartbeatFactor,omitempty"`
 
+	// NodeCPUOvercommit is the factor to scale the cpu allocatable of the nodes relative to the capacity,
+	// which packs more pods onto the nodes than the capacity allows if it is greater than 1.
+	// It is disabled if it is zero.
+	// is the default value for flag --cpu-overcommit
+	NodeCPUOvercommit float64 `json:"nodeCPUOvercommit,omitempty"`
+
+	// NodeMemoryOvercommit is the factor to scale the memory allocatable of the nodes relative to the capacity,
+	// which packs more pods onto the nodes than the capacity allows if it is greater than 1.
+	// It is disabled if it is zero.
+	// is the default value for flag --memory-overcommit
+	NodeMemoryOvercommit float64 `json:"nodeMemoryOvercommit,omitempty"`
+
 	// BindAddress is the address to bind to.
 	// +default="0.0.0.0"
 	BindAddress string `json:"bindAddress,omitempty"`
@@ -453,9 +603,71 @@ type KwokctlConfigurationOptions struct {
 	// +default=false
 	DisableQPSLimits *bool `json:"disableQPSLimits,omitempty"`
 
-	// EtcdQuotaBackendSize is the backend quota for etcd.
+	// EtcdQuotaBackendSize is the backend quota for etcd, e.g. 8Gi.
+	// It is rendered into --quota-backend-bytes, and 0 leaves the default quota of etcd.
+	// is the default value for flag --etcd-quota-backend-size and env KWOK_ETCD_QUOTA_BACKEND_SIZE
 	// +default="8Gi"
 	EtcdQuotaBackendSize string `json:"etcdQuotaBackendSize,omitempty"`
+
+	// EtcdUnsafeNoFsync disables fsync of etcd, which speeds up the writes a lot.
+	// WARNING: it is unsafe and may lose data on crash, only use it for disposable clusters.
+	// It requires etcd 3.5 or later.
+	// is the default value for flag --etcd-unsafe-no-fsync and env KWOK_ETCD_UNSAFE_NO_FSYNC
+	// +default=false
+	EtcdUnsafeNoFsync *bool `json:"etcdUnsafeNoFsync,omitempty"`
+
+	// EtcdHeartbeatInterval is the interval of the heartbeats of etcd, e.g. 100ms.
+	// is the default value for flag --etcd-heartbeat-interval and env KWOK_ETCD_HEARTBEAT_INTERVAL
+	EtcdHeartbeatInterval string `json:"etcdHeartbeatInterval,omitempty"`
+
+	// EtcdElectionTimeout is the timeout of the leader election of etcd, e.g. 1s.
+	// It is recommended to be 10 times of the heartbeat interval.
+	// is the default value for flag --etcd-election-timeout and env KWOK_ETCD_ELECTION_TIMEOUT
+	EtcdElectionTimeout string `json:"etcdElectionTimeout,omitempty"`
+
+	// EtcdExtraClientURLs is the extra URLs for etcd to listen on and advertise for the clients,
+	// in addition to the one on the bind address, e.g. http://10.0.0.1:2379.
+	// It allows the tools to reach etcd on other interfaces than the apiserver does.
+	// In the compose runtime, etcd listens in the container, and the ports of the URLs are published to it.
+	// It is not supported in the kind runtime.
+	// is the default value for flag --etcd-extra-client-urls
+	EtcdExtraClientURLs []string `json:"etcdExtraClientURLs,omitempty"`
+
+	// EtcdReplicas is the number of the members of etcd, the kube-apiserver connects to all of them.
+	// Only the first member is exposed to the host and used for the snapshots.
+	// It is not supported in the kind runtime.
+	// is the default value for flag --etcd-replicas and env KWOK_ETCD_REPLICAS
+	// +default=1
+	EtcdReplicas uint32 `json:"etcdReplicas,omitempty"`
+
+	// PodDNSPolicy is the dnsPolicy set by the kwok-controller on the created pods that have the default one and no dnsConfig.
+	// It is one of ClusterFirstWithHostNet, ClusterFirst, Default or None.
+	// is the default value for flag --pod-dns-policy
+	PodDNSPolicy string `json:"podDNSPolicy,omitempty"`
+
+	// PodDNSNameservers is the nameservers of the dnsConfig set by the kwok-controller on the created pods that do not have one.
+	// is the default value for flag --pod-dns-nameservers
+	PodDNSNameservers []string `json:"podDNSNameservers,omitempty"`
+
+	// PodDNSSearches is the search domains of the dnsConfig set by the kwok-controller on the created pods that do not have one.
+	// is the default value for flag --pod-dns-searches
+	PodDNSSearches []string `json:"podDNSSearches,omitempty"`
+
+	// PodDNSOptions is the options of the dnsConfig set by the kwok-controller on the created pods that do not have one,
+	// each option is in the form of name or name:value, e.g. ndots:5.
+	// is the default value for flag --pod-dns-options
+	PodDNSOptions []string `json:"podDNSOptions,omitempty"`
+
+	// AutoSnapshotInterval is the interval of the etcd snapshots saved in the background while the cluster is running,
+	// e.g. 10m, the first one is saved as soon as the cluster is started.
+	// The snapshots are saved into the snapshots directory of the workdir of the cluster.
+	// is the default value for flag --auto-snapshot-interval
+	AutoSnapshotInterval string `json:"autoSnapshotInterval,omitempty"`
+
+	// AutoSnapshotRetention is the number of the latest automatic snapshots to keep, the older ones are pruned.
+	// is the default value for flag --auto-snapshot-retention
+	// +default=6
+	AutoSnapshotRetention int `json:"autoSnapshotRetention,omitempty"`
 }
 
 // Component is a component of the cluster.
@@ -511,11 +723,37 @@ type Component struct {
 	// MetricsDiscovery is the metrics discovery of the component.
 	MetricsDiscovery *ComponentMetric `json:"metricsDiscovery,omitempty"`
 
+	// Readiness is the readiness check of the component,
+	// the component is ready once it is running if it is not set.
+	// It is only run by the binary and compose runtimes, the kind runtime
+	// reports the readiness of the pods of the components from the kubelet instead.
+	// +optional
+	Readiness *ComponentReadiness `json:"readiness,omitempty"`
+
+	// RestartPolicy is the policy to restart the container of the component when it exits,
+	// the container is restarted unless it is stopped if it is not set.
+	// It is only supported by the compose runtimes.
+	// +optional
+	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty"`
+
 	// Version is the version of the component.
 	// +optional
 	Version string `json:"version,omitempty"`
 }
 
+// RestartPolicy is the policy to restart the container of a component.
+// +enum
+type RestartPolicy string
+
+const (
+	// RestartPolicyAlways always restarts the container.
+	RestartPolicyAlways RestartPolicy = "Always"
+	// RestartPolicyOnFailure restarts the container only if it exits with a non-zero code.
+	RestartPolicyOnFailure RestartPolicy = "OnFailure"
+	// RestartPolicyNo never restarts the container.
+	RestartPolicyNo RestartPolicy = "No"
+)
+
 // Env represents an environment variable present in a Container.
 type Env struct {
 	// Name of the environment variable.
@@ -562,6 +800,36 @@ type ComponentMetric struct {
 	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
 }
 
+// ComponentReadiness represents a readiness check of a component,
+// either an HTTP GET on an endpoint of the component or a command run on the host.
+type ComponentReadiness struct {
+	// Scheme is the scheme of the readiness endpoint.
+	// +optional
+	Scheme string `json:"scheme,omitempty"`
+	// Host is the host of the readiness endpoint in the component,
+	// the port is forwarded from the component.
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Path is the path of the readiness endpoint.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// CertPath is the cert path of the readiness endpoint.
+	// +optional
+	CertPath string `json:"certPath,omitempty"`
+	// KeyPath is the key path of the readiness endpoint.
+	// +optional
+	KeyPath string `json:"keyPath,omitempty"`
+	// InsecureSkipVerify is the flag to skip verify the readiness endpoint.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// Command is run on the host if the Host is not set,
+	// the component is ready if it exits with zero.
+	// +optional
+	Command []string `json:"command,omitempty"`
+}
+
 // Protocol defines network protocols supported for things like component ports.
 // +enum
 type Protocol string
@@ -72,6 +72,11 @@ func (in *Component) DeepCopyInto(out *Component) {
 		*out = new(ComponentMetric)
 		**out = **in
 	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ComponentReadiness)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -121,6 +126,16 @@ func (in *ComponentPatches) DeepCopyInto(out *ComponentPatches) {
 		*out = make([]Env, len(*in))
 		copy(*out, *in)
 	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ComponentReadiness)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CommandOverride != nil {
+		in, out := &in.CommandOverride, &out.CommandOverride
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -134,6 +149,27 @@ func (in *ComponentPatches) DeepCopy() *ComponentPatches {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentReadiness) DeepCopyInto(out *ComponentReadiness) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentReadiness.
+func (in *ComponentReadiness) DeepCopy() *ComponentReadiness {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentReadiness)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Env) DeepCopyInto(out *Env) {
 	*out = *in
@@ -226,6 +262,51 @@ func (in *KwokConfigurationOptions) DeepCopyInto(out *KwokConfigurationOptions)
 		*out = new(bool)
 		**out = **in
 	}
+	if in.EnableStageWebhook != nil {
+		in, out := &in.EnableStageWebhook, &out.EnableStageWebhook
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableNodeVolumeStatus != nil {
+		in, out := &in.EnableNodeVolumeStatus, &out.EnableNodeVolumeStatus
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableNodeMemoryPressureEviction != nil {
+		in, out := &in.EnableNodeMemoryPressureEviction, &out.EnableNodeMemoryPressureEviction
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisregardFinalizers != nil {
+		in, out := &in.DisregardFinalizers, &out.DisregardFinalizers
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableClientTransportTuning != nil {
+		in, out := &in.EnableClientTransportTuning, &out.EnableClientTransportTuning
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableMetricsFor != nil {
+		in, out := &in.DisableMetricsFor, &out.DisableMetricsFor
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodDNSNameservers != nil {
+		in, out := &in.PodDNSNameservers, &out.PodDNSNameservers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodDNSSearches != nil {
+		in, out := &in.PodDNSSearches, &out.PodDNSSearches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodDNSOptions != nil {
+		in, out := &in.PodDNSOptions, &out.PodDNSOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -294,6 +375,13 @@ func (in *KwokctlConfigurationOptions) DeepCopyInto(out *KwokctlConfigurationOpt
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RuntimeKubeVersions != nil {
+		in, out := &in.RuntimeKubeVersions, &out.RuntimeKubeVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.SecurePort != nil {
 		in, out := &in.SecurePort, &out.SecurePort
 		*out = new(bool)
@@ -304,6 +392,11 @@ func (in *KwokctlConfigurationOptions) DeepCopyInto(out *KwokctlConfigurationOpt
 		*out = new(bool)
 		**out = **in
 	}
+	if in.LogOpts != nil {
+		in, out := &in.LogOpts, &out.LogOpts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.DisableKubeScheduler != nil {
 		in, out := &in.DisableKubeScheduler, &out.DisableKubeScheduler
 		*out = new(bool)
@@ -329,6 +422,11 @@ func (in *KwokctlConfigurationOptions) DeepCopyInto(out *KwokctlConfigurationOpt
 		*out = new(bool)
 		**out = **in
 	}
+	if in.KubeApiserverPriorityAndFairness != nil {
+		in, out := &in.KubeApiserverPriorityAndFairness, &out.KubeApiserverPriorityAndFairness
+		*out = new(bool)
+		**out = **in
+	}
 	if in.HeartbeatFactor != nil {
 		in, out := &in.HeartbeatFactor, &out.HeartbeatFactor
 		*out = new(float64)
@@ -344,6 +442,31 @@ func (in *KwokctlConfigurationOptions) DeepCopyInto(out *KwokctlConfigurationOpt
 		*out = new(bool)
 		**out = **in
 	}
+	if in.EtcdUnsafeNoFsync != nil {
+		in, out := &in.EtcdUnsafeNoFsync, &out.EtcdUnsafeNoFsync
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EtcdExtraClientURLs != nil {
+		in, out := &in.EtcdExtraClientURLs, &out.EtcdExtraClientURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodDNSNameservers != nil {
+		in, out := &in.PodDNSNameservers, &out.PodDNSNameservers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodDNSSearches != nil {
+		in, out := &in.PodDNSSearches, &out.PodDNSSearches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodDNSOptions != nil {
+		in, out := &in.PodDNSOptions, &out.PodDNSOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -61,12 +61,44 @@ func SetObjectDefaults_KwokConfiguration(in *KwokConfiguration) {
 	if in.Options.PodPlayStageParallelism == 0 {
 		in.Options.PodPlayStageParallelism = 4
 	}
+	if in.Options.PodPreprocessParallelism == 0 {
+		in.Options.PodPreprocessParallelism = 1
+	}
 	if in.Options.NodePlayStageParallelism == 0 {
 		in.Options.NodePlayStageParallelism = 4
 	}
+	if in.Options.NodePreprocessParallelism == 0 {
+		in.Options.NodePreprocessParallelism = 1
+	}
 	if in.Options.NodeLeaseParallelism == 0 {
 		in.Options.NodeLeaseParallelism = 4
 	}
+	if in.Options.EnableStageWebhook == nil {
+		var ptrVar1 bool = false
+		in.Options.EnableStageWebhook = &ptrVar1
+	}
+	if in.Options.EnableNodeVolumeStatus == nil {
+		var ptrVar1 bool = false
+		in.Options.EnableNodeVolumeStatus = &ptrVar1
+	}
+	if in.Options.EnableNodeMemoryPressureEviction == nil {
+		var ptrVar1 bool = false
+		in.Options.EnableNodeMemoryPressureEviction = &ptrVar1
+	}
+	if in.Options.DisregardFinalizers == nil {
+		var ptrVar1 bool = false
+		in.Options.DisregardFinalizers = &ptrVar1
+	}
+	if in.Options.PodAdmissionFailurePolicy == "" {
+		in.Options.PodAdmissionFailurePolicy = "Ignore"
+	}
+	if in.Options.RBACSelfCheckPolicy == "" {
+		in.Options.RBACSelfCheckPolicy = "Warn"
+	}
+	if in.Options.EnableClientTransportTuning == nil {
+		var ptrVar1 bool = false
+		in.Options.EnableClientTransportTuning = &ptrVar1
+	}
 }
 
 func SetObjectDefaults_KwokctlConfiguration(in *KwokctlConfiguration) {
@@ -89,6 +121,10 @@ func SetObjectDefaults_KwokctlConfiguration(in *KwokctlConfiguration) {
 	if in.Options.EtcdPrefix == "" {
 		in.Options.EtcdPrefix = "/registry"
 	}
+	if in.Options.KubeApiserverPriorityAndFairness == nil {
+		var ptrVar1 bool = false
+		in.Options.KubeApiserverPriorityAndFairness = &ptrVar1
+	}
 	if in.Options.KubeControllerManagerNodeMonitorPeriodMilliseconds == 0 {
 		in.Options.KubeControllerManagerNodeMonitorPeriodMilliseconds = 5000
 	}
@@ -115,6 +151,16 @@ func SetObjectDefaults_KwokctlConfiguration(in *KwokctlConfiguration) {
 	if in.Options.EtcdQuotaBackendSize == "" {
 		in.Options.EtcdQuotaBackendSize = "8Gi"
 	}
+	if in.Options.EtcdUnsafeNoFsync == nil {
+		var ptrVar1 bool = false
+		in.Options.EtcdUnsafeNoFsync = &ptrVar1
+	}
+	if in.Options.EtcdReplicas == 0 {
+		in.Options.EtcdReplicas = 1
+	}
+	if in.Options.AutoSnapshotRetention == 0 {
+		in.Options.AutoSnapshotRetention = 6
+	}
 	for i := range in.Components {
 		a := &in.Components[i]
 		for j := range a.Ports {
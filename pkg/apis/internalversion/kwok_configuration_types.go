@@ -89,15 +89,102 @@ type KwokConfigurationOptions struct {
 	// EnableProfiling enables /debug/pprof handler.
 	EnableProfilingHandler bool
 
+	// ProfilingAddress is the address to serve the /debug/pprof on a dedicated listener.
+	ProfilingAddress string
+
 	// PodPlayStageParallelism is the number of PodPlayStages that are allowed to run in parallel.
 	PodPlayStageParallelism uint
 
+	// PodPlayStageParallelismRampMilliseconds is the window over which the PodPlayStages workers are started one by one.
+	PodPlayStageParallelismRampMilliseconds int64
+
+	// PodPlayStageParallelismRampJitterMilliseconds is the maximum random delay added to the start of each PodPlayStages worker.
+	PodPlayStageParallelismRampJitterMilliseconds int64
+
+	// PodStatusUpdateParallelism is the number of pod updates that are allowed to be sent to the apiserver in parallel.
+	PodStatusUpdateParallelism uint
+
+	// PodPreprocessParallelism is the number of workers that match the pods to the PodPlayStages in parallel.
+	PodPreprocessParallelism uint
+
 	// NodePlayStageParallelism is the number of NodePlayStages that are allowed to run in parallel.
 	NodePlayStageParallelism uint
 
+	// NodePreprocessParallelism is the number of workers that match the nodes to the NodePlayStages in parallel.
+	NodePreprocessParallelism uint
+
 	// NodeLeaseDurationSeconds is the duration the Kubelet will set on its corresponding Lease.
 	NodeLeaseDurationSeconds uint
 
 	// NodeLeaseParallelism is the number of NodeLeases that are allowed to be processed in parallel.
 	NodeLeaseParallelism uint
+
+	// NodeLeaseRenewIntervalSeconds is the interval to renew the node leases.
+	NodeLeaseRenewIntervalSeconds uint
+
+	// NodeLeaseRenewIntervalJitter is the factor of the random jitter added to the renew interval of the node leases.
+	NodeLeaseRenewIntervalJitter float64
+
+	// EnableStageWebhook enables the webhook of the stage next.
+	EnableStageWebhook bool
+
+	// EnableNodeVolumeStatus enables reporting the volumes used by the pods on a node in the status of the node.
+	EnableNodeVolumeStatus bool
+
+	// EnableNodeMemoryPressureEviction enables evicting the pods on a node under memory pressure.
+	EnableNodeMemoryPressureEviction bool
+
+	// DisregardFinalizers makes the stages delete the nodes and pods without waiting for the finalizers of other controllers.
+	DisregardFinalizers bool
+
+	// PodAdmissionFailurePolicy is what to do with the pods that do not fit their node, one of Ignore, Pending or Fail.
+	PodAdmissionFailurePolicy string
+
+	// RBACSelfCheckPolicy is what to do if the permissions required by kwok are missing, one of Ignore, Warn or Fail.
+	RBACSelfCheckPolicy string
+
+	// NodeCPUOvercommit is the factor to scale the cpu allocatable of the nodes relative to the capacity.
+	NodeCPUOvercommit float64
+
+	// NodeMemoryOvercommit is the factor to scale the memory allocatable of the nodes relative to the capacity.
+	NodeMemoryOvercommit float64
+
+	// GlobalDelayJitterMilliseconds is the maximum random delay added to the delay of all stages.
+	GlobalDelayJitterMilliseconds int64
+
+	// FaultInjectionErrorRate is the probability that a write request to the apiserver fails with a transient error.
+	FaultInjectionErrorRate float64
+
+	// FaultInjectionLatencyMilliseconds is the latency added to each write request to the apiserver.
+	FaultInjectionLatencyMilliseconds int64
+
+	// EnableClientTransportTuning replaces the transport of the client to the apiserver with one tuned by the ClientTransport options.
+	EnableClientTransportTuning bool
+
+	// ClientTransportMaxIdleConnsPerHost is the maximum number of idle connections kept to the apiserver.
+	ClientTransportMaxIdleConnsPerHost int
+
+	// ClientTransportMaxConnsPerHost is the maximum number of connections to the apiserver.
+	ClientTransportMaxConnsPerHost int
+
+	// ClientTransportKeepAliveSeconds is the interval of the TCP keep-alive probes of the connections to the apiserver.
+	ClientTransportKeepAliveSeconds int64
+
+	// ClientTransportIdleConnTimeoutSeconds is how long an idle connection to the apiserver is kept before it is closed.
+	ClientTransportIdleConnTimeoutSeconds int64
+
+	// ClientTransportDisableHTTP2 disables HTTP/2 to the apiserver.
+	ClientTransportDisableHTTP2 bool
+
+	// DisableMetricsFor is a list of the metric dimensions to disable, any of node, pod or container.
+	DisableMetricsFor []string
+
+	// PodDNSPolicy is the dnsPolicy set on the created pods that have the default one and no dnsConfig.
+	PodDNSPolicy string
+	// PodDNSNameservers is the nameservers of the dnsConfig set on the created pods that do not have one.
+	PodDNSNameservers []string
+	// PodDNSSearches is the search domains of the dnsConfig set on the created pods that do not have one.
+	PodDNSSearches []string
+	// PodDNSOptions is the options of the dnsConfig set on the created pods that do not have one.
+	PodDNSOptions []string
 }
@@ -57,10 +57,18 @@ type ComponentPatches struct {
 	Name string
 	// ExtraArgs is the extra args to be patched on the component.
 	ExtraArgs []ExtraArgs
-	// ExtraVolumes is the extra volumes to be patched on the component.
+	// ExtraVolumes is the extra volumes to be patched on the component,
+	// which must not shadow the volumes mounted by kwokctl.
 	ExtraVolumes []Volume
 	// ExtraEnvs is the extra environment variables to be patched on the component.
 	ExtraEnvs []Env
+	// Readiness overrides the readiness check of the component.
+	// It is ignored by the kind runtime, which reports the readiness of the pods of the components.
+	Readiness *ComponentReadiness
+	// CommandOverride replaces the command of the component.
+	CommandOverride []string
+	// RestartPolicy overrides the restart policy of the component.
+	RestartPolicy RestartPolicy
 }
 
 // KwokctlConfigurationOptions holds information about the options.
@@ -74,10 +82,16 @@ type KwokctlConfigurationOptions struct {
 	// KubeApiserverInsecurePort is the port to expose kubectl proxy.
 	KubeApiserverInsecurePort uint32
 
+	// KubeApiserverReadOnlyPort is the port to expose the legacy insecure port of the apiserver itself.
+	KubeApiserverReadOnlyPort uint32
+
 	// InsecureKubeconfig is the flag to use insecure kubeconfig.
 	// only available when KubeApiserverInsecurePort is set.
 	InsecureKubeconfig bool
 
+	// KubeApiserverUnixSocketProxy is the flag to expose apiserver over a unix socket in the workdir.
+	KubeApiserverUnixSocketProxy bool
+
 	// Runtime is the runtime to use.
 	Runtime string
 
@@ -101,6 +115,9 @@ type KwokctlConfigurationOptions struct {
 	// KubeVersion is the version of Kubernetes to use.
 	KubeVersion string
 
+	// RuntimeKubeVersions is the version of Kubernetes to use for each runtime.
+	RuntimeKubeVersions map[string]string
+
 	// EtcdVersion is the version of Etcd to use.
 	EtcdVersion string
 
@@ -129,6 +146,19 @@ type KwokctlConfigurationOptions struct {
 	// QuietPull is the flag to quiet the pull.
 	QuietPull bool
 
+	// NetworkMTU is the MTU of the network created by the compose runtime.
+	NetworkMTU uint32
+
+	// LogDriver is the logging driver of the containers created by the compose runtime.
+	LogDriver string
+
+	// LogOpts is the options of the logging driver, in the form of key=value.
+	LogOpts []string
+
+	// KubeApiserverInClusterAddress is the host of kube-apiserver in the kubeconfig used by the components
+	// in the network of the compose runtime.
+	KubeApiserverInClusterAddress string
+
 	// KubeSchedulerConfig is the configuration path for kube-scheduler.
 	KubeSchedulerConfig string
 
@@ -239,15 +269,57 @@ type KwokctlConfigurationOptions struct {
 	// KubeRuntimeConfig is a set of key=value pairs that enable or disable built-in APIs.
 	KubeRuntimeConfig string
 
+	// KubeServiceClusterIPRange is the CIDR range from which to assign service cluster IPs.
+	KubeServiceClusterIPRange string
+
 	// KubeAuditPolicy is path to the file that defines the audit policy configuration
 	KubeAuditPolicy string
 
+	// KubeAuditWebhook is the URL of the endpoint that the audit events are sent to
+	KubeAuditWebhook string
+
+	// KubeAuditLogMaxSize is the maximum size in megabytes of the audit log before it gets rotated.
+	KubeAuditLogMaxSize int32
+
+	// KubeAuditLogMaxAge is the maximum number of days to retain the rotated audit logs.
+	KubeAuditLogMaxAge int32
+
+	// KubeAuditLogMaxBackup is the maximum number of the rotated audit logs to retain.
+	KubeAuditLogMaxBackup int32
+
+	// CABundle is path to the PEM bundle of the CAs trusted by the components for their outgoing TLS connections.
+	CABundle string
+
 	// KubeAuthorization is the flag to enable authorization on secure port.
 	KubeAuthorization bool
 
 	// KubeAdmission is the flag to enable admission for kube-apiserver.
 	KubeAdmission bool
 
+	// KubeAdmissionPlugins is the comma-separated list of admission plugins to enable for kube-apiserver.
+	KubeAdmissionPlugins string
+
+	// KubeApiserverTLSMinVersion is the minimum TLS version supported by kube-apiserver.
+	KubeApiserverTLSMinVersion string
+
+	// KubeApiserverTLSCipherSuites is the comma-separated list of cipher suites for kube-apiserver.
+	KubeApiserverTLSCipherSuites string
+
+	// KubeApiserverRequestTimeout is the duration a handler of kube-apiserver must keep a request open before timing it out.
+	KubeApiserverRequestTimeout string
+
+	// KubeApiserverMinRequestTimeout is the minimum duration a watch request of kube-apiserver is kept open.
+	KubeApiserverMinRequestTimeout string
+
+	// KubeApiserverPriorityAndFairness enables API Priority and Fairness of kube-apiserver.
+	KubeApiserverPriorityAndFairness bool
+
+	// KubeApiserverMaxRequestsInflight is the maximum number of non-mutating requests in flight of kube-apiserver.
+	KubeApiserverMaxRequestsInflight uint32
+
+	// KubeApiserverMaxMutatingRequestsInflight is the maximum number of mutating requests in flight of kube-apiserver.
+	KubeApiserverMaxMutatingRequestsInflight uint32
+
 	// EtcdPeerPort is etcd peer port in the binary runtime
 	EtcdPeerPort uint32
 
@@ -266,6 +338,9 @@ type KwokctlConfigurationOptions struct {
 	// KwokControllerPort is kwok-controller port that is exposed to the host.
 	KwokControllerPort uint32
 
+	// KwokControllerProfilingPort is kwok-controller profiling port that is exposed to the host.
+	KwokControllerProfilingPort uint32
+
 	// MetricsServerPort is metrics-server port that is exposed to the host.
 	MetricsServerPort uint32
 
@@ -284,9 +359,18 @@ type KwokctlConfigurationOptions struct {
 	// NodeLeaseDurationSeconds is the duration the Kubelet will set on its corresponding Lease.
 	NodeLeaseDurationSeconds uint
 
+	// ManageNodesParallelism is the number of the node stages that the kwok-controller plays in parallel.
+	ManageNodesParallelism uint
+
 	// HeartbeatFactor is the scale factor for all about heartbeat.
 	HeartbeatFactor float64
 
+	// NodeCPUOvercommit is the factor to scale the cpu allocatable of the nodes relative to the capacity.
+	NodeCPUOvercommit float64
+
+	// NodeMemoryOvercommit is the factor to scale the memory allocatable of the nodes relative to the capacity.
+	NodeMemoryOvercommit float64
+
 	// BindAddress is the address to bind to.
 	BindAddress string
 
@@ -298,6 +382,35 @@ type KwokctlConfigurationOptions struct {
 
 	// EtcdQuotaBackendSize is the backend quota for etcd.
 	EtcdQuotaBackendSize string
+
+	// EtcdUnsafeNoFsync disables fsync of etcd, only for disposable clusters.
+	EtcdUnsafeNoFsync bool
+
+	// EtcdHeartbeatInterval is the interval of the heartbeats of etcd.
+	EtcdHeartbeatInterval string
+
+	// EtcdElectionTimeout is the timeout of the leader election of etcd.
+	EtcdElectionTimeout string
+
+	// EtcdExtraClientURLs is the extra URLs for etcd to listen on and advertise for the clients.
+	EtcdExtraClientURLs []string
+
+	// EtcdReplicas is the number of the members of etcd.
+	EtcdReplicas uint32
+
+	// PodDNSPolicy is the dnsPolicy set by the kwok-controller on the created pods that have the default one and no dnsConfig.
+	PodDNSPolicy string
+	// PodDNSNameservers is the nameservers of the dnsConfig set by the kwok-controller on the created pods that do not have one.
+	PodDNSNameservers []string
+	// PodDNSSearches is the search domains of the dnsConfig set by the kwok-controller on the created pods that do not have one.
+	PodDNSSearches []string
+	// PodDNSOptions is the options of the dnsConfig set by the kwok-controller on the created pods that do not have one.
+	PodDNSOptions []string
+
+	// AutoSnapshotInterval is the interval of the etcd snapshots saved in the background while the cluster is running.
+	AutoSnapshotInterval string
+	// AutoSnapshotRetention is the number of the latest automatic snapshots to keep.
+	AutoSnapshotRetention int
 }
 
 // Component is a component of the cluster.
@@ -343,10 +456,29 @@ type Component struct {
 	// MetricsDiscovery is the metrics discovery of the component.
 	MetricsDiscovery *ComponentMetric
 
+	// Readiness is the readiness check of the component.
+	// It is only run by the binary and compose runtimes.
+	Readiness *ComponentReadiness
+
+	// RestartPolicy is the policy to restart the container of the component when it exits.
+	RestartPolicy RestartPolicy
+
 	// Version is the version of the component.
 	Version string
 }
 
+// RestartPolicy is the policy to restart the container of a component.
+type RestartPolicy string
+
+const (
+	// RestartPolicyAlways always restarts the container.
+	RestartPolicyAlways RestartPolicy = "Always"
+	// RestartPolicyOnFailure restarts the container only if it exits with a non-zero code.
+	RestartPolicyOnFailure RestartPolicy = "OnFailure"
+	// RestartPolicyNo never restarts the container.
+	RestartPolicyNo RestartPolicy = "No"
+)
+
 // Env represents an environment variable present in a Container.
 type Env struct {
 	// Name of the environment variable.
@@ -387,6 +519,26 @@ type ComponentMetric struct {
 	InsecureSkipVerify bool
 }
 
+// ComponentReadiness represents a readiness check of a component.
+type ComponentReadiness struct {
+	// Scheme is the scheme of the readiness endpoint.
+	Scheme string
+	// Host is the host of the readiness endpoint in the component.
+	Host string
+	// Path is the path of the readiness endpoint.
+	Path string
+
+	// CertPath is the cert path of the readiness endpoint.
+	CertPath string
+	// KeyPath is the key path of the readiness endpoint.
+	KeyPath string
+	// InsecureSkipVerify is the flag to skip verify the readiness endpoint.
+	InsecureSkipVerify bool
+
+	// Command is run on the host if the Host is not set.
+	Command []string
+}
+
 // Protocol defines network protocols supported for things like component ports.
 type Protocol string
 
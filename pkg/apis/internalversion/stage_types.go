@@ -48,6 +48,10 @@ type StageSpec struct {
 	Next StageNext
 	// ImmediateNextStage means that the next stage of matching is performed immediately, without waiting for the Apiserver to push.
 	ImmediateNextStage bool
+	// MaxCount limits how many times this stage is played for each resource,
+	// the stage is no longer matched for the resource once the limit is reached.
+	// Zero means unlimited.
+	MaxCount int
 }
 
 // StageResourceRef specifies the kind and version of the resource.
@@ -87,6 +91,47 @@ type StageNext struct {
 	Delete bool
 	// Patches means that the resource will be patched.
 	Patches []StagePatch
+	// Webhook means that the resource will be sent to an external HTTP endpoint,
+	// and the patch returned by the endpoint will be applied to the resource.
+	Webhook *StageWebhook
+	// Conditions means that the conditions of the resource will be computed from CEL expressions.
+	Conditions []StageCondition
+	// StatusMessage means that the reason and message in the status of the resource will be rendered from the templates and set.
+	StatusMessage *StageStatusMessage
+	// StartTimeBackdate means that the startTime in the status of the resource will be moved into the past.
+	StartTimeBackdate *StageStartTimeBackdate
+}
+
+// StageStatusMessage describes the reason and message in the status of the resource.
+type StageStatusMessage struct {
+	// Reason is the template of the reason in the status.
+	Reason string
+	// Message is the template of the message in the status.
+	Message string
+}
+
+// StageStartTimeBackdate describes how far the startTime in the status of the resource is moved into the past.
+type StageStartTimeBackdate struct {
+	// DurationMilliseconds indicates the duration the startTime is moved into the past.
+	DurationMilliseconds *int64
+	// DurationFrom is the expression used to get the value.
+	DurationFrom *ExpressionFromSource
+}
+
+// StageWebhook describes an external HTTP endpoint that participates in the stage.
+type StageWebhook struct {
+	// URL is the address that the resource will be POSTed to.
+	URL string
+	// Template indicates the template for the request body.
+	Template string
+	// TimeoutMilliseconds indicates the timeout of each request.
+	TimeoutMilliseconds *int64
+	// Retries indicates the number of retries when the request fails.
+	Retries int
+	// Subresource indicates the name of the subresource that will be patched.
+	Subresource string
+	// Type indicates the type of the patch returned by the webhook.
+	Type *StagePatchType
 }
 
 // StagePatch describes the patch for the resource.
@@ -140,6 +185,18 @@ type FinalizerItem struct {
 	Value string
 }
 
+// StageCondition describes a condition computed from a CEL expression.
+type StageCondition struct {
+	// Type is the type of the condition, e.g. Ready.
+	Type string
+	// Expression is the CEL expression over the resource, which must evaluate to a bool.
+	Expression string
+	// Reason is the reason of the condition when it is False.
+	Reason string
+	// Message is the message of the condition when it is False.
+	Message string
+}
+
 // StageEvent describes one event in the Kubernetes.
 type StageEvent struct {
 	// Type is the type of this event (Normal, Warning), It is machine-readable.
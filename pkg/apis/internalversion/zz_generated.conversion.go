@@ -200,6 +200,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ComponentReadiness)(nil), (*configv1alpha1.ComponentReadiness)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_internalversion_ComponentReadiness_To_v1alpha1_ComponentReadiness(a.(*ComponentReadiness), b.(*configv1alpha1.ComponentReadiness), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*configv1alpha1.ComponentReadiness)(nil), (*ComponentReadiness)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ComponentReadiness_To_internalversion_ComponentReadiness(a.(*configv1alpha1.ComponentReadiness), b.(*ComponentReadiness), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Env)(nil), (*configv1alpha1.Env)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_internalversion_Env_To_v1alpha1_Env(a.(*Env), b.(*configv1alpha1.Env), scope)
 	}); err != nil {
@@ -570,6 +580,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*StageCondition)(nil), (*v1alpha1.StageCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_internalversion_StageCondition_To_v1alpha1_StageCondition(a.(*StageCondition), b.(*v1alpha1.StageCondition), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.StageCondition)(nil), (*StageCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_StageCondition_To_internalversion_StageCondition(a.(*v1alpha1.StageCondition), b.(*StageCondition), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*StageDelay)(nil), (*v1alpha1.StageDelay)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_internalversion_StageDelay_To_v1alpha1_StageDelay(a.(*StageDelay), b.(*v1alpha1.StageDelay), scope)
 	}); err != nil {
@@ -635,6 +655,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*StageStartTimeBackdate)(nil), (*v1alpha1.StageStartTimeBackdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_internalversion_StageStartTimeBackdate_To_v1alpha1_StageStartTimeBackdate(a.(*StageStartTimeBackdate), b.(*v1alpha1.StageStartTimeBackdate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.StageStartTimeBackdate)(nil), (*StageStartTimeBackdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_StageStartTimeBackdate_To_internalversion_StageStartTimeBackdate(a.(*v1alpha1.StageStartTimeBackdate), b.(*StageStartTimeBackdate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*StageStatusMessage)(nil), (*v1alpha1.StageStatusMessage)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_internalversion_StageStatusMessage_To_v1alpha1_StageStatusMessage(a.(*StageStatusMessage), b.(*v1alpha1.StageStatusMessage), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.StageStatusMessage)(nil), (*StageStatusMessage)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_StageStatusMessage_To_internalversion_StageStatusMessage(a.(*v1alpha1.StageStatusMessage), b.(*StageStatusMessage), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*StageWebhook)(nil), (*v1alpha1.StageWebhook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_internalversion_StageWebhook_To_v1alpha1_StageWebhook(a.(*StageWebhook), b.(*v1alpha1.StageWebhook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.StageWebhook)(nil), (*StageWebhook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_StageWebhook_To_internalversion_StageWebhook(a.(*v1alpha1.StageWebhook), b.(*StageWebhook), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Volume)(nil), (*configv1alpha1.Volume)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_internalversion_Volume_To_v1alpha1_Volume(a.(*Volume), b.(*configv1alpha1.Volume), scope)
 	}); err != nil {
@@ -1071,6 +1121,8 @@ func autoConvert_internalversion_Component_To_v1alpha1_Component(in *Component,
 	}
 	out.Metric = (*configv1alpha1.ComponentMetric)(unsafe.Pointer(in.Metric))
 	out.MetricsDiscovery = (*configv1alpha1.ComponentMetric)(unsafe.Pointer(in.MetricsDiscovery))
+	out.Readiness = (*configv1alpha1.ComponentReadiness)(unsafe.Pointer(in.Readiness))
+	out.RestartPolicy = configv1alpha1.RestartPolicy(in.RestartPolicy)
 	out.Version = in.Version
 	return nil
 }
@@ -1104,6 +1156,8 @@ func autoConvert_v1alpha1_Component_To_internalversion_Component(in *configv1alp
 	}
 	out.Metric = (*ComponentMetric)(unsafe.Pointer(in.Metric))
 	out.MetricsDiscovery = (*ComponentMetric)(unsafe.Pointer(in.MetricsDiscovery))
+	out.Readiness = (*ComponentReadiness)(unsafe.Pointer(in.Readiness))
+	out.RestartPolicy = RestartPolicy(in.RestartPolicy)
 	out.Version = in.Version
 	return nil
 }
@@ -1158,6 +1212,9 @@ func autoConvert_internalversion_ComponentPatches_To_v1alpha1_ComponentPatches(i
 		out.ExtraVolumes = nil
 	}
 	out.ExtraEnvs = *(*[]configv1alpha1.Env)(unsafe.Pointer(&in.ExtraEnvs))
+	out.Readiness = (*configv1alpha1.ComponentReadiness)(unsafe.Pointer(in.Readiness))
+	out.CommandOverride = *(*[]string)(unsafe.Pointer(&in.CommandOverride))
+	out.RestartPolicy = configv1alpha1.RestartPolicy(in.RestartPolicy)
 	return nil
 }
 
@@ -1181,6 +1238,9 @@ func autoConvert_v1alpha1_ComponentPatches_To_internalversion_ComponentPatches(i
 		out.ExtraVolumes = nil
 	}
 	out.ExtraEnvs = *(*[]Env)(unsafe.Pointer(&in.ExtraEnvs))
+	out.Readiness = (*ComponentReadiness)(unsafe.Pointer(in.Readiness))
+	out.CommandOverride = *(*[]string)(unsafe.Pointer(&in.CommandOverride))
+	out.RestartPolicy = RestartPolicy(in.RestartPolicy)
 	return nil
 }
 
@@ -1189,6 +1249,38 @@ func Convert_v1alpha1_ComponentPatches_To_internalversion_ComponentPatches(in *c
 	return autoConvert_v1alpha1_ComponentPatches_To_internalversion_ComponentPatches(in, out, s)
 }
 
+func autoConvert_internalversion_ComponentReadiness_To_v1alpha1_ComponentReadiness(in *ComponentReadiness, out *configv1alpha1.ComponentReadiness, s conversion.Scope) error {
+	out.Scheme = in.Scheme
+	out.Host = in.Host
+	out.Path = in.Path
+	out.CertPath = in.CertPath
+	out.KeyPath = in.KeyPath
+	out.InsecureSkipVerify = in.InsecureSkipVerify
+	out.Command = *(*[]string)(unsafe.Pointer(&in.Command))
+	return nil
+}
+
+// Convert_internalversion_ComponentReadiness_To_v1alpha1_ComponentReadiness is an autogenerated conversion function.
+func Convert_internalversion_ComponentReadiness_To_v1alpha1_ComponentReadiness(in *ComponentReadiness, out *configv1alpha1.ComponentReadiness, s conversion.Scope) error {
+	return autoConvert_internalversion_ComponentReadiness_To_v1alpha1_ComponentReadiness(in, out, s)
+}
+
+func autoConvert_v1alpha1_ComponentReadiness_To_internalversion_ComponentReadiness(in *configv1alpha1.ComponentReadiness, out *ComponentReadiness, s conversion.Scope) error {
+	out.Scheme = in.Scheme
+	out.Host = in.Host
+	out.Path = in.Path
+	out.CertPath = in.CertPath
+	out.KeyPath = in.KeyPath
+	out.InsecureSkipVerify = in.InsecureSkipVerify
+	out.Command = *(*[]string)(unsafe.Pointer(&in.Command))
+	return nil
+}
+
+// Convert_v1alpha1_ComponentReadiness_To_internalversion_ComponentReadiness is an autogenerated conversion function.
+func Convert_v1alpha1_ComponentReadiness_To_internalversion_ComponentReadiness(in *configv1alpha1.ComponentReadiness, out *ComponentReadiness, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ComponentReadiness_To_internalversion_ComponentReadiness(in, out, s)
+}
+
 func autoConvert_internalversion_Env_To_v1alpha1_Env(in *Env, out *configv1alpha1.Env, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Value = in.Value
@@ -1513,10 +1605,50 @@ func autoConvert_internalversion_KwokConfigurationOptions_To_v1alpha1_KwokConfig
 	if err := v1.Convert_bool_To_Pointer_bool(&in.EnableProfilingHandler, &out.EnableProfilingHandler, s); err != nil {
 		return err
 	}
+	out.ProfilingAddress = in.ProfilingAddress
 	out.PodPlayStageParallelism = in.PodPlayStageParallelism
+	out.PodPlayStageParallelismRampMilliseconds = in.PodPlayStageParallelismRampMilliseconds
+	out.PodPlayStageParallelismRampJitterMilliseconds = in.PodPlayStageParallelismRampJitterMilliseconds
+	out.PodStatusUpdateParallelism = in.PodStatusUpdateParallelism
+	out.PodPreprocessParallelism = in.PodPreprocessParallelism
 	out.NodePlayStageParallelism = in.NodePlayStageParallelism
+	out.NodePreprocessParallelism = in.NodePreprocessParallelism
 	out.NodeLeaseDurationSeconds = in.NodeLeaseDurationSeconds
 	out.NodeLeaseParallelism = in.NodeLeaseParallelism
+	out.NodeLeaseRenewIntervalSeconds = in.NodeLeaseRenewIntervalSeconds
+	out.NodeLeaseRenewIntervalJitter = in.NodeLeaseRenewIntervalJitter
+	if err := v1.Convert_bool_To_Pointer_bool(&in.EnableStageWebhook, &out.EnableStageWebhook, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_bool_To_Pointer_bool(&in.EnableNodeVolumeStatus, &out.EnableNodeVolumeStatus, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_bool_To_Pointer_bool(&in.EnableNodeMemoryPressureEviction, &out.EnableNodeMemoryPressureEviction, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_bool_To_Pointer_bool(&in.DisregardFinalizers, &out.DisregardFinalizers, s); err != nil {
+		return err
+	}
+	out.PodAdmissionFailurePolicy = in.PodAdmissionFailurePolicy
+	out.RBACSelfCheckPolicy = in.RBACSelfCheckPolicy
+	out.NodeCPUOvercommit = in.NodeCPUOvercommit
+	out.NodeMemoryOvercommit = in.NodeMemoryOvercommit
+	out.GlobalDelayJitterMilliseconds = in.GlobalDelayJitterMilliseconds
+	out.FaultInjectionErrorRate = in.FaultInjectionErrorRate
+	out.FaultInjectionLatencyMilliseconds = in.FaultInjectionLatencyMilliseconds
+	if err := v1.Convert_bool_To_Pointer_bool(&in.EnableClientTransportTuning, &out.EnableClientTransportTuning, s); err != nil {
+		return err
+	}
+	out.ClientTransportMaxIdleConnsPerHost = in.ClientTransportMaxIdleConnsPerHost
+	out.ClientTransportMaxConnsPerHost = in.ClientTransportMaxConnsPerHost
+	out.ClientTransportKeepAliveSeconds = in.ClientTransportKeepAliveSeconds
+	out.ClientTransportIdleConnTimeoutSeconds = in.ClientTransportIdleConnTimeoutSeconds
+	out.ClientTransportDisableHTTP2 = in.ClientTransportDisableHTTP2
+	out.DisableMetricsFor = *(*[]string)(unsafe.Pointer(&in.DisableMetricsFor))
+	out.PodDNSPolicy = in.PodDNSPolicy
+	out.PodDNSNameservers = *(*[]string)(unsafe.Pointer(&in.PodDNSNameservers))
+	out.PodDNSSearches = *(*[]string)(unsafe.Pointer(&in.PodDNSSearches))
+	out.PodDNSOptions = *(*[]string)(unsafe.Pointer(&in.PodDNSOptions))
 	return nil
 }
 
@@ -1554,10 +1686,50 @@ func autoConvert_v1alpha1_KwokConfigurationOptions_To_internalversion_KwokConfig
 	if err := v1.Convert_Pointer_bool_To_bool(&in.EnableProfilingHandler, &out.EnableProfilingHandler, s); err != nil {
 		return err
 	}
+	out.ProfilingAddress = in.ProfilingAddress
 	out.PodPlayStageParallelism = in.PodPlayStageParallelism
+	out.PodPlayStageParallelismRampMilliseconds = in.PodPlayStageParallelismRampMilliseconds
+	out.PodPlayStageParallelismRampJitterMilliseconds = in.PodPlayStageParallelismRampJitterMilliseconds
+	out.PodStatusUpdateParallelism = in.PodStatusUpdateParallelism
+	out.PodPreprocessParallelism = in.PodPreprocessParallelism
 	out.NodePlayStageParallelism = in.NodePlayStageParallelism
+	out.NodePreprocessParallelism = in.NodePreprocessParallelism
 	out.NodeLeaseDurationSeconds = in.NodeLeaseDurationSeconds
 	out.NodeLeaseParallelism = in.NodeLeaseParallelism
+	out.NodeLeaseRenewIntervalSeconds = in.NodeLeaseRenewIntervalSeconds
+	out.NodeLeaseRenewIntervalJitter = in.NodeLeaseRenewIntervalJitter
+	if err := v1.Convert_Pointer_bool_To_bool(&in.EnableStageWebhook, &out.EnableStageWebhook, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_bool_To_bool(&in.EnableNodeVolumeStatus, &out.EnableNodeVolumeStatus, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_bool_To_bool(&in.EnableNodeMemoryPressureEviction, &out.EnableNodeMemoryPressureEviction, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_bool_To_bool(&in.DisregardFinalizers, &out.DisregardFinalizers, s); err != nil {
+		return err
+	}
+	out.PodAdmissionFailurePolicy = in.PodAdmissionFailurePolicy
+	out.RBACSelfCheckPolicy = in.RBACSelfCheckPolicy
+	out.NodeCPUOvercommit = in.NodeCPUOvercommit
+	out.NodeMemoryOvercommit = in.NodeMemoryOvercommit
+	out.GlobalDelayJitterMilliseconds = in.GlobalDelayJitterMilliseconds
+	out.FaultInjectionErrorRate = in.FaultInjectionErrorRate
+	out.FaultInjectionLatencyMilliseconds = in.FaultInjectionLatencyMilliseconds
+	if err := v1.Convert_Pointer_bool_To_bool(&in.EnableClientTransportTuning, &out.EnableClientTransportTuning, s); err != nil {
+		return err
+	}
+	out.ClientTransportMaxIdleConnsPerHost = in.ClientTransportMaxIdleConnsPerHost
+	out.ClientTransportMaxConnsPerHost = in.ClientTransportMaxConnsPerHost
+	out.ClientTransportKeepAliveSeconds = in.ClientTransportKeepAliveSeconds
+	out.ClientTransportIdleConnTimeoutSeconds = in.ClientTransportIdleConnTimeoutSeconds
+	out.ClientTransportDisableHTTP2 = in.ClientTransportDisableHTTP2
+	out.DisableMetricsFor = *(*[]string)(unsafe.Pointer(&in.DisableMetricsFor))
+	out.PodDNSPolicy = in.PodDNSPolicy
+	out.PodDNSNameservers = *(*[]string)(unsafe.Pointer(&in.PodDNSNameservers))
+	out.PodDNSSearches = *(*[]string)(unsafe.Pointer(&in.PodDNSSearches))
+	out.PodDNSOptions = *(*[]string)(unsafe.Pointer(&in.PodDNSOptions))
 	return nil
 }
 
@@ -1647,7 +1819,9 @@ func autoConvert_internalversion_KwokctlConfigurationOptions_To_v1alpha1_Kwokctl
 	out.EnableCRDs = *(*[]string)(unsafe.Pointer(&in.EnableCRDs))
 	out.KubeApiserverPort = in.KubeApiserverPort
 	out.KubeApiserverInsecurePort = in.KubeApiserverInsecurePort
+	out.KubeApiserverReadOnlyPort = in.KubeApiserverReadOnlyPort
 	out.InsecureKubeconfig = in.InsecureKubeconfig
+	out.KubeApiserverUnixSocketProxy = in.KubeApiserverUnixSocketProxy
 	out.Runtime = in.Runtime
 	out.Runtimes = *(*[]string)(unsafe.Pointer(&in.Runtimes))
 	out.PrometheusPort = in.PrometheusPort
@@ -1655,6 +1829,7 @@ func autoConvert_internalversion_KwokctlConfigurationOptions_To_v1alpha1_Kwokctl
 	out.JaegerOtlpGrpcPort = in.JaegerOtlpGrpcPort
 	out.KwokVersion = in.KwokVersion
 	out.KubeVersion = in.KubeVersion
+	out.RuntimeKubeVersions = *(*map[string]string)(unsafe.Pointer(&in.RuntimeKubeVersions))
 	out.EtcdVersion = in.EtcdVersion
 	out.DashboardVersion = in.DashboardVersion
 	out.DashboardMetricsScraperVersion = in.DashboardMetricsScraperVersion
@@ -1668,6 +1843,10 @@ func autoConvert_internalversion_KwokctlConfigurationOptions_To_v1alpha1_Kwokctl
 	if err := v1.Convert_bool_To_Pointer_bool(&in.QuietPull, &out.QuietPull, s); err != nil {
 		return err
 	}
+	out.NetworkMTU = in.NetworkMTU
+	out.LogDriver = in.LogDriver
+	out.LogOpts = *(*[]string)(unsafe.Pointer(&in.LogOpts))
+	out.KubeApiserverInClusterAddress = in.KubeApiserverInClusterAddress
 	out.KubeSchedulerConfig = in.KubeSchedulerConfig
 	if err := v1.Convert_bool_To_Pointer_bool(&in.DisableKubeScheduler, &out.DisableKubeScheduler, s); err != nil {
 		return err
@@ -1708,34 +1887,67 @@ func autoConvert_internalversion_KwokctlConfigurationOptions_To_v1alpha1_Kwokctl
 	out.KindBinary = in.KindBinary
 	out.KubeFeatureGates = in.KubeFeatureGates
 	out.KubeRuntimeConfig = in.KubeRuntimeConfig
+	out.KubeServiceClusterIPRange = in.KubeServiceClusterIPRange
 	out.KubeAuditPolicy = in.KubeAuditPolicy
+	out.KubeAuditWebhook = in.KubeAuditWebhook
+	out.KubeAuditLogMaxSize = in.KubeAuditLogMaxSize
+	out.KubeAuditLogMaxAge = in.KubeAuditLogMaxAge
+	out.KubeAuditLogMaxBackup = in.KubeAuditLogMaxBackup
+	out.CABundle = in.CABundle
 	if err := v1.Convert_bool_To_Pointer_bool(&in.KubeAuthorization, &out.KubeAuthorization, s); err != nil {
 		return err
 	}
 	if err := v1.Convert_bool_To_Pointer_bool(&in.KubeAdmission, &out.KubeAdmission, s); err != nil {
 		return err
 	}
+	out.KubeAdmissionPlugins = in.KubeAdmissionPlugins
+	out.KubeApiserverTLSMinVersion = in.KubeApiserverTLSMinVersion
+	out.KubeApiserverTLSCipherSuites = in.KubeApiserverTLSCipherSuites
+	out.KubeApiserverRequestTimeout = in.KubeApiserverRequestTimeout
+	out.KubeApiserverMinRequestTimeout = in.KubeApiserverMinRequestTimeout
+	if err := v1.Convert_bool_To_Pointer_bool(&in.KubeApiserverPriorityAndFairness, &out.KubeApiserverPriorityAndFairness, s); err != nil {
+		return err
+	}
+	out.KubeApiserverMaxRequestsInflight = in.KubeApiserverMaxRequestsInflight
+	out.KubeApiserverMaxMutatingRequestsInflight = in.KubeApiserverMaxMutatingRequestsInflight
 	out.EtcdPeerPort = in.EtcdPeerPort
 	out.EtcdPort = in.EtcdPort
 	out.KubeControllerManagerPort = in.KubeControllerManagerPort
 	out.KubeSchedulerPort = in.KubeSchedulerPort
 	out.DashboardPort = in.DashboardPort
 	out.KwokControllerPort = in.KwokControllerPort
+	out.KwokControllerProfilingPort = in.KwokControllerProfilingPort
 	out.MetricsServerPort = in.MetricsServerPort
 	out.CacheDir = in.CacheDir
 	out.KubeControllerManagerNodeMonitorPeriodMilliseconds = in.KubeControllerManagerNodeMonitorPeriodMilliseconds
 	out.KubeControllerManagerNodeMonitorGracePeriodMilliseconds = in.KubeControllerManagerNodeMonitorGracePeriodMilliseconds
 	out.NodeStatusUpdateFrequencyMilliseconds = in.NodeStatusUpdateFrequencyMilliseconds
 	out.NodeLeaseDurationSeconds = in.NodeLeaseDurationSeconds
+	out.ManageNodesParallelism = in.ManageNodesParallelism
 	if err := v1.Convert_float64_To_Pointer_float64(&in.HeartbeatFactor, &out.HeartbeatFactor, s); err != nil {
 		return err
 	}
+	out.NodeCPUOvercommit = in.NodeCPUOvercommit
+	out.NodeMemoryOvercommit = in.NodeMemoryOvercommit
 	out.BindAddress = in.BindAddress
 	out.KubeApiserverCertSANs = *(*[]string)(unsafe.Pointer(&in.KubeApiserverCertSANs))
 	if err := v1.Convert_bool_To_Pointer_bool(&in.DisableQPSLimits, &out.DisableQPSLimits, s); err != nil {
 		return err
 	}
 	out.EtcdQuotaBackendSize = in.EtcdQuotaBackendSize
+	if err := v1.Convert_bool_To_Pointer_bool(&in.EtcdUnsafeNoFsync, &out.EtcdUnsafeNoFsync, s); err != nil {
+		return err
+	}
+	out.EtcdHeartbeatInterval = in.EtcdHeartbeatInterval
+	out.EtcdElectionTimeout = in.EtcdElectionTimeout
+	out.EtcdExtraClientURLs = *(*[]string)(unsafe.Pointer(&in.EtcdExtraClientURLs))
+	out.EtcdReplicas = in.EtcdReplicas
+	out.PodDNSPolicy = in.PodDNSPolicy
+	out.PodDNSNameservers = *(*[]string)(unsafe.Pointer(&in.PodDNSNameservers))
+	out.PodDNSSearches = *(*[]string)(unsafe.Pointer(&in.PodDNSSearches))
+	out.PodDNSOptions = *(*[]string)(unsafe.Pointer(&in.PodDNSOptions))
+	out.AutoSnapshotInterval = in.AutoSnapshotInterval
+	out.AutoSnapshotRetention = in.AutoSnapshotRetention
 	return nil
 }
 
@@ -1748,7 +1960,9 @@ func autoConvert_v1alpha1_KwokctlConfigurationOptions_To_internalversion_Kwokctl
 	out.EnableCRDs = *(*[]string)(unsafe.Pointer(&in.EnableCRDs))
 	out.KubeApiserverPort = in.KubeApiserverPort
 	out.KubeApiserverInsecurePort = in.KubeApiserverInsecurePort
+	out.KubeApiserverReadOnlyPort = in.KubeApiserverReadOnlyPort
 	out.InsecureKubeconfig = in.InsecureKubeconfig
+	out.KubeApiserverUnixSocketProxy = in.KubeApiserverUnixSocketProxy
 	out.Runtime = in.Runtime
 	out.Runtimes = *(*[]string)(unsafe.Pointer(&in.Runtimes))
 	out.PrometheusPort = in.PrometheusPort
@@ -1756,6 +1970,7 @@ func autoConvert_v1alpha1_KwokctlConfigurationOptions_To_internalversion_Kwokctl
 	out.JaegerOtlpGrpcPort = in.JaegerOtlpGrpcPort
 	out.KwokVersion = in.KwokVersion
 	out.KubeVersion = in.KubeVersion
+	out.RuntimeKubeVersions = *(*map[string]string)(unsafe.Pointer(&in.RuntimeKubeVersions))
 	out.EtcdVersion = in.EtcdVersion
 	out.DashboardVersion = in.DashboardVersion
 	out.DashboardMetricsScraperVersion = in.DashboardMetricsScraperVersion
@@ -1769,6 +1984,10 @@ func autoConvert_v1alpha1_KwokctlConfigurationOptions_To_internalversion_Kwokctl
 	if err := v1.Convert_Pointer_bool_To_bool(&in.QuietPull, &out.QuietPull, s); err != nil {
 		return err
 	}
+	out.NetworkMTU = in.NetworkMTU
+	out.LogDriver = in.LogDriver
+	out.LogOpts = *(*[]string)(unsafe.Pointer(&in.LogOpts))
+	out.KubeApiserverInClusterAddress = in.KubeApiserverInClusterAddress
 	out.KubeSchedulerConfig = in.KubeSchedulerConfig
 	if err := v1.Convert_Pointer_bool_To_bool(&in.DisableKubeScheduler, &out.DisableKubeScheduler, s); err != nil {
 		return err
@@ -1825,34 +2044,67 @@ func autoConvert_v1alpha1_KwokctlConfigurationOptions_To_internalversion_Kwokctl
 	// INFO: in.Mode opted out of conversion generation
 	out.KubeFeatureGates = in.KubeFeatureGates
 	out.KubeRuntimeConfig = in.KubeRuntimeConfig
+	out.KubeServiceClusterIPRange = in.KubeServiceClusterIPRange
 	out.KubeAuditPolicy = in.KubeAuditPolicy
+	out.KubeAuditWebhook = in.KubeAuditWebhook
+	out.KubeAuditLogMaxSize = in.KubeAuditLogMaxSize
+	out.KubeAuditLogMaxAge = in.KubeAuditLogMaxAge
+	out.KubeAuditLogMaxBackup = in.KubeAuditLogMaxBackup
+	out.CABundle = in.CABundle
 	if err := v1.Convert_Pointer_bool_To_bool(&in.KubeAuthorization, &out.KubeAuthorization, s); err != nil {
 		return err
 	}
 	if err := v1.Convert_Pointer_bool_To_bool(&in.KubeAdmission, &out.KubeAdmission, s); err != nil {
 		return err
 	}
+	out.KubeAdmissionPlugins = in.KubeAdmissionPlugins
+	out.KubeApiserverTLSMinVersion = in.KubeApiserverTLSMinVersion
+	out.KubeApiserverTLSCipherSuites = in.KubeApiserverTLSCipherSuites
+	out.KubeApiserverRequestTimeout = in.KubeApiserverRequestTimeout
+	out.KubeApiserverMinRequestTimeout = in.KubeApiserverMinRequestTimeout
+	if err := v1.Convert_Pointer_bool_To_bool(&in.KubeApiserverPriorityAndFairness, &out.KubeApiserverPriorityAndFairness, s); err != nil {
+		return err
+	}
+	out.KubeApiserverMaxRequestsInflight = in.KubeApiserverMaxRequestsInflight
+	out.KubeApiserverMaxMutatingRequestsInflight = in.KubeApiserverMaxMutatingRequestsInflight
 	out.EtcdPeerPort = in.EtcdPeerPort
 	out.EtcdPort = in.EtcdPort
 	out.KubeControllerManagerPort = in.KubeControllerManagerPort
 	out.KubeSchedulerPort = in.KubeSchedulerPort
 	out.DashboardPort = in.DashboardPort
 	out.KwokControllerPort = in.KwokControllerPort
+	out.KwokControllerProfilingPort = in.KwokControllerProfilingPort
 	out.MetricsServerPort = in.MetricsServerPort
 	out.CacheDir = in.CacheDir
 	out.KubeControllerManagerNodeMonitorPeriodMilliseconds = in.KubeControllerManagerNodeMonitorPeriodMilliseconds
 	out.KubeControllerManagerNodeMonitorGracePeriodMilliseconds = in.KubeControllerManagerNodeMonitorGracePeriodMilliseconds
 	out.NodeStatusUpdateFrequencyMilliseconds = in.NodeStatusUpdateFrequencyMilliseconds
 	out.NodeLeaseDurationSeconds = in.NodeLeaseDurationSeconds
+	out.ManageNodesParallelism = in.ManageNodesParallelism
 	if err := v1.Convert_Pointer_float64_To_float64(&in.HeartbeatFactor, &out.HeartbeatFactor, s); err != nil {
 		return err
 	}
+	out.NodeCPUOvercommit = in.NodeCPUOvercommit
+	out.NodeMemoryOvercommit = in.NodeMemoryOvercommit
 	out.BindAddress = in.BindAddress
 	out.KubeApiserverCertSANs = *(*[]string)(unsafe.Pointer(&in.KubeApiserverCertSANs))
 	if err := v1.Convert_Pointer_bool_To_bool(&in.DisableQPSLimits, &out.DisableQPSLimits, s); err != nil {
 		return err
 	}
 	out.EtcdQuotaBackendSize = in.EtcdQuotaBackendSize
+	if err := v1.Convert_Pointer_bool_To_bool(&in.EtcdUnsafeNoFsync, &out.EtcdUnsafeNoFsync, s); err != nil {
+		return err
+	}
+	out.EtcdHeartbeatInterval = in.EtcdHeartbeatInterval
+	out.EtcdElectionTimeout = in.EtcdElectionTimeout
+	out.EtcdExtraClientURLs = *(*[]string)(unsafe.Pointer(&in.EtcdExtraClientURLs))
+	out.EtcdReplicas = in.EtcdReplicas
+	out.PodDNSPolicy = in.PodDNSPolicy
+	out.PodDNSNameservers = *(*[]string)(unsafe.Pointer(&in.PodDNSNameservers))
+	out.PodDNSSearches = *(*[]string)(unsafe.Pointer(&in.PodDNSSearches))
+	out.PodDNSOptions = *(*[]string)(unsafe.Pointer(&in.PodDNSOptions))
+	out.AutoSnapshotInterval = in.AutoSnapshotInterval
+	out.AutoSnapshotRetention = in.AutoSnapshotRetention
 	return nil
 }
 
@@ -2396,6 +2648,32 @@ func Convert_v1alpha1_Stage_To_internalversion_Stage(in *v1alpha1.Stage, out *St
 	return autoConvert_v1alpha1_Stage_To_internalversion_Stage(in, out, s)
 }
 
+func autoConvert_internalversion_StageCondition_To_v1alpha1_StageCondition(in *StageCondition, out *v1alpha1.StageCondition, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Expression = in.Expression
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_internalversion_StageCondition_To_v1alpha1_StageCondition is an autogenerated conversion function.
+func Convert_internalversion_StageCondition_To_v1alpha1_StageCondition(in *StageCondition, out *v1alpha1.StageCondition, s conversion.Scope) error {
+	return autoConvert_internalversion_StageCondition_To_v1alpha1_StageCondition(in, out, s)
+}
+
+func autoConvert_v1alpha1_StageCondition_To_internalversion_StageCondition(in *v1alpha1.StageCondition, out *StageCondition, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Expression = in.Expression
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v1alpha1_StageCondition_To_internalversion_StageCondition is an autogenerated conversion function.
+func Convert_v1alpha1_StageCondition_To_internalversion_StageCondition(in *v1alpha1.StageCondition, out *StageCondition, s conversion.Scope) error {
+	return autoConvert_v1alpha1_StageCondition_To_internalversion_StageCondition(in, out, s)
+}
+
 func autoConvert_internalversion_StageDelay_To_v1alpha1_StageDelay(in *StageDelay, out *v1alpha1.StageDelay, s conversion.Scope) error {
 	out.DurationMilliseconds = (*int64)(unsafe.Pointer(in.DurationMilliseconds))
 	out.DurationFrom = (*v1alpha1.ExpressionFromSource)(unsafe.Pointer(in.DurationFrom))
@@ -2485,6 +2763,10 @@ func autoConvert_internalversion_StageNext_To_v1alpha1_StageNext(in *StageNext,
 	} else {
 		out.Patches = nil
 	}
+	out.Webhook = (*v1alpha1.StageWebhook)(unsafe.Pointer(in.Webhook))
+	out.Conditions = *(*[]v1alpha1.StageCondition)(unsafe.Pointer(&in.Conditions))
+	out.StatusMessage = (*v1alpha1.StageStatusMessage)(unsafe.Pointer(in.StatusMessage))
+	out.StartTimeBackdate = (*v1alpha1.StageStartTimeBackdate)(unsafe.Pointer(in.StartTimeBackdate))
 	return nil
 }
 
@@ -2503,6 +2785,10 @@ func autoConvert_v1alpha1_StageNext_To_internalversion_StageNext(in *v1alpha1.St
 	} else {
 		out.Patches = nil
 	}
+	out.Webhook = (*StageWebhook)(unsafe.Pointer(in.Webhook))
+	out.Conditions = *(*[]StageCondition)(unsafe.Pointer(&in.Conditions))
+	out.StatusMessage = (*StageStatusMessage)(unsafe.Pointer(in.StatusMessage))
+	out.StartTimeBackdate = (*StageStartTimeBackdate)(unsafe.Pointer(in.StartTimeBackdate))
 	// INFO: in.StatusTemplate opted out of conversion generation
 	// INFO: in.StatusSubresource opted out of conversion generation
 	// INFO: in.StatusPatchAs opted out of conversion generation
@@ -2592,6 +2878,7 @@ func autoConvert_internalversion_StageSpec_To_v1alpha1_StageSpec(in *StageSpec,
 	if err := v1.Convert_bool_To_Pointer_bool(&in.ImmediateNextStage, &out.ImmediateNextStage, s); err != nil {
 		return err
 	}
+	out.MaxCount = in.MaxCount
 	return nil
 }
 
@@ -2614,6 +2901,7 @@ func autoConvert_v1alpha1_StageSpec_To_internalversion_StageSpec(in *v1alpha1.St
 	if err := v1.Convert_Pointer_bool_To_bool(&in.ImmediateNextStage, &out.ImmediateNextStage, s); err != nil {
 		return err
 	}
+	out.MaxCount = in.MaxCount
 	return nil
 }
 
@@ -2622,6 +2910,80 @@ func Convert_v1alpha1_StageSpec_To_internalversion_StageSpec(in *v1alpha1.StageS
 	return autoConvert_v1alpha1_StageSpec_To_internalversion_StageSpec(in, out, s)
 }
 
+func autoConvert_internalversion_StageStartTimeBackdate_To_v1alpha1_StageStartTimeBackdate(in *StageStartTimeBackdate, out *v1alpha1.StageStartTimeBackdate, s conversion.Scope) error {
+	out.DurationMilliseconds = (*int64)(unsafe.Pointer(in.DurationMilliseconds))
+	out.DurationFrom = (*v1alpha1.ExpressionFromSource)(unsafe.Pointer(in.DurationFrom))
+	return nil
+}
+
+// Convert_internalversion_StageStartTimeBackdate_To_v1alpha1_StageStartTimeBackdate is an autogenerated conversion function.
+func Convert_internalversion_StageStartTimeBackdate_To_v1alpha1_StageStartTimeBackdate(in *StageStartTimeBackdate, out *v1alpha1.StageStartTimeBackdate, s conversion.Scope) error {
+	return autoConvert_internalversion_StageStartTimeBackdate_To_v1alpha1_StageStartTimeBackdate(in, out, s)
+}
+
+func autoConvert_v1alpha1_StageStartTimeBackdate_To_internalversion_StageStartTimeBackdate(in *v1alpha1.StageStartTimeBackdate, out *StageStartTimeBackdate, s conversion.Scope) error {
+	out.DurationMilliseconds = (*int64)(unsafe.Pointer(in.DurationMilliseconds))
+	out.DurationFrom = (*ExpressionFromSource)(unsafe.Pointer(in.DurationFrom))
+	return nil
+}
+
+// Convert_v1alpha1_StageStartTimeBackdate_To_internalversion_StageStartTimeBackdate is an autogenerated conversion function.
+func Convert_v1alpha1_StageStartTimeBackdate_To_internalversion_StageStartTimeBackdate(in *v1alpha1.StageStartTimeBackdate, out *StageStartTimeBackdate, s conversion.Scope) error {
+	return autoConvert_v1alpha1_StageStartTimeBackdate_To_internalversion_StageStartTimeBackdate(in, out, s)
+}
+
+func autoConvert_internalversion_StageStatusMessage_To_v1alpha1_StageStatusMessage(in *StageStatusMessage, out *v1alpha1.StageStatusMessage, s conversion.Scope) error {
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_internalversion_StageStatusMessage_To_v1alpha1_StageStatusMessage is an autogenerated conversion function.
+func Convert_internalversion_StageStatusMessage_To_v1alpha1_StageStatusMessage(in *StageStatusMessage, out *v1alpha1.StageStatusMessage, s conversion.Scope) error {
+	return autoConvert_internalversion_StageStatusMessage_To_v1alpha1_StageStatusMessage(in, out, s)
+}
+
+func autoConvert_v1alpha1_StageStatusMessage_To_internalversion_StageStatusMessage(in *v1alpha1.StageStatusMessage, out *StageStatusMessage, s conversion.Scope) error {
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v1alpha1_StageStatusMessage_To_internalversion_StageStatusMessage is an autogenerated conversion function.
+func Convert_v1alpha1_StageStatusMessage_To_internalversion_StageStatusMessage(in *v1alpha1.StageStatusMessage, out *StageStatusMessage, s conversion.Scope) error {
+	return autoConvert_v1alpha1_StageStatusMessage_To_internalversion_StageStatusMessage(in, out, s)
+}
+
+func autoConvert_internalversion_StageWebhook_To_v1alpha1_StageWebhook(in *StageWebhook, out *v1alpha1.StageWebhook, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Template = in.Template
+	out.TimeoutMilliseconds = (*int64)(unsafe.Pointer(in.TimeoutMilliseconds))
+	out.Retries = in.Retries
+	out.Subresource = in.Subresource
+	out.Type = (*v1alpha1.StagePatchType)(unsafe.Pointer(in.Type))
+	return nil
+}
+
+// Convert_internalversion_StageWebhook_To_v1alpha1_StageWebhook is an autogenerated conversion function.
+func Convert_internalversion_StageWebhook_To_v1alpha1_StageWebhook(in *StageWebhook, out *v1alpha1.StageWebhook, s conversion.Scope) error {
+	return autoConvert_internalversion_StageWebhook_To_v1alpha1_StageWebhook(in, out, s)
+}
+
+func autoConvert_v1alpha1_StageWebhook_To_internalversion_StageWebhook(in *v1alpha1.StageWebhook, out *StageWebhook, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Template = in.Template
+	out.TimeoutMilliseconds = (*int64)(unsafe.Pointer(in.TimeoutMilliseconds))
+	out.Retries = in.Retries
+	out.Subresource = in.Subresource
+	out.Type = (*StagePatchType)(unsafe.Pointer(in.Type))
+	return nil
+}
+
+// Convert_v1alpha1_StageWebhook_To_internalversion_StageWebhook is an autogenerated conversion function.
+func Convert_v1alpha1_StageWebhook_To_internalversion_StageWebhook(in *v1alpha1.StageWebhook, out *StageWebhook, s conversion.Scope) error {
+	return autoConvert_v1alpha1_StageWebhook_To_internalversion_StageWebhook(in, out, s)
+}
+
 func autoConvert_internalversion_Volume_To_v1alpha1_Volume(in *Volume, out *configv1alpha1.Volume, s conversion.Scope) error {
 	out.Name = in.Name
 	if err := v1.Convert_bool_To_Pointer_bool(&in.ReadOnly, &out.ReadOnly, s); err != nil {
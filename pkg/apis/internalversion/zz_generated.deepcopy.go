@@ -360,6 +360,11 @@ func (in *Component) DeepCopyInto(out *Component) {
 		*out = new(ComponentMetric)
 		**out = **in
 	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ComponentReadiness)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -407,6 +412,16 @@ func (in *ComponentPatches) DeepCopyInto(out *ComponentPatches) {
 		*out = make([]Env, len(*in))
 		copy(*out, *in)
 	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ComponentReadiness)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CommandOverride != nil {
+		in, out := &in.CommandOverride, &out.CommandOverride
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -420,6 +435,27 @@ func (in *ComponentPatches) DeepCopy() *ComponentPatches {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentReadiness) DeepCopyInto(out *ComponentReadiness) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentReadiness.
+func (in *ComponentReadiness) DeepCopy() *ComponentReadiness {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentReadiness)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Env) DeepCopyInto(out *Env) {
 	*out = *in
@@ -682,6 +718,26 @@ func (in *KwokConfigurationOptions) DeepCopyInto(out *KwokConfigurationOptions)
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DisableMetricsFor != nil {
+		in, out := &in.DisableMetricsFor, &out.DisableMetricsFor
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodDNSNameservers != nil {
+		in, out := &in.PodDNSNameservers, &out.PodDNSNameservers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodDNSSearches != nil {
+		in, out := &in.PodDNSSearches, &out.PodDNSSearches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodDNSOptions != nil {
+		in, out := &in.PodDNSOptions, &out.PodDNSOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -741,11 +797,43 @@ func (in *KwokctlConfigurationOptions) DeepCopyInto(out *KwokctlConfigurationOpt
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RuntimeKubeVersions != nil {
+		in, out := &in.RuntimeKubeVersions, &out.RuntimeKubeVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LogOpts != nil {
+		in, out := &in.LogOpts, &out.LogOpts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.KubeApiserverCertSANs != nil {
 		in, out := &in.KubeApiserverCertSANs, &out.KubeApiserverCertSANs
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.EtcdExtraClientURLs != nil {
+		in, out := &in.EtcdExtraClientURLs, &out.EtcdExtraClientURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodDNSNameservers != nil {
+		in, out := &in.PodDNSNameservers, &out.PodDNSNameservers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodDNSSearches != nil {
+		in, out := &in.PodDNSSearches, &out.PodDNSSearches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodDNSOptions != nil {
+		in, out := &in.PodDNSOptions, &out.PodDNSOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1201,6 +1289,22 @@ func (in *Stage) DeepCopy() *Stage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageCondition) DeepCopyInto(out *StageCondition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageCondition.
+func (in *StageCondition) DeepCopy() *StageCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(StageCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StageDelay) DeepCopyInto(out *StageDelay) {
 	*out = *in
@@ -1299,6 +1403,26 @@ func (in *StageNext) DeepCopyInto(out *StageNext) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(StageWebhook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]StageCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.StatusMessage != nil {
+		in, out := &in.StatusMessage, &out.StatusMessage
+		*out = new(StageStatusMessage)
+		**out = **in
+	}
+	if in.StartTimeBackdate != nil {
+		in, out := &in.StartTimeBackdate, &out.StartTimeBackdate
+		*out = new(StageStartTimeBackdate)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1424,6 +1548,74 @@ func (in *StageSpec) DeepCopy() *StageSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageStartTimeBackdate) DeepCopyInto(out *StageStartTimeBackdate) {
+	*out = *in
+	if in.DurationMilliseconds != nil {
+		in, out := &in.DurationMilliseconds, &out.DurationMilliseconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DurationFrom != nil {
+		in, out := &in.DurationFrom, &out.DurationFrom
+		*out = new(ExpressionFromSource)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageStartTimeBackdate.
+func (in *StageStartTimeBackdate) DeepCopy() *StageStartTimeBackdate {
+	if in == nil {
+		return nil
+	}
+	out := new(StageStartTimeBackdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageStatusMessage) DeepCopyInto(out *StageStatusMessage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageStatusMessage.
+func (in *StageStatusMessage) DeepCopy() *StageStatusMessage {
+	if in == nil {
+		return nil
+	}
+	out := new(StageStatusMessage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageWebhook) DeepCopyInto(out *StageWebhook) {
+	*out = *in
+	if in.TimeoutMilliseconds != nil {
+		in, out := &in.TimeoutMilliseconds, &out.TimeoutMilliseconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(StagePatchType)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageWebhook.
+func (in *StageWebhook) DeepCopy() *StageWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(StageWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Volume) DeepCopyInto(out *Volume) {
 	*out = *in
@@ -79,6 +79,13 @@ type StageSpec struct {
 	Next StageNext `json:"next"`
 	// ImmediateNextStage means that the next stage of matching is performed immediately, without waiting for the Apiserver to push.
 	ImmediateNextStage *bool `json:"immediateNextStage,omitempty"`
+	// MaxCount limits how many times this stage is played for each resource,
+	// the stage is no longer matched for the resource once the limit is reached.
+	// Zero means unlimited.
+	// +default=0
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	MaxCount int `json:"maxCount,omitempty"`
 }
 
 // StageResourceRef specifies the kind and version of the resource.
@@ -122,6 +129,20 @@ type StageNext struct {
 	Delete bool `json:"delete,omitempty"`
 	// Patches means that the resource will be patched.
 	Patches []StagePatch `json:"patches,omitempty"`
+	// Webhook means that the resource will be sent to an external HTTP endpoint,
+	// and the patch returned by the endpoint will be applied to the resource.
+	// It only takes effect if the stage webhook is enabled in the kwok configuration.
+	Webhook *StageWebhook `json:"webhook,omitempty"`
+	// Conditions means that the conditions of the resource will be computed from CEL expressions,
+	// and set in the status of the resource. It is only supported for Pod.
+	Conditions []StageCondition `json:"conditions,omitempty"`
+	// StatusMessage means that the reason and message in the status of the resource will be rendered
+	// from the templates and set, e.g. when the pod is moved to Failed. It is only supported for Pod.
+	StatusMessage *StageStatusMessage `json:"statusMessage,omitempty"`
+	// StartTimeBackdate means that the startTime in the status of the resource will be set to
+	// the time the stage is applied minus the duration, e.g. to simulate the age of a running pod.
+	// It is only supported for Pod.
+	StartTimeBackdate *StageStartTimeBackdate `json:"startTimeBackdate,omitempty"`
 
 	// StatusTemplate indicates the template for modifying the status of the resource in the next.
 	// Deprecated: Use Patches instead.
@@ -161,6 +182,27 @@ type StagePatch struct {
 	Impersonation *ImpersonationConfig `json:"impersonation,omitempty"`
 }
 
+// StageWebhook describes an external HTTP endpoint that participates in the stage.
+type StageWebhook struct {
+	// URL is the address that the resource will be POSTed to.
+	// The response body is used as the patch of the resource, an empty body means no patch.
+	URL string `json:"url"`
+	// Template indicates the template for the request body.
+	// If it is empty, the resource will be sent as is.
+	Template string `json:"template,omitempty"`
+	// TimeoutMilliseconds indicates the timeout of each request.
+	// +default=10000
+	// +kubebuilder:default=10000
+	TimeoutMilliseconds *int64 `json:"timeoutMilliseconds,omitempty"`
+	// Retries indicates the number of retries when the request fails.
+	Retries int `json:"retries,omitempty"`
+	// Subresource indicates the name of the subresource that will be patched.
+	Subresource string `json:"subresource,omitempty"`
+	// Type indicates the type of the patch returned by the webhook.
+	// +kubebuilder:validation:Enum=json;merge;strategic
+	Type *StagePatchType `json:"type,omitempty"`
+}
+
 // StagePatchType is the type of the patch.
 type StagePatchType string
 
@@ -195,6 +237,39 @@ type FinalizerItem struct {
 	Value string `json:"value,omitempty"`
 }
 
+// StageCondition describes a condition computed from a CEL expression.
+type StageCondition struct {
+	// Type is the type of the condition, e.g. Ready.
+	Type string `json:"type"`
+	// Expression is the CEL expression over the resource, e.g. `pod`, which must evaluate to a bool.
+	// The status of the condition is True if it evaluates to true, otherwise False.
+	Expression string `json:"expression"`
+	// Reason is the reason of the condition when it is False.
+	Reason string `json:"reason,omitempty"`
+	// Message is the message of the condition when it is False.
+	Message string `json:"message,omitempty"`
+}
+
+// StageStatusMessage describes the reason and message in the status of the resource.
+type StageStatusMessage struct {
+	// Reason is the template of the reason in the status, e.g. `Evicted`.
+	Reason string `json:"reason,omitempty"`
+	// Message is the template of the message in the status,
+	// e.g. `Pod {{ .metadata.name }} was evicted from node {{ .spec.nodeName }}`.
+	Message string `json:"message,omitempty"`
+}
+
+// StageStartTimeBackdate describes how far the startTime in the status of the resource is moved into the past.
+type StageStartTimeBackdate struct {
+	// DurationMilliseconds indicates the duration the startTime is moved into the past.
+	// +kubebuilder:validation:Minimum=0
+	DurationMilliseconds *int64 `json:"durationMilliseconds,omitempty"`
+	// DurationFrom is the expression used to get the value.
+	// If it is a string type, the value get will be parsed by time.ParseDuration.
+	// If it is missing, DurationMilliseconds is used.
+	DurationFrom *ExpressionFromSource `json:"durationFrom,omitempty"`
+}
+
 // StageEvent describes one event in the Kubernetes.
 type StageEvent struct {
 	// Type is the type of this event (Normal, Warning), It is machine-readable.
@@ -1677,6 +1677,22 @@ func (in *Stage) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageCondition) DeepCopyInto(out *StageCondition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageCondition.
+func (in *StageCondition) DeepCopy() *StageCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(StageCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StageDelay) DeepCopyInto(out *StageDelay) {
 	*out = *in
@@ -1808,6 +1824,26 @@ func (in *StageNext) DeepCopyInto(out *StageNext) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(StageWebhook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]StageCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.StatusMessage != nil {
+		in, out := &in.StatusMessage, &out.StatusMessage
+		*out = new(StageStatusMessage)
+		**out = **in
+	}
+	if in.StartTimeBackdate != nil {
+		in, out := &in.StartTimeBackdate, &out.StartTimeBackdate
+		*out = new(StageStartTimeBackdate)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.StatusSubresource != nil {
 		in, out := &in.StatusSubresource, &out.StatusSubresource
 		*out = new(string)
@@ -1948,6 +1984,32 @@ func (in *StageSpec) DeepCopy() *StageSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageStartTimeBackdate) DeepCopyInto(out *StageStartTimeBackdate) {
+	*out = *in
+	if in.DurationMilliseconds != nil {
+		in, out := &in.DurationMilliseconds, &out.DurationMilliseconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DurationFrom != nil {
+		in, out := &in.DurationFrom, &out.DurationFrom
+		*out = new(ExpressionFromSource)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageStartTimeBackdate.
+func (in *StageStartTimeBackdate) DeepCopy() *StageStartTimeBackdate {
+	if in == nil {
+		return nil
+	}
+	out := new(StageStartTimeBackdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StageStatus) DeepCopyInto(out *StageStatus) {
 	*out = *in
@@ -1970,3 +2032,45 @@ func (in *StageStatus) DeepCopy() *StageStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageStatusMessage) DeepCopyInto(out *StageStatusMessage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageStatusMessage.
+func (in *StageStatusMessage) DeepCopy() *StageStatusMessage {
+	if in == nil {
+		return nil
+	}
+	out := new(StageStatusMessage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageWebhook) DeepCopyInto(out *StageWebhook) {
+	*out = *in
+	if in.TimeoutMilliseconds != nil {
+		in, out := &in.TimeoutMilliseconds, &out.TimeoutMilliseconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(StagePatchType)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageWebhook.
+func (in *StageWebhook) DeepCopy() *StageWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(StageWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -56,6 +56,12 @@ func SetObjectDefaults_Stage(in *Stage) {
 	if in.Spec.ResourceRef.APIGroup == "" {
 		in.Spec.ResourceRef.APIGroup = "v1"
 	}
+	if in.Spec.Next.Webhook != nil {
+		if in.Spec.Next.Webhook.TimeoutMilliseconds == nil {
+			var ptrVar1 int64 = 10000
+			in.Spec.Next.Webhook.TimeoutMilliseconds = &ptrVar1
+		}
+	}
 	if in.Spec.Next.StatusSubresource == nil {
 		var ptrVar1 string = "status"
 		in.Spec.Next.StatusSubresource = &ptrVar1
@@ -332,6 +332,32 @@ func Load(ctx context.Context, src ...string) ([]InternalObject, error) {
 	return objs, nil
 }
 
+// Unique removes the duplicate objects with the same type, namespace and name,
+// the later one takes precedence and replaces the earlier one in place.
+func Unique(objs []InternalObject) []InternalObject {
+	type key struct {
+		typ       string
+		namespace string
+		name      string
+	}
+	index := map[key]int{}
+	out := make([]InternalObject, 0, len(objs))
+	for _, obj := range objs {
+		k := key{
+			typ:       fmt.Sprintf("%T", obj),
+			namespace: obj.GetNamespace(),
+			name:      obj.GetName(),
+		}
+		if i, ok := index[k]; ok {
+			out[i] = obj
+			continue
+		}
+		index[k] = len(out)
+		out = append(out, obj)
+	}
+	return out
+}
+
 // LoadUnstructured loads the given path into the context.
 func LoadUnstructured(src ...string) ([]InternalObject, error) {
 	raws, err := loadRawMessages(src)
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
@@ -153,3 +154,88 @@ kind: KwokctlConfiguration
 		})
 	}
 }
+
+func TestLoadMerge(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	base := filepath.Join(dir, "base.yaml")
+	err := os.WriteFile(base, []byte(`kind: KwokctlConfiguration
+apiVersion: config.kwok.x-k8s.io/v1alpha1
+options:
+  kubeVersion: v1.29.0
+  runtime: binary
+---
+kind: Stage
+apiVersion: kwok.x-k8s.io/v1alpha1
+metadata:
+  name: pod-ready
+spec:
+  resourceRef:
+    apiGroup: v1
+    kind: Pod
+  delay:
+    durationMilliseconds: 1000
+---
+kind: Stage
+apiVersion: kwok.x-k8s.io/v1alpha1
+metadata:
+  name: pod-delete
+spec:
+  resourceRef:
+    apiGroup: v1
+    kind: Pod
+`), 0640)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	override := filepath.Join(dir, "override.yaml")
+	err = os.WriteFile(override, []byte(`kind: KwokctlConfiguration
+apiVersion: config.kwok.x-k8s.io/v1alpha1
+options:
+  kubeVersion: v1.30.0
+---
+kind: Stage
+apiVersion: kwok.x-k8s.io/v1alpha1
+metadata:
+  name: pod-ready
+spec:
+  resourceRef:
+    apiGroup: v1
+    kind: Pod
+  delay:
+    durationMilliseconds: 2000
+`), 0640)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objs, err := Load(ctx, base, override)
+	if err != nil {
+		t.Fatal(err)
+	}
+	objs = Unique(objs)
+
+	kwokctlConfigs := FilterWithType[*internalversion.KwokctlConfiguration](objs)
+	if len(kwokctlConfigs) != 1 {
+		t.Fatalf("want 1 KwokctlConfiguration, got %d", len(kwokctlConfigs))
+	}
+	if got := kwokctlConfigs[0].Options.KubeVersion; got != "v1.30.0" {
+		t.Errorf("want kubeVersion from the later file v1.30.0, got %s", got)
+	}
+	if got := kwokctlConfigs[0].Options.Runtime; got != "binary" {
+		t.Errorf("want runtime from the earlier file binary, got %s", got)
+	}
+
+	stages := FilterWithType[*internalversion.Stage](objs)
+	if len(stages) != 2 {
+		t.Fatalf("want 2 Stages, got %d", len(stages))
+	}
+	if stages[0].Name != "pod-ready" || stages[1].Name != "pod-delete" {
+		t.Errorf("want stages in the order of first appearance, got %s, %s", stages[0].Name, stages[1].Name)
+	}
+	if got := *stages[0].Spec.Delay.DurationMilliseconds; got != 2000 {
+		t.Errorf("want delay from the later file 2000, got %d", got)
+	}
+}
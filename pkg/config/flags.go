@@ -35,6 +35,13 @@ func InitFlags(ctx context.Context, flags *pflag.FlagSet) (context.Context, erro
 	config := flags.StringSliceP("config", "c", []string{defaultConfigPath}, "config path")
 	_ = flags.Parse(os.Args[1:])
 
+	// The runtime is parsed ahead, it is needed to default the configuration.
+	runtimeFlags := pflag.NewFlagSet("runtime", pflag.ContinueOnError)
+	runtimeFlags.ParseErrorsWhitelist.UnknownFlags = true
+	runtimeFlags.Usage = func() {}
+	runtimeFlags.StringVar(&runtimeFromFlag, "runtime", "", "")
+	_ = runtimeFlags.Parse(os.Args[1:])
+
 	// Expand the all config paths.
 	defaultConfigPath, err := path.Expand(defaultConfigPath)
 	if err != nil {
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+// NewMergedGetter returns a new DynamicGetter that merges the static list into the list of the dynamic getter.
+// The item of the dynamic getter overrides the static item with the same key,
+// and onOverride is called with the key of each overridden item if it is not nil.
+func NewMergedGetter[T any](dynamic DynamicGetter[[]T], static []T, keyFunc func(T) string, onOverride func(key string)) DynamicGetter[[]T] {
+	getter := &mergedGetter[T]{
+		dynamic:    dynamic,
+		static:     static,
+		keyFunc:    keyFunc,
+		onOverride: onOverride,
+	}
+	return struct {
+		Getter[[]T]
+		Starter
+		Synced
+	}{
+		Getter:  withCache[[]T](getter),
+		Starter: dynamic,
+		Synced:  dynamic,
+	}
+}
+
+type mergedGetter[T any] struct {
+	dynamic    Getter[[]T]
+	static     []T
+	keyFunc    func(T) string
+	onOverride func(key string)
+}
+
+func (m *mergedGetter[T]) Get() []T {
+	dynamic := m.dynamic.Get()
+	if len(m.static) == 0 {
+		return dynamic
+	}
+
+	index := make(map[string]int, len(dynamic))
+	for i, item := range dynamic {
+		index[m.keyFunc(item)] = i
+	}
+
+	merged := make([]T, 0, len(m.static)+len(dynamic))
+	overridden := make(map[int]struct{}, len(dynamic))
+	for _, item := range m.static {
+		key := m.keyFunc(item)
+		i, ok := index[key]
+		if !ok {
+			merged = append(merged, item)
+			continue
+		}
+		if m.onOverride != nil {
+			m.onOverride(key)
+		}
+		merged = append(merged, dynamic[i])
+		overridden[i] = struct{}{}
+	}
+	for i, item := range dynamic {
+		if _, ok := overridden[i]; ok {
+			continue
+		}
+		merged = append(merged, item)
+	}
+	return merged
+}
+
+// Version is never empty even if the dynamic getter has not been synced yet,
+// so that the static list is served from the start.
+func (m *mergedGetter[T]) Version() string {
+	return "merged/" + m.dynamic.Version()
+}
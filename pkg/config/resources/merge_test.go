@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type fakeDynamicGetter[T any] struct {
+	data    T
+	version string
+	syncCh  chan struct{}
+}
+
+func (f *fakeDynamicGetter[T]) Get() T {
+	return f.data
+}
+
+func (f *fakeDynamicGetter[T]) Version() string {
+	return f.version
+}
+
+func (f *fakeDynamicGetter[T]) Start(ctx context.Context) error {
+	return nil
+}
+
+func (f *fakeDynamicGetter[T]) Sync() <-chan struct{} {
+	return f.syncCh
+}
+
+type item struct {
+	name  string
+	value string
+}
+
+func TestMergedGetter(t *testing.T) {
+	tests := []struct {
+		name           string
+		static         []item
+		dynamic        []item
+		version        string
+		want           []item
+		wantOverridden []string
+	}{
+		{
+			name:    "only dynamic",
+			version: "1",
+			dynamic: []item{{"a", "dynamic"}},
+			want:    []item{{"a", "dynamic"}},
+		},
+		{
+			name:    "not synced",
+			static:  []item{{"a", "static"}},
+			version: "",
+			want:    []item{{"a", "static"}},
+		},
+		{
+			name:    "only static",
+			version: "1",
+			static:  []item{{"a", "static"}},
+			want:    []item{{"a", "static"}},
+		},
+		{
+			name:    "additive",
+			version: "1",
+			static:  []item{{"a", "static"}},
+			dynamic: []item{{"b", "dynamic"}},
+			want:    []item{{"a", "static"}, {"b", "dynamic"}},
+		},
+		{
+			name:           "override by name",
+			version:        "1",
+			static:         []item{{"a", "static"}, {"b", "static"}},
+			dynamic:        []item{{"c", "dynamic"}, {"b", "dynamic"}},
+			want:           []item{{"a", "static"}, {"b", "dynamic"}, {"c", "dynamic"}},
+			wantOverridden: []string{"b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dynamic := &fakeDynamicGetter[[]item]{
+				data:    tt.dynamic,
+				version: tt.version,
+			}
+			var overridden []string
+			getter := NewMergedGetter[item](dynamic, tt.static, func(i item) string {
+				return i.name
+			}, func(key string) {
+				overridden = append(overridden, key)
+			})
+
+			got := getter.Get()
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(overridden, tt.wantOverridden) {
+				t.Errorf("overridden got = %v, want %v", overridden, tt.wantOverridden)
+			}
+
+			// The merged list is cached until the version of the dynamic getter changes.
+			_ = getter.Get()
+			if len(overridden) != len(tt.wantOverridden) {
+				t.Errorf("want the merged list cached, got overridden %v", overridden)
+			}
+		})
+	}
+}
@@ -20,8 +20,11 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"sort"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/util/validation"
+
 	configv1alpha1 "sigs.k8s.io/kwok/pkg/apis/config/v1alpha1"
 	"sigs.k8s.io/kwok/pkg/apis/internalversion"
 	"sigs.k8s.io/kwok/pkg/apis/v1alpha1"
@@ -30,6 +33,7 @@ import (
 	"sigs.k8s.io/kwok/pkg/log"
 	"sigs.k8s.io/kwok/pkg/utils/envs"
 	"sigs.k8s.io/kwok/pkg/utils/format"
+	"sigs.k8s.io/kwok/pkg/utils/maps"
 	"sigs.k8s.io/kwok/pkg/utils/path"
 	"sigs.k8s.io/kwok/pkg/utils/version"
 )
@@ -44,6 +48,16 @@ var (
 	// ClustersDir is the directory of the clusters.
 	ClustersDir = path.Join(WorkDir, "clusters")
 
+	// runtimeFromFlag is the runtime specified by the flag --runtime,
+	// it is parsed ahead of the command to default the version of Kubernetes for the runtime.
+	runtimeFromFlag string
+
+	// ResolveRuntime returns the registered name of a runtime which may be given by an alias,
+	// it is set by the registry of the runtimes so that the defaulting sees through the aliases.
+	ResolveRuntime = func(name string) (string, error) {
+		return name, nil
+	}
+
 	// GOOS is the operating system target for which the code is compiled.
 	GOOS = runtime.GOOS
 
@@ -63,6 +77,27 @@ var (
 	binarySuffixZip = "zip"
 )
 
+// maxClusterNameLength is the maximum length of the name of a cluster,
+// the longest name derived from it is the hostname of the kind node, <cluster name>-control-plane,
+// which must be a DNS label.
+var maxClusterNameLength = validation.DNS1123LabelMaxLength - len(ClusterName("")) - len("-control-plane")
+
+// ValidateClusterName validates the name of a cluster,
+// which flows into the names of containers, networks and the SANs of certificates,
+// so it must be a DNS label short enough for the names derived from it.
+func ValidateClusterName(name string) error {
+	if name == "" {
+		return fmt.Errorf("invalid cluster name: must not be empty")
+	}
+	if len(name) > maxClusterNameLength {
+		return fmt.Errorf("invalid cluster name %q: must be no more than %d characters", name, maxClusterNameLength)
+	}
+	if errs := validation.IsDNS1123Label(name); len(errs) != 0 {
+		return fmt.Errorf("invalid cluster name %q: %s", name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
 // ClusterName returns the cluster name.
 func ClusterName(name string) string {
 	return fmt.Sprintf("%s-%s", consts.ProjectName, name)
@@ -152,6 +187,40 @@ func setKwokConfigurationDefaults(config *configv1alpha1.KwokConfiguration) *con
 	return config
 }
 
+// runtimeKubeVersion returns the default version of Kubernetes for the runtime,
+// the runtime specified by the flag takes precedence over the one in the configuration.
+func runtimeKubeVersion(conf *configv1alpha1.KwokctlConfigurationOptions) string {
+	rt := conf.Runtime
+	if runtimeFromFlag != "" {
+		rt = runtimeFromFlag
+	}
+	if v := conf.RuntimeKubeVersions[rt]; v != "" {
+		return v
+	}
+
+	// The runtime or the keys may be aliases, compare them by the registered names.
+	resolved, err := ResolveRuntime(rt)
+	if err != nil {
+		return consts.KubeVersion
+	}
+	if v := conf.RuntimeKubeVersions[resolved]; v != "" {
+		return v
+	}
+	names := maps.Keys(conf.RuntimeKubeVersions)
+	sort.Strings(names)
+	for _, name := range names {
+		v := conf.RuntimeKubeVersions[name]
+		if v == "" {
+			continue
+		}
+		target, err := ResolveRuntime(name)
+		if err == nil && target == resolved {
+			return v
+		}
+	}
+	return consts.KubeVersion
+}
+
 func convertToInternalKwokctlConfiguration(config *configv1alpha1.KwokctlConfiguration) (*internalversion.KwokctlConfiguration, error) {
 	obj := setKwokctlConfigurationDefaults(config)
 	return internalversion.ConvertToInternalKwokctlConfiguration(obj)
@@ -171,8 +240,10 @@ func setKwokctlConfigurationDefaults(config *configv1alpha1.KwokctlConfiguration
 	}
 	conf.KwokVersion = version.AddPrefixV(envs.GetEnvWithPrefix("VERSION", conf.KwokVersion))
 
+	conf.Runtime = envs.GetEnvWithPrefix("RUNTIME", conf.Runtime)
+
 	if conf.KubeVersion == "" {
-		conf.KubeVersion = consts.KubeVersion
+		conf.KubeVersion = runtimeKubeVersion(conf)
 	}
 	conf.KubeVersion = version.AddPrefixV(envs.GetEnvWithPrefix("KUBE_VERSION", conf.KubeVersion))
 
@@ -192,7 +263,12 @@ func setKwokctlConfigurationDefaults(config *configv1alpha1.KwokctlConfiguration
 
 	conf.QuietPull = format.Ptr(envs.GetEnvWithPrefix("QUIET_PULL", *conf.QuietPull))
 
-	conf.Runtime = envs.GetEnvWithPrefix("RUNTIME", conf.Runtime)
+	conf.NetworkMTU = envs.GetEnvWithPrefix("NETWORK_MTU", conf.NetworkMTU)
+
+	conf.LogDriver = envs.GetEnvWithPrefix("LOG_DRIVER", conf.LogDriver)
+
+	conf.KubeApiserverInClusterAddress = envs.GetEnvWithPrefix("KUBE_APISERVER_IN_CLUSTER_ADDRESS", conf.KubeApiserverInClusterAddress)
+
 	if conf.Runtime == "" && len(conf.Runtimes) == 0 {
 		conf.Runtimes = []string{
 			consts.RuntimeTypeDocker,
@@ -256,9 +332,19 @@ func setKwokctlKubernetesConfig(conf *configv1alpha1.KwokctlConfigurationOptions
 
 	conf.KubeAuthorization = format.Ptr(envs.GetEnvWithPrefix("KUBE_AUTHORIZATION", *conf.KubeAuthorization))
 	conf.KubeAdmission = envs.GetEnvWithPrefix("KUBE_ADMISSION", conf.KubeAdmission)
+	conf.KubeAdmissionPlugins = envs.GetEnvWithPrefix("KUBE_ADMISSION_PLUGINS", conf.KubeAdmissionPlugins)
+	conf.KubeApiserverTLSMinVersion = envs.GetEnvWithPrefix("KUBE_APISERVER_TLS_MIN_VERSION", conf.KubeApiserverTLSMinVersion)
+	conf.KubeApiserverTLSCipherSuites = envs.GetEnvWithPrefix("KUBE_APISERVER_TLS_CIPHER_SUITES", conf.KubeApiserverTLSCipherSuites)
+	conf.KubeApiserverRequestTimeout = envs.GetEnvWithPrefix("KUBE_APISERVER_REQUEST_TIMEOUT", conf.KubeApiserverRequestTimeout)
+	conf.KubeApiserverMinRequestTimeout = envs.GetEnvWithPrefix("KUBE_APISERVER_MIN_REQUEST_TIMEOUT", conf.KubeApiserverMinRequestTimeout)
+	conf.KubeApiserverPriorityAndFairness = format.Ptr(envs.GetEnvWithPrefix("KUBE_APISERVER_PRIORITY_AND_FAIRNESS", *conf.KubeApiserverPriorityAndFairness))
+	conf.KubeApiserverMaxRequestsInflight = envs.GetEnvWithPrefix("KUBE_APISERVER_MAX_REQUESTS_INFLIGHT", conf.KubeApiserverMaxRequestsInflight)
+	conf.KubeApiserverMaxMutatingRequestsInflight = envs.GetEnvWithPrefix("KUBE_APISERVER_MAX_MUTATING_REQUESTS_INFLIGHT", conf.KubeApiserverMaxMutatingRequestsInflight)
 
 	conf.KubeApiserverPort = envs.GetEnvWithPrefix("KUBE_APISERVER_PORT", conf.KubeApiserverPort)
 	conf.KubeApiserverInsecurePort = envs.GetEnvWithPrefix("KUBE_APISERVER_INSECURE_PORT", conf.KubeApiserverInsecurePort)
+	conf.KubeApiserverReadOnlyPort = envs.GetEnvWithPrefix("KUBE_APISERVER_READONLY_PORT", conf.KubeApiserverReadOnlyPort)
+	conf.KubeApiserverUnixSocketProxy = envs.GetEnvWithPrefix("KUBE_APISERVER_UNIX_SOCKET_PROXY", conf.KubeApiserverUnixSocketProxy)
 
 	if conf.KubeFeatureGates == "" {
 		if conf.Mode == configv1alpha1.ModeStableFeatureGateAndAPI {
@@ -273,8 +359,15 @@ func setKwokctlKubernetesConfig(conf *configv1alpha1.KwokctlConfigurationOptions
 		}
 	}
 	conf.KubeRuntimeConfig = envs.GetEnvWithPrefix("KUBE_RUNTIME_CONFIG", conf.KubeRuntimeConfig)
+	conf.KubeServiceClusterIPRange = envs.GetEnvWithPrefix("KUBE_SERVICE_CLUSTER_IP_RANGE", conf.KubeServiceClusterIPRange)
 
 	conf.KubeAuditPolicy = envs.GetEnvWithPrefix("KUBE_AUDIT_POLICY", conf.KubeAuditPolicy)
+	conf.KubeAuditWebhook = envs.GetEnvWithPrefix("KUBE_AUDIT_WEBHOOK", conf.KubeAuditWebhook)
+	conf.KubeAuditLogMaxSize = envs.GetEnvWithPrefix("KUBE_AUDIT_LOG_MAXSIZE", conf.KubeAuditLogMaxSize)
+	conf.KubeAuditLogMaxAge = envs.GetEnvWithPrefix("KUBE_AUDIT_LOG_MAXAGE", conf.KubeAuditLogMaxAge)
+	conf.KubeAuditLogMaxBackup = envs.GetEnvWithPrefix("KUBE_AUDIT_LOG_MAXBACKUP", conf.KubeAuditLogMaxBackup)
+
+	conf.CABundle = envs.GetEnvWithPrefix("CA_BUNDLE", conf.CABundle)
 
 	kubectlBinaryPrefix := conf.KubeBinaryPrefix
 	if conf.KubeBinaryPrefix == "" {
@@ -361,6 +454,8 @@ func setKwokctlKwokConfig(conf *configv1alpha1.KwokctlConfigurationOptions) {
 	}
 	conf.KwokControllerImage = envs.GetEnvWithPrefix("CONTROLLER_IMAGE", conf.KwokControllerImage)
 	conf.KwokControllerPort = envs.GetEnvWithPrefix("CONTROLLER_PORT", conf.KwokControllerPort)
+	conf.KwokControllerProfilingPort = envs.GetEnvWithPrefix("CONTROLLER_PROFILING_PORT", conf.KwokControllerProfilingPort)
+	conf.ManageNodesParallelism = envs.GetEnvWithPrefix("MANAGE_NODES_PARALLELISM", conf.ManageNodesParallelism)
 }
 
 func setKwokctlEtcdConfig(conf *configv1alpha1.KwokctlConfigurationOptions) {
@@ -376,6 +471,17 @@ func setKwokctlEtcdConfig(conf *configv1alpha1.KwokctlConfigurationOptions) {
 
 	conf.EtcdBinary = envs.GetEnvWithPrefix("ETCD_BINARY", conf.EtcdBinary)
 
+	conf.EtcdQuotaBackendSize = envs.GetEnvWithPrefix("ETCD_QUOTA_BACKEND_SIZE", conf.EtcdQuotaBackendSize)
+
+	conf.EtcdUnsafeNoFsync = format.Ptr(envs.GetEnvWithPrefix("ETCD_UNSAFE_NO_FSYNC", *conf.EtcdUnsafeNoFsync))
+
+	conf.EtcdHeartbeatInterval = envs.GetEnvWithPrefix("ETCD_HEARTBEAT_INTERVAL", conf.EtcdHeartbeatInterval)
+	conf.EtcdElectionTimeout = envs.GetEnvWithPrefix("ETCD_ELECTION_TIMEOUT", conf.EtcdElectionTimeout)
+	conf.EtcdReplicas = envs.GetEnvWithPrefix("ETCD_REPLICAS", conf.EtcdReplicas)
+
+	conf.AutoSnapshotInterval = envs.GetEnvWithPrefix("AUTO_SNAPSHOT_INTERVAL", conf.AutoSnapshotInterval)
+	conf.AutoSnapshotRetention = envs.GetEnvWithPrefix("AUTO_SNAPSHOT_RETENTION", conf.AutoSnapshotRetention)
+
 	if conf.EtcdBinaryTar == "" {
 		conf.EtcdBinaryTar = conf.EtcdBinaryPrefix + "/etcd-v" + strings.TrimSuffix(conf.EtcdVersion, "-0") + "-" + GOOS + "-" + GOARCH + "." + func() string {
 			if GOOS == linux {
@@ -585,6 +691,16 @@ func joinImageURI(prefix, name, version string) string {
 	return prefix + "/" + name + ":" + version
 }
 
+// podReadyToStartContainersRelease is the release that the PodReadyToStartContainers condition is enabled by default.
+const podReadyToStartContainersRelease = 29
+
+// EnablePodReadyToStartContainers returns true if the pods of the version have the PodReadyToStartContainers condition,
+// the unknown version is considered as the latest.
+func EnablePodReadyToStartContainers(kubeVersion string) bool {
+	minor := parseRelease(kubeVersion)
+	return minor >= podReadyToStartContainersRelease || minor == -1
+}
+
 // parseRelease returns the release of the version.
 func parseRelease(ver string) int {
 	v, err := version.ParseVersion(ver)
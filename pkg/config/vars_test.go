@@ -0,0 +1,200 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	configv1alpha1 "sigs.k8s.io/kwok/pkg/apis/config/v1alpha1"
+	"sigs.k8s.io/kwok/pkg/consts"
+)
+
+func Test_runtimeKubeVersion(t *testing.T) {
+	versions := map[string]string{
+		consts.RuntimeTypeKind:   "v1.30.0",
+		consts.RuntimeTypeDocker: "v1.29.0",
+	}
+	tests := []struct {
+		name        string
+		runtime     string
+		flagRuntime string
+		versions    map[string]string
+		resolve     map[string]string
+		want        string
+	}{
+		{
+			name: "no runtime versions",
+			want: consts.KubeVersion,
+		},
+		{
+			name:     "runtime from configuration",
+			runtime:  consts.RuntimeTypeKind,
+			versions: versions,
+			want:     "v1.30.0",
+		},
+		{
+			name:        "runtime from flag",
+			runtime:     consts.RuntimeTypeKind,
+			flagRuntime: consts.RuntimeTypeDocker,
+			versions:    versions,
+			want:        "v1.29.0",
+		},
+		{
+			name:        "runtime from flag by alias",
+			flagRuntime: "compose",
+			versions:    versions,
+			resolve:     map[string]string{"compose": consts.RuntimeTypeDocker},
+			want:        "v1.29.0",
+		},
+		{
+			name:     "version keyed by alias",
+			runtime:  consts.RuntimeTypeNerdctl,
+			versions: map[string]string{"containerd": "v1.28.0"},
+			resolve:  map[string]string{"containerd": consts.RuntimeTypeNerdctl},
+			want:     "v1.28.0",
+		},
+		{
+			name:     "runtime without version",
+			runtime:  consts.RuntimeTypeBinary,
+			versions: versions,
+			want:     consts.KubeVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtimeFromFlag = tt.flagRuntime
+			resolveRuntime := ResolveRuntime
+			ResolveRuntime = func(name string) (string, error) {
+				if target, ok := tt.resolve[name]; ok {
+					return target, nil
+				}
+				return name, nil
+			}
+			defer func() {
+				runtimeFromFlag = ""
+				ResolveRuntime = resolveRuntime
+			}()
+			got := runtimeKubeVersion(&configv1alpha1.KwokctlConfigurationOptions{
+				Runtime:             tt.runtime,
+				RuntimeKubeVersions: tt.versions,
+			})
+			if got != tt.want {
+				t.Errorf("runtimeKubeVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnablePodReadyToStartContainers(t *testing.T) {
+	tests := []struct {
+		kubeVersion string
+		want        bool
+	}{
+		{
+			kubeVersion: "v1.28.0",
+			want:        false,
+		},
+		{
+			kubeVersion: "v1.29.0",
+			want:        true,
+		},
+		{
+			kubeVersion: "v1.31.2-eks-1234",
+			want:        true,
+		},
+		{
+			kubeVersion: "v1.19.0",
+			want:        false,
+		},
+		{
+			kubeVersion: "unknown",
+			want:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kubeVersion, func(t *testing.T) {
+			if got := EnablePodReadyToStartContainers(tt.kubeVersion); got != tt.want {
+				t.Errorf("EnablePodReadyToStartContainers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateClusterName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:  "default",
+			input: "kwok",
+		},
+		{
+			name:  "with dash and digits",
+			input: "kwok-e2e-1",
+		},
+		{
+			name:  "longest",
+			input: strings.Repeat("a", maxClusterNameLength),
+		},
+		{
+			name:    "empty",
+			input:   "",
+			wantErr: true,
+		},
+		{
+			name:    "uppercase",
+			input:   "Kwok",
+			wantErr: true,
+		},
+		{
+			name:    "underscore",
+			input:   "kwok_1",
+			wantErr: true,
+		},
+		{
+			name:    "dot",
+			input:   "kwok.1",
+			wantErr: true,
+		},
+		{
+			name:    "leading dash",
+			input:   "-kwok",
+			wantErr: true,
+		},
+		{
+			name:    "trailing dash",
+			input:   "kwok-",
+			wantErr: true,
+		},
+		{
+			name:    "too long",
+			input:   strings.Repeat("a", maxClusterNameLength+1),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateClusterName(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateClusterName(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/kwok/pkg/utils/path"
+)
+
+// ClusterWorkdir returns the workdir of the cluster, which is the directory of the cluster under ClustersDir,
+// or the custom workdir it links to if the cluster was created with --workdir.
+func ClusterWorkdir(name string) string {
+	workdir := path.Join(ClustersDir, name)
+	target, err := os.Readlink(workdir)
+	if err != nil {
+		return workdir
+	}
+	if !filepath.IsAbs(target) {
+		target = path.Join(ClustersDir, target)
+	}
+	return target
+}
+
+// IsCustomClusterWorkdir returns true if the cluster was created with a custom workdir.
+func IsCustomClusterWorkdir(name string) bool {
+	fi, err := os.Lstat(path.Join(ClustersDir, name))
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeSymlink != 0
+}
+
+// LinkClusterWorkdir records the custom workdir of the cluster by linking to it from ClustersDir,
+// so that the other commands find the cluster by its name.
+func LinkClusterWorkdir(name, workdir string) error {
+	workdir, err := path.Expand(workdir)
+	if err != nil {
+		return err
+	}
+
+	link := path.Join(ClustersDir, name)
+	if _, err := os.Lstat(link); err == nil {
+		if ClusterWorkdir(name) == workdir {
+			return nil
+		}
+		return fmt.Errorf("cluster %q already exists at %s", name, ClusterWorkdir(name))
+	}
+
+	err = os.MkdirAll(workdir, 0750)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(ClustersDir, 0750)
+	if err != nil {
+		return err
+	}
+	return os.Symlink(workdir, link)
+}
+
+// UnlinkClusterWorkdir removes the link to the custom workdir of the cluster, if any.
+func UnlinkClusterWorkdir(name string) error {
+	if !IsCustomClusterWorkdir(name) {
+		return nil
+	}
+	return os.Remove(path.Join(ClustersDir, name))
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClusterWorkdir(t *testing.T) {
+	origin := ClustersDir
+	t.Cleanup(func() {
+		ClustersDir = origin
+	})
+	ClustersDir = filepath.Join(t.TempDir(), "clusters")
+	custom := filepath.Join(t.TempDir(), "custom")
+
+	if got, want := ClusterWorkdir("kwok"), filepath.Join(ClustersDir, "kwok"); got != want {
+		t.Fatalf("ClusterWorkdir() = %q, want %q", got, want)
+	}
+	if IsCustomClusterWorkdir("kwok") {
+		t.Fatalf("IsCustomClusterWorkdir() = true, want false")
+	}
+
+	err := LinkClusterWorkdir("kwok", custom)
+	if err != nil {
+		t.Fatalf("LinkClusterWorkdir() error = %v", err)
+	}
+	if got := ClusterWorkdir("kwok"); got != custom {
+		t.Fatalf("ClusterWorkdir() = %q, want %q", got, custom)
+	}
+	if !IsCustomClusterWorkdir("kwok") {
+		t.Fatalf("IsCustomClusterWorkdir() = false, want true")
+	}
+	if fi, err := os.Stat(custom); err != nil || !fi.IsDir() {
+		t.Fatalf("custom workdir is not created: %v", err)
+	}
+
+	err = LinkClusterWorkdir("kwok", custom)
+	if err != nil {
+		t.Fatalf("LinkClusterWorkdir() with the same workdir error = %v", err)
+	}
+	err = LinkClusterWorkdir("kwok", filepath.Join(t.TempDir(), "other"))
+	if err == nil {
+		t.Fatalf("LinkClusterWorkdir() with another workdir want error")
+	}
+
+	err = UnlinkClusterWorkdir("kwok")
+	if err != nil {
+		t.Fatalf("UnlinkClusterWorkdir() error = %v", err)
+	}
+	if IsCustomClusterWorkdir("kwok") {
+		t.Fatalf("IsCustomClusterWorkdir() = true after unlink, want false")
+	}
+	if _, err := os.Stat(custom); err != nil {
+		t.Fatalf("custom workdir is removed by unlink: %v", err)
+	}
+
+	err = os.MkdirAll(filepath.Join(ClustersDir, "default"), 0750)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = UnlinkClusterWorkdir("default")
+	if err != nil {
+		t.Fatalf("UnlinkClusterWorkdir() of default workdir error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(ClustersDir, "default")); err != nil {
+		t.Fatalf("default workdir is removed by unlink: %v", err)
+	}
+}
+
+func TestLinkClusterWorkdirRelative(t *testing.T) {
+	origin := ClustersDir
+	t.Cleanup(func() {
+		ClustersDir = origin
+	})
+	ClustersDir = filepath.Join(t.TempDir(), "clusters")
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+	})
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = LinkClusterWorkdir("kwok", "./custom/../workdir")
+	if err != nil {
+		t.Fatalf("LinkClusterWorkdir() error = %v", err)
+	}
+
+	want := filepath.Join(dir, "workdir")
+	target, err := os.Readlink(filepath.Join(ClustersDir, "kwok"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target != want {
+		t.Fatalf("link target = %q, want %q", target, want)
+	}
+	if got := ClusterWorkdir("kwok"); got != want {
+		t.Fatalf("ClusterWorkdir() = %q, want %q", got, want)
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Fatalf("workdir is not created: %v", err)
+	}
+
+	// Relinking the same workdir by another relative path is a no-op.
+	err = os.Chdir(filepath.Join(dir, "workdir"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = LinkClusterWorkdir("kwok", ".")
+	if err != nil {
+		t.Fatalf("LinkClusterWorkdir() with the same workdir error = %v", err)
+	}
+}
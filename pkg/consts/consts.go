@@ -104,6 +104,7 @@ const (
 	ComponentEtcd                       = "etcd"
 	ComponentKubeApiserver              = "kube-apiserver"
 	ComponentKubeApiserverInsecureProxy = "kube-apiserver-insecure-proxy"
+	ComponentKubeApiserverSocketProxy   = "kube-apiserver-socket-proxy"
 	ComponentKubeControllerManager      = "kube-controller-manager"
 	ComponentKubeScheduler              = "kube-scheduler"
 	ComponentKwokController             = "kwok-controller"
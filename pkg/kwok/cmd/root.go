@@ -20,7 +20,9 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -33,6 +35,7 @@ import (
 	nodeheartbeat "sigs.k8s.io/kwok/kustomize/stage/node/heartbeat"
 	nodeheartbeatwithlease "sigs.k8s.io/kwok/kustomize/stage/node/heartbeat-with-lease"
 	podfast "sigs.k8s.io/kwok/kustomize/stage/pod/fast"
+	podreadytostartcontainers "sigs.k8s.io/kwok/kustomize/stage/pod/ready-to-start-containers"
 	"sigs.k8s.io/kwok/pkg/apis/internalversion"
 	"sigs.k8s.io/kwok/pkg/apis/v1alpha1"
 	"sigs.k8s.io/kwok/pkg/client/clientset/versioned"
@@ -45,6 +48,7 @@ import (
 	"sigs.k8s.io/kwok/pkg/utils/format"
 	"sigs.k8s.io/kwok/pkg/utils/kubeconfig"
 	"sigs.k8s.io/kwok/pkg/utils/path"
+	"sigs.k8s.io/kwok/pkg/utils/poddns"
 	"sigs.k8s.io/kwok/pkg/utils/slices"
 	"sigs.k8s.io/kwok/pkg/utils/version"
 	"sigs.k8s.io/kwok/pkg/utils/wait"
@@ -93,8 +97,28 @@ func NewCommand(ctx context.Context) *cobra.Command {
 	cmd.Flags().StringVar(&flags.Kubeconfig, "kubeconfig", flags.Kubeconfig, "Path to the kubeconfig file to use")
 	cmd.Flags().StringVar(&flags.Master, "master", flags.Master, "The address of the Kubernetes API server (overrides any value in kubeconfig).")
 	cmd.Flags().StringVar(&flags.Options.ServerAddress, "server-address", flags.Options.ServerAddress, "Address to expose the server on")
+	cmd.Flags().BoolVar(&flags.Options.EnableContentionProfiling, "enable-contention-profiling", flags.Options.EnableContentionProfiling, "Enable block and mutex profiling, if the debugging and profiling handlers are enabled")
+	cmd.Flags().StringVar(&flags.Options.ProfilingAddress, "profiling-address", flags.Options.ProfilingAddress, "Address to expose the /debug/pprof on a dedicated listener if the debugging and profiling handlers are enabled")
+	cmd.Flags().UintVar(&flags.Options.NodePlayStageParallelism, "manage-nodes-parallelism", flags.Options.NodePlayStageParallelism, "Number of the node stages that are allowed to be played in parallel")
 	cmd.Flags().UintVar(&flags.Options.NodeLeaseDurationSeconds, "node-lease-duration-seconds", flags.Options.NodeLeaseDurationSeconds, "Duration of node lease seconds")
+	cmd.Flags().UintVar(&flags.Options.NodeLeaseRenewIntervalSeconds, "node-lease-renew-interval-seconds", flags.Options.NodeLeaseRenewIntervalSeconds, "Interval of renewing the node leases in seconds, defaults to a quarter of the lease duration")
+	cmd.Flags().Float64Var(&flags.Options.NodeLeaseRenewIntervalJitter, "node-lease-renew-interval-jitter", flags.Options.NodeLeaseRenewIntervalJitter, "Factor of the random jitter added to the renew interval of the node leases, defaults to 0.04")
 	cmd.Flags().StringSliceVar(&flags.Options.EnableCRDs, "enable-crds", flags.Options.EnableCRDs, "List of CRDs to enable")
+	cmd.Flags().BoolVar(&flags.Options.EnableStageWebhook, "enable-stage-webhook", flags.Options.EnableStageWebhook, "Enable the webhook of stages, which sends resources to external HTTP endpoints")
+	cmd.Flags().BoolVar(&flags.Options.EnableNodeVolumeStatus, "enable-node-volume-status", flags.Options.EnableNodeVolumeStatus, "Report the volumes used by the pods on a node in the volumesInUse and volumesAttached of the node status")
+	cmd.Flags().BoolVar(&flags.Options.EnableNodeMemoryPressureEviction, "enable-node-memory-pressure-eviction", flags.Options.EnableNodeMemoryPressureEviction, "Evict the pods on a node whose MemoryPressure condition is true, by QoS class and priority")
+	cmd.Flags().StringSliceVar(&flags.Options.DisableMetricsFor, "disable-metrics-for", flags.Options.DisableMetricsFor, "List of the metric dimensions to disable, any of node, pod or container")
+	cmd.Flags().StringVar(&flags.Options.PodDNSPolicy, "pod-dns-policy", flags.Options.PodDNSPolicy, "dnsPolicy set by the mutating webhook on the created pods that have the default one and no dnsConfig")
+	cmd.Flags().StringSliceVar(&flags.Options.PodDNSNameservers, "pod-dns-nameservers", flags.Options.PodDNSNameservers, "Nameservers of the dnsConfig set by the mutating webhook on the created pods that do not have one")
+	cmd.Flags().StringSliceVar(&flags.Options.PodDNSSearches, "pod-dns-searches", flags.Options.PodDNSSearches, "Search domains of the dnsConfig set by the mutating webhook on the created pods that do not have one")
+	cmd.Flags().StringSliceVar(&flags.Options.PodDNSOptions, "pod-dns-options", flags.Options.PodDNSOptions, "Options of the dnsConfig set by the mutating webhook on the created pods that do not have one, in the form name or name:value")
+	cmd.Flags().StringVar(&flags.Options.RBACSelfCheckPolicy, "rbac-self-check-policy", flags.Options.RBACSelfCheckPolicy, "What to do if the permissions required by kwok are missing on startup, one of Ignore, Warn or Fail")
+	cmd.Flags().StringVar(&flags.Options.PodAdmissionFailurePolicy, "pod-admission-failure-policy", flags.Options.PodAdmissionFailurePolicy, "What to do with the pods that do not fit the node affinity, resources or NoExecute taints of their node, one of Ignore, Pending or Fail")
+	cmd.Flags().Float64Var(&flags.Options.NodeCPUOvercommit, "node-cpu-overcommit", flags.Options.NodeCPUOvercommit, "Factor to scale the cpu allocatable of the nodes relative to the capacity, e.g. 2 to fit twice as many pods, 0 disables it")
+	cmd.Flags().Float64Var(&flags.Options.NodeMemoryOvercommit, "node-memory-overcommit", flags.Options.NodeMemoryOvercommit, "Factor to scale the memory allocatable of the nodes relative to the capacity, e.g. 2 to fit twice as many pods, 0 disables it")
+	cmd.Flags().Int64Var(&flags.Options.GlobalDelayJitterMilliseconds, "global-delay-jitter-milliseconds", flags.Options.GlobalDelayJitterMilliseconds, "Maximum random delay in milliseconds added to the delay of all stages, to simulate a noisy cluster")
+	cmd.Flags().BoolVar(&flags.Options.EnableClientTransportTuning, "enable-client-transport-tuning", flags.Options.EnableClientTransportTuning, "Tune the transport of the client to the apiserver for large simulations, with the clientTransport options of the configuration")
+	cmd.Flags().BoolVar(&flags.Options.DisregardFinalizers, "disregard-finalizers", flags.Options.DisregardFinalizers, "Delete nodes and pods without waiting for the finalizers added by other controllers")
 
 	cmd.Flags().BoolVar(&flags.Options.EnableCNI, "experimental-enable-cni", flags.Options.EnableCNI, "Experimental support for getting pod ip from CNI, for CNI-related components, Only works with Linux")
 	_ = cmd.Flags().MarkDeprecated("experimental-enable-cni", "It will be removed and will be supported in the form of plugins")
@@ -138,13 +162,20 @@ func runE(ctx context.Context, flags *flagpole) error {
 		}
 	}
 
-	stagesData := config.FilterWithTypeFromContext[*internalversion.Stage](ctx)
-	err := checkConfigOrCRD(flags.Options.EnableCRDs, v1alpha1.StageKind, stagesData)
-	if err != nil {
-		return err
+	switch flags.Options.RBACSelfCheckPolicy {
+	case "", rbacSelfCheckPolicyIgnore, rbacSelfCheckPolicyWarn, rbacSelfCheckPolicyFail:
+	default:
+		return fmt.Errorf("invalid rbac self check policy %q, must be one of %s, %s or %s",
+			flags.Options.RBACSelfCheckPolicy, rbacSelfCheckPolicyIgnore, rbacSelfCheckPolicyWarn, rbacSelfCheckPolicyFail)
 	}
 
+	stagesData := config.FilterWithTypeFromContext[*internalversion.Stage](ctx)
+
+	var err error
+	var staticStages []*internalversion.Stage
 	var groupStages map[internalversion.StageResourceRef][]*internalversion.Stage
+	var useDefaultPodStages bool
+	podRef := internalversion.StageResourceRef{APIGroup: "v1", Kind: "Pod"}
 
 	if !slices.Contains(flags.Options.EnableCRDs, v1alpha1.StageKind) {
 		groupStages = slices.GroupBy(stagesData, func(stage *internalversion.Stage) internalversion.StageResourceRef {
@@ -152,7 +183,6 @@ func runE(ctx context.Context, flags *flagpole) error {
 		})
 
 		nodeRef := internalversion.StageResourceRef{APIGroup: "v1", Kind: "Node"}
-		podRef := internalversion.StageResourceRef{APIGroup: "v1", Kind: "Pod"}
 
 		if len(groupStages[nodeRef]) == 0 {
 			logger.Warn("No node stages found, using default node stages")
@@ -162,12 +192,12 @@ func runE(ctx context.Context, flags *flagpole) error {
 			}
 		}
 
-		if len(groupStages[podRef]) == 0 {
-			groupStages[podRef], err = getDefaultPodStages()
-			if err != nil {
-				return err
-			}
-		}
+		useDefaultPodStages = len(groupStages[podRef]) == 0
+	} else if len(stagesData) != 0 {
+		logger.Info("Merging the stages from --config with the Stage CRD, the CRD ones override the config ones with the same name",
+			"count", len(stagesData),
+		)
+		staticStages = stagesData
 	}
 
 	if flags.Kubeconfig == "" && flags.Master == "" {
@@ -184,6 +214,44 @@ func runE(ctx context.Context, flags *flagpole) error {
 		return err
 	}
 
+	if flags.Options.EnableClientTransportTuning {
+		transportConfig := clientTransportConfig(flags.Options)
+		err = client.ConfigureTransport(restConfig, transportConfig)
+		if err != nil {
+			return err
+		}
+		logger.Info("Client transport tuning is enabled",
+			"maxIdleConnsPerHost", transportConfig.MaxIdleConnsPerHost,
+			"maxConnsPerHost", transportConfig.MaxConnsPerHost,
+			"keepAlive", transportConfig.KeepAlive,
+			"idleConnTimeout", transportConfig.IdleConnTimeout,
+			"disableHTTP2", transportConfig.DisableHTTP2,
+		)
+	}
+
+	if flags.Options.FaultInjectionErrorRate > 0 || flags.Options.FaultInjectionLatencyMilliseconds > 0 {
+		faultInjection := controllers.FaultInjectionConfig{
+			ErrorRate: flags.Options.FaultInjectionErrorRate,
+			Latency:   time.Duration(flags.Options.FaultInjectionLatencyMilliseconds) * time.Millisecond,
+		}
+		err = faultInjection.Validate()
+		if err != nil {
+			return err
+		}
+		restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return controllers.NewFaultInjectionRoundTripper(rt, faultInjection)
+		})
+		logger.Warn("Fault injection is enabled",
+			"errorRate", faultInjection.ErrorRate,
+			"latency", faultInjection.Latency,
+		)
+	}
+
+	err = podDNSConfig(flags).Validate()
+	if err != nil {
+		return fmt.Errorf("invalid pod dns: %w", err)
+	}
+
 	dynamicClient, err := clientset.ToDynamicClient()
 	if err != nil {
 		return err
@@ -215,6 +283,22 @@ func runE(ctx context.Context, flags *flagpole) error {
 		return err
 	}
 
+	err = rbacSelfCheck(ctx, typedClient, &flags.Options)
+	if err != nil {
+		return err
+	}
+
+	if useDefaultPodStages {
+		serverVersion, err := typedClient.Discovery().ServerVersion()
+		if err != nil {
+			return err
+		}
+		groupStages[podRef], err = getDefaultPodStages(serverVersion.GitVersion)
+		if err != nil {
+			return err
+		}
+	}
+
 	switch {
 	case flags.Options.ManageSingleNode != "":
 		logger.Info("Watch single node",
@@ -236,7 +320,12 @@ func runE(ctx context.Context, flags *flagpole) error {
 	ctx = log.NewContext(ctx, logger.With("id", id))
 
 	metrics := config.FilterWithTypeFromContext[*internalversion.Metric](ctx)
-	enableMetrics := len(metrics) != 0 || slices.Contains(flags.Options.EnableCRDs, v1alpha1.MetricKind)
+	disabledMetricsDimensions, err := metricsDimensions(flags.Options.DisableMetricsFor)
+	if err != nil {
+		return err
+	}
+	enableMetrics := (len(metrics) != 0 || slices.Contains(flags.Options.EnableCRDs, v1alpha1.MetricKind)) &&
+		len(disabledMetricsDimensions) != len(allMetricsDimensions)
 	ctr, err := controllers.NewController(controllers.Config{
 		Clock:                                 clock.RealClock{},
 		DynamicClient:                         dynamicClient,
@@ -247,7 +336,15 @@ func runE(ctx context.Context, flags *flagpole) error {
 		TypedKwokClient:                       typedKwokClient,
 		EnableCNI:                             flags.Options.EnableCNI,
 		EnableMetrics:                         enableMetrics,
-		EnablePodCache:                        enableMetrics,
+		EnablePodCache:                        enableMetrics || flags.Options.EnableNodeMemoryPressureEviction,
+		EnableStageWebhook:                    flags.Options.EnableStageWebhook,
+		EnableNodeVolumeStatus:                flags.Options.EnableNodeVolumeStatus,
+		EnableNodeMemoryPressureEviction:      flags.Options.EnableNodeMemoryPressureEviction,
+		DisregardFinalizers:                   flags.Options.DisregardFinalizers,
+		PodAdmissionFailurePolicy:             flags.Options.PodAdmissionFailurePolicy,
+		NodeCPUOvercommit:                     flags.Options.NodeCPUOvercommit,
+		NodeMemoryOvercommit:                  flags.Options.NodeMemoryOvercommit,
+		GlobalDelayJitter:                     time.Duration(flags.Options.GlobalDelayJitterMilliseconds) * time.Millisecond,
 		ManageSingleNode:                      flags.Options.ManageSingleNode,
 		ManageAllNodes:                        flags.Options.ManageAllNodes,
 		ManageNodesWithAnnotationSelector:     flags.Options.ManageNodesWithAnnotationSelector,
@@ -259,9 +356,17 @@ func runE(ctx context.Context, flags *flagpole) error {
 		NodeName:                              flags.Options.NodeName,
 		NodePort:                              flags.Options.NodePort,
 		PodPlayStageParallelism:               flags.Options.PodPlayStageParallelism,
+		PodPlayStageParallelismRamp:           time.Duration(flags.Options.PodPlayStageParallelismRampMilliseconds) * time.Millisecond,
+		PodPlayStageParallelismRampJitter:     time.Duration(flags.Options.PodPlayStageParallelismRampJitterMilliseconds) * time.Millisecond,
+		PodStatusUpdateParallelism:            flags.Options.PodStatusUpdateParallelism,
+		PodPreprocessParallelism:              flags.Options.PodPreprocessParallelism,
 		NodePlayStageParallelism:              flags.Options.NodePlayStageParallelism,
+		NodePreprocessParallelism:             flags.Options.NodePreprocessParallelism,
 		LocalStages:                           groupStages,
+		StaticStages:                          staticStages,
 		NodeLeaseParallelism:                  flags.Options.NodeLeaseParallelism,
+		NodeLeaseRenewIntervalSeconds:         flags.Options.NodeLeaseRenewIntervalSeconds,
+		NodeLeaseRenewIntervalJitter:          flags.Options.NodeLeaseRenewIntervalJitter,
 		NodeLeaseDurationSeconds:              flags.Options.NodeLeaseDurationSeconds,
 		ID:                                    id,
 	})
@@ -279,7 +384,17 @@ func runE(ctx context.Context, flags *flagpole) error {
 		return err
 	}
 
+	if flags.Options.ProfilingAddress != "" && flags.Options.EnableDebuggingHandlers && flags.Options.EnableProfilingHandler {
+		go func() {
+			err := server.RunProfilingServer(ctx, flags.Options.ProfilingAddress, flags.Options.EnableContentionProfiling)
+			if err != nil {
+				logger.Error("Failed to run profiling server", err)
+			}
+		}()
+	}
+
 	<-ctx.Done()
+	ctr.Wait()
 	return nil
 }
 
@@ -357,6 +472,10 @@ func startServer(ctx context.Context, flags *flagpole, ctr *controllers.Controll
 		if err != nil {
 			return err
 		}
+		disabledMetricsDimensions, err := metricsDimensions(flags.Options.DisableMetricsFor)
+		if err != nil {
+			return err
+		}
 
 		conf := server.Config{
 			TypedKwokClient:       typedKwokClient,
@@ -375,6 +494,8 @@ func startServer(ctx context.Context, flags *flagpole, ctr *controllers.Controll
 			DataSource:            ctr,
 			NodeCacheGetter:       ctr.GetNodeCache(),
 			PodCacheGetter:        ctr.GetPodCache(),
+
+			DisabledMetricsDimensions: disabledMetricsDimensions,
 		}
 		svc, err := server.NewServer(conf)
 		if err != nil {
@@ -386,11 +507,17 @@ func startServer(ctx context.Context, flags *flagpole, ctr *controllers.Controll
 
 		if flags.Options.EnableDebuggingHandlers {
 			svc.InstallDebuggingHandlers()
+			svc.InstallManageNodesSelector()
+			svc.InstallNodePods()
 			svc.InstallProfilingHandler(flags.Options.EnableProfilingHandler, flags.Options.EnableContentionProfiling)
 		} else {
 			svc.InstallDebuggingDisabledHandlers()
 		}
 
+		if dns := podDNSConfig(flags); !dns.IsEmpty() {
+			svc.InstallPodDNSWebhook(dns)
+		}
+
 		err = svc.InstallCRD(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to install crd: %w", err)
@@ -415,12 +542,24 @@ func startServer(ctx context.Context, flags *flagpole, ctr *controllers.Controll
 				}
 			}
 		}()
+	} else if !podDNSConfig(flags).IsEmpty() {
+		logger.Warn("The pod dns is ignored, because the mutating webhook is served only with --server-address")
 	}
 
 	<-ctx.Done()
 	return nil
 }
 
+// podDNSConfig returns the DNS settings set by the mutating webhook on the created pods
+func podDNSConfig(flags *flagpole) poddns.Config {
+	return poddns.Config{
+		Policy:      flags.Options.PodDNSPolicy,
+		Nameservers: flags.Options.PodDNSNameservers,
+		Searches:    flags.Options.PodDNSSearches,
+		Options:     flags.Options.PodDNSOptions,
+	}
+}
+
 func checkConfigOrCRD[T metav1.Object](crds []string, kind string, crs []T) error {
 	if slices.Contains(crds, kind) && len(crs) != 0 {
 		return fmt.Errorf("%s already exists in --config, so please remove it, or remove %s from --enable-crd", kind, kind)
@@ -429,6 +568,56 @@ func checkConfigOrCRD[T metav1.Object](crds []string, kind string, crs []T) erro
 	return nil
 }
 
+// allMetricsDimensions is the dimensions of the metrics that can be disabled
+var allMetricsDimensions = []internalversion.Dimension{
+	internalversion.DimensionNode,
+	internalversion.DimensionPod,
+	internalversion.DimensionContainer,
+}
+
+// metricsDimensions converts the names of the dimensions to disable, ignoring the duplicates
+func metricsDimensions(names []string) ([]internalversion.Dimension, error) {
+	dimensions := make([]internalversion.Dimension, 0, len(names))
+	for _, name := range names {
+		dimension := internalversion.Dimension(name)
+		if !slices.Contains(allMetricsDimensions, dimension) {
+			return nil, fmt.Errorf("invalid metrics dimension %q, must be one of node, pod or container", name)
+		}
+		if !slices.Contains(dimensions, dimension) {
+			dimensions = append(dimensions, dimension)
+		}
+	}
+	return dimensions, nil
+}
+
+// The defaults of the client transport tuning, for the large simulations
+const (
+	defaultClientTransportMaxIdleConnsPerHost = 100
+	defaultClientTransportKeepAlive           = 30 * time.Second
+	defaultClientTransportIdleConnTimeout     = 90 * time.Second
+)
+
+// clientTransportConfig returns the transport config from the options, with the unset ones defaulted
+func clientTransportConfig(opts internalversion.KwokConfigurationOptions) client.TransportConfig {
+	conf := client.TransportConfig{
+		MaxIdleConnsPerHost: opts.ClientTransportMaxIdleConnsPerHost,
+		MaxConnsPerHost:     opts.ClientTransportMaxConnsPerHost,
+		KeepAlive:           time.Duration(opts.ClientTransportKeepAliveSeconds) * time.Second,
+		IdleConnTimeout:     time.Duration(opts.ClientTransportIdleConnTimeoutSeconds) * time.Second,
+		DisableHTTP2:        opts.ClientTransportDisableHTTP2,
+	}
+	if conf.MaxIdleConnsPerHost <= 0 {
+		conf.MaxIdleConnsPerHost = defaultClientTransportMaxIdleConnsPerHost
+	}
+	if conf.KeepAlive <= 0 {
+		conf.KeepAlive = defaultClientTransportKeepAlive
+	}
+	if conf.IdleConnTimeout <= 0 {
+		conf.IdleConnTimeout = defaultClientTransportIdleConnTimeout
+	}
+	return conf
+}
+
 func waitForReady(ctx context.Context, clientset kubernetes.Interface) error {
 	logger := log.FromContext(ctx)
 	backoff := wait.Backoff{
@@ -458,6 +647,45 @@ func waitForReady(ctx context.Context, clientset kubernetes.Interface) error {
 	return nil
 }
 
+const (
+	rbacSelfCheckPolicyIgnore = "Ignore"
+	rbacSelfCheckPolicyWarn   = "Warn"
+	rbacSelfCheckPolicyFail   = "Fail"
+)
+
+// rbacSelfCheck reviews the permissions required by kwok, so the missing ones are reported on startup
+// instead of failing the requests later.
+func rbacSelfCheck(ctx context.Context, clientset kubernetes.Interface, opts *internalversion.KwokConfigurationOptions) error {
+	policy := opts.RBACSelfCheckPolicy
+	if policy == "" || policy == rbacSelfCheckPolicyIgnore {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	missing, err := controllers.CheckRBAC(ctx, clientset, controllers.RequiredRBACRules(opts))
+	if err != nil {
+		if policy == rbacSelfCheckPolicyFail {
+			return err
+		}
+		logger.Warn("Failed to check the permissions", "err", err)
+		return nil
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	summary := slices.Map(missing, func(rule controllers.RBACRule) string {
+		return rule.String()
+	})
+	if policy == rbacSelfCheckPolicyFail {
+		return fmt.Errorf("missing permissions: %s", strings.Join(summary, "; "))
+	}
+	logger.Warn("Missing permissions, the controllers may fail to manage the resources",
+		"missing", summary,
+	)
+	return nil
+}
+
 func getDefaultNodeStages(lease bool) ([]*internalversion.Stage, error) {
 	nodeStages := []*internalversion.Stage{}
 	nodeInitStage, err := config.UnmarshalWithType[*internalversion.Stage](nodefast.DefaultNodeInit)
@@ -479,10 +707,14 @@ func getDefaultNodeStages(lease bool) ([]*internalversion.Stage, error) {
 	return nodeStages, nil
 }
 
-func getDefaultPodStages() ([]*internalversion.Stage, error) {
-	return slices.MapWithError([]string{
+func getDefaultPodStages(kubeVersion string) ([]*internalversion.Stage, error) {
+	rawStages := []string{
 		podfast.DefaultPodReady,
 		podfast.DefaultPodComplete,
 		podfast.DefaultPodDelete,
-	}, config.UnmarshalWithType[*internalversion.Stage, string])
+	}
+	if config.EnablePodReadyToStartContainers(kubeVersion) {
+		rawStages = append(rawStages, podreadytostartcontainers.DefaultPodReadyToStartContainers)
+	}
+	return slices.MapWithError(rawStages, config.UnmarshalWithType[*internalversion.Stage, string])
 }
@@ -19,7 +19,9 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"sync"
 	"time"
 
 	coordinationv1 "k8s.io/api/coordination/v1"
@@ -47,6 +49,7 @@ import (
 	"sigs.k8s.io/kwok/pkg/utils/gotpl"
 	"sigs.k8s.io/kwok/pkg/utils/informer"
 	"sigs.k8s.io/kwok/pkg/utils/lifecycle"
+	"sigs.k8s.io/kwok/pkg/utils/maps"
 	"sigs.k8s.io/kwok/pkg/utils/patch"
 	"sigs.k8s.io/kwok/pkg/utils/queue"
 	"sigs.k8s.io/kwok/pkg/utils/slices"
@@ -68,22 +71,29 @@ type Controller struct {
 	broadcaster record.EventBroadcaster
 	recorder    record.EventRecorder
 
-	nodeCacheGetter      informer.Getter[*corev1.Node]
+	nodeCacheGetter      *informer.ReplaceableGetter[*corev1.Node]
 	podCacheGetter       informer.Getter[*corev1.Pod]
+	pvcCacheGetter       informer.Getter[*corev1.PersistentVolumeClaim]
+	pvCacheGetter        informer.Getter[*corev1.PersistentVolume]
 	nodeLeaseCacheGetter informer.Getter[*coordinationv1.Lease]
 
 	onNodeManagedFunc   func(nodeName string)
 	onNodeUnmanagedFunc func(nodeName string)
 	readOnlyFunc        func(nodeName string) bool
 
+	manageNodesSelectorMut            sync.Mutex
 	manageNodesWithLabelSelector      string
 	manageNodesWithAnnotationSelector string
 	manageNodesWithFieldSelector      string
+	nodesWatchCtx                     context.Context
+	nodesWatchCancel                  context.CancelFunc
 	manageNodeLeasesWithFieldSelector string
 	managePodsWithFieldSelector       string
 
 	nodesChan chan informer.Event[*corev1.Node]
 	podsChan  chan informer.Event[*corev1.Pod]
+	pvcsChan  chan informer.Event[*corev1.PersistentVolumeClaim]
+	pvsChan   chan informer.Event[*corev1.PersistentVolume]
 
 	nodeLeasesInformer *informer.Informer[*coordinationv1.Lease, *coordinationv1.LeaseList]
 	nodesInformer      *informer.Informer[*corev1.Node, *corev1.NodeList]
@@ -95,6 +105,10 @@ type Controller struct {
 
 	podOnNodeManageQueue queue.Queue[string]
 	nodeManageQueue      queue.Queue[string]
+
+	stageWebhookClient *http.Client
+
+	shutdown *shutdownOrder
 }
 
 // Config is the configuration for the controller
@@ -118,13 +132,29 @@ type Config struct {
 	NodeName                              string
 	NodePort                              int
 	LocalStages                           map[internalversion.StageResourceRef][]*internalversion.Stage
+	StaticStages                          []*internalversion.Stage
 	PodPlayStageParallelism               uint
+	PodPlayStageParallelismRamp           time.Duration
+	PodPlayStageParallelismRampJitter     time.Duration
+	PodStatusUpdateParallelism            uint
+	PodPreprocessParallelism              uint
 	NodePlayStageParallelism              uint
+	NodePreprocessParallelism             uint
 	NodeLeaseDurationSeconds              uint
 	NodeLeaseParallelism                  uint
+	NodeLeaseRenewIntervalSeconds         uint
+	NodeLeaseRenewIntervalJitter          float64
 	ID                                    string
 	EnableMetrics                         bool
 	EnablePodCache                        bool
+	EnableStageWebhook                    bool
+	EnableNodeVolumeStatus                bool
+	EnableNodeMemoryPressureEviction      bool
+	DisregardFinalizers                   bool
+	PodAdmissionFailurePolicy             string
+	NodeCPUOvercommit                     float64
+	NodeMemoryOvercommit                  float64
+	GlobalDelayJitter                     time.Duration
 	FuncMap                               gotpl.FuncMap
 }
 
@@ -145,6 +175,16 @@ func (c Config) validate() error {
 	default:
 		return fmt.Errorf("no nodes are managed")
 	}
+
+	if err := validateOvercommitFactor("node-cpu-overcommit", c.NodeCPUOvercommit); err != nil {
+		return err
+	}
+	if err := validateOvercommitFactor("node-memory-overcommit", c.NodeMemoryOvercommit); err != nil {
+		return err
+	}
+	if c.GlobalDelayJitter < 0 {
+		return fmt.Errorf("global-delay-jitter must not be negative, got %s", c.GlobalDelayJitter)
+	}
 	return nil
 }
 
@@ -159,6 +199,10 @@ func NewController(conf Config) (*Controller, error) {
 		conf: conf,
 	}
 
+	if conf.EnableStageWebhook {
+		c.stageWebhookClient = &http.Client{}
+	}
+
 	return c, nil
 }
 
@@ -189,14 +233,19 @@ func (c *Controller) init(ctx context.Context) (err error) {
 
 	nodesCli := c.conf.TypedClient.CoreV1().Nodes()
 	c.nodesInformer = informer.NewInformer[*corev1.Node, *corev1.NodeList](nodesCli)
-	c.nodeCacheGetter, err = c.nodesInformer.WatchWithCache(ctx, informer.Option{
+	c.nodesWatchCtx = ctx
+	watchCtx, cancel := context.WithCancel(ctx)
+	nodeCacheGetter, err := c.nodesInformer.WatchWithCache(watchCtx, informer.Option{
 		LabelSelector:      c.manageNodesWithLabelSelector,
 		AnnotationSelector: c.manageNodesWithAnnotationSelector,
 		FieldSelector:      c.manageNodesWithFieldSelector,
 	}, c.nodesChan)
 	if err != nil {
+		cancel()
 		return fmt.Errorf("failed to watch nodes: %w", err)
 	}
+	c.nodeCacheGetter = informer.NewReplaceableGetter(nodeCacheGetter)
+	c.nodesWatchCancel = cancel
 
 	podsCli := c.conf.TypedClient.CoreV1().Pods(corev1.NamespaceAll)
 	c.podsInformer = informer.NewInformer[*corev1.Pod, *corev1.PodList](podsCli)
@@ -213,6 +262,24 @@ func (c *Controller) init(ctx context.Context) (err error) {
 		return fmt.Errorf("failed to watch pods: %w", err)
 	}
 
+	if c.conf.EnableNodeVolumeStatus {
+		pvcsCli := c.conf.TypedClient.CoreV1().PersistentVolumeClaims(corev1.NamespaceAll)
+		pvcsInformer := informer.NewInformer[*corev1.PersistentVolumeClaim, *corev1.PersistentVolumeClaimList](pvcsCli)
+		c.pvcsChan = make(chan informer.Event[*corev1.PersistentVolumeClaim], 1)
+		c.pvcCacheGetter, err = pvcsInformer.WatchWithCache(ctx, informer.Option{}, c.pvcsChan)
+		if err != nil {
+			return fmt.Errorf("failed to watch persistent volume claims: %w", err)
+		}
+
+		pvsCli := c.conf.TypedClient.CoreV1().PersistentVolumes()
+		pvsInformer := informer.NewInformer[*corev1.PersistentVolume, *corev1.PersistentVolumeList](pvsCli)
+		c.pvsChan = make(chan informer.Event[*corev1.PersistentVolume], 1)
+		c.pvCacheGetter, err = pvsInformer.WatchWithCache(ctx, informer.Option{}, c.pvsChan)
+		if err != nil {
+			return fmt.Errorf("failed to watch persistent volumes: %w", err)
+		}
+	}
+
 	if c.conf.NodeLeaseDurationSeconds != 0 {
 		nodeLeasesCli := c.conf.TypedClient.CoordinationV1().Leases(corev1.NamespaceNodeLease)
 		c.nodeLeasesInformer = informer.NewInformer[*coordinationv1.Lease, *coordinationv1.LeaseList](nodeLeasesCli)
@@ -242,11 +309,6 @@ func (c *Controller) initNodeLeaseController(ctx context.Context) error {
 		return fmt.Errorf("failed to watch node leases: %w", err)
 	}
 
-	leaseDuration := time.Duration(c.conf.NodeLeaseDurationSeconds) * time.Second
-	// https://github.com/kubernetes/kubernetes/blob/02f4d643eae2e225591702e1bbf432efea453a26/pkg/kubelet/kubelet.go#L199-L200
-	renewInterval := leaseDuration / 4
-	// https://github.com/kubernetes/component-helpers/blob/d17b6f1e84500ee7062a26f5327dc73cb3e9374a/apimachinery/lease/controller.go#L100
-	renewIntervalJitter := 0.04
 	c.nodeLeases, err = NewNodeLeaseController(NodeLeaseControllerConfig{
 		Clock:                c.conf.Clock,
 		TypedClient:          c.conf.TypedClient,
@@ -255,8 +317,8 @@ func (c *Controller) initNodeLeaseController(ctx context.Context) error {
 		GetLease: func(nodeName string) (*coordinationv1.Lease, bool) {
 			return c.nodeLeaseCacheGetter.GetWithNamespace(nodeName, corev1.NamespaceNodeLease)
 		},
-		RenewInterval:       renewInterval,
-		RenewIntervalJitter: renewIntervalJitter,
+		RenewInterval:       time.Duration(c.conf.NodeLeaseRenewIntervalSeconds) * time.Second,
+		RenewIntervalJitter: c.conf.NodeLeaseRenewIntervalJitter,
 		MutateLeaseFunc: setNodeOwnerFunc(func(nodeName string) []metav1.OwnerReference {
 			node, ok := c.nodeCacheGetter.Get(nodeName)
 			if !ok {
@@ -297,7 +359,7 @@ func (c *Controller) initNodeLeaseController(ctx context.Context) error {
 
 	go c.nodeLeaseSyncWorker(ctx)
 
-	err = c.nodeLeases.Start(ctx)
+	err = c.nodeLeases.Start(c.shutdown.register(ctx, shutdownStepNodeLeases, c.nodeLeases.Wait))
 	if err != nil {
 		return fmt.Errorf("failed to start node leases controller: %w", err)
 	}
@@ -370,7 +432,7 @@ func (c *Controller) initStagesManager(ctx context.Context) error {
 	](
 		c.conf.TypedKwokClient.KwokV1alpha1().Stages(),
 		func(objs []*v1alpha1.Stage) []*internalversion.Stage {
-			return slices.FilterAndMap(objs, func(obj *v1alpha1.Stage) (*internalversion.Stage, bool) {
+			stages := slices.FilterAndMap(objs, func(obj *v1alpha1.Stage) (*internalversion.Stage, bool) {
 				r, err := internalversion.ConvertToInternalStage(obj)
 				if err != nil {
 					logger.Error("failed to convert to internal stage", err, "obj", obj)
@@ -378,9 +440,32 @@ func (c *Controller) initStagesManager(ctx context.Context) error {
 				}
 				return r, true
 			})
+			return stages
 		},
 	)
 
+	// The stages from the config are merged with the ones from the Stage CRD,
+	// and the CRD ones take precedence over the config ones with the same name.
+	if len(c.conf.StaticStages) != 0 {
+		funcMap := c.stageFuncMap()
+		for _, stage := range c.conf.StaticStages {
+			err := lifecycle.ValidateStage(stage, funcMap)
+			if err != nil {
+				return err
+			}
+		}
+		c.stageGetter = resources.NewMergedGetter(
+			c.stageGetter,
+			c.conf.StaticStages,
+			func(stage *internalversion.Stage) string {
+				return stage.Name
+			},
+			func(name string) {
+				logger.Info("Stage from the config is overridden by the Stage CRD", "stage", name)
+			},
+		)
+	}
+
 	err := c.stageGetter.Start(ctx)
 	if err != nil {
 		return err
@@ -389,6 +474,7 @@ func (c *Controller) initStagesManager(ctx context.Context) error {
 	stagesManager := NewStagesManager(StagesManagerConfig{
 		StartFunc:   c.startStageController,
 		StageGetter: c.stageGetter,
+		FuncMap:     c.stageFuncMap(),
 	})
 
 	err = stagesManager.Start(ctx)
@@ -414,6 +500,32 @@ func (c *Controller) onNodeUnmanaged(nodeName string) {
 	c.onNodeUnmanagedFunc(nodeName)
 }
 
+// onNodeUpdated re-evaluates the pods rejected on the node, which may fit the updated node
+func (c *Controller) onNodeUpdated(nodeName string) {
+	if c.pods == nil {
+		return
+	}
+	c.pods.RequeueRejectedPods(nodeName)
+}
+
+// volumesSyncWorker re-resolves the volumes of the pods with unbound claims when the claims or the volumes are updated
+func (c *Controller) volumesSyncWorker(ctx context.Context) {
+	for {
+		select {
+		case event := <-c.pvcsChan:
+			if event.Type != informer.Deleted && event.Object.Spec.VolumeName != "" {
+				c.pods.ResyncNodeVolumes()
+			}
+		case event := <-c.pvsChan:
+			if event.Type != informer.Deleted {
+				c.pods.ResyncNodeVolumes()
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func (c *Controller) initNodeController(ctx context.Context, lifecycle resources.Getter[lifecycle.Lifecycle]) (err error) {
 	c.nodes, err = NewNodeController(NodeControllerConfig{
 		Clock:                                 c.conf.Clock,
@@ -425,17 +537,26 @@ func (c *Controller) initNodeController(ctx context.Context, lifecycle resources
 		DisregardStatusWithLabelSelector:      c.conf.DisregardStatusWithLabelSelector,
 		OnNodeManagedFunc:                     c.onNodeManaged,
 		OnNodeUnmanagedFunc:                   c.onNodeUnmanaged,
+		OnNodeUpdatedFunc:                     c.onNodeUpdated,
 		Lifecycle:                             lifecycle,
 		PlayStageParallelism:                  c.conf.NodePlayStageParallelism,
+		PreprocessParallelism:                 c.conf.NodePreprocessParallelism,
+		DelayJitter:                           c.conf.GlobalDelayJitter,
 		FuncMap:                               c.conf.FuncMap,
 		Recorder:                              c.recorder,
 		ReadOnlyFunc:                          c.readOnlyFunc,
 		EnableMetrics:                         c.conf.EnableMetrics,
+		DisregardFinalizers:                   c.conf.DisregardFinalizers,
+		StageWebhookClient:                    c.stageWebhookClient,
+		CPUOvercommit:                         c.conf.NodeCPUOvercommit,
+		MemoryOvercommit:                      c.conf.NodeMemoryOvercommit,
+		EnableMemoryPressureEviction:          c.conf.EnableNodeMemoryPressureEviction,
+		PodsOnNodeFunc:                        c.podsOnNode,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create nodes controller: %w", err)
 	}
-	err = c.nodes.Start(ctx, c.nodesChan)
+	err = c.nodes.Start(c.shutdown.register(ctx, shutdownStepNodes, c.nodes.Wait), c.nodesChan)
 	if err != nil {
 		return fmt.Errorf("failed to start nodes controller: %w", err)
 	}
@@ -454,6 +575,11 @@ func (c *Controller) initPodController(ctx context.Context, lifecycle resources.
 		DisregardStatusWithLabelSelector:      c.conf.DisregardStatusWithLabelSelector,
 		Lifecycle:                             lifecycle,
 		PlayStageParallelism:                  c.conf.PodPlayStageParallelism,
+		PreprocessParallelism:                 c.conf.PodPreprocessParallelism,
+		DelayJitter:                           c.conf.GlobalDelayJitter,
+		PlayStageParallelismRamp:              c.conf.PodPlayStageParallelismRamp,
+		PlayStageParallelismRampJitter:        c.conf.PodPlayStageParallelismRampJitter,
+		StatusUpdateParallelism:               c.conf.PodStatusUpdateParallelism,
 		NodeGetFunc: func(nodeName string) (*NodeInfo, bool) {
 			if c.nodes == nil {
 				return nil, false
@@ -461,20 +587,31 @@ func (c *Controller) initPodController(ctx context.Context, lifecycle resources.
 
 			return c.nodes.Get(nodeName)
 		},
-		FuncMap:       c.conf.FuncMap,
-		Recorder:      c.recorder,
-		ReadOnlyFunc:  c.readOnlyFunc,
-		EnableMetrics: c.conf.EnableMetrics,
+		FuncMap:                   c.conf.FuncMap,
+		Recorder:                  c.recorder,
+		ReadOnlyFunc:              c.readOnlyFunc,
+		EnableMetrics:             c.conf.EnableMetrics,
+		EnablePodsOnNodeIndex:     c.conf.EnableNodeMemoryPressureEviction,
+		EnableNodeVolumeStatus:    c.conf.EnableNodeVolumeStatus,
+		PVCCacheGetter:            c.pvcCacheGetter,
+		PVCacheGetter:             c.pvCacheGetter,
+		DisregardFinalizers:       c.conf.DisregardFinalizers,
+		PodAdmissionFailurePolicy: c.conf.PodAdmissionFailurePolicy,
+		StageWebhookClient:        c.stageWebhookClient,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create pods controller: %w", err)
 	}
 
-	err = c.pods.Start(ctx, c.podsChan)
+	err = c.pods.Start(c.shutdown.register(ctx, shutdownStepPods, c.pods.Wait), c.podsChan)
 	if err != nil {
 		return fmt.Errorf("failed to start pods controller: %w", err)
 	}
 
+	if c.conf.EnableNodeVolumeStatus {
+		go c.volumesSyncWorker(ctx)
+	}
+
 	return nil
 }
 
@@ -514,14 +651,16 @@ func (c *Controller) initStageController(ctx context.Context, ref internalversio
 		DisregardStatusWithLabelSelector:      c.conf.DisregardStatusWithLabelSelector,
 		Lifecycle:                             lifecycle,
 		PlayStageParallelism:                  1,
+		DelayJitter:                           c.conf.GlobalDelayJitter,
 		FuncMap:                               c.conf.FuncMap,
 		Recorder:                              c.recorder,
+		StageWebhookClient:                    c.stageWebhookClient,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create stage controller: %w", err)
 	}
 
-	err = stage.Start(ctx, stageChan)
+	err = stage.Start(c.shutdown.register(ctx, shutdownStepStages, nil), stageChan)
 	if err != nil {
 		return fmt.Errorf("failed to start stage controller: %w", err)
 	}
@@ -536,8 +675,17 @@ func (c *Controller) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to init controller: %w", err)
 	}
 
+	c.shutdown = newShutdownOrder(ctx, defaultShutdownDrainTimeout)
+
 	if len(c.conf.LocalStages) != 0 {
+		funcMap := c.stageFuncMap()
 		for ref, stage := range c.conf.LocalStages {
+			for _, s := range stage {
+				err = lifecycle.ValidateStage(s, funcMap)
+				if err != nil {
+					return err
+				}
+			}
 			lifecycle, err := lifecycle.NewLifecycle(stage)
 			if err != nil {
 				return err
@@ -556,6 +704,25 @@ func (c *Controller) Start(ctx context.Context) error {
 	return nil
 }
 
+// Wait waits for the subcontrollers to be stopped after the context passed to Start is done,
+// they are stopped in the reverse order of their dependencies, and each one is drained before the next.
+func (c *Controller) Wait() {
+	if c.shutdown == nil {
+		return
+	}
+	<-c.shutdown.Done()
+}
+
+// stageFuncMap returns all functions available in the templates of stages,
+// it is only used to validate the templates.
+func (c *Controller) stageFuncMap() gotpl.FuncMap {
+	return maps.Merge(
+		(&NodeController{}).funcMap(),
+		(&PodController{}).funcMap(),
+		c.conf.FuncMap,
+	)
+}
+
 func (c *Controller) podsOnNodeSyncWorker(ctx context.Context) {
 	logger := log.FromContext(ctx)
 	for ctx.Err() == nil {
@@ -588,6 +755,26 @@ func (c *Controller) ListPods(nodeName string) ([]log.ObjectRef, bool) {
 	return c.pods.List(nodeName)
 }
 
+// podsOnNode returns the pods on the given node from the pod cache
+func (c *Controller) podsOnNode(nodeName string) []*corev1.Pod {
+	if c.podCacheGetter == nil {
+		return nil
+	}
+	refs, ok := c.ListPods(nodeName)
+	if !ok {
+		return nil
+	}
+	pods := make([]*corev1.Pod, 0, len(refs))
+	for _, ref := range refs {
+		pod, ok := c.podCacheGetter.GetWithNamespace(ref.Name, ref.Namespace)
+		if !ok {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
 // GetPodCache returns the pod cache
 func (c *Controller) GetPodCache() informer.Getter[*corev1.Pod] {
 	return c.podCacheGetter
@@ -595,6 +782,9 @@ func (c *Controller) GetPodCache() informer.Getter[*corev1.Pod] {
 
 // GetNodeCache returns the node cache
 func (c *Controller) GetNodeCache() informer.Getter[*corev1.Node] {
+	if c.nodeCacheGetter == nil {
+		return nil
+	}
 	return c.nodeCacheGetter
 }
 
@@ -23,6 +23,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -193,3 +194,55 @@ func TestController(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordStagesReload(t *testing.T) {
+	newStage := func(name, kind string) *internalversion.Stage {
+		return &internalversion.Stage{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: internalversion.StageSpec{
+				ResourceRef: internalversion.StageResourceRef{
+					APIGroup: "v1",
+					Kind:     kind,
+				},
+			},
+		}
+	}
+
+	ctx := context.Background()
+	before := testutil.ToFloat64(stagesReloadsTotal)
+	m := NewStagesManager(StagesManagerConfig{})
+
+	m.recordReload(ctx, []*internalversion.Stage{
+		newStage("node-initialize", "Node"),
+		newStage("pod-ready", "Pod"),
+		newStage("pod-complete", "Pod"),
+	})
+	if got := testutil.ToFloat64(stagesReloadsTotal) - before; got != 1 {
+		t.Errorf("want 1 reload, got %v", got)
+	}
+	if got := testutil.ToFloat64(stagesLoaded.WithLabelValues("v1", "Pod")); got != 2 {
+		t.Errorf("want 2 pod stages, got %v", got)
+	}
+	if got := testutil.ToFloat64(stagesLoaded.WithLabelValues("v1", "Node")); got != 1 {
+		t.Errorf("want 1 node stage, got %v", got)
+	}
+
+	m.recordReload(ctx, []*internalversion.Stage{
+		newStage("pod-complete", "Pod"),
+		newStage("node-initialize", "Node"),
+		newStage("pod-ready", "Pod"),
+	})
+	if got := testutil.ToFloat64(stagesReloadsTotal) - before; got != 1 {
+		t.Errorf("want the unchanged stages not to be counted as a reload, got %v reloads", got)
+	}
+
+	m.recordReload(ctx, []*internalversion.Stage{
+		newStage("pod-ready", "Pod"),
+	})
+	if got := testutil.ToFloat64(stagesReloadsTotal) - before; got != 2 {
+		t.Errorf("want 2 reloads, got %v", got)
+	}
+	if got := testutil.CollectAndCount(stagesLoaded); got != 1 {
+		t.Errorf("want the removed kinds to be reset, got %d series", got)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitterDelay(t *testing.T) {
+	tests := []struct {
+		name   string
+		delay  time.Duration
+		jitter time.Duration
+	}{
+		{
+			name: "no delay and no jitter",
+		},
+		{
+			name:  "delay without jitter",
+			delay: time.Second,
+		},
+		{
+			name:   "jitter without delay",
+			jitter: time.Second,
+		},
+		{
+			name:   "jitter with delay",
+			delay:  5 * time.Second,
+			jitter: time.Second,
+		},
+		{
+			name:   "negative jitter",
+			delay:  time.Second,
+			jitter: -time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxDelay := tt.delay
+			if tt.jitter > 0 {
+				maxDelay += tt.jitter
+			}
+			for i := 0; i != 100; i++ {
+				got := jitterDelay(tt.delay, tt.jitter)
+				if got < tt.delay || got > maxDelay {
+					t.Fatalf("jitterDelay() = %v, want within [%v, %v]", got, tt.delay, maxDelay)
+				}
+			}
+		})
+	}
+}
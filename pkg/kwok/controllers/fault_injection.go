@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// FaultInjectionConfig is the configuration for injecting faults into the requests to the apiserver
+type FaultInjectionConfig struct {
+	// ErrorRate is the probability, between 0 and 1, that a write request fails with a transient error.
+	ErrorRate float64
+	// Latency is the latency added to each write request.
+	Latency time.Duration
+	Clock   clock.Clock
+}
+
+// Validate validates the configuration
+func (c FaultInjectionConfig) Validate() error {
+	if c.ErrorRate < 0 || c.ErrorRate > 1 {
+		return fmt.Errorf("fault injection error rate must be between 0 and 1, got %v", c.ErrorRate)
+	}
+	if c.Latency < 0 {
+		return fmt.Errorf("fault injection latency must not be negative, got %v", c.Latency)
+	}
+	return nil
+}
+
+// NewFaultInjectionRoundTripper returns a http.RoundTripper which injects transient errors and latency
+// into the write requests, the read requests are passed through so that informers stay stable.
+// The injected error is a 503 response, which is retried by the controllers.
+func NewFaultInjectionRoundTripper(rt http.RoundTripper, conf FaultInjectionConfig) http.RoundTripper {
+	if conf.Clock == nil {
+		conf.Clock = clock.RealClock{}
+	}
+	return &faultInjectionRoundTripper{
+		rt:        rt,
+		errorRate: conf.ErrorRate,
+		latency:   conf.Latency,
+		clock:     conf.Clock,
+		rand:      rand.Float64,
+	}
+}
+
+type faultInjectionRoundTripper struct {
+	rt        http.RoundTripper
+	errorRate float64
+	latency   time.Duration
+	clock     clock.Clock
+	rand      func() float64
+}
+
+const faultInjectionStatus = `{"kind":"Status","apiVersion":"v1","status":"Failure","message":"injected fault","reason":"ServiceUnavailable","code":503}`
+
+// RoundTrip implements http.RoundTripper
+func (f *faultInjectionRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return f.rt.RoundTrip(req)
+	}
+
+	if f.latency > 0 {
+		select {
+		case <-req.Context().Done():
+			closeRequestBody(req)
+			return nil, req.Context().Err()
+		case <-f.clock.After(f.latency):
+		}
+	}
+
+	if f.errorRate > 0 && f.rand() < f.errorRate {
+		faultInjectionFailuresTotal.Inc()
+		closeRequestBody(req)
+		return &http.Response{
+			Status:     http.StatusText(http.StatusServiceUnavailable),
+			StatusCode: http.StatusServiceUnavailable,
+			Header: http.Header{
+				"Content-Type": []string{"application/json"},
+			},
+			Body:    io.NopCloser(bytes.NewBufferString(faultInjectionStatus)),
+			Request: req,
+		}, nil
+	}
+	return f.rt.RoundTrip(req)
+}
+
+// closeRequestBody closes the body of the request which is not sent,
+// a http.RoundTripper must always close the body.
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
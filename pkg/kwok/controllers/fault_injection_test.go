@@ -0,0 +1,212 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestFaultInjectionRoundTripper(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		errorRate  float64
+		rand       float64
+		wantStatus int
+		wantInject bool
+	}{
+		{
+			name:       "read request is passed through",
+			method:     http.MethodGet,
+			errorRate:  1,
+			rand:       0,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "write request is failed",
+			method:     http.MethodPatch,
+			errorRate:  1,
+			rand:       0,
+			wantStatus: http.StatusServiceUnavailable,
+			wantInject: true,
+		},
+		{
+			name:       "write request above the rate is passed through",
+			method:     http.MethodPost,
+			errorRate:  0.5,
+			rand:       0.6,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "zero rate",
+			method:     http.MethodDelete,
+			errorRate:  0,
+			rand:       0,
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			rt := NewFaultInjectionRoundTripper(http.DefaultTransport, FaultInjectionConfig{
+				ErrorRate: tt.errorRate,
+			})
+			rt.(*faultInjectionRoundTripper).rand = func() float64 {
+				return tt.rand
+			}
+
+			before := testutil.ToFloat64(faultInjectionFailuresTotal)
+
+			req, err := http.NewRequest(tt.method, server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := rt.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = resp.Body.Close()
+			}()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("want status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+
+			injected := testutil.ToFloat64(faultInjectionFailuresTotal) - before
+			if tt.wantInject != (injected == 1) {
+				t.Errorf("want injected %v, got %v", tt.wantInject, injected)
+			}
+		})
+	}
+}
+
+func TestFaultInjectionRoundTripperClosesBody(t *testing.T) {
+	rt := NewFaultInjectionRoundTripper(http.DefaultTransport, FaultInjectionConfig{
+		ErrorRate: 1,
+	})
+
+	body := &closeRecorder{Reader: strings.NewReader("{}")}
+	req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:0", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if !body.closed {
+		t.Errorf("want the body of the failed request closed")
+	}
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestFaultInjectionConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    FaultInjectionConfig
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			conf: FaultInjectionConfig{ErrorRate: 0.5, Latency: time.Second},
+		},
+		{
+			name: "full rate",
+			conf: FaultInjectionConfig{ErrorRate: 1},
+		},
+		{
+			name:    "negative rate",
+			conf:    FaultInjectionConfig{ErrorRate: -0.1},
+			wantErr: true,
+		},
+		{
+			name:    "rate above 1",
+			conf:    FaultInjectionConfig{ErrorRate: 1.5},
+			wantErr: true,
+		},
+		{
+			name:    "negative latency",
+			conf:    FaultInjectionConfig{Latency: -time.Second},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.conf.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFaultInjectionRoundTripperStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	conf := &rest.Config{
+		Host: server.URL,
+	}
+	conf.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return NewFaultInjectionRoundTripper(rt, FaultInjectionConfig{
+			ErrorRate: 1,
+		})
+	})
+	cli, err := kubernetes.NewForConfig(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = cli.CoreV1().Pods("default").Create(context.Background(), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pod",
+		},
+	}, metav1.CreateOptions{})
+	if !apierrors.IsServiceUnavailable(err) {
+		t.Errorf("want service unavailable error, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/informer"
+)
+
+// ManageNodesSelector returns the label selector and annotation selector of the managed nodes
+func (c *Controller) ManageNodesSelector() (labelSelector, annotationSelector string) {
+	c.manageNodesSelectorMut.Lock()
+	defer c.manageNodesSelectorMut.Unlock()
+	return c.manageNodesWithLabelSelector, c.manageNodesWithAnnotationSelector
+}
+
+// UpdateManageNodesSelector updates the selectors of the managed nodes without restarting the controller,
+// the nodes that are selected start to be managed, and the ones that are no longer selected are released.
+func (c *Controller) UpdateManageNodesSelector(ctx context.Context, labelSelector, annotationSelector string) error {
+	if c.conf.ManageSingleNode != "" || c.conf.ManageAllNodes {
+		return fmt.Errorf("the selectors can only be updated when managing nodes with selectors")
+	}
+	if labelSelector == "" && annotationSelector == "" {
+		return fmt.Errorf("no nodes are managed")
+	}
+	newLabelSelector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return fmt.Errorf("failed to parse label selector %q: %w", labelSelector, err)
+	}
+	newAnnotationSelector, err := labels.Parse(annotationSelector)
+	if err != nil {
+		return fmt.Errorf("failed to parse annotation selector %q: %w", annotationSelector, err)
+	}
+
+	if c.nodesInformer == nil {
+		return fmt.Errorf("controller is not started")
+	}
+
+	c.manageNodesSelectorMut.Lock()
+	defer c.manageNodesSelectorMut.Unlock()
+
+	if labelSelector == c.manageNodesWithLabelSelector &&
+		annotationSelector == c.manageNodesWithAnnotationSelector {
+		return nil
+	}
+
+	watchCtx, cancel := context.WithCancel(c.nodesWatchCtx)
+	nodeCacheGetter, err := c.nodesInformer.WatchWithCache(watchCtx, informer.Option{
+		LabelSelector:      labelSelector,
+		AnnotationSelector: annotationSelector,
+		FieldSelector:      c.manageNodesWithFieldSelector,
+	}, c.nodesChan)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to watch nodes: %w", err)
+	}
+
+	oldNodeCacheGetter := c.nodeCacheGetter.Replace(nodeCacheGetter)
+	c.nodesWatchCancel()
+	c.nodesWatchCancel = cancel
+	c.manageNodesWithLabelSelector = labelSelector
+	c.manageNodesWithAnnotationSelector = annotationSelector
+
+	logger := log.FromContext(ctx)
+	logger.Info("Updated managed nodes selector",
+		"labelSelector", labelSelector,
+		"annotationSelector", annotationSelector,
+	)
+
+	// Release the nodes that are no longer selected,
+	// the newly selected ones are added by the new watch.
+	for _, nodeName := range c.ListNodes() {
+		node, ok := oldNodeCacheGetter.Get(nodeName)
+		if !ok {
+			continue
+		}
+		if nodeSelected(node, newLabelSelector, newAnnotationSelector) {
+			continue
+		}
+		logger.Info("Release node", "node", nodeName, "reason", "no longer selected")
+		select {
+		case c.nodesChan <- informer.Event[*corev1.Node]{Type: informer.Deleted, Object: node}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if c.nodeLeases != nil {
+			err = c.nodeLeases.Release(ctx, nodeName)
+			if err != nil {
+				logger.Error("Failed to release lease", err, "node", nodeName)
+			}
+		}
+	}
+	return nil
+}
+
+// nodeSelected returns whether the node matches both of the selectors,
+// an empty annotation selector matches all nodes.
+func nodeSelected(node *corev1.Node, labelSelector, annotationSelector labels.Selector) bool {
+	if !labelSelector.Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	if annotationSelector.Empty() {
+		return true
+	}
+	return annotationSelector.Matches(labels.Set(node.Annotations))
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	nodefast "sigs.k8s.io/kwok/kustomize/stage/node/fast"
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/utils/wait"
+)
+
+func TestControllerUpdateManageNodesSelector(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+
+	nodeInit, err := config.UnmarshalWithType[*internalversion.Stage](nodefast.DefaultNodeInit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientset := fake.NewSimpleClientset(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node-a",
+				Labels: map[string]string{
+					"type": "kwok",
+					"pool": "a",
+				},
+			},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node-b",
+				Labels: map[string]string{
+					"type": "kwok",
+				},
+			},
+		},
+	)
+	ctr, err := NewController(Config{
+		TypedClient:                  clientset,
+		ManageNodesWithLabelSelector: "type=kwok",
+		LocalStages: map[internalversion.StageResourceRef][]*internalversion.Stage{
+			nodeRef: {nodeInit},
+		},
+		NodePlayStageParallelism: 1,
+		NodeLeaseDurationSeconds: 40,
+		NodeLeaseParallelism:     1,
+		ID:                       "kwok",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ctr.Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitHeld := func(want map[string]bool) {
+		t.Helper()
+		err := wait.Poll(ctx, func(ctx context.Context) (bool, error) {
+			for nodeName, held := range want {
+				if ctr.nodeLeases.Held(nodeName) != held {
+					return false, fmt.Errorf("want lease of node %s held %v", nodeName, held)
+				}
+			}
+			return true, nil
+		}, wait.WithContinueOnError(10), wait.WithInterval(100*time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	waitHeld(map[string]bool{
+		"node-a": true,
+		"node-b": true,
+	})
+
+	err = ctr.UpdateManageNodesSelector(ctx, "type=kwok,pool=a", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitHeld(map[string]bool{
+		"node-a": true,
+		"node-b": false,
+	})
+
+	labelSelector, annotationSelector := ctr.ManageNodesSelector()
+	if labelSelector != "type=kwok,pool=a" || annotationSelector != "" {
+		t.Errorf("want selector %q, got %q, %q", "type=kwok,pool=a", labelSelector, annotationSelector)
+	}
+}
+
+func TestControllerUpdateManageNodesSelectorInvalid(t *testing.T) {
+	tests := []struct {
+		name               string
+		conf               Config
+		labelSelector      string
+		annotationSelector string
+	}{
+		{
+			name: "manage all nodes",
+			conf: Config{
+				ManageAllNodes: true,
+			},
+			labelSelector: "type=kwok",
+		},
+		{
+			name: "empty selectors",
+			conf: Config{
+				ManageNodesWithLabelSelector: "type=kwok",
+			},
+		},
+		{
+			name: "invalid label selector",
+			conf: Config{
+				ManageNodesWithLabelSelector: "type=kwok",
+			},
+			labelSelector: "type==,",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctr, err := NewController(tt.conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = ctr.UpdateManageNodesSelector(context.Background(), tt.labelSelector, tt.annotationSelector)
+			if err == nil {
+				t.Errorf("want error")
+			}
+		})
+	}
+}
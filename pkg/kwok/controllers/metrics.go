@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+var (
+	podPlayStageActiveWorkers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "kwok",
+		Subsystem: "pod_controller",
+		Name:      "play_stage_active_workers",
+		Help:      "Number of active workers playing pod stages.",
+	})
+
+	podStatusUpdateActiveWorkers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "kwok",
+		Subsystem: "pod_controller",
+		Name:      "status_update_active_workers",
+		Help:      "Number of pod updates being sent to the apiserver, capped by the status update parallelism.",
+	})
+
+	faultInjectionFailuresTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kwok",
+		Subsystem: "fault_injection",
+		Name:      "failures_total",
+		Help:      "Number of failures injected into the requests to the apiserver.",
+	})
+
+	stagesReloadsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kwok",
+		Subsystem: "stages",
+		Name:      "reloads_total",
+		Help:      "Number of times the stages were reloaded after they changed.",
+	})
+
+	stagesLoaded = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "kwok",
+		Subsystem: "stages",
+		Name:      "loaded",
+		Help:      "Number of stages loaded for each kind of resource, including the ones from the config.",
+	}, []string{"api_group", "kind"})
+
+	reconcileErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kwok",
+		Name:      "reconcile_errors_total",
+		Help:      "Number of errors of the controllers while reconciling the resources.",
+	}, []string{"controller", "category"})
+)
+
+func init() {
+	prometheus.MustRegister(podPlayStageActiveWorkers)
+	prometheus.MustRegister(podStatusUpdateActiveWorkers)
+	prometheus.MustRegister(faultInjectionFailuresTotal)
+	prometheus.MustRegister(stagesReloadsTotal)
+	prometheus.MustRegister(stagesLoaded)
+	prometheus.MustRegister(reconcileErrorsTotal)
+}
+
+// recordReconcileError counts the error of the controller while reconciling a resource
+func recordReconcileError(controller string, err error) {
+	reconcileErrorsTotal.WithLabelValues(controller, reconcileErrorCategory(err)).Inc()
+}
+
+// reconcileErrorCategory returns the category of the error to keep the cardinality of the metric low
+func reconcileErrorCategory(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "canceled"
+	case apierrors.IsConflict(err):
+		return "conflict"
+	case apierrors.IsNotFound(err):
+		return "not_found"
+	case apierrors.IsAlreadyExists(err):
+		return "already_exists"
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return "forbidden"
+	case apierrors.IsTooManyRequests(err):
+		return "throttled"
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err):
+		return "timeout"
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return "invalid"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestReconcileErrorCategory(t *testing.T) {
+	gr := schema.GroupResource{Resource: "pods"}
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "canceled",
+			err:  fmt.Errorf("failed to patch: %w", context.Canceled),
+			want: "canceled",
+		},
+		{
+			name: "conflict",
+			err:  apierrors.NewConflict(gr, "pod", fmt.Errorf("conflict")),
+			want: "conflict",
+		},
+		{
+			name: "wrapped not found",
+			err:  fmt.Errorf("failed to get: %w", apierrors.NewNotFound(gr, "pod")),
+			want: "not_found",
+		},
+		{
+			name: "forbidden",
+			err:  apierrors.NewForbidden(gr, "pod", fmt.Errorf("forbidden")),
+			want: "forbidden",
+		},
+		{
+			name: "throttled",
+			err:  apierrors.NewTooManyRequests("throttled", 1),
+			want: "throttled",
+		},
+		{
+			name: "other",
+			err:  fmt.Errorf("unknown"),
+			want: "other",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reconcileErrorCategory(tt.err); got != tt.want {
+				t.Errorf("reconcileErrorCategory() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -20,6 +20,9 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
+	"sort"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -53,17 +56,27 @@ type NodeController struct {
 	disregardStatusWithLabelSelector      labels.Selector
 	onNodeManagedFunc                     func(nodeName string)
 	onNodeUnmanagedFunc                   func(nodeName string)
+	onNodeUpdatedFunc                     func(nodeName string)
 	nodesSets                             maps.SyncMap[string, *NodeInfo]
 	renderer                              gotpl.Renderer
-	preprocessChan                        chan *corev1.Node
+	preprocessShards                      *preprocessShards[*corev1.Node]
 	playStageParallelism                  uint
+	delayJitter                           time.Duration
 	lifecycle                             resources.Getter[lifecycle.Lifecycle]
 	delayQueue                            queue.WeightDelayingQueue[resourceStageJob[*corev1.Node]]
 	delayQueueMapping                     maps.SyncMap[string, resourceStageJob[*corev1.Node]]
+	stageCounter                          *lifecycle.Counter
 	backoff                               wait.Backoff
 	recorder                              record.EventRecorder
 	readOnlyFunc                          func(nodeName string) bool
 	enableMetrics                         bool
+	disregardFinalizers                   bool
+	stageWebhookClient                    *http.Client
+	overcommit                            overcommit
+	enableMemoryPressureEviction          bool
+	podsOnNodeFunc                        func(nodeName string) []*corev1.Pod
+
+	workers sync.WaitGroup
 }
 
 // NodeControllerConfig is the configuration for the NodeController
@@ -72,6 +85,7 @@ type NodeControllerConfig struct {
 	TypedClient                           kubernetes.Interface
 	OnNodeManagedFunc                     func(nodeName string)
 	OnNodeUnmanagedFunc                   func(nodeName string)
+	OnNodeUpdatedFunc                     func(nodeName string)
 	DisregardStatusWithAnnotationSelector string
 	DisregardStatusWithLabelSelector      string
 	NodeIP                                string
@@ -79,10 +93,18 @@ type NodeControllerConfig struct {
 	NodePort                              int
 	Lifecycle                             resources.Getter[lifecycle.Lifecycle]
 	PlayStageParallelism                  uint
+	PreprocessParallelism                 uint
+	DelayJitter                           time.Duration
 	FuncMap                               gotpl.FuncMap
 	Recorder                              record.EventRecorder
 	ReadOnlyFunc                          func(nodeName string) bool
 	EnableMetrics                         bool
+	DisregardFinalizers                   bool
+	StageWebhookClient                    *http.Client
+	CPUOvercommit                         float64
+	MemoryOvercommit                      float64
+	EnableMemoryPressureEviction          bool
+	PodsOnNodeFunc                        func(nodeName string) []*corev1.Pod
 }
 
 // NodeInfo is the collection of necessary node information
@@ -96,6 +118,21 @@ func NewNodeController(conf NodeControllerConfig) (*NodeController, error) {
 		return nil, fmt.Errorf("playStageParallelism must be greater than 0")
 	}
 
+	if conf.DelayJitter < 0 {
+		return nil, fmt.Errorf("delayJitter must not be negative")
+	}
+
+	if conf.EnableMemoryPressureEviction && conf.PodsOnNodeFunc == nil {
+		return nil, fmt.Errorf("podsOnNodeFunc is required to evict pods on memory pressure")
+	}
+
+	if err := validateOvercommitFactor("cpuOvercommit", conf.CPUOvercommit); err != nil {
+		return nil, err
+	}
+	if err := validateOvercommitFactor("memoryOvercommit", conf.MemoryOvercommit); err != nil {
+		return nil, err
+	}
+
 	disregardStatusWithAnnotationSelector, err := labelsParse(conf.DisregardStatusWithAnnotationSelector)
 	if err != nil {
 		return nil, err
@@ -117,39 +154,70 @@ func NewNodeController(conf NodeControllerConfig) (*NodeController, error) {
 		disregardStatusWithLabelSelector:      disregardStatusWithLabelSelector,
 		onNodeManagedFunc:                     conf.OnNodeManagedFunc,
 		onNodeUnmanagedFunc:                   conf.OnNodeUnmanagedFunc,
+		onNodeUpdatedFunc:                     conf.OnNodeUpdatedFunc,
 		nodeIP:                                conf.NodeIP,
 		nodeName:                              conf.NodeName,
 		nodePort:                              conf.NodePort,
 		delayQueue:                            queue.NewWeightDelayingQueue[resourceStageJob[*corev1.Node]](conf.Clock),
 		backoff:                               defaultBackoff(),
+		stageCounter:                          lifecycle.NewCounter(),
 		lifecycle:                             conf.Lifecycle,
 		playStageParallelism:                  conf.PlayStageParallelism,
-		preprocessChan:                        make(chan *corev1.Node),
+		delayJitter:                           conf.DelayJitter,
+		preprocessShards:                      newPreprocessShards[*corev1.Node](conf.PreprocessParallelism),
 		recorder:                              conf.Recorder,
 		readOnlyFunc:                          conf.ReadOnlyFunc,
 		enableMetrics:                         conf.EnableMetrics,
+		disregardFinalizers:                   conf.DisregardFinalizers,
+		stageWebhookClient:                    conf.StageWebhookClient,
+		overcommit: overcommit{
+			CPU:    conf.CPUOvercommit,
+			Memory: conf.MemoryOvercommit,
+		},
+		enableMemoryPressureEviction: conf.EnableMemoryPressureEviction,
+		podsOnNodeFunc:               conf.PodsOnNodeFunc,
 	}
 
-	funcMap := maps.Merge(gotpl.FuncMap{
+	c.renderer = gotpl.NewRenderer(maps.Merge(c.funcMap(), conf.FuncMap))
+	return c, nil
+}
+
+// funcMap returns the functions provided by the controller for rendering the templates of stages
+func (c *NodeController) funcMap() gotpl.FuncMap {
+	return gotpl.FuncMap{
 		"NodeIP":   c.funcNodeIP,
 		"NodeName": c.funcNodeName,
 		"NodePort": c.funcNodePort,
-	}, conf.FuncMap)
-	c.renderer = gotpl.NewRenderer(funcMap)
-	return c, nil
+	}
 }
 
 // Start starts the fake nodes controller
 // if nodeSelectorFunc is not nil, it will use it to determine if the node should be managed
 func (c *NodeController) Start(ctx context.Context, events <-chan informer.Event[*corev1.Node]) error {
-	go c.preprocessWorker(ctx)
+	for i := 0; i < c.preprocessShards.Len(); i++ {
+		preprocessChan := c.preprocessShards.Chan(i)
+		c.workers.Add(1)
+		go func() {
+			defer c.workers.Done()
+			c.preprocessWorker(ctx, preprocessChan)
+		}()
+	}
 	for i := uint(0); i < c.playStageParallelism; i++ {
-		go c.playStageWorker(ctx)
+		c.workers.Add(1)
+		go func() {
+			defer c.workers.Done()
+			c.playStageWorker(ctx)
+		}()
 	}
 	go c.watchResources(ctx, events)
 	return nil
 }
 
+// Wait waits for the workers to stop after the context passed to Start is done
+func (c *NodeController) Wait() {
+	c.workers.Wait()
+}
+
 func (c *NodeController) need(node *corev1.Node) bool {
 	if c.disregardStatusWithAnnotationSelector != nil &&
 		len(node.Annotations) != 0 &&
@@ -167,7 +235,7 @@ func (c *NodeController) need(node *corev1.Node) bool {
 
 // ManageNode manages a node
 func (c *NodeController) ManageNode(node *corev1.Node) {
-	c.preprocessChan <- node
+	c.preprocessShards.Push(node.Name, node)
 }
 
 // watchResources watch resources and send to preprocessChan
@@ -192,18 +260,23 @@ loop:
 							"node", node.Name,
 						)
 					} else {
-						c.preprocessChan <- node
+						c.preprocessShards.Push(node.Name, node)
 					}
 
 					if c.onNodeManagedFunc != nil && event.Type != informer.Modified {
 						c.onNodeManagedFunc(node.Name)
 					}
+					if c.onNodeUpdatedFunc != nil && event.Type == informer.Modified {
+						c.onNodeUpdatedFunc(node.Name)
+					}
 				}
 			case informer.Deleted:
 				node := event.Object
 				if _, has := c.nodesSets.Load(node.Name); has {
 					c.deleteNodeInfo(node)
 
+					c.stageCounter.Forget(node.UID)
+
 					// Cancel delay job
 					key := node.Name
 					resourceJob, ok := c.delayQueueMapping.LoadAndDelete(key)
@@ -240,16 +313,27 @@ func (c *NodeController) deleteResource(ctx context.Context, node *corev1.Node)
 }
 
 // preprocessWorker receives the resource from the preprocessChan and preprocess it
-func (c *NodeController) preprocessWorker(ctx context.Context) {
+func (c *NodeController) preprocessWorker(ctx context.Context, preprocessChan <-chan *corev1.Node) {
 	logger := log.FromContext(ctx)
 	for {
 		select {
 		case <-ctx.Done():
 			logger.Debug("Stop preprocess worker")
 			return
-		case node := <-c.preprocessChan:
+		case node := <-preprocessChan:
+			if c.enableMemoryPressureEviction && isNodeConditionTrue(node, corev1.NodeMemoryPressure) {
+				err := c.evictPodOnMemoryPressure(ctx, node)
+				if err != nil {
+					recordReconcileError("node", err)
+					logger.Error("Failed to evict pod on memory pressure", err,
+						"node", node.Name,
+					)
+				}
+			}
+
 			err := c.preprocess(ctx, node)
 			if err != nil {
+				recordReconcileError("node", err)
 				logger.Error("Failed to preprocess node", err,
 					"node", node.Name,
 				)
@@ -283,7 +367,7 @@ func (c *NodeController) preprocess(ctx context.Context, node *corev1.Node) erro
 		return err
 	}
 
-	lc := c.lifecycle.Get()
+	lc := c.stageCounter.Filter(node.UID, c.lifecycle.Get())
 	stage, err := lc.Match(ctx, node.Labels, node.Annotations, data)
 	if err != nil {
 		return fmt.Errorf("stage match: %w", err)
@@ -297,6 +381,7 @@ func (c *NodeController) preprocess(ctx context.Context, node *corev1.Node) erro
 
 	now := c.clock.Now()
 	delay, _ := stage.Delay(ctx, data, now)
+	delay = jitterDelay(delay, c.delayJitter)
 
 	if delay != 0 {
 		stageName := stage.Name()
@@ -330,6 +415,7 @@ func (c *NodeController) playStageWorker(ctx context.Context) {
 		c.delayQueueMapping.Delete(node.Key)
 		needRetry, err := c.playStage(ctx, node.Resource, node.Stage)
 		if err != nil {
+			recordReconcileError("node", err)
 			logger.Error("failed to apply stage", err,
 				"node", node.Key,
 				"stage", node.Stage.Name(),
@@ -352,7 +438,7 @@ func (c *NodeController) playStageWorker(ctx context.Context) {
 
 // playStage plays the stage.
 // The returned boolean indicates whether the applying action needs to be retried.
-func (c *NodeController) playStage(ctx context.Context, node *corev1.Node, stage *lifecycle.Stage) (bool, error) {
+func (c *NodeController) playStage(ctx context.Context, node *corev1.Node, stage *lifecycle.Stage) (needRetry bool, err error) {
 	next := stage.Next()
 	logger := log.FromContext(ctx)
 	logger = logger.With(
@@ -360,10 +446,19 @@ func (c *NodeController) playStage(ctx context.Context, node *corev1.Node, stage
 		"stage", stage.Name(),
 	)
 
-	var (
-		result *corev1.Node
-		err    error
-	)
+	var result *corev1.Node
+
+	if next.Delete() && !c.disregardFinalizers {
+		if pending := pendingFinalizers(node.Finalizers); len(pending) != 0 {
+			logger.Debug("Skip node",
+				"reason", "waiting for finalizers to be removed",
+				"finalizers", pending,
+			)
+			return false, nil
+		}
+	}
+
+	defer c.stageCounter.Track(node.UID, stage, &err)()
 
 	if event := next.Event(); event != nil && c.recorder != nil {
 		c.recorder.Event(&corev1.ObjectReference{
@@ -398,6 +493,11 @@ func (c *NodeController) playStage(ctx context.Context, node *corev1.Node, stage
 		}
 
 		for _, patch := range patches {
+			err = c.overcommit.applyToPatch(node, patch)
+			if err != nil {
+				return false, fmt.Errorf("failed to apply overcommit for node %s: %w", node.Name, err)
+			}
+
 			changed, err := checkNeedPatchWithTyped(node, patch.Data, patch.Type)
 			if err != nil {
 				return false, fmt.Errorf("failed to check need patch for node %s: %w", node.Name, err)
@@ -413,12 +513,35 @@ func (c *NodeController) playStage(ctx context.Context, node *corev1.Node, stage
 				}
 			}
 		}
+
+		if next.HasWebhook() {
+			if c.stageWebhookClient == nil {
+				logger.Warn("Skip webhook",
+					"reason", "stage webhook is disabled",
+				)
+			} else {
+				current := node
+				if result != nil {
+					current = result
+				}
+				patch, err := next.Webhook(ctx, current, c.renderer, c.stageWebhookClient)
+				if err != nil {
+					return false, fmt.Errorf("failed to call webhook for node %s: %w", node.Name, err)
+				}
+				if patch != nil {
+					result, err = c.patchResource(ctx, node, patch)
+					if err != nil {
+						return shouldRetry(err), fmt.Errorf("failed to patch node %s: %w", node.Name, err)
+					}
+				}
+			}
+		}
 	}
 
 	if result != nil && stage.ImmediateNextStage() {
 		logger.Debug("Re-push to preprocessChan",
 			"reason", "immediateNextStage is true")
-		c.preprocessChan <- result
+		c.preprocessShards.Push(result.Name, result)
 	}
 	return false, nil
 }
@@ -513,9 +636,11 @@ func (c *NodeController) Get(nodeName string) (*NodeInfo, bool) {
 	return nil, has
 }
 
-// List returns all name of nodes
+// List returns all name of nodes, sorted by name
 func (c *NodeController) List() []string {
-	return c.nodesSets.Keys()
+	names := c.nodesSets.Keys()
+	sort.Strings(names)
+	return names
 }
 
 func (c *NodeController) funcNodeIP() string {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 
+	nodechaos "sigs.k8s.io/kwok/kustomize/stage/node/chaos"
 	nodefast "sigs.k8s.io/kwok/kustomize/stage/node/fast"
 	"sigs.k8s.io/kwok/pkg/apis/internalversion"
 	"sigs.k8s.io/kwok/pkg/config"
@@ -165,3 +167,117 @@ func TestNodeController(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestNodeControllerOutage(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node0",
+				Labels: map[string]string{
+					"node-outage.stage.kwok.x-k8s.io": "true",
+				},
+				Annotations: map[string]string{
+					"node":                                  "true",
+					"node-outage.stage.kwok.x-k8s.io/delay": "0s",
+					"node-outage.stage.kwok.x-k8s.io/jitter-delay": "0s",
+					"node-outage.stage.kwok.x-k8s.io/duration":     "2s",
+				},
+			},
+			Status: corev1.NodeStatus{
+				Phase: corev1.NodeRunning,
+				Conditions: []corev1.NodeCondition{
+					{
+						Type:   corev1.NodeReady,
+						Status: corev1.ConditionTrue,
+					},
+				},
+			},
+		},
+	)
+
+	nodeOutage, err := config.UnmarshalWithType[*internalversion.Stage](nodechaos.DefaultNodeOutage)
+	if err != nil {
+		t.Fatal(fmt.Errorf("failed to unmarshal node outage stage: %w", err))
+	}
+	nodeOutageRecover, err := config.UnmarshalWithType[*internalversion.Stage](nodechaos.DefaultNodeOutageRecover)
+	if err != nil {
+		t.Fatal(fmt.Errorf("failed to unmarshal node outage recover stage: %w", err))
+	}
+	nodeStages := []*internalversion.Stage{nodeOutage, nodeOutageRecover}
+
+	lc, _ := lifecycle.NewLifecycle(nodeStages)
+	nodes, err := NewNodeController(NodeControllerConfig{
+		TypedClient:          clientset,
+		NodeIP:               "10.0.0.1",
+		Lifecycle:            resources.NewStaticGetter(lc),
+		PlayStageParallelism: 2,
+	})
+	if err != nil {
+		t.Fatal(fmt.Errorf("new nodes controller error: %w", err))
+	}
+	ctx := context.Background()
+	ctx = log.NewContext(ctx, log.NewLogger(os.Stderr, log.LevelDebug))
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	t.Cleanup(func() {
+		cancel()
+		time.Sleep(time.Second)
+	})
+
+	nodeCh := make(chan informer.Event[*corev1.Node], 1)
+	nodesCli := clientset.CoreV1().Nodes()
+	nodesInformer := informer.NewInformer[*corev1.Node, *corev1.NodeList](nodesCli)
+	err = nodesInformer.Watch(ctx, informer.Option{
+		AnnotationSelector: "node=true",
+	}, nodeCh)
+	if err != nil {
+		t.Fatal(fmt.Errorf("failed to watch nodes: %w", err))
+	}
+
+	err = nodes.Start(ctx, nodeCh)
+	if err != nil {
+		t.Fatal(fmt.Errorf("failed to start nodes controller: %w", err))
+	}
+
+	waitReady := func(status corev1.ConditionStatus, state string) error {
+		return wait.Poll(ctx, func(ctx context.Context) (done bool, err error) {
+			node, err := clientset.CoreV1().Nodes().Get(ctx, "node0", metav1.GetOptions{})
+			if err != nil {
+				return false, fmt.Errorf("failed to get node0: %w", err)
+			}
+			if got := node.Annotations["node-outage.stage.kwok.x-k8s.io/state"]; got != state {
+				return false, nil
+			}
+			for _, cond := range node.Status.Conditions {
+				if cond.Type == corev1.NodeReady {
+					return cond.Status == status, nil
+				}
+			}
+			return false, nil
+		}, wait.WithInterval(100*time.Millisecond))
+	}
+
+	err = waitReady(corev1.ConditionFalse, "NotReady")
+	if err != nil {
+		t.Fatal(fmt.Errorf("want node0 to be not ready: %w", err))
+	}
+
+	err = waitReady(corev1.ConditionTrue, "Recovered")
+	if err != nil {
+		t.Fatal(fmt.Errorf("want node0 to recover: %w", err))
+	}
+}
+
+func TestNodeControllerList(t *testing.T) {
+	c := &NodeController{}
+	for _, name := range []string{"node-c", "node-a", "node-d", "node-b"} {
+		c.nodesSets.Store(name, &NodeInfo{})
+	}
+
+	want := []string{"node-a", "node-b", "node-c", "node-d"}
+	for i := 0; i != 10; i++ {
+		got := c.List()
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("List() = %v, want %v", got, want)
+		}
+	}
+}
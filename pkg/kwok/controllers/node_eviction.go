@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/kwok/pkg/log"
+)
+
+const (
+	evictionReason          = "Evicted"
+	memoryPressureMessage   = "The node was low on resource: memory."
+	mirrorPodAnnotationName = "kubernetes.io/config.mirror"
+)
+
+// isNodeConditionTrue returns true if the condition of the node is true
+func isNodeConditionTrue(node *corev1.Node, conditionType corev1.NodeConditionType) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == conditionType {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// evictPodOnMemoryPressure evicts one pod on the node under memory pressure,
+// like kubelet does, the pod is marked as failed with the reason Evicted.
+// It is called each time the node is updated, until the pressure is relieved.
+// The pods on the node are read from the cache, not from the apiserver.
+func (c *NodeController) evictPodOnMemoryPressure(ctx context.Context, node *corev1.Node) error {
+	pods := c.podsOnNodeFunc(node.Name)
+
+	candidates := make([]*corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil ||
+			pod.Status.Phase == corev1.PodSucceeded ||
+			pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if _, ok := pod.Annotations[mirrorPodAnnotationName]; ok {
+			continue
+		}
+		candidates = append(candidates, pod)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sortPodsForEviction(candidates)
+	pod := candidates[0]
+
+	patch := fmt.Sprintf(`{"status":{"phase":%q,"reason":%q,"message":%q}}`, corev1.PodFailed, evictionReason, memoryPressureMessage)
+	_, err := c.typedClient.CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{}, "status")
+	if err != nil {
+		return fmt.Errorf("failed to evict pod %s: %w", log.KObj(pod), err)
+	}
+
+	if c.recorder != nil {
+		c.recorder.Event(&corev1.ObjectReference{
+			Kind:      "Pod",
+			UID:       pod.UID,
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		}, corev1.EventTypeWarning, evictionReason, memoryPressureMessage)
+	}
+
+	logger := log.FromContext(ctx)
+	logger.Info("Evicted pod on memory pressure",
+		"node", node.Name,
+		"pod", log.KObj(pod),
+		"qosClass", podQOSClass(pod),
+	)
+	return nil
+}
+
+// sortPodsForEviction sorts the pods in the order of eviction,
+// the pods with lower QoS class are evicted first, then the pods with lower priority.
+func sortPodsForEviction(pods []*corev1.Pod) {
+	sort.SliceStable(pods, func(i, j int) bool {
+		qi, qj := qosRank(podQOSClass(pods[i])), qosRank(podQOSClass(pods[j]))
+		if qi != qj {
+			return qi < qj
+		}
+		return podPriority(pods[i]) < podPriority(pods[j])
+	})
+}
+
+func qosRank(class corev1.PodQOSClass) int {
+	switch class {
+	case corev1.PodQOSBestEffort:
+		return 0
+	case corev1.PodQOSBurstable:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func podPriority(pod *corev1.Pod) int32 {
+	if pod.Spec.Priority == nil {
+		return 0
+	}
+	return *pod.Spec.Priority
+}
+
+// podQOSClass returns the QoS class of the pod,
+// it is computed from the resources of containers if the status is not set.
+func podQOSClass(pod *corev1.Pod) corev1.PodQOSClass {
+	if pod.Status.QOSClass != "" {
+		return pod.Status.QOSClass
+	}
+
+	hasResources := false
+	guaranteed := true
+	for _, container := range pod.Spec.Containers {
+		requests := container.Resources.Requests
+		limits := container.Resources.Limits
+		if len(requests) != 0 || len(limits) != 0 {
+			hasResources = true
+		}
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			limit, ok := limits[name]
+			if !ok {
+				guaranteed = false
+				continue
+			}
+			if request, ok := requests[name]; ok && request.Cmp(limit) != 0 {
+				guaranteed = false
+			}
+		}
+	}
+
+	switch {
+	case !hasResources:
+		return corev1.PodQOSBestEffort
+	case guaranteed:
+		return corev1.PodQOSGuaranteed
+	default:
+		return corev1.PodQOSBurstable
+	}
+}
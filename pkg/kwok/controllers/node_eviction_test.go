@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/kwok/pkg/utils/format"
+)
+
+func newEvictionTestPod(name string, priority int32, resources corev1.ResourceRequirements) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "node0",
+			Priority: format.Ptr(priority),
+			Containers: []corev1.Container{
+				{
+					Name:      "container",
+					Resources: resources,
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+}
+
+var (
+	guaranteedResources = corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}
+	burstableResources = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}
+	bestEffortResources = corev1.ResourceRequirements{}
+)
+
+func TestSortPodsForEviction(t *testing.T) {
+	pods := []*corev1.Pod{
+		newEvictionTestPod("guaranteed", 0, guaranteedResources),
+		newEvictionTestPod("burstable-high", 1000, burstableResources),
+		newEvictionTestPod("burstable-low", 0, burstableResources),
+		newEvictionTestPod("best-effort", 0, bestEffortResources),
+	}
+	sortPodsForEviction(pods)
+
+	want := []string{"best-effort", "burstable-low", "burstable-high", "guaranteed"}
+	for i, pod := range pods {
+		if pod.Name != want[i] {
+			t.Errorf("want pod %d %s, got %s", i, want[i], pod.Name)
+		}
+	}
+}
+
+func TestNodeControllerEvictPodOnMemoryPressure(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node0",
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:   corev1.NodeMemoryPressure,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		},
+	}
+	objs := []runtime.Object{
+		node,
+		newEvictionTestPod("guaranteed", 0, guaranteedResources),
+		newEvictionTestPod("best-effort", 0, bestEffortResources),
+	}
+	clientset := fake.NewSimpleClientset(objs...)
+	ctx := context.Background()
+	c := &NodeController{
+		typedClient:                  clientset,
+		enableMemoryPressureEviction: true,
+		podsOnNodeFunc: func(nodeName string) []*corev1.Pod {
+			list, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			pods := []*corev1.Pod{}
+			for i := range list.Items {
+				if list.Items[i].Spec.NodeName == nodeName {
+					pods = append(pods, &list.Items[i])
+				}
+			}
+			return pods
+		},
+	}
+
+	if !isNodeConditionTrue(node, corev1.NodeMemoryPressure) {
+		t.Fatal("want node under memory pressure")
+	}
+
+	want := []string{"best-effort", "guaranteed"}
+	for i, name := range want {
+		err := c.evictPodOnMemoryPressure(ctx, node)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pod, err := clientset.CoreV1().Pods("default").Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pod.Status.Phase != corev1.PodFailed || pod.Status.Reason != evictionReason {
+			t.Fatalf("eviction %d: want pod %s evicted, got phase %s reason %q", i, name, pod.Status.Phase, pod.Status.Reason)
+		}
+
+		for _, other := range want[i+1:] {
+			pod, err := clientset.CoreV1().Pods("default").Get(ctx, other, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pod.Status.Phase == corev1.PodFailed {
+				t.Fatalf("eviction %d: want pod %s not evicted before %s", i, other, name)
+			}
+		}
+	}
+}
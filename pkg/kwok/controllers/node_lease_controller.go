@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	coordinationv1 "k8s.io/api/coordination/v1"
@@ -54,6 +55,8 @@ type NodeLeaseController struct {
 
 	holderIdentity    string
 	onNodeManagedFunc func(nodeName string)
+
+	workers sync.WaitGroup
 }
 
 // NodeLeaseControllerConfig is the configuration for NodeLeaseController
@@ -64,10 +67,12 @@ type NodeLeaseControllerConfig struct {
 	LeaseDurationSeconds uint
 	LeaseParallelism     uint
 	GetLease             func(nodeName string) (*coordinationv1.Lease, bool)
-	RenewInterval        time.Duration
-	RenewIntervalJitter  float64
-	MutateLeaseFunc      func(*coordinationv1.Lease) error
-	OnNodeManagedFunc    func(nodeName string)
+	// RenewInterval is the interval to renew the leases, defaults to a quarter of the lease duration like the kubelet.
+	RenewInterval time.Duration
+	// RenewIntervalJitter is the factor of the random jitter added to the renew interval, defaults to 0.04 like the kubelet.
+	RenewIntervalJitter float64
+	MutateLeaseFunc     func(*coordinationv1.Lease) error
+	OnNodeManagedFunc   func(nodeName string)
 }
 
 // NewNodeLeaseController constructs and returns a NodeLeaseController
@@ -76,6 +81,21 @@ func NewNodeLeaseController(conf NodeLeaseControllerConfig) (*NodeLeaseControlle
 		return nil, fmt.Errorf("node leases parallelism must be greater than 0")
 	}
 
+	leaseDuration := time.Duration(conf.LeaseDurationSeconds) * time.Second
+	if conf.RenewInterval == 0 {
+		// https://github.com/kubernetes/kubernetes/blob/02f4d643eae2e225591702e1bbf432efea453a26/pkg/kubelet/kubelet.go#L199-L200
+		conf.RenewInterval = leaseDuration / 4
+	} else if conf.RenewInterval < 0 || conf.RenewInterval > leaseDuration {
+		return nil, fmt.Errorf("node leases renew interval %s must be greater than 0 and not greater than the lease duration %s", conf.RenewInterval, leaseDuration)
+	}
+
+	if conf.RenewIntervalJitter == 0 {
+		// https://github.com/kubernetes/component-helpers/blob/d17b6f1e84500ee7062a26f5327dc73cb3e9374a/apimachinery/lease/controller.go#L100
+		conf.RenewIntervalJitter = 0.04
+	} else if conf.RenewIntervalJitter < 0 {
+		return nil, fmt.Errorf("node leases renew interval jitter must not be negative")
+	}
+
 	if conf.Clock == nil {
 		conf.Clock = clock.RealClock{}
 	}
@@ -100,11 +120,20 @@ func NewNodeLeaseController(conf NodeLeaseControllerConfig) (*NodeLeaseControlle
 // Start starts the NodeLeaseController
 func (c *NodeLeaseController) Start(ctx context.Context) error {
 	for i := uint(0); i < c.leaseParallelism; i++ {
-		go c.syncWorker(ctx)
+		c.workers.Add(1)
+		go func() {
+			defer c.workers.Done()
+			c.syncWorker(ctx)
+		}()
 	}
 	return nil
 }
 
+// Wait waits for the workers to stop after the context passed to Start is done
+func (c *NodeLeaseController) Wait() {
+	c.workers.Wait()
+}
+
 func (c *NodeLeaseController) syncWorker(ctx context.Context) {
 	logger := log.FromContext(ctx)
 	for ctx.Err() == nil {
@@ -121,6 +150,7 @@ func (c *NodeLeaseController) syncWorker(ctx context.Context) {
 
 		lease, err := c.sync(ctx, nodeName)
 		if err != nil {
+			recordReconcileError("lease", err)
 			logger.Error("Failed to sync lease", err,
 				"node", nodeName,
 			)
@@ -160,6 +190,25 @@ func (c *NodeLeaseController) ReleaseHold(name string) {
 	c.holdLeaseSet.Delete(name)
 }
 
+// Release stops holding the lease and clears its holder,
+// so that the node can be taken over by another controller without waiting for the lease to expire.
+func (c *NodeLeaseController) Release(ctx context.Context, name string) error {
+	c.ReleaseHold(name)
+
+	lease, ok := c.getLease(name)
+	if !ok || lease == nil || format.ElemOrDefault(lease.Spec.HolderIdentity) != c.holderIdentity {
+		return nil
+	}
+
+	lease = lease.DeepCopy()
+	lease.Spec.HolderIdentity = nil
+	_, err := c.typedClient.CoordinationV1().Leases(lease.Namespace).Update(ctx, lease, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 // Held returns true if the NodeLeaseController holds the lease
 func (c *NodeLeaseController) Held(name string) bool {
 	lease, ok := c.getLease(name)
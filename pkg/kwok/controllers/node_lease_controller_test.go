@@ -24,10 +24,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
 
 	"sigs.k8s.io/kwok/pkg/log"
 	"sigs.k8s.io/kwok/pkg/utils/format"
@@ -315,3 +319,110 @@ func TestExpireTime(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeLeaseControllerReconcileErrors(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "leases", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(coordinationv1.Resource("leases"), "lease0", fmt.Errorf("injected"))
+	})
+
+	ctx := context.Background()
+	ctx = log.NewContext(ctx, log.NewLogger(os.Stderr, log.LevelDebug))
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	t.Cleanup(func() {
+		cancel()
+		time.Sleep(time.Second)
+	})
+
+	nodeLeases, err := NewNodeLeaseController(NodeLeaseControllerConfig{
+		TypedClient: clientset,
+		GetLease: func(nodeName string) (*coordinationv1.Lease, bool) {
+			return nil, false
+		},
+		HolderIdentity:       "test",
+		LeaseDurationSeconds: 40,
+		LeaseParallelism:     1,
+		RenewInterval:        10 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(fmt.Errorf("new node leases controller error: %w", err))
+	}
+
+	counter := reconcileErrorsTotal.WithLabelValues("lease", "forbidden")
+	before := testutil.ToFloat64(counter)
+
+	err = nodeLeases.Start(ctx)
+	if err != nil {
+		t.Fatal(fmt.Errorf("start node leases controller error: %w", err))
+	}
+
+	nodeLeases.TryHold("lease0")
+
+	for i := 0; i < 50 && testutil.ToFloat64(counter) == before; i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if got := testutil.ToFloat64(counter) - before; got < 1 {
+		t.Errorf("want reconcile errors of lease incremented, got %v", got)
+	}
+	if nodeLeases.Held("lease0") {
+		t.Error("lease0 held")
+	}
+}
+
+func TestNewNodeLeaseControllerRenewInterval(t *testing.T) {
+	tests := []struct {
+		name                string
+		renewInterval       time.Duration
+		renewIntervalJitter float64
+		wantRenewInterval   time.Duration
+		wantJitter          float64
+		wantErr             bool
+	}{
+		{
+			name:              "default",
+			wantRenewInterval: 10 * time.Second,
+			wantJitter:        0.04,
+		},
+		{
+			name:                "custom",
+			renewInterval:       30 * time.Second,
+			renewIntervalJitter: 0.5,
+			wantRenewInterval:   30 * time.Second,
+			wantJitter:          0.5,
+		},
+		{
+			name:          "larger than lease duration",
+			renewInterval: 41 * time.Second,
+			wantErr:       true,
+		},
+		{
+			name:                "negative jitter",
+			renewIntervalJitter: -0.1,
+			wantErr:             true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewNodeLeaseController(NodeLeaseControllerConfig{
+				TypedClient:          fake.NewSimpleClientset(),
+				LeaseDurationSeconds: 40,
+				LeaseParallelism:     1,
+				RenewInterval:        tt.renewInterval,
+				RenewIntervalJitter:  tt.renewIntervalJitter,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewNodeLeaseController() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if c.renewInterval != tt.wantRenewInterval {
+				t.Errorf("want renew interval %s, got %s", tt.wantRenewInterval, c.renewInterval)
+			}
+			if c.renewIntervalJitter != tt.wantJitter {
+				t.Errorf("want renew interval jitter %v, got %v", tt.wantJitter, c.renewIntervalJitter)
+			}
+		})
+	}
+}
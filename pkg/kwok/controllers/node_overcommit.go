@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/kwok/pkg/utils/lifecycle"
+)
+
+// overcommit is the factors to scale the allocatable of the nodes relative to the capacity
+type overcommit struct {
+	CPU    float64
+	Memory float64
+}
+
+// validateOvercommitFactor checks the overcommit factor, zero means disabled
+func validateOvercommitFactor(name string, factor float64) error {
+	if factor < 0 || math.IsNaN(factor) || math.IsInf(factor, 0) {
+		return fmt.Errorf("%s must be greater than 0, or 0 to disable it, got %v", name, factor)
+	}
+	return nil
+}
+
+// enabled returns true if any of the factors would change the allocatable
+func (o overcommit) enabled() bool {
+	return (o.CPU != 0 && o.CPU != 1) ||
+		(o.Memory != 0 && o.Memory != 1)
+}
+
+// applyToPatch scales the cpu and memory of the allocatable set by the merge patch of the node status.
+// The allocatable is computed from the capacity, either in the patch or of the node,
+// so that rendering the same patch again does not compound the factor.
+func (o overcommit) applyToPatch(node *corev1.Node, patch *lifecycle.Patch) error {
+	if !o.enabled() {
+		return nil
+	}
+	if patch.Type != types.MergePatchType && patch.Type != types.StrategicMergePatchType {
+		return nil
+	}
+
+	var data map[string]any
+	err := json.Unmarshal(patch.Data, &data)
+	if err != nil {
+		return err
+	}
+	status, ok := data["status"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	allocatable, ok := status["allocatable"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	capacity, _ := status["capacity"].(map[string]any)
+
+	changed := false
+	for name, factor := range map[corev1.ResourceName]float64{
+		corev1.ResourceCPU:    o.CPU,
+		corev1.ResourceMemory: o.Memory,
+	} {
+		if factor == 0 || factor == 1 {
+			continue
+		}
+		base, ok, err := overcommitBase(node, name, capacity, allocatable)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		allocatable[string(name)] = scaleQuantity(name, base, factor).String()
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	patch.Data, err = json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// overcommitBase returns the quantity to scale for the resource,
+// which is the capacity in the patch, then the capacity of the node, then the allocatable in the patch.
+func overcommitBase(node *corev1.Node, name corev1.ResourceName, capacity, allocatable map[string]any) (resource.Quantity, bool, error) {
+	if v, ok := capacity[string(name)]; ok {
+		return parseQuantity(name, v)
+	}
+	if q, ok := node.Status.Capacity[name]; ok {
+		return q, true, nil
+	}
+	if v, ok := allocatable[string(name)]; ok {
+		return parseQuantity(name, v)
+	}
+	return resource.Quantity{}, false, nil
+}
+
+func parseQuantity(name corev1.ResourceName, v any) (resource.Quantity, bool, error) {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return resource.Quantity{}, false, fmt.Errorf("invalid quantity of %s: %v", name, v)
+	}
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return resource.Quantity{}, false, fmt.Errorf("invalid quantity of %s: %w", name, err)
+	}
+	return q, true, nil
+}
+
+// scaleQuantity multiplies the quantity by the factor, the cpu is kept in millicores
+func scaleQuantity(name corev1.ResourceName, q resource.Quantity, factor float64) *resource.Quantity {
+	if name == corev1.ResourceCPU {
+		return resource.NewMilliQuantity(int64(float64(q.MilliValue())*factor), q.Format)
+	}
+	return resource.NewQuantity(int64(float64(q.Value())*factor), q.Format)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/kwok/pkg/utils/lifecycle"
+)
+
+func TestOvercommitApplyToPatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		overcommit overcommit
+		node       *corev1.Node
+		patchType  types.PatchType
+		data       string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "disabled",
+			overcommit: overcommit{},
+			patchType:  types.MergePatchType,
+			data:       `{"status":{"allocatable":{"cpu":"4","memory":"8Gi"}}}`,
+			want:       `{"status":{"allocatable":{"cpu":"4","memory":"8Gi"}}}`,
+		},
+		{
+			name:       "scale from capacity in patch",
+			overcommit: overcommit{CPU: 2, Memory: 1.5},
+			patchType:  types.MergePatchType,
+			data:       `{"status":{"allocatable":{"cpu":"3","memory":"7Gi","pods":"110"},"capacity":{"cpu":"4","memory":"8Gi","pods":"110"}}}`,
+			want:       `{"status":{"allocatable":{"cpu":"8","memory":"12Gi","pods":"110"},"capacity":{"cpu":"4","memory":"8Gi","pods":"110"}}}`,
+		},
+		{
+			name:       "scale from capacity of node",
+			overcommit: overcommit{CPU: 0.5},
+			node: &corev1.Node{
+				Status: corev1.NodeStatus{
+					Capacity: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("4"),
+					},
+				},
+			},
+			patchType: types.StrategicMergePatchType,
+			data:      `{"status":{"allocatable":{"cpu":"4","memory":"8Gi"}}}`,
+			want:      `{"status":{"allocatable":{"cpu":"2","memory":"8Gi"}}}`,
+		},
+		{
+			name:       "scale from allocatable",
+			overcommit: overcommit{CPU: 2},
+			patchType:  types.MergePatchType,
+			data:       `{"status":{"allocatable":{"cpu":"1k"}}}`,
+			want:       `{"status":{"allocatable":{"cpu":"2k"}}}`,
+		},
+		{
+			name:       "no allocatable",
+			overcommit: overcommit{CPU: 2},
+			patchType:  types.MergePatchType,
+			data:       `{"status":{"phase":"Running"}}`,
+			want:       `{"status":{"phase":"Running"}}`,
+		},
+		{
+			name:       "json patch is skipped",
+			overcommit: overcommit{CPU: 2},
+			patchType:  types.JSONPatchType,
+			data:       `[{"op":"replace","path":"/status/allocatable/cpu","value":"4"}]`,
+			want:       `[{"op":"replace","path":"/status/allocatable/cpu","value":"4"}]`,
+		},
+		{
+			name:       "invalid quantity",
+			overcommit: overcommit{CPU: 2},
+			patchType:  types.MergePatchType,
+			data:       `{"status":{"allocatable":{"cpu":"four"}}}`,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := tt.node
+			if node == nil {
+				node = &corev1.Node{}
+			}
+			patch := &lifecycle.Patch{
+				Data: []byte(tt.data),
+				Type: tt.patchType,
+			}
+			err := tt.overcommit.applyToPatch(node, patch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyToPatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got, want any
+			if err := json.Unmarshal(patch.Data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("applyToPatch() got %s, want %s", patch.Data, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateOvercommitFactor(t *testing.T) {
+	for _, factor := range []float64{0, 0.5, 1, 2} {
+		if err := validateOvercommitFactor("cpuOvercommit", factor); err != nil {
+			t.Errorf("validateOvercommitFactor(%v) unexpected error: %v", factor, err)
+		}
+	}
+	for _, factor := range []float64{-1, -0.5} {
+		if err := validateOvercommitFactor("cpuOvercommit", factor); err == nil {
+			t.Errorf("validateOvercommitFactor(%v) want error", factor)
+		}
+	}
+}
@@ -0,0 +1,479 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/lifecycle"
+	"sigs.k8s.io/kwok/pkg/utils/slices"
+)
+
+const (
+	// podAdmissionFailurePolicyIgnore runs the stages for all pods without checking if they fit the node.
+	podAdmissionFailurePolicyIgnore = "Ignore"
+	// podAdmissionFailurePolicyPending leaves the pods that do not fit the node Pending with the reason.
+	podAdmissionFailurePolicyPending = "Pending"
+	// podAdmissionFailurePolicyFail fails the pods that do not fit the node like the kubelet does.
+	podAdmissionFailurePolicyFail = "Fail"
+
+	podRejectedMessagePrefix    = "Pod was rejected: "
+	taintRejectedReason         = "TaintToleration"
+	nodeAffinityRejectedReason  = "NodeAffinity"
+	nodeAffinityRejectedMessage = "Predicate NodeAffinity failed: node(s) didn't match Pod's node affinity/selector"
+)
+
+// validatePodAdmissionFailurePolicy returns an error if the policy is unknown
+func validatePodAdmissionFailurePolicy(policy string) error {
+	switch policy {
+	case "", podAdmissionFailurePolicyIgnore, podAdmissionFailurePolicyPending, podAdmissionFailurePolicyFail:
+		return nil
+	}
+	return fmt.Errorf("unknown pod admission failure policy %q, must be one of %s, %s or %s", policy,
+		podAdmissionFailurePolicyIgnore, podAdmissionFailurePolicyPending, podAdmissionFailurePolicyFail)
+}
+
+// podAdmission tracks the resources requested by the admitted pods on each node,
+// and the pods left Pending by a rejection which are re-evaluated when the node changes.
+type podAdmission struct {
+	mut      sync.Mutex
+	nodes    map[string]map[log.ObjectRef]corev1.ResourceList
+	rejected map[string]map[log.ObjectRef]*corev1.Pod
+}
+
+// admitted returns whether the pod is admitted on the node
+func (a *podAdmission) admitted(nodeName string, pod log.ObjectRef) bool {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+	_, ok := a.nodes[nodeName][pod]
+	return ok
+}
+
+// admit checks if the pod fits the node and records its requests if it does,
+// the reason and message are returned if the pod is rejected.
+// The pod is recorded without any check if force is true.
+func (a *podAdmission) admit(node *corev1.Node, pod *corev1.Pod, force bool) (reason, message string) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	key := log.KObj(pod)
+	requests := podRequests(pod)
+	pods := a.nodes[node.Name]
+	if _, ok := pods[key]; ok {
+		return "", ""
+	}
+
+	if !force {
+		reason, message = checkNodeAffinity(node, pod)
+		if reason != "" {
+			return reason, message
+		}
+		reason, message = checkNodeTaints(node, pod)
+		if reason != "" {
+			return reason, message
+		}
+		reason, message = checkNodeResources(node, requests, pods)
+		if reason != "" {
+			return reason, message
+		}
+	}
+
+	if pods == nil {
+		if a.nodes == nil {
+			a.nodes = map[string]map[log.ObjectRef]corev1.ResourceList{}
+		}
+		pods = map[log.ObjectRef]corev1.ResourceList{}
+		a.nodes[node.Name] = pods
+	}
+	pods[key] = requests
+	a.forgetRejected(node.Name, key)
+	return "", ""
+}
+
+// reject records the pod rejected on the node to be re-evaluated later
+func (a *podAdmission) reject(nodeName string, pod *corev1.Pod) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+	pods := a.rejected[nodeName]
+	if pods == nil {
+		if a.rejected == nil {
+			a.rejected = map[string]map[log.ObjectRef]*corev1.Pod{}
+		}
+		pods = map[log.ObjectRef]*corev1.Pod{}
+		a.rejected[nodeName] = pods
+	}
+	pods[log.KObj(pod)] = pod
+}
+
+// rejectedPods returns the pods rejected on the node
+func (a *podAdmission) rejectedPods(nodeName string) []*corev1.Pod {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+	pods := a.rejected[nodeName]
+	if len(pods) == 0 {
+		return nil
+	}
+	out := make([]*corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		out = append(out, pod)
+	}
+	return out
+}
+
+// forgetRejected forgets the pod rejected on the node, the caller must hold the lock
+func (a *podAdmission) forgetRejected(nodeName string, pod log.ObjectRef) {
+	pods := a.rejected[nodeName]
+	if pods == nil {
+		return
+	}
+	delete(pods, pod)
+	if len(pods) == 0 {
+		delete(a.rejected, nodeName)
+	}
+}
+
+// release releases the resources requested by the pod on the node
+func (a *podAdmission) release(nodeName string, pod log.ObjectRef) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+	a.forgetRejected(nodeName, pod)
+	pods := a.nodes[nodeName]
+	if pods == nil {
+		return
+	}
+	delete(pods, pod)
+	if len(pods) == 0 {
+		delete(a.nodes, nodeName)
+	}
+}
+
+// checkNodeAffinity checks if the node matches the node selector and the required node affinity of the pod,
+// the preferred node affinity is only used by the scheduler and is not checked.
+func checkNodeAffinity(node *corev1.Node, pod *corev1.Pod) (reason, message string) {
+	if len(pod.Spec.NodeSelector) != 0 &&
+		!labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return nodeAffinityRejectedReason, podRejectedMessagePrefix + nodeAffinityRejectedMessage
+	}
+
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil ||
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return "", ""
+	}
+	// The terms are ORed, and an empty list of terms matches no node.
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if matchNodeSelectorTerm(node, term) {
+			return "", ""
+		}
+	}
+	return nodeAffinityRejectedReason, podRejectedMessagePrefix + nodeAffinityRejectedMessage
+}
+
+// matchNodeSelectorTerm returns whether the node matches all the requirements of the term,
+// an empty term matches no node.
+func matchNodeSelectorTerm(node *corev1.Node, term corev1.NodeSelectorTerm) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	for _, req := range term.MatchExpressions {
+		if !matchNodeSelectorRequirement(labels.Set(node.Labels), req) {
+			return false
+		}
+	}
+	for _, req := range term.MatchFields {
+		if !matchNodeSelectorField(node, req) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchNodeSelectorField returns whether the node matches the requirement of the field,
+// metadata.name with In and NotIn is the only one supported by the node affinity.
+// The name is compared directly, as a node name may be longer than a label value.
+func matchNodeSelectorField(node *corev1.Node, req corev1.NodeSelectorRequirement) bool {
+	if req.Key != "metadata.name" {
+		return false
+	}
+	switch req.Operator {
+	case corev1.NodeSelectorOpIn:
+		return slices.Contains(req.Values, node.Name)
+	case corev1.NodeSelectorOpNotIn:
+		return !slices.Contains(req.Values, node.Name)
+	default:
+		return false
+	}
+}
+
+// nodeSelectorOperators maps the operators of the node selector to the ones of the label selector
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+// matchNodeSelectorRequirement returns whether the set matches the requirement,
+// an invalid requirement matches nothing.
+func matchNodeSelectorRequirement(set labels.Set, req corev1.NodeSelectorRequirement) bool {
+	op, ok := nodeSelectorOperators[req.Operator]
+	if !ok {
+		return false
+	}
+	r, err := labels.NewRequirement(req.Key, op, req.Values)
+	if err != nil {
+		return false
+	}
+	return r.Matches(set)
+}
+
+// checkNodeTaints checks if the pod tolerates the NoExecute taints of the node
+func checkNodeTaints(node *corev1.Node, pod *corev1.Pod) (reason, message string) {
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+		tolerated := false
+		for j := range pod.Spec.Tolerations {
+			if pod.Spec.Tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return taintRejectedReason, podRejectedMessagePrefix + fmt.Sprintf("Predicate TaintToleration failed: node(s) had untolerated taint {%s: %s}", taint.Key, taint.Value)
+		}
+	}
+	return "", ""
+}
+
+// checkNodeResources checks if the node has enough allocatable resources for the requests
+// in addition to the ones of the admitted pods.
+func checkNodeResources(node *corev1.Node, requests corev1.ResourceList, pods map[log.ObjectRef]corev1.ResourceList) (reason, message string) {
+	allocatable := node.Status.Allocatable
+	if len(allocatable) == 0 {
+		allocatable = node.Status.Capacity
+	}
+
+	if capacity, ok := allocatable[corev1.ResourcePods]; ok && int64(len(pods))+1 > capacity.Value() {
+		return insufficientResource(corev1.ResourcePods, 1, int64(len(pods)), capacity.Value())
+	}
+
+	for _, name := range sortedResourceNames(requests) {
+		request := requests[name]
+		if request.IsZero() {
+			continue
+		}
+		var used resource.Quantity
+		for _, podRequests := range pods {
+			if q, ok := podRequests[name]; ok {
+				used.Add(q)
+			}
+		}
+		capacity := allocatable[name]
+		if resourceValue(name, used)+resourceValue(name, request) > resourceValue(name, capacity) {
+			return insufficientResource(name, resourceValue(name, request), resourceValue(name, used), resourceValue(name, capacity))
+		}
+	}
+	return "", ""
+}
+
+// insufficientResource returns the reason and message of the pod rejected by the kubelet for the resource
+func insufficientResource(name corev1.ResourceName, requested, used, capacity int64) (reason, message string) {
+	return "OutOf" + string(name), podRejectedMessagePrefix + fmt.Sprintf("Node didn't have enough resource: %s, requested: %d, used: %d, capacity: %d", name, requested, used, capacity)
+}
+
+// sortedResourceNames returns the names of the resources in order
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// resourceValue returns the value of the quantity, cpu is counted in millicores
+func resourceValue(name corev1.ResourceName, q resource.Quantity) int64 {
+	if name == corev1.ResourceCPU {
+		return q.MilliValue()
+	}
+	return q.Value()
+}
+
+// podRequests returns the resources requested by the pod,
+// which is the larger of the sum of the containers and the largest init container, plus the overhead.
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		for name, q := range container.Resources.Requests {
+			sum := requests[name]
+			sum.Add(q)
+			requests[name] = sum
+		}
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, q := range container.Resources.Requests {
+			if current, ok := requests[name]; !ok || q.Cmp(current) > 0 {
+				requests[name] = q.DeepCopy()
+			}
+		}
+	}
+	for name, q := range pod.Spec.Overhead {
+		sum := requests[name]
+		sum.Add(q)
+		requests[name] = sum
+	}
+	return requests
+}
+
+// isPodRejected returns whether the pod was rejected by the admission
+func isPodRejected(pod *corev1.Pod) bool {
+	return strings.HasPrefix(pod.Status.Message, podRejectedMessagePrefix)
+}
+
+// admitPod checks if the pod fits its node, the pods that do not fit are handled by the policy.
+// It returns whether the stages should be played for the pod.
+func (c *PodController) admitPod(ctx context.Context, pod *corev1.Pod) (bool, error) {
+	key := log.KObj(pod)
+	if pod.DeletionTimestamp != nil ||
+		pod.Status.Phase == corev1.PodSucceeded ||
+		pod.Status.Phase == corev1.PodFailed {
+		c.podAdmission.release(pod.Spec.NodeName, key)
+		c.RequeueRejectedPods(pod.Spec.NodeName)
+		return pod.DeletionTimestamp != nil || !isPodRejected(pod), nil
+	}
+
+	if c.podAdmission.admitted(pod.Spec.NodeName, key) {
+		return true, nil
+	}
+
+	if c.nodeCacheGetter == nil {
+		return true, nil
+	}
+	node, ok := c.nodeCacheGetter.Get(pod.Spec.NodeName)
+	if !ok {
+		return true, nil
+	}
+
+	// The pods that are already running were admitted before, they are only recorded.
+	force := pod.Status.PodIP != "" || pod.Status.Phase == corev1.PodRunning
+	reason, message := c.podAdmission.admit(node, pod, force)
+	if reason == "" {
+		if isPodRejected(pod) {
+			// Clear the reason of the previous rejection.
+			_, err := c.patchResource(ctx, pod, &lifecycle.Patch{
+				Data:        []byte(`{"status":{"reason":null,"message":null}}`),
+				Type:        types.MergePatchType,
+				Subresource: "status",
+			})
+			if err != nil {
+				c.podAdmission.release(pod.Spec.NodeName, key)
+				return false, err
+			}
+		}
+		return true, nil
+	}
+
+	phase := corev1.PodPending
+	if c.podAdmissionFailurePolicy == podAdmissionFailurePolicyFail {
+		phase = corev1.PodFailed
+	}
+	if pod.Status.Phase == phase && pod.Status.Reason == reason && pod.Status.Message == message {
+		if phase == corev1.PodPending {
+			c.podAdmission.reject(pod.Spec.NodeName, pod)
+		}
+		return false, nil
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"status": map[string]any{
+			"phase":   phase,
+			"reason":  reason,
+			"message": message,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	result, err := c.patchResource(ctx, pod, &lifecycle.Patch{
+		Data:        data,
+		Type:        types.MergePatchType,
+		Subresource: "status",
+	})
+	if err != nil {
+		return false, err
+	}
+	if phase == corev1.PodPending {
+		// The Pending pod is admitted once the node has room for it.
+		c.podAdmission.reject(pod.Spec.NodeName, result)
+	}
+
+	logger := log.FromContext(ctx)
+	logger.Info("Rejected pod",
+		"pod", key,
+		"node", pod.Spec.NodeName,
+		"reason", reason,
+		"phase", phase,
+	)
+	return false, nil
+}
+
+// RequeueRejectedPods re-evaluates the admission of the pods left Pending on the node,
+// it is called when the node is updated or the resources of a pod on it are released.
+func (c *PodController) RequeueRejectedPods(nodeName string) {
+	if c.rejectedPodsQueue == nil || len(c.podAdmission.rejectedPods(nodeName)) == 0 {
+		return
+	}
+	c.rejectedPodsQueue.Add(nodeName)
+}
+
+// requeueRejectedPodsWorker pushes the rejected pods of the queued nodes back to the preprocessChan
+func (c *PodController) requeueRejectedPodsWorker(ctx context.Context) {
+	logger := log.FromContext(ctx)
+	for ctx.Err() == nil {
+		nodeName, ok := c.rejectedPodsQueue.GetOrWaitWithDone(ctx.Done())
+		if !ok {
+			return
+		}
+		for _, pod := range c.podAdmission.rejectedPods(nodeName) {
+			logger.Debug("Re-push to preprocessChan",
+				"reason", "re-evaluate the rejected pod",
+				"pod", log.KObj(pod),
+				"node", nodeName,
+			)
+			c.preprocessShards.Push(log.KObj(pod).String(), pod)
+		}
+	}
+}
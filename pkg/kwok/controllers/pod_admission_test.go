@@ -0,0 +1,536 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/kwok/pkg/utils/queue"
+)
+
+type fakeNodeGetter map[string]*corev1.Node
+
+func (f fakeNodeGetter) Get(name string) (*corev1.Node, bool) {
+	node, ok := f[name]
+	return node, ok
+}
+
+func (f fakeNodeGetter) GetWithNamespace(name, _ string) (*corev1.Node, bool) {
+	return f.Get(name)
+}
+
+func (f fakeNodeGetter) List() []*corev1.Node {
+	out := make([]*corev1.Node, 0, len(f))
+	for _, node := range f {
+		out = append(out, node)
+	}
+	return out
+}
+
+func TestPodControllerAdmitPod(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node",
+		},
+		Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{
+				{
+					Key:    "dedicated",
+					Value:  "infra",
+					Effect: corev1.TaintEffectNoSchedule,
+				},
+			},
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+				corev1.ResourcePods:   resource.MustParse("2"),
+			},
+		},
+	}
+	taintedNode := node.DeepCopy()
+	taintedNode.Name = "tainted-node"
+	taintedNode.Spec.Taints = []corev1.Taint{
+		{
+			Key:    "maintenance",
+			Effect: corev1.TaintEffectNoExecute,
+		},
+	}
+
+	newPod := func(name, nodeName, cpu string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+				Containers: []corev1.Container{
+					{
+						Name: "container",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse(cpu),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		policy     string
+		admitted   []*corev1.Pod
+		pod        *corev1.Pod
+		wantAdmit  bool
+		wantPhase  corev1.PodPhase
+		wantReason string
+	}{
+		{
+			name:      "fits with fail policy",
+			policy:    podAdmissionFailurePolicyFail,
+			admitted:  []*corev1.Pod{newPod("running", "node", "1")},
+			pod:       newPod("pod", "node", "1"),
+			wantAdmit: true,
+		},
+		{
+			name:       "out of cpu with fail policy",
+			policy:     podAdmissionFailurePolicyFail,
+			admitted:   []*corev1.Pod{newPod("running", "node", "1500m")},
+			pod:        newPod("pod", "node", "1"),
+			wantPhase:  corev1.PodFailed,
+			wantReason: "OutOfcpu",
+		},
+		{
+			name:       "out of cpu with pending policy",
+			policy:     podAdmissionFailurePolicyPending,
+			admitted:   []*corev1.Pod{newPod("running", "node", "1500m")},
+			pod:        newPod("pod", "node", "1"),
+			wantPhase:  corev1.PodPending,
+			wantReason: "OutOfcpu",
+		},
+		{
+			name:       "out of pods with fail policy",
+			policy:     podAdmissionFailurePolicyFail,
+			admitted:   []*corev1.Pod{newPod("running-1", "node", "0"), newPod("running-2", "node", "0")},
+			pod:        newPod("pod", "node", "0"),
+			wantPhase:  corev1.PodFailed,
+			wantReason: "OutOfpods",
+		},
+		{
+			name:       "untolerated taint with pending policy",
+			policy:     podAdmissionFailurePolicyPending,
+			pod:        newPod("pod", "tainted-node", "0"),
+			wantPhase:  corev1.PodPending,
+			wantReason: taintRejectedReason,
+		},
+		{
+			name:   "tolerated taint with fail policy",
+			policy: podAdmissionFailurePolicyFail,
+			pod: func() *corev1.Pod {
+				pod := newPod("pod", "tainted-node", "0")
+				pod.Spec.Tolerations = []corev1.Toleration{
+					{
+						Key:      "maintenance",
+						Operator: corev1.TolerationOpExists,
+					},
+				}
+				return pod
+			}(),
+			wantAdmit: true,
+		},
+		{
+			name:   "unmatched node selector with fail policy",
+			policy: podAdmissionFailurePolicyFail,
+			pod: func() *corev1.Pod {
+				pod := newPod("pod", "node", "0")
+				pod.Spec.NodeSelector = map[string]string{"disktype": "ssd"}
+				return pod
+			}(),
+			wantPhase:  corev1.PodFailed,
+			wantReason: nodeAffinityRejectedReason,
+		},
+		{
+			name:   "running pod is not rejected",
+			policy: podAdmissionFailurePolicyFail,
+			admitted: []*corev1.Pod{
+				newPod("running", "node", "2"),
+			},
+			pod: func() *corev1.Pod {
+				pod := newPod("pod", "node", "1")
+				pod.Status.Phase = corev1.PodRunning
+				pod.Status.PodIP = "10.0.0.2"
+				return pod
+			}(),
+			wantAdmit: true,
+			wantPhase: corev1.PodRunning,
+		},
+		{
+			name:   "rejected pod skips the stages",
+			policy: podAdmissionFailurePolicyFail,
+			pod: func() *corev1.Pod {
+				pod := newPod("pod", "node", "4")
+				pod.Status.Phase = corev1.PodFailed
+				pod.Status.Reason = "OutOfcpu"
+				pod.Status.Message = podRejectedMessagePrefix + "Node didn't have enough resource"
+				return pod
+			}(),
+			wantPhase:  corev1.PodFailed,
+			wantReason: "OutOfcpu",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			clientset := fake.NewSimpleClientset(tt.pod.DeepCopy())
+			c := &PodController{
+				typedClient: clientset,
+				nodeCacheGetter: fakeNodeGetter{
+					node.Name:        node,
+					taintedNode.Name: taintedNode,
+				},
+				podAdmissionFailurePolicy: tt.policy,
+			}
+			for _, pod := range tt.admitted {
+				admit, err := c.admitPod(ctx, pod)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !admit {
+					t.Fatalf("want pod %s admitted", pod.Name)
+				}
+			}
+
+			admit, err := c.admitPod(ctx, tt.pod)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if admit != tt.wantAdmit {
+				t.Fatalf("want admit %v, got %v", tt.wantAdmit, admit)
+			}
+
+			got, err := clientset.CoreV1().Pods(tt.pod.Namespace).Get(ctx, tt.pod.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status.Phase != tt.wantPhase {
+				t.Errorf("want phase %q, got %q", tt.wantPhase, got.Status.Phase)
+			}
+			if got.Status.Reason != tt.wantReason {
+				t.Errorf("want reason %q, got %q", tt.wantReason, got.Status.Reason)
+			}
+		})
+	}
+}
+
+func TestPodControllerAdmitPodRelease(t *testing.T) {
+	ctx := context.Background()
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node",
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourcePods: resource.MustParse("1"),
+			},
+		},
+	}
+	first := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: "node"},
+	}
+	second := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: "node"},
+	}
+	clientset := fake.NewSimpleClientset(first.DeepCopy(), second.DeepCopy())
+	c := &PodController{
+		typedClient:               clientset,
+		nodeCacheGetter:           fakeNodeGetter{node.Name: node},
+		podAdmissionFailurePolicy: podAdmissionFailurePolicyPending,
+	}
+
+	admit, err := c.admitPod(ctx, first)
+	if err != nil || !admit {
+		t.Fatalf("want first pod admitted, got %v, %v", admit, err)
+	}
+	admit, err = c.admitPod(ctx, second)
+	if err != nil || admit {
+		t.Fatalf("want second pod rejected, got %v, %v", admit, err)
+	}
+
+	// The second pod is admitted once the first pod is completed.
+	first.Status.Phase = corev1.PodSucceeded
+	_, err = c.admitPod(ctx, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err = clientset.CoreV1().Pods(second.Namespace).Get(ctx, second.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admit, err = c.admitPod(ctx, second)
+	if err != nil || !admit {
+		t.Fatalf("want second pod admitted, got %v, %v", admit, err)
+	}
+	second, err = clientset.CoreV1().Pods(second.Namespace).Get(ctx, second.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Status.Reason != "" || second.Status.Message != "" {
+		t.Errorf("want the rejection cleared, got reason %q, message %q", second.Status.Reason, second.Status.Message)
+	}
+}
+
+func TestCheckNodeAffinity(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-0",
+			Labels: map[string]string{
+				"disktype": "ssd",
+				"zone":     "zone-a",
+				"gpus":     "4",
+			},
+		},
+	}
+
+	required := func(terms ...corev1.NodeSelectorTerm) *corev1.Affinity {
+		return &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: terms,
+				},
+			},
+		}
+	}
+	expression := func(key string, op corev1.NodeSelectorOperator, values ...string) corev1.NodeSelectorTerm {
+		return corev1.NodeSelectorTerm{
+			MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: key, Operator: op, Values: values},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		nodeName     string
+		nodeSelector map[string]string
+		affinity     *corev1.Affinity
+		wantReason   string
+	}{
+		{
+			name: "no affinity",
+		},
+		{
+			name:         "matched node selector",
+			nodeSelector: map[string]string{"disktype": "ssd"},
+		},
+		{
+			name:         "unmatched node selector",
+			nodeSelector: map[string]string{"disktype": "hdd"},
+			wantReason:   nodeAffinityRejectedReason,
+		},
+		{
+			name:     "required in",
+			affinity: required(expression("zone", corev1.NodeSelectorOpIn, "zone-a", "zone-b")),
+		},
+		{
+			name:       "required not in",
+			affinity:   required(expression("zone", corev1.NodeSelectorOpNotIn, "zone-a")),
+			wantReason: nodeAffinityRejectedReason,
+		},
+		{
+			name:     "required gt",
+			affinity: required(expression("gpus", corev1.NodeSelectorOpGt, "2")),
+		},
+		{
+			name:       "required does not exist",
+			affinity:   required(expression("disktype", corev1.NodeSelectorOpDoesNotExist)),
+			wantReason: nodeAffinityRejectedReason,
+		},
+		{
+			name: "required terms are ORed",
+			affinity: required(
+				expression("zone", corev1.NodeSelectorOpIn, "zone-b"),
+				expression("disktype", corev1.NodeSelectorOpExists),
+			),
+		},
+		{
+			name: "required expressions are ANDed",
+			affinity: required(corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"zone-a"}},
+					{Key: "disktype", Operator: corev1.NodeSelectorOpIn, Values: []string{"hdd"}},
+				},
+			}),
+			wantReason: nodeAffinityRejectedReason,
+		},
+		{
+			name: "required match fields",
+			affinity: required(corev1.NodeSelectorTerm{
+				MatchFields: []corev1.NodeSelectorRequirement{
+					{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-0"}},
+				},
+			}),
+		},
+		{
+			name: "required match fields not in",
+			affinity: required(corev1.NodeSelectorTerm{
+				MatchFields: []corev1.NodeSelectorRequirement{
+					{Key: "metadata.name", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"node-0"}},
+				},
+			}),
+			wantReason: nodeAffinityRejectedReason,
+		},
+		{
+			name:     "required match fields of a name longer than a label value",
+			nodeName: "ip-10-0-0-1.us-west-2.compute.internal.kwok-with-a-long-cluster-name.example.com",
+			affinity: required(corev1.NodeSelectorTerm{
+				MatchFields: []corev1.NodeSelectorRequirement{
+					{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"ip-10-0-0-1.us-west-2.compute.internal.kwok-with-a-long-cluster-name.example.com"}},
+				},
+			}),
+		},
+		{
+			name: "required match fields with unsupported operator",
+			affinity: required(corev1.NodeSelectorTerm{
+				MatchFields: []corev1.NodeSelectorRequirement{
+					{Key: "metadata.name", Operator: corev1.NodeSelectorOpExists},
+				},
+			}),
+			wantReason: nodeAffinityRejectedReason,
+		},
+		{
+			name:       "required empty terms",
+			affinity:   required(),
+			wantReason: nodeAffinityRejectedReason,
+		},
+		{
+			name: "preferred is not checked",
+			affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{
+						{
+							Weight:     1,
+							Preference: expression("zone", corev1.NodeSelectorOpIn, "zone-b"),
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				Spec: corev1.PodSpec{
+					NodeSelector: tt.nodeSelector,
+					Affinity:     tt.affinity,
+				},
+			}
+			node := node.DeepCopy()
+			if tt.nodeName != "" {
+				node.Name = tt.nodeName
+			}
+			reason, _ := checkNodeAffinity(node, pod)
+			if reason != tt.wantReason {
+				t.Errorf("want reason %q, got %q", tt.wantReason, reason)
+			}
+		})
+	}
+}
+
+func TestPodControllerRequeueRejectedPods(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node",
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourcePods: resource.MustParse("1"),
+			},
+		},
+	}
+	first := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: "node"},
+	}
+	second := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: "node"},
+	}
+	clientset := fake.NewSimpleClientset(first.DeepCopy(), second.DeepCopy())
+	nodes := fakeNodeGetter{node.Name: node}
+	c := &PodController{
+		typedClient:               clientset,
+		nodeCacheGetter:           nodes,
+		podAdmissionFailurePolicy: podAdmissionFailurePolicyPending,
+		preprocessShards:          newPreprocessShards[*corev1.Pod](1),
+		rejectedPodsQueue:         queue.NewQueue[string](),
+	}
+	go c.requeueRejectedPodsWorker(ctx)
+
+	admit, err := c.admitPod(ctx, first)
+	if err != nil || !admit {
+		t.Fatalf("want first pod admitted, got %v, %v", admit, err)
+	}
+	admit, err = c.admitPod(ctx, second)
+	if err != nil || admit {
+		t.Fatalf("want second pod rejected, got %v, %v", admit, err)
+	}
+
+	// The second pod is requeued and admitted once the node has room for it.
+	updated := node.DeepCopy()
+	updated.Status.Allocatable[corev1.ResourcePods] = resource.MustParse("2")
+	nodes[node.Name] = updated
+	c.RequeueRejectedPods(node.Name)
+
+	var requeued *corev1.Pod
+	select {
+	case requeued = <-c.preprocessShards.Chan(0):
+	case <-time.After(10 * time.Second):
+		t.Fatal("want the rejected pod requeued")
+	}
+	if requeued.Name != second.Name {
+		t.Fatalf("want pod %s requeued, got %s", second.Name, requeued.Name)
+	}
+	admit, err = c.admitPod(ctx, requeued)
+	if err != nil || !admit {
+		t.Fatalf("want second pod admitted, got %v, %v", admit, err)
+	}
+	if pods := c.podAdmission.rejectedPods(node.Name); len(pods) != 0 {
+		t.Errorf("want no rejected pods, got %d", len(pods))
+	}
+	second, err = clientset.CoreV1().Pods(second.Namespace).Get(ctx, second.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Status.Reason != "" || second.Status.Message != "" {
+		t.Errorf("want the rejection cleared, got reason %q, message %q", second.Status.Reason, second.Status.Message)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/utils/cel"
+	"sigs.k8s.io/kwok/pkg/utils/lifecycle"
+)
+
+// podConditions computes the conditions of the pods from the CEL expressions of the stages
+type podConditions struct {
+	env *cel.Environment
+}
+
+// newPodConditions creates a podConditions, the compiled programs are cached by the environment
+func newPodConditions() (*podConditions, error) {
+	env, err := lifecycle.NewPodConditionsEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	return &podConditions{
+		env: env,
+	}, nil
+}
+
+// evaluate evaluates the conditions for the pod, the last transition time is kept if the status is not changed
+func (p *podConditions) evaluate(ctx context.Context, pod *corev1.Pod, conditions []internalversion.StageCondition, now time.Time) ([]corev1.PodCondition, error) {
+	result := make([]corev1.PodCondition, 0, len(conditions))
+	for _, condition := range conditions {
+		program, err := p.env.Compile(condition.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile condition %s: %w", condition.Type, err)
+		}
+		refVal, _, err := program.ContextEval(ctx, map[string]any{
+			"pod": pod,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate condition %s: %w", condition.Type, err)
+		}
+		ok, err := cel.AsBool(refVal)
+		if err != nil {
+			return nil, fmt.Errorf("condition %s must evaluate to a bool: %w", condition.Type, err)
+		}
+
+		podCondition := corev1.PodCondition{
+			Type:   corev1.PodConditionType(condition.Type),
+			Status: corev1.ConditionFalse,
+		}
+		if ok {
+			podCondition.Status = corev1.ConditionTrue
+		} else {
+			podCondition.Reason = condition.Reason
+			podCondition.Message = condition.Message
+		}
+
+		podCondition.LastTransitionTime = metav1.NewTime(now)
+		for _, existing := range pod.Status.Conditions {
+			if existing.Type == podCondition.Type && existing.Status == podCondition.Status {
+				podCondition.LastTransitionTime = existing.LastTransitionTime
+				break
+			}
+		}
+		result = append(result, podCondition)
+	}
+	return result, nil
+}
+
+// patch returns the patch to set the conditions in the status of the pod
+func (p *podConditions) patch(ctx context.Context, pod *corev1.Pod, conditions []internalversion.StageCondition, now time.Time) (*lifecycle.Patch, error) {
+	podConditions, err := p.evaluate(ctx, pod, conditions, now)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"status": map[string]any{
+			"conditions": podConditions,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &lifecycle.Patch{
+		Data:        data,
+		Type:        types.StrategicMergePatchType,
+		Subresource: "status",
+	}, nil
+}
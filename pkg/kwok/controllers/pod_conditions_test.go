@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+)
+
+func TestPodConditionsEvaluate(t *testing.T) {
+	p, err := newPodConditions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := metav1.NewTime(now.Add(-time.Hour))
+	conditions := []internalversion.StageCondition{
+		{
+			Type:       "Ready",
+			Expression: `pod.spec.containers.all(c, ("example.com/ready-" + c.name) in pod.metadata.annotations)`,
+			Reason:     "ContainersNotReady",
+			Message:    "not all containers are annotated as ready",
+		},
+		{
+			Type:       "Multi",
+			Expression: `size(pod.spec.containers) > 1`,
+		},
+	}
+	containers := []corev1.Container{
+		{Name: "app"},
+		{Name: "sidecar"},
+	}
+
+	tests := []struct {
+		name    string
+		pod     *corev1.Pod
+		want    []corev1.PodCondition
+		wantErr bool
+	}{
+		{
+			name: "all containers annotated",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"example.com/ready-app":     "",
+						"example.com/ready-sidecar": "",
+					},
+				},
+				Spec: corev1.PodSpec{Containers: containers},
+			},
+			want: []corev1.PodCondition{
+				{Type: "Ready", Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(now)},
+				{Type: "Multi", Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(now)},
+			},
+		},
+		{
+			name: "one container not annotated",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"example.com/ready-app": "",
+					},
+				},
+				Spec: corev1.PodSpec{Containers: containers},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{
+						{Type: "Ready", Status: corev1.ConditionFalse, LastTransitionTime: before},
+						{Type: "Multi", Status: corev1.ConditionFalse, LastTransitionTime: before},
+					},
+				},
+			},
+			want: []corev1.PodCondition{
+				{
+					Type:               "Ready",
+					Status:             corev1.ConditionFalse,
+					Reason:             "ContainersNotReady",
+					Message:            "not all containers are annotated as ready",
+					LastTransitionTime: before,
+				},
+				{Type: "Multi", Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(now)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.evaluate(context.Background(), tt.pod, conditions, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("evaluate() got %d conditions, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].Type != tt.want[i].Type ||
+					got[i].Status != tt.want[i].Status ||
+					got[i].Reason != tt.want[i].Reason ||
+					got[i].Message != tt.want[i].Message ||
+					!got[i].LastTransitionTime.Equal(&tt.want[i].LastTransitionTime) {
+					t.Errorf("evaluate() got %+v, want %+v", got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPodConditionsEvaluateError(t *testing.T) {
+	p, err := newPodConditions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expression := range []string{
+		`pod.metadata.name`,
+		`pod.spec.containers.all(`,
+	} {
+		_, err := p.evaluate(context.Background(), &corev1.Pod{}, []internalversion.StageCondition{
+			{Type: "Ready", Expression: expression},
+		}, time.Now())
+		if err == nil {
+			t.Errorf("evaluate(%q) want error", expression)
+		}
+	}
+}
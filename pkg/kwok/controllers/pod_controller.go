@@ -19,6 +19,10 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -60,15 +64,34 @@ type PodController struct {
 	renderer                              gotpl.Renderer
 	podsSets                              maps.SyncMap[log.ObjectRef, *PodInfo]
 	podsOnNode                            maps.SyncMap[string, *maps.SyncMap[log.ObjectRef, *PodInfo]]
-	preprocessChan                        chan *corev1.Pod
+	preprocessShards                      *preprocessShards[*corev1.Pod]
 	playStageParallelism                  uint
+	delayJitter                           time.Duration
+	playStageParallelismRamp              time.Duration
+	playStageParallelismRampJitter        time.Duration
+	statusUpdateSlots                     chan struct{}
 	lifecycle                             resources.Getter[lifecycle.Lifecycle]
 	delayQueue                            queue.WeightDelayingQueue[resourceStageJob[*corev1.Pod]]
 	backoff                               wait.Backoff
 	delayQueueMapping                     maps.SyncMap[string, resourceStageJob[*corev1.Pod]]
+	stageCounter                          *lifecycle.Counter
 	recorder                              record.EventRecorder
 	readOnlyFunc                          func(nodeName string) bool
 	enableMetrics                         bool
+	enablePodsOnNodeIndex                 bool
+	enableNodeVolumeStatus                bool
+	pvcCacheGetter                        informer.Getter[*corev1.PersistentVolumeClaim]
+	pvCacheGetter                         informer.Getter[*corev1.PersistentVolume]
+	disregardFinalizers                   bool
+	podAdmissionFailurePolicy             string
+	podAdmission                          podAdmission
+	rejectedPodsQueue                     queue.Queue[string]
+	nodeVolumes                           nodeVolumes
+	nodeVolumesQueue                      queue.DelayingQueue[string]
+	stageWebhookClient                    *http.Client
+	podConditions                         *podConditions
+
+	workers sync.WaitGroup
 }
 
 // PodInfo is the collection of necessary pod information
@@ -89,10 +112,22 @@ type PodControllerConfig struct {
 	NodeHasMetric                         func(nodeName string) bool
 	Lifecycle                             resources.Getter[lifecycle.Lifecycle]
 	PlayStageParallelism                  uint
+	PreprocessParallelism                 uint
+	DelayJitter                           time.Duration
+	PlayStageParallelismRamp              time.Duration
+	PlayStageParallelismRampJitter        time.Duration
+	StatusUpdateParallelism               uint
 	FuncMap                               gotpl.FuncMap
 	Recorder                              record.EventRecorder
 	ReadOnlyFunc                          func(nodeName string) bool
 	EnableMetrics                         bool
+	EnablePodsOnNodeIndex                 bool
+	EnableNodeVolumeStatus                bool
+	PVCCacheGetter                        informer.Getter[*corev1.PersistentVolumeClaim]
+	PVCacheGetter                         informer.Getter[*corev1.PersistentVolume]
+	DisregardFinalizers                   bool
+	PodAdmissionFailurePolicy             string
+	StageWebhookClient                    *http.Client
 }
 
 // NewPodController creates a new fake pods controller
@@ -101,6 +136,19 @@ func NewPodController(conf PodControllerConfig) (*PodController, error) {
 		return nil, fmt.Errorf("playStageParallelism must be greater than 0")
 	}
 
+	if conf.DelayJitter < 0 {
+		return nil, fmt.Errorf("delayJitter must not be negative")
+	}
+
+	if conf.EnableNodeVolumeStatus && (conf.PVCCacheGetter == nil || conf.PVCacheGetter == nil) {
+		return nil, fmt.Errorf("pvcCacheGetter and pvCacheGetter are required to report the volumes of nodes")
+	}
+
+	err := validatePodAdmissionFailurePolicy(conf.PodAdmissionFailurePolicy)
+	if err != nil {
+		return nil, err
+	}
+
 	disregardStatusWithAnnotationSelector, err := labelsParse(conf.DisregardStatusWithAnnotationSelector)
 	if err != nil {
 		return nil, err
@@ -127,34 +175,89 @@ func NewPodController(conf PodControllerConfig) (*PodController, error) {
 		nodeGetFunc:                           conf.NodeGetFunc,
 		delayQueue:                            queue.NewWeightDelayingQueue[resourceStageJob[*corev1.Pod]](conf.Clock),
 		backoff:                               defaultBackoff(),
+		stageCounter:                          lifecycle.NewCounter(),
 		lifecycle:                             conf.Lifecycle,
 		playStageParallelism:                  conf.PlayStageParallelism,
-		preprocessChan:                        make(chan *corev1.Pod),
+		delayJitter:                           conf.DelayJitter,
+		playStageParallelismRamp:              conf.PlayStageParallelismRamp,
+		playStageParallelismRampJitter:        conf.PlayStageParallelismRampJitter,
+		preprocessShards:                      newPreprocessShards[*corev1.Pod](conf.PreprocessParallelism),
 		recorder:                              conf.Recorder,
 		readOnlyFunc:                          conf.ReadOnlyFunc,
 		enableMetrics:                         conf.EnableMetrics,
+		enablePodsOnNodeIndex:                 conf.EnableMetrics || conf.EnablePodsOnNodeIndex,
+		enableNodeVolumeStatus:                conf.EnableNodeVolumeStatus,
+		pvcCacheGetter:                        conf.PVCCacheGetter,
+		pvCacheGetter:                         conf.PVCacheGetter,
+		disregardFinalizers:                   conf.DisregardFinalizers,
+		podAdmissionFailurePolicy:             conf.PodAdmissionFailurePolicy,
+		stageWebhookClient:                    conf.StageWebhookClient,
+	}
+	if c.podAdmissionFailurePolicy != "" && c.podAdmissionFailurePolicy != podAdmissionFailurePolicyIgnore {
+		c.rejectedPodsQueue = queue.NewQueue[string]()
 	}
-	funcMap := maps.Merge(gotpl.FuncMap{
+	if c.enableNodeVolumeStatus {
+		c.nodeVolumesQueue = queue.NewDelayingQueue[string](c.clock)
+	}
+	c.podConditions, err = newPodConditions()
+	if err != nil {
+		return nil, err
+	}
+	if conf.StatusUpdateParallelism > 0 {
+		c.statusUpdateSlots = make(chan struct{}, conf.StatusUpdateParallelism)
+	}
+	c.renderer = gotpl.NewRenderer(maps.Merge(c.funcMap(), conf.FuncMap))
+	return c, nil
+}
+
+// funcMap returns the functions provided by the controller for rendering the templates of stages
+func (c *PodController) funcMap() gotpl.FuncMap {
+	return gotpl.FuncMap{
 		"NodeIP":     c.funcNodeIP,
 		"PodIP":      c.funcPodIP,
 		"NodeIPWith": c.funcNodeIPWith,
 		"PodIPWith":  c.funcPodIPWith,
-	}, conf.FuncMap)
-	c.renderer = gotpl.NewRenderer(funcMap)
-	return c, nil
+	}
 }
 
 // Start starts the fake pod controller
 // It will modify the pods status to we want
 func (c *PodController) Start(ctx context.Context, events <-chan informer.Event[*corev1.Pod]) error {
-	go c.preprocessWorker(ctx)
+	for i := 0; i < c.preprocessShards.Len(); i++ {
+		preprocessChan := c.preprocessShards.Chan(i)
+		c.workers.Add(1)
+		go func() {
+			defer c.workers.Done()
+			c.preprocessWorker(ctx, preprocessChan)
+		}()
+	}
 	for i := uint(0); i < c.playStageParallelism; i++ {
-		go c.playStageWorker(ctx)
+		delay := c.playStageWorkerDelay(i)
+		c.workers.Add(1)
+		go func() {
+			defer c.workers.Done()
+			c.playStageWorker(ctx, delay)
+		}()
+	}
+	if c.rejectedPodsQueue != nil {
+		go c.requeueRejectedPodsWorker(ctx)
+	}
+	if c.nodeVolumesQueue != nil {
+		c.workers.Add(1)
+		go func() {
+			defer c.workers.Done()
+			c.nodeVolumesWorker(ctx)
+		}()
 	}
 	go c.watchResources(ctx, events)
 	return nil
 }
 
+// Wait waits for the workers to stop after the context passed to Start is done
+func (c *PodController) Wait() {
+	c.workers.Wait()
+}
+
 // deleteResource deletes a pod
 func (c *PodController) deleteResource(ctx context.Context, pod *corev1.Pod) error {
 	logger := log.FromContext(ctx)
@@ -163,7 +266,13 @@ func (c *PodController) deleteResource(ctx context.Context, pod *corev1.Pod) err
 		"node", pod.Spec.NodeName,
 	)
 
-	err := c.typedClient.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, deleteOpt)
+	release, err := c.acquireStatusUpdate(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	err = c.typedClient.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, deleteOpt)
 	if err != nil {
 		return err
 	}
@@ -173,16 +282,17 @@ func (c *PodController) deleteResource(ctx context.Context, pod *corev1.Pod) err
 }
 
 // preprocessWorker receives the resource from the preprocessChan and preprocess it
-func (c *PodController) preprocessWorker(ctx context.Context) {
+func (c *PodController) preprocessWorker(ctx context.Context, preprocessChan <-chan *corev1.Pod) {
 	logger := log.FromContext(ctx)
 	for {
 		select {
 		case <-ctx.Done():
 			logger.Debug("Stop preprocess worker")
 			return
-		case pod := <-c.preprocessChan:
+		case pod := <-preprocessChan:
 			err := c.preprocess(ctx, pod)
 			if err != nil {
+				recordReconcileError("pod", err)
 				logger.Error("Failed to preprocess node", err,
 					"pod", log.KObj(pod),
 					"node", pod.Spec.NodeName,
@@ -213,12 +323,25 @@ func (c *PodController) preprocess(ctx context.Context, pod *corev1.Pod) error {
 		}
 	}
 
+	if c.podAdmissionFailurePolicy != "" && c.podAdmissionFailurePolicy != podAdmissionFailurePolicyIgnore {
+		admitted, err := c.admitPod(ctx, pod)
+		if err != nil {
+			return fmt.Errorf("pod admission: %w", err)
+		}
+		if !admitted {
+			logger.Debug("Skip pod",
+				"reason", "rejected by admission",
+			)
+			return nil
+		}
+	}
+
 	data, err := expression.ToJSONStandard(pod)
 	if err != nil {
 		return err
 	}
 
-	lc := c.lifecycle.Get()
+	lc := c.stageCounter.Filter(pod.UID, c.lifecycle.Get())
 	stage, err := lc.Match(ctx, pod.Labels, pod.Annotations, data)
 	if err != nil {
 		return fmt.Errorf("stage match: %w", err)
@@ -232,6 +355,7 @@ func (c *PodController) preprocess(ctx context.Context, pod *corev1.Pod) error {
 
 	now := c.clock.Now()
 	delay, _ := stage.Delay(ctx, data, now)
+	delay = jitterDelay(delay, c.delayJitter)
 
 	if delay != 0 {
 		stageName := stage.Name()
@@ -253,10 +377,34 @@ func (c *PodController) preprocess(ctx context.Context, pod *corev1.Pod) error {
 	return nil
 }
 
+// playStageWorkerDelay returns the delay before the i-th playStageWorker starts,
+// the workers are spread evenly over the ramp window with an additional random jitter.
+func (c *PodController) playStageWorkerDelay(i uint) time.Duration {
+	var delay time.Duration
+	if c.playStageParallelismRamp > 0 {
+		delay = c.playStageParallelismRamp * time.Duration(i) / time.Duration(c.playStageParallelism)
+	}
+	if c.playStageParallelismRampJitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(c.playStageParallelismRampJitter)))
+	}
+	return delay
+}
+
 // playStageWorker receives the resource from the playStageChan and play the stage
-func (c *PodController) playStageWorker(ctx context.Context) {
+func (c *PodController) playStageWorker(ctx context.Context, delay time.Duration) {
 	logger := log.FromContext(ctx)
 
+	if delay > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.clock.After(delay):
+		}
+	}
+
+	podPlayStageActiveWorkers.Inc()
+	defer podPlayStageActiveWorkers.Dec()
+
 	for ctx.Err() == nil {
 		pod, ok := c.delayQueue.GetOrWaitWithDone(ctx.Done())
 		if !ok {
@@ -265,6 +413,7 @@ func (c *PodController) playStageWorker(ctx context.Context) {
 		c.delayQueueMapping.Delete(pod.Key)
 		needRetry, err := c.playStage(ctx, pod.Resource, pod.Stage)
 		if err != nil {
+			recordReconcileError("pod", err)
 			logger.Error("failed to apply stage", err,
 				"pod", pod.Key,
 				"stage", pod.Stage.Name(),
@@ -287,7 +436,7 @@ func (c *PodController) playStageWorker(ctx context.Context) {
 
 // playStage plays the stage.
 // The returned boolean indicates whether the applying action needs to be retried.
-func (c *PodController) playStage(ctx context.Context, pod *corev1.Pod, stage *lifecycle.Stage) (bool, error) {
+func (c *PodController) playStage(ctx context.Context, pod *corev1.Pod, stage *lifecycle.Stage) (needRetry bool, err error) {
 	next := stage.Next()
 	logger := log.FromContext(ctx)
 	logger = logger.With(
@@ -296,10 +445,19 @@ func (c *PodController) playStage(ctx context.Context, pod *corev1.Pod, stage *l
 		"stage", stage.Name(),
 	)
 
-	var (
-		result *corev1.Pod
-		err    error
-	)
+	var result *corev1.Pod
+
+	if next.Delete() && !c.disregardFinalizers {
+		if pending := pendingFinalizers(pod.Finalizers); len(pending) != 0 {
+			logger.Debug("Skip pod",
+				"reason", "waiting for finalizers to be removed",
+				"finalizers", pending,
+			)
+			return false, nil
+		}
+	}
+
+	defer c.stageCounter.Track(pod.UID, stage, &err)()
 
 	if event := next.Event(); event != nil && c.recorder != nil {
 		c.recorder.Event(&corev1.ObjectReference{
@@ -349,12 +507,70 @@ func (c *PodController) playStage(ctx context.Context, pod *corev1.Pod, stage *l
 				}
 			}
 		}
+
+		current := pod
+		if result != nil {
+			current = result
+		}
+		var conditionsPatch *lifecycle.Patch
+		if conditions := next.Conditions(); len(conditions) != 0 {
+			conditionsPatch, err = c.podConditions.patch(ctx, current, conditions, c.clock.Now())
+			if err != nil {
+				return false, fmt.Errorf("failed to compute conditions for pod %s: %w", pod.Name, err)
+			}
+		}
+		statusMessagePatch, err := next.StatusMessage(current, c.renderer)
+		if err != nil {
+			return false, fmt.Errorf("failed to get status message for pod %s: %w", pod.Name, err)
+		}
+		startTimePatch, err := next.StartTime(ctx, current, c.clock.Now())
+		if err != nil {
+			return false, fmt.Errorf("failed to get start time for pod %s: %w", pod.Name, err)
+		}
+		patch, err := lifecycle.MergeStatusPatches(conditionsPatch, statusMessagePatch, startTimePatch)
+		if err != nil {
+			return false, fmt.Errorf("failed to merge status patches for pod %s: %w", pod.Name, err)
+		}
+		if patch != nil {
+			changed, err := checkNeedPatchWithTyped(current, patch.Data, patch.Type)
+			if err != nil {
+				return false, fmt.Errorf("failed to check need patch for pod %s: %w", pod.Name, err)
+			}
+			if changed {
+				result, err = c.patchResource(ctx, pod, patch)
+				if err != nil {
+					return shouldRetry(err), fmt.Errorf("failed to patch status of pod %s: %w", pod.Name, err)
+				}
+			}
+		}
+
+		if next.HasWebhook() {
+			if c.stageWebhookClient == nil {
+				logger.Warn("Skip webhook",
+					"reason", "stage webhook is disabled",
+				)
+			} else {
+				if result != nil {
+					current = result
+				}
+				patch, err := next.Webhook(ctx, current, c.renderer, c.stageWebhookClient)
+				if err != nil {
+					return false, fmt.Errorf("failed to call webhook for pod %s: %w", pod.Name, err)
+				}
+				if patch != nil {
+					result, err = c.patchResource(ctx, pod, patch)
+					if err != nil {
+						return shouldRetry(err), fmt.Errorf("failed to patch pod %s: %w", pod.Name, err)
+					}
+				}
+			}
+		}
 	}
 
 	if result != nil && stage.ImmediateNextStage() {
 		logger.Debug("Re-push to preprocessChan",
 			"reason", "immediateNextStage is true")
-		c.preprocessChan <- result
+		c.preprocessShards.Push(log.KObj(result).String(), result)
 	}
 	return false, nil
 }
@@ -381,6 +597,12 @@ func (c *PodController) patchResource(ctx context.Context, pod *corev1.Pod, patc
 		)
 		subresource = []string{patch.Subresource}
 	}
+	release, err := c.acquireStatusUpdate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	result, err := c.typedClient.CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, patch.Type, patch.Data, metav1.PatchOptions{}, subresource...)
 	if err != nil {
 		return nil, err
@@ -389,6 +611,24 @@ func (c *PodController) patchResource(ctx context.Context, pod *corev1.Pod, patc
 	return result, nil
 }
 
+// acquireStatusUpdate waits for a free slot to send an update of the pod to the apiserver,
+// the returned function must be called to release the slot once the update is done.
+func (c *PodController) acquireStatusUpdate(ctx context.Context) (func(), error) {
+	if c.statusUpdateSlots == nil {
+		return func() {}, nil
+	}
+	select {
+	case c.statusUpdateSlots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	podStatusUpdateActiveWorkers.Inc()
+	return func() {
+		podStatusUpdateActiveWorkers.Dec()
+		<-c.statusUpdateSlots
+	}, nil
+}
+
 func (c *PodController) need(pod *corev1.Pod) bool {
 	if _, has := c.nodeGetFunc(pod.Spec.NodeName); !has {
 		return false
@@ -422,7 +662,7 @@ loop:
 			switch event.Type {
 			case informer.Added, informer.Modified, informer.Sync:
 				pod := event.Object
-				if c.enableMetrics {
+				if c.enablePodsOnNodeIndex {
 					c.putPodInfo(pod)
 				}
 				if c.need(pod) {
@@ -434,7 +674,10 @@ loop:
 							"node", pod.Spec.NodeName,
 						)
 					} else {
-						c.preprocessChan <- pod.DeepCopy()
+						c.preprocessShards.Push(log.KObj(pod).String(), pod.DeepCopy())
+						if c.enableNodeVolumeStatus {
+							c.syncNodeVolumes(pod)
+						}
 					}
 				} else {
 					logger.Debug("Skip pod",
@@ -455,13 +698,22 @@ loop:
 				}
 			case informer.Deleted:
 				pod := event.Object
-				if c.enableMetrics {
+				if c.enablePodsOnNodeIndex {
 					c.deletePodInfo(pod)
 				}
 				if c.need(pod) {
 					// Recycling PodIP
 					c.recyclingPodIP(ctx, pod)
 
+					c.podAdmission.release(pod.Spec.NodeName, log.KObj(pod))
+					c.RequeueRejectedPods(pod.Spec.NodeName)
+
+					if c.enableNodeVolumeStatus {
+						c.deleteNodeVolumes(pod)
+					}
+
+					c.stageCounter.Forget(pod.UID)
+
 					// Cancel delay job
 					key := log.KObj(pod).String()
 					resourceJob, ok := c.delayQueueMapping.LoadAndDelete(key)
@@ -647,13 +899,20 @@ func (c *PodController) Get(namespace, name string) (*PodInfo, bool) {
 	return podInfo, true
 }
 
-// List lists pod info
+// List lists pod info, sorted by namespace and name
 func (c *PodController) List(nodeName string) ([]log.ObjectRef, bool) {
 	m, ok := c.podsOnNode.Load(nodeName)
 	if !ok {
 		return nil, false
 	}
-	return m.Keys(), true
+	refs := m.Keys()
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Namespace != refs[j].Namespace {
+			return refs[i].Namespace < refs[j].Namespace
+		}
+		return refs[i].Name < refs[j].Name
+	})
+	return refs, true
 }
 
 // addStageJob adds a stage to be applied into the underlying weight delay queue and the associated helper map
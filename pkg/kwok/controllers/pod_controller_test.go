@@ -18,9 +18,15 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -28,13 +34,20 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	clocktesting "k8s.io/utils/clock/testing"
 
 	podfast "sigs.k8s.io/kwok/kustomize/stage/pod/fast"
+	podgeneral "sigs.k8s.io/kwok/kustomize/stage/pod/general"
 	"sigs.k8s.io/kwok/pkg/apis/internalversion"
 	"sigs.k8s.io/kwok/pkg/config"
 	"sigs.k8s.io/kwok/pkg/config/resources"
 	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/expression"
+	"sigs.k8s.io/kwok/pkg/utils/format"
 	"sigs.k8s.io/kwok/pkg/utils/informer"
 	"sigs.k8s.io/kwok/pkg/utils/lifecycle"
 	"sigs.k8s.io/kwok/pkg/utils/slices"
@@ -370,3 +383,365 @@ func TestPodController(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestPodControllerPlayStageWorkerDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		ramp    time.Duration
+		jitter  time.Duration
+		workers uint
+		want    []time.Duration
+	}{
+		{
+			name:    "no ramp",
+			workers: 3,
+			want:    []time.Duration{0, 0, 0},
+		},
+		{
+			name:    "ramp",
+			ramp:    4 * time.Second,
+			workers: 4,
+			want:    []time.Duration{0, time.Second, 2 * time.Second, 3 * time.Second},
+		},
+		{
+			name:    "ramp with jitter",
+			ramp:    4 * time.Second,
+			jitter:  time.Second,
+			workers: 4,
+			want:    []time.Duration{0, time.Second, 2 * time.Second, 3 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &PodController{
+				playStageParallelism:           tt.workers,
+				playStageParallelismRamp:       tt.ramp,
+				playStageParallelismRampJitter: tt.jitter,
+			}
+			for i := uint(0); i < tt.workers; i++ {
+				got := c.playStageWorkerDelay(i)
+				if got < tt.want[i] || got > tt.want[i]+tt.jitter {
+					t.Errorf("playStageWorkerDelay(%d) = %v, want in [%v, %v]", i, got, tt.want[i], tt.want[i]+tt.jitter)
+				}
+			}
+		})
+	}
+}
+
+func TestPodGeneralDeleteStage(t *testing.T) {
+	stage, err := config.UnmarshalWithType[*internalversion.Stage](podgeneral.DefaultPodDelete)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lc, err := lifecycle.NewLifecycle([]*internalversion.Stage{stage})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock := clocktesting.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name               string
+		gracePeriodSeconds *int64
+		want               time.Duration
+	}{
+		{
+			name: "default grace period",
+			want: 30 * time.Second,
+		},
+		{
+			name:               "custom grace period",
+			gracePeriodSeconds: format.Ptr[int64](5),
+			want:               5 * time.Second,
+		},
+		{
+			name:               "force delete",
+			gracePeriodSeconds: format.Ptr[int64](0),
+			want:               0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gracePeriodSeconds := int64(corev1.DefaultTerminationGracePeriodSeconds)
+			if tt.gracePeriodSeconds != nil {
+				gracePeriodSeconds = *tt.gracePeriodSeconds
+			}
+			// Like the apiserver, the deletion timestamp is the time of deletion plus the grace period.
+			deletionTimestamp := metav1.NewTime(clock.Now().Add(time.Duration(gracePeriodSeconds) * time.Second))
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:                       "pod",
+					Namespace:                  "default",
+					DeletionTimestamp:          &deletionTimestamp,
+					DeletionGracePeriodSeconds: &gracePeriodSeconds,
+				},
+				Spec: corev1.PodSpec{
+					NodeName:                      "node",
+					TerminationGracePeriodSeconds: tt.gracePeriodSeconds,
+				},
+			}
+
+			data, err := expression.ToJSONStandard(pod)
+			if err != nil {
+				t.Fatal(err)
+			}
+			matched, err := lc.Match(context.Background(), pod.Labels, pod.Annotations, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if matched == nil || matched.Name() != "pod-delete" {
+				t.Fatalf("want stage pod-delete, got %v", matched)
+			}
+
+			delay, _ := matched.Delay(context.Background(), data, clock.Now())
+			if delay != tt.want {
+				t.Errorf("want delay %s, got %s", tt.want, delay)
+			}
+			if !matched.Next().Delete() {
+				t.Errorf("want pod to be deleted")
+			}
+		})
+	}
+}
+
+func TestPodControllerFinalizers(t *testing.T) {
+	stage, err := config.UnmarshalWithType[*internalversion.Stage](podfast.DefaultPodDelete)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lc, err := lifecycle.NewLifecycle([]*internalversion.Stage{stage})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                string
+		finalizers          []string
+		disregardFinalizers bool
+		wantDeleted         bool
+	}{
+		{
+			name:        "no finalizers",
+			wantDeleted: true,
+		},
+		{
+			name:        "kwok finalizers",
+			finalizers:  []string{"kwok.x-k8s.io/fake"},
+			wantDeleted: true,
+		},
+		{
+			name:        "finalizers of other controllers",
+			finalizers:  []string{"kwok.x-k8s.io/fake", "example.com/protect"},
+			wantDeleted: false,
+		},
+		{
+			name:                "disregard finalizers",
+			finalizers:          []string{"example.com/protect"},
+			disregardFinalizers: true,
+			wantDeleted:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			now := metav1.Now()
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "pod",
+					Namespace:         "default",
+					DeletionTimestamp: &now,
+					Finalizers:        tt.finalizers,
+				},
+				Spec: corev1.PodSpec{
+					NodeName: "node",
+				},
+			}
+			clientset := fake.NewSimpleClientset(pod.DeepCopy())
+			c := &PodController{
+				typedClient:         clientset,
+				disregardFinalizers: tt.disregardFinalizers,
+			}
+
+			data, err := expression.ToJSONStandard(pod)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, err := lc.Match(ctx, pod.Labels, pod.Annotations, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s == nil {
+				t.Fatal("want pod-delete stage to match")
+			}
+
+			_, err = c.playStage(ctx, pod, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+			if tt.wantDeleted {
+				if err == nil {
+					t.Fatalf("want pod deleted, got finalizers %v", got.Finalizers)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("want pod to be kept until the finalizers are removed: %v", err)
+			}
+			if !slices.Equal(got.Finalizers, tt.finalizers) {
+				t.Fatalf("want finalizers %v, got %v", tt.finalizers, got.Finalizers)
+			}
+
+			// The pod is deleted once the other controllers remove their finalizers.
+			pod.Finalizers = nil
+			_, err = c.playStage(ctx, pod, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+			if err == nil {
+				t.Fatal("want pod deleted after the finalizers are removed")
+			}
+		})
+	}
+}
+
+func TestPodControllerStatusUpdateParallelism(t *testing.T) {
+	const (
+		parallelism = 2
+		updates     = 10
+	)
+
+	// The fake clientset serializes the requests, so the patches are sent to a server
+	// which holds each of them until it is unblocked.
+	var inflight, maxInflight int32
+	entered := make(chan struct{}, updates)
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inflight, 1)
+		for {
+			m := atomic.LoadInt32(&maxInflight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInflight, m, n) {
+				break
+			}
+		}
+		entered <- struct{}{}
+		<-unblock
+		atomic.AddInt32(&inflight, -1)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"},
+		})
+	}))
+	defer server.Close()
+
+	typedClient, err := kubernetes.NewForConfig(&rest.Config{
+		Host: server.URL,
+		QPS:  -1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewPodController(PodControllerConfig{
+		TypedClient:             typedClient,
+		PlayStageParallelism:    updates,
+		StatusUpdateParallelism: parallelism,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"},
+	}
+	patch := &lifecycle.Patch{
+		Data:        []byte(`{"status":{"phase":"Running"}}`),
+		Type:        types.MergePatchType,
+		Subresource: "status",
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < updates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.patchResource(ctx, pod, patch)
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	// Once the slots are filled, the other patches wait until the held ones are unblocked.
+	for i := 0; i < parallelism; i++ {
+		<-entered
+	}
+	select {
+	case <-entered:
+		t.Errorf("want no patch sent while the slots are held")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(unblock)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&maxInflight); got > parallelism {
+		t.Errorf("want at most %d patches in parallel, got %d", parallelism, got)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	for i := 0; i < parallelism; i++ {
+		release, err := c.acquireStatusUpdate(canceled)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer release()
+	}
+	cancel()
+	_, err = c.acquireStatusUpdate(canceled)
+	if err == nil {
+		t.Errorf("want error when waiting for a slot with a canceled context")
+	}
+}
+
+func TestPodControllerList(t *testing.T) {
+	c := &PodController{}
+	for _, ref := range []log.ObjectRef{
+		{Namespace: "ns-b", Name: "pod-a"},
+		{Namespace: "ns-a", Name: "pod-c"},
+		{Namespace: "ns-a", Name: "pod-a"},
+		{Namespace: "ns-b", Name: "pod-b"},
+	} {
+		c.putPodInfo(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ref.Name,
+				Namespace: ref.Namespace,
+			},
+			Spec: corev1.PodSpec{
+				NodeName: "node",
+			},
+		})
+	}
+
+	want := []log.ObjectRef{
+		{Namespace: "ns-a", Name: "pod-a"},
+		{Namespace: "ns-a", Name: "pod-c"},
+		{Namespace: "ns-b", Name: "pod-a"},
+		{Namespace: "ns-b", Name: "pod-b"},
+	}
+	for i := 0; i != 10; i++ {
+		got, ok := c.List("node")
+		if !ok {
+			t.Fatalf("List() not found")
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("List() = %v, want %v", got, want)
+		}
+	}
+
+	_, ok := c.List("other")
+	if ok {
+		t.Fatalf("List() of node without pods want not found")
+	}
+}
@@ -0,0 +1,343 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/kwok/pkg/log"
+)
+
+// nodeVolumes tracks the volumes used by the pods on each node
+type nodeVolumes struct {
+	mut   sync.Mutex
+	nodes map[string]map[log.ObjectRef][]corev1.UniqueVolumeName
+	// patched is the volumes of each node in its last successful patch
+	patched map[string][]corev1.UniqueVolumeName
+	// retries is the number of failed patches of each node since its last successful patch
+	retries map[string]uint64
+	// unbound is the pods with claims that are not bound to volumes yet
+	unbound map[log.ObjectRef]*corev1.Pod
+}
+
+// set sets the volumes used by the pod on the node,
+// and returns true if the volumes used by all pods on the node differ from the patched ones.
+func (n *nodeVolumes) set(nodeName string, pod log.ObjectRef, volumes []corev1.UniqueVolumeName) bool {
+	n.mut.Lock()
+	defer n.mut.Unlock()
+
+	pods := n.nodes[nodeName]
+	if len(volumes) == 0 {
+		if pods == nil {
+			return false
+		}
+		delete(pods, pod)
+		if len(pods) == 0 {
+			delete(n.nodes, nodeName)
+		}
+	} else {
+		if pods == nil {
+			if n.nodes == nil {
+				n.nodes = map[string]map[log.ObjectRef][]corev1.UniqueVolumeName{}
+			}
+			pods = map[log.ObjectRef][]corev1.UniqueVolumeName{}
+			n.nodes[nodeName] = pods
+		}
+		pods[pod] = volumes
+	}
+
+	return !equalVolumeNames(n.listLocked(nodeName), n.patched[nodeName])
+}
+
+// get returns the volumes used by the pod on the node
+func (n *nodeVolumes) get(nodeName string, pod log.ObjectRef) ([]corev1.UniqueVolumeName, bool) {
+	n.mut.Lock()
+	defer n.mut.Unlock()
+	volumes, ok := n.nodes[nodeName][pod]
+	return volumes, ok
+}
+
+// pending returns the volumes used by all pods on the node if they differ from the patched ones
+func (n *nodeVolumes) pending(nodeName string) ([]corev1.UniqueVolumeName, bool) {
+	n.mut.Lock()
+	defer n.mut.Unlock()
+	volumes := n.listLocked(nodeName)
+	if equalVolumeNames(volumes, n.patched[nodeName]) {
+		return nil, false
+	}
+	return volumes, true
+}
+
+// commit records the volumes of the node after they are patched successfully
+func (n *nodeVolumes) commit(nodeName string, volumes []corev1.UniqueVolumeName) {
+	n.mut.Lock()
+	defer n.mut.Unlock()
+	delete(n.retries, nodeName)
+	if len(volumes) == 0 {
+		delete(n.patched, nodeName)
+		return
+	}
+	if n.patched == nil {
+		n.patched = map[string][]corev1.UniqueVolumeName{}
+	}
+	n.patched[nodeName] = volumes
+}
+
+// fail records a failed patch of the node and returns the number of failed patches before it
+func (n *nodeVolumes) fail(nodeName string) uint64 {
+	n.mut.Lock()
+	defer n.mut.Unlock()
+	if n.retries == nil {
+		n.retries = map[string]uint64{}
+	}
+	retries := n.retries[nodeName]
+	n.retries[nodeName] = retries + 1
+	return retries
+}
+
+// setUnbound sets whether the pod has claims that are not bound to volumes yet
+func (n *nodeVolumes) setUnbound(key log.ObjectRef, pod *corev1.Pod, unbound bool) {
+	n.mut.Lock()
+	defer n.mut.Unlock()
+	if !unbound {
+		delete(n.unbound, key)
+		return
+	}
+	if n.unbound == nil {
+		n.unbound = map[log.ObjectRef]*corev1.Pod{}
+	}
+	n.unbound[key] = pod
+}
+
+// listUnbound returns the pods with claims that are not bound to volumes yet
+func (n *nodeVolumes) listUnbound() []*corev1.Pod {
+	n.mut.Lock()
+	defer n.mut.Unlock()
+	pods := make([]*corev1.Pod, 0, len(n.unbound))
+	for _, pod := range n.unbound {
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
+func (n *nodeVolumes) listLocked(nodeName string) []corev1.UniqueVolumeName {
+	pods := n.nodes[nodeName]
+	if len(pods) == 0 {
+		return nil
+	}
+	set := map[corev1.UniqueVolumeName]struct{}{}
+	for _, volumes := range pods {
+		for _, volume := range volumes {
+			set[volume] = struct{}{}
+		}
+	}
+	out := make([]corev1.UniqueVolumeName, 0, len(set))
+	for volume := range set {
+		out = append(out, volume)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i] < out[j]
+	})
+	return out
+}
+
+func equalVolumeNames(a, b []corev1.UniqueVolumeName) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// syncNodeVolumes tracks the volumes used by the pod and queues its node to be patched if they are changed
+func (c *PodController) syncNodeVolumes(pod *corev1.Pod) {
+	if pod.Spec.NodeName == "" {
+		return
+	}
+
+	key := log.KObj(pod)
+	var volumes []corev1.UniqueVolumeName
+	if pod.DeletionTimestamp == nil &&
+		pod.Status.Phase != corev1.PodSucceeded &&
+		pod.Status.Phase != corev1.PodFailed {
+		claims := podVolumeClaims(pod)
+		if len(claims) == 0 {
+			return
+		}
+
+		// The volumes of the pod are immutable, so they are resolved only once all claims are bound.
+		if tracked, ok := c.nodeVolumes.get(pod.Spec.NodeName, key); ok && len(tracked) == len(claims) {
+			return
+		}
+
+		volumes = c.resolveVolumeClaims(pod.Namespace, claims)
+		c.nodeVolumes.setUnbound(key, pod, len(volumes) != len(claims))
+	} else {
+		c.nodeVolumes.setUnbound(key, nil, false)
+	}
+
+	if c.nodeVolumes.set(pod.Spec.NodeName, key, volumes) {
+		c.nodeVolumesQueue.Add(pod.Spec.NodeName)
+	}
+}
+
+// deleteNodeVolumes untracks the volumes used by the pod and queues its node to be patched if they are changed
+func (c *PodController) deleteNodeVolumes(pod *corev1.Pod) {
+	if pod.Spec.NodeName == "" {
+		return
+	}
+	key := log.KObj(pod)
+	c.nodeVolumes.setUnbound(key, nil, false)
+	if c.nodeVolumes.set(pod.Spec.NodeName, key, nil) {
+		c.nodeVolumesQueue.Add(pod.Spec.NodeName)
+	}
+}
+
+// ResyncNodeVolumes resolves the volumes of the pods with unbound claims again,
+// it is called when a persistent volume claim or a persistent volume is updated.
+func (c *PodController) ResyncNodeVolumes() {
+	if !c.enableNodeVolumeStatus {
+		return
+	}
+	for _, pod := range c.nodeVolumes.listUnbound() {
+		c.syncNodeVolumes(pod)
+	}
+}
+
+// nodeVolumesWorker patches the volumes of the queued nodes, and retries the failed ones with backoff
+func (c *PodController) nodeVolumesWorker(ctx context.Context) {
+	logger := log.FromContext(ctx)
+	for ctx.Err() == nil {
+		nodeName, ok := c.nodeVolumesQueue.GetOrWaitWithDone(ctx.Done())
+		if !ok {
+			return
+		}
+		err := c.patchPendingNodeVolumes(ctx, nodeName)
+		if err != nil {
+			retryCount := c.nodeVolumes.fail(nodeName)
+			logger.Error("Failed to sync volumes of node", err,
+				"node", nodeName,
+				"retry", retryCount,
+			)
+			c.nodeVolumesQueue.AddAfter(nodeName, backoffDelayByStep(retryCount, c.backoff))
+		}
+	}
+}
+
+// patchPendingNodeVolumes patches the volumes of the node if they differ from the patched ones
+func (c *PodController) patchPendingNodeVolumes(ctx context.Context, nodeName string) error {
+	volumes, changed := c.nodeVolumes.pending(nodeName)
+	if !changed {
+		return nil
+	}
+	err := c.patchNodeVolumes(ctx, nodeName, volumes)
+	if err != nil {
+		return err
+	}
+	c.nodeVolumes.commit(nodeName, volumes)
+	return nil
+}
+
+// resolveVolumeClaims returns the unique names of the volumes bound to the claims from the cache,
+// the claims that are not bound yet are skipped.
+func (c *PodController) resolveVolumeClaims(namespace string, claims []string) []corev1.UniqueVolumeName {
+	volumes := make([]corev1.UniqueVolumeName, 0, len(claims))
+	for _, claim := range claims {
+		pvc, ok := c.pvcCacheGetter.GetWithNamespace(claim, namespace)
+		if !ok || pvc.Spec.VolumeName == "" {
+			continue
+		}
+
+		pv, ok := c.pvCacheGetter.Get(pvc.Spec.VolumeName)
+		if !ok {
+			continue
+		}
+		volumes = append(volumes, uniqueVolumeName(pv))
+	}
+	return volumes
+}
+
+// patchNodeVolumes replaces the volumesInUse and volumesAttached of the node
+func (c *PodController) patchNodeVolumes(ctx context.Context, nodeName string, volumes []corev1.UniqueVolumeName) error {
+	var attached []corev1.AttachedVolume
+	if len(volumes) != 0 {
+		attached = make([]corev1.AttachedVolume, 0, len(volumes))
+		for _, volume := range volumes {
+			attached = append(attached, corev1.AttachedVolume{
+				Name: volume,
+			})
+		}
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"status": map[string]any{
+			"volumesInUse":    volumes,
+			"volumesAttached": attached,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.typedClient.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+	if err != nil {
+		return fmt.Errorf("failed to patch volumes of node %s: %w", nodeName, err)
+	}
+
+	logger := log.FromContext(ctx)
+	logger.Debug("Patch volumes of node",
+		"node", nodeName,
+		"volumes", volumes,
+	)
+	return nil
+}
+
+// podVolumeClaims returns the names of the persistent volume claims used by the pod,
+// including the claims created for the generic ephemeral volumes.
+func podVolumeClaims(pod *corev1.Pod) []string {
+	var claims []string
+	for _, volume := range pod.Spec.Volumes {
+		switch {
+		case volume.PersistentVolumeClaim != nil:
+			claims = append(claims, volume.PersistentVolumeClaim.ClaimName)
+		case volume.Ephemeral != nil:
+			claims = append(claims, pod.Name+"-"+volume.Name)
+		}
+	}
+	return claims
+}
+
+// uniqueVolumeName returns the unique name of the persistent volume like the kubelet does,
+// the CSI volumes are named after the driver and the volume handle.
+func uniqueVolumeName(pv *corev1.PersistentVolume) corev1.UniqueVolumeName {
+	if pv.Spec.CSI != nil {
+		return corev1.UniqueVolumeName("kubernetes.io/csi/" + pv.Spec.CSI.Driver + "^" + pv.Spec.CSI.VolumeHandle)
+	}
+	return corev1.UniqueVolumeName("kubernetes.io/" + pv.Name)
+}
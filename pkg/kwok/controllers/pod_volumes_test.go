@@ -0,0 +1,279 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/clock"
+
+	"sigs.k8s.io/kwok/pkg/utils/informer"
+	"sigs.k8s.io/kwok/pkg/utils/queue"
+	"sigs.k8s.io/kwok/pkg/utils/wait"
+)
+
+func TestPodControllerNodeVolumes(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewSimpleClientset(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node0",
+			},
+		},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "data",
+				Namespace: "default",
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				VolumeName: "pv-csi",
+			},
+		},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod1-cache",
+				Namespace: "default",
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				VolumeName: "pv-local",
+			},
+		},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "unbound",
+				Namespace: "default",
+			},
+		},
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pv-csi",
+			},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{
+						Driver:       "csi.example.com",
+						VolumeHandle: "vol-1",
+					},
+				},
+			},
+		},
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pv-local",
+			},
+		},
+	)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	pvcsInformer := informer.NewInformer[*corev1.PersistentVolumeClaim, *corev1.PersistentVolumeClaimList](clientset.CoreV1().PersistentVolumeClaims(corev1.NamespaceAll))
+	pvcCache, err := pvcsInformer.WatchWithCache(ctx, informer.Option{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pvsInformer := informer.NewInformer[*corev1.PersistentVolume, *corev1.PersistentVolumeList](clientset.CoreV1().PersistentVolumes())
+	pvCache, err := pvsInformer.WatchWithCache(ctx, informer.Option{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = wait.Poll(ctx, func(ctx context.Context) (bool, error) {
+		return len(pvcCache.List()) == 3 && len(pvCache.List()) == 2, nil
+	}, wait.WithTimeout(10*time.Second), wait.WithInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var failPatch bool
+	clientset.PrependReactor("patch", "nodes", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if failPatch {
+			return true, nil, errors.New("injected")
+		}
+		return false, nil, nil
+	})
+
+	c := &PodController{
+		typedClient:            clientset,
+		enableNodeVolumeStatus: true,
+		pvcCacheGetter:         pvcCache,
+		pvCacheGetter:          pvCache,
+		nodeVolumesQueue:       queue.NewDelayingQueue[string](clock.RealClock{}),
+	}
+
+	newPod := func(name string, volumes ...corev1.Volume) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: corev1.PodSpec{
+				NodeName: "node0",
+				Volumes:  volumes,
+			},
+		}
+	}
+	claimVolume := func(name, claim string) corev1.Volume {
+		return corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: claim,
+				},
+			},
+		}
+	}
+
+	pod0 := newPod("pod0", claimVolume("data", "data"), claimVolume("pending", "unbound"))
+	pod1 := newPod("pod1",
+		claimVolume("data", "data"),
+		corev1.Volume{
+			Name: "cache",
+			VolumeSource: corev1.VolumeSource{
+				Ephemeral: &corev1.EphemeralVolumeSource{},
+			},
+		},
+	)
+	pod2 := newPod("pod2", corev1.Volume{
+		Name: "empty",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+
+	steps := []struct {
+		name      string
+		do        func()
+		failPatch bool
+		want      []corev1.UniqueVolumeName
+	}{
+		{
+			name: "pod with claims",
+			do: func() {
+				c.syncNodeVolumes(pod0)
+			},
+			want: []corev1.UniqueVolumeName{
+				"kubernetes.io/csi/csi.example.com^vol-1",
+			},
+		},
+		{
+			name: "pod with shared and ephemeral claims",
+			do: func() {
+				c.syncNodeVolumes(pod1)
+			},
+			want: []corev1.UniqueVolumeName{
+				"kubernetes.io/csi/csi.example.com^vol-1",
+				"kubernetes.io/pv-local",
+			},
+		},
+		{
+			name: "pod without claims",
+			do: func() {
+				c.syncNodeVolumes(pod2)
+			},
+			want: []corev1.UniqueVolumeName{
+				"kubernetes.io/csi/csi.example.com^vol-1",
+				"kubernetes.io/pv-local",
+			},
+		},
+		{
+			name: "delete pod with ephemeral claim and fail to patch",
+			do: func() {
+				c.deleteNodeVolumes(pod1)
+			},
+			failPatch: true,
+			want: []corev1.UniqueVolumeName{
+				"kubernetes.io/csi/csi.example.com^vol-1",
+				"kubernetes.io/pv-local",
+			},
+		},
+		{
+			name: "retry the failed patch",
+			do:   func() {},
+			want: []corev1.UniqueVolumeName{
+				"kubernetes.io/csi/csi.example.com^vol-1",
+			},
+		},
+		{
+			name: "claim bound after the pod",
+			do: func() {
+				pvc, err := clientset.CoreV1().PersistentVolumeClaims("default").Get(ctx, "unbound", metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				pvc.Spec.VolumeName = "pv-local"
+				_, err = clientset.CoreV1().PersistentVolumeClaims("default").Update(ctx, pvc, metav1.UpdateOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = wait.Poll(ctx, func(ctx context.Context) (bool, error) {
+					pvc, ok := pvcCache.GetWithNamespace("unbound", "default")
+					return ok && pvc.Spec.VolumeName != "", nil
+				}, wait.WithTimeout(10*time.Second), wait.WithInterval(10*time.Millisecond))
+				if err != nil {
+					t.Fatal(err)
+				}
+				c.ResyncNodeVolumes()
+			},
+			want: []corev1.UniqueVolumeName{
+				"kubernetes.io/csi/csi.example.com^vol-1",
+				"kubernetes.io/pv-local",
+			},
+		},
+		{
+			name: "pod succeeded",
+			do: func() {
+				pod := pod0.DeepCopy()
+				pod.Status.Phase = corev1.PodSucceeded
+				c.syncNodeVolumes(pod)
+			},
+			want: nil,
+		},
+	}
+	for _, step := range steps {
+		step.do()
+
+		failPatch = step.failPatch
+		err := c.patchPendingNodeVolumes(ctx, "node0")
+		failPatch = false
+		if step.failPatch {
+			if err == nil {
+				t.Fatalf("%s: want error, got nil", step.name)
+			}
+		} else if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		node, err := clientset.CoreV1().Nodes().Get(ctx, "node0", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(node.Status.VolumesInUse, step.want) {
+			t.Errorf("%s: want volumesInUse %v, got %v", step.name, step.want, node.Status.VolumesInUse)
+		}
+		if len(node.Status.VolumesAttached) != len(step.want) {
+			t.Errorf("%s: want %d volumesAttached, got %v", step.name, len(step.want), node.Status.VolumesAttached)
+		}
+	}
+}
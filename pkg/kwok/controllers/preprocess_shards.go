@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"hash/fnv"
+)
+
+// preprocessShards is the channels of the preprocess workers,
+// the resources are dispatched by their key so that the events of the same resource
+// are always preprocessed in order by the same worker.
+type preprocessShards[T any] struct {
+	chans []chan T
+}
+
+// newPreprocessShards creates the channels for n preprocess workers, at least one
+func newPreprocessShards[T any](n uint) *preprocessShards[T] {
+	if n == 0 {
+		n = 1
+	}
+	chans := make([]chan T, n)
+	for i := range chans {
+		chans[i] = make(chan T)
+	}
+	return &preprocessShards[T]{
+		chans: chans,
+	}
+}
+
+// Len returns the number of the preprocess workers
+func (s *preprocessShards[T]) Len() int {
+	return len(s.chans)
+}
+
+// Chan returns the channel of the i-th preprocess worker
+func (s *preprocessShards[T]) Chan(i int) <-chan T {
+	return s.chans[i]
+}
+
+// Push sends the resource to the preprocess worker of the key
+func (s *preprocessShards[T]) Push(key string, obj T) {
+	s.chans[s.index(key)] <- obj
+}
+
+func (s *preprocessShards[T]) index(key string) int {
+	if len(s.chans) == 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(s.chans)))
+}
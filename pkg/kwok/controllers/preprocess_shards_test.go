@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+)
+
+func TestPreprocessShards(t *testing.T) {
+	tests := []struct {
+		name string
+		n    uint
+		want int
+	}{
+		{
+			name: "zero",
+			n:    0,
+			want: 1,
+		},
+		{
+			name: "one",
+			n:    1,
+			want: 1,
+		},
+		{
+			name: "many",
+			n:    4,
+			want: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newPreprocessShards[string](tt.n)
+			if got := s.Len(); got != tt.want {
+				t.Fatalf("Len() = %d, want %d", got, tt.want)
+			}
+
+			keys := []string{"default/a", "default/b", "kube-system/c", "node-0", "node-1"}
+			for _, key := range keys {
+				index := s.index(key)
+				if index < 0 || index >= s.Len() {
+					t.Fatalf("index(%q) = %d, out of range", key, index)
+				}
+				if again := s.index(key); again != index {
+					t.Fatalf("index(%q) = %d, then %d, want stable", key, index, again)
+				}
+
+				go s.Push(key, key)
+				if got := <-s.Chan(index); got != key {
+					t.Fatalf("Chan(%d) received %q, want %q", index, got, key)
+				}
+			}
+		})
+	}
+}
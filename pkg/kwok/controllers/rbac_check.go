@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/apis/v1alpha1"
+)
+
+// RBACRule is a permission required by kwok, kept in sync with the +kubebuilder:rbac markers of the APIs
+type RBACRule struct {
+	Group    string
+	Resource string
+	Verbs    []string
+}
+
+// String returns the rule in the form of verbs on resource.group
+func (r RBACRule) String() string {
+	resource := r.Resource
+	if r.Group != "" {
+		resource += "." + r.Group
+	}
+	return strings.Join(r.Verbs, ",") + " " + resource
+}
+
+var (
+	crudVerbs   = []string{"create", "delete", "get", "list", "patch", "update", "watch"}
+	statusVerbs = []string{"patch", "update"}
+
+	// coreRBACRules are the permissions of the markers in pkg/apis/v1alpha1/doc.go which are always required
+	coreRBACRules = []RBACRule{
+		{Resource: "nodes", Verbs: []string{"get", "list", "watch"}},
+		{Resource: "nodes/status", Verbs: statusVerbs},
+		{Resource: "pods", Verbs: []string{"delete", "get", "list", "patch", "update", "watch"}},
+		{Resource: "pods/status", Verbs: statusVerbs},
+		{Resource: "events", Verbs: crudVerbs},
+	}
+
+	// nodeLeaseRBACRules are the permissions required if the node leases are enabled
+	nodeLeaseRBACRules = []RBACRule{
+		{Group: "coordination.k8s.io", Resource: "leases", Verbs: []string{"create", "get", "list", "patch", "update", "watch"}},
+	}
+
+	// nodeVolumeStatusRBACRules are the permissions required if the volumes in the status of nodes are enabled
+	nodeVolumeStatusRBACRules = []RBACRule{
+		{Resource: "persistentvolumeclaims", Verbs: []string{"get", "list", "watch"}},
+		{Resource: "persistentvolumes", Verbs: []string{"get", "list", "watch"}},
+	}
+
+	// crdRBACResources are the resources of the CRDs which are only required if the CRD is enabled
+	crdRBACResources = map[string]string{
+		v1alpha1.StageKind:                "stages",
+		v1alpha1.AttachKind:               "attaches",
+		v1alpha1.ClusterAttachKind:        "clusterattaches",
+		v1alpha1.ExecKind:                 "execs",
+		v1alpha1.ClusterExecKind:          "clusterexecs",
+		v1alpha1.PortForwardKind:          "portforwards",
+		v1alpha1.ClusterPortForwardKind:   "clusterportforwards",
+		v1alpha1.LogsKind:                 "logs",
+		v1alpha1.ClusterLogsKind:          "clusterlogs",
+		v1alpha1.ResourceUsageKind:        "resourceusages",
+		v1alpha1.ClusterResourceUsageKind: "clusterresourceusages",
+		v1alpha1.MetricKind:               "metrics",
+	}
+)
+
+// RequiredRBACRules returns the permissions required by kwok with the features and the CRDs enabled in the options
+func RequiredRBACRules(opts *internalversion.KwokConfigurationOptions) []RBACRule {
+	rules := append([]RBACRule{}, coreRBACRules...)
+	if opts.NodeLeaseDurationSeconds != 0 {
+		rules = append(rules, nodeLeaseRBACRules...)
+	}
+	if opts.EnableNodeVolumeStatus {
+		rules = append(rules, nodeVolumeStatusRBACRules...)
+	}
+	for _, crd := range opts.EnableCRDs {
+		resource, ok := crdRBACResources[crd]
+		if !ok {
+			continue
+		}
+		rules = append(rules,
+			RBACRule{Group: v1alpha1.GroupVersion.Group, Resource: resource, Verbs: crudVerbs},
+			RBACRule{Group: v1alpha1.GroupVersion.Group, Resource: resource + "/status", Verbs: statusVerbs},
+		)
+	}
+	return rules
+}
+
+// CheckRBAC reviews the rules with SelfSubjectAccessReviews in all namespaces,
+// and returns the rules with only the verbs that are not allowed.
+func CheckRBAC(ctx context.Context, typedClient kubernetes.Interface, rules []RBACRule) ([]RBACRule, error) {
+	reviews := typedClient.AuthorizationV1().SelfSubjectAccessReviews()
+
+	var missing []RBACRule
+	for _, rule := range rules {
+		resource, subresource, _ := strings.Cut(rule.Resource, "/")
+		var denied []string
+		for _, verb := range rule.Verbs {
+			review, err := reviews.Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Verb:        verb,
+						Group:       rule.Group,
+						Resource:    resource,
+						Subresource: subresource,
+					},
+				},
+			}, metav1.CreateOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to review access to %s %s: %w", verb, rule.Resource, err)
+			}
+			if !review.Status.Allowed {
+				denied = append(denied, verb)
+			}
+		}
+		if len(denied) != 0 {
+			missing = append(missing, RBACRule{
+				Group:    rule.Group,
+				Resource: rule.Resource,
+				Verbs:    denied,
+			})
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/apis/v1alpha1"
+	"sigs.k8s.io/kwok/pkg/utils/slices"
+)
+
+func TestCheckRBAC(t *testing.T) {
+	rules := []RBACRule{
+		{Resource: "nodes", Verbs: []string{"get", "list", "watch"}},
+		{Resource: "pods/status", Verbs: []string{"patch", "update"}},
+		{Group: "coordination.k8s.io", Resource: "leases", Verbs: []string{"create", "get"}},
+	}
+	tests := []struct {
+		name    string
+		denied  map[string]bool
+		err     error
+		want    []RBACRule
+		wantErr bool
+	}{
+		{
+			name: "all allowed",
+		},
+		{
+			name: "missing",
+			denied: map[string]bool{
+				"update /pods/status":                true,
+				"create coordination.k8s.io/leases/": true,
+			},
+			want: []RBACRule{
+				{Resource: "pods/status", Verbs: []string{"update"}},
+				{Group: "coordination.k8s.io", Resource: "leases", Verbs: []string{"create"}},
+			},
+		},
+		{
+			name:    "review failed",
+			err:     fmt.Errorf("unavailable"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset()
+			clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				if tt.err != nil {
+					return true, nil, tt.err
+				}
+				review := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+				attrs := review.Spec.ResourceAttributes
+				key := fmt.Sprintf("%s %s/%s/%s", attrs.Verb, attrs.Group, attrs.Resource, attrs.Subresource)
+				review.Status.Allowed = !tt.denied[key]
+				return true, review, nil
+			})
+
+			got, err := CheckRBAC(context.Background(), clientset, rules)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckRBAC() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckRBAC() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequiredRBACRules(t *testing.T) {
+	core := []string{
+		"get,list,watch nodes",
+		"patch,update nodes/status",
+		"delete,get,list,patch,update,watch pods",
+		"patch,update pods/status",
+		"create,delete,get,list,patch,update,watch events",
+	}
+	tests := []struct {
+		name string
+		opts internalversion.KwokConfigurationOptions
+		want []string
+	}{
+		{
+			name: "default",
+			want: core,
+		},
+		{
+			name: "node lease",
+			opts: internalversion.KwokConfigurationOptions{
+				NodeLeaseDurationSeconds: 40,
+			},
+			want: append(slices.Clone(core),
+				"create,get,list,patch,update,watch leases.coordination.k8s.io",
+			),
+		},
+		{
+			name: "node volume status",
+			opts: internalversion.KwokConfigurationOptions{
+				EnableNodeVolumeStatus: true,
+			},
+			want: append(slices.Clone(core),
+				"get,list,watch persistentvolumeclaims",
+				"get,list,watch persistentvolumes",
+			),
+		},
+		{
+			name: "crds",
+			opts: internalversion.KwokConfigurationOptions{
+				EnableCRDs: []string{v1alpha1.StageKind, "Unknown"},
+			},
+			want: append(slices.Clone(core),
+				"create,delete,get,list,patch,update,watch stages.kwok.x-k8s.io",
+				"patch,update stages/status.kwok.x-k8s.io",
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := RequiredRBACRules(&tt.opts)
+			got := make([]string, 0, len(rules))
+			for _, rule := range rules {
+				got = append(got, rule.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RequiredRBACRules() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/kwok/pkg/log"
+)
+
+const (
+	shutdownStepStages     = "stages"
+	shutdownStepPods       = "pods"
+	shutdownStepNodes      = "nodes"
+	shutdownStepNodeLeases = "node leases"
+
+	defaultShutdownDrainTimeout = 10 * time.Second
+)
+
+// shutdownSteps is the order to stop the subcontrollers in,
+// which is the reverse of the order they depend on each other.
+// The node leases are stopped last so that the nodes are not taken over
+// by another controller while the pods on them are still being managed.
+var shutdownSteps = []string{
+	shutdownStepStages,
+	shutdownStepPods,
+	shutdownStepNodes,
+	shutdownStepNodeLeases,
+}
+
+// shutdownEntry is a subcontroller registered to the shutdown
+type shutdownEntry struct {
+	cancel context.CancelFunc
+	wait   func()
+}
+
+// shutdownOrder stops the subcontrollers in the order of shutdownSteps once the root context is done,
+// the subcontrollers of each step are canceled and drained before the next step.
+type shutdownOrder struct {
+	root         context.Context
+	drainTimeout time.Duration
+
+	mut     sync.Mutex
+	entries map[string][]*shutdownEntry
+	stopped bool
+	done    chan struct{}
+}
+
+// newShutdownOrder creates a shutdown order for the root context
+func newShutdownOrder(root context.Context, drainTimeout time.Duration) *shutdownOrder {
+	s := &shutdownOrder{
+		root:         root,
+		drainTimeout: drainTimeout,
+		entries:      map[string][]*shutdownEntry{},
+		done:         make(chan struct{}),
+	}
+	go func() {
+		<-root.Done()
+		s.shutdown(context.WithoutCancel(root))
+	}()
+	return s
+}
+
+// register returns the context for a subcontroller of the step, wait is used to drain it.
+// If ctx is canceled while the root context is still running, for example when the stages are removed,
+// the returned context is canceled immediately, otherwise it is canceled by the shutdown.
+func (s *shutdownOrder) register(ctx context.Context, step string, wait func()) context.Context {
+	subCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	entry := &shutdownEntry{
+		cancel: cancel,
+		wait:   wait,
+	}
+
+	s.mut.Lock()
+	if s.stopped {
+		s.mut.Unlock()
+		cancel()
+		return subCtx
+	}
+	s.entries[step] = append(s.entries[step], entry)
+	s.mut.Unlock()
+
+	go func() {
+		select {
+		case <-subCtx.Done():
+			return
+		case <-ctx.Done():
+		}
+		// The cancellation of the root context is propagated to ctx,
+		// so the root context is always done first in the case of shutdown.
+		if s.root.Err() != nil {
+			return
+		}
+		s.unregister(step, entry)
+		cancel()
+	}()
+	return subCtx
+}
+
+// unregister removes the entry from the step
+func (s *shutdownOrder) unregister(step string, entry *shutdownEntry) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	entries := s.entries[step]
+	for i, e := range entries {
+		if e == entry {
+			s.entries[step] = append(entries[:i], entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// shutdown stops the steps in order
+func (s *shutdownOrder) shutdown(ctx context.Context) {
+	defer close(s.done)
+
+	s.mut.Lock()
+	entries := s.entries
+	s.entries = map[string][]*shutdownEntry{}
+	s.stopped = true
+	s.mut.Unlock()
+
+	logger := log.FromContext(ctx)
+	for _, step := range shutdownSteps {
+		stepEntries := entries[step]
+		if len(stepEntries) == 0 {
+			continue
+		}
+
+		logger.Info("Stop controller", "controller", step)
+		for _, entry := range stepEntries {
+			entry.cancel()
+		}
+
+		if !s.drain(stepEntries) {
+			logger.Warn("Timed out waiting for controller to drain",
+				"controller", step,
+				"timeout", s.drainTimeout,
+			)
+		}
+	}
+}
+
+// drain waits for the entries to return, it returns false if timed out
+func (s *shutdownOrder) drain(entries []*shutdownEntry) bool {
+	drained := make(chan struct{})
+	go func() {
+		for _, entry := range entries {
+			if entry.wait != nil {
+				entry.wait()
+			}
+		}
+		close(drained)
+	}()
+
+	timer := time.NewTimer(s.drainTimeout)
+	defer timer.Stop()
+	select {
+	case <-drained:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// Done returns a channel that is closed once the shutdown has finished
+func (s *shutdownOrder) Done() <-chan struct{} {
+	return s.done
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShutdownOrder(t *testing.T) {
+	root, cancel := context.WithCancel(context.Background())
+	s := newShutdownOrder(root, time.Second)
+
+	var mut sync.Mutex
+	var stopped []string
+	register := func(step string) context.Context {
+		var ctx context.Context
+		ctx = s.register(root, step, func() {
+			<-ctx.Done()
+			// The later steps must not be canceled before this one is drained.
+			time.Sleep(10 * time.Millisecond)
+			mut.Lock()
+			defer mut.Unlock()
+			stopped = append(stopped, step)
+		})
+		return ctx
+	}
+
+	// Registered in the order of starting.
+	leasesCtx := register(shutdownStepNodeLeases)
+	register(shutdownStepNodes)
+	podsCtx := register(shutdownStepPods)
+
+	cancel()
+
+	select {
+	case <-s.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for shutdown")
+	}
+
+	want := []string{shutdownStepPods, shutdownStepNodes, shutdownStepNodeLeases}
+	if !reflect.DeepEqual(stopped, want) {
+		t.Errorf("want stopped in order %v, got %v", want, stopped)
+	}
+	if podsCtx.Err() == nil || leasesCtx.Err() == nil {
+		t.Errorf("want all contexts canceled")
+	}
+}
+
+func TestShutdownOrderCancelBeforeShutdown(t *testing.T) {
+	root, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newShutdownOrder(root, time.Second)
+
+	// The stage controllers are canceled by the stages manager when the stages are removed.
+	stageCtx, stageCancel := context.WithCancel(root)
+	ctx := s.register(stageCtx, shutdownStepPods, nil)
+	leasesCtx := s.register(root, shutdownStepNodeLeases, nil)
+
+	stageCancel()
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("want context canceled with its parent before shutdown")
+	}
+	if leasesCtx.Err() != nil {
+		t.Errorf("want other contexts not canceled")
+	}
+}
+
+func TestShutdownOrderDrainTimeout(t *testing.T) {
+	root, cancel := context.WithCancel(context.Background())
+	s := newShutdownOrder(root, 10*time.Millisecond)
+
+	block := make(chan struct{})
+	defer close(block)
+	s.register(root, shutdownStepPods, func() {
+		<-block
+	})
+	leasesCtx := s.register(root, shutdownStepNodeLeases, nil)
+
+	cancel()
+	select {
+	case <-s.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("want shutdown to continue after the drain timeout")
+	}
+	if leasesCtx.Err() == nil {
+		t.Errorf("want the later steps canceled after the drain timeout")
+	}
+}
@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"sync/atomic"
 	"time"
 
@@ -57,11 +58,14 @@ type StageController struct {
 	renderer                              gotpl.Renderer
 	preprocessChan                        chan *unstructured.Unstructured
 	playStageParallelism                  uint
+	delayJitter                           time.Duration
 	lifecycle                             resources.Getter[lifecycle.Lifecycle]
 	delayQueue                            queue.WeightDelayingQueue[resourceStageJob[*unstructured.Unstructured]]
 	backoff                               wait.Backoff
 	delayQueueMapping                     maps.SyncMap[string, resourceStageJob[*unstructured.Unstructured]]
+	stageCounter                          *lifecycle.Counter
 	recorder                              record.EventRecorder
+	stageWebhookClient                    *http.Client
 }
 
 // StageControllerConfig is the configuration for the StageController
@@ -75,8 +79,10 @@ type StageControllerConfig struct {
 	DisregardStatusWithLabelSelector      string
 	Lifecycle                             resources.Getter[lifecycle.Lifecycle]
 	PlayStageParallelism                  uint
+	DelayJitter                           time.Duration
 	FuncMap                               gotpl.FuncMap
 	Recorder                              record.EventRecorder
+	StageWebhookClient                    *http.Client
 }
 
 // NewStageController creates a new fake resources controller
@@ -85,6 +91,10 @@ func NewStageController(conf StageControllerConfig) (*StageController, error) {
 		return nil, fmt.Errorf("playStageParallelism must be greater than 0")
 	}
 
+	if conf.DelayJitter < 0 {
+		return nil, fmt.Errorf("delayJitter must not be negative")
+	}
+
 	disregardStatusWithAnnotationSelector, err := labelsParse(conf.DisregardStatusWithAnnotationSelector)
 	if err != nil {
 		return nil, err
@@ -109,10 +119,13 @@ func NewStageController(conf StageControllerConfig) (*StageController, error) {
 		disregardStatusWithLabelSelector:      disregardStatusWithLabelSelector,
 		delayQueue:                            queue.NewWeightDelayingQueue[resourceStageJob[*unstructured.Unstructured]](conf.Clock),
 		backoff:                               defaultBackoff(),
+		stageCounter:                          lifecycle.NewCounter(),
 		lifecycle:                             conf.Lifecycle,
 		playStageParallelism:                  conf.PlayStageParallelism,
+		delayJitter:                           conf.DelayJitter,
 		preprocessChan:                        make(chan *unstructured.Unstructured),
 		recorder:                              conf.Recorder,
+		stageWebhookClient:                    conf.StageWebhookClient,
 	}
 
 	c.renderer = gotpl.NewRenderer(conf.FuncMap)
@@ -162,6 +175,7 @@ func (c *StageController) preprocessWorker(ctx context.Context) {
 		case resource := <-c.preprocessChan:
 			err := c.preprocess(ctx, resource)
 			if err != nil {
+				recordReconcileError("stage", err)
 				logger.Error("Failed to preprocess resource", err,
 					"resource", log.KObj(resource),
 				)
@@ -195,7 +209,7 @@ func (c *StageController) preprocess(ctx context.Context, resource *unstructured
 		return err
 	}
 
-	lc := c.lifecycle.Get()
+	lc := c.stageCounter.Filter(resource.GetUID(), c.lifecycle.Get())
 	stage, err := lc.Match(ctx, resource.GetLabels(), resource.GetAnnotations(), data)
 	if err != nil {
 		return fmt.Errorf("stage match: %w", err)
@@ -209,6 +223,7 @@ func (c *StageController) preprocess(ctx context.Context, resource *unstructured
 
 	now := c.clock.Now()
 	delay, _ := stage.Delay(ctx, data, now)
+	delay = jitterDelay(delay, c.delayJitter)
 
 	if delay != 0 {
 		stageName := stage.Name()
@@ -243,6 +258,7 @@ func (c *StageController) playStageWorker(ctx context.Context) {
 		c.delayQueueMapping.Delete(resource.Key)
 		needRetry, err := c.playStage(ctx, resource.Resource, resource.Stage)
 		if err != nil {
+			recordReconcileError("stage", err)
 			logger.Error("failed to apply stage", err,
 				"resource", resource.Key,
 				"stage", resource.Stage.Name(),
@@ -265,7 +281,7 @@ func (c *StageController) playStageWorker(ctx context.Context) {
 
 // playStage plays the stage.
 // The returned boolean indicates whether the applying action needs to be retried.
-func (c *StageController) playStage(ctx context.Context, resource *unstructured.Unstructured, stage *lifecycle.Stage) (bool, error) {
+func (c *StageController) playStage(ctx context.Context, resource *unstructured.Unstructured, stage *lifecycle.Stage) (needRetry bool, err error) {
 	next := stage.Next()
 	logger := log.FromContext(ctx)
 	logger = logger.With(
@@ -273,10 +289,9 @@ func (c *StageController) playStage(ctx context.Context, resource *unstructured.
 		"stage", stage.Name(),
 	)
 
-	var (
-		result *unstructured.Unstructured
-		err    error
-	)
+	var result *unstructured.Unstructured
+
+	defer c.stageCounter.Track(resource.GetUID(), stage, &err)()
 
 	if event := next.Event(); event != nil && c.recorder != nil {
 		c.recorder.Event(&corev1.ObjectReference{
@@ -327,6 +342,29 @@ func (c *StageController) playStage(ctx context.Context, resource *unstructured.
 				}
 			}
 		}
+
+		if next.HasWebhook() {
+			if c.stageWebhookClient == nil {
+				logger.Warn("Skip webhook",
+					"reason", "stage webhook is disabled",
+				)
+			} else {
+				current := resource
+				if result != nil {
+					current = result
+				}
+				patch, err := next.Webhook(ctx, current.Object, c.renderer, c.stageWebhookClient)
+				if err != nil {
+					return false, fmt.Errorf("failed to call webhook for resource %s: %w", resource.GetName(), err)
+				}
+				if patch != nil {
+					result, err = c.patchResource(ctx, resource, patch)
+					if err != nil {
+						return shouldRetry(err), fmt.Errorf("failed to patch resource %s: %w", resource.GetName(), err)
+					}
+				}
+			}
+		}
 	}
 
 	if result != nil && stage.ImmediateNextStage() {
@@ -419,6 +457,8 @@ loop:
 			case informer.Deleted:
 				resource := event.Object
 				if c.need(resource) {
+					c.stageCounter.Forget(resource.GetUID())
+
 					// Cancel delay job
 					key := log.KObj(resource).String()
 					resourceJob, ok := c.delayQueueMapping.LoadAndDelete(key)
@@ -18,10 +18,12 @@ package controllers
 
 import (
 	"context"
+	"reflect"
 
 	"sigs.k8s.io/kwok/pkg/apis/internalversion"
 	"sigs.k8s.io/kwok/pkg/config/resources"
 	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/gotpl"
 	"sigs.k8s.io/kwok/pkg/utils/lifecycle"
 	"sigs.k8s.io/kwok/pkg/utils/sets"
 	"sigs.k8s.io/kwok/pkg/utils/slices"
@@ -31,6 +33,7 @@ import (
 type StagesManagerConfig struct {
 	StageGetter resources.DynamicGetter[[]*internalversion.Stage]
 	StartFunc   func(ctx context.Context, ref internalversion.StageResourceRef, lifecycle resources.Getter[lifecycle.Lifecycle]) error
+	FuncMap     gotpl.FuncMap
 }
 
 // StagesManager is a stages manager
@@ -38,7 +41,9 @@ type StagesManagerConfig struct {
 type StagesManager struct {
 	stageGetter resources.DynamicGetter[[]*internalversion.Stage]
 	startFunc   func(ctx context.Context, ref internalversion.StageResourceRef, lifecycle resources.Getter[lifecycle.Lifecycle]) error
+	funcMap     gotpl.FuncMap
 	cache       map[internalversion.StageResourceRef]context.CancelCauseFunc
+	loaded      map[string]internalversion.StageSpec
 }
 
 // NewStagesManager creates a stage controller manager
@@ -46,6 +51,7 @@ func NewStagesManager(conf StagesManagerConfig) *StagesManager {
 	return &StagesManager{
 		stageGetter: conf.StageGetter,
 		startFunc:   conf.StartFunc,
+		funcMap:     conf.FuncMap,
 		cache:       map[internalversion.StageResourceRef]context.CancelCauseFunc{},
 	}
 }
@@ -70,8 +76,11 @@ func (c *StagesManager) run(ctx context.Context) {
 }
 
 func (c *StagesManager) manage(ctx context.Context) {
+	stages := c.stageGetter.Get()
+	c.recordReload(ctx, stages)
+
 	set := sets.NewSets[internalversion.StageResourceRef]()
-	for _, stage := range c.stageGetter.Get() {
+	for _, stage := range stages {
 		set.Insert(stage.Spec.ResourceRef)
 	}
 
@@ -90,6 +99,12 @@ func (c *StagesManager) manage(ctx context.Context) {
 					return nil, false
 				}
 
+				err := lifecycle.ValidateStage(stage, c.funcMap)
+				if err != nil {
+					logger.Error("failed to validate lifecycle stage", err, "ref", ref)
+					return nil, false
+				}
+
 				lifecycleStage, err := lifecycle.NewStage(stage)
 				if err != nil {
 					logger.Error("failed to create lifecycle stage", err, "ref", ref)
@@ -120,3 +135,34 @@ func (c *StagesManager) manage(ctx context.Context) {
 		delete(c.cache, ref)
 	}
 }
+
+// recordReload records the stages in the metrics and the log if they are changed since the last sync,
+// the stages include the ones from the config merged with the ones from the Stage CRD.
+func (c *StagesManager) recordReload(ctx context.Context, stages []*internalversion.Stage) {
+	loaded := make(map[string]internalversion.StageSpec, len(stages))
+	for _, stage := range stages {
+		loaded[stage.Name] = stage.Spec
+	}
+	if c.loaded != nil && reflect.DeepEqual(c.loaded, loaded) {
+		return
+	}
+	c.loaded = loaded
+
+	counts := map[internalversion.StageResourceRef]int{}
+	for _, stage := range stages {
+		counts[stage.Spec.ResourceRef]++
+	}
+
+	stagesReloadsTotal.Inc()
+	stagesLoaded.Reset()
+	kinds := make([]any, 0, len(counts)*2)
+	for ref, count := range counts {
+		stagesLoaded.WithLabelValues(ref.APIGroup, ref.Kind).Set(float64(count))
+		kinds = append(kinds, ref.APIGroup+"/"+ref.Kind, count)
+	}
+
+	logger := log.FromContext(ctx)
+	logger.Debug("Reloaded stages",
+		append([]any{"total", len(stages)}, kinds...)...,
+	)
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
@@ -34,6 +35,7 @@ import (
 
 	"sigs.k8s.io/kwok/pkg/utils/lifecycle"
 	utilsnet "sigs.k8s.io/kwok/pkg/utils/net"
+	"sigs.k8s.io/kwok/pkg/utils/slices"
 	"sigs.k8s.io/kwok/pkg/utils/wait"
 )
 
@@ -302,3 +304,14 @@ func checkNeedMergePatch(obj any, patchData []byte) (bool, error) {
 
 	return true, nil
 }
+
+// kwokFinalizerPrefix is the prefix of the finalizers added by the stages of kwok.
+const kwokFinalizerPrefix = "kwok.x-k8s.io/"
+
+// pendingFinalizers returns the finalizers that are added by other controllers,
+// the resource is not deleted by the stages until they are removed.
+func pendingFinalizers(finalizers []string) []string {
+	return slices.Filter(finalizers, func(finalizer string) bool {
+		return !strings.HasPrefix(finalizer, kwokFinalizerPrefix)
+	})
+}
@@ -188,3 +188,33 @@ func Test_shouldRetry(t *testing.T) {
 		})
 	}
 }
+
+func TestPendingFinalizers(t *testing.T) {
+	tests := []struct {
+		name       string
+		finalizers []string
+		want       []string
+	}{
+		{
+			name: "empty",
+			want: []string{},
+		},
+		{
+			name:       "only kwok finalizers",
+			finalizers: []string{"kwok.x-k8s.io/fake"},
+			want:       []string{},
+		},
+		{
+			name:       "mixed finalizers",
+			finalizers: []string{"kwok.x-k8s.io/fake", "kubernetes.io/pvc-protection", "example.com/protect"},
+			want:       []string{"kubernetes.io/pvc-protection", "example.com/protect"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pendingFinalizers(tt.finalizers); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pendingFinalizers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -112,7 +112,7 @@ func TestResourceEvaluation(t *testing.T) {
 		t.Fatalf("evaluation failed: %v", err)
 	}
 
-	if actual != 18 {
-		t.Errorf("expected %v, got %v", 18, actual)
+	if actual != 1.8 {
+		t.Errorf("expected %v, got %v", 1.8, actual)
 	}
 }
@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/kwok/pkg/log"
 	"sigs.k8s.io/kwok/pkg/utils/informer"
 	"sigs.k8s.io/kwok/pkg/utils/maps"
+	"sigs.k8s.io/kwok/pkg/utils/slices"
 )
 
 // UpdateHandler handles updating metrics on request
@@ -40,6 +41,8 @@ type UpdateHandler struct {
 	nodeCacheGetter informer.Getter[*corev1.Node]
 	podCacheGetter  informer.Getter[*corev1.Pod]
 
+	disabledDimensions []internalversion.Dimension
+
 	handler  http.Handler
 	registry *prometheus.Registry
 
@@ -59,6 +62,9 @@ type UpdateHandlerConfig struct {
 	Environment     *Environment
 	NodeCacheGetter informer.Getter[*corev1.Node]
 	PodCacheGetter  informer.Getter[*corev1.Pod]
+
+	// DisabledDimensions is the dimensions of the metrics that are not registered
+	DisabledDimensions []internalversion.Dimension
 }
 
 // NewMetricsUpdateHandler creates new metric update handler based on the config
@@ -75,6 +81,8 @@ func NewMetricsUpdateHandler(conf UpdateHandlerConfig) *UpdateHandler {
 		podCacheGetter:  conf.PodCacheGetter,
 		registry:        registry,
 		handler:         handler,
+
+		disabledDimensions: conf.DisabledDimensions,
 	}
 	return h
 }
@@ -530,6 +538,9 @@ func (h *UpdateHandler) Update(ctx context.Context, nodeName string, metrics []i
 	for _, metric := range metrics {
 		metric := metric
 		metricName := metric.Name
+		if slices.Contains(h.disabledDimensions, metric.Dimension) {
+			continue
+		}
 		keys, err := h.updateMetric(ctx, &metric, nodeName)
 		if err != nil {
 			logger.Error("failed to update metrics", err,
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/log"
+)
+
+type fakeGetter[T runtime.Object] map[string]T
+
+func (g fakeGetter[T]) Get(name string) (T, bool) {
+	obj, ok := g[name]
+	return obj, ok
+}
+
+func (g fakeGetter[T]) GetWithNamespace(name, namespace string) (T, bool) {
+	obj, ok := g[namespace+"/"+name]
+	return obj, ok
+}
+
+func (g fakeGetter[T]) List() []T {
+	list := make([]T, 0, len(g))
+	for _, obj := range g {
+		list = append(list, obj)
+	}
+	return list
+}
+
+type fakeDataSource map[string][]log.ObjectRef
+
+func (d fakeDataSource) ListPods(nodeName string) ([]log.ObjectRef, bool) {
+	pods, ok := d[nodeName]
+	return pods, ok
+}
+
+func TestUpdateHandlerDisabledDimensions(t *testing.T) {
+	metricConfigs := []internalversion.MetricConfig{
+		{
+			Name:      "kwok_node_up",
+			Kind:      internalversion.KindGauge,
+			Dimension: internalversion.DimensionNode,
+			Value:     "1",
+		},
+		{
+			Name:      "kwok_pod_up",
+			Kind:      internalversion.KindGauge,
+			Dimension: internalversion.DimensionPod,
+			Value:     "1",
+			Labels: []internalversion.MetricLabel{
+				{Name: "pod", Value: "pod.metadata.name"},
+			},
+		},
+		{
+			Name:      "kwok_container_up",
+			Kind:      internalversion.KindGauge,
+			Dimension: internalversion.DimensionContainer,
+			Value:     "1",
+			Labels: []internalversion.MetricLabel{
+				{Name: "container", Value: "container.name"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		disabled []internalversion.Dimension
+		want     []string
+		notWant  []string
+	}{
+		{
+			name: "all enabled",
+			want: []string{"kwok_node_up", "kwok_pod_up", "kwok_container_up"},
+		},
+		{
+			name:     "pod and container disabled",
+			disabled: []internalversion.Dimension{internalversion.DimensionPod, internalversion.DimensionContainer},
+			want:     []string{"kwok_node_up"},
+			notWant:  []string{"kwok_pod_up", "kwok_container_up"},
+		},
+		{
+			name:     "node disabled",
+			disabled: []internalversion.Dimension{internalversion.DimensionNode},
+			want:     []string{"kwok_pod_up", "kwok_container_up"},
+			notWant:  []string{"kwok_node_up"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := NewEnvironment(EnvironmentConfig{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			h := NewMetricsUpdateHandler(UpdateHandlerConfig{
+				Environment: env,
+				DataSource: fakeDataSource{
+					"node0": {log.KRef("default", "pod0")},
+				},
+				NodeCacheGetter: fakeGetter[*corev1.Node]{
+					"node0": {ObjectMeta: metav1.ObjectMeta{Name: "node0"}},
+				},
+				PodCacheGetter: fakeGetter[*corev1.Pod]{
+					"default/pod0": {
+						ObjectMeta: metav1.ObjectMeta{Name: "pod0", Namespace: "default"},
+						Spec: corev1.PodSpec{
+							NodeName:   "node0",
+							Containers: []corev1.Container{{Name: "container0"}},
+						},
+					},
+				},
+				DisabledDimensions: tt.disabled,
+			})
+
+			h.Update(context.Background(), "node0", metricConfigs)
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			resp := httptest.NewRecorder()
+			h.ServeHTTP(resp, req)
+			body := resp.Body.String()
+			for _, name := range tt.want {
+				if !strings.Contains(body, name) {
+					t.Errorf("want metric %s, got:\n%s", name, body)
+				}
+			}
+			for _, name := range tt.notWant {
+				if strings.Contains(body, name) {
+					t.Errorf("want no metric %s, got:\n%s", name, body)
+				}
+			}
+		})
+	}
+}
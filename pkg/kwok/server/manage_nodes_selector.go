@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+)
+
+// ManageNodesSelectorUpdater gets and updates the selectors of the managed nodes
+type ManageNodesSelectorUpdater interface {
+	ManageNodesSelector() (labelSelector, annotationSelector string)
+	UpdateManageNodesSelector(ctx context.Context, labelSelector, annotationSelector string) error
+}
+
+// ManageNodesSelector is the selectors of the managed nodes
+type ManageNodesSelector struct {
+	LabelSelector      string `json:"labelSelector,omitempty"`
+	AnnotationSelector string `json:"annotationSelector,omitempty"`
+}
+
+// InstallManageNodesSelector installs the handler to get and update the selectors of the managed nodes,
+// it is only installed if the data source supports it.
+func (s *Server) InstallManageNodesSelector() {
+	updater, ok := s.dataSource.(ManageNodesSelectorUpdater)
+	if !ok {
+		return
+	}
+
+	ws := new(restful.WebService)
+	ws.Path("/manage/nodes/selector").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("").
+		To(func(req *restful.Request, resp *restful.Response) {
+			s.getManageNodesSelector(updater, resp)
+		}).
+		Operation("getManageNodesSelector"))
+	ws.Route(ws.PUT("").
+		To(func(req *restful.Request, resp *restful.Response) {
+			s.updateManageNodesSelector(updater, req, resp)
+		}).
+		Operation("updateManageNodesSelector"))
+	s.restfulCont.Add(ws)
+}
+
+func (s *Server) getManageNodesSelector(updater ManageNodesSelectorUpdater, resp *restful.Response) {
+	labelSelector, annotationSelector := updater.ManageNodesSelector()
+	_ = resp.WriteEntity(ManageNodesSelector{
+		LabelSelector:      labelSelector,
+		AnnotationSelector: annotationSelector,
+	})
+}
+
+func (s *Server) updateManageNodesSelector(updater ManageNodesSelectorUpdater, req *restful.Request, resp *restful.Response) {
+	selector := ManageNodesSelector{}
+	err := req.ReadEntity(&selector)
+	if err != nil {
+		_ = resp.WriteError(http.StatusBadRequest, err)
+		return
+	}
+
+	err = updater.UpdateManageNodesSelector(req.Request.Context(), selector.LabelSelector, selector.AnnotationSelector)
+	if err != nil {
+		_ = resp.WriteError(http.StatusBadRequest, err)
+		return
+	}
+	s.getManageNodesSelector(updater, resp)
+}
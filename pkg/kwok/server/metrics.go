@@ -140,6 +140,8 @@ func (s *Server) getMetrics(metric *internalversion.Metric, env *metrics.Environ
 				DataSource:      s.dataSource,
 				NodeCacheGetter: s.nodeCacheGetter,
 				PodCacheGetter:  s.podCacheGetter,
+
+				DisabledDimensions: s.disabledMetricsDimensions,
 			})
 			s.metricsUpdateHandler.Store(nodeName, handler)
 		}
@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"github.com/emicklei/go-restful/v3"
+
+	"sigs.k8s.io/kwok/pkg/log"
+)
+
+// InstallNodePods installs the handler to list the pods on a node from the cache of the controller,
+// it is much cheaper than listing the pods with a field selector on the apiserver.
+func (s *Server) InstallNodePods() {
+	ws := new(restful.WebService)
+	ws.Path("/nodes").
+		Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("/{nodeName}/pods").
+		To(s.getNodePods).
+		Operation("getNodePods"))
+	s.restfulCont.Add(ws)
+}
+
+func (s *Server) getNodePods(req *restful.Request, resp *restful.Response) {
+	nodeName := req.PathParameter("nodeName")
+	pods, ok := s.dataSource.ListPods(nodeName)
+	if !ok || pods == nil {
+		// the node has no pods tracked by the controller, same as an empty list from the apiserver
+		pods = []log.ObjectRef{}
+	}
+	_ = resp.WriteEntity(pods)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/emicklei/go-restful/v3"
+
+	"sigs.k8s.io/kwok/pkg/log"
+)
+
+type fakePodsDataSource map[string][]log.ObjectRef
+
+func (f fakePodsDataSource) ListPods(nodeName string) ([]log.ObjectRef, bool) {
+	pods, ok := f[nodeName]
+	return pods, ok
+}
+
+func (f fakePodsDataSource) ListNodes() []string {
+	nodes := make([]string, 0, len(f))
+	for nodeName := range f {
+		nodes = append(nodes, nodeName)
+	}
+	return nodes
+}
+
+func (f fakePodsDataSource) StartedContainersTotal(nodeName string) int64 {
+	return 0
+}
+
+func TestInstallNodePods(t *testing.T) {
+	dataSource := fakePodsDataSource{
+		"node-0": {
+			{Namespace: "default", Name: "pod-0"},
+			{Namespace: "kube-system", Name: "pod-1"},
+		},
+		"node-1": {},
+	}
+	tests := []struct {
+		name string
+		path string
+		want []log.ObjectRef
+	}{
+		{
+			name: "node with pods",
+			path: "/nodes/node-0/pods",
+			want: []log.ObjectRef{
+				{Namespace: "default", Name: "pod-0"},
+				{Namespace: "kube-system", Name: "pod-1"},
+			},
+		},
+		{
+			name: "node without pods",
+			path: "/nodes/node-1/pods",
+			want: []log.ObjectRef{},
+		},
+		{
+			name: "unknown node",
+			path: "/nodes/node-2/pods",
+			want: []log.ObjectRef{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				restfulCont: restful.NewContainer(),
+				dataSource:  dataSource,
+			}
+			s.InstallNodePods()
+
+			rec := httptest.NewRecorder()
+			s.restfulCont.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("want status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+			}
+
+			got := []log.ObjectRef{}
+			err := json.Unmarshal(rec.Body.Bytes(), &got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
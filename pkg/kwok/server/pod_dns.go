@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kwok/pkg/utils/poddns"
+)
+
+// PodDNSWebhookPath is the path of the mutating admission webhook that sets the DNS settings on the pods
+const PodDNSWebhookPath = "/mutate/pods/dns"
+
+// InstallPodDNSWebhook installs the mutating admission webhook that sets the DNS settings
+// on the created pods that do not have one, the pods are allowed even if they cannot be mutated.
+func (s *Server) InstallPodDNSWebhook(dns poddns.Config) {
+	ws := new(restful.WebService)
+	ws.Path(PodDNSWebhookPath).
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+	ws.Route(ws.POST("").
+		To(func(req *restful.Request, resp *restful.Response) {
+			s.mutatePodDNS(dns, req, resp)
+		}).
+		Operation("mutatePodDNS"))
+	s.restfulCont.Add(ws)
+}
+
+func (s *Server) mutatePodDNS(dns poddns.Config, req *restful.Request, resp *restful.Response) {
+	review := admissionv1.AdmissionReview{}
+	err := req.ReadEntity(&review)
+	if err != nil {
+		_ = resp.WriteError(http.StatusBadRequest, err)
+		return
+	}
+	if review.Request == nil {
+		_ = resp.WriteError(http.StatusBadRequest, fmt.Errorf("admission review without request"))
+		return
+	}
+
+	review.Response = podDNSAdmissionResponse(dns, review.Request)
+	review.Request = nil
+	_ = resp.WriteEntity(review)
+}
+
+// podDNSAdmissionResponse returns the response with the patch to set the DNS settings on the created pod
+func podDNSAdmissionResponse(dns poddns.Config, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	response := &admissionv1.AdmissionResponse{
+		UID:     req.UID,
+		Allowed: true,
+	}
+	if req.Operation != admissionv1.Create ||
+		req.Resource.Group != "" || req.Resource.Resource != "pods" || req.SubResource != "" {
+		return response
+	}
+
+	pod := corev1.Pod{}
+	err := json.Unmarshal(req.Object.Raw, &pod)
+	if err != nil {
+		response.Warnings = []string{fmt.Sprintf("kwok: failed to decode pod to set the dns: %v", err)}
+		return response
+	}
+
+	if !dns.Apply(&pod) {
+		return response
+	}
+
+	// The add operation replaces the member if it exists.
+	patch, err := json.Marshal([]map[string]any{
+		{"op": "add", "path": "/spec/dnsPolicy", "value": pod.Spec.DNSPolicy},
+		{"op": "add", "path": "/spec/dnsConfig", "value": pod.Spec.DNSConfig},
+	})
+	if err != nil {
+		response.Result = &metav1.Status{Message: err.Error()}
+		return response
+	}
+	patchType := admissionv1.PatchTypeJSONPatch
+	response.Patch = patch
+	response.PatchType = &patchType
+	return response
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/emicklei/go-restful/v3"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/kwok/pkg/utils/poddns"
+)
+
+func TestInstallPodDNSWebhook(t *testing.T) {
+	dns := poddns.Config{
+		Policy:      string(corev1.DNSNone),
+		Nameservers: []string{"10.96.0.10"},
+		Options:     []string{"ndots:5"},
+	}
+	podsResource := metav1.GroupVersionResource{Version: "v1", Resource: "pods"}
+	tests := []struct {
+		name      string
+		operation admissionv1.Operation
+		resource  metav1.GroupVersionResource
+		pod       corev1.Pod
+		wantPatch string
+	}{
+		{
+			name:      "created pod with the default dns",
+			operation: admissionv1.Create,
+			resource:  podsResource,
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{DNSPolicy: corev1.DNSClusterFirst},
+			},
+			wantPatch: `[{"op":"add","path":"/spec/dnsPolicy","value":"None"},{"op":"add","path":"/spec/dnsConfig","value":{"nameservers":["10.96.0.10"],"options":[{"name":"ndots","value":"5"}]}}]`,
+		},
+		{
+			name:      "created pod with its own dns config",
+			operation: admissionv1.Create,
+			resource:  podsResource,
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{
+					DNSPolicy: corev1.DNSClusterFirst,
+					DNSConfig: &corev1.PodDNSConfig{Searches: []string{"example.com"}},
+				},
+			},
+		},
+		{
+			name:      "updated pod",
+			operation: admissionv1.Update,
+			resource:  podsResource,
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{DNSPolicy: corev1.DNSClusterFirst},
+			},
+		},
+		{
+			name:      "other resource",
+			operation: admissionv1.Create,
+			resource:  metav1.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				restfulCont: restful.NewContainer(),
+			}
+			s.InstallPodDNSWebhook(dns)
+
+			raw, err := json.Marshal(tt.pod)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := json.Marshal(admissionv1.AdmissionReview{
+				TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+				Request: &admissionv1.AdmissionRequest{
+					UID:       "uid-0",
+					Operation: tt.operation,
+					Resource:  tt.resource,
+					Object:    runtime.RawExtension{Raw: raw},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, PodDNSWebhookPath, bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			s.restfulCont.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("want status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+			}
+
+			got := admissionv1.AdmissionReview{}
+			err = json.Unmarshal(rec.Body.Bytes(), &got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Response == nil {
+				t.Fatalf("want response, got none")
+			}
+			if got.Response.UID != "uid-0" || !got.Response.Allowed {
+				t.Errorf("want allowed response for uid-0, got %+v", got.Response)
+			}
+			if string(got.Response.Patch) != tt.wantPatch {
+				t.Errorf("want patch %s, got %s", tt.wantPatch, got.Response.Patch)
+			}
+			if (tt.wantPatch != "") != (got.Response.PatchType != nil) {
+				t.Errorf("want patch type only with patch, got %v", got.Response.PatchType)
+			}
+		})
+	}
+}
@@ -17,11 +17,47 @@ limitations under the License.
 package server
 
 import (
+	"context"
+	"errors"
+	"net"
 	"net/http"
 	"net/http/pprof"
 	"runtime"
+	"time"
+
+	"sigs.k8s.io/kwok/pkg/log"
+)
+
+var (
+	setBlockProfileRate     = runtime.SetBlockProfileRate
+	setMutexProfileFraction = runtime.SetMutexProfileFraction
 )
 
+// startContentionProfiling enables the block and mutex profiling like the kubelet,
+// so that the contention can be seen in /debug/pprof/block and /debug/pprof/mutex.
+func startContentionProfiling() {
+	setBlockProfileRate(1)
+	setMutexProfileFraction(1)
+}
+
+// handleRegistrar is the mux the handlers of the /debug/pprof endpoint are registered on
+type handleRegistrar interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+// installProfiling registers the handlers of the /debug/pprof endpoint on the mux,
+// and starts the contention profiling if it is enabled.
+func installProfiling(mux handleRegistrar, enableContentionProfiling bool) {
+	mux.Handle(pprofBasePath, http.HandlerFunc(pprof.Index))
+	mux.Handle(pprofBasePath+"cmdline", http.HandlerFunc(pprof.Cmdline))
+	mux.Handle(pprofBasePath+"profile", http.HandlerFunc(pprof.Profile))
+	mux.Handle(pprofBasePath+"symbol", http.HandlerFunc(pprof.Symbol))
+	mux.Handle(pprofBasePath+"trace", http.HandlerFunc(pprof.Trace))
+	if enableContentionProfiling {
+		startContentionProfiling()
+	}
+}
+
 // InstallProfilingHandler registers the HTTP request patterns for /debug/pprof endpoint.
 func (s *Server) InstallProfilingHandler(enableProfilingLogHandler bool, enableContentionProfiling bool) {
 	if !enableProfilingLogHandler {
@@ -30,8 +66,35 @@ func (s *Server) InstallProfilingHandler(enableProfilingLogHandler bool, enableC
 	}
 
 	// Setup pprof handlers.
-	s.restfulCont.Handle(pprofBasePath, http.HandlerFunc(pprof.Index))
-	if enableContentionProfiling {
-		runtime.SetBlockProfileRate(1)
+	installProfiling(s.restfulCont, enableContentionProfiling)
+}
+
+// RunProfilingServer serves the /debug/pprof endpoint on a dedicated address until the context is done.
+func RunProfilingServer(ctx context.Context, address string, enableContentionProfiling bool) error {
+	mux := http.NewServeMux()
+	installProfiling(mux, enableContentionProfiling)
+
+	svc := &http.Server{
+		ReadHeaderTimeout: 5 * time.Second,
+		BaseContext: func(_ net.Listener) context.Context {
+			return ctx
+		},
+		Addr:    address,
+		Handler: mux,
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = svc.Close()
+	}()
+
+	logger := log.FromContext(ctx)
+	logger.Info("Starting profiling server",
+		"address", address,
+	)
+	err := svc.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
 	}
+	return nil
 }
@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/emicklei/go-restful/v3"
+)
+
+func TestInstallProfilingHandlerContentionProfiling(t *testing.T) {
+	tests := []struct {
+		name                      string
+		enableProfilingHandler    bool
+		enableContentionProfiling bool
+		wantStatus                int
+		wantRate                  int
+	}{
+		{
+			name:       "profiling disabled",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:                      "contention profiling without profiling handler",
+			enableContentionProfiling: true,
+			wantStatus:                http.StatusMethodNotAllowed,
+		},
+		{
+			name:                   "profiling without contention profiling",
+			enableProfilingHandler: true,
+			wantStatus:             http.StatusOK,
+		},
+		{
+			name:                      "contention profiling",
+			enableProfilingHandler:    true,
+			enableContentionProfiling: true,
+			wantStatus:                http.StatusOK,
+			wantRate:                  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var blockRate, mutexFraction int
+			oldSetBlockProfileRate, oldSetMutexProfileFraction := setBlockProfileRate, setMutexProfileFraction
+			defer func() {
+				setBlockProfileRate, setMutexProfileFraction = oldSetBlockProfileRate, oldSetMutexProfileFraction
+			}()
+			setBlockProfileRate = func(rate int) {
+				blockRate = rate
+			}
+			setMutexProfileFraction = func(rate int) int {
+				mutexFraction = rate
+				return 0
+			}
+
+			s := &Server{
+				restfulCont: restful.NewContainer(),
+			}
+			s.InstallProfilingHandler(tt.enableProfilingHandler, tt.enableContentionProfiling)
+
+			if blockRate != tt.wantRate {
+				t.Errorf("want block profile rate %d, got %d", tt.wantRate, blockRate)
+			}
+			if mutexFraction != tt.wantRate {
+				t.Errorf("want mutex profile fraction %d, got %d", tt.wantRate, mutexFraction)
+			}
+
+			rec := httptest.NewRecorder()
+			s.restfulCont.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, pprofBasePath, nil))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("want status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestInstallProfiling(t *testing.T) {
+	mux := http.NewServeMux()
+	installProfiling(mux, false)
+
+	for _, path := range []string{pprofBasePath, pprofBasePath + "cmdline", pprofBasePath + "symbol", pprofBasePath + "heap"} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("want status %d for %s, got %d", http.StatusOK, path, rec.Code)
+		}
+	}
+}
@@ -73,7 +73,8 @@ type Server struct {
 	resourceUsages        resources.Getter[[]*internalversion.ResourceUsage]
 	metrics               resources.Getter[[]*internalversion.Metric]
 
-	metricsUpdateHandler maps.SyncMap[string, *metrics.UpdateHandler]
+	metricsUpdateHandler      maps.SyncMap[string, *metrics.UpdateHandler]
+	disabledMetricsDimensions []internalversion.Dimension
 
 	cumulatives    map[string]cumulative
 	cumulativesMut sync.Mutex
@@ -109,6 +110,9 @@ type Config struct {
 	ResourceUsages        []*internalversion.ResourceUsage
 	Metrics               []*internalversion.Metric
 
+	// DisabledMetricsDimensions is the dimensions of the metrics that are not registered and emitted
+	DisabledMetricsDimensions []internalversion.Dimension
+
 	DataSource      DataSource
 	NodeCacheGetter informer.Getter[*corev1.Node]
 	PodCacheGetter  informer.Getter[*corev1.Pod]
@@ -137,6 +141,8 @@ func NewServer(conf Config) (*Server, error) {
 		resourceUsages:        resources.NewStaticGetter(conf.ResourceUsages),
 		metrics:               resources.NewStaticGetter(conf.Metrics),
 
+		disabledMetricsDimensions: conf.DisabledMetricsDimensions,
+
 		cumulatives: map[string]cumulative{},
 
 		dataSource:      conf.DataSource,
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rotate implements the cert rotate command
+package rotate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/consts"
+	"sigs.k8s.io/kwok/pkg/kwokctl/components"
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
+	"sigs.k8s.io/kwok/pkg/kwokctl/pki"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+	"sigs.k8s.io/kwok/pkg/log"
+)
+
+type flagpole struct {
+	Name string
+	CA   bool
+}
+
+// NewCommand returns a new cobra.Command for cert rotate
+func NewCommand(ctx context.Context) *cobra.Command {
+	flags := &flagpole{}
+
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "rotate",
+		Short: "Regenerate the admin certificate and restart the components using it",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags.Name = config.DefaultCluster
+			return runE(cmd.Context(), flags)
+		},
+	}
+	cmd.Flags().BoolVar(&flags.CA, "ca", flags.CA, "Regenerate the CA too, all components are restarted")
+	return cmd
+}
+
+func runE(ctx context.Context, flags *flagpole) error {
+	name := config.ClusterName(flags.Name)
+	workdir := config.ClusterWorkdir(flags.Name)
+
+	logger := log.FromContext(ctx)
+	logger = logger.With("cluster", flags.Name)
+	ctx = log.NewContext(ctx, logger)
+
+	rt, err := runtime.DefaultRegistry.Load(ctx, name, workdir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Cluster does not exist")
+		}
+		return err
+	}
+
+	conf, err := rt.Config(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The kube-apiserver of kind uses the certificates generated by kubeadm, which are signed by the CA.
+	inKind := components.GetRuntimeMode(conf.Options.Runtime) == components.RuntimeModeCluster
+	if flags.CA && inKind {
+		return fmt.Errorf("rotating the CA is not supported by the %s runtime", conf.Options.Runtime)
+	}
+
+	pkiPath := rt.GetWorkdirPath(runtime.PkiName)
+	if dryrun.DryRun {
+		dryrun.PrintMessage("# Rotate PKI in %s", pkiPath)
+	} else {
+		err = pki.RotatePki(pkiPath, flags.CA)
+		if err != nil {
+			return fmt.Errorf("failed to rotate pki: %w", err)
+		}
+	}
+	logger.Info("Rotated certificates", "ca", flags.CA)
+
+	// The kubeconfigs refer to the certificates by path, so they don't need to be rewritten,
+	// but the components need to be restarted to load the new certificates.
+	switch {
+	case flags.CA:
+		err = rt.Stop(ctx)
+		if err != nil {
+			return err
+		}
+		err = rt.Start(ctx)
+		if err != nil {
+			return err
+		}
+		logger.Info("Restarted cluster")
+	case inKind:
+		err = restartComponent(ctx, rt, consts.ComponentKwokController)
+		if err != nil {
+			return err
+		}
+	default:
+		// The kube-apiserver serves with the admin certificate.
+		err = restartComponent(ctx, rt, consts.ComponentKubeApiserver)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func restartComponent(ctx context.Context, rt runtime.Runtime, name string) error {
+	err := rt.StopComponent(ctx, name)
+	if err != nil {
+		return err
+	}
+	err = rt.StartComponent(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	logger := log.FromContext(ctx)
+	logger.Info("Restarted component", "component", name)
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cluster contains a command to clone the config of a cluster to a new name.
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/consts"
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/file"
+	"sigs.k8s.io/kwok/pkg/utils/path"
+)
+
+type flagpole struct {
+	Name   string
+	Rename string
+	Output string
+}
+
+// NewCommand returns a new cobra.Command for cluster cloning
+func NewCommand(ctx context.Context) *cobra.Command {
+	flags := &flagpole{}
+
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "cluster",
+		Short: "Clone the config of a cluster to a new name without creating it",
+		Long: "Clone the config of a cluster to a new name without creating it, " +
+			"the ports are reallocated and the PKI is generated when the new cluster is created with the config.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags.Name = config.DefaultCluster
+			return runE(cmd.Context(), flags)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Rename, "rename", "", "Name of the new cluster")
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "", "Path to write the config of the new cluster to, default is stdout")
+	return cmd
+}
+
+func runE(ctx context.Context, flags *flagpole) error {
+	if flags.Rename == "" {
+		return fmt.Errorf("--rename is required")
+	}
+	if flags.Rename == flags.Name {
+		return fmt.Errorf("the new name %q is the same as the cluster", flags.Rename)
+	}
+	err := config.ValidateClusterName(flags.Rename)
+	if err != nil {
+		return err
+	}
+
+	workdir := config.ClusterWorkdir(flags.Name)
+	newWorkdir := config.ClusterWorkdir(flags.Rename)
+
+	logger := log.FromContext(ctx)
+	logger = logger.With("cluster", flags.Name)
+	ctx = log.NewContext(ctx, logger)
+
+	if file.Exists(path.Join(newWorkdir, consts.ConfigName)) {
+		return fmt.Errorf("cluster %q already exists", flags.Rename)
+	}
+
+	objs, err := config.Load(ctx, path.Join(workdir, consts.ConfigName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Cluster does not exist")
+		}
+		return err
+	}
+
+	err = runtime.CloneConfig(ctx, objs, workdir, newWorkdir, runtime.GetUsedPorts(ctx))
+	if err != nil {
+		return err
+	}
+
+	if flags.Output == "" {
+		if dryrun.DryRun {
+			dryrun.PrintMessage("# Display the config of cluster %s", flags.Rename)
+			return nil
+		}
+		return config.SaveTo(ctx, os.Stdout, objs)
+	}
+
+	output, err := path.Expand(flags.Output)
+	if err != nil {
+		return err
+	}
+	if dryrun.DryRun {
+		dryrun.PrintMessage("# Save the config of cluster %s to %s", flags.Rename, output)
+		return nil
+	}
+	err = config.Save(ctx, output, objs)
+	if err != nil {
+		return err
+	}
+	logger.Info("Cloned cluster config, create the cluster with it by",
+		"command", fmt.Sprintf("kwokctl create cluster --name=%s --config=%s", flags.Rename, output),
+	)
+	return nil
+}
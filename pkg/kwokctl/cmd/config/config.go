@@ -22,6 +22,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/config/dump"
+	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/config/merge"
 	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/config/reset"
 	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/config/tidy"
 	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/config/view"
@@ -32,12 +34,14 @@ func NewCommand(ctx context.Context) *cobra.Command {
 	cmd := &cobra.Command{
 		Args:  cobra.NoArgs,
 		Use:   "config [command]",
-		Short: "Manage [reset, tidy, view] default config",
+		Short: "Manage [dump, merge, reset, tidy, view] config",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
 		},
 	}
 
+	cmd.AddCommand(dump.NewCommand(ctx))
+	cmd.AddCommand(merge.NewCommand(ctx))
 	cmd.AddCommand(reset.NewCommand(ctx))
 	cmd.AddCommand(tidy.NewCommand(ctx))
 	cmd.AddCommand(view.NewCommand(ctx))
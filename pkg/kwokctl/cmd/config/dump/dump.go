@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dump provides the kwokctl config dump command.
+package dump
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
+	"sigs.k8s.io/kwok/pkg/utils/printers"
+)
+
+type flagpole struct {
+	Output string
+}
+
+// NewCommand returns a new cobra.Command for config dump
+func NewCommand(ctx context.Context) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "dump",
+		Short: "Display the resolved kwokctl configuration with all the defaults applied",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(cmd.Context(), flags)
+		},
+	}
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "yaml", "Output format (yaml, json)")
+	return cmd
+}
+
+func runE(ctx context.Context, flags *flagpole) error {
+	if dryrun.DryRun {
+		dryrun.PrintMessage("# Displaying resolved kwokctl configuration")
+		return nil
+	}
+	conf := config.GetKwokctlConfiguration(ctx)
+	return dumpConfig(os.Stdout, flags.Output, conf)
+}
+
+// dumpConfig prints the internal configuration, converted back to the external version for serialization
+func dumpConfig(w io.Writer, output string, conf *internalversion.KwokctlConfiguration) error {
+	versioned, err := internalversion.ConvertToV1alpha1KwokctlConfiguration(conf)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		output = printers.OutputYAML
+	}
+	return printers.WriteObject(w, output, versioned)
+}
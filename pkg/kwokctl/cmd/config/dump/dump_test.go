@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	configv1alpha1 "sigs.k8s.io/kwok/pkg/apis/config/v1alpha1"
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/config"
+)
+
+func TestDumpConfig(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "kwok.yaml")
+	err := os.WriteFile(p, []byte(`
+apiVersion: config.kwok.x-k8s.io/v1alpha1
+kind: KwokctlConfiguration
+options:
+  kubeApiserverPort: 32766
+`), 0640)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objs, err := config.Load(context.Background(), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	confs := config.FilterWithType[*internalversion.KwokctlConfiguration](objs)
+	if len(confs) != 1 {
+		t.Fatalf("want 1 configuration, got %d", len(confs))
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{
+			name:   "yaml",
+			output: "yaml",
+			want:   []string{"kind: KwokctlConfiguration", "kubeApiserverPort: 32766", "kubeVersion: "},
+		},
+		{
+			name:   "json",
+			output: "json",
+			want:   []string{`"kind": "KwokctlConfiguration"`, `"kubeApiserverPort": 32766`, `"kubeVersion": `},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			err := dumpConfig(out, tt.output, confs[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("want %q in %s", want, out.String())
+				}
+			}
+		})
+	}
+
+	out := bytes.NewBuffer(nil)
+	err = dumpConfig(out, "json", confs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got configv1alpha1.KwokctlConfiguration
+	err = json.Unmarshal(out.Bytes(), &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Options.KubeApiserverPort != 32766 {
+		t.Errorf("want kubeApiserverPort 32766, got %d", got.Options.KubeApiserverPort)
+	}
+	if got.Options.KubeVersion == "" {
+		t.Errorf("want kubeVersion to be defaulted")
+	}
+
+	err = dumpConfig(out, "table", confs[0])
+	if err == nil {
+		t.Errorf("want error for unknown output format")
+	}
+}
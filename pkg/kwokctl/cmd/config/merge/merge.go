@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package merge provides the kwokctl config merge command.
+package merge
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
+	"sigs.k8s.io/kwok/pkg/utils/path"
+)
+
+type flagpole struct {
+	Output string
+}
+
+// NewCommand returns a new cobra.Command for config merge
+func NewCommand(ctx context.Context) *cobra.Command {
+	flags := &flagpole{}
+
+	cmd := &cobra.Command{
+		Args:  cobra.MinimumNArgs(1),
+		Use:   "merge [file...]",
+		Short: "Merge the specified config files into a single one, the later files take precedence. It does not touch the default config file.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(cmd.Context(), flags, args)
+		},
+	}
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", flags.Output, "Path of the merged config file, it will be written to stdout if not specified")
+	return cmd
+}
+
+func runE(ctx context.Context, flags *flagpole, args []string) error {
+	srcs := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "-" {
+			srcs = append(srcs, arg)
+			continue
+		}
+		p, err := path.Expand(arg)
+		if err != nil {
+			return err
+		}
+		srcs = append(srcs, p)
+	}
+
+	if dryrun.DryRun {
+		if flags.Output == "" {
+			dryrun.PrintMessage("# Merge config files %v", srcs)
+		} else {
+			dryrun.PrintMessage("# Merge config files %v into %s", srcs, flags.Output)
+		}
+		return nil
+	}
+
+	objs, err := config.Load(ctx, srcs...)
+	if err != nil {
+		return err
+	}
+	objs = config.Unique(objs)
+
+	if flags.Output == "" {
+		return config.SaveTo(ctx, os.Stdout, objs)
+	}
+
+	output, err := path.Expand(flags.Output)
+	if err != nil {
+		return err
+	}
+	return config.Save(ctx, output, objs)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package node implements the cordon node and uncordon node commands
+package node
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/kwokctl/drain"
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/client"
+)
+
+type flagpole struct {
+	Name string
+}
+
+// NewCommand returns a new cobra.Command for cordon node
+func NewCommand(ctx context.Context) *cobra.Command {
+	return newCommand(ctx, true, "Mark the node as unschedulable")
+}
+
+// NewUncordonCommand returns a new cobra.Command for uncordon node
+func NewUncordonCommand(ctx context.Context) *cobra.Command {
+	return newCommand(ctx, false, "Mark the node as schedulable")
+}
+
+func newCommand(ctx context.Context, unschedulable bool, short string) *cobra.Command {
+	flags := &flagpole{}
+
+	cmd := &cobra.Command{
+		Args:  cobra.ExactArgs(1),
+		Use:   "node [name]",
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags.Name = config.DefaultCluster
+			return runE(cmd.Context(), flags, args[0], unschedulable)
+		},
+	}
+	return cmd
+}
+
+func runE(ctx context.Context, flags *flagpole, nodeName string, unschedulable bool) error {
+	name := config.ClusterName(flags.Name)
+	workdir := config.ClusterWorkdir(flags.Name)
+
+	logger := log.FromContext(ctx)
+	logger = logger.With("cluster", flags.Name)
+	ctx = log.NewContext(ctx, logger)
+
+	rt, err := runtime.DefaultRegistry.Load(ctx, name, workdir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Cluster does not exist")
+		}
+		return err
+	}
+
+	kubeconfigPath := rt.GetWorkdirPath(runtime.InHostKubeconfigName)
+	clientset, err := client.NewClientset("", kubeconfigPath)
+	if err != nil {
+		return err
+	}
+	restConfig, err := clientset.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	typedClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	return cordon(ctx, typedClient, nodeName, unschedulable, dryrun.DryRun)
+}
+
+// cordon marks the node as unschedulable or schedulable,
+// only the equivalent kubectl command is printed in dry-run mode.
+func cordon(ctx context.Context, clientset kubernetes.Interface, nodeName string, unschedulable bool, dryRun bool) error {
+	verb := "uncordon"
+	if unschedulable {
+		verb = "cordon"
+	}
+
+	if dryRun {
+		dryrun.PrintMessage("kubectl %s %s", verb, nodeName)
+		return nil
+	}
+
+	err := drain.Cordon(ctx, clientset, nodeName, unschedulable)
+	if err != nil {
+		return err
+	}
+
+	logger := log.FromContext(ctx)
+	if unschedulable {
+		logger.Info("Cordoned node", "node", nodeName)
+	} else {
+		logger.Info("Uncordoned node", "node", nodeName)
+	}
+	return nil
+}
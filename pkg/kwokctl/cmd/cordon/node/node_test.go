@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCordon(t *testing.T) {
+	tests := []struct {
+		name              string
+		nodeName          string
+		unschedulable     bool
+		dryRun            bool
+		initUnschedulable bool
+		want              bool
+		wantErr           bool
+	}{
+		{
+			name:          "cordon",
+			nodeName:      "node-0",
+			unschedulable: true,
+			want:          true,
+		},
+		{
+			name:              "uncordon",
+			nodeName:          "node-0",
+			initUnschedulable: true,
+			want:              false,
+		},
+		{
+			name:          "cordon with dry run",
+			nodeName:      "node-0",
+			unschedulable: true,
+			dryRun:        true,
+			want:          false,
+		},
+		{
+			name:              "uncordon with dry run",
+			nodeName:          "node-0",
+			initUnschedulable: true,
+			dryRun:            true,
+			want:              true,
+		},
+		{
+			name:          "node not found",
+			nodeName:      "node-1",
+			unschedulable: true,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-0"},
+				Spec: corev1.NodeSpec{
+					Unschedulable: tt.initUnschedulable,
+				},
+			})
+
+			err := cordon(context.Background(), clientset, tt.nodeName, tt.unschedulable, tt.dryRun)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("cordon() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if tt.dryRun && len(clientset.Actions()) != 0 {
+				t.Errorf("want no requests in dry run, got %v", clientset.Actions())
+			}
+
+			node, err := clientset.CoreV1().Nodes().Get(context.Background(), "node-0", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if node.Spec.Unschedulable != tt.want {
+				t.Errorf("want unschedulable %v, got %v", tt.want, node.Spec.Unschedulable)
+			}
+		})
+	}
+}
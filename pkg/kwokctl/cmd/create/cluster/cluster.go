@@ -29,10 +29,16 @@ import (
 	"sigs.k8s.io/kwok/pkg/apis/internalversion"
 	"sigs.k8s.io/kwok/pkg/config"
 	"sigs.k8s.io/kwok/pkg/consts"
+	"sigs.k8s.io/kwok/pkg/kwokctl/components"
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
+	"sigs.k8s.io/kwok/pkg/kwokctl/pki"
 	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+	"sigs.k8s.io/kwok/pkg/kwokctl/snapshot"
 	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/file"
 	"sigs.k8s.io/kwok/pkg/utils/kubeconfig"
 	"sigs.k8s.io/kwok/pkg/utils/path"
+	"sigs.k8s.io/kwok/pkg/utils/slices"
 )
 
 type flagpole struct {
@@ -40,8 +46,12 @@ type flagpole struct {
 	Timeout    time.Duration
 	Wait       time.Duration
 	Kubeconfig string
+	Workdir    string
 	ExtraArgs  []string
 
+	FromSnapshot       string
+	FromSnapshotFormat string
+
 	*internalversion.KwokctlConfiguration
 }
 
@@ -62,11 +72,17 @@ func NewCommand(ctx context.Context) *cobra.Command {
 	}
 
 	cmd.Flags().Uint32Var(&flags.Options.KubeApiserverPort, "kube-apiserver-port", flags.Options.KubeApiserverPort, `Port of the apiserver (default random)`)
+	cmd.Flags().StringVar(&flags.Options.KubeApiserverInClusterAddress, "kube-apiserver-in-cluster-address", flags.Options.KubeApiserverInClusterAddress, `Address of the apiserver used by the components within the network, a host name is checked to resolve on the network after the cluster starts, only for docker/podman/nerdctl runtime (default '${CLUSTER_NAME}-kube-apiserver')`)
 	cmd.Flags().Uint32Var(&flags.Options.KubeApiserverInsecurePort, "kube-apiserver-insecure-port", flags.Options.KubeApiserverInsecurePort, `Insecure port of the apiserver`)
+	cmd.Flags().Uint32Var(&flags.Options.KubeApiserverReadOnlyPort, "readonly-port", flags.Options.KubeApiserverReadOnlyPort, `Deprecated and insecure, port of the legacy insecure port of the apiserver itself, which serves without authentication and authorization, only available with --secure-port before Kubernetes 1.20.0`)
+	cmd.Flags().BoolVar(&flags.Options.KubeApiserverUnixSocketProxy, "apiserver-proxy", flags.Options.KubeApiserverUnixSocketProxy, `Additionally expose the apiserver over a unix socket in the workdir, only for binary runtime`)
 	cmd.Flags().Uint32Var(&flags.Options.PrometheusPort, "prometheus-port", flags.Options.PrometheusPort, `Port to expose Prometheus metrics`)
 	cmd.Flags().Uint32Var(&flags.Options.JaegerPort, "jaeger-port", flags.Options.JaegerPort, `Port to expose Jaeger UI`)
 	cmd.Flags().BoolVar(&flags.Options.SecurePort, "secure-port", flags.Options.SecurePort, `The apiserver port on which to serve HTTPS with authentication and authorization, is not available before Kubernetes 1.13.0`)
 	cmd.Flags().BoolVar(&flags.Options.QuietPull, "quiet-pull", flags.Options.QuietPull, `Pull without printing progress information`)
+	cmd.Flags().Uint32Var(&flags.Options.NetworkMTU, "network-mtu", flags.Options.NetworkMTU, `MTU of the network created by the compose runtime (default runtime default)`)
+	cmd.Flags().StringVar(&flags.Options.LogDriver, "log-driver", flags.Options.LogDriver, `Logging driver of the containers created by the compose runtime (default runtime default)`)
+	cmd.Flags().StringArrayVar(&flags.Options.LogOpts, "log-opt", flags.Options.LogOpts, `Options of the logging driver in the form of key=value, only for the compose runtime`)
 	cmd.Flags().StringVar(&flags.Options.KubeSchedulerConfig, "kube-scheduler-config", flags.Options.KubeSchedulerConfig, `Path to a kube-scheduler configuration file`)
 	cmd.Flags().BoolVar(&flags.Options.DisableKubeScheduler, "disable-kube-scheduler", flags.Options.DisableKubeScheduler, `Disable the kube-scheduler`)
 	cmd.Flags().BoolVar(&flags.Options.DisableKubeControllerManager, "disable-kube-controller-manager", flags.Options.DisableKubeControllerManager, `Disable the kube-controller-manager`)
@@ -99,6 +115,7 @@ func NewCommand(ctx context.Context) *cobra.Command {
 '${KWOK_JAEGER_IMAGE_PREFIX}/all-in-one:${KWOK_JAEGER_VERSION}'
 `)
 	cmd.Flags().Uint32Var(&flags.Options.KwokControllerPort, "controller-port", flags.Options.KwokControllerPort, `Port of kwok-controller given to the host`)
+	cmd.Flags().Uint32Var(&flags.Options.KwokControllerProfilingPort, "controller-profiling-port", flags.Options.KwokControllerProfilingPort, `Port of kwok-controller profiling given to the host, the /debug/pprof is served on it if it is not zero`)
 	cmd.Flags().StringVar(&flags.Options.KindNodeImage, "kind-node-image", flags.Options.KindNodeImage, `Image of kind node, only for kind/kind-podman runtime
 '${KWOK_KIND_NODE_IMAGE_PREFIX}/node:${KWOK_KUBE_VERSION}'
 `)
@@ -132,18 +149,49 @@ func NewCommand(ctx context.Context) *cobra.Command {
 `)
 	cmd.Flags().StringVar(&flags.Options.KubeFeatureGates, "kube-feature-gates", flags.Options.KubeFeatureGates, `A set of key=value pairs that describe feature gates for alpha/experimental features of Kubernetes`)
 	cmd.Flags().StringVar(&flags.Options.KubeRuntimeConfig, "kube-runtime-config", flags.Options.KubeRuntimeConfig, `A set of key=value pairs that enable or disable built-in APIs`)
+	cmd.Flags().StringVar(&flags.Options.KubeServiceClusterIPRange, "kube-service-cluster-ip-range", flags.Options.KubeServiceClusterIPRange, `A CIDR range from which to assign service cluster IPs, a pair of CIDRs separated by a comma for dual-stack`)
 	cmd.Flags().StringVar(&flags.Options.KubeAuditPolicy, "kube-audit-policy", flags.Options.KubeAuditPolicy, "Path to the file that defines the audit policy configuration")
+	cmd.Flags().StringVar(&flags.Options.KubeAuditWebhook, "kube-audit-webhook", flags.Options.KubeAuditWebhook, "URL of the endpoint that the audit events are sent to, it requires --kube-audit-policy")
+	cmd.Flags().Int32Var(&flags.Options.KubeAuditLogMaxSize, "kube-audit-log-maxsize", flags.Options.KubeAuditLogMaxSize, "Maximum size in megabytes of the audit log before it gets rotated, 0 means never rotated, only available for binary runtime")
+	cmd.Flags().Int32Var(&flags.Options.KubeAuditLogMaxAge, "kube-audit-log-maxage", flags.Options.KubeAuditLogMaxAge, "Maximum number of days to retain the rotated audit logs, 0 means no limit")
+	cmd.Flags().Int32Var(&flags.Options.KubeAuditLogMaxBackup, "kube-audit-log-maxbackup", flags.Options.KubeAuditLogMaxBackup, "Maximum number of the rotated audit logs to retain, 0 means no limit")
+	cmd.Flags().StringVar(&flags.Options.CABundle, "ca-bundle", flags.Options.CABundle, "Path to the PEM bundle of the CAs trusted by the components for their outgoing TLS connections, it replaces the system trust store of the components")
 	cmd.Flags().BoolVar(&flags.Options.KubeAuthorization, "kube-authorization", flags.Options.KubeAuthorization, "Enable authorization for kube-apiserver, only for non kind/kind-podman runtime")
 	cmd.Flags().BoolVar(&flags.Options.KubeAdmission, "kube-admission", flags.Options.KubeAdmission, "Enable admission for kube-apiserver, only for non kind/kind-podman runtime")
+	cmd.Flags().StringVar(&flags.Options.KubeAdmissionPlugins, "kube-admission-plugins", flags.Options.KubeAdmissionPlugins, "A set of admission plugins to enable for kube-apiserver, e.g. ResourceQuota,LimitRanger. Without --kube-admission only these plugins are enabled, only for non kind/kind-podman runtime")
 	cmd.Flags().StringVar(&flags.Options.Runtime, "runtime", flags.Options.Runtime, fmt.Sprintf("Runtime of the cluster (%s)", strings.Join(runtime.DefaultRegistry.List(), " or ")))
 	cmd.Flags().DurationVar(&flags.Timeout, "timeout", 0, "Timeout for waiting for the cluster to be created")
 	cmd.Flags().DurationVar(&flags.Wait, "wait", 0, "Wait for the cluster to be ready")
 	cmd.Flags().StringVar(&flags.Kubeconfig, "kubeconfig", flags.Kubeconfig, "The path to the kubeconfig file will be added to the newly created cluster and set to current-context")
+	cmd.Flags().StringVar(&flags.Workdir, "workdir", "", "The path to the workdir of the cluster, instead of the default one under the home directory")
+	cmd.Flags().StringVar(&flags.Options.KubeApiserverTLSMinVersion, "kube-apiserver-tls-min-version", flags.Options.KubeApiserverTLSMinVersion, "Minimum TLS version supported by kube-apiserver, one of VersionTLS10, VersionTLS11, VersionTLS12 or VersionTLS13, only valid with --secure-port")
+	cmd.Flags().StringVar(&flags.Options.KubeApiserverTLSCipherSuites, "kube-apiserver-tls-cipher-suites", flags.Options.KubeApiserverTLSCipherSuites, "Comma-separated list of cipher suites for kube-apiserver, only valid with --secure-port")
+	cmd.Flags().StringVar(&flags.Options.KubeApiserverRequestTimeout, "kube-apiserver-request-timeout", flags.Options.KubeApiserverRequestTimeout, "Duration a handler of kube-apiserver must keep a request open before timing it out, e.g. 5m")
+	cmd.Flags().StringVar(&flags.Options.KubeApiserverMinRequestTimeout, "kube-apiserver-min-request-timeout", flags.Options.KubeApiserverMinRequestTimeout, "Minimum duration a watch request of kube-apiserver is kept open, e.g. 30m")
+	cmd.Flags().BoolVar(&flags.Options.KubeApiserverPriorityAndFairness, "kube-apiserver-priority-and-fairness", flags.Options.KubeApiserverPriorityAndFairness, "Enable API Priority and Fairness of kube-apiserver, it takes precedence over --disable-qps-limits for kube-apiserver")
+	cmd.Flags().Uint32Var(&flags.Options.KubeApiserverMaxRequestsInflight, "kube-apiserver-max-requests-inflight", flags.Options.KubeApiserverMaxRequestsInflight, "Maximum number of non-mutating requests in flight of kube-apiserver, only valid with --kube-apiserver-priority-and-fairness")
+	cmd.Flags().Uint32Var(&flags.Options.KubeApiserverMaxMutatingRequestsInflight, "kube-apiserver-max-mutating-requests-inflight", flags.Options.KubeApiserverMaxMutatingRequestsInflight, "Maximum number of mutating requests in flight of kube-apiserver, only valid with --kube-apiserver-priority-and-fairness")
 	cmd.Flags().BoolVar(&flags.Options.DisableQPSLimits, "disable-qps-limits", flags.Options.DisableQPSLimits, "Disable QPS limits for components")
 	cmd.Flags().StringSliceVar(&flags.Options.EnableCRDs, "enable-crds", flags.Options.EnableCRDs, "List of CRDs to enable")
 	cmd.Flags().UintVar(&flags.Options.NodeLeaseDurationSeconds, "node-lease-duration-seconds", flags.Options.NodeLeaseDurationSeconds, "Duration of node lease in seconds")
+	cmd.Flags().UintVar(&flags.Options.ManageNodesParallelism, "manage-nodes-parallelism", flags.Options.ManageNodesParallelism, "Number of the node stages that the kwok-controller plays in parallel, the default of the kwok-controller is used if it is zero")
 	cmd.Flags().Float64Var(&flags.Options.HeartbeatFactor, "heartbeat-factor", flags.Options.HeartbeatFactor, "Scale factor for all about heartbeat")
-	cmd.Flags().StringVar(&flags.Options.EtcdQuotaBackendSize, "etcd-quota-backend-size", flags.Options.EtcdQuotaBackendSize, "Quota backend size for etcd")
+	cmd.Flags().Float64Var(&flags.Options.NodeCPUOvercommit, "cpu-overcommit", flags.Options.NodeCPUOvercommit, "Factor to scale the cpu allocatable of the nodes relative to the capacity, e.g. 2 to fit twice as many pods, 0 disables it")
+	cmd.Flags().Float64Var(&flags.Options.NodeMemoryOvercommit, "memory-overcommit", flags.Options.NodeMemoryOvercommit, "Factor to scale the memory allocatable of the nodes relative to the capacity, e.g. 2 to fit twice as many pods, 0 disables it")
+	cmd.Flags().StringVar(&flags.Options.EtcdQuotaBackendSize, "etcd-quota-backend-size", flags.Options.EtcdQuotaBackendSize, "Quota backend size for etcd, 0 leaves the default quota of etcd")
+	cmd.Flags().StringSliceVar(&flags.Options.EtcdExtraClientURLs, "etcd-extra-client-urls", flags.Options.EtcdExtraClientURLs, "Extra URLs for etcd to listen on and advertise for the clients, in addition to the one on the bind address, not supported in kind runtime")
+	cmd.Flags().Uint32Var(&flags.Options.EtcdReplicas, "etcd-replicas", flags.Options.EtcdReplicas, "Number of the members of etcd, only the first member is exposed to the host, not supported in kind runtime")
+	cmd.Flags().StringVar(&flags.Options.EtcdHeartbeatInterval, "etcd-heartbeat-interval", flags.Options.EtcdHeartbeatInterval, "Interval of the heartbeats of etcd, e.g. 100ms")
+	cmd.Flags().StringVar(&flags.Options.EtcdElectionTimeout, "etcd-election-timeout", flags.Options.EtcdElectionTimeout, "Timeout of the leader election of etcd, e.g. 1s, it is recommended to be 10 times of the heartbeat interval")
+	cmd.Flags().BoolVar(&flags.Options.EtcdUnsafeNoFsync, "etcd-unsafe-no-fsync", flags.Options.EtcdUnsafeNoFsync, "Disable fsync of etcd to speed up writes, it may lose data on crash, only for disposable clusters")
+	cmd.Flags().StringVar(&flags.Options.PodDNSPolicy, "pod-dns-policy", flags.Options.PodDNSPolicy, "The dnsPolicy set by the kwok-controller on the created pods that have the default one and no dnsConfig (ClusterFirstWithHostNet, ClusterFirst, Default or None)")
+	cmd.Flags().StringSliceVar(&flags.Options.PodDNSNameservers, "pod-dns-nameservers", flags.Options.PodDNSNameservers, "The nameservers of the dnsConfig set by the kwok-controller on the created pods that do not have one")
+	cmd.Flags().StringSliceVar(&flags.Options.PodDNSSearches, "pod-dns-searches", flags.Options.PodDNSSearches, "The search domains of the dnsConfig set by the kwok-controller on the created pods that do not have one")
+	cmd.Flags().StringSliceVar(&flags.Options.PodDNSOptions, "pod-dns-options", flags.Options.PodDNSOptions, "The options in the form of name or name:value of the dnsConfig set by the kwok-controller on the created pods that do not have one")
+	cmd.Flags().StringVar(&flags.Options.AutoSnapshotInterval, "auto-snapshot-interval", flags.Options.AutoSnapshotInterval, "Interval of the etcd snapshots saved into the workdir in the background while the cluster is running, e.g. 10m, disabled if empty")
+	cmd.Flags().IntVar(&flags.Options.AutoSnapshotRetention, "auto-snapshot-retention", flags.Options.AutoSnapshotRetention, "Number of the latest automatic snapshots to keep, the older ones are pruned")
+	cmd.Flags().StringVar(&flags.FromSnapshot, "from-snapshot", flags.FromSnapshot, "Path to a snapshot to restore into the newly created cluster")
+	cmd.Flags().StringVar(&flags.FromSnapshotFormat, "from-snapshot-format", "etcd", "Format of the snapshot file given by --from-snapshot (etcd, k8s)")
 	cmd.Flags().StringArrayVar(&flags.ExtraArgs, "extra-args", flags.ExtraArgs, "Pass a single extra arg key-value pair to the component in the format `component=key=value`")
 
 	return cmd
@@ -194,14 +242,37 @@ func mutationComponentPatches(flags *flagpole) {
 }
 
 func runE(ctx context.Context, flags *flagpole) error {
+	err := config.ValidateClusterName(flags.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, opt := range flags.Options.LogOpts {
+		if !strings.Contains(opt, "=") {
+			return fmt.Errorf("invalid log option %q, must be in the form of key=value", opt)
+		}
+	}
+
 	name := config.ClusterName(flags.Name)
-	workdir := path.Join(config.ClustersDir, flags.Name)
+	workdir := config.ClusterWorkdir(flags.Name)
 
 	logger := log.FromContext(ctx)
 	logger = logger.With("cluster", flags.Name)
 	ctx = log.NewContext(ctx, logger)
 
-	var err error
+	if flags.FromSnapshot != "" {
+		if flags.FromSnapshotFormat != "etcd" && flags.FromSnapshotFormat != "k8s" {
+			return fmt.Errorf("unsupport format %q", flags.FromSnapshotFormat)
+		}
+		flags.FromSnapshot, err = path.Expand(flags.FromSnapshot)
+		if err != nil {
+			return err
+		}
+		if !file.Exists(flags.FromSnapshot) {
+			return fmt.Errorf("path %q does not exist", flags.FromSnapshot)
+		}
+	}
+
 	if flags.Kubeconfig != "" {
 		flags.Kubeconfig, err = path.Expand(flags.Kubeconfig)
 		if err != nil {
@@ -209,6 +280,86 @@ func runE(ctx context.Context, flags *flagpole) error {
 		}
 	}
 
+	if flags.Options.CABundle != "" {
+		flags.Options.CABundle, err = path.Expand(flags.Options.CABundle)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(flags.Options.CABundle)
+		if err != nil {
+			return fmt.Errorf("failed to read ca bundle: %w", err)
+		}
+		err = pki.ValidateCABundle(data)
+		if err != nil {
+			return fmt.Errorf("invalid ca bundle %q: %w", flags.Options.CABundle, err)
+		}
+	}
+
+	if flags.Options.KubeAuditLogMaxSize < 0 {
+		return fmt.Errorf("--kube-audit-log-maxsize must not be negative, got %v", flags.Options.KubeAuditLogMaxSize)
+	}
+	if flags.Options.KubeAuditLogMaxAge < 0 {
+		return fmt.Errorf("--kube-audit-log-maxage must not be negative, got %v", flags.Options.KubeAuditLogMaxAge)
+	}
+	if flags.Options.KubeAuditLogMaxBackup < 0 {
+		return fmt.Errorf("--kube-audit-log-maxbackup must not be negative, got %v", flags.Options.KubeAuditLogMaxBackup)
+	}
+
+	if flags.Options.KubeAuditWebhook != "" {
+		err = runtime.ValidateAuditWebhook(flags.Options.KubeAuditWebhook, flags.Options.KubeAuditPolicy)
+		if err != nil {
+			return err
+		}
+	}
+
+	if flags.Options.KubeApiserverInClusterAddress != "" {
+		err = runtime.ValidateInClusterAddress(flags.Options.KubeApiserverInClusterAddress)
+		if err != nil {
+			return err
+		}
+	}
+
+	if flags.Workdir != "" {
+		flags.Workdir, err = path.Expand(flags.Workdir)
+		if err != nil {
+			return err
+		}
+		if dryrun.DryRun {
+			dryrun.PrintMessage("ln -s %s %s", flags.Workdir, path.Join(config.ClustersDir, flags.Name))
+		} else {
+			err = config.LinkClusterWorkdir(flags.Name, flags.Workdir)
+			if err != nil {
+				return err
+			}
+		}
+		workdir = flags.Workdir
+	}
+
+	if flags.Options.AutoSnapshotInterval != "" {
+		interval, err := time.ParseDuration(flags.Options.AutoSnapshotInterval)
+		if err != nil {
+			return fmt.Errorf("invalid --auto-snapshot-interval: %w", err)
+		}
+		if interval <= 0 {
+			return fmt.Errorf("--auto-snapshot-interval must be greater than 0, got %s", interval)
+		}
+	}
+	if flags.Options.AutoSnapshotRetention <= 0 {
+		return fmt.Errorf("--auto-snapshot-retention must be greater than 0, got %d", flags.Options.AutoSnapshotRetention)
+	}
+
+	err = runtime.PodDNSConfig(&flags.Options).Validate()
+	if err != nil {
+		return fmt.Errorf("invalid pod dns: %w", err)
+	}
+
+	if flags.Options.NodeCPUOvercommit < 0 {
+		return fmt.Errorf("--cpu-overcommit must be greater than 0, or 0 to disable it, got %v", flags.Options.NodeCPUOvercommit)
+	}
+	if flags.Options.NodeMemoryOvercommit < 0 {
+		return fmt.Errorf("--memory-overcommit must be greater than 0, or 0 to disable it, got %v", flags.Options.NodeMemoryOvercommit)
+	}
+
 	gctx := ctx
 	if flags.Timeout > 0 {
 		var cancel context.CancelFunc
@@ -219,14 +370,41 @@ func runE(ctx context.Context, flags *flagpole) error {
 	mutationHeartbeat(flags)
 	mutationComponentPatches(flags)
 
+	if flags.Options.KubeApiserverReadOnlyPort != 0 {
+		logger.Warn("The legacy insecure port of the apiserver is deprecated and serves without authentication and authorization, "+
+			"only use it for the legacy tooling on a trusted network",
+			"port", flags.Options.KubeApiserverReadOnlyPort,
+		)
+	}
+
+	if flags.Options.EtcdUnsafeNoFsync {
+		logger.Warn("Etcd fsync is disabled, the data may be lost on crash, only use it for disposable clusters")
+	}
+
+	_, warning, err := components.EtcdTimeoutArgs(flags.Options.EtcdHeartbeatInterval, flags.Options.EtcdElectionTimeout)
+	if err != nil {
+		return err
+	}
+	if warning != "" {
+		logger.Warn(warning,
+			"heartbeatInterval", flags.Options.EtcdHeartbeatInterval,
+			"electionTimeout", flags.Options.EtcdElectionTimeout,
+		)
+	}
+
 	// Choose runtime
 	var rt runtime.Runtime
 	if flags.Options.Runtime == "" {
 		errs := make([]error, 0, len(flags.Options.Runtimes))
 		for _, r := range flags.Options.Runtimes {
+			r, err = runtime.DefaultRegistry.Resolve(r)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
 			buildRuntime, ok := runtime.DefaultRegistry.Get(r)
 			if !ok {
-				err = fmt.Errorf("runtime %q not found", flags.Options.Runtime)
+				err = fmt.Errorf("runtime %q not found", r)
 				errs = append(errs, err)
 				continue
 			}
@@ -246,6 +424,10 @@ func runE(ctx context.Context, flags *flagpole) error {
 			return fmt.Errorf("runtime %v not available: %v", flags.Options.Runtimes, errs)
 		}
 	} else {
+		flags.Options.Runtime, err = runtime.DefaultRegistry.Resolve(flags.Options.Runtime)
+		if err != nil {
+			return err
+		}
 		buildRuntime, ok := runtime.DefaultRegistry.Get(flags.Options.Runtime)
 		if !ok {
 			return fmt.Errorf("runtime %q not found", flags.Options.Runtime)
@@ -279,6 +461,12 @@ func runE(ctx context.Context, flags *flagpole) error {
 			} else {
 				logger.Info("Cluster is cleaned up")
 			}
+			if !dryrun.DryRun {
+				err = config.UnlinkClusterWorkdir(flags.Name)
+				if err != nil {
+					logger.Error("Failed to unlink workdir", err)
+				}
+			}
 		}
 		err = rt.SetConfig(ctx, flags.KwokctlConfiguration)
 		if err != nil {
@@ -353,6 +541,18 @@ func runE(ctx context.Context, flags *flagpole) error {
 		return fmt.Errorf("failed to init crs %q: %w", name, err)
 	}
 
+	if flags.FromSnapshot != "" {
+		start = time.Now()
+		logger.Info("Restoring snapshot", "path", flags.FromSnapshot)
+		err = restoreSnapshot(ctx, rt, flags.FromSnapshot, flags.FromSnapshotFormat, flags.Options.KubeApiserverPriorityAndFairness)
+		if err != nil {
+			return fmt.Errorf("failed to restore snapshot %q: %w", flags.FromSnapshot, err)
+		}
+		logger.Info("Snapshot is restored",
+			"elapsed", time.Since(start),
+		)
+	}
+
 	// Wait for cluster to be ready
 	if flags.Wait > 0 {
 		start = time.Now()
@@ -379,3 +579,21 @@ Thanks for using kwok!
 	}
 	return nil
 }
+
+func restoreSnapshot(ctx context.Context, rt runtime.Runtime, snapshotPath string, format string, priorityAndFairness bool) error {
+	switch format {
+	case "etcd":
+		return rt.SnapshotRestore(ctx, snapshotPath)
+	case "k8s":
+		filters := snapshot.Resources
+		if priorityAndFairness {
+			// Load the FlowSchemas and PriorityLevelConfigurations of the snapshot at startup
+			filters = append(slices.Clone(snapshot.FlowControlResources), filters...)
+		}
+		return rt.SnapshotRestoreWithYAML(ctx, snapshotPath, runtime.SnapshotRestoreWithYAMLConfig{
+			Filters: filters,
+		})
+	default:
+		return fmt.Errorf("unsupport format %q", format)
+	}
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dump implements the `debug dump` command
+package dump
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/file"
+	"sigs.k8s.io/kwok/pkg/utils/path"
+	"sigs.k8s.io/kwok/pkg/utils/printers"
+	"sigs.k8s.io/kwok/pkg/utils/yaml"
+)
+
+type flagpole struct {
+	Name    string
+	Tail    int
+	Events  int
+	Archive bool
+}
+
+// NewCommand returns a new cobra.Command for dumping the debug information of the cluster
+func NewCommand(ctx context.Context) *cobra.Command {
+	flags := &flagpole{
+		Tail:   200,
+		Events: 500,
+	}
+	cmd := &cobra.Command{
+		Args:  cobra.MaximumNArgs(1),
+		Use:   "dump [output-dir]",
+		Short: "Dumps the config, components, recent events and logs of the cluster to a tempdir or [output-dir] if specified",
+		Long: "Dumps the config, components, recent events and logs of the cluster for bug reports, " +
+			"the secrets in the config are redacted",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags.Name = config.DefaultCluster
+			return runE(cmd.Context(), flags, args)
+		},
+	}
+	cmd.Flags().IntVar(&flags.Tail, "tail", flags.Tail, "Number of the last lines of the logs to dump for each component, all of the logs if negative")
+	cmd.Flags().IntVar(&flags.Events, "events", flags.Events, "Number of the most recent events to dump, all of the events if negative")
+	cmd.Flags().BoolVar(&flags.Archive, "archive", flags.Archive, "Archive the dump into a .tar.gz file next to the output directory")
+	return cmd
+}
+
+func runE(ctx context.Context, flags *flagpole, args []string) error {
+	name := config.ClusterName(flags.Name)
+	workdir := config.ClusterWorkdir(flags.Name)
+
+	logger := log.FromContext(ctx).With("cluster", flags.Name)
+	ctx = log.NewContext(ctx, logger)
+
+	rt, err := runtime.DefaultRegistry.Load(ctx, name, workdir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Cluster does not exist")
+		}
+		return err
+	}
+
+	var dir string
+	if len(args) == 0 || args[0] == "" {
+		dir = path.Join(workdir, "export", "dump")
+	} else {
+		dir = path.Join(args[0], name)
+	}
+
+	if dryrun.DryRun {
+		dryrun.PrintMessage("# Dump the debug information of the cluster to %s", dir)
+		return nil
+	}
+
+	if file.Exists(dir) {
+		return fmt.Errorf("%s already exists", dir)
+	}
+	err = file.MkdirAll(dir)
+	if err != nil {
+		return err
+	}
+	logger.Info("Dumping the debug information", "dir", dir)
+
+	// All steps are best effort, as the dump is most wanted when the cluster is broken.
+	steps := []struct {
+		name string
+		fun  func() error
+	}{
+		{"config", func() error { return dumpConfig(ctx, rt, dir) }},
+		{"components", func() error { return dumpComponents(ctx, rt, dir) }},
+		{"events", func() error { return dumpEvents(ctx, rt, dir, flags.Events) }},
+		{"logs", func() error { return dumpLogs(ctx, rt, dir, flags.Tail) }},
+	}
+	for _, step := range steps {
+		err = step.fun()
+		if err != nil {
+			logger.Warn("Failed to dump", "step", step.name, "err", err)
+		}
+	}
+
+	if !flags.Archive {
+		return nil
+	}
+
+	archive := dir + ".tar.gz"
+	err = file.TarGz(ctx, dir, archive)
+	if err != nil {
+		return err
+	}
+	err = file.RemoveAll(dir)
+	if err != nil {
+		return err
+	}
+	logger.Info("Archived the debug information", "file", archive)
+	return nil
+}
+
+// dumpConfig dumps the config of the cluster with the secrets redacted
+func dumpConfig(ctx context.Context, rt runtime.Runtime, dir string) error {
+	objs, err := config.Load(ctx, rt.GetWorkdirPath(runtime.ConfigName))
+	if err != nil {
+		return err
+	}
+	redactObjects(objs)
+	return config.Save(ctx, path.Join(dir, "config.yaml"), objs)
+}
+
+// componentInfo is the information of a component in the dump
+type componentInfo struct {
+	Name    string   `json:"name"`
+	Status  string   `json:"status"`
+	Version string   `json:"version,omitempty"`
+	Image   string   `json:"image,omitempty"`
+	Binary  string   `json:"binary,omitempty"`
+	Command []string `json:"command,omitempty"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// dumpComponents dumps the status and the args of the components
+func dumpComponents(ctx context.Context, rt runtime.Runtime, dir string) error {
+	components, err := rt.ListComponents(ctx)
+	if err != nil {
+		return err
+	}
+
+	infos := make([]componentInfo, 0, len(components))
+	for _, component := range components {
+		info := componentInfo{
+			Name:    component.Name,
+			Version: component.Version,
+			Image:   component.Image,
+			Binary:  component.Binary,
+			Command: redactArgs(component.Command),
+			Args:    redactArgs(component.Args),
+		}
+		status, err := rt.InspectComponent(ctx, component.Name)
+		if err != nil {
+			info.Status = "Error: " + err.Error()
+		} else {
+			info.Status = componentStatus(status)
+		}
+		infos = append(infos, info)
+	}
+
+	data, err := yaml.Marshal(infos)
+	if err != nil {
+		return err
+	}
+	return file.Write(path.Join(dir, "components.yaml"), data)
+}
+
+func componentStatus(status runtime.ComponentStatus) string {
+	switch status {
+	case runtime.ComponentStatusReady:
+		return "Ready"
+	case runtime.ComponentStatusRunning:
+		return "NotReady"
+	case runtime.ComponentStatusStopped:
+		return "Stopped"
+	default:
+		return "Unknown"
+	}
+}
+
+// dumpEvents dumps the most recent events of the cluster
+func dumpEvents(ctx context.Context, rt runtime.Runtime, dir string, limit int) error {
+	clients, err := rt.GetClients(ctx)
+	if err != nil {
+		return err
+	}
+	list, err := clients.Kubernetes.CoreV1().Events(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = printers.NewTablePrinter(buf).WriteAll(eventRecords(list.Items, limit))
+	if err != nil {
+		return err
+	}
+	return file.Write(path.Join(dir, "events.txt"), buf.Bytes())
+}
+
+// eventRecords returns the table of the most recent events, from the oldest to the latest
+func eventRecords(events []corev1.Event, limit int) [][]string {
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).Before(eventTime(events[j]))
+	})
+	if limit >= 0 && len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+
+	records := [][]string{
+		{"LAST SEEN", "NAMESPACE", "TYPE", "REASON", "OBJECT", "MESSAGE"},
+	}
+	for _, event := range events {
+		records = append(records, []string{
+			eventTime(event).UTC().Format(time.RFC3339),
+			event.Namespace,
+			event.Type,
+			event.Reason,
+			strings.ToLower(event.InvolvedObject.Kind) + "/" + event.InvolvedObject.Name,
+			strings.TrimSpace(event.Message),
+		})
+	}
+	return records
+}
+
+func eventTime(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// dumpLogs dumps the last lines of the logs of each component
+func dumpLogs(ctx context.Context, rt runtime.Runtime, dir string, tail int) error {
+	components, err := rt.ListComponents(ctx)
+	if err != nil {
+		return err
+	}
+
+	logsDir := path.Join(dir, "logs")
+	err = file.MkdirAll(logsDir)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, component := range components {
+		buf := bytes.NewBuffer(nil)
+		err = rt.Logs(ctx, component.Name, buf, runtime.LogsOptions{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get logs of %s: %w", component.Name, err))
+			continue
+		}
+		err = file.Write(path.Join(logsDir, component.Name+".log"), lastLines(buf.Bytes(), tail))
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// lastLines returns the last n lines of the data, all of the data if n is negative
+func lastLines(data []byte, n int) []byte {
+	if n < 0 {
+		return data
+	}
+	if n == 0 {
+		return nil
+	}
+
+	// The trailing newline does not start a new line.
+	end := len(data) - 1
+	if end >= 0 && data[end] == '\n' {
+		end--
+	}
+	count := 0
+	for i := end; i >= 0; i-- {
+		if data[i] != '\n' {
+			continue
+		}
+		count++
+		if count == n {
+			return data[i+1:]
+		}
+	}
+	return data
+}
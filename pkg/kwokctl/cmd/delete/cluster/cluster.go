@@ -26,6 +26,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
 	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
 	"sigs.k8s.io/kwok/pkg/log"
 	"sigs.k8s.io/kwok/pkg/utils/kubeconfig"
@@ -33,10 +34,11 @@ import (
 )
 
 type flagpole struct {
-	Name       string
-	Kubeconfig string
-	All        bool
-	Force      bool
+	Name            string
+	Kubeconfig      string
+	All             bool
+	Force           bool
+	PreserveWorkdir bool
 }
 
 // NewCommand returns a new cobra.Command for cluster deletion
@@ -58,6 +60,7 @@ func NewCommand(ctx context.Context) *cobra.Command {
 	cmd.Flags().StringVar(&flags.Kubeconfig, "kubeconfig", flags.Kubeconfig, "The path to the kubeconfig file that will remove the deleted cluster")
 	cmd.Flags().BoolVar(&flags.All, "all", flags.All, "Delete all clusters managed by kwokctl")
 	cmd.Flags().BoolVar(&flags.Force, "force", false, "Force delete the cluster")
+	cmd.Flags().BoolVar(&flags.PreserveWorkdir, "preserve-workdir", false, "Keep the workdir of the cluster, e.g. the etcd data, logs and config, for debugging, a subsequent create with the same name reuses it")
 	return cmd
 }
 
@@ -71,13 +74,13 @@ func runE(ctx context.Context, flags *flagpole) error {
 			return err
 		}
 		for _, cluster := range clusters {
-			err = deleteCluster(ctx, cluster, flags.Kubeconfig, flags.Force)
+			err = deleteCluster(ctx, cluster, flags)
 			if err != nil {
 				return err
 			}
 		}
 	} else {
-		err = deleteCluster(ctx, flags.Name, flags.Kubeconfig, flags.Force)
+		err = deleteCluster(ctx, flags.Name, flags)
 		if err != nil {
 			return err
 		}
@@ -86,16 +89,15 @@ func runE(ctx context.Context, flags *flagpole) error {
 	return nil
 }
 
-func deleteCluster(ctx context.Context, clusterName string, kubeconfigPath string, force bool) error {
+func deleteCluster(ctx context.Context, clusterName string, flags *flagpole) error {
 	name := config.ClusterName(clusterName)
-	workdir := path.Join(config.ClustersDir, clusterName)
+	workdir := config.ClusterWorkdir(clusterName)
 
 	logger := log.FromContext(ctx)
 	logger = logger.With("cluster", clusterName)
 	ctx = log.NewContext(ctx, logger)
 
-	var err error
-	kubeconfigPath, err = path.Expand(kubeconfigPath)
+	kubeconfigPath, err := path.Expand(flags.Kubeconfig)
 	if err != nil {
 		return err
 	}
@@ -110,7 +112,7 @@ func deleteCluster(ctx context.Context, clusterName string, kubeconfigPath strin
 	}
 
 	if err := rt.Available(ctx); err != nil {
-		if !force {
+		if !flags.Force {
 			return err
 		}
 		logger.Warn("Unavailable runtime but proceed with force delete", "err", err)
@@ -141,10 +143,30 @@ func deleteCluster(ctx context.Context, clusterName string, kubeconfigPath strin
 			"kubeconfig", kubeconfigPath,
 		)
 	}
+	if flags.PreserveWorkdir {
+		ctx = runtime.WithPreserveWorkdir(ctx)
+	}
 	err = rt.Uninstall(ctx)
 	if err != nil {
 		return err
 	}
+	if flags.PreserveWorkdir {
+		logger.Info("Cluster is deleted, the workdir is preserved",
+			"elapsed", time.Since(start),
+			"workdir", workdir,
+		)
+		return nil
+	}
+	if dryrun.DryRun {
+		if config.IsCustomClusterWorkdir(clusterName) {
+			dryrun.PrintMessage("rm %s", path.Join(config.ClustersDir, clusterName))
+		}
+	} else {
+		err = config.UnlinkClusterWorkdir(clusterName)
+		if err != nil {
+			return err
+		}
+	}
 	logger.Info("Cluster is deleted",
 		"elapsed", time.Since(start),
 	)
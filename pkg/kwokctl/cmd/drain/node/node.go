@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package node implements the drain node command
+package node
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/kwokctl/drain"
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/client"
+)
+
+type flagpole struct {
+	Name          string
+	Recreate      bool
+	RetryInterval time.Duration
+	Timeout       time.Duration
+}
+
+// NewCommand returns a new cobra.Command for drain node
+func NewCommand(ctx context.Context) *cobra.Command {
+	flags := &flagpole{}
+
+	cmd := &cobra.Command{
+		Args:  cobra.ExactArgs(1),
+		Use:   "node [name]",
+		Short: "Cordon the node and evict the pods on it",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags.Name = config.DefaultCluster
+			return runE(cmd.Context(), flags, args[0])
+		},
+	}
+	cmd.Flags().BoolVar(&flags.Recreate, "recreate", flags.Recreate, "Recreate the evicted pods which are not managed by a controller, so that they can be scheduled to other nodes")
+	cmd.Flags().DurationVar(&flags.RetryInterval, "retry-interval", 5*time.Second, "Interval to retry the eviction blocked by a PodDisruptionBudget")
+	cmd.Flags().DurationVar(&flags.Timeout, "timeout", 5*time.Minute, "Timeout to wait for each pod to be evicted")
+	return cmd
+}
+
+func runE(ctx context.Context, flags *flagpole, nodeName string) error {
+	name := config.ClusterName(flags.Name)
+	workdir := config.ClusterWorkdir(flags.Name)
+
+	logger := log.FromContext(ctx)
+	logger = logger.With("cluster", flags.Name)
+	ctx = log.NewContext(ctx, logger)
+
+	rt, err := runtime.DefaultRegistry.Load(ctx, name, workdir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Cluster does not exist")
+		}
+		return err
+	}
+
+	kubeconfigPath := rt.GetWorkdirPath(runtime.InHostKubeconfigName)
+	clientset, err := client.NewClientset("", kubeconfigPath)
+	if err != nil {
+		return err
+	}
+	restConfig, err := clientset.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	typedClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	err = drain.Drain(ctx, typedClient, drain.Config{
+		NodeName:      nodeName,
+		Recreate:      flags.Recreate,
+		RetryInterval: flags.RetryInterval,
+		Timeout:       flags.Timeout,
+		DryRun:        dryrun.DryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Drained node", "node", nodeName)
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle contains a command to export a cluster to a bundle
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/file"
+	"sigs.k8s.io/kwok/pkg/utils/path"
+)
+
+type flagpole struct {
+	Name   string
+	Output string
+}
+
+// NewCommand returns a new cobra.Command for exporting a cluster to a bundle
+func NewCommand(ctx context.Context) *cobra.Command {
+	flags := &flagpole{}
+
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "bundle",
+		Short: "Exports the config, the PKI and an etcd snapshot of the cluster to a bundle",
+		Long: "Exports the config, the PKI and an etcd snapshot of the cluster to a .tar.gz bundle, " +
+			"the cluster is recreated from it on another machine by kwokctl import bundle",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags.Name = config.DefaultCluster
+			return runE(cmd.Context(), flags)
+		},
+	}
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "", "Path to the bundle")
+	return cmd
+}
+
+func runE(ctx context.Context, flags *flagpole) error {
+	if flags.Output == "" {
+		return fmt.Errorf("--output is required")
+	}
+	output, err := path.Expand(flags.Output)
+	if err != nil {
+		return err
+	}
+	if file.Exists(output) {
+		return fmt.Errorf("%s already exists", output)
+	}
+
+	name := config.ClusterName(flags.Name)
+	workdir := config.ClusterWorkdir(flags.Name)
+
+	logger := log.FromContext(ctx)
+	logger = logger.With("cluster", flags.Name)
+	ctx = log.NewContext(ctx, logger)
+
+	rt, err := runtime.DefaultRegistry.Load(ctx, name, workdir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Cluster does not exist")
+		}
+		return err
+	}
+
+	if dryrun.DryRun {
+		dryrun.PrintMessage("# Export the cluster %s to %s", flags.Name, output)
+		return nil
+	}
+
+	snapshot := path.Join(workdir, "export", runtime.BundleSnapshotName)
+	err = file.MkdirAll(path.Join(workdir, "export"))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Remove(snapshot)
+	}()
+	err = rt.SnapshotSave(ctx, snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	err = runtime.WriteBundle(ctx, workdir, snapshot, output)
+	if err != nil {
+		return err
+	}
+	logger.Info("Exported the cluster", "bundle", output)
+	return nil
+}
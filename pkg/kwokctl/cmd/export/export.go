@@ -22,7 +22,10 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/export/bundle"
+	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/export/compose"
 	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/export/logs"
+	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/export/stages"
 )
 
 // NewCommand returns a new cobra.Command for export
@@ -30,12 +33,15 @@ func NewCommand(ctx context.Context) *cobra.Command {
 	cmd := &cobra.Command{
 		Args:  cobra.NoArgs,
 		Use:   "export",
-		Short: "Exports one of [logs]",
+		Short: "Exports one of [logs, compose, stages, bundle]",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
 		},
 	}
 	// add subcommands
 	cmd.AddCommand(logs.NewCommand(ctx))
+	cmd.AddCommand(compose.NewCommand(ctx))
+	cmd.AddCommand(stages.NewCommand(ctx))
+	cmd.AddCommand(bundle.NewCommand(ctx))
 	return cmd
 }
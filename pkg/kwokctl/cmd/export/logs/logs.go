@@ -51,7 +51,7 @@ func NewCommand(ctx context.Context) *cobra.Command {
 
 func runE(ctx context.Context, flags *flagpole, args []string) error {
 	name := config.ClusterName(flags.Name)
-	workdir := path.Join(config.ClustersDir, flags.Name)
+	workdir := config.ClusterWorkdir(flags.Name)
 
 	logger := log.FromContext(ctx).With("cluster", flags.Name)
 	ctx = log.NewContext(ctx, logger)
@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package stages implements the `export stages` command
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/apis/v1alpha1"
+	"sigs.k8s.io/kwok/pkg/client/clientset/versioned"
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/client"
+	"sigs.k8s.io/kwok/pkg/utils/slices"
+)
+
+// lastAppliedConfigAnnotation is the annotation set by kubectl apply,
+// it is dropped because it is only meaningful for the live object.
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+type flagpole struct {
+	Name string
+}
+
+// NewCommand returns a new cobra.Command for exporting the stages
+func NewCommand(ctx context.Context) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.MaximumNArgs(1),
+		Use:   "stages [output-file]",
+		Short: "Exports the Stage resources of the cluster as a config to stdout or [output-file] if specified",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags.Name = config.DefaultCluster
+			return runE(cmd.Context(), flags, args)
+		},
+	}
+	return cmd
+}
+
+func runE(ctx context.Context, flags *flagpole, args []string) error {
+	name := config.ClusterName(flags.Name)
+	workdir := config.ClusterWorkdir(flags.Name)
+
+	logger := log.FromContext(ctx).With("cluster", flags.Name)
+	ctx = log.NewContext(ctx, logger)
+
+	rt, err := runtime.DefaultRegistry.Load(ctx, name, workdir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Cluster does not exist")
+		}
+		return err
+	}
+
+	conf, err := rt.Config(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(conf.Options.EnableCRDs, v1alpha1.StageKind) {
+		return fmt.Errorf("the %s CRD is not enabled in the cluster, the stages are loaded from %s", v1alpha1.StageKind, rt.GetWorkdirPath(runtime.ConfigName))
+	}
+
+	clientset, err := client.NewClientset("", rt.GetWorkdirPath(runtime.InHostKubeconfigName))
+	if err != nil {
+		return err
+	}
+	restConfig, err := clientset.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	typedKwokClient, err := versioned.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	objs, err := exportStages(ctx, typedKwokClient)
+	if err != nil {
+		return err
+	}
+	if len(objs) == 0 {
+		logger.Info("No stages found in the cluster")
+		return nil
+	}
+
+	if len(args) == 0 || args[0] == "" {
+		return config.SaveTo(ctx, os.Stdout, objs)
+	}
+
+	err = config.Save(ctx, args[0], objs)
+	if err != nil {
+		return err
+	}
+	logger.Info("Exported stages", "count", len(objs), "path", args[0])
+	return nil
+}
+
+// exportStages lists the stages from the Stage CRD
+// and strips the fields managed by the server, so they can be loaded as a config again.
+func exportStages(ctx context.Context, typedKwokClient versioned.Interface) ([]config.InternalObject, error) {
+	list, err := typedKwokClient.KwokV1alpha1().Stages().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stages: %w", err)
+	}
+
+	sort.SliceStable(list.Items, func(i, j int) bool {
+		return list.Items[i].Name < list.Items[j].Name
+	})
+
+	objs := make([]config.InternalObject, 0, len(list.Items))
+	for i := range list.Items {
+		item := list.Items[i]
+		stage := &v1alpha1.Stage{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        item.Name,
+				Labels:      item.Labels,
+				Annotations: cleanAnnotations(item.Annotations),
+			},
+			Spec: item.Spec,
+		}
+		internalStage, err := internalversion.ConvertToInternalStage(stage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert stage %s: %w", item.Name, err)
+		}
+		objs = append(objs, internalStage)
+	}
+	return objs, nil
+}
+
+func cleanAnnotations(annotations map[string]string) map[string]string {
+	if _, ok := annotations[lastAppliedConfigAnnotation]; !ok {
+		return annotations
+	}
+	out := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		if k == lastAppliedConfigAnnotation {
+			continue
+		}
+		out[k] = v
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stages
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/apis/v1alpha1"
+	"sigs.k8s.io/kwok/pkg/client/clientset/versioned/fake"
+	"sigs.k8s.io/kwok/pkg/config"
+)
+
+func TestExportStages(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1alpha1.Stage{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "pod-ready",
+				ResourceVersion: "42",
+				UID:             "0b1f8c6e-5b8a-4b8f-9d3b-3c6f1a2e4d5f",
+				Labels:          map[string]string{"app": "fake"},
+				Annotations: map[string]string{
+					lastAppliedConfigAnnotation: "{}",
+				},
+			},
+			Spec: v1alpha1.StageSpec{
+				ResourceRef: v1alpha1.StageResourceRef{APIGroup: "v1", Kind: "Pod"},
+			},
+			Status: v1alpha1.StageStatus{
+				Conditions: []v1alpha1.Condition{
+					{Type: "Available", Status: v1alpha1.ConditionTrue},
+				},
+			},
+		},
+		&v1alpha1.Stage{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "node-initialize",
+				Annotations: map[string]string{"note": "keep"},
+			},
+			Spec: v1alpha1.StageSpec{
+				ResourceRef: v1alpha1.StageResourceRef{APIGroup: "v1", Kind: "Node"},
+			},
+		},
+	)
+
+	objs, err := exportStages(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stages := config.FilterWithType[*internalversion.Stage](objs)
+	if len(stages) != 2 {
+		t.Fatalf("want 2 stages, got %d", len(stages))
+	}
+	if stages[0].Name != "node-initialize" || stages[1].Name != "pod-ready" {
+		t.Errorf("want stages sorted by name, got %s, %s", stages[0].Name, stages[1].Name)
+	}
+	if stages[0].Annotations["note"] != "keep" {
+		t.Errorf("want annotations kept, got %v", stages[0].Annotations)
+	}
+	if stages[1].ResourceVersion != "" || stages[1].UID != "" || stages[1].Annotations != nil {
+		t.Errorf("want server managed metadata stripped, got %+v", stages[1].ObjectMeta)
+	}
+	if stages[1].Labels["app"] != "fake" {
+		t.Errorf("want labels kept, got %v", stages[1].Labels)
+	}
+
+	path := filepath.Join(t.TempDir(), "stages.yaml")
+	err = config.Save(context.Background(), path, objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := config.Load(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loadedStages := config.FilterWithType[*internalversion.Stage](loaded)
+	if len(loadedStages) != 2 {
+		t.Fatalf("want 2 stages loaded back, got %d", len(loadedStages))
+	}
+	if loadedStages[1].Spec.ResourceRef.Kind != "Pod" {
+		t.Errorf("want stage pod-ready loaded back, got %+v", loadedStages[1])
+	}
+}
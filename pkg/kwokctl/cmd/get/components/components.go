@@ -19,17 +19,20 @@ package components
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/spf13/cobra"
 
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
 	"sigs.k8s.io/kwok/pkg/config"
 	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
 	"sigs.k8s.io/kwok/pkg/log"
-	"sigs.k8s.io/kwok/pkg/utils/path"
 	"sigs.k8s.io/kwok/pkg/utils/printers"
+	"sigs.k8s.io/kwok/pkg/utils/yaml"
 )
 
 type flagpole struct {
@@ -49,13 +52,13 @@ func NewCommand(ctx context.Context) *cobra.Command {
 			return runE(cmd.Context(), flags)
 		},
 	}
-	cmd.Flags().StringVarP(&flags.Output, "output", "o", "name", "Output format (name, wide)")
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "name", "Output format (name, wide, yaml, json)")
 	return cmd
 }
 
 func runE(ctx context.Context, flags *flagpole) error {
 	name := config.ClusterName(flags.Name)
-	workdir := path.Join(config.ClustersDir, flags.Name)
+	workdir := config.ClusterWorkdir(flags.Name)
 
 	logger := log.FromContext(ctx)
 	logger = logger.With("cluster", flags.Name)
@@ -74,41 +77,86 @@ func runE(ctx context.Context, flags *flagpole) error {
 		return err
 	}
 
-	switch flags.Output {
-	default:
-		return fmt.Errorf("unknown output format %q", flags.Output)
-	case "name":
+	if flags.Output == "name" {
 		for _, component := range components {
 			fmt.Println(component.Name)
 		}
+		return nil
+	}
+
+	statuses := make([]componentStatus, 0, len(components))
+	for _, component := range components {
+		s, err := rt.InspectComponent(ctx, component.Name)
+		statuses = append(statuses, newComponentStatus(component, s, err))
+	}
+
+	return printComponents(os.Stdout, flags.Output, statuses)
+}
+
+// componentStatus is the status of a component
+type componentStatus struct {
+	Name   string `json:"name"`
+	Image  string `json:"image,omitempty"`
+	Binary string `json:"binary,omitempty"`
+	Status string `json:"status"`
+}
+
+func newComponentStatus(component internalversion.Component, s runtime.ComponentStatus, err error) componentStatus {
+	status := componentStatus{
+		Name:   component.Name,
+		Image:  component.Image,
+		Binary: component.Binary,
+	}
+	if err != nil {
+		status.Status = "Error:" + err.Error()
+		return status
+	}
+	switch s {
+	default:
+		status.Status = "Unknown"
+	case runtime.ComponentStatusReady:
+		status.Status = "Ready"
+	case runtime.ComponentStatusRunning:
+		status.Status = "NotReady"
+	case runtime.ComponentStatusStopped:
+		status.Status = "Stopped"
+	}
+	return status
+}
+
+// printComponents prints the status of the components in the output format
+func printComponents(w io.Writer, output string, statuses []componentStatus) error {
+	switch output {
+	default:
+		return fmt.Errorf("unknown output format %q", output)
 	case "wide":
 		records := [][]string{
-			{"NAME", "STATUS"},
+			{"NAME", "IMAGE/BINARY", "STATUS"},
 		}
-
-		for _, component := range components {
-			s, err := rt.InspectComponent(ctx, component.Name)
-			if err != nil {
-				records = append(records, []string{component.Name, "Error:" + err.Error()})
-				continue
+		for _, status := range statuses {
+			source := status.Image
+			if source == "" {
+				source = status.Binary
 			}
-			switch s {
-			default:
-				records = append(records, []string{component.Name, "Unknown"})
-			case runtime.ComponentStatusReady:
-				records = append(records, []string{component.Name, "Ready"})
-			case runtime.ComponentStatusRunning:
-				records = append(records, []string{component.Name, "NotReady"})
-			case runtime.ComponentStatusStopped:
-				records = append(records, []string{component.Name, "Stopped"})
+			if source == "" {
+				source = "<none>"
 			}
+			records = append(records, []string{status.Name, source, status.Status})
 		}
-
-		w := printers.NewTablePrinter(os.Stdout)
-		err := w.WriteAll(records)
+		return printers.NewTablePrinter(w).WriteAll(records)
+	case "yaml":
+		data, err := yaml.Marshal(statuses)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case "json":
+		data, err := json.MarshalIndent(statuses, "", "  ")
 		if err != nil {
 			return err
 		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
 	}
-	return nil
 }
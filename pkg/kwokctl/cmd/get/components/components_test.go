@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+)
+
+func TestPrintComponents(t *testing.T) {
+	statuses := []componentStatus{
+		newComponentStatus(internalversion.Component{Name: "etcd", Image: "registry.k8s.io/etcd:3.5.11-0"}, runtime.ComponentStatusReady, nil),
+		newComponentStatus(internalversion.Component{Name: "kube-apiserver", Binary: "/usr/local/bin/kube-apiserver"}, runtime.ComponentStatusRunning, nil),
+		newComponentStatus(internalversion.Component{Name: "kwok-controller"}, runtime.ComponentStatusStopped, nil),
+		newComponentStatus(internalversion.Component{Name: "prometheus"}, runtime.ComponentStatusUnknown, errors.New("not found")),
+	}
+
+	tests := []struct {
+		name    string
+		output  string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "wide",
+			output: "wide",
+			want: []string{
+				"NAME  IMAGE/BINARY  STATUS",
+				"etcd  registry.k8s.io/etcd:3.5.11-0  Ready",
+				"kube-apiserver  /usr/local/bin/kube-apiserver  NotReady",
+				"kwok-controller  <none>  Stopped",
+				"prometheus  <none>  Error:not found",
+			},
+		},
+		{
+			name:   "yaml",
+			output: "yaml",
+			want: []string{
+				"- image: registry.k8s.io/etcd:3.5.11-0",
+				"  name: etcd",
+				"  status: Ready",
+				"- binary: /usr/local/bin/kube-apiserver",
+				"  name: kube-apiserver",
+				"  status: NotReady",
+			},
+		},
+		{
+			name:   "json",
+			output: "json",
+			want: []string{
+				`"name": "kwok-controller",`,
+				`"status": "Stopped"`,
+				`"status": "Error:not found"`,
+			},
+		},
+		{
+			name:    "unknown",
+			output:  "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			err := printComponents(out, tt.output, statuses)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("printComponents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := strings.Join(strings.Fields(out.String()), " ")
+			for _, want := range tt.want {
+				if !strings.Contains(got, strings.Join(strings.Fields(want), " ")) {
+					t.Errorf("want %q in %q", want, out.String())
+				}
+			}
+		})
+	}
+}
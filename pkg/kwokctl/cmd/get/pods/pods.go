@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pods contains a command to list the pods on a node from the cache of the kwok-controller.
+package pods
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/format"
+	"sigs.k8s.io/kwok/pkg/utils/printers"
+)
+
+type flagpole struct {
+	Name   string
+	Node   string
+	Output string
+}
+
+// NewCommand returns a new cobra.Command for get pods
+func NewCommand(ctx context.Context) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "pods",
+		Short: "List the pods on a node from the cache of the kwok-controller",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags.Name = config.DefaultCluster
+			return runE(cmd.Context(), flags)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Node, "node", "", "Name of the node to list the pods on")
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "table", "Output format (table, name, json)")
+	_ = cmd.MarkFlagRequired("node")
+	return cmd
+}
+
+func runE(ctx context.Context, flags *flagpole) error {
+	name := config.ClusterName(flags.Name)
+	workdir := config.ClusterWorkdir(flags.Name)
+
+	logger := log.FromContext(ctx)
+	logger = logger.With("cluster", flags.Name)
+	ctx = log.NewContext(ctx, logger)
+
+	rt, err := runtime.DefaultRegistry.Load(ctx, name, workdir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Cluster does not exist")
+		}
+		return err
+	}
+
+	conf, err := rt.Config(ctx)
+	if err != nil {
+		return err
+	}
+
+	if conf.Options.KwokControllerPort == 0 {
+		return fmt.Errorf("the port of kwok-controller is not exposed to the host, create the cluster with --controller-port")
+	}
+
+	address := net.JoinHostPort(conf.Options.BindAddress, format.String(conf.Options.KwokControllerPort))
+	u := podsOnNodeURL(address, flags.Node)
+	if rt.IsDryRun() {
+		dryrun.PrintMessage("curl %s", u)
+		return nil
+	}
+
+	pods, err := listPodsOnNode(ctx, u, flags.Node)
+	if err != nil {
+		return err
+	}
+
+	return printPods(os.Stdout, flags.Output, pods)
+}
+
+// podsOnNodeURL returns the url of the pods on the node in the kwok-controller server
+func podsOnNodeURL(address string, nodeName string) string {
+	u := url.URL{
+		Scheme:  "http",
+		Host:    address,
+		Path:    "/nodes/" + nodeName + "/pods",
+		RawPath: "/nodes/" + url.PathEscape(nodeName) + "/pods",
+	}
+	return u.String()
+}
+
+// listPodsOnNode lists the pods on the node from the kwok-controller server
+func listPodsOnNode(ctx context.Context, u string, nodeName string) ([]log.ObjectRef, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods on node %q: %w", nodeName, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to list pods on node %q: %s: %s", nodeName, resp.Status, body)
+	}
+
+	var pods []log.ObjectRef
+	err = json.NewDecoder(resp.Body).Decode(&pods)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode pods on node %q: %w", nodeName, err)
+	}
+	return pods, nil
+}
+
+// printPods prints the pods in the output format
+func printPods(w io.Writer, output string, pods []log.ObjectRef) error {
+	switch output {
+	default:
+		return fmt.Errorf("unknown output format %q", output)
+	case "table", "":
+		records := [][]string{
+			{"NAMESPACE", "NAME"},
+		}
+		for _, pod := range pods {
+			records = append(records, []string{pod.Namespace, pod.Name})
+		}
+		return printers.NewTablePrinter(w).WriteAll(records)
+	case "name":
+		for _, pod := range pods {
+			_, err := fmt.Fprintln(w, pod.String())
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		data, err := json.MarshalIndent(pods, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	}
+}
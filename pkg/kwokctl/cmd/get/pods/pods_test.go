@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pods
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kwok/pkg/log"
+)
+
+func TestPodsOnNodeURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		nodeName string
+		want     string
+	}{
+		{
+			name:     "ipv4",
+			address:  "127.0.0.1:10247",
+			nodeName: "node-0",
+			want:     "http://127.0.0.1:10247/nodes/node-0/pods",
+		},
+		{
+			name:     "ipv6",
+			address:  "[::1]:10247",
+			nodeName: "node-0",
+			want:     "http://[::1]:10247/nodes/node-0/pods",
+		},
+		{
+			name:     "escaped node name",
+			address:  "0.0.0.0:10247",
+			nodeName: "node/0",
+			want:     "http://0.0.0.0:10247/nodes/node%2F0/pods",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := podsOnNodeURL(tt.address, tt.nodeName); got != tt.want {
+				t.Errorf("podsOnNodeURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListPodsOnNode(t *testing.T) {
+	svc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/nodes/node-0/pods":
+			_, _ = w.Write([]byte(`[{"name":"pod-0","namespace":"default"},{"name":"pod-1","namespace":"kube-system"}]`))
+		case "/nodes/node-1/pods":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer svc.Close()
+	address := strings.TrimPrefix(svc.URL, "http://")
+
+	tests := []struct {
+		name     string
+		nodeName string
+		want     []log.ObjectRef
+		wantErr  bool
+	}{
+		{
+			name:     "node with pods",
+			nodeName: "node-0",
+			want: []log.ObjectRef{
+				{Namespace: "default", Name: "pod-0"},
+				{Namespace: "kube-system", Name: "pod-1"},
+			},
+		},
+		{
+			name:     "node without pods",
+			nodeName: "node-1",
+			want:     []log.ObjectRef{},
+		},
+		{
+			name:     "endpoint not installed",
+			nodeName: "node-2",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := listPodsOnNode(context.Background(), podsOnNodeURL(address, tt.nodeName), tt.nodeName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("listPodsOnNode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("listPodsOnNode() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintPods(t *testing.T) {
+	pods := []log.ObjectRef{
+		{Namespace: "default", Name: "pod-0"},
+		{Namespace: "kube-system", Name: "pod-1"},
+	}
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "table",
+			output: "table",
+			want:   "NAMESPACE NAME default pod-0 kube-system pod-1",
+		},
+		{
+			name:   "name",
+			output: "name",
+			want:   "default/pod-0 kube-system/pod-1",
+		},
+		{
+			name:    "unknown",
+			output:  "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			err := printPods(out, tt.output, pods)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("printPods() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := strings.Join(strings.Fields(out.String()), " ")
+			if got != tt.want {
+				t.Errorf("printPods() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
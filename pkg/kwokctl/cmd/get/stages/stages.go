@@ -0,0 +1,227 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package stages contains a command to list the stages loaded by the cluster.
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/apis/v1alpha1"
+	"sigs.k8s.io/kwok/pkg/client/clientset/versioned"
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/client"
+	"sigs.k8s.io/kwok/pkg/utils/printers"
+	"sigs.k8s.io/kwok/pkg/utils/slices"
+)
+
+type flagpole struct {
+	Name   string
+	Output string
+}
+
+// NewCommand returns a new cobra.Command for get stages
+func NewCommand(ctx context.Context) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "stages",
+		Short: "List the stages loaded by the cluster",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags.Name = config.DefaultCluster
+			return runE(cmd.Context(), flags)
+		},
+	}
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "table", "Output format (table, yaml, json)")
+	return cmd
+}
+
+func runE(ctx context.Context, flags *flagpole) error {
+	name := config.ClusterName(flags.Name)
+	workdir := config.ClusterWorkdir(flags.Name)
+
+	logger := log.FromContext(ctx)
+	logger = logger.With("cluster", flags.Name)
+	ctx = log.NewContext(ctx, logger)
+
+	rt, err := runtime.DefaultRegistry.Load(ctx, name, workdir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Cluster does not exist")
+		}
+		return err
+	}
+
+	conf, err := rt.Config(ctx)
+	if err != nil {
+		return err
+	}
+
+	var stages []*v1alpha1.Stage
+	if slices.Contains(conf.Options.EnableCRDs, v1alpha1.StageKind) {
+		clientset, err := client.NewClientset("", rt.GetWorkdirPath(runtime.InHostKubeconfigName))
+		if err != nil {
+			return err
+		}
+		restConfig, err := clientset.ToRESTConfig()
+		if err != nil {
+			return err
+		}
+		typedKwokClient, err := versioned.NewForConfig(restConfig)
+		if err != nil {
+			return err
+		}
+		stages, err = listStagesFromCRD(ctx, typedKwokClient)
+		if err != nil {
+			return err
+		}
+	} else {
+		objs, err := config.Load(ctx, rt.GetWorkdirPath(runtime.ConfigName))
+		if err != nil {
+			return err
+		}
+		stages, err = listStagesFromConfig(config.FilterWithType[*internalversion.Stage](objs))
+		if err != nil {
+			return err
+		}
+		if len(stages) == 0 {
+			logger.Info("No stages are configured, the default stages of the kwok controller are used")
+			return nil
+		}
+	}
+
+	return printStages(os.Stdout, flags.Output, stages)
+}
+
+// listStagesFromCRD lists the stages from the Stage CRD
+func listStagesFromCRD(ctx context.Context, typedKwokClient versioned.Interface) ([]*v1alpha1.Stage, error) {
+	list, err := typedKwokClient.KwokV1alpha1().Stages().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stages: %w", err)
+	}
+	stages := make([]*v1alpha1.Stage, 0, len(list.Items))
+	for i := range list.Items {
+		stage := list.Items[i].DeepCopy()
+		stage.APIVersion = v1alpha1.GroupVersion.String()
+		stage.Kind = v1alpha1.StageKind
+		stages = append(stages, stage)
+	}
+	sortStages(stages)
+	return stages, nil
+}
+
+// listStagesFromConfig lists the static stages from the config of the cluster
+func listStagesFromConfig(internalStages []*internalversion.Stage) ([]*v1alpha1.Stage, error) {
+	stages := make([]*v1alpha1.Stage, 0, len(internalStages))
+	for _, internalStage := range internalStages {
+		stage, err := internalversion.ConvertToV1alpha1Stage(internalStage)
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, stage)
+	}
+	sortStages(stages)
+	return stages, nil
+}
+
+func sortStages(stages []*v1alpha1.Stage) {
+	sort.SliceStable(stages, func(i, j int) bool {
+		ri, rj := resourceOf(stages[i]), resourceOf(stages[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return stages[i].Name < stages[j].Name
+	})
+}
+
+// printStages prints the stages in the output format
+func printStages(w io.Writer, output string, stages []*v1alpha1.Stage) error {
+	switch output {
+	default:
+		return fmt.Errorf("unknown output format %q", output)
+	case "table", "":
+		records := [][]string{
+			{"NAME", "RESOURCE", "SELECTOR"},
+		}
+		for _, stage := range stages {
+			records = append(records, []string{stage.Name, resourceOf(stage), selectorSummary(stage.Spec.Selector)})
+		}
+		return printers.NewTablePrinter(w).WriteAll(records)
+	case printers.OutputYAML, printers.OutputJSON:
+		return printers.WriteObject(w, output, stageList(stages))
+	}
+}
+
+// stageList wraps the stages in a list like kubectl does
+func stageList(stages []*v1alpha1.Stage) map[string]any {
+	return map[string]any{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      stages,
+	}
+}
+
+// resourceOf returns the resource the stage applies to, e.g. v1/Pod
+func resourceOf(stage *v1alpha1.Stage) string {
+	return stage.Spec.ResourceRef.APIGroup + "/" + stage.Spec.ResourceRef.Kind
+}
+
+// selectorSummary returns a one-line summary of the selector
+func selectorSummary(selector *v1alpha1.StageSelector) string {
+	if selector == nil {
+		return "<none>"
+	}
+
+	items := []string{}
+	for _, k := range sortedKeys(selector.MatchLabels) {
+		items = append(items, fmt.Sprintf("label %s=%s", k, selector.MatchLabels[k]))
+	}
+	for _, k := range sortedKeys(selector.MatchAnnotations) {
+		items = append(items, fmt.Sprintf("annotation %s=%s", k, selector.MatchAnnotations[k]))
+	}
+	for _, expr := range selector.MatchExpressions {
+		item := fmt.Sprintf("%s %s", expr.Key, expr.Operator)
+		if len(expr.Values) != 0 {
+			item += " [" + strings.Join(expr.Values, ",") + "]"
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return "<none>"
+	}
+	return strings.Join(items, "; ")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
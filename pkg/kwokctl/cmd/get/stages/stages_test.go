@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stages
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/apis/v1alpha1"
+	"sigs.k8s.io/kwok/pkg/client/clientset/versioned/fake"
+)
+
+func TestListStagesFromCRD(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1alpha1.Stage{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-ready"},
+			Spec: v1alpha1.StageSpec{
+				ResourceRef: v1alpha1.StageResourceRef{APIGroup: "v1", Kind: "Pod"},
+				Selector: &v1alpha1.StageSelector{
+					MatchLabels: map[string]string{"app": "fake"},
+					MatchExpressions: []v1alpha1.SelectorRequirement{
+						{Key: ".status.phase", Operator: v1alpha1.SelectorOpIn, Values: []string{"Pending"}},
+					},
+				},
+			},
+		},
+		&v1alpha1.Stage{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-initialize"},
+			Spec: v1alpha1.StageSpec{
+				ResourceRef: v1alpha1.StageResourceRef{APIGroup: "v1", Kind: "Node"},
+			},
+		},
+	)
+
+	stages, err := listStagesFromCRD(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stages) != 2 {
+		t.Fatalf("want 2 stages, got %d", len(stages))
+	}
+	if stages[0].Name != "node-initialize" || stages[1].Name != "pod-ready" {
+		t.Errorf("want stages sorted by resource, got %s, %s", stages[0].Name, stages[1].Name)
+	}
+
+	out := bytes.NewBuffer(nil)
+	err = printStages(out, "table", stages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("want 3 lines, got %q", out.String())
+	}
+	for _, want := range []string{"node-initialize", "v1/Node", "<none>"} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("want %q in %q", want, lines[1])
+		}
+	}
+	for _, want := range []string{"pod-ready", "v1/Pod", "label app=fake; .status.phase In [Pending]"} {
+		if !strings.Contains(lines[2], want) {
+			t.Errorf("want %q in %q", want, lines[2])
+		}
+	}
+
+	out.Reset()
+	err = printStages(out, "json", stages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list struct {
+		Kind  string           `json:"kind"`
+		Items []v1alpha1.Stage `json:"items"`
+	}
+	err = json.Unmarshal(out.Bytes(), &list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Kind != "List" || len(list.Items) != 2 || list.Items[1].Kind != v1alpha1.StageKind {
+		t.Errorf("unexpected json output %s", out.String())
+	}
+
+	out.Reset()
+	err = printStages(out, "yaml", stages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "name: pod-ready") {
+		t.Errorf("unexpected yaml output %s", out.String())
+	}
+
+	err = printStages(out, "xml", stages)
+	if err == nil {
+		t.Errorf("want error for unknown output format")
+	}
+}
+
+func TestListStagesFromConfig(t *testing.T) {
+	stages, err := listStagesFromConfig([]*internalversion.Stage{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-delete"},
+			Spec: internalversion.StageSpec{
+				ResourceRef: internalversion.StageResourceRef{APIGroup: "v1", Kind: "Pod"},
+				Selector: &internalversion.StageSelector{
+					MatchAnnotations: map[string]string{"kwok.x-k8s.io/delete": "true"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stages) != 1 {
+		t.Fatalf("want 1 stage, got %d", len(stages))
+	}
+	if stages[0].Kind != v1alpha1.StageKind {
+		t.Errorf("want kind %q, got %q", v1alpha1.StageKind, stages[0].Kind)
+	}
+	if got, want := selectorSummary(stages[0].Spec.Selector), "annotation kwok.x-k8s.io/delete=true"; got != want {
+		t.Errorf("selectorSummary() got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle contains a command to import a cluster from a bundle
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/consts"
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/exec"
+	"sigs.k8s.io/kwok/pkg/utils/file"
+	"sigs.k8s.io/kwok/pkg/utils/path"
+)
+
+type flagpole struct {
+	Name   string
+	Create bool
+}
+
+// NewCommand returns a new cobra.Command for importing a cluster from a bundle
+func NewCommand(ctx context.Context) *cobra.Command {
+	flags := &flagpole{}
+
+	cmd := &cobra.Command{
+		Args:  cobra.ExactArgs(1),
+		Use:   "bundle <bundle>",
+		Short: "Imports a cluster from a bundle exported by kwokctl export bundle",
+		Long: "Imports a cluster from a bundle exported by kwokctl export bundle, " +
+			"the ports are reallocated and the admin cert is reissued for this host, " +
+			"the cluster is created with the imported config and etcd snapshot",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags.Name = config.DefaultCluster
+			return runE(cmd.Context(), flags, args[0])
+		},
+	}
+	cmd.Flags().BoolVar(&flags.Create, "create", true, "Create the cluster with the imported config and etcd snapshot, otherwise only prepare the workdir")
+	return cmd
+}
+
+func runE(ctx context.Context, flags *flagpole, src string) error {
+	err := config.ValidateClusterName(flags.Name)
+	if err != nil {
+		return err
+	}
+
+	src, err = path.Expand(src)
+	if err != nil {
+		return err
+	}
+	if !file.Exists(src) {
+		return fmt.Errorf("path %q does not exist", src)
+	}
+
+	workdir := config.ClusterWorkdir(flags.Name)
+
+	logger := log.FromContext(ctx)
+	logger = logger.With("cluster", flags.Name)
+	ctx = log.NewContext(ctx, logger)
+
+	if file.Exists(path.Join(workdir, consts.ConfigName)) || file.Exists(path.Join(workdir, runtime.PkiName)) {
+		return fmt.Errorf("cluster %q already exists", flags.Name)
+	}
+
+	if dryrun.DryRun {
+		dryrun.PrintMessage("# Import the bundle %s to %s", src, workdir)
+		if flags.Create {
+			bundleDir := path.Join(workdir, runtime.BundleName)
+			dryrun.PrintMessage("%s", strings.Join(append([]string{"kwokctl"}, createArgs(flags.Name, path.Join(bundleDir, consts.ConfigName), path.Join(bundleDir, runtime.BundleSnapshotName))...), " "))
+		}
+		return nil
+	}
+
+	configPath, snapshotPath, err := runtime.ReadBundle(ctx, src, config.ClusterName(flags.Name), workdir, runtime.GetUsedPorts(ctx))
+	if err != nil {
+		return err
+	}
+
+	args := createArgs(flags.Name, configPath, snapshotPath)
+	if !flags.Create {
+		logger.Info("Imported the bundle, create the cluster with it by",
+			"command", strings.Join(append([]string{"kwokctl"}, args...), " "),
+		)
+		return nil
+	}
+
+	// The config of the bundle is loaded by kwokctl at startup, so the cluster is created by a new kwokctl.
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	logger.Info("Imported the bundle, creating the cluster")
+	return exec.Exec(exec.WithStdIO(ctx), exe, args...)
+}
+
+// createArgs returns the arguments of kwokctl to create the cluster with the imported config and etcd snapshot
+func createArgs(name, configPath, snapshotPath string) []string {
+	return []string{
+		"create",
+		"cluster",
+		"--name=" + name,
+		"--config=" + configPath,
+		"--from-snapshot=" + snapshotPath,
+		"--from-snapshot-format=etcd",
+	}
+}
@@ -27,12 +27,12 @@ import (
 	"sigs.k8s.io/kwok/pkg/config"
 	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
 	"sigs.k8s.io/kwok/pkg/log"
-	"sigs.k8s.io/kwok/pkg/utils/path"
 )
 
 type flagpole struct {
-	Name   string
-	Follow bool
+	Name       string
+	Follow     bool
+	Timestamps bool
 }
 
 // NewCommand returns a new cobra.Command for getting the list of clusters
@@ -51,12 +51,13 @@ func NewCommand(ctx context.Context) *cobra.Command {
 		},
 	}
 	cmd.Flags().BoolVarP(&flags.Follow, "follow", "f", false, "Specify if the logs should be streamed")
+	cmd.Flags().BoolVar(&flags.Timestamps, "timestamps", false, "Include RFC3339 timestamps on each line of the logs, where the runtime supports it")
 	return cmd
 }
 
 func runE(ctx context.Context, flags *flagpole, args []string) error {
 	name := config.ClusterName(flags.Name)
-	workdir := path.Join(config.ClustersDir, flags.Name)
+	workdir := config.ClusterWorkdir(flags.Name)
 
 	logger := log.FromContext(ctx)
 	logger = logger.With("cluster", flags.Name)
@@ -70,6 +71,10 @@ func runE(ctx context.Context, flags *flagpole, args []string) error {
 		return err
 	}
 
+	if flags.Timestamps && args[0] == "audit" {
+		logger.Warn("The timestamps are not supported by audit logs, ignored")
+	}
+
 	if args[0] == "audit" {
 		if flags.Follow {
 			err = rt.AuditLogsFollow(ctx, os.Stdout)
@@ -78,9 +83,9 @@ func runE(ctx context.Context, flags *flagpole, args []string) error {
 		}
 	} else {
 		if flags.Follow {
-			err = rt.LogsFollow(ctx, args[0], os.Stdout)
+			err = rt.LogsFollow(ctx, args[0], os.Stdout, runtime.LogsOptions{Timestamps: flags.Timestamps})
 		} else {
-			err = rt.Logs(ctx, args[0], os.Stdout)
+			err = rt.Logs(ctx, args[0], os.Stdout, runtime.LogsOptions{Timestamps: flags.Timestamps})
 		}
 	}
 	if err != nil {
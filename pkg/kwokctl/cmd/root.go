@@ -23,13 +23,20 @@ import (
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/attach"
+	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/cert"
+	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/clone"
 	conf "sigs.k8s.io/kwok/pkg/kwokctl/cmd/config"
+	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/cordon"
 	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/create"
+	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/debug"
 	del "sigs.k8s.io/kwok/pkg/kwokctl/cmd/delete"
+	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/drain"
 	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/etcdctl"
 	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/export"
 	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/get"
 	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/hack"
+	import_ "sigs.k8s.io/kwok/pkg/kwokctl/cmd/import"
 	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/kubectl"
 	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/logs"
 	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/port_forward"
@@ -37,6 +44,10 @@ import (
 	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/snapshot"
 	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/start"
 	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/stop"
+	switch_ "sigs.k8s.io/kwok/pkg/kwokctl/cmd/switch"
+	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/uncordon"
+	versioncmd "sigs.k8s.io/kwok/pkg/kwokctl/cmd/version"
+	"sigs.k8s.io/kwok/pkg/kwokctl/cmd/wait"
 	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
 	"sigs.k8s.io/kwok/pkg/utils/version"
 )
@@ -62,18 +73,29 @@ func NewCommand(ctx context.Context) *cobra.Command {
 	cmd.AddCommand(
 		conf.NewCommand(ctx),
 		create.NewCommand(ctx),
+		clone.NewCommand(ctx),
 		del.NewCommand(ctx),
 		get.NewCommand(ctx),
 		start.NewCommand(ctx),
 		stop.NewCommand(ctx),
+		switch_.NewCommand(ctx),
 		kubectl.NewCommand(ctx),
 		etcdctl.NewCommand(ctx),
 		logs.NewCommand(ctx),
+		attach.NewCommand(ctx),
 		scale.NewCommand(ctx),
+		drain.NewCommand(ctx),
+		cordon.NewCommand(ctx),
+		uncordon.NewCommand(ctx),
+		cert.NewCommand(ctx),
 		snapshot.NewCommand(ctx),
 		export.NewCommand(ctx),
+		import_.NewCommand(ctx),
+		debug.NewCommand(ctx),
 		hack.NewCommand(ctx),
 		port_forward.NewCommand(ctx),
+		wait.NewCommand(ctx),
+		versioncmd.NewCommand(ctx),
 	)
 	return cmd
 }
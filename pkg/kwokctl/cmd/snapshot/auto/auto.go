@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package auto provides a command to save the snapshots of a cluster periodically.
+package auto
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+	"sigs.k8s.io/kwok/pkg/log"
+)
+
+type flagpole struct {
+	Name      string
+	Interval  time.Duration
+	Retention int
+}
+
+// NewCommand returns a new cobra.Command for saving the snapshots periodically.
+func NewCommand(ctx context.Context) *cobra.Command {
+	flags := &flagpole{}
+
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "auto",
+		Short: "Save the etcd snapshot of the cluster into the workdir periodically until interrupted",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags.Name = config.DefaultCluster
+			return runE(cmd.Context(), flags)
+		},
+	}
+	cmd.Flags().DurationVar(&flags.Interval, "interval", 10*time.Minute, "Interval between two snapshots")
+	cmd.Flags().IntVar(&flags.Retention, "retention", 6, "Number of the latest snapshots to keep, the older ones are pruned")
+	return cmd
+}
+
+func runE(ctx context.Context, flags *flagpole) error {
+	name := config.ClusterName(flags.Name)
+	workdir := config.ClusterWorkdir(flags.Name)
+
+	logger := log.FromContext(ctx)
+	logger = logger.With("cluster", flags.Name)
+	ctx = log.NewContext(ctx, logger)
+
+	rt, err := runtime.DefaultRegistry.Load(ctx, name, workdir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Cluster does not exist")
+		}
+		return err
+	}
+
+	logger.Info("Start saving snapshots",
+		"dir", rt.GetWorkdirPath(runtime.AutoSnapshotsName),
+		"interval", flags.Interval,
+		"retention", flags.Retention,
+	)
+	return runtime.AutoSnapshot(ctx, rt, runtime.AutoSnapshotConfig{
+		Interval:  flags.Interval,
+		Retention: flags.Retention,
+	})
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diff provides a command to compare two snapshots of a cluster.
+package diff
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/kwok/pkg/kwokctl/snapshot"
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/client"
+	"sigs.k8s.io/kwok/pkg/utils/yaml"
+)
+
+type flagpole struct {
+	From         string
+	To           string
+	Output       string
+	Filters      []string
+	IncludeNoise bool
+}
+
+// NewCommand returns a new cobra.Command to compare two snapshots.
+func NewCommand(ctx context.Context) *cobra.Command {
+	flags := &flagpole{}
+
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "diff",
+		Short: "Compare two snapshots in k8s format, exits non-zero if they differ",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(cmd.Context(), flags)
+		},
+	}
+	cmd.Flags().StringVar(&flags.From, "from", "", "Path to the snapshot to compare from")
+	cmd.Flags().StringVar(&flags.To, "to", "", "Path to the snapshot to compare to")
+	cmd.Flags().StringSliceVar(&flags.Filters, "filter", snapshot.Resources, "Filter the resources to compare")
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "text", "Output format (text, yaml, json)")
+	cmd.Flags().BoolVar(&flags.IncludeNoise, "include-noise", false, "Keep the resourceVersion, managedFields and timestamps in the comparison")
+	return cmd
+}
+
+func runE(ctx context.Context, flags *flagpole) error {
+	if flags.From == "" || flags.To == "" {
+		return fmt.Errorf("both --from and --to are required")
+	}
+
+	from, err := os.Open(flags.From)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = from.Close()
+	}()
+
+	to, err := os.Open(flags.To)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = to.Close()
+	}()
+
+	logger := log.FromContext(ctx)
+
+	filters, errs := client.MappingForResources(newSchemeRESTMapper(scheme.Scheme), flags.Filters)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			logger.Error("failed to get mapping", err)
+		}
+	}
+
+	report, err := snapshot.Diff(ctx, yaml.NewDecoder(from), yaml.NewDecoder(to), snapshot.DiffConfig{
+		Filters:      filters,
+		NoFilters:    len(flags.Filters) == 0,
+		IncludeNoise: flags.IncludeNoise,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = printReport(os.Stdout, flags.Output, report)
+	if err != nil {
+		return err
+	}
+
+	if !report.Empty() {
+		return fmt.Errorf("snapshots differ: %d added, %d removed, %d changed", len(report.Added), len(report.Removed), len(report.Changed))
+	}
+	return nil
+}
+
+// newSchemeRESTMapper returns a RESTMapper of the built-in types so the filters can be resolved without a cluster,
+// only the preferred version of each group is registered to keep the resources unambiguous,
+// and the scope is not used to filter the resources, so all of them are registered as namespaced.
+func newSchemeRESTMapper(s *runtime.Scheme) meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(s.PrioritizedVersionsAllGroups())
+	for gvk := range s.AllKnownTypes() {
+		versions := s.PrioritizedVersionsForGroup(gvk.Group)
+		if len(versions) == 0 || versions[0].Version != gvk.Version {
+			continue
+		}
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+	return mapper
+}
+
+func printReport(w io.Writer, output string, report *snapshot.DiffReport) error {
+	switch output {
+	default:
+		return fmt.Errorf("unknown output format %q", output)
+	case "text", "":
+		return printText(w, report)
+	case "yaml":
+		data, err := yaml.Marshal(report)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	}
+}
+
+// printText prints the report like a unified diff, + for added, - for removed and ~ for changed objects
+func printText(w io.Writer, report *snapshot.DiffReport) error {
+	for _, obj := range report.Added {
+		_, err := fmt.Fprintf(w, "+ %s\n", objectRef(obj))
+		if err != nil {
+			return err
+		}
+	}
+	for _, obj := range report.Removed {
+		_, err := fmt.Fprintf(w, "- %s\n", objectRef(obj))
+		if err != nil {
+			return err
+		}
+	}
+	for _, obj := range report.Changed {
+		_, err := fmt.Fprintf(w, "~ %s\n", objectRef(obj))
+		if err != nil {
+			return err
+		}
+		for _, field := range obj.Fields {
+			_, err = fmt.Fprintf(w, "    %s: %s -> %s\n", field.Path, fieldValue(field.From), fieldValue(field.To))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func objectRef(obj snapshot.DiffObject) string {
+	if obj.Namespace == "" {
+		return fmt.Sprintf("%s/%s %s", obj.APIVersion, obj.Kind, obj.Name)
+	}
+	return fmt.Sprintf("%s/%s %s/%s", obj.APIVersion, obj.Kind, obj.Namespace, obj.Name)
+}
+
+func fieldValue(v any) string {
+	if v == nil {
+		return "<none>"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"testing"
+
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/kwok/pkg/kwokctl/snapshot"
+	"sigs.k8s.io/kwok/pkg/utils/client"
+)
+
+func TestNewSchemeRESTMapper(t *testing.T) {
+	mapper := newSchemeRESTMapper(scheme.Scheme)
+	mappings, errs := client.MappingForResources(mapper, snapshot.Resources)
+	if len(errs) != 0 {
+		t.Fatalf("MappingForResources() errors = %v", errs)
+	}
+	if len(mappings) != len(snapshot.Resources) {
+		t.Errorf("MappingForResources() got %d mappings, want %d", len(mappings), len(snapshot.Resources))
+	}
+}
@@ -26,11 +26,11 @@ import (
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
 	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
 	"sigs.k8s.io/kwok/pkg/kwokctl/snapshot"
 	"sigs.k8s.io/kwok/pkg/log"
 	"sigs.k8s.io/kwok/pkg/utils/file"
-	"sigs.k8s.io/kwok/pkg/utils/path"
 )
 
 type flagpole struct {
@@ -38,6 +38,11 @@ type flagpole struct {
 	Path    string
 	Format  string
 	Filters []string
+	// OnConflict is how to resolve the conflicts with the existing resources
+	OnConflict string
+	Prune      bool
+
+	S3 snapshot.S3Config
 }
 
 // NewCommand returns a new cobra.Command to restore the cluster as a snapshot.
@@ -53,21 +58,42 @@ func NewCommand(ctx context.Context) *cobra.Command {
 			return runE(cmd.Context(), flags)
 		},
 	}
-	cmd.Flags().StringVar(&flags.Path, "path", "", "Path to the snapshot")
-	cmd.Flags().StringVar(&flags.Format, "format", "etcd", "Format of the snapshot file (etcd, k8s)")
+	cmd.Flags().StringVar(&flags.Path, "path", "", "Path to the snapshot, - reads the snapshot from stdin, only support for etcd format, it is the name of the object under --s3-prefix for s3 format")
+	cmd.Flags().StringVar(&flags.Format, "format", "etcd", "Format of the snapshot file (etcd, k8s, s3), s3 downloads the etcd snapshot from an S3 compatible object storage")
 	cmd.Flags().StringSliceVar(&flags.Filters, "filter", snapshot.Resources, "Filter the resources to restore, only support for k8s format")
+	cmd.Flags().StringVar(&flags.OnConflict, "on-conflict", string(snapshot.ConflictStrategySkip), "How to resolve the conflicts with the existing resources, Skip skips them and Retry re-reads and re-applies them with server-side apply, only support for k8s format")
+	cmd.Flags().BoolVar(&flags.Prune, "prune", false, "Delete the resources of the filters in the cluster that are absent from the snapshot, only support for k8s format")
+	cmd.Flags().StringVar(&flags.S3.Endpoint, "s3-endpoint", snapshot.DefaultS3Endpoint, "Endpoint of the S3 compatible object storage, prefixed with http:// to access it without TLS, only support for s3 format")
+	cmd.Flags().StringVar(&flags.S3.Bucket, "s3-bucket", "", "Bucket of the snapshot in the object storage, only support for s3 format")
+	cmd.Flags().StringVar(&flags.S3.Prefix, "s3-prefix", "", "Prefix of the key of the snapshot in the bucket, only support for s3 format")
 	return cmd
 }
 
 func runE(ctx context.Context, flags *flagpole) error {
 	name := config.ClusterName(flags.Name)
-	workdir := path.Join(config.ClustersDir, flags.Name)
+	workdir := config.ClusterWorkdir(flags.Name)
 	if flags.Path == "" {
 		return fmt.Errorf("path is required")
 	}
-	if !file.Exists(flags.Path) {
+	if flags.Format == "s3" {
+		if flags.S3.Bucket == "" {
+			return fmt.Errorf("--s3-bucket is required for s3 format")
+		}
+	} else if flags.Path == "-" {
+		if flags.Format != "etcd" {
+			return fmt.Errorf("reading the snapshot from stdin is only supported for etcd format")
+		}
+	} else if !file.Exists(flags.Path) {
 		return fmt.Errorf("path %q does not exist", flags.Path)
 	}
+	if flags.Prune {
+		if flags.Format != "k8s" {
+			return fmt.Errorf("prune is only supported for k8s format")
+		}
+		if len(flags.Filters) == 0 {
+			return fmt.Errorf("prune requires the filters of the resources to delete")
+		}
+	}
 
 	logger := log.FromContext(ctx)
 	logger = logger.With("cluster", flags.Name)
@@ -83,13 +109,30 @@ func runE(ctx context.Context, flags *flagpole) error {
 
 	switch flags.Format {
 	case "etcd":
-		err = rt.SnapshotRestore(ctx, flags.Path)
+		if flags.Path == "-" {
+			err = rt.SnapshotRestoreFromReader(ctx, os.Stdin)
+		} else {
+			err = rt.SnapshotRestore(ctx, flags.Path)
+		}
+		if err != nil {
+			return err
+		}
+	case "s3":
+		err = restoreFromS3(ctx, rt, flags.S3, flags.Path)
 		if err != nil {
 			return err
 		}
 	case "k8s":
+		conflictStrategy := snapshot.ConflictStrategy(flags.OnConflict)
+		switch conflictStrategy {
+		case snapshot.ConflictStrategySkip, snapshot.ConflictStrategyRetry:
+		default:
+			return fmt.Errorf("unsupport conflict strategy %q, must be one of %s or %s", flags.OnConflict, snapshot.ConflictStrategySkip, snapshot.ConflictStrategyRetry)
+		}
 		err = rt.SnapshotRestoreWithYAML(ctx, flags.Path, runtime.SnapshotRestoreWithYAMLConfig{
-			Filters: flags.Filters,
+			Filters:          flags.Filters,
+			ConflictStrategy: conflictStrategy,
+			Prune:            flags.Prune,
 		})
 		if err != nil {
 			return err
@@ -99,3 +142,20 @@ func runE(ctx context.Context, flags *flagpole) error {
 	}
 	return nil
 }
+
+// restoreFromS3 restores the etcd snapshot of the cluster streamed from the object storage,
+// the download is printed as an aws command in dry-run.
+func restoreFromS3(ctx context.Context, rt runtime.Runtime, conf snapshot.S3Config, name string) error {
+	if dryrun.DryRun {
+		return rt.SnapshotRestoreFromReader(ctx, dryrun.NewPipeFromCommandReader(conf.DownloadCommand(name)))
+	}
+
+	r, err := snapshot.NewS3Reader(ctx, conf, name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	return rt.SnapshotRestoreFromReader(ctx, r)
+}
@@ -26,11 +26,12 @@ import (
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
 	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
 	"sigs.k8s.io/kwok/pkg/kwokctl/snapshot"
 	"sigs.k8s.io/kwok/pkg/log"
 	"sigs.k8s.io/kwok/pkg/utils/file"
-	"sigs.k8s.io/kwok/pkg/utils/path"
+	"sigs.k8s.io/kwok/pkg/utils/slices"
 )
 
 type flagpole struct {
@@ -38,6 +39,13 @@ type flagpole struct {
 	Path    string
 	Format  string
 	Filters []string
+	Watch   bool
+
+	Anonymize              bool
+	AnonymizeAnnotations   []string
+	AnonymizeConfigMapKeys []string
+
+	S3 snapshot.S3Config
 }
 
 // NewCommand returns a new cobra.Command for cluster snapshotting.
@@ -53,19 +61,37 @@ func NewCommand(ctx context.Context) *cobra.Command {
 			return runE(cmd.Context(), flags)
 		},
 	}
-	cmd.Flags().StringVar(&flags.Path, "path", "", "Path to the snapshot")
-	cmd.Flags().StringVar(&flags.Format, "format", "etcd", "Format of the snapshot file (etcd, k8s)")
-	cmd.Flags().StringSliceVar(&flags.Filters, "filter", snapshot.Resources, "Filter the resources to save, only support for k8s format")
+	cmd.Flags().StringVar(&flags.Path, "path", "", "Path to the snapshot, - writes the snapshot to stdout, only support for etcd format, it is the name of the object under --s3-prefix for s3 format")
+	cmd.Flags().StringVar(&flags.Format, "format", "etcd", "Format of the snapshot file (etcd, k8s, jsonl, s3), jsonl is the k8s format with one JSON object per line, s3 uploads the etcd snapshot to an S3 compatible object storage")
+	cmd.Flags().StringSliceVar(&flags.Filters, "filter", append(slices.Clone(snapshot.Resources), snapshot.FlowControlResources...), "Filter the resources to save, only support for k8s and jsonl format")
+	cmd.Flags().BoolVar(&flags.Watch, "watch", false, "Keep watching the resources and append the changes to the snapshot until interrupted, only support for k8s and jsonl format")
+	cmd.Flags().BoolVar(&flags.Anonymize, "anonymize", false, "Redact the sensitive data for sharing the snapshot, the data and stringData of Secret are always redacted, only support for k8s and jsonl format")
+	cmd.Flags().StringSliceVar(&flags.AnonymizeAnnotations, "anonymize-annotation", nil, "Regexp of the annotation keys whose values are redacted in all resources, only valid with --anonymize")
+	cmd.Flags().StringSliceVar(&flags.AnonymizeConfigMapKeys, "anonymize-configmap-key", nil, "Regexp of the keys whose values are redacted in the data of ConfigMap, e.g. token, only valid with --anonymize")
+	cmd.Flags().StringVar(&flags.S3.Endpoint, "s3-endpoint", snapshot.DefaultS3Endpoint, "Endpoint of the S3 compatible object storage, prefixed with http:// to access it without TLS, only support for s3 format")
+	cmd.Flags().StringVar(&flags.S3.Bucket, "s3-bucket", "", "Bucket of the snapshot in the object storage, only support for s3 format")
+	cmd.Flags().StringVar(&flags.S3.Prefix, "s3-prefix", "", "Prefix of the key of the snapshot in the bucket, only support for s3 format")
 	return cmd
 }
 
 func runE(ctx context.Context, flags *flagpole) error {
 	name := config.ClusterName(flags.Name)
-	workdir := path.Join(config.ClustersDir, flags.Name)
+	workdir := config.ClusterWorkdir(flags.Name)
 	if flags.Path == "" {
 		return fmt.Errorf("path is required")
 	}
-	if file.Exists(flags.Path) {
+	if !flags.Anonymize && (len(flags.AnonymizeAnnotations) != 0 || len(flags.AnonymizeConfigMapKeys) != 0) {
+		return fmt.Errorf("--anonymize-annotation and --anonymize-configmap-key are only valid with --anonymize")
+	}
+	if flags.Format == "s3" {
+		if flags.S3.Bucket == "" {
+			return fmt.Errorf("--s3-bucket is required for s3 format")
+		}
+	} else if flags.Path == "-" {
+		if flags.Format != "etcd" {
+			return fmt.Errorf("writing the snapshot to stdout is only supported for etcd format")
+		}
+	} else if file.Exists(flags.Path) {
 		return fmt.Errorf("file %q already exists", flags.Path)
 	}
 
@@ -82,20 +108,66 @@ func runE(ctx context.Context, flags *flagpole) error {
 	}
 
 	switch flags.Format {
-	case "etcd":
-		err = rt.SnapshotSave(ctx, flags.Path)
+	case "etcd", "s3":
+		if flags.Watch {
+			return fmt.Errorf("watch is only supported for k8s and jsonl format")
+		}
+		if flags.Anonymize {
+			return fmt.Errorf("anonymize is only supported for k8s and jsonl format")
+		}
+		switch {
+		case flags.Format == "s3":
+			err = saveToS3(ctx, rt, flags.S3, flags.Path)
+		case flags.Path == "-":
+			err = rt.SnapshotSaveToWriter(ctx, os.Stdout)
+		default:
+			err = rt.SnapshotSave(ctx, flags.Path)
+		}
 		if err != nil {
 			return err
 		}
-	case "k8s":
+	case "k8s", "jsonl":
 		err = rt.SnapshotSaveWithYAML(ctx, flags.Path, runtime.SnapshotSaveWithYAMLConfig{
-			Filters: flags.Filters,
+			Filters:   flags.Filters,
+			Watch:     flags.Watch,
+			Anonymize: flags.Anonymize,
+			AnonymizeConfig: snapshot.AnonymizeConfig{
+				Annotations:   flags.AnonymizeAnnotations,
+				ConfigMapKeys: flags.AnonymizeConfigMapKeys,
+			},
+			JSONLines: flags.Format == "jsonl",
 		})
 		if err != nil {
 			return err
 		}
 	default:
-		return fmt.Errorf("unsupport format %q", flags.Format)
+		return fmt.Errorf("unsupported format %q, must be one of etcd, k8s, jsonl, s3", flags.Format)
+	}
+	return nil
+}
+
+// saveToS3 streams the etcd snapshot of the cluster to the object storage,
+// the upload is printed as an aws command in dry-run.
+func saveToS3(ctx context.Context, rt runtime.Runtime, conf snapshot.S3Config, name string) error {
+	if dryrun.DryRun {
+		return rt.SnapshotSaveToWriter(ctx, dryrun.NewPipeToCommandWriter(conf.UploadCommand(name)))
 	}
+
+	w, err := snapshot.NewS3Writer(ctx, conf, name)
+	if err != nil {
+		return err
+	}
+	err = rt.SnapshotSaveToWriter(ctx, w)
+	if err != nil {
+		w.Abort(err)
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	logger := log.FromContext(ctx)
+	logger.Info("Uploaded snapshot", "url", conf.URL(name))
 	return nil
 }
@@ -28,11 +28,11 @@ import (
 	"sigs.k8s.io/kwok/pkg/config"
 	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
 	"sigs.k8s.io/kwok/pkg/log"
-	"sigs.k8s.io/kwok/pkg/utils/path"
 )
 
 type flagpole struct {
-	Name string
+	Name        string
+	SaveMetrics bool
 }
 
 // NewCommand returns a new cobra.Command for stop cluster
@@ -48,12 +48,14 @@ func NewCommand(ctx context.Context) *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolVar(&flags.SaveMetrics, "save-metrics", flags.SaveMetrics, "Scrape the metrics of components before stopping and save them to the workdir")
+
 	return cmd
 }
 
 func runE(ctx context.Context, flags *flagpole) error {
 	name := config.ClusterName(flags.Name)
-	workdir := path.Join(config.ClustersDir, flags.Name)
+	workdir := config.ClusterWorkdir(flags.Name)
 
 	logger := log.FromContext(ctx)
 	logger = logger.With("cluster", flags.Name)
@@ -67,6 +69,15 @@ func runE(ctx context.Context, flags *flagpole) error {
 		return err
 	}
 
+	if flags.SaveMetrics {
+		dir := rt.GetWorkdirPath(runtime.MetricsName)
+		logger.Info("Saving metrics", "dir", dir)
+		err = runtime.SaveMetrics(ctx, rt, dir)
+		if err != nil {
+			logger.Error("Failed to save metrics", err)
+		}
+	}
+
 	start := time.Now()
 	logger.Info("Cluster is stopping")
 	err = rt.Stop(ctx)
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package version contains a command to print the version of kwokctl and the components.
+package version
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/consts"
+	"sigs.k8s.io/kwok/pkg/utils/printers"
+	"sigs.k8s.io/kwok/pkg/utils/version"
+)
+
+type flagpole struct {
+	Components bool
+}
+
+// NewCommand returns a new cobra.Command for version
+func NewCommand(ctx context.Context) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "version",
+		Short: "Print the version of kwokctl, and the components with --components",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(cmd.Context(), flags)
+		},
+	}
+	cmd.Flags().BoolVar(&flags.Components, "components", flags.Components, "Print the versions and the images or binaries of the components that create would use with the current config")
+	return cmd
+}
+
+func runE(ctx context.Context, flags *flagpole) error {
+	fmt.Println(version.DisplayVersion())
+	if !flags.Components {
+		return nil
+	}
+
+	conf := config.GetKwokctlConfiguration(ctx)
+	if conf.Options.Runtime != "" {
+		fmt.Printf("Runtime: %s\n", conf.Options.Runtime)
+	} else {
+		fmt.Printf("Runtime: the first available of %s\n", strings.Join(conf.Options.Runtimes, ", "))
+	}
+	w := printers.NewTablePrinter(os.Stdout)
+	return w.WriteAll(componentVersions(conf.Options))
+}
+
+// componentVersions returns the versions of the components resolved by the options,
+// with the images for the container runtimes and the binaries for the binary runtime.
+func componentVersions(opts internalversion.KwokctlConfigurationOptions) [][]string {
+	binary := opts.Runtime == consts.RuntimeTypeBinary
+	kind := strings.HasPrefix(opts.Runtime, consts.RuntimeTypeKind)
+
+	header := "IMAGE"
+	if binary {
+		header = "BINARY"
+	}
+	records := [][]string{
+		{"NAME", "VERSION", header},
+	}
+	add := func(name, ver, image, bin string) {
+		source := image
+		if binary {
+			source = bin
+		}
+		if source == "" {
+			source = "<none>"
+		}
+		records = append(records, []string{name, ver, source})
+	}
+
+	// The kube components of kind are from the node image.
+	kubeImage := func(image string) string {
+		if kind {
+			return opts.KindNodeImage
+		}
+		return image
+	}
+
+	add(consts.ComponentEtcd, opts.EtcdVersion, kubeImage(opts.EtcdImage), opts.EtcdBinary)
+	add(consts.ComponentKubeApiserver, opts.KubeVersion, kubeImage(opts.KubeApiserverImage), opts.KubeApiserverBinary)
+	add(consts.ComponentKubeControllerManager, opts.KubeVersion, kubeImage(opts.KubeControllerManagerImage), opts.KubeControllerManagerBinary)
+	add(consts.ComponentKubeScheduler, opts.KubeVersion, kubeImage(opts.KubeSchedulerImage), opts.KubeSchedulerBinary)
+	add(consts.ComponentKwokController, opts.KwokVersion, opts.KwokControllerImage, opts.KwokControllerBinary)
+	add(consts.ComponentDashboard, opts.DashboardVersion, opts.DashboardImage, "")
+	add(consts.ComponentDashboardMetricsScraper, opts.DashboardMetricsScraperVersion, opts.DashboardMetricsScraperImage, "")
+	add(consts.ComponentPrometheus, opts.PrometheusVersion, opts.PrometheusImage, opts.PrometheusBinary)
+	add(consts.ComponentJaeger, opts.JaegerVersion, opts.JaegerImage, opts.JaegerBinary)
+	add(consts.ComponentMetricsServer, opts.MetricsServerVersion, opts.MetricsServerImage, opts.MetricsServerBinary)
+	add("kubectl", opts.KubeVersion, opts.KubectlImage, opts.KubectlBinary)
+	if kind {
+		add("kind", opts.KindVersion, opts.KindNodeImage, opts.KindBinary)
+	}
+	return records
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/consts"
+)
+
+func TestComponentVersions(t *testing.T) {
+	opts := internalversion.KwokctlConfigurationOptions{
+		KubeVersion:                 "v1.31.0",
+		EtcdVersion:                 "3.5.15-0",
+		KwokVersion:                 "v0.6.0",
+		DashboardVersion:            "v2.7.0",
+		PrometheusVersion:           "v2.53.0",
+		EtcdImage:                   "registry.k8s.io/etcd:3.5.15-0",
+		KubeApiserverImage:          "registry.k8s.io/kube-apiserver:v1.31.0",
+		KubeControllerManagerImage:  "registry.k8s.io/kube-controller-manager:v1.31.0",
+		KubeSchedulerImage:          "registry.k8s.io/kube-scheduler:v1.31.0",
+		KwokControllerImage:         "registry.k8s.io/kwok/kwok:v0.6.0",
+		DashboardImage:              "docker.io/kubernetesui/dashboard:v2.7.0",
+		PrometheusImage:             "docker.io/prom/prometheus:v2.53.0",
+		KubectlImage:                "registry.k8s.io/kubectl:v1.31.0",
+		EtcdBinary:                  "/root/.kwok/cache/etcd",
+		KubeApiserverBinary:         "/root/.kwok/cache/kube-apiserver",
+		KubeControllerManagerBinary: "/root/.kwok/cache/kube-controller-manager",
+		KubeSchedulerBinary:         "/root/.kwok/cache/kube-scheduler",
+		KwokControllerBinary:        "/root/.kwok/cache/kwok",
+		PrometheusBinary:            "/root/.kwok/cache/prometheus",
+		KubectlBinary:               "/root/.kwok/cache/kubectl",
+		KindVersion:                 "v0.24.0",
+		KindNodeImage:               "docker.io/kindest/node:v1.31.0",
+		KindBinary:                  "/root/.kwok/cache/kind",
+	}
+	tests := []struct {
+		name    string
+		runtime string
+		want    [][]string
+	}{
+		{
+			name:    "container",
+			runtime: consts.RuntimeTypeDocker,
+			want: [][]string{
+				{"NAME", "VERSION", "IMAGE"},
+				{"etcd", "3.5.15-0", "registry.k8s.io/etcd:3.5.15-0"},
+				{"kube-apiserver", "v1.31.0", "registry.k8s.io/kube-apiserver:v1.31.0"},
+				{"kube-controller-manager", "v1.31.0", "registry.k8s.io/kube-controller-manager:v1.31.0"},
+				{"kube-scheduler", "v1.31.0", "registry.k8s.io/kube-scheduler:v1.31.0"},
+				{"kwok-controller", "v0.6.0", "registry.k8s.io/kwok/kwok:v0.6.0"},
+				{"dashboard", "v2.7.0", "docker.io/kubernetesui/dashboard:v2.7.0"},
+				{"dashboard-metrics-scraper", "", "<none>"},
+				{"prometheus", "v2.53.0", "docker.io/prom/prometheus:v2.53.0"},
+				{"jaeger", "", "<none>"},
+				{"metrics-server", "", "<none>"},
+				{"kubectl", "v1.31.0", "registry.k8s.io/kubectl:v1.31.0"},
+			},
+		},
+		{
+			name:    "binary",
+			runtime: consts.RuntimeTypeBinary,
+			want: [][]string{
+				{"NAME", "VERSION", "BINARY"},
+				{"etcd", "3.5.15-0", "/root/.kwok/cache/etcd"},
+				{"kube-apiserver", "v1.31.0", "/root/.kwok/cache/kube-apiserver"},
+				{"kube-controller-manager", "v1.31.0", "/root/.kwok/cache/kube-controller-manager"},
+				{"kube-scheduler", "v1.31.0", "/root/.kwok/cache/kube-scheduler"},
+				{"kwok-controller", "v0.6.0", "/root/.kwok/cache/kwok"},
+				{"dashboard", "v2.7.0", "<none>"},
+				{"dashboard-metrics-scraper", "", "<none>"},
+				{"prometheus", "v2.53.0", "/root/.kwok/cache/prometheus"},
+				{"jaeger", "", "<none>"},
+				{"metrics-server", "", "<none>"},
+				{"kubectl", "v1.31.0", "/root/.kwok/cache/kubectl"},
+			},
+		},
+		{
+			name:    "kind",
+			runtime: consts.RuntimeTypeKindPodman,
+			want: [][]string{
+				{"NAME", "VERSION", "IMAGE"},
+				{"etcd", "3.5.15-0", "docker.io/kindest/node:v1.31.0"},
+				{"kube-apiserver", "v1.31.0", "docker.io/kindest/node:v1.31.0"},
+				{"kube-controller-manager", "v1.31.0", "docker.io/kindest/node:v1.31.0"},
+				{"kube-scheduler", "v1.31.0", "docker.io/kindest/node:v1.31.0"},
+				{"kwok-controller", "v0.6.0", "registry.k8s.io/kwok/kwok:v0.6.0"},
+				{"dashboard", "v2.7.0", "docker.io/kubernetesui/dashboard:v2.7.0"},
+				{"dashboard-metrics-scraper", "", "<none>"},
+				{"prometheus", "v2.53.0", "docker.io/prom/prometheus:v2.53.0"},
+				{"jaeger", "", "<none>"},
+				{"metrics-server", "", "<none>"},
+				{"kubectl", "v1.31.0", "registry.k8s.io/kubectl:v1.31.0"},
+				{"kind", "v0.24.0", "docker.io/kindest/node:v1.31.0"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := opts
+			opts.Runtime = tt.runtime
+			got := componentVersions(opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("componentVersions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package wait implements the wait command
+package wait
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+	"sigs.k8s.io/kwok/pkg/kwokctl/wait"
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/client"
+)
+
+type flagpole struct {
+	Name      string
+	Namespace string
+	For       string
+	Timeout   time.Duration
+}
+
+// NewCommand returns a new cobra.Command for wait
+func NewCommand(ctx context.Context) *cobra.Command {
+	flags := &flagpole{}
+
+	cmd := &cobra.Command{
+		Args:  cobra.ExactArgs(1),
+		Use:   "wait [kind/name]",
+		Short: "Wait for a node or pod to match the condition",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags.Name = config.DefaultCluster
+			return runE(cmd.Context(), flags, args[0])
+		},
+	}
+	cmd.Flags().StringVarP(&flags.Namespace, "namespace", "n", "default", "Namespace of the pod")
+	cmd.Flags().StringVar(&flags.For, "for", "", "The condition to wait on: condition=<type>[=<status>] or phase=<phase>")
+	cmd.Flags().DurationVar(&flags.Timeout, "timeout", 30*time.Second, "Timeout to wait for the condition")
+	return cmd
+}
+
+func runE(ctx context.Context, flags *flagpole, resource string) error {
+	kind, resourceName, err := wait.ParseResource(resource)
+	if err != nil {
+		return err
+	}
+	cond, err := wait.ParseCondition(flags.For)
+	if err != nil {
+		return err
+	}
+
+	name := config.ClusterName(flags.Name)
+	workdir := config.ClusterWorkdir(flags.Name)
+
+	logger := log.FromContext(ctx)
+	logger = logger.With("cluster", flags.Name)
+	ctx = log.NewContext(ctx, logger)
+
+	rt, err := runtime.DefaultRegistry.Load(ctx, name, workdir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Cluster does not exist")
+		}
+		return err
+	}
+
+	if dryrun.DryRun {
+		dryrun.PrintMessage("kubectl wait --for=%s %s/%s --namespace=%s --timeout=%s", cond.KubectlFor(), kind, resourceName, flags.Namespace, flags.Timeout)
+		return nil
+	}
+
+	kubeconfigPath := rt.GetWorkdirPath(runtime.InHostKubeconfigName)
+	clientset, err := client.NewClientset("", kubeconfigPath)
+	if err != nil {
+		return err
+	}
+	restConfig, err := clientset.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	typedClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	err = wait.Wait(ctx, typedClient, wait.Config{
+		Kind:      kind,
+		Namespace: flags.Namespace,
+		Name:      resourceName,
+		Condition: cond,
+		Timeout:   flags.Timeout,
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Condition met", "resource", resource, "for", cond.String())
+	return nil
+}
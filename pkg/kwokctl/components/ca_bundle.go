@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+)
+
+// caBundleMountPath is the path the CA bundle is mounted to in the container of the component.
+const caBundleMountPath = "/etc/kwok/pki/ca-bundle.crt"
+
+// ApplyCABundle makes the component trust the CA bundle for its outgoing TLS connections,
+// the SSL_CERT_FILE env is honored by the components written in Go on Linux.
+func ApplyCABundle(runtime string, component *internalversion.Component, caBundlePath string) {
+	if caBundlePath == "" {
+		return
+	}
+
+	certFile := caBundlePath
+	if GetRuntimeMode(runtime) != RuntimeModeNative {
+		certFile = caBundleMountPath
+		component.Volumes = append(component.Volumes,
+			internalversion.Volume{
+				HostPath:  caBundlePath,
+				MountPath: caBundleMountPath,
+				ReadOnly:  true,
+			},
+		)
+	}
+	component.Envs = append(component.Envs,
+		internalversion.Env{
+			Name:  "SSL_CERT_FILE",
+			Value: certFile,
+		},
+	)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/consts"
+)
+
+func TestApplyCABundle(t *testing.T) {
+	tests := []struct {
+		name         string
+		runtime      string
+		caBundlePath string
+		want         internalversion.Component
+	}{
+		{
+			name:    "no ca bundle",
+			runtime: consts.RuntimeTypeBinary,
+			want: internalversion.Component{
+				Name: "kube-apiserver",
+			},
+		},
+		{
+			name:         "binary",
+			runtime:      consts.RuntimeTypeBinary,
+			caBundlePath: "/workdir/pki/ca-bundle.crt",
+			want: internalversion.Component{
+				Name: "kube-apiserver",
+				Envs: []internalversion.Env{
+					{Name: "SSL_CERT_FILE", Value: "/workdir/pki/ca-bundle.crt"},
+				},
+			},
+		},
+		{
+			name:         "container",
+			runtime:      consts.RuntimeTypeDocker,
+			caBundlePath: "/workdir/pki/ca-bundle.crt",
+			want: internalversion.Component{
+				Name: "kube-apiserver",
+				Volumes: []internalversion.Volume{
+					{HostPath: "/workdir/pki/ca-bundle.crt", MountPath: caBundleMountPath, ReadOnly: true},
+				},
+				Envs: []internalversion.Env{
+					{Name: "SSL_CERT_FILE", Value: caBundleMountPath},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component := internalversion.Component{
+				Name: "kube-apiserver",
+			}
+			ApplyCABundle(tt.runtime, &component, tt.caBundlePath)
+			if diff := cmp.Diff(tt.want, component); diff != "" {
+				t.Errorf("ApplyCABundle() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
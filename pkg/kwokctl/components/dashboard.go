@@ -20,6 +20,7 @@ import (
 	"sigs.k8s.io/kwok/pkg/apis/internalversion"
 	"sigs.k8s.io/kwok/pkg/consts"
 	"sigs.k8s.io/kwok/pkg/utils/format"
+	"sigs.k8s.io/kwok/pkg/utils/net"
 	"sigs.k8s.io/kwok/pkg/utils/version"
 )
 
@@ -72,6 +73,7 @@ func BuildDashboardComponent(conf BuildDashboardComponentConfig) (component inte
 	user := ""
 	var volumes []internalversion.Volume
 	var ports []internalversion.Port
+	var readiness *internalversion.ComponentReadiness
 	if GetRuntimeMode(conf.Runtime) != RuntimeModeNative {
 		dashboardArgs = append(dashboardArgs,
 			"--kubeconfig=/root/.kube/config",
@@ -107,6 +109,11 @@ func BuildDashboardComponent(conf BuildDashboardComponentConfig) (component inte
 				Protocol: internalversion.ProtocolTCP,
 			},
 		)
+		readiness = &internalversion.ComponentReadiness{
+			Scheme: "http",
+			Host:   conf.ProjectName + "-" + consts.ComponentDashboard + ":8080",
+			Path:   "/",
+		}
 	} else {
 		dashboardArgs = append(dashboardArgs,
 			"--kubeconfig="+conf.KubeconfigPath,
@@ -120,6 +127,11 @@ func BuildDashboardComponent(conf BuildDashboardComponentConfig) (component inte
 				Protocol: internalversion.ProtocolTCP,
 			},
 		)
+		readiness = &internalversion.ComponentReadiness{
+			Scheme: "http",
+			Host:   net.LocalAddress + ":" + format.String(conf.Port),
+			Path:   "/",
+		}
 	}
 
 	component = internalversion.Component{
@@ -128,11 +140,12 @@ func BuildDashboardComponent(conf BuildDashboardComponentConfig) (component inte
 		Links: []string{
 			consts.ComponentKubeApiserver,
 		},
-		WorkDir: conf.Workdir,
-		Ports:   ports,
-		Volumes: volumes,
-		Args:    dashboardArgs,
-		User:    user,
+		WorkDir:   conf.Workdir,
+		Ports:     ports,
+		Volumes:   volumes,
+		Args:      dashboardArgs,
+		User:      user,
+		Readiness: readiness,
 	}
 	return component, nil
 }
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/utils/version"
+)
+
+func TestBuildDashboardComponentReadiness(t *testing.T) {
+	tests := []struct {
+		name          string
+		runtime       string
+		wantReadiness *internalversion.ComponentReadiness
+	}{
+		{
+			name:    "docker",
+			runtime: "docker",
+			wantReadiness: &internalversion.ComponentReadiness{
+				Scheme: "http",
+				Host:   "kwok-test-dashboard:8080",
+				Path:   "/",
+			},
+		},
+		{
+			name:    "binary",
+			runtime: "binary",
+			wantReadiness: &internalversion.ComponentReadiness{
+				Scheme: "http",
+				Host:   "127.0.0.1:8000",
+				Path:   "/",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component, err := BuildDashboardComponent(BuildDashboardComponentConfig{
+				Runtime:     tt.runtime,
+				ProjectName: "kwok-test",
+				Version:     version.NewVersion(2, 7, 0),
+				BindAddress: "0.0.0.0",
+				Port:        8000,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(component.Readiness, tt.wantReadiness) {
+				t.Errorf("BuildDashboardComponent() readiness = %+v, want %+v", component.Readiness, tt.wantReadiness)
+			}
+		})
+	}
+}
@@ -18,8 +18,11 @@ package components
 
 import (
 	"fmt"
+	"net/url"
 	"runtime"
 	"strconv"
+	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/resource"
 
@@ -28,6 +31,7 @@ import (
 	"sigs.k8s.io/kwok/pkg/log"
 	"sigs.k8s.io/kwok/pkg/utils/format"
 	"sigs.k8s.io/kwok/pkg/utils/net"
+	"sigs.k8s.io/kwok/pkg/utils/slices"
 	"sigs.k8s.io/kwok/pkg/utils/version"
 )
 
@@ -45,6 +49,50 @@ type BuildEtcdComponentConfig struct {
 	PeerPort         uint32
 	Verbosity        log.Level
 	QuotaBackendSize string
+	UnsafeNoFsync    bool
+	ExtraClientURLs  []string
+
+	HeartbeatInterval string
+	ElectionTimeout   string
+
+	// Index is the index of this member in Members.
+	Index int
+	// Members is all the members of etcd, it is empty for a single member.
+	Members []EtcdMember
+}
+
+// EtcdMember is a member of etcd with multiple members.
+type EtcdMember struct {
+	// ComponentName is the name of the component of the member.
+	ComponentName string
+	// Address is the address of the member reachable by the other members and components.
+	Address string
+	// Port is the client port of the member.
+	Port uint32
+	// PeerPort is the peer port of the member.
+	PeerPort uint32
+}
+
+// EtcdComponentName returns the name of the component of the etcd member of the index,
+// the first member keeps the name etcd so that the snapshots and etcdctl work on it.
+func EtcdComponentName(index int) string {
+	if index == 0 {
+		return consts.ComponentEtcd
+	}
+	return consts.ComponentEtcd + "-" + format.String(index)
+}
+
+func etcdMemberName(index int) string {
+	return "node" + format.String(index)
+}
+
+// etcdInitialCluster returns the --initial-cluster of etcd for the members
+func etcdInitialCluster(members []EtcdMember) string {
+	cluster := make([]string, 0, len(members))
+	for i, member := range members {
+		cluster = append(cluster, etcdMemberName(i)+"=http://"+member.Address+":"+format.String(member.PeerPort))
+	}
+	return strings.Join(cluster, ",")
 }
 
 // BuildEtcdComponent builds an etcd component.
@@ -52,20 +100,58 @@ func BuildEtcdComponent(conf BuildEtcdComponentConfig) (component internalversio
 	var volumes []internalversion.Volume
 	var ports []internalversion.Port
 
-	quantity, err := resource.ParseQuantity(conf.QuotaBackendSize)
+	name := consts.ComponentEtcd
+	var member *EtcdMember
+	if len(conf.Members) > 1 {
+		if conf.Index < 0 || conf.Index >= len(conf.Members) {
+			return internalversion.Component{}, fmt.Errorf("invalid index %d of etcd members, must be less than %d", conf.Index, len(conf.Members))
+		}
+		member = &conf.Members[conf.Index]
+		name = member.ComponentName
+	}
+
+	etcdArgs := []string{
+		"--name=" + etcdMemberName(conf.Index),
+		"--auto-compaction-retention=1",
+	}
+
+	if conf.QuotaBackendSize != "" {
+		quantity, err := resource.ParseQuantity(conf.QuotaBackendSize)
+		if err != nil {
+			return internalversion.Component{}, err
+		}
+
+		quotaBackendSize, ok := quantity.AsInt64()
+		if !ok {
+			return internalversion.Component{}, fmt.Errorf("failed to convert quota backend size to int64")
+		}
+
+		// Zero is the default quota of etcd, so the flag is left out.
+		if quotaBackendSize != 0 {
+			etcdArgs = append(etcdArgs, "--quota-backend-bytes="+strconv.FormatInt(quotaBackendSize, 10))
+		}
+	}
+
+	if conf.UnsafeNoFsync {
+		if conf.Version.LT(version.NewVersion(3, 5, 0)) {
+			return internalversion.Component{}, fmt.Errorf("unsafe no fsync requires etcd 3.5 or later, got %s", conf.Version)
+		}
+		etcdArgs = append(etcdArgs, "--unsafe-no-fsync")
+	}
+
+	timeoutArgs, _, err := EtcdTimeoutArgs(conf.HeartbeatInterval, conf.ElectionTimeout)
 	if err != nil {
 		return internalversion.Component{}, err
 	}
-
-	quotaBackendSize, ok := quantity.AsInt64()
-	if !ok {
-		return internalversion.Component{}, fmt.Errorf("failed to convert quota backend size to int64")
+	for _, arg := range timeoutArgs {
+		etcdArgs = append(etcdArgs, "--"+arg.Key+"="+arg.Value)
 	}
 
-	etcdArgs := []string{
-		"--name=node0",
-		"--auto-compaction-retention=1",
-		"--quota-backend-bytes=" + strconv.FormatInt(quotaBackendSize, 10),
+	for _, u := range conf.ExtraClientURLs {
+		err = validateEtcdClientURL(u)
+		if err != nil {
+			return internalversion.Component{}, err
+		}
 	}
 
 	var metric *internalversion.ComponentMetric
@@ -97,17 +183,44 @@ func BuildEtcdComponent(conf BuildEtcdComponentConfig) (component internalversio
 				Protocol: internalversion.ProtocolTCP,
 			},
 		)
+		// The extra client urls are on the host, so etcd only listens in the container,
+		// and the ports of the extra urls are published to the client port of the container.
+		listenClientURL := "http://" + conf.BindAddress + ":2379"
+		hostPorts := []uint32{conf.Port}
+		for _, u := range conf.ExtraClientURLs {
+			hostPort, err := etcdClientURLPort(u)
+			if err != nil {
+				return internalversion.Component{}, err
+			}
+			if slices.Contains(hostPorts, hostPort) {
+				continue
+			}
+			hostPorts = append(hostPorts, hostPort)
+			ports = append(ports, internalversion.Port{
+				Name:     "http-" + format.String(hostPort),
+				HostPort: hostPort,
+				Port:     2379,
+				Protocol: internalversion.ProtocolTCP,
+			})
+		}
+		advertiseClientURLs := etcdClientURLs(listenClientURL, conf.ExtraClientURLs)
+		advertisePeerURL := "http://" + conf.BindAddress + ":2380"
+		initialCluster := "node0=" + advertisePeerURL
+		if member != nil {
+			advertisePeerURL = "http://" + member.Address + ":2380"
+			initialCluster = etcdInitialCluster(conf.Members)
+		}
 		etcdArgs = append(etcdArgs,
-			"--initial-advertise-peer-urls=http://"+conf.BindAddress+":2380",
+			"--initial-advertise-peer-urls="+advertisePeerURL,
 			"--listen-peer-urls=http://"+conf.BindAddress+":2380",
-			"--advertise-client-urls=http://"+conf.BindAddress+":2379",
-			"--listen-client-urls=http://"+conf.BindAddress+":2379",
-			"--initial-cluster=node0=http://"+conf.BindAddress+":2380",
+			"--advertise-client-urls="+advertiseClientURLs,
+			"--listen-client-urls="+listenClientURL,
+			"--initial-cluster="+initialCluster,
 		)
 
 		metric = &internalversion.ComponentMetric{
 			Scheme: "http",
-			Host:   conf.ProjectName + "-" + consts.ComponentEtcd + ":2379",
+			Host:   conf.ProjectName + "-" + name + ":2379",
 			Path:   "/metrics",
 		}
 	} else {
@@ -130,13 +243,20 @@ func BuildEtcdComponent(conf BuildEtcdComponentConfig) (component internalversio
 			},
 		)
 
+		clientURLs := etcdClientURLs("http://"+conf.BindAddress+":"+etcdClientPortStr, conf.ExtraClientURLs)
+		advertisePeerURL := "http://" + conf.BindAddress + ":" + etcdPeerPortStr
+		initialCluster := "node0=" + advertisePeerURL
+		if member != nil {
+			advertisePeerURL = "http://" + member.Address + ":" + etcdPeerPortStr
+			initialCluster = etcdInitialCluster(conf.Members)
+		}
 		etcdArgs = append(etcdArgs,
 			"--data-dir="+conf.DataPath,
-			"--initial-advertise-peer-urls=http://"+conf.BindAddress+":"+etcdPeerPortStr,
+			"--initial-advertise-peer-urls="+advertisePeerURL,
 			"--listen-peer-urls=http://"+conf.BindAddress+":"+etcdPeerPortStr,
-			"--advertise-client-urls=http://"+conf.BindAddress+":"+etcdClientPortStr,
-			"--listen-client-urls=http://"+conf.BindAddress+":"+etcdClientPortStr,
-			"--initial-cluster=node0=http://"+conf.BindAddress+":"+etcdPeerPortStr,
+			"--advertise-client-urls="+clientURLs,
+			"--listen-client-urls="+clientURLs,
+			"--initial-cluster="+initialCluster,
 		)
 
 		metric = &internalversion.ComponentMetric{
@@ -165,7 +285,7 @@ func BuildEtcdComponent(conf BuildEtcdComponentConfig) (component internalversio
 	}
 
 	return internalversion.Component{
-		Name:    consts.ComponentEtcd,
+		Name:    name,
 		Version: conf.Version.String(),
 		Volumes: volumes,
 		Command: []string{consts.ComponentEtcd},
@@ -178,3 +298,101 @@ func BuildEtcdComponent(conf BuildEtcdComponentConfig) (component internalversio
 		Envs:    envs,
 	}, nil
 }
+
+const (
+	// etcdDefaultHeartbeatInterval is the default value of --heartbeat-interval of etcd
+	etcdDefaultHeartbeatInterval = 100 * time.Millisecond
+	// etcdDefaultElectionTimeout is the default value of --election-timeout of etcd
+	etcdDefaultElectionTimeout = 1000 * time.Millisecond
+)
+
+// EtcdTimeoutArgs validates the heartbeat interval and the election timeout,
+// and returns the args of etcd for them.
+// The flags of etcd are in milliseconds, so the durations are rounded down to milliseconds.
+// etcd refuses to start if the election timeout is less than 5 times of the heartbeat interval,
+// and a warning is returned if it is less than the 10 times recommended by etcd.
+func EtcdTimeoutArgs(heartbeatInterval, electionTimeout string) ([]internalversion.ExtraArgs, string, error) {
+	var args []internalversion.ExtraArgs
+
+	heartbeat := etcdDefaultHeartbeatInterval
+	if heartbeatInterval != "" {
+		d, err := time.ParseDuration(heartbeatInterval)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid heartbeat interval %q of etcd: %w", heartbeatInterval, err)
+		}
+		if d < time.Millisecond {
+			return nil, "", fmt.Errorf("invalid heartbeat interval %q of etcd: must be at least 1ms", heartbeatInterval)
+		}
+		heartbeat = d.Truncate(time.Millisecond)
+		args = append(args, internalversion.ExtraArgs{
+			Key:   "heartbeat-interval",
+			Value: format.String(heartbeat.Milliseconds()),
+		})
+	}
+
+	election := etcdDefaultElectionTimeout
+	if electionTimeout != "" {
+		d, err := time.ParseDuration(electionTimeout)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid election timeout %q of etcd: %w", electionTimeout, err)
+		}
+		if d < time.Millisecond {
+			return nil, "", fmt.Errorf("invalid election timeout %q of etcd: must be at least 1ms", electionTimeout)
+		}
+		election = d.Truncate(time.Millisecond)
+		args = append(args, internalversion.ExtraArgs{
+			Key:   "election-timeout",
+			Value: format.String(election.Milliseconds()),
+		})
+	}
+
+	if election < 5*heartbeat {
+		return nil, "", fmt.Errorf("invalid election timeout %s of etcd: must be at least 5 times of the heartbeat interval %s", election, heartbeat)
+	}
+
+	var warning string
+	if election < 10*heartbeat {
+		warning = "The election timeout of etcd is less than 10 times of the heartbeat interval, the leader may be re-elected frequently"
+	}
+	return args, warning, nil
+}
+
+// etcdClientURLs returns the comma-separated client URLs of etcd,
+// etcd takes the last one if the flag is repeated, so the URLs are joined into a single flag.
+func etcdClientURLs(defaultURL string, extraURLs []string) string {
+	urls := []string{defaultURL}
+	for _, u := range extraURLs {
+		if !slices.Contains(urls, u) {
+			urls = append(urls, u)
+		}
+	}
+	return strings.Join(urls, ",")
+}
+
+// etcdClientURLPort returns the port of the client URL of etcd
+func etcdClientURLPort(s string) (uint32, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid etcd client url %q: %w", s, err)
+	}
+	port, err := strconv.ParseUint(u.Port(), 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid etcd client url %q: %w", s, err)
+	}
+	return uint32(port), nil
+}
+
+// validateEtcdClientURL validates the client URL of etcd
+func validateEtcdClientURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return fmt.Errorf("invalid etcd client url %q: %w", s, err)
+	}
+	if u.Scheme != "http" || u.Host == "" || u.Port() == "" {
+		return fmt.Errorf("invalid etcd client url %q: must be in the form of http://host:port", s)
+	}
+	if u.Path != "" && u.Path != "/" {
+		return fmt.Errorf("invalid etcd client url %q: must not have a path", s)
+	}
+	return nil
+}
@@ -0,0 +1,405 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/utils/slices"
+	"sigs.k8s.io/kwok/pkg/utils/version"
+)
+
+func TestBuildEtcdComponentUnsafeNoFsync(t *testing.T) {
+	tests := []struct {
+		name          string
+		version       version.Version
+		unsafeNoFsync bool
+		want          bool
+		wantErr       bool
+	}{
+		{
+			name:    "disabled",
+			version: version.NewVersion(3, 5, 0),
+		},
+		{
+			name:          "enabled",
+			version:       version.NewVersion(3, 5, 0),
+			unsafeNoFsync: true,
+			want:          true,
+		},
+		{
+			name:          "unsupported version",
+			version:       version.NewVersion(3, 4, 13),
+			unsafeNoFsync: true,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component, err := BuildEtcdComponent(BuildEtcdComponentConfig{
+				Runtime:          "binary",
+				Version:          tt.version,
+				BindAddress:      "127.0.0.1",
+				Port:             2379,
+				PeerPort:         2380,
+				QuotaBackendSize: "8Gi",
+				UnsafeNoFsync:    tt.unsafeNoFsync,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BuildEtcdComponent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := slices.Contains(component.Args, "--unsafe-no-fsync"); got != tt.want {
+				t.Errorf("want --unsafe-no-fsync %v, got %v in %q", tt.want, got, component.Args)
+			}
+		})
+	}
+}
+
+func TestBuildEtcdComponentExtraClientURLs(t *testing.T) {
+	tests := []struct {
+		name            string
+		runtime         string
+		bindAddress     string
+		port            uint32
+		extraClientURLs []string
+		wantListen      string
+		wantAdvertise   string
+		wantPorts       []internalversion.Port
+		wantErr         bool
+	}{
+		{
+			name:          "binary default",
+			runtime:       "binary",
+			bindAddress:   "127.0.0.1",
+			port:          2379,
+			wantListen:    "http://127.0.0.1:2379",
+			wantAdvertise: "http://127.0.0.1:2379",
+		},
+		{
+			name:            "binary extra urls",
+			runtime:         "binary",
+			bindAddress:     "127.0.0.1",
+			port:            2379,
+			extraClientURLs: []string{"http://10.0.0.1:2379", "http://127.0.0.1:2379", "http://[::1]:2379"},
+			wantListen:      "http://127.0.0.1:2379,http://10.0.0.1:2379,http://[::1]:2379",
+			wantAdvertise:   "http://127.0.0.1:2379,http://10.0.0.1:2379,http://[::1]:2379",
+		},
+		{
+			name:            "container extra urls",
+			runtime:         "docker",
+			bindAddress:     "0.0.0.0",
+			port:            32379,
+			extraClientURLs: []string{"http://10.0.0.1:32379", "http://10.0.0.2:32380", "http://10.0.0.3:32380"},
+			wantListen:      "http://0.0.0.0:2379",
+			wantAdvertise:   "http://0.0.0.0:2379,http://10.0.0.1:32379,http://10.0.0.2:32380,http://10.0.0.3:32380",
+			wantPorts: []internalversion.Port{
+				{
+					Name:     "http",
+					HostPort: 32379,
+					Port:     2379,
+					Protocol: internalversion.ProtocolTCP,
+				},
+				{
+					Name:     "http-32380",
+					HostPort: 32380,
+					Port:     2379,
+					Protocol: internalversion.ProtocolTCP,
+				},
+			},
+		},
+		{
+			name:            "without port",
+			runtime:         "binary",
+			bindAddress:     "127.0.0.1",
+			extraClientURLs: []string{"http://10.0.0.1"},
+			wantErr:         true,
+		},
+		{
+			name:            "https",
+			runtime:         "binary",
+			bindAddress:     "127.0.0.1",
+			extraClientURLs: []string{"https://10.0.0.1:2379"},
+			wantErr:         true,
+		},
+		{
+			name:            "with path",
+			runtime:         "binary",
+			bindAddress:     "127.0.0.1",
+			extraClientURLs: []string{"http://10.0.0.1:2379/etcd"},
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component, err := BuildEtcdComponent(BuildEtcdComponentConfig{
+				Runtime:          tt.runtime,
+				Version:          version.NewVersion(3, 5, 0),
+				BindAddress:      tt.bindAddress,
+				Port:             tt.port,
+				PeerPort:         2380,
+				QuotaBackendSize: "8Gi",
+				ExtraClientURLs:  tt.extraClientURLs,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BuildEtcdComponent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if want := "--listen-client-urls=" + tt.wantListen; !slices.Contains(component.Args, want) {
+				t.Errorf("want %s in %q", want, component.Args)
+			}
+			if want := "--advertise-client-urls=" + tt.wantAdvertise; !slices.Contains(component.Args, want) {
+				t.Errorf("want %s in %q", want, component.Args)
+			}
+			for _, port := range tt.wantPorts {
+				if !slices.Contains(component.Ports, port) {
+					t.Errorf("want port %v in %v", port, component.Ports)
+				}
+			}
+			if tt.wantPorts != nil && len(component.Ports) != len(tt.wantPorts)+1 {
+				t.Errorf("want ports %v and the peer port, got %v", tt.wantPorts, component.Ports)
+			}
+		})
+	}
+}
+
+func TestEtcdTimeoutArgs(t *testing.T) {
+	tests := []struct {
+		name              string
+		heartbeatInterval string
+		electionTimeout   string
+		want              []internalversion.ExtraArgs
+		wantWarning       bool
+		wantErr           bool
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:              "both",
+			heartbeatInterval: "200ms",
+			electionTimeout:   "2s",
+			want: []internalversion.ExtraArgs{
+				{Key: "heartbeat-interval", Value: "200"},
+				{Key: "election-timeout", Value: "2000"},
+			},
+		},
+		{
+			name:              "heartbeat interval only",
+			heartbeatInterval: "50ms",
+			want: []internalversion.ExtraArgs{
+				{Key: "heartbeat-interval", Value: "50"},
+			},
+		},
+		{
+			name:              "less than recommended",
+			heartbeatInterval: "100ms",
+			electionTimeout:   "500ms",
+			want: []internalversion.ExtraArgs{
+				{Key: "heartbeat-interval", Value: "100"},
+				{Key: "election-timeout", Value: "500"},
+			},
+			wantWarning: true,
+		},
+		{
+			name:              "heartbeat interval against default election timeout",
+			heartbeatInterval: "150ms",
+			want: []internalversion.ExtraArgs{
+				{Key: "heartbeat-interval", Value: "150"},
+			},
+			wantWarning: true,
+		},
+		{
+			name:              "less than minimum",
+			heartbeatInterval: "100ms",
+			electionTimeout:   "400ms",
+			wantErr:           true,
+		},
+		{
+			name:            "invalid",
+			electionTimeout: "1",
+			wantErr:         true,
+		},
+		{
+			name:              "sub-millisecond",
+			heartbeatInterval: "100us",
+			wantErr:           true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warning, err := EtcdTimeoutArgs(tt.heartbeatInterval, tt.electionTimeout)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EtcdTimeoutArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EtcdTimeoutArgs() got = %v, want %v", got, tt.want)
+			}
+			if (warning != "") != tt.wantWarning {
+				t.Errorf("EtcdTimeoutArgs() warning = %q, wantWarning %v", warning, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestBuildEtcdComponentQuotaBackendSize(t *testing.T) {
+	tests := []struct {
+		name             string
+		quotaBackendSize string
+		want             []string
+		wantErr          bool
+	}{
+		{
+			name:             "size",
+			quotaBackendSize: "8Gi",
+			want:             []string{"--quota-backend-bytes=8589934592"},
+		},
+		{
+			name:             "zero",
+			quotaBackendSize: "0",
+			want:             []string{},
+		},
+		{
+			name: "empty",
+			want: []string{},
+		},
+		{
+			name:             "invalid",
+			quotaBackendSize: "8GB",
+			wantErr:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component, err := BuildEtcdComponent(BuildEtcdComponentConfig{
+				Runtime:          "binary",
+				Version:          version.NewVersion(3, 5, 0),
+				BindAddress:      "127.0.0.1",
+				Port:             2379,
+				PeerPort:         2380,
+				QuotaBackendSize: tt.quotaBackendSize,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BuildEtcdComponent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got := slices.Filter(component.Args, func(arg string) bool {
+				return strings.HasPrefix(arg, "--quota-backend-bytes")
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBuildEtcdComponentMembers(t *testing.T) {
+	members := []EtcdMember{
+		{ComponentName: EtcdComponentName(0), Address: "kwok-etcd", Port: 2379, PeerPort: 2380},
+		{ComponentName: EtcdComponentName(1), Address: "kwok-etcd-1", Port: 2379, PeerPort: 2380},
+		{ComponentName: EtcdComponentName(2), Address: "kwok-etcd-2", Port: 2379, PeerPort: 2380},
+	}
+	initialCluster := "--initial-cluster=node0=http://kwok-etcd:2380,node1=http://kwok-etcd-1:2380,node2=http://kwok-etcd-2:2380"
+	tests := []struct {
+		name     string
+		index    int
+		members  []EtcdMember
+		wantName string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "single member",
+			wantName: "etcd",
+			want: []string{
+				"--name=node0",
+				"--initial-advertise-peer-urls=http://0.0.0.0:2380",
+				"--initial-cluster=node0=http://0.0.0.0:2380",
+			},
+		},
+		{
+			name:     "first member",
+			members:  members,
+			wantName: "etcd",
+			want: []string{
+				"--name=node0",
+				"--initial-advertise-peer-urls=http://kwok-etcd:2380",
+				"--listen-peer-urls=http://0.0.0.0:2380",
+				initialCluster,
+			},
+		},
+		{
+			name:     "third member",
+			index:    2,
+			members:  members,
+			wantName: "etcd-2",
+			want: []string{
+				"--name=node2",
+				"--initial-advertise-peer-urls=http://kwok-etcd-2:2380",
+				"--listen-peer-urls=http://0.0.0.0:2380",
+				initialCluster,
+			},
+		},
+		{
+			name:    "index out of members",
+			index:   3,
+			members: members,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component, err := BuildEtcdComponent(BuildEtcdComponentConfig{
+				Runtime:     "docker",
+				ProjectName: "kwok",
+				Version:     version.NewVersion(3, 5, 0),
+				BindAddress: "0.0.0.0",
+				Index:       tt.index,
+				Members:     tt.members,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BuildEtcdComponent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if component.Name != tt.wantName {
+				t.Errorf("want name %q, got %q", tt.wantName, component.Name)
+			}
+			if component.Metric.Host != "kwok-"+tt.wantName+":2379" {
+				t.Errorf("want metric host of %q, got %q", tt.wantName, component.Metric.Host)
+			}
+			for _, want := range tt.want {
+				if !slices.Contains(component.Args, want) {
+					t.Errorf("want arg %q in %q", want, component.Args)
+				}
+			}
+		})
+	}
+}
@@ -17,43 +17,63 @@ limitations under the License.
 package components
 
 import (
+	"crypto/tls"
 	"fmt"
 	"strings"
+	"time"
 
 	"sigs.k8s.io/kwok/pkg/apis/internalversion"
 	"sigs.k8s.io/kwok/pkg/consts"
 	"sigs.k8s.io/kwok/pkg/log"
 	"sigs.k8s.io/kwok/pkg/utils/format"
 	"sigs.k8s.io/kwok/pkg/utils/net"
+	"sigs.k8s.io/kwok/pkg/utils/slices"
 	"sigs.k8s.io/kwok/pkg/utils/version"
 )
 
 // BuildKubeApiserverComponentConfig is the configuration for building a kube-apiserver component.
 type BuildKubeApiserverComponentConfig struct {
-	Runtime           string
-	ProjectName       string
-	Binary            string
-	Image             string
-	Version           version.Version
-	Workdir           string
-	BindAddress       string
-	Port              uint32
-	EtcdAddress       string
-	EtcdPort          uint32
-	KubeRuntimeConfig string
-	KubeFeatureGates  string
-	SecurePort        bool
-	KubeAuthorization bool
-	KubeAdmission     bool
-	AuditPolicyPath   string
-	AuditLogPath      string
-	CaCertPath        string
-	AdminCertPath     string
-	AdminKeyPath      string
-	Verbosity         log.Level
-	DisableQPSLimits  bool
-	TracingConfigPath string
-	EtcdPrefix        string
+	Runtime               string
+	ProjectName           string
+	Binary                string
+	Image                 string
+	Version               version.Version
+	Workdir               string
+	BindAddress           string
+	Port                  uint32
+	ReadOnlyPort          uint32
+	EtcdAddress           string
+	EtcdPort              uint32
+	EtcdMembers           []EtcdMember
+	KubeRuntimeConfig     string
+	KubeFeatureGates      string
+	ServiceClusterIPRange string
+	SecurePort            bool
+	KubeAuthorization     bool
+	KubeAdmission         bool
+	AdmissionPlugins      string
+	MutatingWebhook       bool
+	AuditPolicyPath       string
+	AuditLogPath          string
+	AuditWebhookPath      string
+	AuditLogMaxSize       int32
+	AuditLogMaxAge        int32
+	AuditLogMaxBackup     int32
+	CaCertPath            string
+	AdminCertPath         string
+	AdminKeyPath          string
+	Verbosity             log.Level
+	DisableQPSLimits      bool
+	TracingConfigPath     string
+	EtcdPrefix            string
+	TLSMinVersion         string
+	TLSCipherSuites       string
+	RequestTimeout        string
+	MinRequestTimeout     string
+
+	PriorityAndFairness         bool
+	MaxRequestsInflight         uint32
+	MaxMutatingRequestsInflight uint32
 }
 
 // BuildKubeApiserverComponent builds a kube-apiserver component.
@@ -71,10 +91,23 @@ func BuildKubeApiserverComponent(conf BuildKubeApiserverComponentConfig) (compon
 		if conf.Version.LT(version.NewVersion(1, 21, 0)) && !conf.KubeAuthorization {
 			return component, fmt.Errorf("the kube-apiserver version is less than 1.21.0, and the --kube-authorization is not enabled, so the --kube-admission cannot be enabled")
 		}
+		if conf.AdmissionPlugins != "" {
+			kubeApiserverArgs = append(kubeApiserverArgs,
+				"--enable-admission-plugins="+conf.AdmissionPlugins,
+			)
+		}
 	} else {
+		admissionPlugins := conf.AdmissionPlugins
+		if conf.MutatingWebhook && !slices.Contains(strings.Split(admissionPlugins, ","), "MutatingAdmissionWebhook") {
+			if admissionPlugins != "" {
+				admissionPlugins += ","
+			}
+			admissionPlugins += "MutatingAdmissionWebhook"
+		}
 		// TODO: use enable-admission-plugins and disable-admission-plugins instead of admission-control
+		// The admission-control replaces the default set of plugins, so only the given plugins are enabled.
 		kubeApiserverArgs = append(kubeApiserverArgs,
-			"--admission-control=",
+			"--admission-control="+admissionPlugins,
 		)
 	}
 
@@ -84,11 +117,28 @@ func BuildKubeApiserverComponent(conf BuildKubeApiserverComponentConfig) (compon
 		)
 	}
 
+	if conf.ServiceClusterIPRange != "" {
+		cidrs, err := net.ParseDualStackCIDRs(conf.ServiceClusterIPRange)
+		if err != nil {
+			return component, fmt.Errorf("invalid service cluster ip range %q: %w", conf.ServiceClusterIPRange, err)
+		}
+		kubeApiserverArgs = append(kubeApiserverArgs,
+			"--service-cluster-ip-range="+net.JoinCIDRs(cidrs),
+		)
+	}
+
 	var featureGates []string
 	if conf.KubeFeatureGates != "" {
 		featureGates = append(featureGates, strings.Split(conf.KubeFeatureGates, ",")...)
 	}
 
+	apfFeatureGates, apfArgs, err := KubeApiserverPriorityAndFairnessArgs(conf.Version, conf.KubeFeatureGates,
+		conf.PriorityAndFairness, conf.MaxRequestsInflight, conf.MaxMutatingRequestsInflight)
+	if err != nil {
+		return component, err
+	}
+	featureGates = append(featureGates, apfFeatureGates...)
+
 	if conf.TracingConfigPath != "" {
 		if conf.Version.LT(version.NewVersion(1, 22, 0)) {
 			return component, fmt.Errorf("the kube-apiserver version is less than 1.22.0, so the --jaeger-port cannot be enabled")
@@ -103,7 +153,8 @@ func BuildKubeApiserverComponent(conf BuildKubeApiserverComponentConfig) (compon
 		)
 	}
 
-	if conf.DisableQPSLimits {
+	// API Priority and Fairness takes over the limits of kube-apiserver
+	if conf.DisableQPSLimits && !conf.PriorityAndFairness {
 		kubeApiserverArgs = append(kubeApiserverArgs,
 			"--max-requests-inflight=0",
 			"--max-mutating-requests-inflight=0",
@@ -117,11 +168,23 @@ func BuildKubeApiserverComponent(conf BuildKubeApiserverComponentConfig) (compon
 		}
 	}
 
+	for _, arg := range apfArgs {
+		kubeApiserverArgs = append(kubeApiserverArgs, "--"+arg.Key+"="+arg.Value)
+	}
+
 	var ports []internalversion.Port
 	var volumes []internalversion.Volume
 	var metric *internalversion.ComponentMetric
 
-	if GetRuntimeMode(conf.Runtime) != RuntimeModeNative {
+	if len(conf.EtcdMembers) > 1 {
+		etcdServers := make([]string, 0, len(conf.EtcdMembers))
+		for _, member := range conf.EtcdMembers {
+			etcdServers = append(etcdServers, "http://"+member.Address+":"+format.String(member.Port))
+		}
+		kubeApiserverArgs = append(kubeApiserverArgs,
+			"--etcd-servers="+strings.Join(etcdServers, ","),
+		)
+	} else if GetRuntimeMode(conf.Runtime) != RuntimeModeNative {
 		kubeApiserverArgs = append(kubeApiserverArgs,
 			"--etcd-servers=http://"+conf.EtcdAddress+":2379",
 		)
@@ -131,7 +194,27 @@ func BuildKubeApiserverComponent(conf BuildKubeApiserverComponentConfig) (compon
 		)
 	}
 
+	timeoutArgs, err := KubeApiserverRequestTimeoutArgs(conf.RequestTimeout, conf.MinRequestTimeout)
+	if err != nil {
+		return component, err
+	}
+	for _, arg := range timeoutArgs {
+		kubeApiserverArgs = append(kubeApiserverArgs, "--"+arg.Key+"="+arg.Value)
+	}
+
+	if !conf.SecurePort && (conf.TLSMinVersion != "" || conf.TLSCipherSuites != "") {
+		return component, fmt.Errorf("the TLS min version and cipher suites of kube-apiserver are only valid with the secure port")
+	}
+
 	if conf.SecurePort {
+		tlsArgs, err := KubeApiserverTLSArgs(conf.TLSMinVersion, conf.TLSCipherSuites)
+		if err != nil {
+			return component, err
+		}
+		for _, arg := range tlsArgs {
+			kubeApiserverArgs = append(kubeApiserverArgs, "--"+arg.Key+"="+arg.Value)
+		}
+
 		if conf.KubeAuthorization {
 			kubeApiserverArgs = append(kubeApiserverArgs,
 				"--authorization-mode=Node,RBAC",
@@ -259,6 +342,15 @@ func BuildKubeApiserverComponent(conf BuildKubeApiserverComponentConfig) (compon
 		}
 	}
 
+	if conf.ReadOnlyPort != 0 {
+		readOnlyArgs, readOnlyPorts, err := kubeApiserverReadOnlyPort(conf)
+		if err != nil {
+			return component, err
+		}
+		kubeApiserverArgs = append(kubeApiserverArgs, readOnlyArgs...)
+		ports = append(ports, readOnlyPorts...)
+	}
+
 	if conf.AuditPolicyPath != "" {
 		if GetRuntimeMode(conf.Runtime) != RuntimeModeNative {
 			volumes = append(volumes,
@@ -267,6 +359,12 @@ func BuildKubeApiserverComponent(conf BuildKubeApiserverComponentConfig) (compon
 					MountPath: "/etc/kubernetes/audit-policy.yaml",
 					ReadOnly:  true,
 				},
+			)
+			if conf.AuditLogMaxSize > 0 {
+				// The rotation renames the audit log, which is not allowed on a mounted file.
+				return component, fmt.Errorf("the rotation of the audit log is not supported in %s runtime", conf.Runtime)
+			}
+			volumes = append(volumes,
 				internalversion.Volume{
 					HostPath:  conf.AuditLogPath,
 					MountPath: "/var/log/kubernetes/audit/audit.log",
@@ -283,6 +381,43 @@ func BuildKubeApiserverComponent(conf BuildKubeApiserverComponentConfig) (compon
 				"--audit-log-path="+conf.AuditLogPath,
 			)
 		}
+
+		if conf.AuditLogMaxSize > 0 {
+			kubeApiserverArgs = append(kubeApiserverArgs,
+				"--audit-log-maxsize="+format.String(conf.AuditLogMaxSize),
+			)
+			if conf.AuditLogMaxAge > 0 {
+				kubeApiserverArgs = append(kubeApiserverArgs,
+					"--audit-log-maxage="+format.String(conf.AuditLogMaxAge),
+				)
+			}
+			if conf.AuditLogMaxBackup > 0 {
+				kubeApiserverArgs = append(kubeApiserverArgs,
+					"--audit-log-maxbackup="+format.String(conf.AuditLogMaxBackup),
+				)
+			}
+		}
+
+		if conf.AuditWebhookPath != "" {
+			if GetRuntimeMode(conf.Runtime) != RuntimeModeNative {
+				volumes = append(volumes,
+					internalversion.Volume{
+						HostPath:  conf.AuditWebhookPath,
+						MountPath: "/etc/kubernetes/audit-webhook.yaml",
+						ReadOnly:  true,
+					},
+				)
+				kubeApiserverArgs = append(kubeApiserverArgs,
+					"--audit-webhook-config-file=/etc/kubernetes/audit-webhook.yaml",
+				)
+			} else {
+				kubeApiserverArgs = append(kubeApiserverArgs,
+					"--audit-webhook-config-file="+conf.AuditWebhookPath,
+				)
+			}
+		}
+	} else if conf.AuditWebhookPath != "" {
+		return component, fmt.Errorf("the audit webhook requires an audit policy")
 	}
 
 	if conf.TracingConfigPath != "" {
@@ -311,6 +446,11 @@ func BuildKubeApiserverComponent(conf BuildKubeApiserverComponentConfig) (compon
 	envs := []internalversion.Env{}
 
 	links := []string{consts.ComponentEtcd}
+	for _, member := range conf.EtcdMembers {
+		if !slices.Contains(links, member.ComponentName) {
+			links = append(links, member.ComponentName)
+		}
+	}
 	if conf.TracingConfigPath != "" {
 		links = append(links, consts.ComponentJaeger)
 	}
@@ -330,3 +470,169 @@ func BuildKubeApiserverComponent(conf BuildKubeApiserverComponentConfig) (compon
 		Envs:    envs,
 	}, nil
 }
+
+// kubeApiserverTLSVersions is the TLS versions supported by the --tls-min-version of kube-apiserver
+var kubeApiserverTLSVersions = []string{
+	"VersionTLS10",
+	"VersionTLS11",
+	"VersionTLS12",
+	"VersionTLS13",
+}
+
+// KubeApiserverTLSArgs validates the TLS min version and the comma-separated cipher suites,
+// and returns the args of kube-apiserver for them.
+func KubeApiserverTLSArgs(minVersion, cipherSuites string) ([]internalversion.ExtraArgs, error) {
+	var args []internalversion.ExtraArgs
+	if minVersion != "" {
+		if !slices.Contains(kubeApiserverTLSVersions, minVersion) {
+			return nil, fmt.Errorf("invalid TLS min version %q of kube-apiserver, must be one of %s",
+				minVersion, strings.Join(kubeApiserverTLSVersions, ", "))
+		}
+		args = append(args, internalversion.ExtraArgs{
+			Key:   "tls-min-version",
+			Value: minVersion,
+		})
+	}
+
+	if cipherSuites != "" {
+		known := map[string]struct{}{}
+		for _, suite := range tls.CipherSuites() {
+			known[suite.Name] = struct{}{}
+		}
+		for _, suite := range tls.InsecureCipherSuites() {
+			known[suite.Name] = struct{}{}
+		}
+		for _, name := range strings.Split(cipherSuites, ",") {
+			if _, ok := known[name]; !ok {
+				return nil, fmt.Errorf("unknown TLS cipher suite %q of kube-apiserver", name)
+			}
+		}
+		args = append(args, internalversion.ExtraArgs{
+			Key:   "tls-cipher-suites",
+			Value: cipherSuites,
+		})
+	}
+	return args, nil
+}
+
+// KubeApiserverRequestTimeoutArgs validates the request timeout and the min request timeout,
+// and returns the args of kube-apiserver for them.
+// The --min-request-timeout of kube-apiserver is in seconds, so the duration is rounded down to seconds.
+func KubeApiserverRequestTimeoutArgs(requestTimeout, minRequestTimeout string) ([]internalversion.ExtraArgs, error) {
+	var args []internalversion.ExtraArgs
+	if requestTimeout != "" {
+		d, err := time.ParseDuration(requestTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid request timeout %q of kube-apiserver: %w", requestTimeout, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("invalid request timeout %q of kube-apiserver: must be positive", requestTimeout)
+		}
+		args = append(args, internalversion.ExtraArgs{
+			Key:   "request-timeout",
+			Value: d.String(),
+		})
+	}
+
+	if minRequestTimeout != "" {
+		d, err := time.ParseDuration(minRequestTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min request timeout %q of kube-apiserver: %w", minRequestTimeout, err)
+		}
+		if d < time.Second {
+			return nil, fmt.Errorf("invalid min request timeout %q of kube-apiserver: must be at least 1s", minRequestTimeout)
+		}
+		args = append(args, internalversion.ExtraArgs{
+			Key:   "min-request-timeout",
+			Value: format.String(int64(d / time.Second)),
+		})
+	}
+	return args, nil
+}
+
+// kubeApiserverReadOnlyPort returns the args and ports to serve the legacy insecure port
+// next to the secure port, which is removed since Kubernetes 1.20.0.
+func kubeApiserverReadOnlyPort(conf BuildKubeApiserverComponentConfig) ([]string, []internalversion.Port, error) {
+	if !conf.SecurePort {
+		return nil, nil, fmt.Errorf("the read-only port of kube-apiserver is only valid with the secure port, the insecure port is already served without it")
+	}
+	if conf.Version.GE(version.NewVersion(1, 20, 0)) {
+		return nil, nil, fmt.Errorf("the kube-apiserver version is %s, the insecure port has been removed since 1.20.0, so the read-only port cannot be enabled", conf.Version)
+	}
+
+	if GetRuntimeMode(conf.Runtime) != RuntimeModeNative {
+		args := []string{
+			"--insecure-bind-address=" + conf.BindAddress,
+			"--insecure-port=8080",
+		}
+		ports := []internalversion.Port{
+			{
+				Name:     "readonly",
+				HostPort: conf.ReadOnlyPort,
+				Port:     8080,
+				Protocol: internalversion.ProtocolTCP,
+			},
+		}
+		return args, ports, nil
+	}
+
+	args := []string{
+		"--insecure-bind-address=" + conf.BindAddress,
+		"--insecure-port=" + format.String(conf.ReadOnlyPort),
+	}
+	ports := []internalversion.Port{
+		{
+			Name:     "readonly",
+			HostPort: 0,
+			Port:     conf.ReadOnlyPort,
+			Protocol: internalversion.ProtocolTCP,
+		},
+	}
+	return args, ports, nil
+}
+
+// KubeApiserverPriorityAndFairnessArgs validates the options of API Priority and Fairness,
+// and returns the feature gates and the args of kube-apiserver for them.
+func KubeApiserverPriorityAndFairnessArgs(ver version.Version, featureGates string, enable bool, maxRequestsInflight, maxMutatingRequestsInflight uint32) ([]string, []internalversion.ExtraArgs, error) {
+	if !enable {
+		if maxRequestsInflight != 0 || maxMutatingRequestsInflight != 0 {
+			return nil, nil, fmt.Errorf("the max requests inflight of kube-apiserver are only valid with API Priority and Fairness")
+		}
+		return nil, nil, nil
+	}
+
+	// The APIs of flowcontrol are alpha and disabled by default before 1.20.0
+	if ver.LT(version.NewVersion(1, 20, 0)) {
+		return nil, nil, fmt.Errorf("the kube-apiserver version is less than 1.20.0, so API Priority and Fairness cannot be enabled")
+	}
+
+	if featureGates != "" && slices.Contains(strings.Split(featureGates, ","), "APIPriorityAndFairness=false") {
+		return nil, nil, fmt.Errorf("API Priority and Fairness cannot be enabled with the feature gate APIPriorityAndFairness=false")
+	}
+
+	var gates []string
+	// FeatureGate APIPriorityAndFairness is locked to enabled since 1.29.0
+	if ver.LT(version.NewVersion(1, 29, 0)) {
+		gates = append(gates, "APIPriorityAndFairness=true")
+	}
+
+	args := []internalversion.ExtraArgs{
+		{
+			Key:   "enable-priority-and-fairness",
+			Value: "true",
+		},
+	}
+	if maxRequestsInflight != 0 {
+		args = append(args, internalversion.ExtraArgs{
+			Key:   "max-requests-inflight",
+			Value: format.String(maxRequestsInflight),
+		})
+	}
+	if maxMutatingRequestsInflight != 0 {
+		args = append(args, internalversion.ExtraArgs{
+			Key:   "max-mutating-requests-inflight",
+			Value: format.String(maxMutatingRequestsInflight),
+		})
+	}
+	return gates, args, nil
+}
@@ -0,0 +1,527 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/utils/slices"
+	"sigs.k8s.io/kwok/pkg/utils/version"
+)
+
+func TestBuildKubeApiserverComponent(t *testing.T) {
+	tests := []struct {
+		name        string
+		conf        BuildKubeApiserverComponentConfig
+		want        []string
+		notWant     []string
+		wantLinks   []string
+		wantPorts   []internalversion.Port
+		wantVolumes []internalversion.Volume
+		wantErr     bool
+	}{
+		{
+			name: "admission disabled",
+			conf: BuildKubeApiserverComponentConfig{
+				KubeAuthorization: true,
+			},
+			want:    []string{"--admission-control="},
+			notWant: []string{"--enable-admission-plugins"},
+		},
+		{
+			name: "admission disabled with plugins",
+			conf: BuildKubeApiserverComponentConfig{
+				KubeAuthorization: true,
+				AdmissionPlugins:  "ResourceQuota,LimitRanger",
+			},
+			want:    []string{"--admission-control=ResourceQuota,LimitRanger"},
+			notWant: []string{"--enable-admission-plugins"},
+		},
+		{
+			name: "admission disabled with mutating webhook",
+			conf: BuildKubeApiserverComponentConfig{
+				KubeAuthorization: true,
+				MutatingWebhook:   true,
+			},
+			want: []string{"--admission-control=MutatingAdmissionWebhook"},
+		},
+		{
+			name: "admission disabled with plugins and mutating webhook",
+			conf: BuildKubeApiserverComponentConfig{
+				KubeAuthorization: true,
+				AdmissionPlugins:  "ResourceQuota,MutatingAdmissionWebhook",
+				MutatingWebhook:   true,
+			},
+			want: []string{"--admission-control=ResourceQuota,MutatingAdmissionWebhook"},
+		},
+		{
+			name: "admission enabled",
+			conf: BuildKubeApiserverComponentConfig{
+				KubeAuthorization: true,
+				KubeAdmission:     true,
+			},
+			notWant: []string{"--admission-control", "--enable-admission-plugins"},
+		},
+		{
+			name: "admission enabled with mutating webhook",
+			conf: BuildKubeApiserverComponentConfig{
+				KubeAuthorization: true,
+				KubeAdmission:     true,
+				MutatingWebhook:   true,
+			},
+			notWant: []string{"--admission-control", "--enable-admission-plugins"},
+		},
+		{
+			name: "admission enabled with plugins",
+			conf: BuildKubeApiserverComponentConfig{
+				KubeAuthorization: true,
+				KubeAdmission:     true,
+				AdmissionPlugins:  "ResourceQuota,LimitRanger",
+			},
+			want:    []string{"--enable-admission-plugins=ResourceQuota,LimitRanger"},
+			notWant: []string{"--admission-control"},
+		},
+		{
+			name:    "service cluster ip range unset",
+			notWant: []string{"--service-cluster-ip-range"},
+		},
+		{
+			name: "service cluster ip range single-stack",
+			conf: BuildKubeApiserverComponentConfig{
+				ServiceClusterIPRange: "10.96.0.0/12",
+			},
+			want: []string{"--service-cluster-ip-range=10.96.0.0/12"},
+		},
+		{
+			name: "service cluster ip range dual-stack",
+			conf: BuildKubeApiserverComponentConfig{
+				ServiceClusterIPRange: "10.96.0.0/12,fd00:10:96::/112",
+			},
+			want: []string{"--service-cluster-ip-range=10.96.0.0/12,fd00:10:96::/112"},
+		},
+		{
+			name: "service cluster ip range invalid",
+			conf: BuildKubeApiserverComponentConfig{
+				ServiceClusterIPRange: "10.96.0.0",
+			},
+			wantErr: true,
+		},
+		{
+			name: "tls not set",
+			conf: BuildKubeApiserverComponentConfig{
+				SecurePort: true,
+			},
+			notWant: []string{"--tls-min-version", "--tls-cipher-suites"},
+		},
+		{
+			name: "tls min version and cipher suites",
+			conf: BuildKubeApiserverComponentConfig{
+				SecurePort:      true,
+				TLSMinVersion:   "VersionTLS12",
+				TLSCipherSuites: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+			},
+			want: []string{
+				"--tls-min-version=VersionTLS12",
+				"--tls-cipher-suites=TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+			},
+		},
+		{
+			name: "tls invalid min version",
+			conf: BuildKubeApiserverComponentConfig{
+				SecurePort:    true,
+				TLSMinVersion: "TLS1.2",
+			},
+			wantErr: true,
+		},
+		{
+			name: "tls unknown cipher suite",
+			conf: BuildKubeApiserverComponentConfig{
+				SecurePort:      true,
+				TLSCipherSuites: "TLS_FAKE_CIPHER",
+			},
+			wantErr: true,
+		},
+		{
+			name: "tls without secure port",
+			conf: BuildKubeApiserverComponentConfig{
+				TLSMinVersion: "VersionTLS12",
+			},
+			wantErr: true,
+		},
+		{
+			name:    "request timeout not set",
+			notWant: []string{"--request-timeout", "--min-request-timeout"},
+		},
+		{
+			name: "request timeout and min request timeout",
+			conf: BuildKubeApiserverComponentConfig{
+				RequestTimeout:    "5m",
+				MinRequestTimeout: "30m",
+			},
+			want: []string{
+				"--request-timeout=5m0s",
+				"--min-request-timeout=1800",
+			},
+		},
+		{
+			name: "min request timeout rounded down to seconds",
+			conf: BuildKubeApiserverComponentConfig{
+				MinRequestTimeout: "90.5s",
+			},
+			want:    []string{"--min-request-timeout=90"},
+			notWant: []string{"--request-timeout"},
+		},
+		{
+			name: "invalid request timeout",
+			conf: BuildKubeApiserverComponentConfig{
+				RequestTimeout: "5",
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative request timeout",
+			conf: BuildKubeApiserverComponentConfig{
+				RequestTimeout: "-1m",
+			},
+			wantErr: true,
+		},
+		{
+			name: "min request timeout less than a second",
+			conf: BuildKubeApiserverComponentConfig{
+				MinRequestTimeout: "500ms",
+			},
+			wantErr: true,
+		},
+		{
+			name: "disable qps limits",
+			conf: BuildKubeApiserverComponentConfig{
+				DisableQPSLimits: true,
+			},
+			want: []string{
+				"--max-requests-inflight=0",
+				"--max-mutating-requests-inflight=0",
+				"--enable-priority-and-fairness=false",
+			},
+		},
+		{
+			name: "priority and fairness takes precedence over disable qps limits",
+			conf: BuildKubeApiserverComponentConfig{
+				DisableQPSLimits:    true,
+				PriorityAndFairness: true,
+			},
+			want: []string{"--enable-priority-and-fairness=true"},
+			notWant: []string{
+				"--max-requests-inflight",
+				"--max-mutating-requests-inflight",
+				"--enable-priority-and-fairness=false",
+				"--feature-gates",
+			},
+		},
+		{
+			name: "priority and fairness with max requests inflight",
+			conf: BuildKubeApiserverComponentConfig{
+				PriorityAndFairness:         true,
+				MaxRequestsInflight:         400,
+				MaxMutatingRequestsInflight: 200,
+			},
+			want: []string{
+				"--enable-priority-and-fairness=true",
+				"--max-requests-inflight=400",
+				"--max-mutating-requests-inflight=200",
+			},
+		},
+		{
+			name: "priority and fairness enables the beta feature gate",
+			conf: BuildKubeApiserverComponentConfig{
+				Version:             version.NewVersion(1, 26, 0),
+				KubeFeatureGates:    "SidecarContainers=true",
+				PriorityAndFairness: true,
+			},
+			want: []string{
+				"--feature-gates=SidecarContainers=true,APIPriorityAndFairness=true",
+				"--enable-priority-and-fairness=true",
+			},
+		},
+		{
+			name: "priority and fairness with the feature gate disabled",
+			conf: BuildKubeApiserverComponentConfig{
+				Version:             version.NewVersion(1, 26, 0),
+				KubeFeatureGates:    "APIPriorityAndFairness=false",
+				PriorityAndFairness: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "priority and fairness before 1.20",
+			conf: BuildKubeApiserverComponentConfig{
+				Version:             version.NewVersion(1, 19, 0),
+				PriorityAndFairness: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "max requests inflight without priority and fairness",
+			conf: BuildKubeApiserverComponentConfig{
+				MaxRequestsInflight: 400,
+			},
+			wantErr: true,
+		},
+		{
+			name: "single etcd member",
+			conf: BuildKubeApiserverComponentConfig{
+				EtcdAddress: "127.0.0.1",
+				EtcdPort:    32379,
+			},
+			want:      []string{"--etcd-servers=http://127.0.0.1:32379"},
+			wantLinks: []string{"etcd"},
+		},
+		{
+			name: "binary etcd members",
+			conf: BuildKubeApiserverComponentConfig{
+				EtcdAddress: "127.0.0.1",
+				EtcdPort:    32379,
+				EtcdMembers: []EtcdMember{
+					{ComponentName: EtcdComponentName(0), Address: "127.0.0.1", Port: 32379},
+					{ComponentName: EtcdComponentName(1), Address: "127.0.0.1", Port: 32381},
+					{ComponentName: EtcdComponentName(2), Address: "127.0.0.1", Port: 32383},
+				},
+			},
+			want:      []string{"--etcd-servers=http://127.0.0.1:32379,http://127.0.0.1:32381,http://127.0.0.1:32383"},
+			wantLinks: []string{"etcd", "etcd-1", "etcd-2"},
+		},
+		{
+			name: "compose etcd members",
+			conf: BuildKubeApiserverComponentConfig{
+				Runtime:     "docker",
+				EtcdAddress: "127.0.0.1",
+				EtcdPort:    32379,
+				EtcdMembers: []EtcdMember{
+					{ComponentName: EtcdComponentName(0), Address: "kwok-etcd", Port: 2379},
+					{ComponentName: EtcdComponentName(1), Address: "kwok-etcd-1", Port: 2379},
+					{ComponentName: EtcdComponentName(2), Address: "kwok-etcd-2", Port: 2379},
+				},
+			},
+			want:      []string{"--etcd-servers=http://kwok-etcd:2379,http://kwok-etcd-1:2379,http://kwok-etcd-2:2379"},
+			wantLinks: []string{"etcd", "etcd-1", "etcd-2"},
+		},
+		{
+			name: "binary readonly port",
+			conf: BuildKubeApiserverComponentConfig{
+				Version:      version.NewVersion(1, 19, 0),
+				ReadOnlyPort: 8081,
+				SecurePort:   true,
+			},
+			want: []string{"--insecure-bind-address=127.0.0.1", "--insecure-port=8081"},
+			wantPorts: []internalversion.Port{
+				{
+					Name:     "readonly",
+					Port:     8081,
+					Protocol: internalversion.ProtocolTCP,
+				},
+			},
+		},
+		{
+			name: "docker readonly port",
+			conf: BuildKubeApiserverComponentConfig{
+				Runtime:      "docker",
+				Version:      version.NewVersion(1, 19, 0),
+				ReadOnlyPort: 8081,
+				SecurePort:   true,
+			},
+			want: []string{"--insecure-bind-address=127.0.0.1", "--insecure-port=8080"},
+			wantPorts: []internalversion.Port{
+				{
+					Name:     "readonly",
+					HostPort: 8081,
+					Port:     8080,
+					Protocol: internalversion.ProtocolTCP,
+				},
+			},
+		},
+		{
+			name: "readonly port without secure port",
+			conf: BuildKubeApiserverComponentConfig{
+				Version:      version.NewVersion(1, 19, 0),
+				ReadOnlyPort: 8081,
+			},
+			wantErr: true,
+		},
+		{
+			name: "readonly port on removed version",
+			conf: BuildKubeApiserverComponentConfig{
+				Version:      version.NewVersion(1, 20, 0),
+				ReadOnlyPort: 8081,
+				SecurePort:   true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "binary audit webhook",
+			conf: BuildKubeApiserverComponentConfig{
+				AuditPolicyPath:  "/workdir/audit.yaml",
+				AuditLogPath:     "/workdir/logs/audit.log",
+				AuditWebhookPath: "/workdir/audit-webhook.yaml",
+			},
+			want: []string{
+				"--audit-policy-file=/workdir/audit.yaml",
+				"--audit-webhook-config-file=/workdir/audit-webhook.yaml",
+			},
+		},
+		{
+			name: "docker audit webhook",
+			conf: BuildKubeApiserverComponentConfig{
+				Runtime:          "docker",
+				AuditPolicyPath:  "/workdir/audit.yaml",
+				AuditLogPath:     "/workdir/logs/audit.log",
+				AuditWebhookPath: "/workdir/audit-webhook.yaml",
+			},
+			want: []string{
+				"--audit-policy-file=/etc/kubernetes/audit-policy.yaml",
+				"--audit-webhook-config-file=/etc/kubernetes/audit-webhook.yaml",
+			},
+			wantVolumes: []internalversion.Volume{
+				{
+					HostPath:  "/workdir/audit-webhook.yaml",
+					MountPath: "/etc/kubernetes/audit-webhook.yaml",
+					ReadOnly:  true,
+				},
+			},
+		},
+		{
+			name: "audit webhook without audit policy",
+			conf: BuildKubeApiserverComponentConfig{
+				AuditLogPath:     "/workdir/logs/audit.log",
+				AuditWebhookPath: "/workdir/audit-webhook.yaml",
+			},
+			wantErr: true,
+		},
+		{
+			name: "binary audit log rotation",
+			conf: BuildKubeApiserverComponentConfig{
+				AuditPolicyPath:   "/workdir/audit.yaml",
+				AuditLogPath:      "/workdir/logs/audit.log",
+				AuditLogMaxSize:   100,
+				AuditLogMaxAge:    7,
+				AuditLogMaxBackup: 5,
+			},
+			want: []string{
+				"--audit-log-path=/workdir/logs/audit.log",
+				"--audit-log-maxsize=100",
+				"--audit-log-maxage=7",
+				"--audit-log-maxbackup=5",
+			},
+		},
+		{
+			name: "binary audit log rotation without limits of backups",
+			conf: BuildKubeApiserverComponentConfig{
+				AuditPolicyPath: "/workdir/audit.yaml",
+				AuditLogPath:    "/workdir/logs/audit.log",
+				AuditLogMaxSize: 10,
+			},
+			want:    []string{"--audit-log-maxsize=10"},
+			notWant: []string{"--audit-log-maxage", "--audit-log-maxbackup"},
+		},
+		{
+			name: "binary audit log without rotation",
+			conf: BuildKubeApiserverComponentConfig{
+				AuditPolicyPath:   "/workdir/audit.yaml",
+				AuditLogPath:      "/workdir/logs/audit.log",
+				AuditLogMaxAge:    7,
+				AuditLogMaxBackup: 5,
+			},
+			notWant: []string{"--audit-log-maxsize", "--audit-log-maxage", "--audit-log-maxbackup"},
+		},
+		{
+			name: "docker audit log rotation",
+			conf: BuildKubeApiserverComponentConfig{
+				Runtime:           "docker",
+				AuditPolicyPath:   "/workdir/audit.yaml",
+				AuditLogPath:      "/workdir/logs/audit.log",
+				AuditLogMaxSize:   100,
+				AuditLogMaxAge:    7,
+				AuditLogMaxBackup: 5,
+			},
+			wantErr: true,
+		},
+		{
+			name: "docker audit log without rotation",
+			conf: BuildKubeApiserverComponentConfig{
+				Runtime:         "docker",
+				AuditPolicyPath: "/workdir/audit.yaml",
+				AuditLogPath:    "/workdir/logs/audit.log",
+			},
+			want: []string{"--audit-log-path=/var/log/kubernetes/audit/audit.log"},
+			wantVolumes: []internalversion.Volume{
+				{
+					HostPath:  "/workdir/logs/audit.log",
+					MountPath: "/var/log/kubernetes/audit/audit.log",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := tt.conf
+			if conf.Runtime == "" {
+				conf.Runtime = "binary"
+			}
+			if conf.Version.EQ(version.Version{}) {
+				conf.Version = version.NewVersion(1, 30, 0)
+			}
+			conf.BindAddress = "127.0.0.1"
+			conf.Port = 6443
+
+			component, err := BuildKubeApiserverComponent(conf)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("want error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			args := strings.Join(component.Args, " ")
+			for _, want := range tt.want {
+				if !slices.Contains(component.Args, want) {
+					t.Errorf("want arg %q in %q", want, args)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(args, notWant) {
+					t.Errorf("want no arg %q in %q", notWant, args)
+				}
+			}
+			if tt.wantLinks != nil && !reflect.DeepEqual(component.Links, tt.wantLinks) {
+				t.Errorf("want links %q, got %q", tt.wantLinks, component.Links)
+			}
+			for _, port := range tt.wantPorts {
+				if !slices.Contains(component.Ports, port) {
+					t.Errorf("want port %v in %v", port, component.Ports)
+				}
+			}
+			for _, volume := range tt.wantVolumes {
+				if !slices.Contains(component.Volumes, volume) {
+					t.Errorf("want volume %v in %v", volume, component.Volumes)
+				}
+			}
+		})
+	}
+}
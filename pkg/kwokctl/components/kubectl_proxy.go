@@ -17,6 +17,8 @@ limitations under the License.
 package components
 
 import (
+	"fmt"
+
 	"sigs.k8s.io/kwok/pkg/apis/internalversion"
 	"sigs.k8s.io/kwok/pkg/consts"
 	"sigs.k8s.io/kwok/pkg/log"
@@ -37,6 +39,7 @@ type BuildKubectlProxyComponentConfig struct {
 	AdminKeyPath   string
 	ConfigPath     string
 	KubeconfigPath string
+	UnixSocketPath string
 	Verbosity      log.Level
 }
 
@@ -47,13 +50,26 @@ func BuildKubectlProxyComponent(conf BuildKubectlProxyComponentConfig) (componen
 	var volumes []internalversion.Volume
 	var ports []internalversion.Port
 
+	name := consts.ComponentKubeApiserverInsecureProxy
+
 	kubectlProxyArgs = append(kubectlProxyArgs,
 		"proxy",
 		"--accept-hosts=^*$",
-		"--address="+conf.BindAddress,
 	)
 
-	if GetRuntimeMode(conf.Runtime) != RuntimeModeNative {
+	if conf.UnixSocketPath != "" {
+		if GetRuntimeMode(conf.Runtime) != RuntimeModeNative {
+			return component, fmt.Errorf("unix socket proxy is only supported for binary runtime")
+		}
+		name = consts.ComponentKubeApiserverSocketProxy
+		kubectlProxyArgs = append(kubectlProxyArgs,
+			"--kubeconfig="+conf.KubeconfigPath,
+			"--unix-socket="+conf.UnixSocketPath,
+		)
+	} else if GetRuntimeMode(conf.Runtime) != RuntimeModeNative {
+		kubectlProxyArgs = append(kubectlProxyArgs,
+			"--address="+conf.BindAddress,
+		)
 		volumes = append(volumes,
 			internalversion.Volume{
 				HostPath:  conf.KubeconfigPath,
@@ -91,6 +107,7 @@ func BuildKubectlProxyComponent(conf BuildKubectlProxyComponentConfig) (componen
 		)
 	} else {
 		kubectlProxyArgs = append(kubectlProxyArgs,
+			"--address="+conf.BindAddress,
 			"--kubeconfig="+conf.KubeconfigPath,
 			"--port="+format.String(conf.Port),
 		)
@@ -112,7 +129,7 @@ func BuildKubectlProxyComponent(conf BuildKubectlProxyComponentConfig) (componen
 	envs := []internalversion.Env{}
 
 	return internalversion.Component{
-		Name: consts.ComponentKubeApiserverInsecureProxy,
+		Name: name,
 		Links: []string{
 			consts.ComponentKubeApiserver,
 		},
@@ -17,6 +17,7 @@ limitations under the License.
 package components
 
 import (
+	"strconv"
 	"strings"
 
 	"sigs.k8s.io/kwok/pkg/apis/internalversion"
@@ -37,6 +38,7 @@ type BuildKwokControllerComponentConfig struct {
 	Workdir                           string
 	BindAddress                       string
 	Port                              uint32
+	ProfilingPort                     uint32
 	ConfigPath                        string
 	KubeconfigPath                    string
 	CaCertPath                        string
@@ -47,7 +49,14 @@ type BuildKwokControllerComponentConfig struct {
 	ManageNodesWithAnnotationSelector string
 	Verbosity                         log.Level
 	NodeLeaseDurationSeconds          uint
+	ManageNodesParallelism            uint
+	NodeCPUOvercommit                 float64
+	NodeMemoryOvercommit              float64
 	EnableCRDs                        []string
+	PodDNSPolicy                      string
+	PodDNSNameservers                 []string
+	PodDNSSearches                    []string
+	PodDNSOptions                     []string
 }
 
 // BuildKwokControllerComponent builds a kwok controller component.
@@ -64,6 +73,23 @@ func BuildKwokControllerComponent(conf BuildKwokControllerComponentConfig) (comp
 		)
 	}
 
+	if conf.ManageNodesParallelism != 0 {
+		kwokControllerArgs = append(kwokControllerArgs,
+			"--manage-nodes-parallelism="+format.String(conf.ManageNodesParallelism),
+		)
+	}
+
+	if conf.NodeCPUOvercommit != 0 {
+		kwokControllerArgs = append(kwokControllerArgs,
+			"--node-cpu-overcommit="+strconv.FormatFloat(conf.NodeCPUOvercommit, 'f', -1, 64),
+		)
+	}
+	if conf.NodeMemoryOvercommit != 0 {
+		kwokControllerArgs = append(kwokControllerArgs,
+			"--node-memory-overcommit="+strconv.FormatFloat(conf.NodeMemoryOvercommit, 'f', -1, 64),
+		)
+	}
+
 	var volumes []internalversion.Volume
 	var ports []internalversion.Port
 
@@ -116,6 +142,21 @@ func BuildKwokControllerComponent(conf BuildKwokControllerComponentConfig) (comp
 			"--server-address="+conf.BindAddress+":10247",
 			"--node-lease-duration-seconds="+format.String(conf.NodeLeaseDurationSeconds),
 		)
+
+		if conf.ProfilingPort != 0 {
+			ports = append(
+				ports,
+				internalversion.Port{
+					Name:     "pprof",
+					HostPort: conf.ProfilingPort,
+					Port:     10246,
+					Protocol: internalversion.ProtocolTCP,
+				},
+			)
+			kwokControllerArgs = append(kwokControllerArgs,
+				"--profiling-address="+conf.BindAddress+":10246",
+			)
+		}
 	} else {
 		ports = append(
 			ports,
@@ -137,6 +178,21 @@ func BuildKwokControllerComponent(conf BuildKwokControllerComponentConfig) (comp
 			"--server-address="+conf.BindAddress+":"+format.String(conf.Port),
 			"--node-lease-duration-seconds="+format.String(conf.NodeLeaseDurationSeconds),
 		)
+
+		if conf.ProfilingPort != 0 {
+			ports = append(
+				ports,
+				internalversion.Port{
+					Name:     "pprof",
+					HostPort: 0,
+					Port:     conf.ProfilingPort,
+					Protocol: internalversion.ProtocolTCP,
+				},
+			)
+			kwokControllerArgs = append(kwokControllerArgs,
+				"--profiling-address="+conf.BindAddress+":"+format.String(conf.ProfilingPort),
+			)
+		}
 	}
 
 	var metricsHost string
@@ -173,6 +229,19 @@ func BuildKwokControllerComponent(conf BuildKwokControllerComponentConfig) (comp
 		kwokControllerArgs = append(kwokControllerArgs, "--enable-crds="+strings.Join(conf.EnableCRDs, ","))
 	}
 
+	if conf.PodDNSPolicy != "" {
+		kwokControllerArgs = append(kwokControllerArgs, "--pod-dns-policy="+conf.PodDNSPolicy)
+	}
+	if len(conf.PodDNSNameservers) != 0 {
+		kwokControllerArgs = append(kwokControllerArgs, "--pod-dns-nameservers="+strings.Join(conf.PodDNSNameservers, ","))
+	}
+	if len(conf.PodDNSSearches) != 0 {
+		kwokControllerArgs = append(kwokControllerArgs, "--pod-dns-searches="+strings.Join(conf.PodDNSSearches, ","))
+	}
+	if len(conf.PodDNSOptions) != 0 {
+		kwokControllerArgs = append(kwokControllerArgs, "--pod-dns-options="+strings.Join(conf.PodDNSOptions, ","))
+	}
+
 	envs := []internalversion.Env{}
 
 	return internalversion.Component{
@@ -0,0 +1,240 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/utils/slices"
+	"sigs.k8s.io/kwok/pkg/utils/version"
+)
+
+func TestBuildKwokControllerComponentProfilingPort(t *testing.T) {
+	tests := []struct {
+		name          string
+		runtime       string
+		profilingPort uint32
+		wantPort      *internalversion.Port
+		wantArg       string
+	}{
+		{
+			name:    "binary without profiling port",
+			runtime: "binary",
+		},
+		{
+			name:    "docker without profiling port",
+			runtime: "docker",
+		},
+		{
+			name:          "binary with profiling port",
+			runtime:       "binary",
+			profilingPort: 6060,
+			wantPort: &internalversion.Port{
+				Name:     "pprof",
+				Port:     6060,
+				Protocol: internalversion.ProtocolTCP,
+			},
+			wantArg: "--profiling-address=127.0.0.1:6060",
+		},
+		{
+			name:          "docker with profiling port",
+			runtime:       "docker",
+			profilingPort: 6060,
+			wantPort: &internalversion.Port{
+				Name:     "pprof",
+				HostPort: 6060,
+				Port:     10246,
+				Protocol: internalversion.ProtocolTCP,
+			},
+			wantArg: "--profiling-address=127.0.0.1:10246",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component := BuildKwokControllerComponent(BuildKwokControllerComponentConfig{
+				Runtime:       tt.runtime,
+				Version:       version.NewVersion(0, 6, 0),
+				BindAddress:   "127.0.0.1",
+				Port:          10247,
+				ProfilingPort: tt.profilingPort,
+			})
+
+			port, ok := slices.Find(component.Ports, func(p internalversion.Port) bool {
+				return p.Name == "pprof"
+			})
+			if tt.wantPort == nil {
+				if ok {
+					t.Errorf("want no pprof port, got %+v", port)
+				}
+				if _, ok := slices.Find(component.Args, func(arg string) bool {
+					return strings.HasPrefix(arg, "--profiling-address")
+				}); ok {
+					t.Errorf("want no --profiling-address in %q", component.Args)
+				}
+				return
+			}
+
+			if !ok {
+				t.Fatalf("want pprof port, got %+v", component.Ports)
+			}
+			if !reflect.DeepEqual(port, *tt.wantPort) {
+				t.Errorf("want pprof port %+v, got %+v", *tt.wantPort, port)
+			}
+			if !slices.Contains(component.Args, tt.wantArg) {
+				t.Errorf("want arg %q in %q", tt.wantArg, component.Args)
+			}
+		})
+	}
+}
+
+func TestBuildKwokControllerComponentOvercommit(t *testing.T) {
+	tests := []struct {
+		name             string
+		cpuOvercommit    float64
+		memoryOvercommit float64
+		wantArgs         []string
+		wantNoArgs       []string
+	}{
+		{
+			name:       "disabled",
+			wantNoArgs: []string{"--node-cpu-overcommit", "--node-memory-overcommit"},
+		},
+		{
+			name:          "cpu only",
+			cpuOvercommit: 2,
+			wantArgs:      []string{"--node-cpu-overcommit=2"},
+			wantNoArgs:    []string{"--node-memory-overcommit"},
+		},
+		{
+			name:             "cpu and memory",
+			cpuOvercommit:    1.5,
+			memoryOvercommit: 0.75,
+			wantArgs:         []string{"--node-cpu-overcommit=1.5", "--node-memory-overcommit=0.75"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component := BuildKwokControllerComponent(BuildKwokControllerComponentConfig{
+				Runtime:              "binary",
+				Version:              version.NewVersion(0, 6, 0),
+				BindAddress:          "127.0.0.1",
+				Port:                 10247,
+				NodeCPUOvercommit:    tt.cpuOvercommit,
+				NodeMemoryOvercommit: tt.memoryOvercommit,
+			})
+			for _, arg := range tt.wantArgs {
+				if !slices.Contains(component.Args, arg) {
+					t.Errorf("want arg %q in %q", arg, component.Args)
+				}
+			}
+			for _, prefix := range tt.wantNoArgs {
+				if _, ok := slices.Find(component.Args, func(arg string) bool {
+					return strings.HasPrefix(arg, prefix)
+				}); ok {
+					t.Errorf("want no %s in %q", prefix, component.Args)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildKwokControllerComponentManageNodesParallelism(t *testing.T) {
+	tests := []struct {
+		name        string
+		parallelism uint
+		want        []string
+	}{
+		{
+			name: "unset",
+			want: []string{},
+		},
+		{
+			name:        "set",
+			parallelism: 16,
+			want:        []string{"--manage-nodes-parallelism=16"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component := BuildKwokControllerComponent(BuildKwokControllerComponentConfig{
+				Runtime:                "binary",
+				Version:                version.NewVersion(0, 6, 0),
+				BindAddress:            "127.0.0.1",
+				Port:                   10247,
+				ManageNodesParallelism: tt.parallelism,
+			})
+			got := slices.Filter(component.Args, func(arg string) bool {
+				return strings.HasPrefix(arg, "--manage-nodes-parallelism")
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBuildKwokControllerComponentPodDNS(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      string
+		nameservers []string
+		searches    []string
+		options     []string
+		want        []string
+	}{
+		{
+			name: "unset",
+			want: []string{},
+		},
+		{
+			name:        "set",
+			policy:      "None",
+			nameservers: []string{"10.96.0.10", "10.96.0.11"},
+			searches:    []string{"svc.cluster.local"},
+			options:     []string{"ndots:5", "edns0"},
+			want: []string{
+				"--pod-dns-policy=None",
+				"--pod-dns-nameservers=10.96.0.10,10.96.0.11",
+				"--pod-dns-searches=svc.cluster.local",
+				"--pod-dns-options=ndots:5,edns0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component := BuildKwokControllerComponent(BuildKwokControllerComponentConfig{
+				Runtime:           "binary",
+				Version:           version.NewVersion(0, 6, 0),
+				BindAddress:       "127.0.0.1",
+				Port:              10247,
+				PodDNSPolicy:      tt.policy,
+				PodDNSNameservers: tt.nameservers,
+				PodDNSSearches:    tt.searches,
+				PodDNSOptions:     tt.options,
+			})
+			got := slices.Filter(component.Args, func(arg string) bool {
+				return strings.HasPrefix(arg, "--pod-dns-")
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"text/template"
+
+	_ "embed"
+)
+
+//go:embed pod_dns_webhook.yaml.tpl
+var podDNSWebhookYamlTpl string
+
+var podDNSWebhookYamlTemplate = template.Must(template.New("pod_dns_webhook").Parse(podDNSWebhookYamlTpl))
+
+// BuildPodDNSWebhook builds the mutating webhook configuration yaml content,
+// which has the kwok-controller set the DNS settings on the created pods.
+func BuildPodDNSWebhook(conf BuildPodDNSWebhookConfig) (string, error) {
+	buf := bytes.NewBuffer(nil)
+	err := podDNSWebhookYamlTemplate.Execute(buf, struct {
+		URL      string
+		CABundle string
+	}{
+		URL:      "https://" + conf.Address + "/mutate/pods/dns",
+		CABundle: base64.StdEncoding.EncodeToString(conf.CACert),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to execute pod dns webhook yaml template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// BuildPodDNSWebhookConfig is the config for BuildPodDNSWebhook.
+type BuildPodDNSWebhookConfig struct {
+	// Address is the host:port of the kwok-controller reachable from kube-apiserver
+	Address string
+	// CACert is the PEM of the CA that signs the serving certificate of the kwok-controller
+	CACert []byte
+}
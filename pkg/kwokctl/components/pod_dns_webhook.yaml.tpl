@@ -0,0 +1,28 @@
+apiVersion: admissionregistration.k8s.io/v1
+kind: MutatingWebhookConfiguration
+metadata:
+  name: kwok-pod-dns
+webhooks:
+- name: pod-dns.kwok.x-k8s.io
+  admissionReviewVersions:
+  - v1
+  clientConfig:
+    url: {{ .URL }}
+    caBundle: {{ .CABundle }}
+  rules:
+  - apiGroups:
+    - ""
+    apiVersions:
+    - v1
+    operations:
+    - CREATE
+    resources:
+    - pods
+  failurePolicy: Ignore
+  sideEffects: None
+  namespaceSelector:
+    matchExpressions:
+    - key: kubernetes.io/metadata.name
+      operator: NotIn
+      values:
+      - kube-system
@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/wait"
+)
+
+const (
+	defaultRetryInterval = 5 * time.Second
+	defaultTimeout       = 5 * time.Minute
+
+	mirrorPodAnnotation = "kubernetes.io/config.mirror"
+)
+
+// Config is the configuration for draining a node.
+type Config struct {
+	// NodeName is the name of the node to drain.
+	NodeName string
+	// Recreate recreates the evicted pods which are not managed by a controller,
+	// without the node name so that they can be scheduled elsewhere.
+	Recreate bool
+	// RetryInterval is the interval to retry the eviction blocked by a PodDisruptionBudget.
+	RetryInterval time.Duration
+	// Timeout is the timeout to wait for each pod to be evicted.
+	Timeout time.Duration
+	DryRun  bool
+}
+
+// Cordon marks the node as unschedulable or schedulable.
+func Cordon(ctx context.Context, clientset kubernetes.Interface, nodeName string, unschedulable bool) error {
+	patch := fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable)
+	_, err := clientset.CoreV1().Nodes().Patch(ctx, nodeName, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to patch node %s: %w", nodeName, err)
+	}
+	return nil
+}
+
+// Drain cordons the node and evicts the pods on it.
+// The eviction blocked by a PodDisruptionBudget is retried until the timeout.
+func Drain(ctx context.Context, clientset kubernetes.Interface, conf Config) error {
+	if conf.RetryInterval <= 0 {
+		conf.RetryInterval = defaultRetryInterval
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = defaultTimeout
+	}
+
+	if conf.DryRun {
+		dryrun.PrintMessage("kubectl drain %s --ignore-daemonsets", conf.NodeName)
+		return nil
+	}
+
+	err := Cordon(ctx, clientset, conf.NodeName, true)
+	if err != nil {
+		return err
+	}
+
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", conf.NodeName).String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pods on node %s: %w", conf.NodeName, err)
+	}
+
+	logger := log.FromContext(ctx)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !needEvict(pod) {
+			logger.Debug("Skip pod", "pod", log.KObj(pod))
+			continue
+		}
+
+		err = evictPod(ctx, clientset, pod, conf)
+		if err != nil {
+			return err
+		}
+		logger.Info("Evicted pod", "pod", log.KObj(pod))
+
+		if conf.Recreate && metav1.GetControllerOf(pod) == nil {
+			err = recreatePod(ctx, clientset, pod, conf)
+			if err != nil {
+				return err
+			}
+			logger.Info("Recreated pod", "pod", log.KObj(pod))
+		}
+	}
+	return nil
+}
+
+// needEvict returns whether the pod should be evicted,
+// the pods managed by DaemonSet, the mirror pods and the terminating pods are skipped.
+func needEvict(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil {
+		return false
+	}
+	if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+		return false
+	}
+	if ref := metav1.GetControllerOf(pod); ref != nil && ref.Kind == "DaemonSet" {
+		return false
+	}
+	return true
+}
+
+func evictPod(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, conf Config) error {
+	logger := log.FromContext(ctx)
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+	}
+	err := wait.Poll(ctx, func(ctx context.Context) (bool, error) {
+		err := clientset.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction)
+		if err == nil || apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		if apierrors.IsTooManyRequests(err) {
+			logger.Info("Eviction is blocked by PodDisruptionBudget, retrying",
+				"pod", log.KObj(pod),
+				"err", err,
+			)
+			return false, nil
+		}
+		return false, err
+	},
+		wait.WithImmediate(),
+		wait.WithInterval(conf.RetryInterval),
+		wait.WithTimeout(conf.Timeout),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to evict pod %s: %w", log.KObj(pod), err)
+	}
+	return nil
+}
+
+func recreatePod(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, conf Config) error {
+	// Wait for the evicted pod to be deleted, so that the pod with the same name can be created.
+	err := wait.Poll(ctx, func(ctx context.Context) (bool, error) {
+		_, err := clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	},
+		wait.WithImmediate(),
+		wait.WithInterval(conf.RetryInterval),
+		wait.WithTimeout(conf.Timeout),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to wait for pod %s to be deleted: %w", log.KObj(pod), err)
+	}
+
+	newPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            pod.Name,
+			Namespace:       pod.Namespace,
+			Labels:          pod.Labels,
+			Annotations:     pod.Annotations,
+			OwnerReferences: pod.OwnerReferences,
+		},
+		Spec: *pod.Spec.DeepCopy(),
+	}
+	newPod.Spec.NodeName = ""
+
+	_, err = clientset.CoreV1().Pods(pod.Namespace).Create(ctx, newPod, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to recreate pod %s: %w", log.KObj(pod), err)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"sigs.k8s.io/kwok/pkg/utils/format"
+)
+
+func TestDrain(t *testing.T) {
+	tests := []struct {
+		name string
+		// blocked is the number of evictions blocked by a PodDisruptionBudget before it succeeds,
+		// -1 means always blocked.
+		blocked      int
+		recreate     bool
+		wantErr      bool
+		wantEvicted  []string
+		wantRecreate []string
+	}{
+		{
+			name:        "without pdb",
+			wantEvicted: []string{"pod-0", "pod-1"},
+		},
+		{
+			name:        "with pdb blocking temporarily",
+			blocked:     2,
+			wantEvicted: []string{"pod-0", "pod-1"},
+		},
+		{
+			name:    "with pdb blocking",
+			blocked: -1,
+			wantErr: true,
+		},
+		{
+			name:         "recreate",
+			recreate:     true,
+			wantEvicted:  []string{"pod-0", "pod-1"},
+			wantRecreate: []string{"pod-0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-0"},
+			}
+			pods := []runtime.Object{
+				newPod("pod-0", "node-0", nil),
+				newPod("pod-1", "node-0", &metav1.OwnerReference{Kind: "ReplicaSet", Name: "rs", Controller: format.Ptr(true)}),
+				newPod("daemon", "node-0", &metav1.OwnerReference{Kind: "DaemonSet", Name: "ds", Controller: format.Ptr(true)}),
+			}
+			clientset := fake.NewSimpleClientset(append(pods, node)...)
+
+			var evicted []string
+			blocked := tt.blocked
+			clientset.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() != "eviction" {
+					return false, nil, nil
+				}
+				eviction := action.(clienttesting.CreateAction).GetObject().(*policyv1.Eviction)
+				if blocked != 0 {
+					blocked--
+					return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+				}
+				evicted = append(evicted, eviction.Name)
+				err := clientset.Tracker().Delete(corev1.SchemeGroupVersion.WithResource("pods"), eviction.Namespace, eviction.Name)
+				return true, nil, err
+			})
+
+			err := Drain(context.Background(), clientset, Config{
+				NodeName:      "node-0",
+				Recreate:      tt.recreate,
+				RetryInterval: 10 * time.Millisecond,
+				Timeout:       100 * time.Millisecond,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Drain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			gotNode, err := clientset.CoreV1().Nodes().Get(context.Background(), "node-0", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !gotNode.Spec.Unschedulable {
+				t.Errorf("want node to be cordoned")
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if len(evicted) != len(tt.wantEvicted) {
+				t.Fatalf("want evicted %v, got %v", tt.wantEvicted, evicted)
+			}
+			for i := range evicted {
+				if evicted[i] != tt.wantEvicted[i] {
+					t.Errorf("want evicted %v, got %v", tt.wantEvicted, evicted)
+				}
+			}
+
+			for _, name := range tt.wantRecreate {
+				pod, err := clientset.CoreV1().Pods("default").Get(context.Background(), name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatalf("want pod %s to be recreated: %v", name, err)
+				}
+				if pod.Spec.NodeName != "" {
+					t.Errorf("want recreated pod %s without node name, got %q", name, pod.Spec.NodeName)
+				}
+			}
+
+			_, err = clientset.CoreV1().Pods("default").Get(context.Background(), "daemon", metav1.GetOptions{})
+			if err != nil {
+				t.Errorf("want daemon pod to be kept: %v", err)
+			}
+		})
+	}
+}
+
+func newPod(name, nodeName string, owner *metav1.OwnerReference) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+		},
+	}
+	if owner != nil {
+		pod.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
+	return pod
+}
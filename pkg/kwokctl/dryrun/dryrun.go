@@ -79,3 +79,51 @@ func (d *dryRunWriter) Close() error {
 func NewCatToFileWriter(name string) io.WriteCloser {
 	return newCatToFileWriter(stdout, name)
 }
+
+type pipeToCommandWriter struct {
+	command string
+}
+
+func (p *pipeToCommandWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+// NewPipeToCommandWriter returns a writer that stands for piping the content to the command.
+func NewPipeToCommandWriter(command string) io.Writer {
+	return &pipeToCommandWriter{
+		command: command,
+	}
+}
+
+// IsPipeToCommandWriter returns the command if the writer is a pipe to command writer.
+func IsPipeToCommandWriter(w io.Writer) (string, bool) {
+	p, ok := w.(*pipeToCommandWriter)
+	if !ok {
+		return "", false
+	}
+	return p.command, true
+}
+
+type pipeFromCommandReader struct {
+	command string
+}
+
+func (p *pipeFromCommandReader) Read([]byte) (int, error) {
+	return 0, io.EOF
+}
+
+// NewPipeFromCommandReader returns a reader that stands for piping the content from the command.
+func NewPipeFromCommandReader(command string) io.Reader {
+	return &pipeFromCommandReader{
+		command: command,
+	}
+}
+
+// IsPipeFromCommandReader returns the command if the reader is a pipe from command reader.
+func IsPipeFromCommandReader(r io.Reader) (string, bool) {
+	p, ok := r.(*pipeFromCommandReader)
+	if !ok {
+		return "", false
+	}
+	return p.command, true
+}
@@ -18,6 +18,7 @@ package dryrun
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 )
 
@@ -95,3 +96,29 @@ func TestNewCatToFileWriter(t *testing.T) {
 		t.Errorf("expected *dryRunWriter, got %T", writer)
 	}
 }
+
+// TestIsPipeToCommandWriter tests the IsPipeToCommandWriter function
+func TestIsPipeToCommandWriter(t *testing.T) {
+	command, ok := IsPipeToCommandWriter(NewPipeToCommandWriter("aws s3 cp - s3://bucket/key"))
+	if !ok || command != "aws s3 cp - s3://bucket/key" {
+		t.Errorf("expected true and the command, got %v and %q", ok, command)
+	}
+
+	_, ok = IsPipeToCommandWriter(&bytes.Buffer{})
+	if ok {
+		t.Errorf("expected false, got %v", ok)
+	}
+}
+
+// TestIsPipeFromCommandReader tests the IsPipeFromCommandReader function
+func TestIsPipeFromCommandReader(t *testing.T) {
+	command, ok := IsPipeFromCommandReader(NewPipeFromCommandReader("aws s3 cp s3://bucket/key -"))
+	if !ok || command != "aws s3 cp s3://bucket/key -" {
+		t.Errorf("expected true and the command, got %v and %q", ok, command)
+	}
+
+	_, ok = IsPipeFromCommandReader(strings.NewReader(""))
+	if ok {
+		t.Errorf("expected false, got %v", ok)
+	}
+}
@@ -144,7 +144,11 @@ func (l *Loader) Load(ctx context.Context, decoder *yaml.Decoder) error {
 	return nil
 }
 
-// Replay replays the resources to cluster
+// replayReorderWindow is the number of the resource patches buffered to reorder them by time
+const replayReorderWindow = 1024
+
+// Replay replays the resources to cluster,
+// the decoder is consumed as a stream and only a window of the resource patches is buffered.
 func (l *Loader) Replay(ctx context.Context, decoder *yaml.Decoder) error {
 	logger := log.FromContext(ctx)
 
@@ -177,8 +181,9 @@ func (l *Loader) Replay(ctx context.Context, decoder *yaml.Decoder) error {
 
 		h.Push(resourcePatch.DurationNanosecond, &resourcePatch)
 
-		// Tolerate events that are out of order over a period of time
-		if h.Len() >= 1024 {
+		// Tolerate events that are out of order over a period of time,
+		// at most replayReorderWindow events are kept in memory.
+		if h.Len() >= replayReorderWindow {
 			_, rp, _ := h.Pop()
 			l.handleResourcePatch(ctx, rp, &dur)
 		}
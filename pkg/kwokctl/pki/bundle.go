@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+// ValidateCABundle checks that the data is a bundle of PEM-encoded certificates
+func ValidateCABundle(data []byte) error {
+	rest := data
+	count := 0
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		count++
+		if block.Type != CertificateBlockType {
+			return fmt.Errorf("unexpected PEM block %d of type %q in CA bundle", count, block.Type)
+		}
+		_, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("failed to parse certificate %d in CA bundle: %w", count, err)
+		}
+	}
+	if count == 0 {
+		return fmt.Errorf("no certificate found in CA bundle")
+	}
+	if len(bytes.TrimSpace(rest)) != 0 {
+		return fmt.Errorf("unexpected data after certificate %d in CA bundle", count)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateCABundle(t *testing.T) {
+	now := time.Now()
+	notBefore := now.UTC()
+	notAfter := now.Add(CertificateValidity).UTC()
+
+	caCert, caKey, err := GenerateCA("kwok-ca", notBefore, notAfter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCert, _, err := GenerateCA("other-ca", notBefore, notAfter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caPEM := EncodeCertToPEM(caCert)
+	otherPEM := EncodeCertToPEM(otherCert)
+	keyPEM, err := EncodePrivateKeyToPEM(caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{
+			name: "single certificate",
+			data: caPEM,
+		},
+		{
+			name: "multiple certificates",
+			data: append(append([]byte{}, caPEM...), otherPEM...),
+		},
+		{
+			name:    "empty",
+			data:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "not pem",
+			data:    []byte("not a certificate"),
+			wantErr: true,
+		},
+		{
+			name:    "private key",
+			data:    append(append([]byte{}, caPEM...), keyPEM...),
+			wantErr: true,
+		},
+		{
+			name:    "trailing data",
+			data:    append(append([]byte{}, caPEM...), []byte("garbage")...),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCABundle(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCABundle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -120,3 +120,56 @@ func GenerateSignCert(cn string, caCert *x509.Certificate, caKey crypto.Signer,
 	}
 	return NewCertAndKey(caCert, caKey, certConfig)
 }
+
+// RotatePki regenerates the admin cert and key in the pki,
+// the CA is regenerated too if rotateCA is true.
+// The alt names of the current admin cert are kept.
+func RotatePki(pkiPath string, rotateCA bool) error {
+	cert, _, err := ReadCertAndKey(pkiPath, "admin")
+	if err != nil {
+		return fmt.Errorf("failed to read admin cert and key: %w", err)
+	}
+	sans := make([]string, 0, len(cert.DNSNames)+len(cert.IPAddresses))
+	sans = append(sans, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+
+	if rotateCA {
+		return GeneratePki(pkiPath, sans...)
+	}
+
+	return reissueAdminCert(pkiPath, sans)
+}
+
+// ReissuePki regenerates the admin cert and key with the default and the given alt names,
+// the CA is kept so that the clients trusting it keep working.
+func ReissuePki(pkiPath string, sans ...string) error {
+	allSANs := append(slices.Clone(DefaultAltNames), sans...)
+	return reissueAdminCert(pkiPath, allSANs)
+}
+
+// reissueAdminCert regenerates the admin cert and key signed by the CA in the pki
+func reissueAdminCert(pkiPath string, sans []string) error {
+	caCert, caKey, err := ReadCertAndKey(pkiPath, "ca")
+	if err != nil {
+		return fmt.Errorf("failed to read CA: %w", err)
+	}
+
+	now := time.Now()
+	notBefore := now.Add(-24 * time.Hour).UTC()
+	notAfter := now.Add(CertificateValidity).UTC()
+	if notAfter.After(caCert.NotAfter) {
+		notAfter = caCert.NotAfter
+	}
+
+	cert, key, err := GenerateSignCert(DefaultUser, caCert, caKey, notBefore, notAfter, DefaultGroups, sans)
+	if err != nil {
+		return fmt.Errorf("failed to generate admin cert and key: %w", err)
+	}
+	err = WriteCertAndKey(pkiPath, "admin", cert, key)
+	if err != nil {
+		return fmt.Errorf("failed to write admin cert and key: %w", err)
+	}
+	return nil
+}
@@ -18,8 +18,11 @@ package pki
 
 import (
 	"fmt"
+	"net"
 	"testing"
 	"time"
+
+	"sigs.k8s.io/kwok/pkg/utils/slices"
 )
 
 func TestGeneratePki(t *testing.T) {
@@ -44,3 +47,114 @@ func TestGeneratePki(t *testing.T) {
 
 	_ = EncodeCertToPEM(cert)
 }
+
+func TestRotatePki(t *testing.T) {
+	pkiPath := t.TempDir()
+	err := GeneratePki(pkiPath, "kwok.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldCA, _, err := ReadCertAndKey(pkiPath, "ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldCert, _, err := ReadCertAndKey(pkiPath, "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = RotatePki(pkiPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca, _, err := ReadCertAndKey(pkiPath, "ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _, err := ReadCertAndKey(pkiPath, "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ca.Equal(oldCA) {
+		t.Errorf("want CA to be kept")
+	}
+	if cert.Equal(oldCert) {
+		t.Errorf("want admin cert to be rotated")
+	}
+	if !slices.Contains(cert.DNSNames, "kwok.example.com") {
+		t.Errorf("want alt name kwok.example.com in %v", cert.DNSNames)
+	}
+	err = cert.CheckSignatureFrom(ca)
+	if err != nil {
+		t.Errorf("want admin cert signed by CA: %v", err)
+	}
+
+	err = RotatePki(pkiPath, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newCA, _, err := ReadCertAndKey(pkiPath, "ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newCert, _, err := ReadCertAndKey(pkiPath, "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newCA.Equal(ca) {
+		t.Errorf("want CA to be rotated")
+	}
+	if !slices.Contains(newCert.DNSNames, "kwok.example.com") {
+		t.Errorf("want alt name kwok.example.com in %v", newCert.DNSNames)
+	}
+	err = newCert.CheckSignatureFrom(newCA)
+	if err != nil {
+		t.Errorf("want admin cert signed by the new CA: %v", err)
+	}
+}
+
+func TestReissuePki(t *testing.T) {
+	pkiPath := t.TempDir()
+	err := GeneratePki(pkiPath, "old.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldCA, _, err := ReadCertAndKey(pkiPath, "ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ReissuePki(pkiPath, "new.example.com", "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca, _, err := ReadCertAndKey(pkiPath, "ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _, err := ReadCertAndKey(pkiPath, "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ca.Equal(oldCA) {
+		t.Errorf("want CA to be kept")
+	}
+	if slices.Contains(cert.DNSNames, "old.example.com") {
+		t.Errorf("want alt name old.example.com dropped from %v", cert.DNSNames)
+	}
+	if !slices.Contains(cert.DNSNames, "new.example.com") || !slices.Contains(cert.DNSNames, "localhost") {
+		t.Errorf("want alt names new.example.com and localhost in %v", cert.DNSNames)
+	}
+	if len(cert.IPAddresses) == 0 || !cert.IPAddresses[len(cert.IPAddresses)-1].Equal(net.ParseIP("10.0.0.1")) {
+		t.Errorf("want ip 10.0.0.1 in %v", cert.IPAddresses)
+	}
+	err = cert.CheckSignatureFrom(ca)
+	if err != nil {
+		t.Errorf("want admin cert signed by CA: %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"fmt"
+	"net/url"
+
+	"sigs.k8s.io/kwok/pkg/utils/kubeconfig"
+)
+
+// ValidateAuditWebhook validates the endpoint that the audit events are sent to.
+func ValidateAuditWebhook(server string, auditPolicy string) error {
+	if auditPolicy == "" {
+		return fmt.Errorf("the audit webhook requires an audit policy")
+	}
+	u, err := url.Parse(server)
+	if err != nil {
+		return fmt.Errorf("invalid audit webhook %q: %w", server, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid audit webhook %q: unsupported scheme %q", server, u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid audit webhook %q: missing host", server)
+	}
+	return nil
+}
+
+// BuildAuditWebhookConfig builds the kubeconfig used by the apiserver to send the audit events to the endpoint.
+func BuildAuditWebhookConfig(server string) ([]byte, error) {
+	return kubeconfig.EncodeKubeconfig(kubeconfig.BuildKubeconfig(kubeconfig.BuildKubeconfigConfig{
+		ProjectName: "audit-webhook",
+		Address:     server,
+	}))
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kwok/pkg/utils/kubeconfig"
+)
+
+func TestValidateAuditWebhook(t *testing.T) {
+	tests := []struct {
+		name        string
+		server      string
+		auditPolicy string
+		wantErr     bool
+	}{
+		{
+			name:        "http",
+			server:      "http://127.0.0.1:8080/audit",
+			auditPolicy: "audit.yaml",
+		},
+		{
+			name:        "https",
+			server:      "https://audit.example.com",
+			auditPolicy: "audit.yaml",
+		},
+		{
+			name:    "without audit policy",
+			server:  "http://127.0.0.1:8080/audit",
+			wantErr: true,
+		},
+		{
+			name:        "unsupported scheme",
+			server:      "ftp://127.0.0.1/audit",
+			auditPolicy: "audit.yaml",
+			wantErr:     true,
+		},
+		{
+			name:        "missing host",
+			server:      "127.0.0.1:8080",
+			auditPolicy: "audit.yaml",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAuditWebhook(tt.server, tt.auditPolicy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAuditWebhook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildAuditWebhookConfig(t *testing.T) {
+	server := "http://127.0.0.1:8080/audit"
+	data, err := BuildAuditWebhookConfig(server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := kubeconfig.DecodeKubeconfig(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	context, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		t.Fatalf("current context %q not found", config.CurrentContext)
+	}
+	cluster, ok := config.Clusters[context.Cluster]
+	if !ok {
+		t.Fatalf("cluster %q not found", context.Cluster)
+	}
+	if cluster.Server != server {
+		t.Errorf("want server %q, got %q", server, cluster.Server)
+	}
+}
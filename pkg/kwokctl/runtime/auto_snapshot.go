@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kwok/pkg/consts"
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/file"
+)
+
+// AutoSnapshotsName is the directory in the workdir to keep the automatic snapshots of etcd
+var AutoSnapshotsName = "snapshots"
+
+const (
+	autoSnapshotProcess    = "auto-snapshot"
+	autoSnapshotPrefix     = "auto-"
+	autoSnapshotSuffix     = ".db"
+	autoSnapshotTimeLayout = "20060102T150405Z"
+)
+
+// AutoSnapshotConfig is the configuration of the automatic snapshots
+type AutoSnapshotConfig struct {
+	// Interval is the interval between two snapshots
+	Interval time.Duration
+	// Retention is the number of the latest snapshots to keep, the older ones are pruned
+	Retention int
+}
+
+// AutoSnapshot saves the snapshot of etcd into the workdir right away and then at every interval until ctx is done,
+// and prunes the snapshots beyond the retention.
+func AutoSnapshot(ctx context.Context, rt Runtime, conf AutoSnapshotConfig) error {
+	if conf.Interval <= 0 {
+		return fmt.Errorf("interval must be greater than 0, got %s", conf.Interval)
+	}
+	if conf.Retention <= 0 {
+		return fmt.Errorf("retention must be greater than 0, got %d", conf.Retention)
+	}
+
+	dir := rt.GetWorkdirPath(AutoSnapshotsName)
+	if dryrun.DryRun {
+		dryrun.PrintMessage("# Save a snapshot to %s every %s and keep the latest %d", dir, conf.Interval, conf.Retention)
+		return nil
+	}
+
+	err := file.MkdirAll(dir)
+	if err != nil {
+		return err
+	}
+
+	save := func(now time.Time) {
+		logger := log.FromContext(ctx)
+		snapshotPath := filepath.Join(dir, autoSnapshotName(now))
+		err := rt.SnapshotSave(ctx, snapshotPath)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Error("Failed to save snapshot", err,
+					"path", snapshotPath,
+				)
+			}
+			return
+		}
+		logger.Info("Saved snapshot", "path", snapshotPath)
+
+		pruned, err := pruneAutoSnapshots(dir, conf.Retention)
+		if err != nil {
+			logger.Error("Failed to prune snapshots", err,
+				"dir", dir,
+			)
+			return
+		}
+		for _, p := range pruned {
+			logger.Info("Pruned snapshot", "path", p)
+		}
+	}
+
+	save(time.Now())
+
+	ticker := time.NewTicker(conf.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			save(now)
+		}
+	}
+}
+
+// StartAutoSnapshot starts saving the snapshots of etcd in the background
+// if the cluster is configured with an auto snapshot interval.
+func (c *Cluster) StartAutoSnapshot(ctx context.Context) error {
+	config, err := c.Config(ctx)
+	if err != nil {
+		return err
+	}
+	conf := config.Options
+	if conf.AutoSnapshotInterval == "" {
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	args := []string{
+		"--name=" + strings.TrimPrefix(c.Name(), consts.ProjectName+"-"),
+		"snapshot",
+		"auto",
+		"--interval=" + conf.AutoSnapshotInterval,
+	}
+	if conf.AutoSnapshotRetention > 0 {
+		args = append(args, "--retention="+strconv.Itoa(conf.AutoSnapshotRetention))
+	}
+	return c.forkExec(ctx, c.Workdir(), autoSnapshotProcess, exe, args...)
+}
+
+// StopAutoSnapshot stops saving the snapshots of etcd in the background.
+func (c *Cluster) StopAutoSnapshot(ctx context.Context) error {
+	return c.forkExecKill(ctx, c.Workdir(), autoSnapshotProcess)
+}
+
+// autoSnapshotName returns the file name of the automatic snapshot taken at t,
+// which sorts in the order of time.
+func autoSnapshotName(t time.Time) string {
+	return autoSnapshotPrefix + t.UTC().Format(autoSnapshotTimeLayout) + autoSnapshotSuffix
+}
+
+// pruneAutoSnapshots removes the automatic snapshots in dir beyond the latest retention,
+// the other files in dir are left alone, it returns the paths of the removed snapshots.
+func pruneAutoSnapshots(dir string, retention int) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() ||
+			!strings.HasPrefix(name, autoSnapshotPrefix) ||
+			!strings.HasSuffix(name, autoSnapshotSuffix) {
+			continue
+		}
+		names = append(names, name)
+	}
+	if len(names) <= retention {
+		return nil, nil
+	}
+
+	sort.Strings(names)
+	pruned := []string{}
+	for _, name := range names[:len(names)-retention] {
+		p := filepath.Join(dir, name)
+		err := file.Remove(p)
+		if err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, p)
+	}
+	return pruned, nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestPruneAutoSnapshots(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	snapshots := []string{
+		autoSnapshotName(base.Add(2 * time.Minute)),
+		autoSnapshotName(base),
+		autoSnapshotName(base.Add(10 * time.Minute)),
+		autoSnapshotName(base.Add(time.Minute)),
+	}
+	others := []string{
+		"restart.db",
+		"auto-notes.txt",
+	}
+
+	tests := []struct {
+		name       string
+		retention  int
+		wantPruned []string
+	}{
+		{
+			name:       "keep all",
+			retention:  4,
+			wantPruned: nil,
+		},
+		{
+			name:      "keep more than exist",
+			retention: 10,
+		},
+		{
+			name:      "keep the latest two",
+			retention: 2,
+			wantPruned: []string{
+				autoSnapshotName(base),
+				autoSnapshotName(base.Add(time.Minute)),
+			},
+		},
+		{
+			name:      "keep the latest one",
+			retention: 1,
+			wantPruned: []string{
+				autoSnapshotName(base),
+				autoSnapshotName(base.Add(time.Minute)),
+				autoSnapshotName(base.Add(2 * time.Minute)),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range append(append([]string{}, snapshots...), others...) {
+				err := os.WriteFile(filepath.Join(dir, name), nil, 0640)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			pruned, err := pruneAutoSnapshots(dir, tt.retention)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotPruned []string
+			for _, p := range pruned {
+				gotPruned = append(gotPruned, filepath.Base(p))
+			}
+			if !reflect.DeepEqual(gotPruned, tt.wantPruned) {
+				t.Errorf("pruneAutoSnapshots() = %v, want %v", gotPruned, tt.wantPruned)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := len(snapshots) + len(others) - len(tt.wantPruned); len(entries) != want {
+				t.Errorf("got %d files left, want %d", len(entries), want)
+			}
+			for _, name := range others {
+				if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+					t.Errorf("want %q to be kept: %v", name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestAutoSnapshotName(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	names := []string{
+		autoSnapshotName(base.Add(time.Hour)),
+		autoSnapshotName(base.Add(time.Second)),
+		autoSnapshotName(base.Add(24 * time.Hour)),
+		autoSnapshotName(base),
+	}
+	sort.Strings(names)
+	want := []string{
+		"auto-20240101T000000Z.db",
+		"auto-20240101T000001Z.db",
+		"auto-20240101T010000Z.db",
+		"auto-20240102T000000Z.db",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+}
+
+type fakeSnapshotRuntime struct {
+	Runtime
+	workdir string
+	saved   chan string
+}
+
+func (r *fakeSnapshotRuntime) GetWorkdirPath(name string) string {
+	return filepath.Join(r.workdir, name)
+}
+
+func (r *fakeSnapshotRuntime) SnapshotSave(_ context.Context, path string) error {
+	err := os.WriteFile(path, nil, 0640)
+	if err != nil {
+		return err
+	}
+	r.saved <- path
+	return nil
+}
+
+func TestAutoSnapshotSavesImmediately(t *testing.T) {
+	rt := &fakeSnapshotRuntime{
+		workdir: t.TempDir(),
+		saved:   make(chan string, 1),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- AutoSnapshot(ctx, rt, AutoSnapshotConfig{
+			Interval:  time.Hour,
+			Retention: 1,
+		})
+	}()
+
+	select {
+	case p := <-rt.saved:
+		if dir := filepath.Dir(p); dir != rt.GetWorkdirPath(AutoSnapshotsName) {
+			t.Errorf("got snapshot in %q, want in %q", dir, rt.GetWorkdirPath(AutoSnapshotsName))
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("want a snapshot before the first interval")
+	}
+
+	cancel()
+	err := <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+}
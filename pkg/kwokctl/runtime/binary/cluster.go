@@ -19,6 +19,7 @@ package binary
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -71,18 +72,8 @@ func (c *Cluster) setup(ctx context.Context, env *env) error {
 
 	pkiPath := c.GetWorkdirPath(runtime.PkiName)
 	if !file.Exists(pkiPath) {
-		sans := []string{}
-		ips, err := net.GetAllIPs()
-		if err != nil {
-			logger := log.FromContext(ctx)
-			logger.Warn("failed to get all ips", "err", err)
-		} else {
-			sans = append(sans, ips...)
-		}
-		if len(conf.KubeApiserverCertSANs) != 0 {
-			sans = append(sans, conf.KubeApiserverCertSANs...)
-		}
-		err = c.MkdirAll(pkiPath)
+		sans := runtime.CertSANs(ctx, c.Name(), conf, false)
+		err := c.MkdirAll(pkiPath)
 		if err != nil {
 			return fmt.Errorf("failed to create pki dir: %w", err)
 		}
@@ -104,6 +95,24 @@ func (c *Cluster) setup(ctx context.Context, env *env) error {
 		if err != nil {
 			return err
 		}
+
+		if conf.KubeAuditWebhook != "" {
+			auditWebhookConfig, err := runtime.BuildAuditWebhookConfig(conf.KubeAuditWebhook)
+			if err != nil {
+				return fmt.Errorf("failed to build audit webhook config: %w", err)
+			}
+			err = c.WriteFile(env.auditWebhookPath, auditWebhookConfig)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if conf.CABundle != "" {
+		err := c.CopyFile(conf.CABundle, path.Join(pkiPath, runtime.CABundleName))
+		if err != nil {
+			return err
+		}
 	}
 
 	etcdDataPath := c.GetWorkdirPath(runtime.EtcdDataDirName)
@@ -134,10 +143,13 @@ type env struct {
 	inClusterKubeconfigPath string
 	kubeconfigPath          string
 	etcdDataPath            string
+	etcdMembers             []components.EtcdMember
 	kwokConfigPath          string
 	pkiPath                 string
 	auditLogPath            string
 	auditPolicyPath         string
+	auditWebhookPath        string
+	caBundlePath            string
 	workdir                 string
 	caCertPath              string
 	adminKeyPath            string
@@ -173,10 +185,18 @@ func (c *Cluster) env(ctx context.Context) (*env, error) {
 	adminCertPath := path.Join(pkiPath, "admin.crt")
 	auditLogPath := ""
 	auditPolicyPath := ""
+	auditWebhookPath := ""
 
 	if config.Options.KubeAuditPolicy != "" {
 		auditLogPath = c.GetLogPath(runtime.AuditLogName)
 		auditPolicyPath = c.GetWorkdirPath(runtime.AuditPolicyName)
+		if config.Options.KubeAuditWebhook != "" {
+			auditWebhookPath = c.GetWorkdirPath(runtime.AuditWebhookConfigName)
+		}
+	}
+	caBundlePath := ""
+	if config.Options.CABundle != "" {
+		caBundlePath = path.Join(pkiPath, runtime.CABundleName)
 	}
 
 	logger := log.FromContext(ctx)
@@ -194,6 +214,8 @@ func (c *Cluster) env(ctx context.Context) (*env, error) {
 		pkiPath:                 pkiPath,
 		auditLogPath:            auditLogPath,
 		auditPolicyPath:         auditPolicyPath,
+		auditWebhookPath:        auditWebhookPath,
+		caBundlePath:            caBundlePath,
 		workdir:                 workdir,
 		caCertPath:              caCertPath,
 		adminKeyPath:            adminKeyPath,
@@ -327,23 +349,66 @@ func (c *Cluster) addEtcd(ctx context.Context, env *env) (err error) {
 		return err
 	}
 
-	etcdComponent, err := components.BuildEtcdComponent(components.BuildEtcdComponentConfig{
-		Runtime:          conf.Runtime,
-		ProjectName:      c.Name(),
-		Workdir:          env.workdir,
-		Binary:           etcdPath,
-		Version:          etcdVersion,
-		BindAddress:      conf.BindAddress,
-		DataPath:         env.etcdDataPath,
-		Port:             conf.EtcdPort,
-		PeerPort:         conf.EtcdPeerPort,
-		Verbosity:        env.verbosity,
-		QuotaBackendSize: conf.EtcdQuotaBackendSize,
-	})
-	if err != nil {
-		return err
+	dataPaths := []string{env.etcdDataPath}
+	if conf.EtcdReplicas > 1 {
+		env.etcdMembers = []components.EtcdMember{
+			{
+				ComponentName: components.EtcdComponentName(0),
+				Address:       net.LocalAddress,
+				Port:          conf.EtcdPort,
+				PeerPort:      conf.EtcdPeerPort,
+			},
+		}
+		for i := 1; i < int(conf.EtcdReplicas); i++ {
+			member := components.EtcdMember{
+				ComponentName: components.EtcdComponentName(i),
+				Address:       net.LocalAddress,
+			}
+			err = c.setupPorts(ctx, env.usedPorts, &member.Port, &member.PeerPort)
+			if err != nil {
+				return err
+			}
+			env.etcdMembers = append(env.etcdMembers, member)
+
+			dataPath := c.GetWorkdirPath(runtime.EtcdDataDirName + "-" + format.String(i))
+			err = c.MkdirAll(dataPath)
+			if err != nil {
+				return fmt.Errorf("failed to mkdir etcd data path: %w", err)
+			}
+			dataPaths = append(dataPaths, dataPath)
+		}
+	}
+
+	for i, dataPath := range dataPaths {
+		port, peerPort, extraClientURLs := conf.EtcdPort, conf.EtcdPeerPort, conf.EtcdExtraClientURLs
+		if i != 0 {
+			// The extra client urls are only served by the first member, as they are bound to the same ports
+			port, peerPort, extraClientURLs = env.etcdMembers[i].Port, env.etcdMembers[i].PeerPort, nil
+		}
+		etcdComponent, err := components.BuildEtcdComponent(components.BuildEtcdComponentConfig{
+			Runtime:           conf.Runtime,
+			ProjectName:       c.Name(),
+			Workdir:           env.workdir,
+			Binary:            etcdPath,
+			Version:           etcdVersion,
+			BindAddress:       conf.BindAddress,
+			DataPath:          dataPath,
+			Port:              port,
+			PeerPort:          peerPort,
+			Verbosity:         env.verbosity,
+			QuotaBackendSize:  conf.EtcdQuotaBackendSize,
+			UnsafeNoFsync:     conf.EtcdUnsafeNoFsync,
+			HeartbeatInterval: conf.EtcdHeartbeatInterval,
+			ElectionTimeout:   conf.EtcdElectionTimeout,
+			ExtraClientURLs:   extraClientURLs,
+			Index:             i,
+			Members:           env.etcdMembers,
+		})
+		if err != nil {
+			return err
+		}
+		env.kwokctlConfig.Components = append(env.kwokctlConfig.Components, etcdComponent)
 	}
-	env.kwokctlConfig.Components = append(env.kwokctlConfig.Components, etcdComponent)
 	return nil
 }
 
@@ -386,29 +451,46 @@ func (c *Cluster) addKubeApiserver(ctx context.Context, env *env) (err error) {
 	}
 
 	kubeApiserverComponent, err := components.BuildKubeApiserverComponent(components.BuildKubeApiserverComponentConfig{
-		Runtime:           conf.Runtime,
-		ProjectName:       c.Name(),
-		Workdir:           env.workdir,
-		Binary:            kubeApiserverPath,
-		Version:           kubeApiserverVersion,
-		BindAddress:       conf.BindAddress,
-		Port:              conf.KubeApiserverPort,
-		EtcdAddress:       net.LocalAddress,
-		EtcdPort:          conf.EtcdPort,
-		KubeRuntimeConfig: conf.KubeRuntimeConfig,
-		KubeFeatureGates:  conf.KubeFeatureGates,
-		SecurePort:        conf.SecurePort,
-		KubeAuthorization: conf.KubeAuthorization,
-		KubeAdmission:     conf.KubeAdmission,
-		AuditPolicyPath:   env.auditPolicyPath,
-		AuditLogPath:      env.auditLogPath,
-		CaCertPath:        env.caCertPath,
-		AdminCertPath:     env.adminCertPath,
-		AdminKeyPath:      env.adminKeyPath,
-		Verbosity:         env.verbosity,
-		DisableQPSLimits:  conf.DisableQPSLimits,
-		TracingConfigPath: kubeApiserverTracingConfigPath,
-		EtcdPrefix:        conf.EtcdPrefix,
+		Runtime:               conf.Runtime,
+		ProjectName:           c.Name(),
+		Workdir:               env.workdir,
+		Binary:                kubeApiserverPath,
+		Version:               kubeApiserverVersion,
+		BindAddress:           conf.BindAddress,
+		Port:                  conf.KubeApiserverPort,
+		ReadOnlyPort:          conf.KubeApiserverReadOnlyPort,
+		EtcdAddress:           net.LocalAddress,
+		EtcdPort:              conf.EtcdPort,
+		EtcdMembers:           env.etcdMembers,
+		KubeRuntimeConfig:     conf.KubeRuntimeConfig,
+		KubeFeatureGates:      conf.KubeFeatureGates,
+		ServiceClusterIPRange: conf.KubeServiceClusterIPRange,
+		SecurePort:            conf.SecurePort,
+		KubeAuthorization:     conf.KubeAuthorization,
+		KubeAdmission:         conf.KubeAdmission,
+		AdmissionPlugins:      conf.KubeAdmissionPlugins,
+		MutatingWebhook:       !runtime.PodDNSConfig(conf).IsEmpty(),
+		AuditPolicyPath:       env.auditPolicyPath,
+		AuditLogPath:          env.auditLogPath,
+		AuditWebhookPath:      env.auditWebhookPath,
+		AuditLogMaxSize:       conf.KubeAuditLogMaxSize,
+		AuditLogMaxAge:        conf.KubeAuditLogMaxAge,
+		AuditLogMaxBackup:     conf.KubeAuditLogMaxBackup,
+		CaCertPath:            env.caCertPath,
+		AdminCertPath:         env.adminCertPath,
+		AdminKeyPath:          env.adminKeyPath,
+		Verbosity:             env.verbosity,
+		DisableQPSLimits:      conf.DisableQPSLimits,
+		TracingConfigPath:     kubeApiserverTracingConfigPath,
+		EtcdPrefix:            conf.EtcdPrefix,
+		TLSMinVersion:         conf.KubeApiserverTLSMinVersion,
+		TLSCipherSuites:       conf.KubeApiserverTLSCipherSuites,
+		RequestTimeout:        conf.KubeApiserverRequestTimeout,
+		MinRequestTimeout:     conf.KubeApiserverMinRequestTimeout,
+
+		PriorityAndFairness:         conf.KubeApiserverPriorityAndFairness,
+		MaxRequestsInflight:         conf.KubeApiserverMaxRequestsInflight,
+		MaxMutatingRequestsInflight: conf.KubeApiserverMaxMutatingRequestsInflight,
 	})
 	if err != nil {
 		return err
@@ -445,6 +527,34 @@ func (c *Cluster) addKubectlProxy(ctx context.Context, env *env) (err error) {
 		}
 		env.kwokctlConfig.Components = append(env.kwokctlConfig.Components, kubectlProxyComponent)
 	}
+
+	// Configure the unix socket proxy
+	if conf.KubeApiserverUnixSocketProxy {
+		// Both proxies are run by the same kubectl binary,
+		// which would share the pid file in the workdir.
+		if conf.KubeApiserverInsecurePort != 0 {
+			return fmt.Errorf("apiserver unix socket proxy cannot be used with insecure port")
+		}
+
+		kubectlPath, err := c.KubectlPath(ctx)
+		if err != nil {
+			return err
+		}
+
+		kubectlProxyComponent, err := components.BuildKubectlProxyComponent(components.BuildKubectlProxyComponentConfig{
+			Runtime:        conf.Runtime,
+			ProjectName:    c.Name(),
+			Workdir:        env.workdir,
+			Binary:         kubectlPath,
+			KubeconfigPath: env.inClusterKubeconfigPath,
+			UnixSocketPath: c.GetWorkdirPath(runtime.KubeApiserverSocketName),
+			Verbosity:      env.verbosity,
+		})
+		if err != nil {
+			return err
+		}
+		env.kwokctlConfig.Components = append(env.kwokctlConfig.Components, kubectlProxyComponent)
+	}
 	return nil
 }
 
@@ -579,6 +689,7 @@ func (c *Cluster) addKwokController(ctx context.Context, env *env) (err error) {
 		Version:                  kwokControllerVersion,
 		BindAddress:              conf.BindAddress,
 		Port:                     conf.KwokControllerPort,
+		ProfilingPort:            conf.KwokControllerProfilingPort,
 		ConfigPath:               env.kwokConfigPath,
 		KubeconfigPath:           env.inClusterKubeconfigPath,
 		CaCertPath:               env.caCertPath,
@@ -587,7 +698,14 @@ func (c *Cluster) addKwokController(ctx context.Context, env *env) (err error) {
 		NodeName:                 "localhost",
 		Verbosity:                env.verbosity,
 		NodeLeaseDurationSeconds: conf.NodeLeaseDurationSeconds,
+		ManageNodesParallelism:   conf.ManageNodesParallelism,
+		NodeCPUOvercommit:        conf.NodeCPUOvercommit,
+		NodeMemoryOvercommit:     conf.NodeMemoryOvercommit,
 		EnableCRDs:               conf.EnableCRDs,
+		PodDNSPolicy:             conf.PodDNSPolicy,
+		PodDNSNameservers:        conf.PodDNSNameservers,
+		PodDNSSearches:           conf.PodDNSSearches,
+		PodDNSOptions:            conf.PodDNSOptions,
 	})
 	if err != nil {
 		return err
@@ -733,8 +851,27 @@ func (c *Cluster) addJaeger(ctx context.Context, env *env) error {
 func (c *Cluster) finishInstall(ctx context.Context, env *env) error {
 	conf := &env.kwokctlConfig.Options
 
+	componentsPatches := make([]internalversion.ComponentPatches, 0, len(env.kwokctlConfig.ComponentsPatches))
+	for _, patch := range env.kwokctlConfig.ComponentsPatches {
+		if len(patch.CommandOverride) != 0 {
+			logger := log.FromContext(ctx)
+			logger.Warn("commandOverride config is not supported in binary runtime, ignored", "component", patch.Name)
+			// The binary runtime always runs the binary of the component, so the command is not overridden.
+			patch.CommandOverride = nil
+		}
+		if patch.RestartPolicy != "" {
+			logger := log.FromContext(ctx)
+			logger.Warn("restartPolicy config is not supported in binary runtime, ignored", "component", patch.Name)
+		}
+		componentsPatches = append(componentsPatches, patch)
+	}
+
 	for i := range env.kwokctlConfig.Components {
-		runtime.ApplyComponentPatches(ctx, &env.kwokctlConfig.Components[i], env.kwokctlConfig.ComponentsPatches)
+		err := runtime.ApplyComponentPatches(ctx, &env.kwokctlConfig.Components[i], componentsPatches)
+		if err != nil {
+			return err
+		}
+		components.ApplyCABundle(conf.Runtime, &env.kwokctlConfig.Components[i], env.caBundlePath)
 	}
 
 	// Setup kubeconfig
@@ -803,6 +940,18 @@ func (c *Cluster) startComponent(ctx context.Context, component internalversion.
 		return nil
 	}
 
+	if component.Name == consts.ComponentKubeApiserverSocketProxy {
+		// The kubectl proxy does not remove the socket when it is killed,
+		// so the stale socket must be removed before listening again.
+		socketPath := c.GetWorkdirPath(runtime.KubeApiserverSocketName)
+		if file.Exists(socketPath) {
+			err := c.Remove(socketPath)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	if len(component.Envs) > 0 {
 		ctx = exec.WithEnv(ctx, slices.Map(component.Envs, func(c internalversion.Env) string {
 			return fmt.Sprintf("%s=%s", c.Name, c.Value)
@@ -899,6 +1048,11 @@ func (c *Cluster) start(ctx context.Context) error {
 			logger.Warn("Cluster is not served yet", "err", err)
 		}
 	}
+
+	err = c.StartAutoSnapshot(ctx)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -940,7 +1094,12 @@ func (c *Cluster) waitServed(ctx context.Context, timeout time.Duration) error {
 }
 
 func (c *Cluster) stop(ctx context.Context) error {
-	err := wait.Poll(ctx, func(ctx context.Context) (bool, error) {
+	err := c.StopAutoSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = wait.Poll(ctx, func(ctx context.Context) (bool, error) {
 		err := c.stopComponents(ctx)
 		return err == nil, err
 	},
@@ -983,13 +1142,16 @@ func (c *Cluster) StopComponent(ctx context.Context, name string) error {
 }
 
 // Logs returns the logs of the specified component.
-func (c *Cluster) Logs(ctx context.Context, name string, out io.Writer) error {
+func (c *Cluster) Logs(ctx context.Context, name string, out io.Writer, opts runtime.LogsOptions) error {
 	_, err := c.GetComponent(ctx, name)
 	if err != nil {
 		return err
 	}
 
 	logger := log.FromContext(ctx)
+	if opts.Timestamps {
+		logger.Warn("The timestamps of logs are not supported by binary runtime, ignored")
+	}
 
 	logs := c.GetLogPath(name + ".log")
 	if c.IsDryRun() {
@@ -1020,13 +1182,16 @@ func (c *Cluster) Logs(ctx context.Context, name string, out io.Writer) error {
 }
 
 // LogsFollow follows the logs of the component
-func (c *Cluster) LogsFollow(ctx context.Context, name string, out io.Writer) error {
+func (c *Cluster) LogsFollow(ctx context.Context, name string, out io.Writer, opts runtime.LogsOptions) error {
 	_, err := c.GetComponent(ctx, name)
 	if err != nil {
 		return err
 	}
 
 	logger := log.FromContext(ctx)
+	if opts.Timestamps {
+		logger.Warn("The timestamps of logs are not supported by binary runtime, ignored")
+	}
 
 	logs := c.GetLogPath(name + ".log")
 	if c.IsDryRun() {
@@ -1057,6 +1222,14 @@ func (c *Cluster) LogsFollow(ctx context.Context, name string, out io.Writer) er
 	return nil
 }
 
+// Attach follows the logs of the component,
+// the components of binary runtime are forked processes without stdio to attach to.
+func (c *Cluster) Attach(ctx context.Context, name string) error {
+	logger := log.FromContext(ctx)
+	logger.Warn("Attach is not supported by binary runtime, following the logs instead")
+	return c.LogsFollow(ctx, name, os.Stdout, runtime.LogsOptions{})
+}
+
 // CollectLogs returns the logs of the specified component.
 func (c *Cluster) CollectLogs(ctx context.Context, dir string) error {
 	logger := log.FromContext(ctx)
@@ -1100,11 +1273,13 @@ func (c *Cluster) CollectLogs(ctx context.Context, dir string) error {
 		return err
 	}
 
+	var errs []error
 	for _, component := range conf.Components {
 		src := c.GetLogPath(component.Name + ".log")
 		dest := path.Join(componentsDir, component.Name+".log")
 		if err = c.CopyFile(src, dest); err != nil {
 			logger.Error("Failed to copy file", err)
+			errs = append(errs, fmt.Errorf("failed to collect logs of %s: %w", component.Name, err))
 		}
 	}
 	if conf.Options.KubeAuditPolicy != "" {
@@ -1112,10 +1287,11 @@ func (c *Cluster) CollectLogs(ctx context.Context, dir string) error {
 		dest := path.Join(componentsDir, runtime.AuditLogName)
 		if err = c.CopyFile(src, dest); err != nil {
 			logger.Error("Failed to copy file", err)
+			errs = append(errs, fmt.Errorf("failed to collect audit logs: %w", err))
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // ListBinaries list binaries in the cluster
@@ -1165,7 +1341,10 @@ func (c *Cluster) InspectComponent(ctx context.Context, name string) (runtime.Co
 		return runtime.ComponentStatusStopped, nil
 	}
 
-	// TODO: check if the component is ready
+	ready, err := runtime.ComponentReady(ctx, c, component)
+	if err != nil || !ready {
+		return runtime.ComponentStatusRunning, nil
+	}
 
 	return runtime.ComponentStatusReady, nil
 }
@@ -1234,6 +1413,9 @@ func (c *Cluster) InitCRs(ctx context.Context) error {
 		if conf.EnableMetricsServer {
 			dryrun.PrintMessage("# Set up apiservice for metrics server")
 		}
+		if !runtime.PodDNSConfig(&conf).IsEmpty() {
+			dryrun.PrintMessage("# Set up mutating webhook for pod dns")
+		}
 
 		return nil
 	}
@@ -1251,6 +1433,15 @@ func (c *Cluster) InitCRs(ctx context.Context) error {
 		_, _ = buf.WriteString("---\n")
 	}
 
+	if !runtime.PodDNSConfig(&conf).IsEmpty() {
+		webhook, err := c.BuildPodDNSWebhook(net.LocalAddress, conf.KwokControllerPort)
+		if err != nil {
+			return err
+		}
+		_, _ = buf.WriteString(webhook)
+		_, _ = buf.WriteString("---\n")
+	}
+
 	if buf.Len() == 0 {
 		return nil
 	}
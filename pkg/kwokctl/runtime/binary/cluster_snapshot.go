@@ -18,6 +18,8 @@ package binary
 
 import (
 	"context"
+	"fmt"
+	"io"
 
 	"sigs.k8s.io/kwok/pkg/consts"
 	"sigs.k8s.io/kwok/pkg/kwokctl/etcd"
@@ -40,6 +42,14 @@ func (c *Cluster) SnapshotSave(ctx context.Context, path string) error {
 
 // SnapshotRestore restore the snapshot of cluster
 func (c *Cluster) SnapshotRestore(ctx context.Context, path string) error {
+	config, err := c.Config(ctx)
+	if err != nil {
+		return err
+	}
+	if config.Options.EtcdReplicas > 1 {
+		return fmt.Errorf("restoring the etcd snapshot is not supported with %d members of etcd", config.Options.EtcdReplicas)
+	}
+
 	logger := log.FromContext(ctx)
 
 	// Restart etcd and kube-apiserver
@@ -79,7 +89,7 @@ func (c *Cluster) SnapshotRestore(ctx context.Context, path string) error {
 	}()
 
 	etcdDataTmp := c.GetWorkdirPath("etcd-data")
-	err := c.RemoveAll(etcdDataTmp)
+	err = c.RemoveAll(etcdDataTmp)
 	if err != nil {
 		return err
 	}
@@ -101,6 +111,16 @@ func (c *Cluster) SnapshotRestore(ctx context.Context, path string) error {
 	return nil
 }
 
+// SnapshotSaveToWriter save the snapshot of cluster to the writer
+func (c *Cluster) SnapshotSaveToWriter(ctx context.Context, w io.Writer) error {
+	return c.Cluster.SnapshotSaveToWriter(ctx, w, c.SnapshotSave)
+}
+
+// SnapshotRestoreFromReader restore the snapshot of cluster from the reader
+func (c *Cluster) SnapshotRestoreFromReader(ctx context.Context, r io.Reader) error {
+	return c.Cluster.SnapshotRestoreFromReader(ctx, r, c.SnapshotRestore)
+}
+
 // SnapshotSaveWithYAML save the snapshot of cluster
 func (c *Cluster) SnapshotSaveWithYAML(ctx context.Context, path string, conf runtime.SnapshotSaveWithYAMLConfig) error {
 	err := c.Cluster.SnapshotSaveWithYAML(ctx, path, conf)
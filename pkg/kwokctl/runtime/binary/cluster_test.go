@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binary
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+)
+
+func TestCollectLogsPartialFailure(t *testing.T) {
+	ctx := context.Background()
+	workdir := t.TempDir()
+
+	rt, err := NewCluster("kwok-test", workdir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := rt.(*Cluster)
+
+	err = c.SetConfig(ctx, &internalversion.KwokctlConfiguration{
+		Components: []internalversion.Component{
+			{Name: "etcd"},
+			{Name: "kwok-controller"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.Save(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the log of etcd exists, the collection of kwok-controller will fail.
+	err = os.MkdirAll(filepath.Dir(c.GetLogPath("etcd.log")), 0750)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(c.GetLogPath("etcd.log"), []byte("etcd log"), 0640)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "logs")
+	err = c.CollectLogs(ctx, dir)
+	if err == nil {
+		t.Fatal("want error for the failing component, got nil")
+	}
+	if !strings.Contains(err.Error(), "kwok-controller") {
+		t.Errorf("want error naming kwok-controller, got %v", err)
+	}
+	if strings.Contains(err.Error(), "etcd") {
+		t.Errorf("want error not naming etcd, got %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "components", "etcd.log"))
+	if err != nil {
+		t.Fatalf("want log of etcd still collected: %v", err)
+	}
+	if string(got) != "etcd log" {
+		t.Errorf("want log of etcd %q, got %q", "etcd log", got)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, runtime.ConfigName)); err != nil {
+		t.Errorf("want config collected: %v", err)
+	}
+}
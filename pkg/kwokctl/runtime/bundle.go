@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/consts"
+	"sigs.k8s.io/kwok/pkg/kwokctl/pki"
+	"sigs.k8s.io/kwok/pkg/utils/file"
+	"sigs.k8s.io/kwok/pkg/utils/path"
+	"sigs.k8s.io/kwok/pkg/utils/sets"
+)
+
+// The layout of a cluster bundle, the files are under the BundleName directory of the tarball.
+const (
+	// BundleName is the directory of the files in the bundle, the bundle is extracted to it in the workdir on import.
+	BundleName = "bundle"
+	// BundleSnapshotName is the etcd snapshot of the cluster in the bundle.
+	BundleSnapshotName = "etcd.db"
+	// BundleWorkdirName records the workdir of the exported cluster,
+	// the extra volumes in it are moved to the workdir of the imported cluster.
+	BundleWorkdirName = "workdir"
+)
+
+// bundleFiles are the optional files in the workdir that are put into the bundle,
+// the options of the cluster are pointed to them on import.
+var bundleFiles = []string{
+	AuditPolicyName,
+	SchedulerConfigName,
+}
+
+// WriteBundle archives the config and the PKI in the workdir of a cluster and its etcd snapshot into the dest tarball.
+func WriteBundle(ctx context.Context, workdir, snapshot, dest string) error {
+	tmp, err := os.MkdirTemp("", "kwok-bundle-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.RemoveAll(tmp)
+	}()
+
+	dir := path.Join(tmp, BundleName)
+	err = file.MkdirAll(dir)
+	if err != nil {
+		return err
+	}
+
+	err = file.Copy(path.Join(workdir, ConfigName), path.Join(dir, ConfigName))
+	if err != nil {
+		return fmt.Errorf("failed to copy config: %w", err)
+	}
+	err = copyDir(path.Join(workdir, PkiName), path.Join(dir, PkiName))
+	if err != nil {
+		return fmt.Errorf("failed to copy pki: %w", err)
+	}
+	for _, name := range bundleFiles {
+		if !file.Exists(path.Join(workdir, name)) {
+			continue
+		}
+		err = file.Copy(path.Join(workdir, name), path.Join(dir, name))
+		if err != nil {
+			return err
+		}
+	}
+	err = file.Copy(snapshot, path.Join(dir, BundleSnapshotName))
+	if err != nil {
+		return fmt.Errorf("failed to copy snapshot: %w", err)
+	}
+	err = file.Write(path.Join(dir, BundleWorkdirName), []byte(workdir))
+	if err != nil {
+		return err
+	}
+
+	return file.TarGz(ctx, dir, dest)
+}
+
+// composeRuntimes are the runtimes that run the components as containers in a network
+var composeRuntimes = sets.NewSets(
+	consts.RuntimeTypeDocker,
+	consts.RuntimeTypePodman,
+	consts.RuntimeTypeNerdctl,
+	consts.RuntimeTypeLima,
+	consts.RuntimeTypeFinch,
+)
+
+// ReadBundle extracts the bundle to the workdir of the new cluster named name and prepares the config to create it,
+// the ports are reallocated and the admin cert is reissued for the same alt names as a new cluster of the name,
+// the CA is kept. It returns the path of the config and the etcd snapshot to create the cluster with.
+func ReadBundle(ctx context.Context, src, name, workdir string, used sets.Sets[uint32]) (configPath, snapshotPath string, err error) {
+	err = file.UntarGz(ctx, src, workdir)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to extract bundle: %w", err)
+	}
+	dir := path.Join(workdir, BundleName)
+
+	configPath = path.Join(dir, ConfigName)
+	snapshotPath = path.Join(dir, BundleSnapshotName)
+	if !file.Exists(configPath) || !file.Exists(snapshotPath) {
+		return "", "", fmt.Errorf("invalid bundle %q, want %s and %s in it", src, ConfigName, BundleSnapshotName)
+	}
+
+	oldWorkdir, err := os.ReadFile(path.Join(dir, BundleWorkdirName))
+	if err != nil {
+		return "", "", err
+	}
+
+	objs, err := config.Load(ctx, configPath)
+	if err != nil {
+		return "", "", err
+	}
+	err = CloneConfig(ctx, objs, strings.TrimSpace(string(oldWorkdir)), workdir, used)
+	if err != nil {
+		return "", "", err
+	}
+
+	confs := config.FilterWithType[*internalversion.KwokctlConfiguration](objs)
+	conf := &confs[0].Options
+	for name, opt := range map[string]*string{
+		AuditPolicyName:                  &conf.KubeAuditPolicy,
+		SchedulerConfigName:              &conf.KubeSchedulerConfig,
+		path.Join(PkiName, CABundleName): &conf.CABundle,
+	} {
+		if *opt != "" && file.Exists(path.Join(dir, name)) {
+			*opt = path.Join(dir, name)
+		}
+	}
+
+	err = config.Save(ctx, configPath, objs)
+	if err != nil {
+		return "", "", err
+	}
+
+	pkiPath := path.Join(workdir, PkiName)
+	err = copyDir(path.Join(dir, PkiName), pkiPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to copy pki: %w", err)
+	}
+
+	runtime := conf.Runtime
+	if r, err := DefaultRegistry.Resolve(runtime); err == nil {
+		runtime = r
+	}
+	sans := CertSANs(ctx, name, conf, composeRuntimes.Has(runtime))
+	err = pki.ReissuePki(pkiPath, sans...)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to reissue pki: %w", err)
+	}
+	return configPath, snapshotPath, nil
+}
+
+// copyDir copies the regular files in the src directory to the dest directory
+func copyDir(src, dest string) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		name, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := path.Join(dest, name)
+		err = file.MkdirAll(filepath.Dir(target))
+		if err != nil {
+			return err
+		}
+		return file.Copy(p, target)
+	})
+}
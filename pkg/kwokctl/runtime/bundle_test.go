@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/kwokctl/pki"
+	"sigs.k8s.io/kwok/pkg/utils/file"
+	"sigs.k8s.io/kwok/pkg/utils/sets"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	workdir := filepath.Join(dir, "kwok")
+	newWorkdir := filepath.Join(dir, "kwok-2")
+
+	conf := &internalversion.KwokctlConfiguration{
+		Options: internalversion.KwokctlConfigurationOptions{
+			Runtime:                       "docker",
+			KubeApiserverPort:             32766,
+			KubeAuditPolicy:               "/home/someone/audit.yaml",
+			KubeApiserverCertSANs:         []string{"kwok.example.com"},
+			KubeApiserverInClusterAddress: "apiserver.example.com",
+		},
+		Components: []internalversion.Component{
+			{Name: "etcd"},
+		},
+		ComponentsPatches: []internalversion.ComponentPatches{
+			{
+				Name: "kube-apiserver",
+				ExtraVolumes: []internalversion.Volume{
+					{HostPath: filepath.Join(workdir, "logs"), MountPath: "/logs"},
+				},
+			},
+		},
+	}
+	err := config.Save(ctx, filepath.Join(workdir, ConfigName), []config.InternalObject{conf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = file.MkdirAll(filepath.Join(workdir, PkiName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pki.GeneratePki(filepath.Join(workdir, PkiName), "old.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = file.Write(filepath.Join(workdir, AuditPolicyName), []byte("kind: Policy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot := filepath.Join(dir, "snapshot.db")
+	err = file.Write(snapshot, []byte("etcd snapshot"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bundle := filepath.Join(dir, "cluster.tar.gz")
+	err = WriteBundle(ctx, workdir, snapshot, bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configPath, snapshotPath, err := ReadBundle(ctx, bundle, "kwok-imported", newWorkdir, sets.Sets[uint32]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(snapshotPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "etcd snapshot" {
+		t.Errorf("want the snapshot kept, got %q", got)
+	}
+
+	objs, err := config.Load(ctx, configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	confs := config.FilterWithType[*internalversion.KwokctlConfiguration](objs)
+	if len(confs) != 1 {
+		t.Fatalf("want 1 config, got %d", len(confs))
+	}
+	newConf := confs[0]
+	if newConf.Options.KubeApiserverPort != 0 {
+		t.Errorf("want port reset, got %d", newConf.Options.KubeApiserverPort)
+	}
+	if len(newConf.Components) != 0 {
+		t.Errorf("want components dropped, got %v", newConf.Components)
+	}
+	if want := filepath.Join(newWorkdir, BundleName, AuditPolicyName); newConf.Options.KubeAuditPolicy != want {
+		t.Errorf("want audit policy %q, got %q", want, newConf.Options.KubeAuditPolicy)
+	}
+	if want := filepath.Join(newWorkdir, "logs"); newConf.ComponentsPatches[0].ExtraVolumes[0].HostPath != want {
+		t.Errorf("want volume %q, got %q", want, newConf.ComponentsPatches[0].ExtraVolumes[0].HostPath)
+	}
+
+	oldCA, _, err := pki.ReadCertAndKey(filepath.Join(workdir, PkiName), "ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, _, err := pki.ReadCertAndKey(filepath.Join(newWorkdir, PkiName), "ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _, err := pki.ReadCertAndKey(filepath.Join(newWorkdir, PkiName), "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ca.Equal(oldCA) {
+		t.Errorf("want CA kept")
+	}
+	if slices.Contains(cert.DNSNames, "old.example.com") || !slices.Contains(cert.DNSNames, "kwok.example.com") {
+		t.Errorf("want alt names of the config, got %v", cert.DNSNames)
+	}
+	for _, name := range []string{"kwok-imported-kube-apiserver", "kwok-imported-kwok-controller", "apiserver.example.com"} {
+		if !slices.Contains(cert.DNSNames, name) {
+			t.Errorf("want alt name %q within the network of the new cluster, got %v", name, cert.DNSNames)
+		}
+	}
+	err = cert.CheckSignatureFrom(ca)
+	if err != nil {
+		t.Errorf("want admin cert signed by CA: %v", err)
+	}
+}
+
+func TestReadBundleInvalid(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	src := filepath.Join(dir, BundleName)
+	err := file.MkdirAll(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = file.Write(filepath.Join(src, ConfigName), []byte{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle := filepath.Join(dir, "cluster.tar.gz")
+	err = file.TarGz(ctx, src, bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = ReadBundle(ctx, bundle, "kwok-kwok", filepath.Join(dir, "kwok"), sets.Sets[uint32]{})
+	if err == nil {
+		t.Errorf("want error without snapshot in the bundle")
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/utils/net"
+	"sigs.k8s.io/kwok/pkg/utils/sets"
+)
+
+// CloneConfig turns the stored config of a cluster into the config to create a new cluster,
+// the components and the ports are dropped so that they are set up again by the create.
+func CloneConfig(ctx context.Context, objs []config.InternalObject, workdir, newWorkdir string, used sets.Sets[uint32]) error {
+	confs := config.FilterWithType[*internalversion.KwokctlConfiguration](objs)
+	if len(confs) == 0 {
+		return fmt.Errorf("failed to load config")
+	}
+
+	for _, conf := range confs {
+		conf.Components = nil
+		conf.Status = internalversion.KwokctlConfigurationStatus{}
+
+		err := clonePorts(ctx, &conf.Options, used)
+		if err != nil {
+			return err
+		}
+
+		for i := range conf.ComponentsPatches {
+			volumes := conf.ComponentsPatches[i].ExtraVolumes
+			for j := range volumes {
+				hostPath := volumes[j].HostPath
+				if hostPath == workdir || strings.HasPrefix(hostPath, workdir+"/") {
+					volumes[j].HostPath = newWorkdir + strings.TrimPrefix(hostPath, workdir)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// clonePorts resets the ports that are allocated by the create if they are zero,
+// and reallocates the ports of the optional components that are enabled by a non-zero port.
+func clonePorts(ctx context.Context, opts *internalversion.KwokctlConfigurationOptions, used sets.Sets[uint32]) error {
+	for _, port := range []*uint32{
+		&opts.KubeApiserverPort,
+		&opts.EtcdPeerPort,
+		&opts.EtcdPort,
+		&opts.KubeControllerManagerPort,
+		&opts.KubeSchedulerPort,
+		&opts.KwokControllerPort,
+		&opts.MetricsServerPort,
+		&opts.JaegerOtlpGrpcPort,
+	} {
+		*port = 0
+	}
+
+	for _, port := range []*uint32{
+		&opts.KubeApiserverInsecurePort,
+		&opts.KubeApiserverReadOnlyPort,
+		&opts.PrometheusPort,
+		&opts.JaegerPort,
+		&opts.DashboardPort,
+		&opts.KwokControllerProfilingPort,
+	} {
+		if *port == 0 {
+			continue
+		}
+		p, err := net.GetUnusedPort(ctx, used)
+		if err != nil {
+			return err
+		}
+		*port = p
+	}
+	return nil
+}
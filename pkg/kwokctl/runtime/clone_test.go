@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/config"
+	"sigs.k8s.io/kwok/pkg/utils/sets"
+)
+
+func TestCloneConfig(t *testing.T) {
+	conf := &internalversion.KwokctlConfiguration{
+		Options: internalversion.KwokctlConfigurationOptions{
+			Runtime:           "binary",
+			KubeVersion:       "v1.30.0",
+			KubeApiserverPort: 32766,
+			EtcdPort:          32765,
+			EtcdPeerPort:      32764,
+			PrometheusPort:    9090,
+		},
+		Components: []internalversion.Component{
+			{Name: "etcd"},
+		},
+		ComponentsPatches: []internalversion.ComponentPatches{
+			{
+				Name: "kube-apiserver",
+				ExtraVolumes: []internalversion.Volume{
+					{HostPath: "/root/.kwok/clusters/kwok/audit", MountPath: "/audit"},
+					{HostPath: "/etc/kubernetes", MountPath: "/etc/kubernetes"},
+					{HostPath: "/root/.kwok/clusters/kwok-3/audit", MountPath: "/other"},
+				},
+			},
+		},
+		Status: internalversion.KwokctlConfigurationStatus{
+			Version: "v0.6.0",
+		},
+	}
+	stage := &internalversion.Stage{}
+	objs := []config.InternalObject{conf, stage}
+
+	used := sets.Sets[uint32]{}
+	used.Insert(9090)
+	err := CloneConfig(context.Background(), objs, "/root/.kwok/clusters/kwok", "/root/.kwok/clusters/kwok-2", used)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(objs) != 2 || objs[1] != stage {
+		t.Errorf("want the other objects kept, got %v", objs)
+	}
+	if conf.Components != nil {
+		t.Errorf("want components dropped, got %v", conf.Components)
+	}
+	if conf.Status.Version != "" {
+		t.Errorf("want status dropped, got %v", conf.Status)
+	}
+	if conf.Options.Runtime != "binary" || conf.Options.KubeVersion != "v1.30.0" {
+		t.Errorf("want options kept, got %+v", conf.Options)
+	}
+	if conf.Options.KubeApiserverPort != 0 || conf.Options.EtcdPort != 0 || conf.Options.EtcdPeerPort != 0 {
+		t.Errorf("want ports reset, got %d, %d, %d", conf.Options.KubeApiserverPort, conf.Options.EtcdPort, conf.Options.EtcdPeerPort)
+	}
+	if conf.Options.PrometheusPort == 0 || conf.Options.PrometheusPort == 9090 {
+		t.Errorf("want prometheus port reallocated, got %d", conf.Options.PrometheusPort)
+	}
+	if conf.Options.JaegerPort != 0 {
+		t.Errorf("want disabled jaeger kept disabled, got %d", conf.Options.JaegerPort)
+	}
+
+	volumes := conf.ComponentsPatches[0].ExtraVolumes
+	if volumes[0].HostPath != "/root/.kwok/clusters/kwok-2/audit" {
+		t.Errorf("want the volume in the workdir moved, got %q", volumes[0].HostPath)
+	}
+	if volumes[1].HostPath != "/etc/kubernetes" {
+		t.Errorf("want the volume out of the workdir kept, got %q", volumes[1].HostPath)
+	}
+	if volumes[2].HostPath != "/root/.kwok/clusters/kwok-3/audit" {
+		t.Errorf("want the volume of another cluster kept, got %q", volumes[2].HostPath)
+	}
+}
+
+func TestCloneConfigWithoutConfig(t *testing.T) {
+	err := CloneConfig(context.Background(), []config.InternalObject{&internalversion.Stage{}}, "a", "b", sets.Sets[uint32]{})
+	if err == nil {
+		t.Errorf("want error without kwokctl configuration")
+	}
+}
@@ -21,11 +21,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/nxadm/tail"
+	"k8s.io/client-go/kubernetes"
 
 	"sigs.k8s.io/kwok/kustomize/crd"
 	nodefast "sigs.k8s.io/kwok/kustomize/stage/node/fast"
@@ -53,6 +56,7 @@ var (
 	ConfigName              = consts.ConfigName
 	InHostKubeconfigName    = "kubeconfig.yaml"
 	InClusterKubeconfigName = "kubeconfig"
+	KubeApiserverSocketName = "kube-apiserver.sock"
 	EtcdDataDirName         = "etcd"
 	PkiName                 = "pki"
 	ManifestsName           = "manifests"
@@ -60,6 +64,8 @@ var (
 	KindName                = "kind.yaml"
 	AuditPolicyName         = "audit.yaml"
 	AuditLogName            = "audit.log"
+	AuditWebhookConfigName  = "audit-webhook.yaml"
+	CABundleName            = "ca-bundle.crt"
 	SchedulerConfigName     = "scheduler.yaml"
 	ApiserverTracingConfig  = "apiserver-tracing-config.yaml"
 )
@@ -72,6 +78,9 @@ type Cluster struct {
 	conf    *internalversion.KwokctlConfiguration
 
 	clientset client.Clientset
+
+	clientsMut sync.Mutex
+	clients    *Clients
 }
 
 // NewCluster creates a new cluster
@@ -334,10 +343,48 @@ func (c *Cluster) Install(ctx context.Context) error {
 
 // Uninstall uninstalls the cluster.
 func (c *Cluster) Uninstall(ctx context.Context) error {
+	if isPreserveWorkdir(ctx) {
+		logger := log.FromContext(ctx)
+		logger.Info("Preserve workdir", "workdir", c.Workdir())
+		return nil
+	}
+
 	// cleanup workdir
 	return c.RemoveAll(c.Workdir())
 }
 
+type preserveWorkdirCtx int
+
+// WithPreserveWorkdir returns a context that makes Uninstall keep the workdir of the cluster,
+// the etcd data, logs and config are left intact for inspection.
+func WithPreserveWorkdir(ctx context.Context) context.Context {
+	return context.WithValue(ctx, preserveWorkdirCtx(0), true)
+}
+
+func isPreserveWorkdir(ctx context.Context) bool {
+	v, _ := ctx.Value(preserveWorkdirCtx(0)).(bool)
+	return v
+}
+
+// LogsOptions is the options of the logs of a component
+type LogsOptions struct {
+	// Timestamps prepends an RFC3339 timestamp to each line, where the runtime supports it
+	Timestamps bool
+}
+
+// LogsArgs returns the arguments of the logs command of the container runtime or kubectl,
+// the prefix is the arguments before the flags, e.g. logs -n kube-system.
+func LogsArgs(prefix []string, target string, follow bool, opts LogsOptions) []string {
+	args := append([]string{}, prefix...)
+	if follow {
+		args = append(args, "-f")
+	}
+	if opts.Timestamps {
+		args = append(args, "--timestamps")
+	}
+	return append(args, target)
+}
+
 // Ready returns true if the cluster is ready
 func (c *Cluster) Ready(ctx context.Context) (bool, error) {
 	out := bytes.NewBuffer(nil)
@@ -357,6 +404,34 @@ func (c *Cluster) Ready(ctx context.Context) (bool, error) {
 	return true, nil
 }
 
+// KubeApiserverReadyz returns true if the /readyz of the kube-apiserver responds with 200.
+// It returns false if the clients cannot be created yet, e.g. before the kubeconfig is written,
+// so that the callers polling it keep retrying.
+func (c *Cluster) KubeApiserverReadyz(ctx context.Context) bool {
+	logger := log.FromContext(ctx)
+	clients, err := c.GetClients(ctx)
+	if err != nil {
+		logger.Debug("Check Ready",
+			"path", "/readyz",
+			"err", err,
+		)
+		return false
+	}
+
+	var statusCode int
+	err = clients.Kubernetes.Discovery().RESTClient().Get().AbsPath("/readyz").Do(ctx).StatusCode(&statusCode).Error()
+	if statusCode != http.StatusOK {
+		logger.Debug("Check Ready",
+			"method", "get",
+			"path", "/readyz",
+			"statusCode", statusCode,
+			"err", err,
+		)
+		return false
+	}
+	return true
+}
+
 // WaitReady waits for the cluster to be ready.
 func (c *Cluster) WaitReady(ctx context.Context, timeout time.Duration) error {
 	var (
@@ -543,6 +618,43 @@ func (c *Cluster) GetClientset(ctx context.Context) (client.Clientset, error) {
 	return clientset, nil
 }
 
+// GetClients returns the typed and dynamic clients of the cluster.
+// The clients are created from the kubeconfig in the workdir on the first call and are reused after that,
+// it is safe to be called concurrently.
+func (c *Cluster) GetClients(ctx context.Context) (*Clients, error) {
+	c.clientsMut.Lock()
+	defer c.clientsMut.Unlock()
+	if c.clients != nil {
+		return c.clients, nil
+	}
+
+	// A clientset of its own is used here,
+	// because the one of GetClientset is not safe to be shared between goroutines.
+	kubeconfigPath := c.GetWorkdirPath(InHostKubeconfigName)
+	clientset, err := client.NewClientset("", kubeconfigPath)
+	if err != nil {
+		return nil, err
+	}
+	restConfig, err := clientset.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	typedClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not get Kubernetes typedClient: %w", err)
+	}
+	dynamicClient, err := clientset.ToDynamicClient()
+	if err != nil {
+		return nil, err
+	}
+
+	c.clients = &Clients{
+		Kubernetes: typedClient,
+		Dynamic:    dynamicClient,
+	}
+	return c.clients, nil
+}
+
 // IsDryRun returns true if the runtime is in dry-run mode
 func (c *Cluster) IsDryRun() bool {
 	return c.dryRun
@@ -18,11 +18,16 @@ package runtime
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"os"
 	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
 
 	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
+	"sigs.k8s.io/kwok/pkg/kwokctl/recording"
 	"sigs.k8s.io/kwok/pkg/kwokctl/snapshot"
 	"sigs.k8s.io/kwok/pkg/log"
 	"sigs.k8s.io/kwok/pkg/utils/client"
@@ -32,7 +37,15 @@ import (
 // SnapshotSaveWithYAML save the snapshot of cluster
 func (c *Cluster) SnapshotSaveWithYAML(ctx context.Context, path string, conf SnapshotSaveWithYAMLConfig) error {
 	if c.IsDryRun() {
-		dryrun.PrintMessage("kubectl get %s -o yaml >%s", strings.Join(conf.Filters, ","), path)
+		output := "yaml"
+		if conf.JSONLines {
+			output = "json"
+		}
+		if conf.Watch {
+			dryrun.PrintMessage("kubectl get %s -o %s --watch >%s", strings.Join(conf.Filters, ","), output, path)
+		} else {
+			dryrun.PrintMessage("kubectl get %s -o %s >%s", strings.Join(conf.Filters, ","), output, path)
+		}
 		return nil
 	}
 
@@ -63,21 +76,55 @@ func (c *Cluster) SnapshotSaveWithYAML(ctx context.Context, path string, conf Sn
 		_ = f.Close()
 	}()
 
+	var anonymizer *snapshot.Anonymizer
+	if conf.Anonymize {
+		anonymizer, err = snapshot.NewAnonymizer(conf.AnonymizeConfig)
+		if err != nil {
+			return err
+		}
+	}
+
 	saver, err := snapshot.NewSaver(snapshot.SaveConfig{
-		Clientset: clientset,
-		Filters:   filters,
+		Clientset:  clientset,
+		Filters:    filters,
+		Anonymizer: anonymizer,
 	})
 	if err != nil {
 		return err
 	}
 
-	encoder := yaml.NewEncoder(f)
+	var writer io.Writer = f
+	var tracks map[*meta.RESTMapping]*snapshot.TrackData
+	if conf.Watch {
+		startTime := time.Now()
+		writer = recording.NewWriteHook(writer, func(b []byte) []byte {
+			return recording.ReplaceTimeToRelative(startTime, b)
+		})
+		tracks = make(map[*meta.RESTMapping]*snapshot.TrackData)
+	}
+
+	var encoder snapshot.Encoder
+	if conf.JSONLines {
+		encoder = json.NewEncoder(writer)
+	} else {
+		encoder = yaml.NewEncoder(writer)
+	}
 
-	err = saver.Save(ctx, encoder, nil)
+	err = saver.Save(ctx, encoder, tracks)
 	if err != nil {
 		return err
 	}
 
+	if conf.Watch {
+		logger.Info("Watching")
+		logger.Info("Press Ctrl+C to stop watching resources")
+
+		err = saver.Record(ctx, encoder, tracks)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -85,7 +132,10 @@ func (c *Cluster) SnapshotSaveWithYAML(ctx context.Context, path string, conf Sn
 func (c *Cluster) SnapshotRestoreWithYAML(ctx context.Context, path string, conf SnapshotRestoreWithYAMLConfig) error {
 	if c.IsDryRun() {
 		dryrun.PrintMessage("kubectl create -f %s", path)
-		return nil
+		// The resources to delete are found by comparing the cluster with the snapshot
+		if !conf.Prune {
+			return nil
+		}
 	}
 
 	clientset, err := c.GetClientset(ctx)
@@ -116,9 +166,12 @@ func (c *Cluster) SnapshotRestoreWithYAML(ctx context.Context, path string, conf
 	}
 
 	loader, err := snapshot.NewLoader(snapshot.LoadConfig{
-		Clientset: clientset,
-		NoFilers:  len(filters) == 0,
-		Filters:   filters,
+		Clientset:        clientset,
+		NoFilers:         len(filters) == 0,
+		Filters:          filters,
+		ConflictStrategy: conf.ConflictStrategy,
+		Prune:            conf.Prune,
+		DryRun:           c.IsDryRun(),
 	})
 	if err != nil {
 		return err
@@ -134,3 +187,107 @@ func (c *Cluster) SnapshotRestoreWithYAML(ctx context.Context, path string, conf
 
 	return nil
 }
+
+// snapshotStagingPattern is the pattern of the file in the workdir to stage the etcd snapshot,
+// etcdctl only saves and restores the snapshot from a file.
+const snapshotStagingPattern = "snapshot-*.db"
+
+// createSnapshotStaging creates an empty staging file for the etcd snapshot in the workdir,
+// in dry-run it only prints the command to create it.
+func (c *Cluster) createSnapshotStaging() (string, error) {
+	if c.IsDryRun() {
+		dryrun.PrintMessage("SNAPSHOT=$(mktemp %s)", c.GetWorkdirPath(strings.ReplaceAll(snapshotStagingPattern, "*", "XXXXXX")))
+		return "${SNAPSHOT}", nil
+	}
+	f, err := os.CreateTemp(c.Workdir(), snapshotStagingPattern)
+	if err != nil {
+		return "", err
+	}
+	err = f.Close()
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// removeSnapshotStaging removes the staging file of the etcd snapshot.
+func (c *Cluster) removeSnapshotStaging(staging string) {
+	if c.IsDryRun() {
+		dryrun.PrintMessage("rm -f %s", staging)
+		return
+	}
+	_ = os.Remove(staging)
+}
+
+// SnapshotSaveToWriter saves the etcd snapshot of the cluster with save and streams it to the writer,
+// the snapshot is staged in a temporary file in the workdir and removed after streaming.
+func (c *Cluster) SnapshotSaveToWriter(ctx context.Context, w io.Writer, save func(ctx context.Context, path string) error) error {
+	staging, err := c.createSnapshotStaging()
+	if err != nil {
+		return err
+	}
+	defer c.removeSnapshotStaging(staging)
+
+	err = save(ctx, staging)
+	if err != nil {
+		return err
+	}
+
+	if c.IsDryRun() {
+		if command, ok := dryrun.IsPipeToCommandWriter(w); ok {
+			dryrun.PrintMessage("cat %s | %s", staging, command)
+		} else {
+			dryrun.PrintMessage("cat %s", staging)
+		}
+		return nil
+	}
+
+	f, err := os.Open(staging)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	_, err = io.Copy(w, f)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SnapshotRestoreFromReader restores the etcd snapshot of the cluster streamed from the reader with restore,
+// the snapshot is staged in a temporary file in the workdir and removed after restoring.
+func (c *Cluster) SnapshotRestoreFromReader(ctx context.Context, r io.Reader, restore func(ctx context.Context, path string) error) error {
+	staging, err := c.createSnapshotStaging()
+	if err != nil {
+		return err
+	}
+	defer c.removeSnapshotStaging(staging)
+
+	if c.IsDryRun() {
+		if command, ok := dryrun.IsPipeFromCommandReader(r); ok {
+			dryrun.PrintMessage("%s >%s", command, staging)
+		} else {
+			dryrun.PrintMessage("cat >%s", staging)
+		}
+	} else {
+		f, err := os.OpenFile(staging, os.O_TRUNC|os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, r)
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = restore(ctx, staging)
+	if err != nil {
+		return err
+	}
+	return nil
+}
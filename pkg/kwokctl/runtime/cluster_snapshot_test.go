@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func assertNoSnapshotStaging(t *testing.T, workdir string) {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(workdir, snapshotStagingPattern))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("want the staging files to be removed, got %v", matches)
+	}
+}
+
+func TestClusterSnapshotSaveToWriter(t *testing.T) {
+	errSave := errors.New("save failed")
+	tests := []struct {
+		name    string
+		saveErr error
+		want    string
+	}{
+		{
+			name: "stream",
+			want: "snapshot data",
+		},
+		{
+			name:    "save failed",
+			saveErr: errSave,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workdir := t.TempDir()
+			c := NewCluster("test", workdir)
+
+			var saved string
+			save := func(_ context.Context, path string) error {
+				saved = path
+				if tt.saveErr != nil {
+					return tt.saveErr
+				}
+				return os.WriteFile(path, []byte(tt.want), 0640)
+			}
+
+			var buf bytes.Buffer
+			err := c.SnapshotSaveToWriter(context.Background(), &buf, save)
+			if !errors.Is(err, tt.saveErr) {
+				t.Fatalf("SnapshotSaveToWriter() error = %v, want %v", err, tt.saveErr)
+			}
+			if filepath.Dir(saved) != workdir {
+				t.Errorf("got snapshot staged in %q, want in %q", saved, workdir)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("SnapshotSaveToWriter() wrote %q, want %q", got, tt.want)
+			}
+			assertNoSnapshotStaging(t, workdir)
+		})
+	}
+}
+
+func TestClusterSnapshotRestoreFromReader(t *testing.T) {
+	errRestore := errors.New("restore failed")
+	tests := []struct {
+		name       string
+		restoreErr error
+	}{
+		{
+			name: "stream",
+		},
+		{
+			name:       "restore failed",
+			restoreErr: errRestore,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workdir := t.TempDir()
+			c := NewCluster("test", workdir)
+
+			want := "snapshot data"
+			var got string
+			restore := func(_ context.Context, path string) error {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				got = string(data)
+				return tt.restoreErr
+			}
+
+			err := c.SnapshotRestoreFromReader(context.Background(), strings.NewReader(want), restore)
+			if !errors.Is(err, tt.restoreErr) {
+				t.Fatalf("SnapshotRestoreFromReader() error = %v, want %v", err, tt.restoreErr)
+			}
+			if got != want {
+				t.Errorf("restored %q, want %q", got, want)
+			}
+			assertNoSnapshotStaging(t, workdir)
+		})
+	}
+}
+
+func TestClusterSnapshotSaveToWriterConcurrently(t *testing.T) {
+	workdir := t.TempDir()
+	c := NewCluster("test", workdir)
+
+	// The staging file of one save must not be touched by another save in the same workdir.
+	inner := make(chan error, 1)
+	save := func(ctx context.Context, path string) error {
+		err := os.WriteFile(path, []byte("outer"), 0640)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		inner <- c.SnapshotSaveToWriter(ctx, &buf, func(_ context.Context, path string) error {
+			return os.WriteFile(path, []byte("inner"), 0640)
+		})
+		return nil
+	}
+
+	var buf bytes.Buffer
+	err := c.SnapshotSaveToWriter(context.Background(), &buf, save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-inner; err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "outer" {
+		t.Errorf("SnapshotSaveToWriter() wrote %q, want %q", got, "outer")
+	}
+	assertNoSnapshotStaging(t, workdir)
+}
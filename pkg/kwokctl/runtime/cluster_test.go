@@ -0,0 +1,261 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/kwok/pkg/utils/path"
+)
+
+func TestClusterGetClients(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/default" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"default"}}`))
+	}))
+	defer server.Close()
+
+	workdir := t.TempDir()
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: kwok
+  cluster:
+    server: %s
+contexts:
+- name: kwok
+  context:
+    cluster: kwok
+current-context: kwok
+`, server.URL)
+	err := os.WriteFile(path.Join(workdir, InHostKubeconfigName), []byte(kubeconfig), 0640)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	c := NewCluster("kwok", workdir)
+
+	var wg sync.WaitGroup
+	got := make([]*Clients, 4)
+	for i := range got {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clients, err := c.GetClients(ctx)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			got[i] = clients
+		}(i)
+	}
+	wg.Wait()
+	if t.Failed() {
+		return
+	}
+	for _, clients := range got[1:] {
+		if clients != got[0] {
+			t.Fatal("want the clients to be shared between the calls")
+		}
+	}
+
+	clients := got[0]
+	ns, err := clients.Kubernetes.CoreV1().Namespaces().Get(ctx, "default", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ns.Name != "default" {
+		t.Errorf("want namespace default, got %q", ns.Name)
+	}
+
+	obj, err := clients.Dynamic.Resource(schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}).
+		Get(ctx, "default", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.GetName() != "default" {
+		t.Errorf("want namespace default, got %q", obj.GetName())
+	}
+}
+
+func TestClusterKubeApiserverReadyz(t *testing.T) {
+	ctx := context.Background()
+	workdir := t.TempDir()
+	c := NewCluster("kwok", workdir)
+
+	if c.KubeApiserverReadyz(ctx) {
+		t.Fatal("want not ready without kubeconfig")
+	}
+
+	var ready atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/readyz" {
+			http.NotFound(w, r)
+			return
+		}
+		if !ready.Load() {
+			http.Error(w, "[-]etcd failed", http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: kwok
+  cluster:
+    server: %s
+contexts:
+- name: kwok
+  context:
+    cluster: kwok
+current-context: kwok
+`, server.URL)
+	err := os.WriteFile(path.Join(workdir, InHostKubeconfigName), []byte(kubeconfig), 0640)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.KubeApiserverReadyz(ctx) {
+		t.Fatal("want not ready while /readyz fails")
+	}
+	ready.Store(true)
+	if !c.KubeApiserverReadyz(ctx) {
+		t.Fatal("want ready once /readyz is ok")
+	}
+}
+
+func TestClusterUninstall(t *testing.T) {
+	tests := []struct {
+		name            string
+		preserveWorkdir bool
+		wantExist       bool
+	}{
+		{
+			name:      "remove workdir",
+			wantExist: false,
+		},
+		{
+			name:            "preserve workdir",
+			preserveWorkdir: true,
+			wantExist:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workdir := filepath.Join(t.TempDir(), "kwok")
+			err := os.MkdirAll(filepath.Join(workdir, "etcd"), 0750)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.Background()
+			if tt.preserveWorkdir {
+				ctx = WithPreserveWorkdir(ctx)
+			}
+			c := NewCluster("kwok", workdir)
+			err = c.Uninstall(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = os.Stat(filepath.Join(workdir, "etcd"))
+			if exist := err == nil; exist != tt.wantExist {
+				t.Errorf("workdir exists = %v, want %v", exist, tt.wantExist)
+			}
+		})
+	}
+}
+
+func TestLogsArgs(t *testing.T) {
+	composePrefix := []string{"logs"}
+	kindPrefix := []string{"logs", "-n", "kube-system"}
+
+	tests := []struct {
+		name   string
+		prefix []string
+		target string
+		follow bool
+		opts   LogsOptions
+		want   []string
+	}{
+		{
+			name:   "compose logs",
+			prefix: composePrefix,
+			target: "kwok-test-kube-apiserver",
+			want:   []string{"logs", "kwok-test-kube-apiserver"},
+		},
+		{
+			name:   "compose follow with timestamps",
+			prefix: composePrefix,
+			target: "kwok-test-kube-apiserver",
+			follow: true,
+			opts:   LogsOptions{Timestamps: true},
+			want:   []string{"logs", "-f", "--timestamps", "kwok-test-kube-apiserver"},
+		},
+		{
+			name:   "kind logs",
+			prefix: kindPrefix,
+			target: "kube-apiserver-kwok-test-control-plane",
+			want:   []string{"logs", "-n", "kube-system", "kube-apiserver-kwok-test-control-plane"},
+		},
+		{
+			name:   "kind follow",
+			prefix: kindPrefix,
+			target: "kube-apiserver-kwok-test-control-plane",
+			follow: true,
+			want:   []string{"logs", "-n", "kube-system", "-f", "kube-apiserver-kwok-test-control-plane"},
+		},
+		{
+			name:   "kind timestamps",
+			prefix: kindPrefix,
+			target: "kube-apiserver-kwok-test-control-plane",
+			opts:   LogsOptions{Timestamps: true},
+			want:   []string{"logs", "-n", "kube-system", "--timestamps", "kube-apiserver-kwok-test-control-plane"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LogsArgs(tt.prefix, tt.target, tt.follow, tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LogsArgs() got = %q, want %q", got, tt.want)
+			}
+			if len(tt.prefix) != 0 && &got[0] == &tt.prefix[0] {
+				t.Errorf("LogsArgs() must not share the prefix")
+			}
+		})
+	}
+}
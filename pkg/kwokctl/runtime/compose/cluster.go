@@ -19,6 +19,7 @@ package compose
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -107,21 +108,8 @@ func (c *Cluster) Available(ctx context.Context) error {
 func (c *Cluster) setup(ctx context.Context, env *env) error {
 	conf := &env.kwokctlConfig.Options
 	if !file.Exists(env.pkiPath) {
-		sans := []string{
-			c.Name() + "-kube-apiserver",
-			c.Name() + "-kwok-controller",
-		}
-		ips, err := net.GetAllIPs()
-		if err != nil {
-			logger := log.FromContext(ctx)
-			logger.Warn("failed to get all ips", "err", err)
-		} else {
-			sans = append(sans, ips...)
-		}
-		if len(conf.KubeApiserverCertSANs) != 0 {
-			sans = append(sans, conf.KubeApiserverCertSANs...)
-		}
-		err = c.MkdirAll(env.pkiPath)
+		sans := runtime.CertSANs(ctx, c.Name(), conf, true)
+		err := c.MkdirAll(env.pkiPath)
 		if err != nil {
 			return fmt.Errorf("failed to create pki dir: %w", err)
 		}
@@ -146,6 +134,24 @@ func (c *Cluster) setup(ctx context.Context, env *env) error {
 		if err != nil {
 			return err
 		}
+
+		if conf.KubeAuditWebhook != "" {
+			auditWebhookConfig, err := runtime.BuildAuditWebhookConfig(conf.KubeAuditWebhook)
+			if err != nil {
+				return fmt.Errorf("failed to build audit webhook config: %w", err)
+			}
+			err = c.WriteFile(env.auditWebhookPath, auditWebhookConfig)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if conf.CABundle != "" {
+		err := c.CopyFile(conf.CABundle, env.caBundlePath)
+		if err != nil {
+			return err
+		}
 	}
 
 	err := c.MkdirAll(env.etcdDataPath)
@@ -176,10 +182,13 @@ type env struct {
 	inClusterKubeconfig           string
 	kubeconfigPath                string
 	etcdDataPath                  string
+	etcdMembers                   []components.EtcdMember
 	kwokConfigPath                string
 	pkiPath                       string
 	auditLogPath                  string
 	auditPolicyPath               string
+	auditWebhookPath              string
+	caBundlePath                  string
 	workdir                       string
 	caCertPath                    string
 	adminKeyPath                  string
@@ -189,6 +198,7 @@ type env struct {
 	inClusterAdminKeyPath         string
 	inClusterAdminCertPath        string
 	inClusterPort                 uint32
+	inClusterAddress              string
 	scheme                        string
 	usedPorts                     sets.Sets[uint32]
 }
@@ -207,9 +217,17 @@ func (c *Cluster) env(ctx context.Context) (*env, error) {
 	pkiPath := c.GetWorkdirPath(runtime.PkiName)
 	auditLogPath := ""
 	auditPolicyPath := ""
+	auditWebhookPath := ""
 	if config.Options.KubeAuditPolicy != "" {
 		auditLogPath = c.GetLogPath(runtime.AuditLogName)
 		auditPolicyPath = c.GetWorkdirPath(runtime.AuditPolicyName)
+		if config.Options.KubeAuditWebhook != "" {
+			auditWebhookPath = c.GetWorkdirPath(runtime.AuditWebhookConfigName)
+		}
+	}
+	caBundlePath := ""
+	if config.Options.CABundle != "" {
+		caBundlePath = path.Join(pkiPath, runtime.CABundleName)
 	}
 
 	workdir := c.Workdir()
@@ -228,6 +246,11 @@ func (c *Cluster) env(ctx context.Context) (*env, error) {
 		inClusterPort = 6443
 	}
 
+	inClusterAddress := c.Name() + "-kube-apiserver"
+	if config.Options.KubeApiserverInClusterAddress != "" {
+		inClusterAddress = config.Options.KubeApiserverInClusterAddress
+	}
+
 	logger := log.FromContext(ctx)
 	verbosity := logger.Level()
 
@@ -244,6 +267,8 @@ func (c *Cluster) env(ctx context.Context) (*env, error) {
 		pkiPath:                       pkiPath,
 		auditLogPath:                  auditLogPath,
 		auditPolicyPath:               auditPolicyPath,
+		auditWebhookPath:              auditWebhookPath,
+		caBundlePath:                  caBundlePath,
 		workdir:                       workdir,
 		caCertPath:                    caCertPath,
 		adminKeyPath:                  adminKeyPath,
@@ -253,6 +278,7 @@ func (c *Cluster) env(ctx context.Context) (*env, error) {
 		inClusterAdminKeyPath:         inClusterAdminKeyPath,
 		inClusterAdminCertPath:        inClusterAdminCertPath,
 		inClusterPort:                 inClusterPort,
+		inClusterAddress:              inClusterAddress,
 		scheme:                        scheme,
 		usedPorts:                     usedPorts,
 	}, nil
@@ -364,22 +390,48 @@ func (c *Cluster) addEtcd(ctx context.Context, env *env) (err error) {
 		return err
 	}
 
-	etcdComponent, err := components.BuildEtcdComponent(components.BuildEtcdComponentConfig{
-		Runtime:          conf.Runtime,
-		ProjectName:      c.Name(),
-		Workdir:          env.workdir,
-		Image:            conf.EtcdImage,
-		Version:          etcdVersion,
-		BindAddress:      net.PublicAddress,
-		Port:             conf.EtcdPort,
-		DataPath:         env.etcdDataPath,
-		Verbosity:        env.verbosity,
-		QuotaBackendSize: conf.EtcdQuotaBackendSize,
-	})
-	if err != nil {
-		return err
+	if conf.EtcdReplicas > 1 {
+		for i := 0; i < int(conf.EtcdReplicas); i++ {
+			name := components.EtcdComponentName(i)
+			env.etcdMembers = append(env.etcdMembers, components.EtcdMember{
+				ComponentName: name,
+				Address:       c.Name() + "-" + name,
+				Port:          2379,
+				PeerPort:      2380,
+			})
+		}
+	}
+
+	replicas := max(int(conf.EtcdReplicas), 1)
+	for i := 0; i < replicas; i++ {
+		// Only the first member is exposed to the host
+		port, extraClientURLs := conf.EtcdPort, conf.EtcdExtraClientURLs
+		if i != 0 {
+			port, extraClientURLs = 0, nil
+		}
+		etcdComponent, err := components.BuildEtcdComponent(components.BuildEtcdComponentConfig{
+			Runtime:           conf.Runtime,
+			ProjectName:       c.Name(),
+			Workdir:           env.workdir,
+			Image:             conf.EtcdImage,
+			Version:           etcdVersion,
+			BindAddress:       net.PublicAddress,
+			Port:              port,
+			DataPath:          env.etcdDataPath,
+			Verbosity:         env.verbosity,
+			QuotaBackendSize:  conf.EtcdQuotaBackendSize,
+			UnsafeNoFsync:     conf.EtcdUnsafeNoFsync,
+			HeartbeatInterval: conf.EtcdHeartbeatInterval,
+			ElectionTimeout:   conf.EtcdElectionTimeout,
+			ExtraClientURLs:   extraClientURLs,
+			Index:             i,
+			Members:           env.etcdMembers,
+		})
+		if err != nil {
+			return err
+		}
+		env.kwokctlConfig.Components = append(env.kwokctlConfig.Components, etcdComponent)
 	}
-	env.kwokctlConfig.Components = append(env.kwokctlConfig.Components, etcdComponent)
 	return nil
 }
 
@@ -396,6 +448,11 @@ func (c *Cluster) addKubeApiserver(ctx context.Context, env *env) (err error) {
 		return err
 	}
 
+	if conf.KubeAuditLogMaxSize > 0 {
+		logger := log.FromContext(ctx)
+		logger.Warn("kubeAuditLogMaxSize config is not supported in compose runtime, ignored")
+	}
+
 	kubeApiserverTracingConfigPath := ""
 	if conf.JaegerPort != 0 {
 		kubeApiserverTracingConfigData, err := k8s.BuildKubeApiserverTracingConfig(k8s.BuildKubeApiserverTracingConfigParam{
@@ -413,29 +470,43 @@ func (c *Cluster) addKubeApiserver(ctx context.Context, env *env) (err error) {
 	}
 
 	kubeApiserverComponent, err := components.BuildKubeApiserverComponent(components.BuildKubeApiserverComponentConfig{
-		Runtime:           conf.Runtime,
-		ProjectName:       c.Name(),
-		Workdir:           env.workdir,
-		Image:             conf.KubeApiserverImage,
-		Version:           kubeApiserverVersion,
-		BindAddress:       net.PublicAddress,
-		Port:              conf.KubeApiserverPort,
-		KubeRuntimeConfig: conf.KubeRuntimeConfig,
-		KubeFeatureGates:  conf.KubeFeatureGates,
-		SecurePort:        conf.SecurePort,
-		KubeAuthorization: conf.KubeAuthorization,
-		KubeAdmission:     conf.KubeAdmission,
-		AuditPolicyPath:   env.auditPolicyPath,
-		AuditLogPath:      env.auditLogPath,
-		CaCertPath:        env.caCertPath,
-		AdminCertPath:     env.adminCertPath,
-		AdminKeyPath:      env.adminKeyPath,
-		EtcdPort:          conf.EtcdPort,
-		EtcdAddress:       c.Name() + "-etcd",
-		Verbosity:         env.verbosity,
-		DisableQPSLimits:  conf.DisableQPSLimits,
-		TracingConfigPath: kubeApiserverTracingConfigPath,
-		EtcdPrefix:        conf.EtcdPrefix,
+		Runtime:               conf.Runtime,
+		ProjectName:           c.Name(),
+		Workdir:               env.workdir,
+		Image:                 conf.KubeApiserverImage,
+		Version:               kubeApiserverVersion,
+		BindAddress:           net.PublicAddress,
+		Port:                  conf.KubeApiserverPort,
+		ReadOnlyPort:          conf.KubeApiserverReadOnlyPort,
+		KubeRuntimeConfig:     conf.KubeRuntimeConfig,
+		KubeFeatureGates:      conf.KubeFeatureGates,
+		ServiceClusterIPRange: conf.KubeServiceClusterIPRange,
+		SecurePort:            conf.SecurePort,
+		KubeAuthorization:     conf.KubeAuthorization,
+		KubeAdmission:         conf.KubeAdmission,
+		AdmissionPlugins:      conf.KubeAdmissionPlugins,
+		MutatingWebhook:       !runtime.PodDNSConfig(conf).IsEmpty(),
+		AuditPolicyPath:       env.auditPolicyPath,
+		AuditLogPath:          env.auditLogPath,
+		AuditWebhookPath:      env.auditWebhookPath,
+		CaCertPath:            env.caCertPath,
+		AdminCertPath:         env.adminCertPath,
+		AdminKeyPath:          env.adminKeyPath,
+		EtcdPort:              conf.EtcdPort,
+		EtcdAddress:           c.Name() + "-etcd",
+		EtcdMembers:           env.etcdMembers,
+		Verbosity:             env.verbosity,
+		DisableQPSLimits:      conf.DisableQPSLimits,
+		TracingConfigPath:     kubeApiserverTracingConfigPath,
+		EtcdPrefix:            conf.EtcdPrefix,
+		TLSMinVersion:         conf.KubeApiserverTLSMinVersion,
+		TLSCipherSuites:       conf.KubeApiserverTLSCipherSuites,
+		RequestTimeout:        conf.KubeApiserverRequestTimeout,
+		MinRequestTimeout:     conf.KubeApiserverMinRequestTimeout,
+
+		PriorityAndFairness:         conf.KubeApiserverPriorityAndFairness,
+		MaxRequestsInflight:         conf.KubeApiserverMaxRequestsInflight,
+		MaxMutatingRequestsInflight: conf.KubeApiserverMaxMutatingRequestsInflight,
 	})
 	if err != nil {
 		return err
@@ -590,6 +661,7 @@ func (c *Cluster) addKwokController(ctx context.Context, env *env) (err error) {
 		Version:                  kwokControllerVersion,
 		BindAddress:              net.PublicAddress,
 		Port:                     conf.KwokControllerPort,
+		ProfilingPort:            conf.KwokControllerProfilingPort,
 		ConfigPath:               env.kwokConfigPath,
 		KubeconfigPath:           env.inClusterOnHostKubeconfigPath,
 		CaCertPath:               env.caCertPath,
@@ -598,7 +670,14 @@ func (c *Cluster) addKwokController(ctx context.Context, env *env) (err error) {
 		NodeName:                 c.Name() + "-kwok-controller",
 		Verbosity:                env.verbosity,
 		NodeLeaseDurationSeconds: conf.NodeLeaseDurationSeconds,
+		ManageNodesParallelism:   conf.ManageNodesParallelism,
+		NodeCPUOvercommit:        conf.NodeCPUOvercommit,
+		NodeMemoryOvercommit:     conf.NodeMemoryOvercommit,
 		EnableCRDs:               conf.EnableCRDs,
+		PodDNSPolicy:             conf.PodDNSPolicy,
+		PodDNSNameservers:        conf.PodDNSNameservers,
+		PodDNSSearches:           conf.PodDNSSearches,
+		PodDNSOptions:            conf.PodDNSOptions,
 	})
 	kwokControllerComponent.Volumes = append(kwokControllerComponent.Volumes, logVolumes...)
 
@@ -809,11 +888,28 @@ func (c *Cluster) preInstall(_ context.Context, env *env) error {
 	return nil
 }
 
+// buildInClusterKubeconfig builds the kubeconfig used by the components to access kube-apiserver within the network
+func (c *Cluster) buildInClusterKubeconfig(env *env) ([]byte, error) {
+	conf := &env.kwokctlConfig.Options
+	return kubeconfig.EncodeKubeconfig(kubeconfig.BuildKubeconfig(kubeconfig.BuildKubeconfigConfig{
+		ProjectName:  c.Name(),
+		SecurePort:   conf.SecurePort,
+		Address:      runtime.InClusterServer(env.scheme, env.inClusterAddress, env.inClusterPort),
+		CACrtPath:    env.inClusterCaCertPath,
+		AdminCrtPath: env.inClusterAdminCertPath,
+		AdminKeyPath: env.inClusterAdminKeyPath,
+	}))
+}
+
 func (c *Cluster) finishInstall(ctx context.Context, env *env) error {
 	conf := &env.kwokctlConfig.Options
 
 	for i := range env.kwokctlConfig.Components {
-		runtime.ApplyComponentPatches(ctx, &env.kwokctlConfig.Components[i], env.kwokctlConfig.ComponentsPatches)
+		err := runtime.ApplyComponentPatches(ctx, &env.kwokctlConfig.Components[i], env.kwokctlConfig.ComponentsPatches)
+		if err != nil {
+			return err
+		}
+		components.ApplyCABundle(conf.Runtime, &env.kwokctlConfig.Components[i], env.caBundlePath)
 	}
 
 	// Setup kubeconfig
@@ -829,14 +925,7 @@ func (c *Cluster) finishInstall(ctx context.Context, env *env) error {
 		return err
 	}
 
-	inClusterKubeconfigData, err := kubeconfig.EncodeKubeconfig(kubeconfig.BuildKubeconfig(kubeconfig.BuildKubeconfigConfig{
-		ProjectName:  c.Name(),
-		SecurePort:   conf.SecurePort,
-		Address:      env.scheme + "://" + c.Name() + "-kube-apiserver:" + format.String(env.inClusterPort),
-		CACrtPath:    env.inClusterCaCertPath,
-		AdminCrtPath: env.inClusterAdminCertPath,
-		AdminKeyPath: env.inClusterAdminKeyPath,
-	}))
+	inClusterKubeconfigData, err := c.buildInClusterKubeconfig(env)
 	if err != nil {
 		return err
 	}
@@ -926,6 +1015,19 @@ func (c *Cluster) Stop(ctx context.Context) error {
 }
 
 func (c *Cluster) start(ctx context.Context) error {
+	// The containers and network are removed if the cluster was deleted with the workdir preserved,
+	// so they are created again from the config in the workdir.
+	if !c.IsDryRun() && !c.inspectNetwork(ctx, c.networkName()) {
+		err := c.createNetwork(ctx)
+		if err != nil {
+			return err
+		}
+		err = c.createComponents(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	if c.isNerdctl {
 		canNerdctlUnlessStopped, _ := c.isCanNerdctlUnlessStopped(ctx)
 		if !canNerdctlUnlessStopped {
@@ -968,10 +1070,43 @@ func (c *Cluster) start(ctx context.Context) error {
 			}
 		}
 	}
+
+	err = c.checkInClusterAddress(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = c.StartAutoSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkInClusterAddress checks that the configured in-cluster address of kube-apiserver resolves within the network
+func (c *Cluster) checkInClusterAddress(ctx context.Context) error {
+	config, err := c.Config(ctx)
+	if err != nil {
+		return err
+	}
+	address := config.Options.KubeApiserverInClusterAddress
+	if address == "" || net.IsIP(address) {
+		return nil
+	}
+
+	err = c.Exec(exec.WithAllWriteTo(ctx, io.Discard), c.runtime, "exec", c.Name()+"-"+consts.ComponentKwokController, "nslookup", address)
+	if err != nil {
+		return fmt.Errorf("the in-cluster address %q of kube-apiserver does not resolve within the network %s: %w", address, c.networkName(), err)
+	}
 	return nil
 }
 
 func (c *Cluster) stop(ctx context.Context) error {
+	err := c.StopAutoSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+
 	if c.isNerdctl {
 		canNerdctlUnlessStopped, _ := c.isCanNerdctlUnlessStopped(ctx)
 		if !canNerdctlUnlessStopped {
@@ -981,7 +1116,7 @@ func (c *Cluster) stop(ctx context.Context) error {
 			}
 		}
 	}
-	err := wait.Poll(ctx, func(ctx context.Context) (bool, error) {
+	err = wait.Poll(ctx, func(ctx context.Context) (bool, error) {
 		err := c.stopComponents(ctx)
 		return err == nil, err
 	},
@@ -1015,12 +1150,14 @@ func (c *Cluster) StopComponent(ctx context.Context, componentName string) error
 	return c.stopComponent(ctx, componentName)
 }
 
-func (c *Cluster) logs(ctx context.Context, name string, out io.Writer, follow bool) error {
-	args := []string{"logs"}
-	if follow {
-		args = append(args, "-f")
+func (c *Cluster) logs(ctx context.Context, name string, out io.Writer, follow bool, opts runtime.LogsOptions) error {
+	args := runtime.LogsArgs([]string{"logs"}, c.Name()+"-"+name, follow, opts)
+	if conf, err := c.Config(ctx); err == nil && !isLogsReadable(conf.Options.LogDriver) {
+		logger := log.FromContext(ctx)
+		logger.Warn("The logs may not be readable with the logging driver",
+			"driver", conf.Options.LogDriver,
+		)
 	}
-	args = append(args, c.Name()+"-"+name)
 	if c.IsDryRun() && !follow {
 		if file, ok := dryrun.IsCatToFileWriter(out); ok {
 			dryrun.PrintMessage("%s >%s", runtime.FormatExec(ctx, name, args...), file)
@@ -1036,13 +1173,33 @@ func (c *Cluster) logs(ctx context.Context, name string, out io.Writer, follow b
 }
 
 // Logs returns the logs of the specified component.
-func (c *Cluster) Logs(ctx context.Context, name string, out io.Writer) error {
-	return c.logs(ctx, name, out, false)
+func (c *Cluster) Logs(ctx context.Context, name string, out io.Writer, opts runtime.LogsOptions) error {
+	return c.logs(ctx, name, out, false, opts)
 }
 
 // LogsFollow follows the logs of the component
-func (c *Cluster) LogsFollow(ctx context.Context, name string, out io.Writer) error {
-	return c.logs(ctx, name, out, true)
+func (c *Cluster) LogsFollow(ctx context.Context, name string, out io.Writer, opts runtime.LogsOptions) error {
+	return c.logs(ctx, name, out, true, opts)
+}
+
+func (c *Cluster) attachArgs(name string) []string {
+	args := []string{"attach"}
+	switch c.runtime {
+	case consts.RuntimeTypeDocker, consts.RuntimeTypePodman:
+		// Do not forward the signals to the component, so that interrupting the attach does not stop it
+		args = append(args, "--sig-proxy=false")
+	}
+	return append(args, c.Name()+"-"+name)
+}
+
+// Attach attaches to the stdio of the component container
+func (c *Cluster) Attach(ctx context.Context, name string) error {
+	_, err := c.GetComponent(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return c.Exec(exec.WithStdIO(ctx), c.runtime, c.attachArgs(name)...)
 }
 
 // CollectLogs returns the logs of the specified component.
@@ -1081,15 +1238,18 @@ func (c *Cluster) CollectLogs(ctx context.Context, dir string) error {
 		return err
 	}
 
+	var errs []error
 	for _, component := range conf.Components {
 		logPath := path.Join(componentsDir, component.Name+".log")
 		f, err := c.OpenFile(logPath)
 		if err != nil {
 			logger.Error("Failed to open file", err)
+			errs = append(errs, fmt.Errorf("failed to collect logs of %s: %w", component.Name, err))
 			continue
 		}
-		if err = c.Logs(ctx, component.Name, f); err != nil {
+		if err = c.Logs(ctx, component.Name, f, runtime.LogsOptions{}); err != nil {
 			logger.Error("Failed to get log", err)
+			errs = append(errs, fmt.Errorf("failed to collect logs of %s: %w", component.Name, err))
 			if err = f.Close(); err != nil {
 				logger.Error("Failed to close file", err)
 				if err = c.Remove(logPath); err != nil {
@@ -1110,9 +1270,11 @@ func (c *Cluster) CollectLogs(ctx context.Context, dir string) error {
 		f, err := c.OpenFile(filePath)
 		if err != nil {
 			logger.Error("Failed to open file", err)
+			errs = append(errs, fmt.Errorf("failed to collect audit logs: %w", err))
 		} else {
 			if err = c.AuditLogs(ctx, f); err != nil {
 				logger.Error("Failed to get audit log", err)
+				errs = append(errs, fmt.Errorf("failed to collect audit logs: %w", err))
 			}
 			if err = f.Close(); err != nil {
 				logger.Error("Failed to close file", err)
@@ -1123,7 +1285,7 @@ func (c *Cluster) CollectLogs(ctx context.Context, dir string) error {
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // ListBinaries list binaries in the cluster
@@ -1175,7 +1337,14 @@ func (c *Cluster) InspectComponent(ctx context.Context, name string) (runtime.Co
 		return runtime.ComponentStatusStopped, nil
 	}
 
-	// TODO: check if the component is ready
+	component, err := c.GetComponent(ctx, name)
+	if err != nil {
+		return runtime.ComponentStatusUnknown, err
+	}
+	ready, err := runtime.ComponentReady(ctx, c, component)
+	if err != nil || !ready {
+		return runtime.ComponentStatusRunning, nil
+	}
 
 	return runtime.ComponentStatusReady, nil
 }
@@ -1195,6 +1364,11 @@ func (c *Cluster) Ready(ctx context.Context) (bool, error) {
 		}
 	}
 
+	// The containers may be running before the kube-apiserver is able to serve.
+	if !c.KubeApiserverReadyz(ctx) {
+		return false, nil
+	}
+
 	return c.Cluster.Ready(ctx)
 }
 
@@ -1243,6 +1417,9 @@ func (c *Cluster) InitCRs(ctx context.Context) error {
 		if conf.EnableMetricsServer {
 			dryrun.PrintMessage("# Set up apiservice for metrics server")
 		}
+		if !runtime.PodDNSConfig(&conf).IsEmpty() {
+			dryrun.PrintMessage("# Set up mutating webhook for pod dns")
+		}
 
 		return nil
 	}
@@ -1260,6 +1437,15 @@ func (c *Cluster) InitCRs(ctx context.Context) error {
 		_, _ = buf.WriteString("---\n")
 	}
 
+	if !runtime.PodDNSConfig(&conf).IsEmpty() {
+		webhook, err := c.BuildPodDNSWebhook(c.Name()+"-"+consts.ComponentKwokController, 10247)
+		if err != nil {
+			return err
+		}
+		_, _ = buf.WriteString(webhook)
+		_, _ = buf.WriteString("---\n")
+	}
+
 	if buf.Len() == 0 {
 		return nil
 	}
@@ -18,6 +18,8 @@ package compose
 
 import (
 	"context"
+	"fmt"
+	"io"
 
 	"sigs.k8s.io/kwok/pkg/consts"
 	"sigs.k8s.io/kwok/pkg/kwokctl/etcd"
@@ -58,6 +60,9 @@ func (c *Cluster) SnapshotRestore(ctx context.Context, path string) error {
 		return err
 	}
 	conf := &config.Options
+	if conf.EtcdReplicas > 1 {
+		return fmt.Errorf("restoring the etcd snapshot is not supported with %d members of etcd", conf.EtcdReplicas)
+	}
 
 	logger := log.FromContext(ctx)
 	// Restore snapshot to host temporary directory
@@ -181,6 +186,16 @@ func (c *Cluster) SnapshotRestore(ctx context.Context, path string) error {
 	return nil
 }
 
+// SnapshotSaveToWriter save the snapshot of cluster to the writer
+func (c *Cluster) SnapshotSaveToWriter(ctx context.Context, w io.Writer) error {
+	return c.Cluster.SnapshotSaveToWriter(ctx, w, c.SnapshotSave)
+}
+
+// SnapshotRestoreFromReader restore the snapshot of cluster from the reader
+func (c *Cluster) SnapshotRestoreFromReader(ctx context.Context, r io.Reader) error {
+	return c.Cluster.SnapshotRestoreFromReader(ctx, r, c.SnapshotRestore)
+}
+
 // SnapshotSaveWithYAML save the snapshot of cluster
 func (c *Cluster) SnapshotSaveWithYAML(ctx context.Context, path string, conf runtime.SnapshotSaveWithYAMLConfig) error {
 	err := c.Cluster.SnapshotSaveWithYAML(ctx, path, conf)
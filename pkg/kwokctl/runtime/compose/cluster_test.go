@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+)
+
+func TestClusterAttachArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		newCluster func(name, workdir string) (runtime.Runtime, error)
+		want       []string
+	}{
+		{
+			name:       "docker",
+			newCluster: NewDockerCluster,
+			want:       []string{"attach", "--sig-proxy=false", "kwok-test-kube-apiserver"},
+		},
+		{
+			name:       "podman",
+			newCluster: NewPodmanCluster,
+			want:       []string{"attach", "--sig-proxy=false", "kwok-test-kube-apiserver"},
+		},
+		{
+			name:       "nerdctl",
+			newCluster: NewNerdctlCluster,
+			want:       []string{"attach", "kwok-test-kube-apiserver"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt, err := tt.newCluster("kwok-test", t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := rt.(*Cluster).attachArgs("kube-apiserver")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("attachArgs() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusterBuildInClusterKubeconfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    string
+	}{
+		{
+			name: "default",
+			want: "server: https://kwok-test-kube-apiserver:6443",
+		},
+		{
+			name:    "override",
+			address: "kube-apiserver.kwok.internal",
+			want:    "server: https://kube-apiserver.kwok.internal:6443",
+		},
+		{
+			name:    "ipv6",
+			address: "fd00::10",
+			want:    "server: https://[fd00::10]:6443",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			rt, err := NewDockerCluster("kwok-test", t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := rt.(*Cluster)
+			err = c.SetConfig(ctx, &internalversion.KwokctlConfiguration{
+				Options: internalversion.KwokctlConfigurationOptions{
+					SecurePort:                    true,
+					KubeApiserverInClusterAddress: tt.address,
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			env, err := c.env(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := c.buildInClusterKubeconfig(env)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(got), tt.want) {
+				t.Errorf("buildInClusterKubeconfig() got = %s, want %q in it", got, tt.want)
+			}
+		})
+	}
+}
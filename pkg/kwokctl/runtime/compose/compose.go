@@ -0,0 +1,205 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+	"sigs.k8s.io/kwok/pkg/utils/format"
+	"sigs.k8s.io/kwok/pkg/utils/yaml"
+)
+
+// composeFile is the compose file of the cluster
+type composeFile struct {
+	Name     string                    `json:"name"`
+	Services map[string]composeService `json:"services"`
+	Networks map[string]composeNetwork `json:"networks,omitempty"`
+}
+
+// composeService is a service of the compose file
+type composeService struct {
+	ContainerName string   `json:"container_name"`
+	Image         string   `json:"image"`
+	PullPolicy    string   `json:"pull_policy,omitempty"`
+	Entrypoint    []string `json:"entrypoint,omitempty"`
+	Command       []string `json:"command,omitempty"`
+	User          string   `json:"user,omitempty"`
+	WorkingDir    string   `json:"working_dir,omitempty"`
+	Restart       string   `json:"restart,omitempty"`
+	Ports         []string `json:"ports,omitempty"`
+	Volumes       []string `json:"volumes,omitempty"`
+	Environment   []string `json:"environment,omitempty"`
+	DependsOn     []string `json:"depends_on,omitempty"`
+	Networks      []string `json:"networks,omitempty"`
+
+	Logging *composeLogging `json:"logging,omitempty"`
+}
+
+// composeLogging is the logging of a service of the compose file
+type composeLogging struct {
+	Driver  string            `json:"driver,omitempty"`
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// loggingFromOptions returns the logging of the containers,
+// it is nil if the runtime default is used.
+func loggingFromOptions(conf *internalversion.KwokctlConfigurationOptions) *composeLogging {
+	if conf.LogDriver == "" && len(conf.LogOpts) == 0 {
+		return nil
+	}
+	logging := &composeLogging{
+		Driver: conf.LogDriver,
+	}
+	for _, opt := range conf.LogOpts {
+		if logging.Options == nil {
+			logging.Options = map[string]string{}
+		}
+		key, value, _ := strings.Cut(opt, "=")
+		logging.Options[key] = value
+	}
+	return logging
+}
+
+// isLogsReadable returns whether the logs of the containers can be read back with the logging driver
+func isLogsReadable(driver string) bool {
+	switch driver {
+	case "", "json-file", "local", "journald", "k8s-file":
+		return true
+	}
+	return false
+}
+
+// composeNetwork is a network of the compose file
+type composeNetwork struct {
+	Name       string            `json:"name"`
+	DriverOpts map[string]string `json:"driver_opts,omitempty"`
+}
+
+// restartPolicyArg returns the restart policy of the container runtime for the restart policy of the component,
+// def is returned if the component does not set one.
+func restartPolicyArg(policy internalversion.RestartPolicy, def string) string {
+	switch policy {
+	case internalversion.RestartPolicyAlways:
+		return "always"
+	case internalversion.RestartPolicyOnFailure:
+		return "on-failure"
+	case internalversion.RestartPolicyNo:
+		return "no"
+	default:
+		return def
+	}
+}
+
+// publishPorts returns the ports of the component published to the host, in the form of hostPort:port/protocol
+func publishPorts(ports []internalversion.Port) []string {
+	var specs []string
+	for _, port := range ports {
+		if port.HostPort == 0 {
+			continue
+		}
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = internalversion.ProtocolTCP
+		}
+		specs = append(specs, format.String(port.HostPort)+":"+format.String(port.Port)+"/"+strings.ToLower(string(protocol)))
+	}
+	return specs
+}
+
+// mountVolumes returns the volumes of the component, in the form of hostPath:mountPath[:ro]
+func mountVolumes(volumes []internalversion.Volume) []string {
+	var specs []string
+	for _, volume := range volumes {
+		if volume.ReadOnly {
+			specs = append(specs, volume.HostPath+":"+volume.MountPath+":ro")
+		} else {
+			specs = append(specs, volume.HostPath+":"+volume.MountPath)
+		}
+	}
+	return specs
+}
+
+// envVars returns the environment variables of the component, in the form of name=value
+func envVars(envs []internalversion.Env) []string {
+	var specs []string
+	for _, env := range envs {
+		specs = append(specs, env.Name+"="+env.Value)
+	}
+	return specs
+}
+
+// convertToCompose converts the components to a compose file,
+// which is equivalent to the containers and the network created by the runtime.
+func convertToCompose(name, network string, networkOpts map[string]string, logging *composeLogging, components []internalversion.Component) composeFile {
+	file := composeFile{
+		Name:     name,
+		Services: map[string]composeService{},
+	}
+	if network != "" {
+		file.Networks = map[string]composeNetwork{
+			network: {
+				Name:       network,
+				DriverOpts: networkOpts,
+			},
+		}
+	}
+
+	for _, component := range components {
+		service := composeService{
+			ContainerName: name + "-" + component.Name,
+			Image:         component.Image,
+			PullPolicy:    "never",
+			Entrypoint:    component.Command,
+			Command:       component.Args,
+			User:          component.User,
+			WorkingDir:    component.WorkDir,
+			Restart:       restartPolicyArg(component.RestartPolicy, "unless-stopped"),
+			Ports:         publishPorts(component.Ports),
+			Volumes:       mountVolumes(component.Volumes),
+			Environment:   envVars(component.Envs),
+			DependsOn:     component.Links,
+			Logging:       logging,
+		}
+		if network != "" {
+			service.Networks = []string{network}
+		}
+		file.Services[component.Name] = service
+	}
+	return file
+}
+
+var _ runtime.ComposeExporter = &Cluster{}
+
+// ExportCompose writes the compose file of the cluster
+func (c *Cluster) ExportCompose(ctx context.Context, w io.Writer) error {
+	config, err := c.Config(ctx)
+	if err != nil {
+		return err
+	}
+
+	file := convertToCompose(c.Name(), c.networkName(), c.networkOpts(&config.Options), loggingFromOptions(&config.Options), config.Components)
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,360 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
+	"sigs.k8s.io/kwok/pkg/utils/yaml"
+)
+
+func Test_convertToCompose(t *testing.T) {
+	components := []internalversion.Component{
+		{
+			Name:  "etcd",
+			Image: "registry.k8s.io/etcd:3.5.11-0",
+			Command: []string{
+				"etcd",
+			},
+			Args: []string{
+				"--data-dir=/etcd-data",
+			},
+		},
+		{
+			Name:  "kube-apiserver",
+			Image: "registry.k8s.io/kube-apiserver:v1.29.0",
+			Links: []string{"etcd"},
+			Ports: []internalversion.Port{
+				{
+					Name:     "https",
+					HostPort: 32766,
+					Port:     6443,
+				},
+				{
+					Name: "metrics",
+					Port: 8080,
+				},
+			},
+			Volumes: []internalversion.Volume{
+				{
+					HostPath:  "/workdir/pki/ca.crt",
+					MountPath: "/etc/kubernetes/pki/ca.crt",
+					ReadOnly:  true,
+				},
+				{
+					HostPath:  "/workdir/logs",
+					MountPath: "/var/log/kubernetes",
+				},
+			},
+			Envs: []internalversion.Env{
+				{
+					Name:  "SSL_CERT_FILE",
+					Value: "/etc/kwok/pki/ca-bundle.crt",
+				},
+			},
+		},
+		{
+			Name:  "kwok-controller",
+			Image: "registry.k8s.io/kwok/kwok:v0.6.0",
+			Links: []string{"kube-apiserver"},
+		},
+	}
+
+	want := composeFile{
+		Name: "kwok-test",
+		Services: map[string]composeService{
+			"etcd": {
+				ContainerName: "kwok-test-etcd",
+				Image:         "registry.k8s.io/etcd:3.5.11-0",
+				PullPolicy:    "never",
+				Entrypoint:    []string{"etcd"},
+				Command:       []string{"--data-dir=/etcd-data"},
+				Restart:       "unless-stopped",
+				Networks:      []string{"kwok-test"},
+			},
+			"kube-apiserver": {
+				ContainerName: "kwok-test-kube-apiserver",
+				Image:         "registry.k8s.io/kube-apiserver:v1.29.0",
+				PullPolicy:    "never",
+				Restart:       "unless-stopped",
+				Ports:         []string{"32766:6443/tcp"},
+				Volumes: []string{
+					"/workdir/pki/ca.crt:/etc/kubernetes/pki/ca.crt:ro",
+					"/workdir/logs:/var/log/kubernetes",
+				},
+				Environment: []string{"SSL_CERT_FILE=/etc/kwok/pki/ca-bundle.crt"},
+				DependsOn:   []string{"etcd"},
+				Networks:    []string{"kwok-test"},
+			},
+			"kwok-controller": {
+				ContainerName: "kwok-test-kwok-controller",
+				Image:         "registry.k8s.io/kwok/kwok:v0.6.0",
+				PullPolicy:    "never",
+				Restart:       "unless-stopped",
+				DependsOn:     []string{"kube-apiserver"},
+				Networks:      []string{"kwok-test"},
+			},
+		},
+		Networks: map[string]composeNetwork{
+			"kwok-test": {
+				Name: "kwok-test",
+			},
+		},
+	}
+
+	got := convertToCompose("kwok-test", "kwok-test", nil, nil, components)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("convertToCompose() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCluster_ExportCompose(t *testing.T) {
+	ctx := context.Background()
+	rt, err := NewDockerCluster("kwok-test", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := rt.(*Cluster)
+
+	conf := &internalversion.KwokctlConfiguration{
+		Components: []internalversion.Component{
+			{Name: "etcd", Image: "etcd"},
+			{Name: "kube-apiserver", Image: "kube-apiserver", Links: []string{"etcd"}},
+			{Name: "kube-controller-manager", Image: "kube-controller-manager", Links: []string{"kube-apiserver"}},
+			{Name: "kube-scheduler", Image: "kube-scheduler", Links: []string{"kube-apiserver"}},
+			{Name: "kwok-controller", Image: "kwok", Links: []string{"kube-apiserver"}},
+		},
+	}
+	err = c.SetConfig(ctx, conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = c.ExportCompose(ctx, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var file composeFile
+	err = yaml.Unmarshal(buf.Bytes(), &file)
+	if err != nil {
+		t.Fatalf("failed to unmarshal exported compose file: %v\n%s", err, buf.String())
+	}
+	for _, component := range conf.Components {
+		service, ok := file.Services[component.Name]
+		if !ok {
+			t.Errorf("service %q not found in exported compose file:\n%s", component.Name, buf.String())
+			continue
+		}
+		if !strings.HasPrefix(service.ContainerName, "kwok-test-") {
+			t.Errorf("unexpected container name %q of service %q", service.ContainerName, component.Name)
+		}
+	}
+	if len(file.Services) != len(conf.Components) {
+		t.Errorf("got %d services, want %d", len(file.Services), len(conf.Components))
+	}
+}
+
+func TestCluster_ExportComposeNetworkMTU(t *testing.T) {
+	tests := []struct {
+		name       string
+		newCluster func(name, workdir string) (runtime.Runtime, error)
+		mtu        uint32
+		want       map[string]string
+	}{
+		{
+			name:       "docker without mtu",
+			newCluster: NewDockerCluster,
+		},
+		{
+			name:       "docker",
+			newCluster: NewDockerCluster,
+			mtu:        1450,
+			want:       map[string]string{"com.docker.network.driver.mtu": "1450"},
+		},
+		{
+			name:       "podman",
+			newCluster: NewPodmanCluster,
+			mtu:        1450,
+			want:       map[string]string{"mtu": "1450"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			rt, err := tt.newCluster("kwok-test", t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := rt.(*Cluster)
+
+			err = c.SetConfig(ctx, &internalversion.KwokctlConfiguration{
+				Options: internalversion.KwokctlConfigurationOptions{
+					NetworkMTU: tt.mtu,
+				},
+				Components: []internalversion.Component{
+					{Name: "etcd", Image: "etcd"},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			buf := bytes.NewBuffer(nil)
+			err = c.ExportCompose(ctx, buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var file composeFile
+			err = yaml.Unmarshal(buf.Bytes(), &file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, file.Networks["kwok-test"].DriverOpts); diff != "" {
+				t.Errorf("unexpected driver options of the network (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_convertToComposeCommandOverride(t *testing.T) {
+	component := internalversion.Component{
+		Name:    "etcd",
+		Image:   "registry.k8s.io/etcd:3.5.11-0",
+		Command: []string{"etcd"},
+		Args:    []string{"--data-dir=/etcd-data"},
+	}
+	err := runtime.ApplyComponentPatches(context.Background(), &component, []internalversion.ComponentPatches{
+		{
+			Name:            "etcd",
+			CommandOverride: []string{"strace", "-f", "etcd"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := convertToCompose("kwok-test", "", nil, nil, []internalversion.Component{component})
+	service := file.Services["etcd"]
+	if diff := cmp.Diff([]string{"strace", "-f", "etcd"}, service.Entrypoint); diff != "" {
+		t.Errorf("unexpected entrypoint (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"--data-dir=/etcd-data"}, service.Command); diff != "" {
+		t.Errorf("unexpected command (-want +got):\n%s", diff)
+	}
+}
+
+func Test_convertToComposeRestartPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		restartPolicy internalversion.RestartPolicy
+		want          string
+	}{
+		{
+			name: "default",
+			want: "unless-stopped",
+		},
+		{
+			name:          "always",
+			restartPolicy: internalversion.RestartPolicyAlways,
+			want:          "always",
+		},
+		{
+			name:          "on failure",
+			restartPolicy: internalversion.RestartPolicyOnFailure,
+			want:          "on-failure",
+		},
+		{
+			name:          "no",
+			restartPolicy: internalversion.RestartPolicyNo,
+			want:          "no",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := convertToCompose("kwok-test", "", nil, nil, []internalversion.Component{
+				{
+					Name:          "kube-scheduler",
+					Image:         "registry.k8s.io/kube-scheduler:v1.30.0",
+					RestartPolicy: tt.restartPolicy,
+				},
+			})
+			if got := file.Services["kube-scheduler"].Restart; got != tt.want {
+				t.Errorf("want restart %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_convertToComposeLogging(t *testing.T) {
+	tests := []struct {
+		name string
+		conf internalversion.KwokctlConfigurationOptions
+		want *composeLogging
+	}{
+		{
+			name: "runtime default",
+		},
+		{
+			name: "log driver",
+			conf: internalversion.KwokctlConfigurationOptions{
+				LogDriver: "syslog",
+				LogOpts:   []string{"syslog-address=udp://127.0.0.1:514", "tag=kwok"},
+			},
+			want: &composeLogging{
+				Driver: "syslog",
+				Options: map[string]string{
+					"syslog-address": "udp://127.0.0.1:514",
+					"tag":            "kwok",
+				},
+			},
+		},
+		{
+			name: "log options only",
+			conf: internalversion.KwokctlConfigurationOptions{
+				LogOpts: []string{"max-size=10m"},
+			},
+			want: &composeLogging{
+				Options: map[string]string{
+					"max-size": "10m",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := convertToCompose("kwok-test", "", nil, loggingFromOptions(&tt.conf), []internalversion.Component{
+				{
+					Name:  "etcd",
+					Image: "registry.k8s.io/etcd:3.5.11-0",
+				},
+			})
+			if diff := cmp.Diff(tt.want, file.Services["etcd"].Logging); diff != "" {
+				t.Errorf("unexpected logging (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -27,4 +27,8 @@ func init() {
 	runtime.DefaultRegistry.Register(consts.RuntimeTypeNerdctl, NewNerdctlCluster)
 	runtime.DefaultRegistry.Register(consts.RuntimeTypeLima, NewLimaCluster)
 	runtime.DefaultRegistry.Register(consts.RuntimeTypeFinch, NewFinchCluster)
+
+	runtime.DefaultRegistry.RegisterAlias("compose", consts.RuntimeTypeDocker)
+	runtime.DefaultRegistry.RegisterAlias("docker-compose", consts.RuntimeTypeDocker)
+	runtime.DefaultRegistry.RegisterAlias("containerd", consts.RuntimeTypeNerdctl)
 }
@@ -21,6 +21,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"sigs.k8s.io/kwok/pkg/apis/internalversion"
@@ -48,14 +49,47 @@ func (c *Cluster) createNetwork(ctx context.Context) error {
 			return nil
 		}
 	}
+	config, err := c.Config(ctx)
+	if err != nil {
+		return err
+	}
+	conf := &config.Options
+
 	args := []string{
 		"network", "create", network,
 	}
 	args = append(args, c.labelArgs()...)
+	opts := c.networkOpts(conf)
+	keys := make([]string, 0, len(opts))
+	for key := range opts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, "--opt", key+"="+opts[key])
+	}
 	logger.Debug("Creating network")
 	return c.Exec(ctx, c.runtime, args...)
 }
 
+// networkOpts returns the driver options of the network.
+func (c *Cluster) networkOpts(conf *internalversion.KwokctlConfigurationOptions) map[string]string {
+	if conf.NetworkMTU == 0 {
+		return nil
+	}
+	return map[string]string{
+		c.networkMTUOption(): format.String(conf.NetworkMTU),
+	}
+}
+
+// networkMTUOption returns the driver option to set the MTU of the network.
+func (c *Cluster) networkMTUOption() string {
+	if c.runtime == consts.RuntimeTypePodman {
+		return "mtu"
+	}
+	return "com.docker.network.driver.mtu"
+}
+
 func (c *Cluster) deleteNetwork(ctx context.Context) error {
 	network := c.networkName()
 	logger := log.FromContext(ctx)
@@ -200,14 +234,20 @@ func (c *Cluster) createComponent(ctx context.Context, componentName string) err
 		return fmt.Errorf("component %s not found", componentName)
 	}
 
+	args := c.createComponentArgs(ctx, component, loggingFromOptions(&conf.Options))
+
+	logger.Debug("Creating component")
+	return c.Exec(ctx, c.runtime, args...)
+}
+
+func (c *Cluster) createComponentArgs(ctx context.Context, component internalversion.Component, logging *composeLogging) []string {
 	args := []string{"create",
-		"--name=" + c.Name() + "-" + componentName,
+		"--name=" + c.Name() + "-" + component.Name,
 		"--pull=never",
 	}
 
-	entrypoint := strings.Join(component.Command, " ")
-	if entrypoint != "" {
-		args = append(args, "--entrypoint="+entrypoint)
+	if len(component.Command) != 0 {
+		args = append(args, "--entrypoint="+component.Command[0])
 	}
 
 	network := c.networkName()
@@ -232,13 +272,16 @@ func (c *Cluster) createComponent(ctx context.Context, componentName string) err
 		// Nerdctl does not support --link and --requires
 	}
 
-	switch c.runtime {
-	case consts.RuntimeTypeDocker, consts.RuntimeTypePodman:
+	switch {
+	case component.RestartPolicy != "":
+		args = append(args, "--restart="+restartPolicyArg(component.RestartPolicy, "unless-stopped"))
+	case c.runtime == consts.RuntimeTypeDocker, c.runtime == consts.RuntimeTypePodman:
 		args = append(args, "--restart=unless-stopped")
 	default:
 		if c.isNerdctl {
 			canNerdctlUnlessStopped, err := c.isCanNerdctlUnlessStopped(ctx)
 			if err != nil {
+				logger := log.FromContext(ctx)
 				logger.Error("Failed to check unless-stopped support", err)
 			}
 			if canNerdctlUnlessStopped {
@@ -251,32 +294,36 @@ func (c *Cluster) createComponent(ctx context.Context, componentName string) err
 
 	args = append(args, c.labelArgs()...)
 
-	for _, port := range component.Ports {
-		if port.HostPort == 0 {
-			continue
+	if logging != nil {
+		if logging.Driver != "" {
+			args = append(args, "--log-driver="+logging.Driver)
 		}
-		protocol := port.Protocol
-		if protocol == "" {
-			protocol = internalversion.ProtocolTCP
+		keys := make([]string, 0, len(logging.Options))
+		for key := range logging.Options {
+			keys = append(keys, key)
 		}
-		args = append(args, "--publish="+format.String(port.HostPort)+":"+format.String(port.Port)+"/"+strings.ToLower(string(protocol)))
-	}
-	for _, volume := range component.Volumes {
-		if volume.ReadOnly {
-			args = append(args, "--volume="+volume.HostPath+":"+volume.MountPath+":ro")
-		} else {
-			args = append(args, "--volume="+volume.HostPath+":"+volume.MountPath)
+		sort.Strings(keys)
+		for _, key := range keys {
+			args = append(args, "--log-opt="+key+"="+logging.Options[key])
 		}
 	}
-	for _, env := range component.Envs {
-		args = append(args, "--env="+env.Name+"="+env.Value)
+
+	for _, port := range publishPorts(component.Ports) {
+		args = append(args, "--publish="+port)
+	}
+	for _, volume := range mountVolumes(component.Volumes) {
+		args = append(args, "--volume="+volume)
+	}
+	for _, env := range envVars(component.Envs) {
+		args = append(args, "--env="+env)
 	}
 
 	args = append(args, component.Image)
+	if len(component.Command) > 1 {
+		args = append(args, component.Command[1:]...)
+	}
 	args = append(args, component.Args...)
-
-	logger.Debug("Creating component")
-	return c.Exec(ctx, c.runtime, args...)
+	return args
 }
 
 func (c *Cluster) createComponents(ctx context.Context) error {
@@ -17,7 +17,11 @@ limitations under the License.
 package compose
 
 import (
+	"context"
+	"reflect"
 	"testing"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
 )
 
 func Test_checkInspect(t *testing.T) {
@@ -100,3 +104,87 @@ func Test_checkInspect(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterCreateComponentArgs(t *testing.T) {
+	rt, err := NewDockerCluster("kwok-test", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := rt.(*Cluster)
+
+	tests := []struct {
+		name          string
+		command       []string
+		restartPolicy internalversion.RestartPolicy
+		logging       *composeLogging
+		want          []string
+	}{
+		{
+			name:    "default command",
+			command: []string{"etcd"},
+			want: []string{
+				"create", "--name=kwok-test-etcd", "--pull=never", "--entrypoint=etcd", "--network=kwok-test",
+				"--restart=unless-stopped", "--label=com.docker.compose.project=kwok-test",
+				"registry.k8s.io/etcd:3.5.11-0", "--data-dir=/etcd-data",
+			},
+		},
+		{
+			name:    "command override",
+			command: []string{"strace", "-f", "etcd"},
+			want: []string{
+				"create", "--name=kwok-test-etcd", "--pull=never", "--entrypoint=strace", "--network=kwok-test",
+				"--restart=unless-stopped", "--label=com.docker.compose.project=kwok-test",
+				"registry.k8s.io/etcd:3.5.11-0", "-f", "etcd", "--data-dir=/etcd-data",
+			},
+		},
+		{
+			name:          "restart policy",
+			command:       []string{"etcd"},
+			restartPolicy: internalversion.RestartPolicyOnFailure,
+			want: []string{
+				"create", "--name=kwok-test-etcd", "--pull=never", "--entrypoint=etcd", "--network=kwok-test",
+				"--restart=on-failure", "--label=com.docker.compose.project=kwok-test",
+				"registry.k8s.io/etcd:3.5.11-0", "--data-dir=/etcd-data",
+			},
+		},
+		{
+			name: "image entrypoint",
+			want: []string{
+				"create", "--name=kwok-test-etcd", "--pull=never", "--network=kwok-test",
+				"--restart=unless-stopped", "--label=com.docker.compose.project=kwok-test",
+				"registry.k8s.io/etcd:3.5.11-0", "--data-dir=/etcd-data",
+			},
+		},
+		{
+			name:    "log driver",
+			command: []string{"etcd"},
+			logging: &composeLogging{
+				Driver: "fluentd",
+				Options: map[string]string{
+					"tag":             "kwok",
+					"fluentd-address": "localhost:24224",
+				},
+			},
+			want: []string{
+				"create", "--name=kwok-test-etcd", "--pull=never", "--entrypoint=etcd", "--network=kwok-test",
+				"--restart=unless-stopped", "--label=com.docker.compose.project=kwok-test",
+				"--log-driver=fluentd", "--log-opt=fluentd-address=localhost:24224", "--log-opt=tag=kwok",
+				"registry.k8s.io/etcd:3.5.11-0", "--data-dir=/etcd-data",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.createComponentArgs(context.Background(), internalversion.Component{
+				Name:          "etcd",
+				Image:         "registry.k8s.io/etcd:3.5.11-0",
+				Command:       tt.command,
+				Args:          []string{"--data-dir=/etcd-data"},
+				RestartPolicy: tt.restartPolicy,
+			}, tt.logging)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("createComponentArgs() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
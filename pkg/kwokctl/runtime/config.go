@@ -21,8 +21,12 @@ import (
 	"io"
 	"time"
 
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
 	"sigs.k8s.io/kwok/pkg/apis/internalversion"
 	"sigs.k8s.io/kwok/pkg/kwokctl/etcd"
+	"sigs.k8s.io/kwok/pkg/kwokctl/snapshot"
 	"sigs.k8s.io/kwok/pkg/utils/client"
 )
 
@@ -98,10 +102,13 @@ type Runtime interface {
 	EtcdctlInCluster(ctx context.Context, args ...string) error
 
 	// Logs logs of a component
-	Logs(ctx context.Context, name string, out io.Writer) error
+	Logs(ctx context.Context, name string, out io.Writer, opts LogsOptions) error
 
 	// LogsFollow follow logs of a component with follow
-	LogsFollow(ctx context.Context, name string, out io.Writer) error
+	LogsFollow(ctx context.Context, name string, out io.Writer, opts LogsOptions) error
+
+	// Attach attach to the stdio of a component
+	Attach(ctx context.Context, name string) error
 
 	// CollectLogs will populate dir with cluster logs and other debug files
 	CollectLogs(ctx context.Context, dir string) error
@@ -124,6 +131,12 @@ type Runtime interface {
 	// SnapshotRestore restore the snapshot of cluster
 	SnapshotRestore(ctx context.Context, path string) error
 
+	// SnapshotSaveToWriter save the snapshot of cluster to the writer
+	SnapshotSaveToWriter(ctx context.Context, w io.Writer) error
+
+	// SnapshotRestoreFromReader restore the snapshot of cluster from the reader
+	SnapshotRestoreFromReader(ctx context.Context, r io.Reader) error
+
 	// SnapshotSaveWithYAML save the snapshot of cluster
 	SnapshotSaveWithYAML(ctx context.Context, path string, conf SnapshotSaveWithYAMLConfig) error
 
@@ -145,16 +158,50 @@ type Runtime interface {
 	// GetClientset returns the clientset of cluster
 	GetClientset(ctx context.Context) (client.Clientset, error)
 
+	// GetClients returns the typed and dynamic clients of cluster,
+	// it is safe to be called concurrently and the clients are shared between the calls.
+	GetClients(ctx context.Context) (*Clients, error)
+
 	// GetEtcdClient returns the etcd client of cluster
 	GetEtcdClient(ctx context.Context) (etcd.Client, func(), error)
 }
 
+// ComposeExporter is implemented by the runtimes that run the components as containers,
+// which can be described by a compose file.
+type ComposeExporter interface {
+	// ExportCompose writes the compose file of the cluster
+	ExportCompose(ctx context.Context, w io.Writer) error
+}
+
+// Clients is the clients of a cluster, which are safe for concurrent use.
+type Clients struct {
+	// Kubernetes is the typed client of the cluster
+	Kubernetes kubernetes.Interface
+	// Dynamic is the dynamic client of the cluster
+	Dynamic dynamic.Interface
+}
+
 type SnapshotSaveWithYAMLConfig struct {
 	Filters []string
+	// Watch keeps watching the resources after saving,
+	// and appends the changes to the snapshot until the context is done.
+	Watch bool
+	// Anonymize redacts the sensitive data of the resources,
+	// the data and stringData of Secret are always redacted.
+	Anonymize bool
+	// AnonymizeConfig is the extra redaction rules if Anonymize is true.
+	AnonymizeConfig snapshot.AnonymizeConfig
+	// JSONLines writes one JSON object per line instead of a YAML stream.
+	JSONLines bool
 }
 
 type SnapshotRestoreWithYAMLConfig struct {
 	Filters []string
+	// ConflictStrategy is how to resolve the conflicts with the existing resources.
+	ConflictStrategy snapshot.ConflictStrategy
+	// Prune deletes the resources of the filters in the cluster that are absent from the snapshot,
+	// only the kinds present in the snapshot are pruned.
+	Prune bool
 }
 
 type ComponentStatus uint64
@@ -39,7 +39,12 @@ import (
 // ForkExec forks a new process and execs the given command.
 // The process will be terminated when the context is canceled.
 func (c *Cluster) ForkExec(ctx context.Context, dir string, name string, args ...string) error {
-	pidPath := path.Join(dir, "pids", path.OnlyName(name)+".pid")
+	return c.forkExec(ctx, dir, path.OnlyName(name), name, args...)
+}
+
+// forkExec is like ForkExec, but the pid and log files are named after key instead of the command.
+func (c *Cluster) forkExec(ctx context.Context, dir string, key string, name string, args ...string) error {
+	pidPath := path.Join(dir, "pids", key+".pid")
 	if file.Exists(pidPath) {
 		pidData, err := os.ReadFile(pidPath)
 		if err == nil {
@@ -53,7 +58,7 @@ func (c *Cluster) ForkExec(ctx context.Context, dir string, name string, args ..
 	}
 	ctx = exec.WithDir(ctx, dir)
 	ctx = exec.WithFork(ctx, true)
-	logPath := path.Join(dir, "logs", path.OnlyName(name)+".log")
+	logPath := path.Join(dir, "logs", key+".log")
 	logFile, err := c.OpenFile(logPath)
 	if err != nil {
 		return fmt.Errorf("open log file %s: %w", logPath, err)
@@ -83,7 +88,12 @@ func (c *Cluster) ForkExec(ctx context.Context, dir string, name string, args ..
 
 // ForkExecKill kills the process if it is running.
 func (c *Cluster) ForkExecKill(ctx context.Context, dir string, name string) error {
-	pidPath := path.Join(dir, "pids", path.OnlyName(name)+".pid")
+	return c.forkExecKill(ctx, dir, path.OnlyName(name))
+}
+
+// forkExecKill is like ForkExecKill, but the pid file is named after key instead of the command.
+func (c *Cluster) forkExecKill(ctx context.Context, dir string, key string) error {
+	pidPath := path.Join(dir, "pids", key+".pid")
 	if !file.Exists(pidPath) {
 		// No pid file exists, which means the process has been terminated
 		logger := log.FromContext(ctx)
@@ -23,9 +23,12 @@ import (
 	"os"
 	"strings"
 
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
 	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
 	"sigs.k8s.io/kwok/pkg/kwokctl/pki"
+	"sigs.k8s.io/kwok/pkg/log"
 	"sigs.k8s.io/kwok/pkg/utils/file"
+	"sigs.k8s.io/kwok/pkg/utils/net"
 )
 
 // DownloadWithCache downloads the src file to the dest file.
@@ -45,6 +48,32 @@ func (c *Cluster) DownloadWithCache(ctx context.Context, cacheDir, src, dest str
 	return file.DownloadWithCache(ctx, cacheDir, src, dest, mode, quiet)
 }
 
+// CertSANs returns the alt names of the certs of the cluster named name, the ips of this host and the configured ones,
+// inNetwork adds the names the components reach the kube-apiserver and the kwok-controller by within the network.
+func CertSANs(ctx context.Context, name string, conf *internalversion.KwokctlConfigurationOptions, inNetwork bool) []string {
+	sans := []string{}
+	if inNetwork {
+		sans = append(sans,
+			name+"-kube-apiserver",
+			name+"-kwok-controller",
+		)
+	}
+	ips, err := net.GetAllIPs()
+	if err != nil {
+		logger := log.FromContext(ctx)
+		logger.Warn("failed to get all ips", "err", err)
+	} else {
+		sans = append(sans, ips...)
+	}
+	if len(conf.KubeApiserverCertSANs) != 0 {
+		sans = append(sans, conf.KubeApiserverCertSANs...)
+	}
+	if inNetwork && conf.KubeApiserverInClusterAddress != "" {
+		sans = append(sans, conf.KubeApiserverInClusterAddress)
+	}
+	return sans
+}
+
 // GeneratePki generates the pki for kwokctl
 func (c *Cluster) GeneratePki(pkiPath string, sans ...string) error {
 	if c.IsDryRun() {
@@ -19,6 +19,7 @@ package kind
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"path/filepath"
@@ -110,18 +111,8 @@ func (c *Cluster) setup(ctx context.Context, env *env) error {
 
 	pkiPath := c.GetWorkdirPath(runtime.PkiName)
 	if !file.Exists(pkiPath) {
-		sans := []string{}
-		ips, err := net.GetAllIPs()
-		if err != nil {
-			logger := log.FromContext(ctx)
-			logger.Warn("failed to get all ips", "err", err)
-		} else {
-			sans = append(sans, ips...)
-		}
-		if len(conf.KubeApiserverCertSANs) != 0 {
-			sans = append(sans, conf.KubeApiserverCertSANs...)
-		}
-		err = c.MkdirAll(pkiPath)
+		sans := runtime.CertSANs(ctx, c.Name(), conf, false)
+		err := c.MkdirAll(pkiPath)
 		if err != nil {
 			return fmt.Errorf("failed to create pki dir: %w", err)
 		}
@@ -167,6 +158,7 @@ type env struct {
 	schedulerConfigPath  string
 	auditLogPath         string
 	auditPolicyPath      string
+	auditWebhookPath     string
 	prometheusConfigPath string
 
 	inClusterOnHostKubeconfigPath string
@@ -192,9 +184,13 @@ func (c *Cluster) env(ctx context.Context) (*env, error) {
 	kwokConfigPath := "/etc/kwok/kwok.yaml"
 	auditLogPath := ""
 	auditPolicyPath := ""
+	auditWebhookPath := ""
 	if config.Options.KubeAuditPolicy != "" {
 		auditLogPath = c.GetLogPath(runtime.AuditLogName)
 		auditPolicyPath = c.GetWorkdirPath(runtime.AuditPolicyName)
+		if config.Options.KubeAuditWebhook != "" {
+			auditWebhookPath = c.GetWorkdirPath(runtime.AuditWebhookConfigName)
+		}
 	}
 
 	logger := log.FromContext(ctx)
@@ -215,6 +211,7 @@ func (c *Cluster) env(ctx context.Context) (*env, error) {
 		prometheusConfigPath:          prometheusConfigPath,
 		auditLogPath:                  auditLogPath,
 		auditPolicyPath:               auditPolicyPath,
+		auditWebhookPath:              auditWebhookPath,
 		inClusterOnHostKubeconfigPath: inClusterOnHostKubeconfigPath,
 		workdir:                       workdir,
 		caCertPath:                    caCertPath,
@@ -365,6 +362,27 @@ func (c *Cluster) addKind(ctx context.Context, env *env) (err error) {
 		if err != nil {
 			return err
 		}
+
+		if conf.KubeAuditWebhook != "" {
+			auditWebhookConfig, err := runtime.BuildAuditWebhookConfig(conf.KubeAuditWebhook)
+			if err != nil {
+				return fmt.Errorf("failed to build audit webhook config: %w", err)
+			}
+			err = c.WriteFile(env.auditWebhookPath, auditWebhookConfig)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if conf.CABundle != "" {
+		logger := log.FromContext(ctx)
+		logger.Warn("The ca bundle is not supported by kind runtime, ignored", "caBundle", conf.CABundle)
+	}
+
+	if conf.KubeApiserverReadOnlyPort != 0 {
+		logger := log.FromContext(ctx)
+		logger.Warn("The read-only port is not supported by kind runtime, ignored", "readOnlyPort", conf.KubeApiserverReadOnlyPort)
 	}
 
 	schedulerConfigPath := ""
@@ -443,36 +461,65 @@ func (c *Cluster) addKind(ctx context.Context, env *env) (err error) {
 		len(kubeControllerManagerComponentPatches.ExtraEnvs) > 0 {
 		logger.Warn("extraEnvs config in etcd, kube-apiserver, kube-scheduler or kube-controller-manager is not supported in kind")
 	}
+	if len(etcdComponentPatches.CommandOverride) > 0 ||
+		len(kubeApiserverComponentPatches.CommandOverride) > 0 ||
+		len(kubeSchedulerComponentPatches.CommandOverride) > 0 ||
+		len(kubeControllerManagerComponentPatches.CommandOverride) > 0 {
+		logger.Warn("commandOverride config in etcd, kube-apiserver, kube-scheduler or kube-controller-manager is not supported in kind")
+	}
+	if len(conf.EtcdExtraClientURLs) > 0 {
+		logger.Warn("etcdExtraClientURLs config is not supported in kind")
+	}
+	if conf.KubeAuditLogMaxSize > 0 {
+		logger.Warn("kubeAuditLogMaxSize config is not supported in kind")
+	}
+	if conf.EtcdReplicas > 1 {
+		logger.Warn("The multiple members of etcd are not supported by kind runtime, ignored", "etcdReplicas", conf.EtcdReplicas)
+	}
 	kindYaml, err := BuildKind(BuildKindConfig{
-		BindAddress:                   conf.BindAddress,
-		KubeApiserverPort:             conf.KubeApiserverPort,
-		KubeApiserverInsecurePort:     conf.KubeApiserverInsecurePort,
-		EtcdPort:                      conf.EtcdPort,
-		JaegerPort:                    conf.JaegerPort,
-		DashboardPort:                 conf.DashboardPort,
-		PrometheusPort:                conf.PrometheusPort,
-		KwokControllerPort:            conf.KwokControllerPort,
-		FeatureGates:                  featureGates,
-		RuntimeConfig:                 runtimeConfig,
-		AuditPolicy:                   env.auditPolicyPath,
-		AuditLog:                      env.auditLogPath,
-		SchedulerConfig:               schedulerConfigPath,
-		TracingConfigPath:             kubeApiserverTracingConfigPath,
-		Workdir:                       c.Workdir(),
-		Verbosity:                     env.verbosity,
-		EtcdExtraArgs:                 etcdComponentPatches.ExtraArgs,
-		EtcdExtraVolumes:              etcdComponentPatches.ExtraVolumes,
-		ApiserverExtraArgs:            kubeApiserverComponentPatches.ExtraArgs,
-		ApiserverExtraVolumes:         kubeApiserverComponentPatches.ExtraVolumes,
-		SchedulerExtraArgs:            kubeSchedulerComponentPatches.ExtraArgs,
-		SchedulerExtraVolumes:         kubeSchedulerComponentPatches.ExtraVolumes,
-		ControllerManagerExtraArgs:    kubeControllerManagerComponentPatches.ExtraArgs,
-		ControllerManagerExtraVolumes: kubeControllerManagerComponentPatches.ExtraVolumes,
-		KwokControllerExtraVolumes:    kwokControllerExtraVolumes,
-		PrometheusExtraVolumes:        prometheusPatches.ExtraVolumes,
-		DisableQPSLimits:              conf.DisableQPSLimits,
-		KubeVersion:                   kubeVersion,
-		EtcdQuotaBackendSize:          conf.EtcdQuotaBackendSize,
+		BindAddress:                    conf.BindAddress,
+		KubeApiserverPort:              conf.KubeApiserverPort,
+		KubeApiserverInsecurePort:      conf.KubeApiserverInsecurePort,
+		EtcdPort:                       conf.EtcdPort,
+		JaegerPort:                     conf.JaegerPort,
+		DashboardPort:                  conf.DashboardPort,
+		PrometheusPort:                 conf.PrometheusPort,
+		KwokControllerPort:             conf.KwokControllerPort,
+		KwokControllerProfilingPort:    conf.KwokControllerProfilingPort,
+		FeatureGates:                   featureGates,
+		RuntimeConfig:                  runtimeConfig,
+		ServiceClusterIPRange:          conf.KubeServiceClusterIPRange,
+		AuditPolicy:                    env.auditPolicyPath,
+		AuditLog:                       env.auditLogPath,
+		AuditWebhook:                   env.auditWebhookPath,
+		SchedulerConfig:                schedulerConfigPath,
+		TracingConfigPath:              kubeApiserverTracingConfigPath,
+		Workdir:                        c.Workdir(),
+		Verbosity:                      env.verbosity,
+		EtcdExtraArgs:                  etcdComponentPatches.ExtraArgs,
+		EtcdExtraVolumes:               etcdComponentPatches.ExtraVolumes,
+		ApiserverExtraArgs:             kubeApiserverComponentPatches.ExtraArgs,
+		ApiserverExtraVolumes:          kubeApiserverComponentPatches.ExtraVolumes,
+		SchedulerExtraArgs:             kubeSchedulerComponentPatches.ExtraArgs,
+		SchedulerExtraVolumes:          kubeSchedulerComponentPatches.ExtraVolumes,
+		ControllerManagerExtraArgs:     kubeControllerManagerComponentPatches.ExtraArgs,
+		ControllerManagerExtraVolumes:  kubeControllerManagerComponentPatches.ExtraVolumes,
+		KwokControllerExtraVolumes:     kwokControllerExtraVolumes,
+		PrometheusExtraVolumes:         prometheusPatches.ExtraVolumes,
+		DisableQPSLimits:               conf.DisableQPSLimits,
+		KubeVersion:                    kubeVersion,
+		EtcdQuotaBackendSize:           conf.EtcdQuotaBackendSize,
+		KubeApiserverTLSMinVersion:     conf.KubeApiserverTLSMinVersion,
+		KubeApiserverTLSCipherSuites:   conf.KubeApiserverTLSCipherSuites,
+		KubeApiserverRequestTimeout:    conf.KubeApiserverRequestTimeout,
+		KubeApiserverMinRequestTimeout: conf.KubeApiserverMinRequestTimeout,
+
+		KubeApiserverPriorityAndFairness:         conf.KubeApiserverPriorityAndFairness,
+		KubeApiserverMaxRequestsInflight:         conf.KubeApiserverMaxRequestsInflight,
+		KubeApiserverMaxMutatingRequestsInflight: conf.KubeApiserverMaxMutatingRequestsInflight,
+		EtcdUnsafeNoFsync:                        conf.EtcdUnsafeNoFsync,
+		EtcdHeartbeatInterval:                    conf.EtcdHeartbeatInterval,
+		EtcdElectionTimeout:                      conf.EtcdElectionTimeout,
 	})
 	if err != nil {
 		return err
@@ -561,7 +608,10 @@ func (c *Cluster) addKubectlProxy(ctx context.Context, env *env) (err error) {
 			return err
 		}
 
-		runtime.ApplyComponentPatches(ctx, &kubectlProxyComponent, env.kwokctlConfig.ComponentsPatches)
+		err = runtime.ApplyComponentPatches(ctx, &kubectlProxyComponent, env.kwokctlConfig.ComponentsPatches)
+		if err != nil {
+			return err
+		}
 
 		dashboardPod, err := yaml.Marshal(components.ConvertToPod(kubectlProxyComponent))
 		if err != nil {
@@ -640,6 +690,7 @@ func (c *Cluster) addKwokController(ctx context.Context, env *env) (err error) {
 		Version:                           kwokControllerVersion,
 		BindAddress:                       net.PublicAddress,
 		Port:                              conf.KwokControllerPort,
+		ProfilingPort:                     conf.KwokControllerProfilingPort,
 		ConfigPath:                        env.kwokConfigPath,
 		KubeconfigPath:                    env.inClusterOnHostKubeconfigPath,
 		CaCertPath:                        env.caCertPath,
@@ -650,11 +701,21 @@ func (c *Cluster) addKwokController(ctx context.Context, env *env) (err error) {
 		ManageNodesWithAnnotationSelector: "kwok.x-k8s.io/node=fake",
 		Verbosity:                         env.verbosity,
 		NodeLeaseDurationSeconds:          40,
+		ManageNodesParallelism:            conf.ManageNodesParallelism,
+		NodeCPUOvercommit:                 conf.NodeCPUOvercommit,
+		NodeMemoryOvercommit:              conf.NodeMemoryOvercommit,
 		EnableCRDs:                        conf.EnableCRDs,
+		PodDNSPolicy:                      conf.PodDNSPolicy,
+		PodDNSNameservers:                 conf.PodDNSNameservers,
+		PodDNSSearches:                    conf.PodDNSSearches,
+		PodDNSOptions:                     conf.PodDNSOptions,
 	})
 	kwokControllerComponent.Volumes = append(kwokControllerComponent.Volumes, logVolumes...)
 
-	runtime.ApplyComponentPatches(ctx, &kwokControllerComponent, env.kwokctlConfig.ComponentsPatches)
+	err = runtime.ApplyComponentPatches(ctx, &kwokControllerComponent, env.kwokctlConfig.ComponentsPatches)
+	if err != nil {
+		return err
+	}
 
 	pod := components.ConvertToPod(kwokControllerComponent)
 	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, corev1.EnvVar{
@@ -709,7 +770,10 @@ func (c *Cluster) addDashboard(ctx context.Context, env *env) (err error) {
 			return fmt.Errorf("failed to build dashboard component: %w", err)
 		}
 
-		runtime.ApplyComponentPatches(ctx, &dashboardComponent, env.kwokctlConfig.ComponentsPatches)
+		err = runtime.ApplyComponentPatches(ctx, &dashboardComponent, env.kwokctlConfig.ComponentsPatches)
+		if err != nil {
+			return err
+		}
 
 		dashboardPod, err := yaml.Marshal(components.ConvertToPod(dashboardComponent))
 		if err != nil {
@@ -861,7 +925,10 @@ func (c *Cluster) addPrometheus(ctx context.Context, env *env) (err error) {
 			},
 		)
 
-		runtime.ApplyComponentPatches(ctx, &prometheusComponent, env.kwokctlConfig.ComponentsPatches)
+		err = runtime.ApplyComponentPatches(ctx, &prometheusComponent, env.kwokctlConfig.ComponentsPatches)
+		if err != nil {
+			return err
+		}
 
 		prometheusPod, err := yaml.Marshal(components.ConvertToPod(prometheusComponent))
 		if err != nil {
@@ -904,7 +971,10 @@ func (c *Cluster) addJaeger(ctx context.Context, env *env) (err error) {
 			return err
 		}
 
-		runtime.ApplyComponentPatches(ctx, &jaegerComponent, env.kwokctlConfig.ComponentsPatches)
+		err = runtime.ApplyComponentPatches(ctx, &jaegerComponent, env.kwokctlConfig.ComponentsPatches)
+		if err != nil {
+			return err
+		}
 
 		jaegerPod, err := yaml.Marshal(components.ConvertToPod(jaegerComponent))
 		if err != nil {
@@ -1042,6 +1112,11 @@ func (c *Cluster) Up(ctx context.Context) error {
 		logger.Error("Failed to chmod pki", err)
 	}
 
+	err = c.StartAutoSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -1159,7 +1234,9 @@ func (c *Cluster) WaitReady(ctx context.Context, timeout time.Duration) error {
 	return nil
 }
 
-// InspectComponent returns the status of the component
+// InspectComponent returns the status of the component,
+// the components run as static pods in the kind node, so their readiness is reported by the kubelet
+// and the readiness checks of the components are not run.
 func (c *Cluster) InspectComponent(ctx context.Context, name string) (runtime.ComponentStatus, error) {
 	ready, running, _, err := c.inspectComponent(ctx, name)
 	if err != nil {
@@ -1201,6 +1278,11 @@ func (c *Cluster) Ready(ctx context.Context) (bool, error) {
 
 // Down stops the cluster
 func (c *Cluster) Down(ctx context.Context) error {
+	err := c.StopAutoSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+
 	kindPath, err := c.preDownloadKind(ctx)
 	if err != nil {
 		return err
@@ -1221,12 +1303,22 @@ func (c *Cluster) Start(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+
+	err = c.StartAutoSnapshot(ctx)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
 // Stop stops the cluster
 func (c *Cluster) Stop(ctx context.Context) error {
-	err := c.Exec(ctx, c.runtime, "stop", c.getClusterName())
+	err := c.StopAutoSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = c.Exec(ctx, c.runtime, "stop", c.getClusterName())
 	if err != nil {
 		return err
 	}
@@ -1387,14 +1479,8 @@ func (c *Cluster) getComponentName(name string) string {
 	return name + "-" + clusterName
 }
 
-func (c *Cluster) logs(ctx context.Context, name string, out io.Writer, follow bool) error {
-	componentName := c.getComponentName(name)
-
-	args := []string{"logs", "-n", "kube-system"}
-	if follow {
-		args = append(args, "-f")
-	}
-	args = append(args, componentName)
+func (c *Cluster) logs(ctx context.Context, name string, out io.Writer, follow bool, opts runtime.LogsOptions) error {
+	args := runtime.LogsArgs([]string{"logs", "-n", "kube-system"}, c.getComponentName(name), follow, opts)
 	if c.IsDryRun() && !follow {
 		if file, ok := dryrun.IsCatToFileWriter(out); ok {
 			dryrun.PrintMessage("%s >%s", runtime.FormatExec(ctx, name, args...), file)
@@ -1410,13 +1496,23 @@ func (c *Cluster) logs(ctx context.Context, name string, out io.Writer, follow b
 }
 
 // Logs returns the logs of the specified component.
-func (c *Cluster) Logs(ctx context.Context, name string, out io.Writer) error {
-	return c.logs(ctx, name, out, false)
+func (c *Cluster) Logs(ctx context.Context, name string, out io.Writer, opts runtime.LogsOptions) error {
+	return c.logs(ctx, name, out, false, opts)
 }
 
 // LogsFollow follows the logs of the component
-func (c *Cluster) LogsFollow(ctx context.Context, name string, out io.Writer) error {
-	return c.logs(ctx, name, out, true)
+func (c *Cluster) LogsFollow(ctx context.Context, name string, out io.Writer, opts runtime.LogsOptions) error {
+	return c.logs(ctx, name, out, true, opts)
+}
+
+// Attach attaches to the stdio of the component pod
+func (c *Cluster) Attach(ctx context.Context, name string) error {
+	_, err := c.GetComponent(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return c.Kubectl(exec.WithStdIO(ctx), "attach", "-n", "kube-system", c.getComponentName(name))
 }
 
 // CollectLogs returns the logs of the specified component.
@@ -1460,15 +1556,18 @@ func (c *Cluster) CollectLogs(ctx context.Context, dir string) error {
 		return err
 	}
 
+	var errs []error
 	for _, component := range conf.Components {
 		logPath := path.Join(componentsDir, component.Name+".log")
 		f, err := c.OpenFile(logPath)
 		if err != nil {
 			logger.Error("Failed to open file", err)
+			errs = append(errs, fmt.Errorf("failed to collect logs of %s: %w", component.Name, err))
 			continue
 		}
-		if err = c.Logs(ctx, component.Name, f); err != nil {
+		if err = c.Logs(ctx, component.Name, f, runtime.LogsOptions{}); err != nil {
 			logger.Error("Failed to get log", err)
+			errs = append(errs, fmt.Errorf("failed to collect logs of %s: %w", component.Name, err))
 			if err = f.Close(); err != nil {
 				logger.Error("Failed to close file", err)
 				if err = c.Remove(logPath); err != nil {
@@ -1489,9 +1588,11 @@ func (c *Cluster) CollectLogs(ctx context.Context, dir string) error {
 		f, err := c.OpenFile(filePath)
 		if err != nil {
 			logger.Error("Failed to open file", err)
+			errs = append(errs, fmt.Errorf("failed to collect audit logs: %w", err))
 		} else {
 			if err = c.AuditLogs(ctx, f); err != nil {
 				logger.Error("Failed to get audit log", err)
+				errs = append(errs, fmt.Errorf("failed to collect audit logs: %w", err))
 			}
 			if err = f.Close(); err != nil {
 				logger.Error("Failed to close file", err)
@@ -1502,7 +1603,7 @@ func (c *Cluster) CollectLogs(ctx context.Context, dir string) error {
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // ListBinaries list binaries in the cluster
@@ -1585,6 +1686,9 @@ func (c *Cluster) InitCRs(ctx context.Context) error {
 		if conf.EnableMetricsServer {
 			dryrun.PrintMessage("# Set up apiservice for metrics server")
 		}
+		if !runtime.PodDNSConfig(&conf).IsEmpty() {
+			dryrun.PrintMessage("# Set up mutating webhook for pod dns")
+		}
 
 		return nil
 	}
@@ -1602,6 +1706,15 @@ func (c *Cluster) InitCRs(ctx context.Context) error {
 		_, _ = buf.WriteString("---\n")
 	}
 
+	if !runtime.PodDNSConfig(&conf).IsEmpty() {
+		webhook, err := c.BuildPodDNSWebhook(net.LocalAddress, 10247)
+		if err != nil {
+			return err
+		}
+		_, _ = buf.WriteString(webhook)
+		_, _ = buf.WriteString("---\n")
+	}
+
 	if buf.Len() == 0 {
 		return nil
 	}
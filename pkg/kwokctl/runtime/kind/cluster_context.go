@@ -18,12 +18,14 @@ package kind
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"net/url"
 
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 
 	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
+	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
 	"sigs.k8s.io/kwok/pkg/utils/format"
 	"sigs.k8s.io/kwok/pkg/utils/kubeconfig"
 	utilsnet "sigs.k8s.io/kwok/pkg/utils/net"
@@ -53,9 +55,16 @@ func (c *Cluster) AddContext(ctx context.Context, kubeconfigPath string) error {
 			Server: "http://" + utilsnet.LocalAddress + ":" + format.String(conf.KubeApiserverInsecurePort),
 		}
 	} else {
-		kubeConfig.Context = &clientcmdapi.Context{
-			Cluster:  "kind-" + c.Name(),
-			AuthInfo: "kind-" + c.Name(),
+		// The kubeconfig exported by kind uses the kind-prefixed names,
+		// which may clash with clusters created by kind itself,
+		// so copy them to the kwok-prefixed context.
+		kindConfig, err := kubeconfig.LoadFromFile(c.GetWorkdirPath(runtime.InHostKubeconfigName))
+		if err != nil {
+			return err
+		}
+		kubeConfig, err = kwokContextFromKind(c.Name(), kindConfig)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -66,6 +75,31 @@ func (c *Cluster) AddContext(ctx context.Context, kubeconfigPath string) error {
 	return nil
 }
 
+// kwokContextFromKind returns the context named by the cluster name from the kubeconfig exported by kind.
+func kwokContextFromKind(name string, kindConfig *clientcmdapi.Config) (*kubeconfig.Config, error) {
+	kindContext, ok := kindConfig.Contexts[kindConfig.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("context %q not found in kind kubeconfig", kindConfig.CurrentContext)
+	}
+	cluster, ok := kindConfig.Clusters[kindContext.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("cluster %q not found in kind kubeconfig", kindContext.Cluster)
+	}
+	user, ok := kindConfig.AuthInfos[kindContext.AuthInfo]
+	if !ok {
+		return nil, fmt.Errorf("user %q not found in kind kubeconfig", kindContext.AuthInfo)
+	}
+
+	return &kubeconfig.Config{
+		Cluster: cluster.DeepCopy(),
+		User:    user.DeepCopy(),
+		Context: &clientcmdapi.Context{
+			Cluster:  name,
+			AuthInfo: name,
+		},
+	}, nil
+}
+
 // RemoveContext remove the context of cluster from kubeconfig
 func (c *Cluster) RemoveContext(ctx context.Context, kubeconfigPath string) error {
 	if c.IsDryRun() {
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kind
+
+import (
+	"path/filepath"
+	"testing"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"sigs.k8s.io/kwok/pkg/utils/kubeconfig"
+)
+
+func TestKwokContextFromKind(t *testing.T) {
+	kindConfig := &clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"kind-kwok-kwok": {Server: "https://127.0.0.1:6443"},
+		},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			"kind-kwok-kwok": {Token: "token"},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			"kind-kwok-kwok": {Cluster: "kind-kwok-kwok", AuthInfo: "kind-kwok-kwok"},
+		},
+		CurrentContext: "kind-kwok-kwok",
+	}
+
+	conf, err := kwokContextFromKind("kwok-kwok", kindConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A cluster created by kind itself with the same name as the kind-prefixed one
+	// must not be affected by the kwok context.
+	kubeconfigPath := filepath.Join(t.TempDir(), "config")
+	err = kubeconfig.AddContext(kubeconfigPath, "kind-kwok-kwok", &kubeconfig.Config{
+		Cluster: &clientcmdapi.Cluster{Server: "https://127.0.0.1:7443"},
+		User:    &clientcmdapi.AuthInfo{Token: "other"},
+		Context: &clientcmdapi.Context{Cluster: "kind-kwok-kwok", AuthInfo: "kind-kwok-kwok"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = kubeconfig.AddContext(kubeconfigPath, "kwok-kwok", conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := kubeconfig.LoadFromFile(kubeconfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.CurrentContext != "kwok-kwok" {
+		t.Errorf("want current-context kwok-kwok, got %q", got.CurrentContext)
+	}
+	ctx := got.Contexts["kwok-kwok"]
+	if ctx == nil || ctx.Cluster != "kwok-kwok" || ctx.AuthInfo != "kwok-kwok" {
+		t.Errorf("want context kwok-kwok to refer to the kwok-prefixed entries, got %#v", ctx)
+	}
+	if server := got.Clusters["kwok-kwok"].Server; server != "https://127.0.0.1:6443" {
+		t.Errorf("want kwok cluster server https://127.0.0.1:6443, got %q", server)
+	}
+	if server := got.Clusters["kind-kwok-kwok"].Server; server != "https://127.0.0.1:7443" {
+		t.Errorf("want kind cluster server unchanged, got %q", server)
+	}
+
+	_, err = kwokContextFromKind("kwok-kwok", &clientcmdapi.Config{CurrentContext: "missing"})
+	if err == nil {
+		t.Errorf("want error for missing context")
+	}
+}
@@ -19,6 +19,7 @@ package kind
 import (
 	"context"
 	"crypto/tls"
+	"io"
 
 	"sigs.k8s.io/kwok/pkg/consts"
 	"sigs.k8s.io/kwok/pkg/kwokctl/etcd"
@@ -113,6 +114,16 @@ func (c *Cluster) SnapshotRestore(ctx context.Context, path string) error {
 	return nil
 }
 
+// SnapshotSaveToWriter save the snapshot of cluster to the writer
+func (c *Cluster) SnapshotSaveToWriter(ctx context.Context, w io.Writer) error {
+	return c.Cluster.SnapshotSaveToWriter(ctx, w, c.SnapshotSave)
+}
+
+// SnapshotRestoreFromReader restore the snapshot of cluster from the reader
+func (c *Cluster) SnapshotRestoreFromReader(ctx context.Context, r io.Reader) error {
+	return c.Cluster.SnapshotRestoreFromReader(ctx, r, c.SnapshotRestore)
+}
+
 // SnapshotSaveWithYAML save the snapshot of cluster
 func (c *Cluster) SnapshotSaveWithYAML(ctx context.Context, path string, conf runtime.SnapshotSaveWithYAMLConfig) error {
 	err := c.Cluster.SnapshotSaveWithYAML(ctx, path, conf)
@@ -27,4 +27,7 @@ func init() {
 	runtime.DefaultRegistry.Register(consts.RuntimeTypeKindNerdctl, NewNerdctlCluster)
 	runtime.DefaultRegistry.Register(consts.RuntimeTypeKindLima, NewLimaCluster)
 	runtime.DefaultRegistry.Register(consts.RuntimeTypeKindFinch, NewFinchCluster)
+
+	runtime.DefaultRegistry.RegisterAlias(consts.RuntimeTypeKind+"-docker", consts.RuntimeTypeKind)
+	runtime.DefaultRegistry.RegisterAlias(consts.RuntimeTypeKind+"-containerd", consts.RuntimeTypeKindNerdctl)
 }
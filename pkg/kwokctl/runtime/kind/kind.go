@@ -27,11 +27,14 @@ import (
 
 	"sigs.k8s.io/kwok/pkg/apis/internalversion"
 	"sigs.k8s.io/kwok/pkg/consts"
+	"sigs.k8s.io/kwok/pkg/kwokctl/components"
+	"sigs.k8s.io/kwok/pkg/kwokctl/k8s"
 	"sigs.k8s.io/kwok/pkg/kwokctl/runtime"
 	kindv1alpha4 "sigs.k8s.io/kwok/pkg/kwokctl/runtime/kind/config/kind/v1alpha4"
 	kubeadmv1beta3 "sigs.k8s.io/kwok/pkg/kwokctl/runtime/kind/config/kubeadm/v1beta3"
 	"sigs.k8s.io/kwok/pkg/log"
 	"sigs.k8s.io/kwok/pkg/utils/format"
+	"sigs.k8s.io/kwok/pkg/utils/net"
 	"sigs.k8s.io/kwok/pkg/utils/version"
 	"sigs.k8s.io/kwok/pkg/utils/yaml"
 )
@@ -39,6 +42,14 @@ import (
 // BuildKind builds the kind yaml content.
 func BuildKind(conf BuildKindConfig) (string, error) {
 	var err error
+	if conf.ServiceClusterIPRange != "" {
+		cidrs, err := net.ParseDualStackCIDRs(conf.ServiceClusterIPRange)
+		if err != nil {
+			return "", fmt.Errorf("invalid service cluster ip range %q: %w", conf.ServiceClusterIPRange, err)
+		}
+		conf.ServiceClusterIPRange = net.JoinCIDRs(cidrs)
+	}
+
 	conf, err = expendExtrasForBuildKind(conf)
 	if err != nil {
 		return "", fmt.Errorf("failed to expand extras for build kind: %w", err)
@@ -106,6 +117,24 @@ func expendExtrasForBuildKind(conf BuildKindConfig) (BuildKindConfig, error) {
 				},
 			)
 		}
+
+		if conf.AuditWebhook != "" {
+			conf.ApiserverExtraArgs = append(conf.ApiserverExtraArgs,
+				internalversion.ExtraArgs{
+					Key:   "audit-webhook-config-file",
+					Value: "/etc/kubernetes/audit/audit-webhook.yaml",
+				},
+			)
+			conf.ApiserverExtraVolumes = append(conf.ApiserverExtraVolumes,
+				internalversion.Volume{
+					Name:      "audit-webhook-config-file",
+					HostPath:  conf.AuditWebhook,
+					MountPath: "/etc/kubernetes/audit/audit-webhook.yaml",
+					ReadOnly:  true,
+					PathType:  internalversion.HostPathFile,
+				},
+			)
+		}
 	}
 
 	if conf.SchedulerConfig != "" {
@@ -179,26 +208,37 @@ func expendExtrasForBuildKind(conf BuildKindConfig) (BuildKindConfig, error) {
 		)
 	}
 
-	if conf.DisableQPSLimits {
-		conf.ApiserverExtraArgs = append(conf.ApiserverExtraArgs,
-			internalversion.ExtraArgs{
-				Key:   "max-requests-inflight",
-				Value: "0",
-			},
-			internalversion.ExtraArgs{
-				Key:   "max-mutating-requests-inflight",
-				Value: "0",
-			},
-		)
+	apfFeatureGates, apfArgs, err := components.KubeApiserverPriorityAndFairnessArgs(conf.KubeVersion, strings.Join(conf.FeatureGates, ","),
+		conf.KubeApiserverPriorityAndFairness, conf.KubeApiserverMaxRequestsInflight, conf.KubeApiserverMaxMutatingRequestsInflight)
+	if err != nil {
+		return conf, err
+	}
+	conf.FeatureGates = append(conf.FeatureGates, apfFeatureGates...)
+	conf.ApiserverExtraArgs = append(conf.ApiserverExtraArgs, apfArgs...)
 
-		// FeatureGate APIPriorityAndFairness is not available before 1.17.0
-		if conf.KubeVersion.GE(version.NewVersion(1, 18, 0)) {
+	if conf.DisableQPSLimits {
+		// API Priority and Fairness takes over the limits of kube-apiserver
+		if !conf.KubeApiserverPriorityAndFairness {
 			conf.ApiserverExtraArgs = append(conf.ApiserverExtraArgs,
 				internalversion.ExtraArgs{
-					Key:   "enable-priority-and-fairness",
-					Value: "false",
+					Key:   "max-requests-inflight",
+					Value: "0",
+				},
+				internalversion.ExtraArgs{
+					Key:   "max-mutating-requests-inflight",
+					Value: "0",
 				},
 			)
+
+			// FeatureGate APIPriorityAndFairness is not available before 1.17.0
+			if conf.KubeVersion.GE(version.NewVersion(1, 18, 0)) {
+				conf.ApiserverExtraArgs = append(conf.ApiserverExtraArgs,
+					internalversion.ExtraArgs{
+						Key:   "enable-priority-and-fairness",
+						Value: "false",
+					},
+				)
+			}
 		}
 		conf.ControllerManagerExtraArgs = append(conf.ControllerManagerExtraArgs,
 			internalversion.ExtraArgs{
@@ -222,6 +262,18 @@ func expendExtrasForBuildKind(conf BuildKindConfig) (BuildKindConfig, error) {
 		)
 	}
 
+	tlsArgs, err := components.KubeApiserverTLSArgs(conf.KubeApiserverTLSMinVersion, conf.KubeApiserverTLSCipherSuites)
+	if err != nil {
+		return conf, err
+	}
+	conf.ApiserverExtraArgs = append(conf.ApiserverExtraArgs, tlsArgs...)
+
+	timeoutArgs, err := components.KubeApiserverRequestTimeoutArgs(conf.KubeApiserverRequestTimeout, conf.KubeApiserverMinRequestTimeout)
+	if err != nil {
+		return conf, err
+	}
+	conf.ApiserverExtraArgs = append(conf.ApiserverExtraArgs, timeoutArgs...)
+
 	if conf.EtcdQuotaBackendSize != "" {
 		quantity, err := resource.ParseQuantity(conf.EtcdQuotaBackendSize)
 		if err != nil {
@@ -233,14 +285,39 @@ func expendExtrasForBuildKind(conf BuildKindConfig) (BuildKindConfig, error) {
 			return conf, fmt.Errorf("failed to convert quota backend size to int64")
 		}
 
+		if etcdQuotaBackendSize != 0 {
+			conf.EtcdExtraArgs = append(conf.EtcdExtraArgs,
+				internalversion.ExtraArgs{
+					Key:   "quota-backend-bytes",
+					Value: strconv.FormatInt(etcdQuotaBackendSize, 10),
+				},
+			)
+		}
+	}
+
+	if conf.EtcdUnsafeNoFsync {
+		// The etcd of kind is the one bundled in the node image, so it follows the kube version.
+		etcdVersion, err := version.ParseVersion(k8s.GetEtcdVersion(int(conf.KubeVersion.Minor)))
+		if err != nil {
+			return conf, fmt.Errorf("failed to parse etcd version: %w", err)
+		}
+		if etcdVersion.LT(version.NewVersion(3, 5, 0)) {
+			return conf, fmt.Errorf("unsafe no fsync requires etcd 3.5 or later, got %s", etcdVersion)
+		}
 		conf.EtcdExtraArgs = append(conf.EtcdExtraArgs,
 			internalversion.ExtraArgs{
-				Key:   "quota-backend-bytes",
-				Value: strconv.FormatInt(etcdQuotaBackendSize, 10),
+				Key:   "unsafe-no-fsync",
+				Value: "true",
 			},
 		)
 	}
 
+	etcdTimeoutArgs, _, err := components.EtcdTimeoutArgs(conf.EtcdHeartbeatInterval, conf.EtcdElectionTimeout)
+	if err != nil {
+		return conf, err
+	}
+	conf.EtcdExtraArgs = append(conf.EtcdExtraArgs, etcdTimeoutArgs...)
+
 	return conf, nil
 }
 
@@ -281,19 +358,23 @@ func expandHostVolumePaths(conf BuildKindConfig) (BuildKindConfig, error) {
 
 // BuildKindConfig is the configuration for building the kind config
 type BuildKindConfig struct {
-	KubeApiserverPort         uint32
-	KubeApiserverInsecurePort uint32
-	EtcdPort                  uint32
-	DashboardPort             uint32
-	PrometheusPort            uint32
-	JaegerPort                uint32
-	KwokControllerPort        uint32
+	KubeApiserverPort           uint32
+	KubeApiserverInsecurePort   uint32
+	EtcdPort                    uint32
+	DashboardPort               uint32
+	PrometheusPort              uint32
+	JaegerPort                  uint32
+	KwokControllerPort          uint32
+	KwokControllerProfilingPort uint32
 
 	RuntimeConfig []string
 	FeatureGates  []string
 
-	AuditPolicy string
-	AuditLog    string
+	ServiceClusterIPRange string
+
+	AuditPolicy  string
+	AuditLog     string
+	AuditWebhook string
 
 	KubeconfigPath    string
 	SchedulerConfig   string
@@ -316,6 +397,19 @@ type BuildKindConfig struct {
 	DisableQPSLimits     bool
 	KubeVersion          version.Version
 	EtcdQuotaBackendSize string
+
+	KubeApiserverTLSMinVersion   string
+	KubeApiserverTLSCipherSuites string
+
+	KubeApiserverRequestTimeout    string
+	KubeApiserverMinRequestTimeout string
+	EtcdUnsafeNoFsync              bool
+	EtcdHeartbeatInterval          string
+	EtcdElectionTimeout            string
+
+	KubeApiserverPriorityAndFairness         bool
+	KubeApiserverMaxRequestsInflight         uint32
+	KubeApiserverMaxMutatingRequestsInflight uint32
 }
 
 func buildKindConfigV1alpha4(conf BuildKindConfig) (*kindv1alpha4.Cluster, error) {
@@ -361,6 +455,14 @@ func buildKindConfigV1alpha4(conf BuildKindConfig) (*kindv1alpha4.Cluster, error
 		})
 	}
 
+	if conf.KwokControllerProfilingPort != 0 {
+		extraPortMappings = append(extraPortMappings, kindv1alpha4.PortMapping{
+			ContainerPort: 10246,
+			HostPort:      int32(conf.KwokControllerProfilingPort),
+			Protocol:      kindv1alpha4.PortMappingProtocolTCP,
+		})
+	}
+
 	if conf.EtcdPort != 0 {
 		extraPortMappings = append(extraPortMappings, kindv1alpha4.PortMapping{
 			ContainerPort: 2379,
@@ -462,6 +564,7 @@ func buildKindConfigV1alpha4(conf BuildKindConfig) (*kindv1alpha4.Cluster, error
 
 		Networking: kindv1alpha4.Networking{
 			APIServerPort: int32(conf.KubeApiserverPort),
+			ServiceSubnet: conf.ServiceClusterIPRange,
 		},
 
 		Nodes: []kindv1alpha4.Node{
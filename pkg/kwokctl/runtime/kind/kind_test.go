@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kind
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kwok/pkg/utils/version"
+)
+
+func TestBuildKindEtcdUnsafeNoFsync(t *testing.T) {
+	tests := []struct {
+		name        string
+		kubeVersion version.Version
+		wantErr     bool
+	}{
+		{
+			name:        "etcd 3.5",
+			kubeVersion: version.NewVersion(1, 29, 0),
+		},
+		{
+			name:        "etcd 3.4",
+			kubeVersion: version.NewVersion(1, 21, 0),
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildKind(BuildKindConfig{
+				KubeVersion:       tt.kubeVersion,
+				EtcdUnsafeNoFsync: true,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BuildKind() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !strings.Contains(got, "unsafe-no-fsync") {
+				t.Errorf("BuildKind() = %s, want unsafe-no-fsync", got)
+			}
+		})
+	}
+}
@@ -43,7 +43,7 @@ func ListClusters(ctx context.Context) ([]string, error) {
 	ret := make([]string, 0, len(entries))
 	for _, entry := range entries {
 		name := entry.Name()
-		if !entry.IsDir() {
+		if !isDirEntry(workdir, entry) {
 			logger.Warn("Found non-directory entry in clusters directory, please remove it", "path", path.Join(workdir, name))
 			continue
 		}
@@ -73,7 +73,7 @@ func GetUsedPorts(ctx context.Context) (rets sets.Sets[uint32]) {
 
 	for _, entry := range entries {
 		name := entry.Name()
-		if !entry.IsDir() {
+		if !isDirEntry(workdir, entry) {
 			logger.Warn("Found non-directory entry in clusters directory, please remove it", "path", path.Join(workdir, name))
 			continue
 		}
@@ -105,3 +105,19 @@ func GetUsedPorts(ctx context.Context) (rets sets.Sets[uint32]) {
 	}
 	return rets
 }
+
+// isDirEntry returns true if the entry is a directory or a link to a directory,
+// the clusters created with a custom workdir are links to it.
+func isDirEntry(dir string, entry os.DirEntry) bool {
+	if entry.IsDir() {
+		return true
+	}
+	if entry.Type()&os.ModeSymlink == 0 {
+		return false
+	}
+	fi, err := os.Stat(path.Join(dir, entry.Name()))
+	if err != nil {
+		return false
+	}
+	return fi.IsDir()
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/file"
+	"sigs.k8s.io/kwok/pkg/utils/format"
+	utilsnet "sigs.k8s.io/kwok/pkg/utils/net"
+	"sigs.k8s.io/kwok/pkg/utils/path"
+	"sigs.k8s.io/kwok/pkg/utils/slices"
+)
+
+// MetricsName is the name of the directory in the workdir to save the metrics of components
+const MetricsName = "metrics"
+
+// SaveMetrics scrapes the metrics endpoint of each component once
+// and writes them to the dir, one file per component.
+// Components that fail to be scraped are skipped.
+func SaveMetrics(ctx context.Context, rt Runtime, dir string) error {
+	components, err := rt.ListComponents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !rt.IsDryRun() {
+		err = file.MkdirAll(dir)
+		if err != nil {
+			return err
+		}
+	}
+
+	logger := log.FromContext(ctx)
+	for _, component := range components {
+		if component.Metric == nil {
+			continue
+		}
+
+		metricsPath := path.Join(dir, component.Name+".prom")
+		if rt.IsDryRun() {
+			dryrun.PrintMessage("curl %s://%s%s >%s", component.Metric.Scheme, component.Metric.Host, component.Metric.Path, metricsPath)
+			continue
+		}
+
+		data, err := scrapeComponentMetrics(ctx, rt, component)
+		if err != nil {
+			logger.Warn("Failed to scrape metrics",
+				"component", component.Name,
+				"err", err,
+			)
+			continue
+		}
+
+		err = file.Write(metricsPath, data)
+		if err != nil {
+			return err
+		}
+		logger.Debug("Saved metrics",
+			"component", component.Name,
+			"path", metricsPath,
+		)
+	}
+	return nil
+}
+
+func scrapeComponentMetrics(ctx context.Context, rt Runtime, component internalversion.Component) ([]byte, error) {
+	metric := component.Metric
+	statusCode, data, err := getFromComponent(ctx, rt, component, componentEndpoint{
+		Scheme:             metric.Scheme,
+		Host:               metric.Host,
+		Path:               metric.Path,
+		CertPath:           metric.CertPath,
+		KeyPath:            metric.KeyPath,
+		InsecureSkipVerify: metric.InsecureSkipVerify,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", statusCode)
+	}
+	return data, nil
+}
+
+// componentEndpoint is an HTTP endpoint served by a component
+type componentEndpoint struct {
+	Scheme             string
+	Host               string
+	Path               string
+	CertPath           string
+	KeyPath            string
+	InsecureSkipVerify bool
+}
+
+// getFromComponent sends a GET request to the endpoint of the component through a forwarded port,
+// and returns the status code and the body of the response.
+func getFromComponent(ctx context.Context, rt Runtime, component internalversion.Component, endpoint componentEndpoint) (int, []byte, error) {
+	_, port, err := net.SplitHostPort(endpoint.Host)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	hostPort, err := utilsnet.GetUnusedPort(ctx, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	cancel, err := rt.PortForward(ctx, component.Name, port, hostPort)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer cancel()
+
+	tlsConfig := &tls.Config{
+		//nolint:gosec
+		InsecureSkipVerify: endpoint.InsecureSkipVerify,
+	}
+	if endpoint.CertPath != "" && endpoint.KeyPath != "" {
+		cert, err := tls.LoadX509KeyPair(
+			hostPathInComponent(component, endpoint.CertPath),
+			hostPathInComponent(component, endpoint.KeyPath),
+		)
+		if err != nil {
+			return 0, nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	cli := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+	}
+
+	url := endpoint.Scheme + "://" + utilsnet.LocalAddress + ":" + format.String(hostPort) + endpoint.Path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	resp, err := cli.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, data, nil
+}
+
+// hostPathInComponent returns the path on the host of the path in the component,
+// the path is returned as is if it is not mounted from the host.
+func hostPathInComponent(component internalversion.Component, p string) string {
+	volume, ok := slices.Find(component.Volumes, func(v internalversion.Volume) bool {
+		return v.MountPath == p
+	})
+	if !ok || volume.HostPath == "" {
+		return p
+	}
+	return volume.HostPath
+}
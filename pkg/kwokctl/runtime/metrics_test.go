@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+)
+
+func TestHostPathInComponent(t *testing.T) {
+	component := internalversion.Component{
+		Volumes: []internalversion.Volume{
+			{
+				HostPath:  "/workdir/pki/admin.crt",
+				MountPath: "/etc/kubernetes/pki/admin.crt",
+			},
+		},
+	}
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "mounted",
+			path: "/etc/kubernetes/pki/admin.crt",
+			want: "/workdir/pki/admin.crt",
+		},
+		{
+			name: "not mounted",
+			path: "/workdir/pki/admin.key",
+			want: "/workdir/pki/admin.key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hostPathInComponent(component, tt.path); got != tt.want {
+				t.Errorf("hostPathInComponent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/kwokctl/components"
+	"sigs.k8s.io/kwok/pkg/utils/format"
+	"sigs.k8s.io/kwok/pkg/utils/poddns"
+)
+
+// PodDNSConfig returns the DNS settings set by the kwok-controller on the created pods.
+func PodDNSConfig(conf *internalversion.KwokctlConfigurationOptions) poddns.Config {
+	return poddns.Config{
+		Policy:      conf.PodDNSPolicy,
+		Nameservers: conf.PodDNSNameservers,
+		Searches:    conf.PodDNSSearches,
+		Options:     conf.PodDNSOptions,
+	}
+}
+
+// BuildPodDNSWebhook builds the mutating webhook configuration of the pod DNS,
+// the host and port are where kube-apiserver reaches the kwok-controller.
+func (c *Cluster) BuildPodDNSWebhook(host string, port uint32) (string, error) {
+	caCert, err := os.ReadFile(path.Join(c.GetWorkdirPath(PkiName), "ca.crt"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read ca cert: %w", err)
+	}
+	return components.BuildPodDNSWebhook(components.BuildPodDNSWebhookConfig{
+		Address: net.JoinHostPort(host, format.String(port)),
+		CACert:  caCert,
+	})
+}
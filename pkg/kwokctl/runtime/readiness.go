@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/exec"
+)
+
+// ComponentReady runs the readiness check of the component,
+// the component is considered ready if it has no readiness check.
+func ComponentReady(ctx context.Context, rt Runtime, component internalversion.Component) (bool, error) {
+	readiness := component.Readiness
+	if readiness == nil {
+		return true, nil
+	}
+
+	logger := log.FromContext(ctx)
+	if readiness.Host == "" {
+		if len(readiness.Command) == 0 {
+			return false, fmt.Errorf("readiness of component %s has neither host nor command", component.Name)
+		}
+		out := bytes.NewBuffer(nil)
+		err := exec.Exec(exec.WithAllWriteTo(ctx, out), readiness.Command[0], readiness.Command[1:]...)
+		if err != nil {
+			logger.Debug("Check component readiness",
+				"component", component.Name,
+				"command", readiness.Command,
+				"output", out,
+				"err", err,
+			)
+			return false, nil
+		}
+		return true, nil
+	}
+
+	scheme := readiness.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	statusCode, data, err := getFromComponent(ctx, rt, component, componentEndpoint{
+		Scheme:             scheme,
+		Host:               readiness.Host,
+		Path:               readiness.Path,
+		CertPath:           readiness.CertPath,
+		KeyPath:            readiness.KeyPath,
+		InsecureSkipVerify: readiness.InsecureSkipVerify,
+	})
+	if err != nil {
+		return false, err
+	}
+	if statusCode < http.StatusOK || statusCode >= http.StatusBadRequest {
+		logger.Debug("Check component readiness",
+			"component", component.Name,
+			"path", readiness.Path,
+			"status", statusCode,
+			"response", string(data),
+		)
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+)
+
+func TestComponentReady(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	tests := []struct {
+		name      string
+		readiness *internalversion.ComponentReadiness
+		want      bool
+		wantErr   bool
+	}{
+		{
+			name: "No readiness check",
+			want: true,
+		},
+		{
+			name: "Command succeeded",
+			readiness: &internalversion.ComponentReadiness{
+				Command: []string{"sh", "-c", "exit 0"},
+			},
+			want: true,
+		},
+		{
+			name: "Command failed",
+			readiness: &internalversion.ComponentReadiness{
+				Command: []string{"sh", "-c", "exit 1"},
+			},
+			want: false,
+		},
+		{
+			name:      "Neither host nor command",
+			readiness: &internalversion.ComponentReadiness{},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component := internalversion.Component{
+				Name:      "component",
+				Readiness: tt.readiness,
+			}
+			got, err := ComponentReady(context.Background(), nil, component)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ComponentReady() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ComponentReady() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -20,6 +20,9 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
+
+	"sigs.k8s.io/kwok/pkg/config"
 )
 
 // BuildRuntime is a function to build a runtime
@@ -28,15 +31,21 @@ type BuildRuntime func(name, workdir string) (Runtime, error)
 // DefaultRegistry is the default registry
 var DefaultRegistry = NewRegistry()
 
+func init() {
+	config.ResolveRuntime = DefaultRegistry.Resolve
+}
+
 // Registry is a registry of runtime
 type Registry struct {
-	items map[string]BuildRuntime
+	items   map[string]BuildRuntime
+	aliases map[string]string
 }
 
 // NewRegistry create a new registry
 func NewRegistry() *Registry {
 	return &Registry{
-		items: map[string]BuildRuntime{},
+		items:   map[string]BuildRuntime{},
+		aliases: map[string]string{},
 	}
 }
 
@@ -45,8 +54,32 @@ func (r *Registry) Register(name string, buildRuntime BuildRuntime) {
 	r.items[name] = buildRuntime
 }
 
-// Get a runtime
+// RegisterAlias registers a friendly name for a runtime,
+// the runtime does not need to be registered yet.
+func (r *Registry) RegisterAlias(alias, name string) {
+	r.aliases[alias] = name
+}
+
+// Resolve returns the registered name of the runtime, which may be given by an alias
+func (r *Registry) Resolve(name string) (string, error) {
+	if _, ok := r.items[name]; ok {
+		return name, nil
+	}
+	if target, ok := r.aliases[name]; ok {
+		if _, ok := r.items[target]; ok {
+			return target, nil
+		}
+	}
+	return "", fmt.Errorf("runtime %q not found, valid runtimes are %s",
+		name, strings.Join(r.ListWithAliases(), ", "))
+}
+
+// Get a runtime, the name may be an alias
 func (r *Registry) Get(name string) (BuildRuntime, bool) {
+	name, err := r.Resolve(name)
+	if err != nil {
+		return nil, false
+	}
 	buildRuntime, ok := r.items[name]
 	return buildRuntime, ok
 }
@@ -76,3 +109,16 @@ func (r *Registry) List() []string {
 	sort.Strings(items)
 	return items
 }
+
+// ListWithAliases lists all registered runtime and the aliases of them
+func (r *Registry) ListWithAliases() []string {
+	items := r.List()
+	for alias, name := range r.aliases {
+		if _, ok := r.items[name]; !ok {
+			continue
+		}
+		items = append(items, fmt.Sprintf("%s (%s)", alias, name))
+	}
+	sort.Strings(items)
+	return items
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistryResolve(t *testing.T) {
+	build := func(name, workdir string) (Runtime, error) {
+		return nil, nil
+	}
+	r := NewRegistry()
+	r.Register("docker", build)
+	r.Register("nerdctl", build)
+	r.RegisterAlias("compose", "docker")
+	r.RegisterAlias("containerd", "nerdctl")
+	r.RegisterAlias("dangling", "missing")
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "registered name",
+			input: "docker",
+			want:  "docker",
+		},
+		{
+			name:  "alias",
+			input: "compose",
+			want:  "docker",
+		},
+		{
+			name:  "another alias",
+			input: "containerd",
+			want:  "nerdctl",
+		},
+		{
+			name:    "unknown name",
+			input:   "unknown",
+			wantErr: true,
+		},
+		{
+			name:    "alias to unregistered runtime",
+			input:   "dangling",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Resolve(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				for _, valid := range []string{"docker", "nerdctl", "compose (docker)", "containerd (nerdctl)"} {
+					if !strings.Contains(err.Error(), valid) {
+						t.Errorf("Resolve() error %q should list %q", err, valid)
+					}
+				}
+				if strings.Contains(err.Error(), "dangling (") {
+					t.Errorf("Resolve() error %q should not list the dangling alias", err)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Resolve() got %q, want %q", got, tt.want)
+			}
+			if _, ok := r.Get(tt.input); !ok {
+				t.Errorf("Get(%q) should find the runtime", tt.input)
+			}
+		})
+	}
+}
@@ -19,10 +19,13 @@ package runtime
 import (
 	"context"
 	"fmt"
+	"net"
 	"sort"
+	"strconv"
 	"strings"
 
 	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"sigs.k8s.io/kwok/pkg/apis/internalversion"
 	"sigs.k8s.io/kwok/pkg/config"
@@ -105,19 +108,49 @@ func GetComponentPatches(conf *internalversion.KwokctlConfiguration, componentNa
 }
 
 // ApplyComponentPatches applies patches to a component.
-func ApplyComponentPatches(ctx context.Context, component *internalversion.Component, patches []internalversion.ComponentPatches) {
+func ApplyComponentPatches(ctx context.Context, component *internalversion.Component, patches []internalversion.ComponentPatches) error {
 	for _, patch := range patches {
-		applyComponentPatch(ctx, component, patch)
+		err := applyComponentPatch(ctx, component, patch)
+		if err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func applyComponentPatch(ctx context.Context, component *internalversion.Component, patch internalversion.ComponentPatches) {
+func applyComponentPatch(ctx context.Context, component *internalversion.Component, patch internalversion.ComponentPatches) error {
 	if patch.Name != component.Name {
-		return
+		return nil
+	}
+
+	err := validateExtraVolumes(component, patch.ExtraVolumes)
+	if err != nil {
+		return err
 	}
 
 	component.Volumes = append(component.Volumes, patch.ExtraVolumes...)
 	component.Envs = append(component.Envs, patch.ExtraEnvs...)
+	if patch.Readiness != nil {
+		component.Readiness = patch.Readiness
+	}
+	if patch.RestartPolicy != "" {
+		switch patch.RestartPolicy {
+		case internalversion.RestartPolicyAlways, internalversion.RestartPolicyOnFailure, internalversion.RestartPolicyNo:
+		default:
+			return fmt.Errorf("invalid restart policy %q of component %q, must be one of %s, %s or %s", patch.RestartPolicy, component.Name,
+				internalversion.RestartPolicyAlways, internalversion.RestartPolicyOnFailure, internalversion.RestartPolicyNo)
+		}
+		component.RestartPolicy = patch.RestartPolicy
+	}
+	if len(patch.CommandOverride) != 0 {
+		logger := log.FromContext(ctx)
+		logger.Warn("The command of the component is overridden, only use it for debugging",
+			"component", component.Name,
+			"command", component.Command,
+			"override", patch.CommandOverride,
+		)
+		component.Command = patch.CommandOverride
+	}
 	for _, a := range patch.ExtraArgs {
 		if a.Override {
 			component.Args = applyComponentArgsOverride(ctx, component.Args, a)
@@ -125,6 +158,30 @@ func applyComponentPatch(ctx context.Context, component *internalversion.Compone
 			component.Args = append(component.Args, fmt.Sprintf("--%s=%s", a.Key, a.Value))
 		}
 	}
+	return nil
+}
+
+// validateExtraVolumes checks that the extra volumes do not shadow the volumes mounted by kwokctl,
+// which would break the component with a confusing error, e.g. mounting over /etc/kubernetes/pki.
+func validateExtraVolumes(component *internalversion.Component, extraVolumes []internalversion.Volume) error {
+	for _, extra := range extraVolumes {
+		if extra.MountPath == "" {
+			continue
+		}
+		extraMountPath := path.Clean(extra.MountPath)
+		for _, v := range component.Volumes {
+			if v.MountPath == "" {
+				continue
+			}
+			mountPath := path.Clean(v.MountPath)
+			if mountPath == extraMountPath ||
+				strings.HasPrefix(mountPath, strings.TrimSuffix(extraMountPath, "/")+"/") {
+				return fmt.Errorf("the extra volume %q of component %s shadows the required mount %q, please mount it to another path",
+					extra.MountPath, component.Name, v.MountPath)
+			}
+		}
+	}
+	return nil
 }
 
 func applyComponentArgsOverride(ctx context.Context, args []string, a internalversion.ExtraArgs) []string {
@@ -206,3 +263,21 @@ func GetLogVolumes(ctx context.Context) []internalversion.Volume {
 	}
 	return volumes
 }
+
+// ValidateInClusterAddress validates the address that the components use to access kube-apiserver within the network,
+// it must be an IP or a DNS name without scheme and port so that it can be resolved by the containers.
+func ValidateInClusterAddress(address string) error {
+	if net.ParseIP(address) != nil {
+		return nil
+	}
+	if errs := validation.IsDNS1123Subdomain(address); len(errs) != 0 {
+		return fmt.Errorf("invalid in-cluster address %q, must be an IP or a DNS name: %s", address, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// InClusterServer returns the URL of kube-apiserver for the components within the network,
+// the address is bracketed if it is an IPv6 address.
+func InClusterServer(scheme, address string, port uint32) string {
+	return scheme + "://" + net.JoinHostPort(address, strconv.FormatUint(uint64(port), 10))
+}
@@ -125,10 +125,224 @@ func TestApplyComponentPatch(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			applyComponentPatch(context.TODO(), &tt.component, tt.patch)
+			err := applyComponentPatch(context.TODO(), &tt.component, tt.patch)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if !reflect.DeepEqual(tt.wantArgs, tt.component.Args) {
 				t.Errorf("Exist is not expact! key:%s want args:%s, got args:%s", tt.patch.ExtraArgs[0].Key, tt.wantArgs, tt.component.Args)
 			}
 		})
 	}
 }
+
+func TestApplyComponentPatchesExtraVolumes(t *testing.T) {
+	component := internalversion.Component{
+		Name: "kube-apiserver",
+		Volumes: []internalversion.Volume{
+			{
+				HostPath:  "/root/.kwok/clusters/kwok/pki/ca.crt",
+				MountPath: "/etc/kubernetes/pki/ca.crt",
+				ReadOnly:  true,
+			},
+			{
+				HostPath:  "/root/.kwok/clusters/kwok/audit.log",
+				MountPath: "/var/log/kubernetes/audit/audit.log",
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		patchName   string
+		extraVolume internalversion.Volume
+		wantErr     bool
+	}{
+		{
+			name: "not colliding",
+			extraVolume: internalversion.Volume{
+				HostPath:  "/tmp/webhook",
+				MountPath: "/etc/kubernetes/webhook",
+			},
+		},
+		{
+			name: "nested in a directory mounted by kwokctl",
+			extraVolume: internalversion.Volume{
+				HostPath:  "/tmp/extra.crt",
+				MountPath: "/etc/kubernetes/pki-extra/extra.crt",
+			},
+		},
+		{
+			name: "same mount path",
+			extraVolume: internalversion.Volume{
+				HostPath:  "/tmp/ca.crt",
+				MountPath: "/etc/kubernetes/pki/ca.crt",
+			},
+			wantErr: true,
+		},
+		{
+			name: "shadow the parent directory",
+			extraVolume: internalversion.Volume{
+				HostPath:  "/tmp/pki",
+				MountPath: "/etc/kubernetes/pki/",
+			},
+			wantErr: true,
+		},
+		{
+			name:      "other component",
+			patchName: "kube-controller-manager",
+			extraVolume: internalversion.Volume{
+				HostPath:  "/tmp/pki",
+				MountPath: "/etc/kubernetes/pki",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component := component
+			component.Volumes = append([]internalversion.Volume{}, component.Volumes...)
+			patchName := component.Name
+			if tt.patchName != "" {
+				patchName = tt.patchName
+			}
+
+			err := ApplyComponentPatches(context.TODO(), &component, []internalversion.ComponentPatches{
+				{
+					Name:         patchName,
+					ExtraVolumes: []internalversion.Volume{tt.extraVolume},
+				},
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("want error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestApplyComponentPatchesRestartPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		patches []internalversion.ComponentPatches
+		want    internalversion.RestartPolicy
+		wantErr bool
+	}{
+		{
+			name: "not set",
+			patches: []internalversion.ComponentPatches{
+				{Name: "kube-scheduler"},
+			},
+		},
+		{
+			name: "on failure",
+			patches: []internalversion.ComponentPatches{
+				{Name: "kube-scheduler", RestartPolicy: internalversion.RestartPolicyOnFailure},
+			},
+			want: internalversion.RestartPolicyOnFailure,
+		},
+		{
+			name: "other component",
+			patches: []internalversion.ComponentPatches{
+				{Name: "etcd", RestartPolicy: internalversion.RestartPolicyNo},
+			},
+		},
+		{
+			name: "invalid",
+			patches: []internalversion.ComponentPatches{
+				{Name: "kube-scheduler", RestartPolicy: "Sometimes"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component := internalversion.Component{
+				Name: "kube-scheduler",
+			}
+			err := ApplyComponentPatches(context.TODO(), &component, tt.patches)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyComponentPatches() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if component.RestartPolicy != tt.want {
+				t.Errorf("want restart policy %q, got %q", tt.want, component.RestartPolicy)
+			}
+		})
+	}
+}
+
+func TestApplyComponentPatchesCommandOverride(t *testing.T) {
+	tests := []struct {
+		name        string
+		patches     []internalversion.ComponentPatches
+		wantCommand []string
+	}{
+		{
+			name: "no override",
+			patches: []internalversion.ComponentPatches{
+				{Name: "etcd"},
+			},
+			wantCommand: []string{"etcd"},
+		},
+		{
+			name: "override",
+			patches: []internalversion.ComponentPatches{
+				{Name: "etcd", CommandOverride: []string{"strace", "-f", "etcd"}},
+			},
+			wantCommand: []string{"strace", "-f", "etcd"},
+		},
+		{
+			name: "other component",
+			patches: []internalversion.ComponentPatches{
+				{Name: "kube-apiserver", CommandOverride: []string{"strace", "-f", "kube-apiserver"}},
+			},
+			wantCommand: []string{"etcd"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component := internalversion.Component{
+				Name:    "etcd",
+				Command: []string{"etcd"},
+				Args:    []string{"--data-dir=/etcd-data"},
+			}
+			err := ApplyComponentPatches(context.TODO(), &component, tt.patches)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(component.Command, tt.wantCommand) {
+				t.Errorf("want command %q, got %q", tt.wantCommand, component.Command)
+			}
+			if !reflect.DeepEqual(component.Args, []string{"--data-dir=/etcd-data"}) {
+				t.Errorf("want args unchanged, got %q", component.Args)
+			}
+		})
+	}
+}
+
+func TestValidateInClusterAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		wantErr bool
+	}{
+		{address: "kube-apiserver.kwok.internal"},
+		{address: "10.0.0.2"},
+		{address: "fd00::2"},
+		{address: "https://kube-apiserver", wantErr: true},
+		{address: "kube-apiserver:6443", wantErr: true},
+		{address: "Kube_Apiserver", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := ValidateInClusterAddress(tt.address)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateInClusterAddress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
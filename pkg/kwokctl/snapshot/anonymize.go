@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RedactedValue is the value that the redacted data is replaced with
+const RedactedValue = "REDACTED"
+
+// AnonymizeRule describes the values of a map field to be redacted,
+// the keys are kept so that the structure of the resource is preserved.
+type AnonymizeRule struct {
+	// Kind is the kind of the resource, empty matches all kinds.
+	Kind string
+	// Field is the path to the map field, e.g. data or metadata.annotations.
+	Field []string
+	// KeyPattern matches the keys to be redacted, nil matches all keys.
+	KeyPattern *regexp.Regexp
+	// Base64 indicates that the redacted value is base64 encoded, e.g. the data of Secret.
+	Base64 bool
+}
+
+// lastAppliedConfigurationPattern matches the annotation where kubectl apply keeps a copy of the whole resource
+var lastAppliedConfigurationPattern = regexp.MustCompile(`^kubectl\.kubernetes\.io/last-applied-configuration$`)
+
+// DefaultAnonymizeRules is the default rules that redact the data and stringData of Secret,
+// and the copy of them in the last applied configuration annotation
+var DefaultAnonymizeRules = []AnonymizeRule{
+	{
+		Kind:   "Secret",
+		Field:  []string{"data"},
+		Base64: true,
+	},
+	{
+		Kind:  "Secret",
+		Field: []string{"stringData"},
+	},
+	{
+		Kind:       "Secret",
+		Field:      []string{"metadata", "annotations"},
+		KeyPattern: lastAppliedConfigurationPattern,
+	},
+}
+
+// AnonymizeConfig is the configuration of the anonymizer
+type AnonymizeConfig struct {
+	// Annotations is the patterns of the annotation keys whose values are redacted in all resources.
+	Annotations []string
+	// ConfigMapKeys is the patterns of the keys whose values are redacted in the data of ConfigMap, e.g. token.
+	ConfigMapKeys []string
+}
+
+// Anonymizer redacts the sensitive data of the resources
+type Anonymizer struct {
+	rules []AnonymizeRule
+}
+
+// NewAnonymizer creates an anonymizer with the default rules and the rules of the config
+func NewAnonymizer(conf AnonymizeConfig) (*Anonymizer, error) {
+	rules := append([]AnonymizeRule{}, DefaultAnonymizeRules...)
+	for _, pattern := range conf.Annotations {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid annotation pattern %q: %w", pattern, err)
+		}
+		rules = append(rules, AnonymizeRule{
+			Field:      []string{"metadata", "annotations"},
+			KeyPattern: re,
+		})
+	}
+	for _, pattern := range conf.ConfigMapKeys {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config map key pattern %q: %w", pattern, err)
+		}
+		rules = append(rules,
+			AnonymizeRule{
+				Kind:       "ConfigMap",
+				Field:      []string{"data"},
+				KeyPattern: re,
+			},
+			AnonymizeRule{
+				Kind:       "ConfigMap",
+				Field:      []string{"binaryData"},
+				KeyPattern: re,
+				Base64:     true,
+			},
+		)
+	}
+	if len(conf.ConfigMapKeys) != 0 {
+		rules = append(rules, AnonymizeRule{
+			Kind:       "ConfigMap",
+			Field:      []string{"metadata", "annotations"},
+			KeyPattern: lastAppliedConfigurationPattern,
+		})
+	}
+	return &Anonymizer{
+		rules: rules,
+	}, nil
+}
+
+// Anonymize redacts the values of the resource in place
+func (a *Anonymizer) Anonymize(obj *unstructured.Unstructured) {
+	kind := obj.GetKind()
+	for _, rule := range a.rules {
+		if rule.Kind != "" && rule.Kind != kind {
+			continue
+		}
+		m, ok, _ := unstructured.NestedMap(obj.Object, rule.Field...)
+		if !ok || len(m) == 0 {
+			continue
+		}
+		value := RedactedValue
+		if rule.Base64 {
+			value = base64.StdEncoding.EncodeToString([]byte(RedactedValue))
+		}
+		changed := false
+		for key := range m {
+			if rule.KeyPattern != nil && !rule.KeyPattern.MatchString(key) {
+				continue
+			}
+			m[key] = value
+			changed = true
+		}
+		if changed {
+			_ = unstructured.SetNestedMap(obj.Object, m, rule.Field...)
+		}
+	}
+}
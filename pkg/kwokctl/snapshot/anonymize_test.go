@@ -0,0 +1,231 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestAnonymizer(t *testing.T) {
+	redactedBase64 := "UkVEQUNURUQ="
+
+	tests := []struct {
+		name    string
+		conf    AnonymizeConfig
+		obj     map[string]any
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "redact secret by default",
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata": map[string]any{
+					"name":      "token",
+					"namespace": "default",
+				},
+				"type": "Opaque",
+				"data": map[string]any{
+					"token": "c2VjcmV0",
+					"ca":    "Y2E=",
+				},
+				"stringData": map[string]any{
+					"password": "secret",
+				},
+			},
+			want: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata": map[string]any{
+					"name":      "token",
+					"namespace": "default",
+				},
+				"type": "Opaque",
+				"data": map[string]any{
+					"token": redactedBase64,
+					"ca":    redactedBase64,
+				},
+				"stringData": map[string]any{
+					"password": RedactedValue,
+				},
+			},
+		},
+		{
+			name: "redact secret applied by kubectl",
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata": map[string]any{
+					"name":      "token",
+					"namespace": "default",
+					"annotations": map[string]any{
+						"kubectl.kubernetes.io/last-applied-configuration": `{"apiVersion":"v1","data":{"token":"c2VjcmV0"},"kind":"Secret","metadata":{"annotations":{},"name":"token","namespace":"default"},"type":"Opaque"}` + "\n",
+						"example.com/owner": "someone@example.com",
+					},
+				},
+				"type": "Opaque",
+				"data": map[string]any{
+					"token": "c2VjcmV0",
+				},
+			},
+			want: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata": map[string]any{
+					"name":      "token",
+					"namespace": "default",
+					"annotations": map[string]any{
+						"kubectl.kubernetes.io/last-applied-configuration": RedactedValue,
+						"example.com/owner": "someone@example.com",
+					},
+				},
+				"type": "Opaque",
+				"data": map[string]any{
+					"token": redactedBase64,
+				},
+			},
+		},
+		{
+			name: "keep config map by default",
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]any{
+					"name": "config",
+				},
+				"data": map[string]any{
+					"token": "secret",
+				},
+			},
+			want: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]any{
+					"name": "config",
+				},
+				"data": map[string]any{
+					"token": "secret",
+				},
+			},
+		},
+		{
+			name: "redact config map keys",
+			conf: AnonymizeConfig{
+				ConfigMapKeys: []string{"(?i)token"},
+			},
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]any{
+					"name": "config",
+					"annotations": map[string]any{
+						"kubectl.kubernetes.io/last-applied-configuration": `{"apiVersion":"v1","data":{"apiToken":"secret"},"kind":"ConfigMap","metadata":{"annotations":{},"name":"config"}}` + "\n",
+					},
+				},
+				"data": map[string]any{
+					"apiToken": "secret",
+					"endpoint": "https://example.com",
+				},
+				"binaryData": map[string]any{
+					"token.bin": "c2VjcmV0",
+				},
+			},
+			want: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]any{
+					"name": "config",
+					"annotations": map[string]any{
+						"kubectl.kubernetes.io/last-applied-configuration": RedactedValue,
+					},
+				},
+				"data": map[string]any{
+					"apiToken": RedactedValue,
+					"endpoint": "https://example.com",
+				},
+				"binaryData": map[string]any{
+					"token.bin": redactedBase64,
+				},
+			},
+		},
+		{
+			name: "redact annotations",
+			conf: AnonymizeConfig{
+				Annotations: []string{"^example.com/"},
+			},
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata": map[string]any{
+					"name": "pod",
+					"annotations": map[string]any{
+						"example.com/owner":  "someone@example.com",
+						"kwok.x-k8s.io/node": "fake",
+					},
+				},
+				"spec": map[string]any{
+					"nodeName": "node",
+				},
+			},
+			want: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata": map[string]any{
+					"name": "pod",
+					"annotations": map[string]any{
+						"example.com/owner":  RedactedValue,
+						"kwok.x-k8s.io/node": "fake",
+					},
+				},
+				"spec": map[string]any{
+					"nodeName": "node",
+				},
+			},
+		},
+		{
+			name: "invalid pattern",
+			conf: AnonymizeConfig{
+				Annotations: []string{"("},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAnonymizer(tt.conf)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("want error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			obj := &unstructured.Unstructured{Object: tt.obj}
+			a.Anonymize(obj)
+			if !reflect.DeepEqual(obj.Object, tt.want) {
+				t.Errorf("Anonymize() got = %v, want %v", obj.Object, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/kwok/pkg/kwokctl/recording"
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/sets"
+	"sigs.k8s.io/kwok/pkg/utils/yaml"
+)
+
+// DiffConfig is the config of comparing two snapshots
+type DiffConfig struct {
+	Filters   []*meta.RESTMapping
+	NoFilters bool
+	// IncludeNoise keeps the resourceVersion, managedFields and timestamps in the comparison,
+	// which are changed by the apiserver and the controllers on every update.
+	IncludeNoise bool
+}
+
+// DiffReport is the report of the differences between two snapshots
+type DiffReport struct {
+	Added   []DiffObject `json:"added,omitempty"`
+	Removed []DiffObject `json:"removed,omitempty"`
+	Changed []DiffObject `json:"changed,omitempty"`
+}
+
+// Empty returns true if there are no differences
+func (r *DiffReport) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Changed) == 0
+}
+
+// DiffObject is an object that differs between two snapshots
+type DiffObject struct {
+	APIVersion string      `json:"apiVersion"`
+	Kind       string      `json:"kind"`
+	Namespace  string      `json:"namespace,omitempty"`
+	Name       string      `json:"name"`
+	Fields     []DiffField `json:"fields,omitempty"`
+}
+
+// DiffField is a field that differs between the two versions of an object,
+// From is nil if the field is added and To is nil if the field is removed.
+type DiffField struct {
+	Path string `json:"path"`
+	From any    `json:"from,omitempty"`
+	To   any    `json:"to,omitempty"`
+}
+
+type diffKey struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+}
+
+func (k diffKey) less(o diffKey) bool {
+	if k.APIVersion != o.APIVersion {
+		return k.APIVersion < o.APIVersion
+	}
+	if k.Kind != o.Kind {
+		return k.Kind < o.Kind
+	}
+	if k.Namespace != o.Namespace {
+		return k.Namespace < o.Namespace
+	}
+	return k.Name < o.Name
+}
+
+func (k diffKey) object() DiffObject {
+	return DiffObject{
+		APIVersion: k.APIVersion,
+		Kind:       k.Kind,
+		Namespace:  k.Namespace,
+		Name:       k.Name,
+	}
+}
+
+// Diff compares the resources of two snapshots in k8s format
+func Diff(ctx context.Context, from, to *yaml.Decoder, conf DiffConfig) (*DiffReport, error) {
+	var filterGKs sets.Sets[schema.GroupKind]
+	if !conf.NoFilters {
+		filterGKs = sets.NewSets[schema.GroupKind]()
+		for _, f := range conf.Filters {
+			filterGKs.Insert(f.GroupVersionKind.GroupKind())
+		}
+	}
+
+	fromObjs, err := decodeForDiff(ctx, from, filterGKs, conf)
+	if err != nil {
+		return nil, err
+	}
+	toObjs, err := decodeForDiff(ctx, to, filterGKs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DiffReport{}
+	for _, key := range sortedDiffKeys(fromObjs) {
+		if _, ok := toObjs[key]; !ok {
+			report.Removed = append(report.Removed, key.object())
+		}
+	}
+	for _, key := range sortedDiffKeys(toObjs) {
+		fromObj, ok := fromObjs[key]
+		if !ok {
+			report.Added = append(report.Added, key.object())
+			continue
+		}
+		fields := diffFields(nil, "", fromObj, toObjs[key])
+		if len(fields) != 0 {
+			obj := key.object()
+			obj.Fields = fields
+			report.Changed = append(report.Changed, obj)
+		}
+	}
+	return report, nil
+}
+
+// decodeForDiff decodes the resources of the snapshot until the first patch of the recording,
+// the resources are skipped unless their GroupKind is in filterGKs, a nil filterGKs keeps all of them.
+func decodeForDiff(ctx context.Context, decoder *yaml.Decoder, filterGKs sets.Sets[schema.GroupKind], conf DiffConfig) (map[diffKey]map[string]any, error) {
+	logger := log.FromContext(ctx)
+
+	objs := map[diffKey]map[string]any{}
+	for ctx.Err() == nil {
+		obj, err := decoder.DecodeUnstructured()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			logger.Warn("Failed to decode resource", "err", err)
+			continue
+		}
+
+		if obj.GetKind() == recording.ResourcePatchType.Kind && obj.GetAPIVersion() == recording.ResourcePatchType.APIVersion {
+			break
+		}
+
+		if filterGKs != nil && !filterGKs.Has(obj.GroupVersionKind().GroupKind()) {
+			continue
+		}
+
+		if !conf.IncludeNoise {
+			removeNoise(obj)
+		}
+
+		key := diffKey{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+		}
+		objs[key] = obj.Object
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return objs, nil
+}
+
+func sortedDiffKeys(objs map[diffKey]map[string]any) []diffKey {
+	keys := make([]diffKey, 0, len(objs))
+	for key := range objs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].less(keys[j])
+	})
+	return keys
+}
+
+// removeNoise removes the fields that are changed by the apiserver and the controllers on every update
+func removeNoise(obj *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(obj.Object, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+	removeTimestamps(obj.Object)
+}
+
+var (
+	// conditionTimestampFields are the timestamps of the conditions in the status
+	conditionTimestampFields = []string{
+		"lastHeartbeatTime",
+		"lastProbeTime",
+		"lastTransitionTime",
+		"lastUpdateTime",
+	}
+	// containerStatusesFields are the lists of the container statuses in the status of a pod
+	containerStatusesFields = []string{
+		"containerStatuses",
+		"initContainerStatuses",
+		"ephemeralContainerStatuses",
+	}
+	// containerStateTimestampPaths are the timestamps of the state of a container status
+	containerStateTimestampPaths = [][]string{
+		{"running", "startedAt"},
+		{"terminated", "startedAt"},
+		{"terminated", "finishedAt"},
+	}
+)
+
+// removeTimestamps removes the creationTimestamp and the known timestamps in the status,
+// the times of the conditions, the start time of a pod and the times of the states of its containers
+func removeTimestamps(obj map[string]any) {
+	unstructured.RemoveNestedField(obj, "metadata", "creationTimestamp")
+
+	status, ok := obj["status"].(map[string]any)
+	if !ok {
+		return
+	}
+	delete(status, "startTime")
+
+	conditions, _ := status["conditions"].([]any)
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, field := range conditionTimestampFields {
+			delete(condition, field)
+		}
+	}
+
+	for _, field := range containerStatusesFields {
+		containerStatuses, _ := status[field].([]any)
+		for _, cs := range containerStatuses {
+			containerStatus, ok := cs.(map[string]any)
+			if !ok {
+				continue
+			}
+			for _, state := range []string{"state", "lastState"} {
+				for _, path := range containerStateTimestampPaths {
+					unstructured.RemoveNestedField(containerStatus, append([]string{state}, path...)...)
+				}
+			}
+		}
+	}
+}
+
+// diffFields appends the fields that differ between from and to, sorted by path
+func diffFields(fields []DiffField, path string, from, to any) []DiffField {
+	switch f := from.(type) {
+	case map[string]any:
+		t, ok := to.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(f)+len(t))
+		for k := range f {
+			keys = append(keys, k)
+		}
+		for k := range t {
+			if _, ok := f[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			sub := k
+			if path != "" {
+				sub = path + "." + k
+			}
+			fields = diffFields(fields, sub, f[k], t[k])
+		}
+		return fields
+	case []any:
+		t, ok := to.([]any)
+		if !ok {
+			break
+		}
+		for i := 0; i < len(f) || i < len(t); i++ {
+			var fv, tv any
+			if i < len(f) {
+				fv = f[i]
+			}
+			if i < len(t) {
+				tv = t[i]
+			}
+			fields = diffFields(fields, fmt.Sprintf("%s[%d]", path, i), fv, tv)
+		}
+		return fields
+	}
+
+	if reflect.DeepEqual(from, to) {
+		return fields
+	}
+	return append(fields, DiffField{
+		Path: path,
+		From: from,
+		To:   to,
+	})
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/kwok/pkg/utils/yaml"
+)
+
+const diffFromSnapshot = `
+apiVersion: v1
+kind: Node
+metadata:
+  name: node-0
+  resourceVersion: "1"
+  creationTimestamp: "2024-01-01T00:00:00Z"
+status:
+  conditions:
+  - type: Ready
+    status: "True"
+    lastHeartbeatTime: "2024-01-01T00:00:00Z"
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod-0
+  namespace: default
+spec:
+  nodeName: node-0
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod-1
+  namespace: default
+spec:
+  nodeName: node-0
+`
+
+const diffToSnapshot = `
+apiVersion: v1
+kind: Node
+metadata:
+  name: node-0
+  resourceVersion: "2"
+  creationTimestamp: "2024-01-01T00:00:00Z"
+status:
+  conditions:
+  - type: Ready
+    status: "True"
+    lastHeartbeatTime: "2024-01-01T00:01:00Z"
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod-0
+  namespace: default
+  labels:
+    app: foo
+spec:
+  nodeName: node-1
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod-2
+  namespace: default
+spec:
+  nodeName: node-0
+`
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		conf DiffConfig
+		want *DiffReport
+	}{
+		{
+			name: "ignore noise",
+			conf: DiffConfig{NoFilters: true},
+			want: &DiffReport{
+				Added: []DiffObject{
+					{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "pod-2"},
+				},
+				Removed: []DiffObject{
+					{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "pod-1"},
+				},
+				Changed: []DiffObject{
+					{
+						APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "pod-0",
+						Fields: []DiffField{
+							{Path: "metadata.labels", To: map[string]any{"app": "foo"}},
+							{Path: "spec.nodeName", From: "node-0", To: "node-1"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "include noise",
+			conf: DiffConfig{NoFilters: true, IncludeNoise: true},
+			want: &DiffReport{
+				Added: []DiffObject{
+					{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "pod-2"},
+				},
+				Removed: []DiffObject{
+					{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "pod-1"},
+				},
+				Changed: []DiffObject{
+					{
+						APIVersion: "v1", Kind: "Node", Name: "node-0",
+						Fields: []DiffField{
+							{Path: "metadata.resourceVersion", From: "1", To: "2"},
+							{Path: "status.conditions[0].lastHeartbeatTime", From: "2024-01-01T00:00:00Z", To: "2024-01-01T00:01:00Z"},
+						},
+					},
+					{
+						APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "pod-0",
+						Fields: []DiffField{
+							{Path: "metadata.labels", To: map[string]any{"app": "foo"}},
+							{Path: "spec.nodeName", From: "node-0", To: "node-1"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "filter",
+			conf: DiffConfig{
+				Filters: []*meta.RESTMapping{
+					{GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Node"}},
+				},
+				IncludeNoise: true,
+			},
+			want: &DiffReport{
+				Changed: []DiffObject{
+					{
+						APIVersion: "v1", Kind: "Node", Name: "node-0",
+						Fields: []DiffField{
+							{Path: "metadata.resourceVersion", From: "1", To: "2"},
+							{Path: "status.conditions[0].lastHeartbeatTime", From: "2024-01-01T00:00:00Z", To: "2024-01-01T00:01:00Z"},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := yaml.NewDecoder(strings.NewReader(diffFromSnapshot))
+			to := yaml.NewDecoder(strings.NewReader(diffToSnapshot))
+			got, err := Diff(context.Background(), from, to, tt.conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Diff() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRemoveTimestamps(t *testing.T) {
+	obj := map[string]any{
+		"metadata": map[string]any{
+			"name":              "pod-0",
+			"creationTimestamp": "2024-01-01T00:00:00Z",
+			"annotations": map[string]any{
+				"lastRestartTime": "2024-01-01T00:00:00Z",
+			},
+		},
+		"spec": map[string]any{
+			"scheduleTime": "2024-01-01T00:00:00Z",
+		},
+		"status": map[string]any{
+			"startTime": "2024-01-01T00:00:00Z",
+			"conditions": []any{
+				map[string]any{
+					"type":               "Ready",
+					"status":             "True",
+					"lastProbeTime":      nil,
+					"lastTransitionTime": "2024-01-01T00:00:00Z",
+				},
+			},
+			"containerStatuses": []any{
+				map[string]any{
+					"name": "app",
+					"state": map[string]any{
+						"running": map[string]any{
+							"startedAt": "2024-01-01T00:00:00Z",
+						},
+					},
+					"lastState": map[string]any{
+						"terminated": map[string]any{
+							"exitCode":   int64(0),
+							"startedAt":  "2024-01-01T00:00:00Z",
+							"finishedAt": "2024-01-01T00:00:00Z",
+						},
+					},
+				},
+			},
+		},
+	}
+	want := map[string]any{
+		"metadata": map[string]any{
+			"name": "pod-0",
+			"annotations": map[string]any{
+				"lastRestartTime": "2024-01-01T00:00:00Z",
+			},
+		},
+		"spec": map[string]any{
+			"scheduleTime": "2024-01-01T00:00:00Z",
+		},
+		"status": map[string]any{
+			"conditions": []any{
+				map[string]any{
+					"type":   "Ready",
+					"status": "True",
+				},
+			},
+			"containerStatuses": []any{
+				map[string]any{
+					"name": "app",
+					"state": map[string]any{
+						"running": map[string]any{},
+					},
+					"lastState": map[string]any{
+						"terminated": map[string]any{
+							"exitCode": int64(0),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	removeTimestamps(obj)
+	if diff := cmp.Diff(want, obj); diff != "" {
+		t.Errorf("removeTimestamps() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -36,6 +36,7 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/util/retry"
 
+	"sigs.k8s.io/kwok/pkg/kwokctl/dryrun"
 	"sigs.k8s.io/kwok/pkg/kwokctl/recording"
 	"sigs.k8s.io/kwok/pkg/log"
 	"sigs.k8s.io/kwok/pkg/utils/client"
@@ -50,6 +51,38 @@ type LoadConfig struct {
 	Clientset client.Clientset
 	Filters   []*meta.RESTMapping
 	NoFilers  bool
+	// ConflictStrategy is how to resolve the conflicts when updating the existing resources,
+	// defaults to ConflictStrategySkip.
+	ConflictStrategy ConflictStrategy
+	// Prune deletes the resources of the Filters in the cluster that are absent from the snapshot after loading,
+	// only the kinds present in the snapshot are pruned and the resources managed by the cluster itself are kept,
+	// the identities of the resources in the snapshot are kept in memory to find them.
+	Prune bool
+	// DryRun only prints the commands to delete the resources that Prune would delete,
+	// without changing the cluster.
+	DryRun bool
+}
+
+// ConflictStrategy is the strategy to resolve the conflicts when updating the existing resources
+type ConflictStrategy string
+
+const (
+	// ConflictStrategySkip skips the resource on conflict
+	ConflictStrategySkip ConflictStrategy = "Skip"
+	// ConflictStrategyRetry re-reads the existing resource and re-applies the resource with server-side apply,
+	// forcing the ownership of the conflicting fields, until it no longer conflicts
+	ConflictStrategyRetry ConflictStrategy = "Retry"
+)
+
+// fieldManager is the field manager of the resources applied on conflict
+const fieldManager = "kwokctl"
+
+// pruneKey is the identity of a resource in the snapshot,
+// the GroupKind is used so that the resources match regardless of the version.
+type pruneKey struct {
+	GroupKind schema.GroupKind
+	Namespace string
+	Name      string
 }
 
 type uniqueKey struct {
@@ -71,6 +104,11 @@ type Loader struct {
 	exist   map[uniqueKey]types.UID
 	pending map[uniqueKey][]*unstructured.Unstructured
 
+	// loaded is the identities of the resources in the snapshot, only tracked for pruning
+	loaded sets.Sets[pruneKey]
+	// loadedGKs is the kinds of the resources in the snapshot, only tracked for pruning
+	loadedGKs sets.Sets[schema.GroupKind]
+
 	restMapper    meta.RESTMapper
 	dynamicClient dynamic.Interface
 
@@ -99,7 +137,8 @@ func NewLoader(loadConfig LoadConfig) (*Loader, error) {
 	return l, nil
 }
 
-// Load loads the resources to cluster
+// Load loads the resources to cluster,
+// the decoder is consumed one document at a time so that huge snapshots are never fully buffered.
 func (l *Loader) Load(ctx context.Context, decoder *yaml.Decoder) error {
 	logger := log.FromContext(ctx)
 
@@ -126,13 +165,156 @@ func (l *Loader) Load(ctx context.Context, decoder *yaml.Decoder) error {
 				"kind", obj.GetKind(),
 				"name", log.KObj(obj),
 			)
-			return nil
+			continue
+		}
+
+		if l.loadConfig.Prune {
+			l.trackLoaded(obj)
 		}
 
+		if l.loadConfig.DryRun {
+			continue
+		}
+
+		// The resource is applied before the next one is decoded,
+		// only the resources waiting for their owners are kept in memory.
 		l.load(ctx, obj)
 	}
 
-	return l.finishLoad(ctx, startTime)
+	if !l.loadConfig.DryRun {
+		err := l.finishLoad(ctx, startTime)
+		if err != nil {
+			return err
+		}
+	}
+
+	if l.loadConfig.Prune {
+		return l.prune(ctx)
+	}
+	return nil
+}
+
+func (l *Loader) trackLoaded(obj *unstructured.Unstructured) {
+	if l.loaded == nil {
+		l.loaded = sets.NewSets[pruneKey]()
+		l.loadedGKs = sets.NewSets[schema.GroupKind]()
+	}
+	gk := obj.GroupVersionKind().GroupKind()
+	l.loadedGKs.Insert(gk)
+	l.loaded.Insert(pruneKey{
+		GroupKind: gk,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	})
+}
+
+// protectedNamespaces are the namespaces managed by the cluster itself
+var protectedNamespaces = sets.NewSets(
+	"kube-system",
+	"kube-public",
+	"kube-node-lease",
+)
+
+// isProtected returns true if the resource is managed by the cluster itself,
+// which is never pruned even if it is absent from the snapshot
+func isProtected(gk schema.GroupKind, namespace, name string) bool {
+	if protectedNamespaces.Has(namespace) {
+		return true
+	}
+	switch gk {
+	case schema.GroupKind{Kind: "Namespace"}:
+		return name == metav1.NamespaceDefault || protectedNamespaces.Has(name)
+	case schema.GroupKind{Kind: "Service"},
+		schema.GroupKind{Kind: "Endpoints"},
+		schema.GroupKind{Group: "discovery.k8s.io", Kind: "EndpointSlice"}:
+		return namespace == metav1.NamespaceDefault && name == "kubernetes"
+	}
+	if gk.Group == "rbac.authorization.k8s.io" {
+		return strings.HasPrefix(name, "system:")
+	}
+	return false
+}
+
+// prune deletes the resources of the filters in the cluster that are absent from the snapshot,
+// only the kinds present in the snapshot are pruned
+func (l *Loader) prune(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+
+	if l.loadConfig.NoFilers {
+		return fmt.Errorf("prune requires the filters of the resources to delete")
+	}
+
+	var pruned, failed int
+	for _, mapping := range l.loadConfig.Filters {
+		gk := mapping.GroupVersionKind.GroupKind()
+		if !l.loadedGKs.Has(gk) {
+			logger.Debug("Skipped pruning",
+				"resource", "absent from snapshot",
+				"kind", gk.String(),
+			)
+			continue
+		}
+
+		list, err := l.dynamicClient.Resource(mapping.Resource).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %w", mapping.Resource.Resource, err)
+		}
+
+		for _, item := range list.Items {
+			if item.GetDeletionTimestamp() != nil {
+				continue
+			}
+			key := pruneKey{
+				GroupKind: gk,
+				Namespace: item.GetNamespace(),
+				Name:      item.GetName(),
+			}
+			if l.loaded.Has(key) || isProtected(gk, key.Namespace, key.Name) {
+				continue
+			}
+
+			if l.loadConfig.DryRun {
+				if key.Namespace != "" {
+					dryrun.PrintMessage("kubectl delete %s %s -n %s", mapping.Resource.GroupResource(), key.Name, key.Namespace)
+				} else {
+					dryrun.PrintMessage("kubectl delete %s %s", mapping.Resource.GroupResource(), key.Name)
+				}
+				continue
+			}
+
+			var ri dynamic.ResourceInterface = l.dynamicClient.Resource(mapping.Resource)
+			if ns := item.GetNamespace(); ns != "" {
+				ri = l.dynamicClient.Resource(mapping.Resource).Namespace(ns)
+			}
+			err = ri.Delete(ctx, item.GetName(), metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				failed++
+				logger.Error("Failed to prune resource", err,
+					"kind", item.GetKind(),
+					"name", log.KObj(&item),
+				)
+				continue
+			}
+			pruned++
+			logger.Debug("Pruned",
+				"kind", item.GetKind(),
+				"name", log.KObj(&item),
+			)
+		}
+	}
+
+	if l.loadConfig.DryRun {
+		return nil
+	}
+
+	logger.Info("Prune resources",
+		"prunedCounter", pruned,
+		"failedCounter", failed,
+	)
+	if failed != 0 {
+		return fmt.Errorf("failed to prune %d resources", failed)
+	}
+	return nil
 }
 
 func (l *Loader) finishLoad(ctx context.Context, startTime time.Time) error {
@@ -302,15 +484,26 @@ func (l *Loader) apply(ctx context.Context, obj *unstructured.Unstructured) *uns
 		}
 		newObj, err = ri.Update(ctx, obj, metav1.UpdateOptions{FieldValidation: "Ignore"})
 		if err != nil {
-			l.failedCounter++
-			if apierrors.IsConflict(err) {
+			if !apierrors.IsConflict(err) {
+				l.failedCounter++
+				logger.Error("Failed to update resource", err)
+				return nil
+			}
+			if l.loadConfig.ConflictStrategy != ConflictStrategyRetry {
+				l.failedCounter++
 				logger.Warn("Conflict")
 				return nil
 			}
-			logger.Error("Failed to update resource", err)
-			return nil
+			newObj, err = applyOnConflict(ctx, ri, obj)
+			if err != nil {
+				l.failedCounter++
+				logger.Error("Failed to apply resource on conflict", err)
+				return nil
+			}
+			logger.Debug("Applied on conflict")
+		} else {
+			logger.Debug("Updated")
 		}
-		logger.Debug("Updated")
 
 		if newObj != nil {
 			status, ok, _ := unstructured.NestedFieldNoCopy(obj.Object, "status")
@@ -330,6 +523,29 @@ func (l *Loader) apply(ctx context.Context, obj *unstructured.Unstructured) *uns
 	return newObj
 }
 
+// applyOnConflict re-reads the existing resource and re-applies the resource with server-side apply,
+// it is retried as long as the existing resource changes in between.
+func applyOnConflict(ctx context.Context, ri dynamic.ResourceInterface, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	var newObj *unstructured.Unstructured
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		applyObj := obj.DeepCopy()
+		applyObj.SetUID(current.GetUID())
+		applyObj.SetResourceVersion(current.GetResourceVersion())
+		applyObj.SetManagedFields(nil)
+		newObj, err = ri.Apply(ctx, obj.GetName(), applyObj, metav1.ApplyOptions{FieldManager: fieldManager, Force: true})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newObj, nil
+}
+
 func isNotFound(err error) bool {
 	return apierrors.IsNotFound(err) ||
 		(apierrors.IsForbidden(err) && strings.Contains(err.Error(), "not found"))
@@ -0,0 +1,481 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"sigs.k8s.io/kwok/pkg/utils/yaml"
+)
+
+func TestLoaderApplyConflict(t *testing.T) {
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+	newConfigMap := func(uid types.UID, resourceVersion string, value string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		obj.SetNamespace("default")
+		obj.SetName("foo")
+		obj.SetUID(uid)
+		obj.SetResourceVersion(resourceVersion)
+		_ = unstructured.SetNestedField(obj.Object, value, "data", "key")
+		return obj
+	}
+
+	tests := []struct {
+		name      string
+		strategy  ConflictStrategy
+		conflicts int
+		wantValue string
+		wantErr   bool
+	}{
+		{
+			name:      "skip",
+			strategy:  ConflictStrategySkip,
+			wantValue: "live",
+			wantErr:   true,
+		},
+		{
+			name:      "retry",
+			strategy:  ConflictStrategyRetry,
+			wantValue: "snapshot",
+		},
+		{
+			name:      "retry with the resource changing in between",
+			strategy:  ConflictStrategyRetry,
+			conflicts: 2,
+			wantValue: "snapshot",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+				map[schema.GroupVersionResource]string{gvr: "ConfigMapList"},
+				newConfigMap("live-uid", "1", "live"),
+			)
+
+			// The live resource was recreated, so the update with the uid of the snapshot conflicts.
+			dynamicClient.PrependReactor("update", "configmaps", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				return true, nil, apierrors.NewConflict(gvr.GroupResource(), "foo", nil)
+			})
+			conflicts := tt.conflicts
+			applies := 0
+			dynamicClient.PrependReactor("patch", "configmaps", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				patch := action.(clienttesting.PatchAction)
+				if patch.GetPatchType() != types.ApplyPatchType {
+					return false, nil, nil
+				}
+				applies++
+				if conflicts > 0 {
+					conflicts--
+					return true, nil, apierrors.NewConflict(gvr.GroupResource(), "foo", nil)
+				}
+
+				// The fake tracker does not support server-side apply of unstructured resources,
+				// so the applied resource replaces the live one after checking the preconditions.
+				obj := &unstructured.Unstructured{}
+				err := obj.UnmarshalJSON(patch.GetPatch())
+				if err != nil {
+					return true, nil, err
+				}
+				if obj.GetUID() != "live-uid" || obj.GetResourceVersion() != "1" {
+					return true, nil, apierrors.NewConflict(gvr.GroupResource(), "foo", nil)
+				}
+				err = dynamicClient.Tracker().Update(gvr, obj, "default")
+				if err != nil {
+					return true, nil, err
+				}
+				return true, obj, nil
+			})
+
+			restMapper := meta.NewDefaultRESTMapper(nil)
+			restMapper.Add(gvk, meta.RESTScopeNamespace)
+
+			l := &Loader{
+				exist:         map[uniqueKey]types.UID{},
+				pending:       map[uniqueKey][]*unstructured.Unstructured{},
+				restMapper:    restMapper,
+				dynamicClient: dynamicClient,
+				loadConfig: LoadConfig{
+					ConflictStrategy: tt.strategy,
+				},
+			}
+
+			newObj := l.apply(context.Background(), newConfigMap("snapshot-uid", "", "snapshot"))
+			if (newObj == nil) != tt.wantErr {
+				t.Fatalf("apply() got = %v, wantErr %v", newObj, tt.wantErr)
+			}
+			if !tt.wantErr && applies != tt.conflicts+1 {
+				t.Errorf("want %d applies, got %d", tt.conflicts+1, applies)
+			}
+
+			got, err := dynamicClient.Resource(gvr).Namespace("default").Get(context.Background(), "foo", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			value, _, _ := unstructured.NestedString(got.Object, "data", "key")
+			if value != tt.wantValue {
+				t.Errorf("want value %q, got %q", tt.wantValue, value)
+			}
+			if got.GetUID() != "live-uid" {
+				t.Errorf("want the uid of the live resource kept, got %q", got.GetUID())
+			}
+		})
+	}
+}
+
+func TestLoaderLoadStreaming(t *testing.T) {
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+	const (
+		total = 5000
+		// maxAhead is the most documents the decoder may read ahead of the applied ones
+		maxAhead = 2
+	)
+
+	// The synthetic snapshot is generated on the fly,
+	// a document is counted as produced once the decoder has read all of it.
+	var produced atomic.Int64
+	r, w := io.Pipe()
+	go func() {
+		for i := 0; i != total; i++ {
+			_, err := fmt.Fprintf(w, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm-%d\n  namespace: default\n  uid: uid-%d\ndata:\n  key: value-%d\n---\n", i, i, i)
+			if err != nil {
+				_ = w.CloseWithError(err)
+				return
+			}
+			produced.Add(1)
+		}
+		_ = w.Close()
+	}()
+
+	scheme := runtime.NewScheme()
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+		map[schema.GroupVersionResource]string{gvr: "ConfigMapList"},
+	)
+	var applied, ahead int64
+	dynamicClient.PrependReactor("create", "configmaps", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		applied++
+		if n := produced.Load() - applied; n > ahead {
+			ahead = n
+		}
+		return false, nil, nil
+	})
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(gvk, meta.RESTScopeNamespace)
+
+	l := &Loader{
+		exist:         map[uniqueKey]types.UID{},
+		pending:       map[uniqueKey][]*unstructured.Unstructured{},
+		restMapper:    restMapper,
+		dynamicClient: dynamicClient,
+		loadConfig: LoadConfig{
+			NoFilers: true,
+		},
+	}
+
+	err := l.Load(context.Background(), yaml.NewDecoder(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied != total || l.successCounter != total {
+		t.Errorf("want %d resources applied, got %d, success %d", total, applied, l.successCounter)
+	}
+	if ahead > maxAhead {
+		t.Errorf("want the decoder at most %d documents ahead of the applied ones, got %d", maxAhead, ahead)
+	}
+	if len(l.pending) != 0 {
+		t.Errorf("want no pending resources, got %d", len(l.pending))
+	}
+}
+
+func TestLoaderLoadPrune(t *testing.T) {
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+	newConfigMap := func(name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		obj.SetNamespace("default")
+		obj.SetName(name)
+		return obj
+	}
+
+	scheme := runtime.NewScheme()
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+		map[schema.GroupVersionResource]string{gvr: "ConfigMapList"},
+		newConfigMap("kept"),
+		newConfigMap("orphan"),
+	)
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(gvk, meta.RESTScopeNamespace)
+
+	l := &Loader{
+		exist:         map[uniqueKey]types.UID{},
+		pending:       map[uniqueKey][]*unstructured.Unstructured{},
+		restMapper:    restMapper,
+		dynamicClient: dynamicClient,
+		loadConfig: LoadConfig{
+			Filters: []*meta.RESTMapping{
+				{Resource: gvr, GroupVersionKind: gvk, Scope: meta.RESTScopeNamespace},
+			},
+			Prune: true,
+		},
+	}
+
+	snapshot := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kept
+  namespace: default
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: created
+  namespace: default
+`
+	err := l.Load(context.Background(), yaml.NewDecoder(strings.NewReader(snapshot)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := dynamicClient.Resource(gvr).Namespace("default").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, item := range list.Items {
+		got = append(got, item.GetName())
+	}
+	sort.Strings(got)
+	want := []string{"created", "kept"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v left after pruning, got %v", want, got)
+	}
+}
+
+func TestLoaderLoadPruneOnlySnapshotKinds(t *testing.T) {
+	configMapGVK := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	configMapGVR := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	secretGVK := schema.GroupVersionKind{Version: "v1", Kind: "Secret"}
+	secretGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+	newObject := func(gvk schema.GroupVersionKind, namespace, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		return obj
+	}
+
+	tests := []struct {
+		name        string
+		deleteErr   error
+		dryRun      bool
+		wantErr     bool
+		wantConfigs []string
+	}{
+		{
+			name:        "prune",
+			wantConfigs: []string{"default/kept", "kube-system/orphan"},
+		},
+		{
+			name:        "delete failed",
+			deleteErr:   apierrors.NewForbidden(configMapGVR.GroupResource(), "orphan", nil),
+			wantErr:     true,
+			wantConfigs: []string{"default/kept", "default/orphan", "kube-system/orphan"},
+		},
+		{
+			name:        "dry run",
+			dryRun:      true,
+			wantConfigs: []string{"default/orphan", "kube-system/orphan"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+				map[schema.GroupVersionResource]string{
+					configMapGVR: "ConfigMapList",
+					secretGVR:    "SecretList",
+				},
+				newObject(configMapGVK, "default", "orphan"),
+				newObject(configMapGVK, "kube-system", "orphan"),
+				newObject(secretGVK, "default", "orphan"),
+			)
+			if tt.deleteErr != nil {
+				dynamicClient.PrependReactor("delete", "configmaps", func(action clienttesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tt.deleteErr
+				})
+			}
+
+			restMapper := meta.NewDefaultRESTMapper(nil)
+			restMapper.Add(configMapGVK, meta.RESTScopeNamespace)
+			restMapper.Add(secretGVK, meta.RESTScopeNamespace)
+
+			l := &Loader{
+				exist:         map[uniqueKey]types.UID{},
+				pending:       map[uniqueKey][]*unstructured.Unstructured{},
+				restMapper:    restMapper,
+				dynamicClient: dynamicClient,
+				loadConfig: LoadConfig{
+					Filters: []*meta.RESTMapping{
+						{Resource: configMapGVR, GroupVersionKind: configMapGVK, Scope: meta.RESTScopeNamespace},
+						{Resource: secretGVR, GroupVersionKind: secretGVK, Scope: meta.RESTScopeNamespace},
+					},
+					Prune:  true,
+					DryRun: tt.dryRun,
+				},
+			}
+
+			// The snapshot has no secrets, so the secrets in the cluster are kept.
+			snapshot := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kept
+  namespace: default
+`
+			err := l.Load(context.Background(), yaml.NewDecoder(strings.NewReader(snapshot)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			list, err := dynamicClient.Resource(configMapGVR).List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, item := range list.Items {
+				got = append(got, item.GetNamespace()+"/"+item.GetName())
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.wantConfigs) {
+				t.Errorf("want configmaps %v left after pruning, got %v", tt.wantConfigs, got)
+			}
+
+			_, err = dynamicClient.Resource(secretGVR).Namespace("default").Get(context.Background(), "orphan", metav1.GetOptions{})
+			if err != nil {
+				t.Errorf("want the secret kept: %v", err)
+			}
+		})
+	}
+}
+
+func TestIsProtected(t *testing.T) {
+	tests := []struct {
+		name      string
+		gk        schema.GroupKind
+		namespace string
+		objName   string
+		want      bool
+	}{
+		{
+			name:      "resource in kube-system",
+			gk:        schema.GroupKind{Kind: "ConfigMap"},
+			namespace: "kube-system",
+			objName:   "foo",
+			want:      true,
+		},
+		{
+			name:      "lease in kube-node-lease",
+			gk:        schema.GroupKind{Group: "coordination.k8s.io", Kind: "Lease"},
+			namespace: "kube-node-lease",
+			objName:   "node-0",
+			want:      true,
+		},
+		{
+			name:    "kube-public namespace",
+			gk:      schema.GroupKind{Kind: "Namespace"},
+			objName: "kube-public",
+			want:    true,
+		},
+		{
+			name:    "user namespace",
+			gk:      schema.GroupKind{Kind: "Namespace"},
+			objName: "foo",
+		},
+		{
+			name:      "kubernetes service",
+			gk:        schema.GroupKind{Kind: "Service"},
+			namespace: "default",
+			objName:   "kubernetes",
+			want:      true,
+		},
+		{
+			name:      "kubernetes endpoints",
+			gk:        schema.GroupKind{Kind: "Endpoints"},
+			namespace: "default",
+			objName:   "kubernetes",
+			want:      true,
+		},
+		{
+			name:      "kubernetes service in another namespace",
+			gk:        schema.GroupKind{Kind: "Service"},
+			namespace: "foo",
+			objName:   "kubernetes",
+		},
+		{
+			name:    "system cluster role",
+			gk:      schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+			objName: "system:node",
+			want:    true,
+		},
+		{
+			name:    "user cluster role binding",
+			gk:      schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"},
+			objName: "foo",
+		},
+		{
+			name:      "system named configmap",
+			gk:        schema.GroupKind{Kind: "ConfigMap"},
+			namespace: "default",
+			objName:   "system:foo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isProtected(tt.gk, tt.namespace, tt.objName); got != tt.want {
+				t.Errorf("isProtected() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
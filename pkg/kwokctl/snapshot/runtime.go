@@ -52,5 +52,12 @@ var Resources = []string{
 	"endpoints",
 }
 
+// FlowControlResources is the resources of API Priority and Fairness,
+// which are saved by default and only loaded at startup when the flow control of the apiserver is enabled.
+var FlowControlResources = []string{
+	"prioritylevelconfiguration.flowcontrol.apiserver.k8s.io",
+	"flowschema.flowcontrol.apiserver.k8s.io",
+}
+
 // ErrNotHandled is returned when a resource is not handled
 var ErrNotHandled = fmt.Errorf("resource not handled")
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// DefaultS3Endpoint is the endpoint of AWS S3
+const DefaultS3Endpoint = "s3.amazonaws.com"
+
+// s3PartSize is the size of the parts uploaded, which is buffered in memory as the size of the snapshot is unknown
+const s3PartSize = 16 << 20
+
+// S3Config is the location of the snapshots in an S3 compatible object storage
+type S3Config struct {
+	// Endpoint is the endpoint of the object storage, e.g. s3.amazonaws.com,
+	// it is accessed over https unless it is prefixed with http://.
+	Endpoint string
+	// Bucket is the bucket of the snapshots
+	Bucket string
+	// Prefix is the prefix of the keys of the snapshots in the bucket
+	Prefix string
+}
+
+// Key returns the key of the snapshot with the name in the bucket
+func (c S3Config) Key(name string) string {
+	return path.Join(c.Prefix, name)
+}
+
+// URL returns the s3:// URL of the snapshot with the name
+func (c S3Config) URL(name string) string {
+	return "s3://" + c.Bucket + "/" + c.Key(name)
+}
+
+// UploadCommand returns the aws command that uploads the snapshot with the name from stdin,
+// which is printed in dry-run.
+func (c S3Config) UploadCommand(name string) string {
+	return fmt.Sprintf("aws s3 cp - %s --endpoint-url %s", c.URL(name), c.endpointURL())
+}
+
+// DownloadCommand returns the aws command that downloads the snapshot with the name to stdout,
+// which is printed in dry-run.
+func (c S3Config) DownloadCommand(name string) string {
+	return fmt.Sprintf("aws s3 cp %s - --endpoint-url %s", c.URL(name), c.endpointURL())
+}
+
+func (c S3Config) endpointURL() string {
+	if strings.HasPrefix(c.Endpoint, "http://") || strings.HasPrefix(c.Endpoint, "https://") {
+		return c.Endpoint
+	}
+	return "https://" + c.Endpoint
+}
+
+// client returns the client of the object storage,
+// the credentials are read from the environment variables of AWS or MinIO, or the credentials file of AWS.
+func (c S3Config) client() (*minio.Client, error) {
+	u, err := url.Parse(c.endpointURL())
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint %q: %w", c.Endpoint, err)
+	}
+	creds := credentials.NewChainCredentials([]credentials.Provider{
+		&credentials.EnvAWS{},
+		&credentials.EnvMinio{},
+		&credentials.FileAWSCredentials{},
+	})
+	client, err := minio.New(u.Host, &minio.Options{
+		Creds:  creds,
+		Secure: u.Scheme == "https",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 client: %w", err)
+	}
+	return client, nil
+}
+
+// S3Writer uploads the data written to it as a snapshot in the object storage
+type S3Writer struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+// NewS3Writer returns a writer that streams the snapshot with the name to the object storage,
+// the upload is completed by Close or aborted by Abort.
+func NewS3Writer(ctx context.Context, conf S3Config, name string) (*S3Writer, error) {
+	client, err := conf.client()
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	w := &S3Writer{
+		pw:   pw,
+		done: make(chan error, 1),
+	}
+	go func() {
+		_, err := client.PutObject(ctx, conf.Bucket, conf.Key(name), pr, -1, minio.PutObjectOptions{
+			ContentType: "application/octet-stream",
+			PartSize:    s3PartSize,
+		})
+		if err != nil {
+			err = fmt.Errorf("failed to upload snapshot to %s: %w", conf.URL(name), err)
+		}
+		_ = pr.CloseWithError(err)
+		w.done <- err
+	}()
+	return w, nil
+}
+
+// Write writes the data to the upload
+func (w *S3Writer) Write(p []byte) (int, error) {
+	return w.pw.Write(p)
+}
+
+// Close completes the upload and waits for it
+func (w *S3Writer) Close() error {
+	_ = w.pw.Close()
+	return <-w.done
+}
+
+// Abort aborts the upload with the error, the uploaded parts are removed
+func (w *S3Writer) Abort(err error) {
+	_ = w.pw.CloseWithError(err)
+	<-w.done
+}
+
+// NewS3Reader returns a reader that streams the snapshot with the name from the object storage
+func NewS3Reader(ctx context.Context, conf S3Config, name string) (io.ReadCloser, error) {
+	client, err := conf.client()
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := client.GetObject(ctx, conf.Bucket, conf.Key(name), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download snapshot from %s: %w", conf.URL(name), err)
+	}
+	_, err = obj.Stat()
+	if err != nil {
+		_ = obj.Close()
+		return nil, fmt.Errorf("failed to download snapshot from %s: %w", conf.URL(name), err)
+	}
+	return obj, nil
+}
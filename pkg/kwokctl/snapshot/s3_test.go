@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 is an object storage serving the requests of the multipart upload and the download
+type fakeS3 struct {
+	mut     sync.Mutex
+	parts   map[int][]byte
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	q := r.URL.Query()
+	switch {
+	case r.Method == http.MethodGet && q.Has("location"):
+		_, _ = fmt.Fprint(w, `<LocationConstraint></LocationConstraint>`)
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		f.parts = map[int][]byte{}
+		_, _ = fmt.Fprint(w, `<InitiateMultipartUploadResult><UploadId>upload</UploadId></InitiateMultipartUploadResult>`)
+	case r.Method == http.MethodPut && q.Has("partNumber"):
+		n, _ := strconv.Atoi(q.Get("partNumber"))
+		data, _ := io.ReadAll(r.Body)
+		f.parts[n] = data
+		w.Header().Set("ETag", fmt.Sprintf(`"part-%d"`, n))
+	case r.Method == http.MethodPost && q.Has("uploadId"):
+		numbers := make([]int, 0, len(f.parts))
+		for n := range f.parts {
+			numbers = append(numbers, n)
+		}
+		sort.Ints(numbers)
+		var data []byte
+		for _, n := range numbers {
+			data = append(data, f.parts[n]...)
+		}
+		f.objects[r.URL.Path] = data
+		f.parts = nil
+		_, _ = fmt.Fprint(w, `<CompleteMultipartUploadResult><Bucket>snapshots</Bucket><ETag>"object"</ETag></CompleteMultipartUploadResult>`)
+	case r.Method == http.MethodDelete && q.Has("uploadId"):
+		f.parts = nil
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.Header().Set("ETag", `"object"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		_, _ = w.Write(data)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func TestS3WriterAndReader(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+
+	fake := &fakeS3{
+		objects: map[string][]byte{},
+	}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	ctx := context.Background()
+	conf := S3Config{
+		Endpoint: server.URL,
+		Bucket:   "snapshots",
+		Prefix:   "ci/kwok",
+	}
+	want := bytes.Repeat([]byte("snapshot data "), 1024)
+
+	w, err := NewS3Writer(ctx, conf, "cluster.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = w.Write(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.objects["/snapshots/ci/kwok/cluster.db"]; !bytes.Equal(got, want) {
+		t.Fatalf("uploaded %d bytes, want %d bytes", len(got), len(want))
+	}
+
+	r, err := NewS3Reader(ctx, conf, "cluster.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(r)
+	_ = r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("downloaded %d bytes, want %d bytes", len(got), len(want))
+	}
+
+	w, err = NewS3Writer(ctx, conf, "aborted.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = w.Write(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Abort(errors.New("save failed"))
+	if _, ok := fake.objects["/snapshots/ci/kwok/aborted.db"]; ok {
+		t.Errorf("want the aborted snapshot not to be uploaded")
+	}
+
+	_, err = NewS3Reader(ctx, conf, "missing.db")
+	if err == nil {
+		t.Errorf("want error for the missing snapshot")
+	}
+}
+
+func TestS3ConfigCommands(t *testing.T) {
+	conf := S3Config{
+		Endpoint: DefaultS3Endpoint,
+		Bucket:   "snapshots",
+		Prefix:   "ci",
+	}
+	if got, want := conf.UploadCommand("cluster.db"), "aws s3 cp - s3://snapshots/ci/cluster.db --endpoint-url https://s3.amazonaws.com"; got != want {
+		t.Errorf("UploadCommand() = %q, want %q", got, want)
+	}
+
+	conf.Endpoint = "http://127.0.0.1:9000"
+	conf.Prefix = ""
+	if got, want := conf.DownloadCommand("cluster.db"), "aws s3 cp s3://snapshots/cluster.db - --endpoint-url http://127.0.0.1:9000"; got != want {
+		t.Errorf("DownloadCommand() = %q, want %q", got, want)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/net"
@@ -43,7 +44,6 @@ import (
 	"sigs.k8s.io/kwok/pkg/utils/heap"
 	"sigs.k8s.io/kwok/pkg/utils/patch"
 	"sigs.k8s.io/kwok/pkg/utils/queue"
-	"sigs.k8s.io/kwok/pkg/utils/yaml"
 )
 
 // PagerConfig is the configuration of the list pager.
@@ -59,6 +59,14 @@ type SaveConfig struct {
 	Clientset   client.Clientset
 	PagerConfig *PagerConfig
 	Filters     []*meta.RESTMapping
+	// Anonymizer redacts the sensitive data of the resources if it is not nil.
+	Anonymizer *Anonymizer
+}
+
+// Encoder encodes the resources to the snapshot, e.g. a yaml.Encoder writes a YAML stream
+// and a json.Encoder writes one JSON object per line.
+type Encoder interface {
+	Encode(obj any) error
 }
 
 // Saver is a snapshot saver.
@@ -99,7 +107,7 @@ func NewSaver(saveConfig SaveConfig) (*Saver, error) {
 }
 
 // Save saves the snapshot of cluster
-func (s *Saver) Save(ctx context.Context, encoder *yaml.Encoder, tracks map[*meta.RESTMapping]*TrackData) error {
+func (s *Saver) Save(ctx context.Context, encoder Encoder, tracks map[*meta.RESTMapping]*TrackData) error {
 	logger := log.FromContext(ctx)
 
 	if tracks != nil {
@@ -163,6 +171,7 @@ func (s *Saver) Save(ctx context.Context, encoder *yaml.Encoder, tracks map[*met
 		}
 		count := 0
 		if err := listPager.EachListItem(ctx, metav1.ListOptions{}, func(obj runtime.Object) error {
+			s.anonymize(obj)
 			if o, ok := obj.(metav1.Object); ok {
 				o.SetResourceVersion("")
 				if track != nil {
@@ -199,7 +208,7 @@ func (s *Saver) Save(ctx context.Context, encoder *yaml.Encoder, tracks map[*met
 }
 
 // Record records the snapshot of cluster.
-func (s *Saver) Record(ctx context.Context, encoder *yaml.Encoder, tracks map[*meta.RESTMapping]*TrackData) error {
+func (s *Saver) Record(ctx context.Context, encoder Encoder, tracks map[*meta.RESTMapping]*TrackData) error {
 	logger := log.FromContext(ctx)
 
 	startTime := time.Now()
@@ -318,6 +327,7 @@ func (s *Saver) buildResourcePatch(ctx context.Context, event watch.Event, patch
 
 		switch event.Type {
 		case watch.Added, watch.Modified:
+			s.anonymize(event.Object)
 			obj.SetResourceVersion("")
 
 			err := rp.SetContent(obj, track, patchMeta)
@@ -334,3 +344,13 @@ func (s *Saver) buildResourcePatch(ctx context.Context, event watch.Event, patch
 		}
 	}
 }
+
+// anonymize redacts the sensitive data of the resource if the anonymizer is set
+func (s *Saver) anonymize(obj runtime.Object) {
+	if s.saveConfig.Anonymizer == nil {
+		return
+	}
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		s.saveConfig.Anonymizer.Anonymize(u)
+	}
+}
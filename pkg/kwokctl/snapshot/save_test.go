@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"sigs.k8s.io/kwok/pkg/apis/action/v1alpha1"
+	"sigs.k8s.io/kwok/pkg/kwokctl/recording"
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/queue"
+)
+
+func TestSaverBuildResourcePatchWorker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	startTime := time.Unix(0, 0)
+	clock := clocktesting.NewFakePassiveClock(startTime)
+	s := &Saver{
+		clock: clock,
+	}
+
+	patchMeta, err := strategicpatch.NewPatchMetaFromStruct(corev1.Pod{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	w := watch.NewFakeWithChanSize(3, false)
+	que := queue.NewQueue[*recording.ResourcePatch]()
+	track := map[log.ObjectRef]json.RawMessage{}
+
+	go s.buildResourcePatchWorker(ctx, w, que, patchMeta, gvr, startTime, track)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "pod",
+			Namespace:       "default",
+			ResourceVersion: "1",
+		},
+	}
+
+	w.Add(pod.DeepCopy())
+	want := []v1alpha1.PatchMethod{v1alpha1.PatchMethodCreate}
+	got := waitResourcePatches(t, que, 1)
+
+	clock.SetTime(startTime.Add(time.Second))
+	pod.Labels = map[string]string{"app": "test"}
+	w.Modify(pod.DeepCopy())
+	want = append(want, v1alpha1.PatchMethodPatch)
+	got = append(got, waitResourcePatches(t, que, 1)...)
+
+	clock.SetTime(startTime.Add(2 * time.Second))
+	w.Delete(pod.DeepCopy())
+	want = append(want, v1alpha1.PatchMethodDelete)
+	got = append(got, waitResourcePatches(t, que, 1)...)
+
+	for i, rp := range got {
+		if rp.Method != want[i] {
+			t.Errorf("event %d: want method %q, got %q", i, want[i], rp.Method)
+		}
+		if rp.GetDuration() != time.Duration(i)*time.Second {
+			t.Errorf("event %d: want duration %s, got %s", i, time.Duration(i)*time.Second, rp.GetDuration())
+		}
+		if name, ns := rp.GetTargetName(); name != "pod" || ns != "default" {
+			t.Errorf("event %d: want target default/pod, got %s/%s", i, ns, name)
+		}
+		if rp.GetTargetGroupVersionResource() != gvr {
+			t.Errorf("event %d: want resource %v, got %v", i, gvr, rp.GetTargetGroupVersionResource())
+		}
+	}
+
+	if len(track) != 0 {
+		t.Errorf("want empty track after delete, got %d", len(track))
+	}
+}
+
+func waitResourcePatches(t *testing.T, que queue.Queue[*recording.ResourcePatch], n int) []*recording.ResourcePatch {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	out := make([]*recording.ResourcePatch, 0, n)
+	for len(out) < n {
+		rp, ok := que.GetOrWaitWithDone(ctx.Done())
+		if !ok {
+			t.Fatalf("timeout waiting for resource patches, got %d, want %d", len(out), n)
+		}
+		out = append(out, rp)
+	}
+	return out
+}
+
+func TestSaverSaveJSONLines(t *testing.T) {
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	objs := []runtime.Object{}
+	for _, name := range []string{"foo", "bar"} {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		obj.SetNamespace("default")
+		obj.SetName(name)
+		obj.SetCreationTimestamp(metav1.NewTime(startTime.Add(time.Second)))
+		_ = unstructured.SetNestedField(obj.Object, "value", "data", "key")
+		objs = append(objs, obj)
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "ConfigMapList"},
+		objs...,
+	)
+
+	s := &Saver{
+		dynamicClient: dynamicClient,
+		saveConfig: SaveConfig{
+			Filters: []*meta.RESTMapping{
+				{Resource: gvr, GroupVersionKind: gvk, Scope: meta.RESTScopeNamespace},
+			},
+		},
+	}
+
+	buf := bytes.NewBuffer(nil)
+	writer := recording.NewWriteHook(buf, func(b []byte) []byte {
+		return recording.ReplaceTimeToRelative(startTime, b)
+	})
+	err := s.Save(context.Background(), json.NewEncoder(writer), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(objs) {
+		t.Fatalf("want %d lines, got %q", len(objs), buf.String())
+	}
+	for _, line := range lines {
+		obj := &unstructured.Unstructured{}
+		err := obj.UnmarshalJSON([]byte(line))
+		if err != nil {
+			t.Fatalf("want one JSON object per line, got %q: %v", line, err)
+		}
+		if obj.GetKind() != "ConfigMap" {
+			t.Errorf("want kind ConfigMap, got %q", obj.GetKind())
+		}
+		got, _, _ := unstructured.NestedString(obj.Object, "metadata", "creationTimestamp")
+		if got != "$(time-offset-nanosecond 1000000000)" {
+			t.Errorf("want the creationTimestamp relative to the start time, got %q", got)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+
+	"sigs.k8s.io/kwok/pkg/log"
+	"sigs.k8s.io/kwok/pkg/utils/wait"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+
+	// retryInterval is the interval to re-establish the watch when it is closed by the server.
+	retryInterval = time.Second
+)
+
+// Kind is the kind of resource to wait for.
+type Kind string
+
+// The kinds of resource supported to wait for.
+const (
+	KindNode Kind = "node"
+	KindPod  Kind = "pod"
+)
+
+// Condition is the condition to wait for.
+type Condition struct {
+	// Type is the type of the status condition, e.g. Ready.
+	Type string
+	// Status is the status of the status condition, defaults to True.
+	Status string
+	// Phase is the phase of the pod, e.g. Running.
+	Phase string
+}
+
+// String returns the condition in the form of the --for flag.
+func (c Condition) String() string {
+	if c.Phase != "" {
+		return "phase=" + c.Phase
+	}
+	return "condition=" + c.Type + "=" + c.Status
+}
+
+// KubectlFor returns the condition in the form of the --for flag of kubectl wait,
+// which has no phase=<phase> but waits for the phase with a jsonpath.
+func (c Condition) KubectlFor() string {
+	if c.Phase != "" {
+		return "jsonpath='{.status.phase}'=" + c.Phase
+	}
+	return "condition=" + c.Type + "=" + c.Status
+}
+
+// ParseCondition parses the condition from the form of
+// condition=<type>[=<status>] or phase=<phase>.
+func ParseCondition(s string) (Condition, error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || value == "" {
+		return Condition{}, fmt.Errorf("invalid condition %q, must be condition=<type>[=<status>] or phase=<phase>", s)
+	}
+	switch key {
+	case "condition":
+		typ, status, ok := strings.Cut(value, "=")
+		if !ok {
+			status = string(corev1.ConditionTrue)
+		}
+		if typ == "" || status == "" {
+			return Condition{}, fmt.Errorf("invalid condition %q, must be condition=<type>[=<status>]", s)
+		}
+		return Condition{
+			Type:   typ,
+			Status: status,
+		}, nil
+	case "phase":
+		return Condition{
+			Phase: value,
+		}, nil
+	default:
+		return Condition{}, fmt.Errorf("unsupported condition %q, must be condition=<type>[=<status>] or phase=<phase>", s)
+	}
+}
+
+// ParseResource parses the resource from the form of <kind>/<name>.
+func ParseResource(s string) (Kind, string, error) {
+	kind, name, ok := strings.Cut(s, "/")
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("invalid resource %q, must be <kind>/<name>", s)
+	}
+	switch strings.ToLower(kind) {
+	case "node", "nodes", "no":
+		return KindNode, name, nil
+	case "pod", "pods", "po":
+		return KindPod, name, nil
+	default:
+		return "", "", fmt.Errorf("unsupported resource kind %q, must be one of [node, pod]", kind)
+	}
+}
+
+// Config is the configuration for waiting for a resource.
+type Config struct {
+	// Kind is the kind of the resource.
+	Kind Kind
+	// Namespace is the namespace of the resource, only used for pods.
+	Namespace string
+	// Name is the name of the resource.
+	Name string
+	// Condition is the condition to wait for.
+	Condition Condition
+	// Timeout is the timeout to wait for the condition.
+	Timeout time.Duration
+}
+
+// Wait watches the resource until it matches the condition or the timeout is reached.
+func Wait(ctx context.Context, clientset kubernetes.Interface, conf Config) error {
+	if conf.Timeout <= 0 {
+		conf.Timeout = defaultTimeout
+	}
+
+	var watchFunc func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	switch conf.Kind {
+	case KindNode:
+		if conf.Condition.Phase != "" {
+			return fmt.Errorf("unsupported condition %q for %s", conf.Condition, conf.Kind)
+		}
+		watchFunc = clientset.CoreV1().Nodes().Watch
+	case KindPod:
+		if conf.Namespace == "" {
+			conf.Namespace = corev1.NamespaceDefault
+		}
+		watchFunc = clientset.CoreV1().Pods(conf.Namespace).Watch
+	default:
+		return fmt.Errorf("unsupported resource kind %q", conf.Kind)
+	}
+
+	logger := log.FromContext(ctx)
+	opts := metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", conf.Name).String(),
+	}
+	err := wait.Poll(ctx, func(ctx context.Context) (bool, error) {
+		w, err := watchFunc(ctx, opts)
+		if err != nil {
+			logger.Warn("Failed to watch", "kind", conf.Kind, "name", conf.Name, "err", err)
+			return false, nil
+		}
+		defer w.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return false, ctx.Err()
+			case event, ok := <-w.ResultChan():
+				if !ok {
+					return false, nil
+				}
+				if event.Type == watch.Error {
+					logger.Warn("Watch error", "kind", conf.Kind, "name", conf.Name, "err", event.Object)
+					return false, nil
+				}
+				if event.Type == watch.Deleted {
+					continue
+				}
+				if match(event.Object, conf.Name, conf.Condition) {
+					return true, nil
+				}
+			}
+		}
+	},
+		wait.WithTimeout(conf.Timeout),
+		wait.WithInterval(retryInterval),
+		wait.WithImmediate(),
+	)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("timed out waiting for %s/%s to be %s", conf.Kind, conf.Name, conf.Condition)
+		}
+		return err
+	}
+	return nil
+}
+
+func match(obj any, name string, cond Condition) bool {
+	switch o := obj.(type) {
+	case *corev1.Node:
+		if o.Name != name {
+			return false
+		}
+		for _, c := range o.Status.Conditions {
+			if strings.EqualFold(string(c.Type), cond.Type) {
+				return strings.EqualFold(string(c.Status), cond.Status)
+			}
+		}
+	case *corev1.Pod:
+		if o.Name != name {
+			return false
+		}
+		if cond.Phase != "" {
+			return strings.EqualFold(string(o.Status.Phase), cond.Phase)
+		}
+		for _, c := range o.Status.Conditions {
+			if strings.EqualFold(string(c.Type), cond.Type) {
+				return strings.EqualFold(string(c.Status), cond.Status)
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Condition
+		wantErr bool
+	}{
+		{
+			input: "condition=Ready",
+			want:  Condition{Type: "Ready", Status: "True"},
+		},
+		{
+			input: "condition=Ready=False",
+			want:  Condition{Type: "Ready", Status: "False"},
+		},
+		{
+			input: "phase=Running",
+			want:  Condition{Phase: "Running"},
+		},
+		{
+			input:   "condition=",
+			wantErr: true,
+		},
+		{
+			input:   "condition==True",
+			wantErr: true,
+		},
+		{
+			input:   "delete",
+			wantErr: true,
+		},
+		{
+			input:   "jsonpath={.status}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCondition(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCondition() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionKubectlFor(t *testing.T) {
+	tests := []struct {
+		cond Condition
+		want string
+	}{
+		{
+			cond: Condition{Type: "Ready", Status: "True"},
+			want: "condition=Ready=True",
+		},
+		{
+			cond: Condition{Phase: "Running"},
+			want: "jsonpath='{.status.phase}'=Running",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.cond.KubectlFor(); got != tt.want {
+				t.Errorf("KubectlFor() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseResource(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantKind Kind
+		wantName string
+		wantErr  bool
+	}{
+		{
+			input:    "node/foo",
+			wantKind: KindNode,
+			wantName: "foo",
+		},
+		{
+			input:    "pods/bar",
+			wantKind: KindPod,
+			wantName: "bar",
+		},
+		{
+			input:   "foo",
+			wantErr: true,
+		},
+		{
+			input:   "deployment/foo",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			kind, name, err := ParseResource(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseResource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if kind != tt.wantKind || name != tt.wantName {
+				t.Errorf("ParseResource() got = %s/%s, want %s/%s", kind, name, tt.wantKind, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestWait(t *testing.T) {
+	notReadyNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionFalse},
+			},
+		},
+	}
+	readyNode := notReadyNode.DeepCopy()
+	readyNode.Status.Conditions[0].Status = corev1.ConditionTrue
+	otherNode := readyNode.DeepCopy()
+	otherNode.Name = "other"
+
+	pendingPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+		},
+	}
+	runningPod := pendingPod.DeepCopy()
+	runningPod.Status.Phase = corev1.PodRunning
+	runningPod.Status.Conditions = []corev1.PodCondition{
+		{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+	}
+
+	tests := []struct {
+		name     string
+		resource string
+		events   []watch.Event
+		conf     Config
+		wantErr  bool
+	}{
+		{
+			name:     "node ready",
+			resource: "nodes",
+			events: []watch.Event{
+				{Type: watch.Added, Object: notReadyNode},
+				{Type: watch.Modified, Object: otherNode},
+				{Type: watch.Modified, Object: readyNode},
+			},
+			conf: Config{
+				Kind:      KindNode,
+				Name:      "foo",
+				Condition: Condition{Type: "Ready", Status: "True"},
+			},
+		},
+		{
+			name:     "node not ready",
+			resource: "nodes",
+			events: []watch.Event{
+				{Type: watch.Added, Object: notReadyNode},
+				{Type: watch.Modified, Object: otherNode},
+			},
+			conf: Config{
+				Kind:      KindNode,
+				Name:      "foo",
+				Condition: Condition{Type: "Ready", Status: "True"},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "pod phase",
+			resource: "pods",
+			events: []watch.Event{
+				{Type: watch.Added, Object: pendingPod},
+				{Type: watch.Modified, Object: runningPod},
+			},
+			conf: Config{
+				Kind:      KindPod,
+				Name:      "foo",
+				Condition: Condition{Phase: "Running"},
+			},
+		},
+		{
+			name:     "pod generic condition",
+			resource: "pods",
+			events: []watch.Event{
+				{Type: watch.Added, Object: pendingPod},
+				{Type: watch.Modified, Object: runningPod},
+			},
+			conf: Config{
+				Kind:      KindPod,
+				Name:      "foo",
+				Condition: Condition{Type: "containersready", Status: "true"},
+			},
+		},
+		{
+			name:     "pod deleted",
+			resource: "pods",
+			events: []watch.Event{
+				{Type: watch.Deleted, Object: runningPod},
+			},
+			conf: Config{
+				Kind:      KindPod,
+				Name:      "foo",
+				Condition: Condition{Phase: "Running"},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "node phase",
+			resource: "nodes",
+			conf: Config{
+				Kind:      KindNode,
+				Name:      "foo",
+				Condition: Condition{Phase: "Running"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset()
+			clientset.PrependWatchReactor(tt.resource, func(action clienttesting.Action) (bool, watch.Interface, error) {
+				w := watch.NewFakeWithChanSize(len(tt.events), false)
+				for _, event := range tt.events {
+					w.Action(event.Type, event.Object)
+				}
+				return true, w, nil
+			})
+
+			conf := tt.conf
+			conf.Timeout = 100 * time.Millisecond
+			err := Wait(context.Background(), clientset, conf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Wait() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
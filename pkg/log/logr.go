@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"context"
+	"log/slog" //nolint:depguard
+	"runtime"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// ToLogr returns a logr.Logger which writes to the given Logger,
+// it can be passed to the libraries that expect a logr.Logger, such as client-go and controller-runtime.
+func ToLogr(logger *Logger) logr.Logger {
+	return logr.New(NewLogrSink(logger))
+}
+
+// NewLogrSink returns a logr.LogSink which writes to the given Logger.
+// The verbosity of logr is mapped to the level below LevelInfo,
+// e.g. V(4) is logged as LevelDebug.
+func NewLogrSink(logger *Logger) logr.LogSink {
+	return &logrSink{
+		handler: logger.handler,
+	}
+}
+
+// logrSink is an adapter of logr.LogSink for the Logger.
+type logrSink struct {
+	handler   slog.Handler
+	name      string
+	callDepth int
+}
+
+var (
+	_ logr.LogSink          = (*logrSink)(nil)
+	_ logr.CallDepthLogSink = (*logrSink)(nil)
+)
+
+// Init receives runtime info about the logr library.
+func (s *logrSink) Init(info logr.RuntimeInfo) {
+	s.callDepth = info.CallDepth
+}
+
+// Enabled tests whether this LogSink is enabled at the specified V-level.
+func (s *logrSink) Enabled(level int) bool {
+	return s.handler.Enabled(context.Background(), logrLevel(level))
+}
+
+// Info logs a non-error message with the given key/value pairs as context.
+func (s *logrSink) Info(level int, msg string, keysAndValues ...any) {
+	s.log(logrLevel(level), msg, keysAndValues...)
+}
+
+// Error logs an error, with the given message and key/value pairs as context.
+func (s *logrSink) Error(err error, msg string, keysAndValues ...any) {
+	if err != nil {
+		keysAndValues = append(keysAndValues[:len(keysAndValues):len(keysAndValues)], slog.Any("err", err))
+	}
+	s.log(LevelError, msg, keysAndValues...)
+}
+
+// WithValues returns a new LogSink with additional key/value pairs.
+func (s *logrSink) WithValues(keysAndValues ...any) logr.LogSink {
+	var (
+		attr  slog.Attr
+		attrs []slog.Attr
+	)
+	for len(keysAndValues) > 0 {
+		attr, keysAndValues = argsToAttr(keysAndValues)
+		attrs = append(attrs, attr)
+	}
+	n := *s
+	n.handler = s.handler.WithAttrs(attrs)
+	return &n
+}
+
+// WithName returns a new LogSink with the specified name appended.
+func (s *logrSink) WithName(name string) logr.LogSink {
+	n := *s
+	if n.name == "" {
+		n.name = name
+	} else {
+		n.name += "/" + name
+	}
+	return &n
+}
+
+// WithCallDepth returns a LogSink that will offset the call stack by the specified number of frames.
+func (s *logrSink) WithCallDepth(depth int) logr.LogSink {
+	n := *s
+	n.callDepth += depth
+	return &n
+}
+
+// log must always be called directly by Info or Error,
+// because it uses a fixed call depth to obtain the pc.
+func (s *logrSink) log(level Level, msg string, args ...any) {
+	if !s.handler.Enabled(context.Background(), level) {
+		return
+	}
+	var pcs [1]uintptr
+	// skip [runtime.Callers, this function, Info or Error, the frames of logr]
+	runtime.Callers(3+s.callDepth, pcs[:])
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	if s.name != "" {
+		r.AddAttrs(slog.String("logger", s.name))
+	}
+	r.Add(args...)
+	_ = s.handler.Handle(context.Background(), r)
+}
+
+// logrLevel converts the verbosity of logr to the Level.
+func logrLevel(level int) Level {
+	return LevelInfo - Level(level)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestToLogr(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(buf *bytes.Buffer)
+		want []map[string]any
+	}{
+		{
+			name: "info",
+			log: func(buf *bytes.Buffer) {
+				logger := ToLogr(NewLogger(buf, LevelInfo))
+				logger.WithName("client").WithValues("key", "value").Info("message", "foo", "bar")
+			},
+			want: []map[string]any{
+				{"level": "INFO", "msg": "message", "logger": "client", "key": "value", "foo": "bar"},
+			},
+		},
+		{
+			name: "verbosity",
+			log: func(buf *bytes.Buffer) {
+				logger := ToLogr(NewLogger(buf, LevelDebug))
+				logger.V(4).Info("debug")
+				logger.V(5).Info("ignored")
+			},
+			want: []map[string]any{
+				{"level": "DEBUG", "msg": "debug"},
+			},
+		},
+		{
+			name: "error",
+			log: func(buf *bytes.Buffer) {
+				logger := ToLogr(NewLogger(buf, LevelInfo))
+				logger.Error(errors.New("failed"), "message")
+			},
+			want: []map[string]any{
+				{"level": "ERROR", "msg": "message", "err": "failed"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			tt.log(buf)
+
+			dec := json.NewDecoder(buf)
+			for i, want := range tt.want {
+				got := map[string]any{}
+				err := dec.Decode(&got)
+				if err != nil {
+					t.Fatalf("record %d: %v", i, err)
+				}
+				for k, v := range want {
+					if got[k] != v {
+						t.Errorf("record %d: want %s=%v, got %v", i, k, v, got[k])
+					}
+				}
+				source, _ := got["source"].(map[string]any)
+				if file, _ := source["file"].(string); !strings.HasSuffix(file, "logr_test.go") {
+					t.Errorf("record %d: want source in logr_test.go, got %v", i, source)
+				}
+			}
+			if dec.More() {
+				t.Errorf("unexpected records: %s", buf.String())
+			}
+		})
+	}
+}
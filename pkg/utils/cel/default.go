@@ -33,7 +33,8 @@ const (
 	sinceSecondName = "SinceSecond"
 	unixSecondName  = "UnixSecond"
 
-	quantityName = "Quantity"
+	quantityName      = "Quantity"
+	quantityLowerName = "quantity"
 )
 
 var (
@@ -75,11 +76,12 @@ var (
 		NewResourceList,
 	}
 	DefaultFuncs = map[string][]any{
-		nowName:         {timeNow},
-		mathRandName:    {mathRand},
-		sinceSecondName: {sinceSecond[*corev1.Node], sinceSecond[*corev1.Pod]},
-		unixSecondName:  {unixSecond},
-		quantityName:    {NewQuantityFromString},
+		nowName:           {timeNow},
+		mathRandName:      {mathRand},
+		sinceSecondName:   {sinceSecond[*corev1.Node], sinceSecond[*corev1.Pod]},
+		unixSecondName:    {unixSecond},
+		quantityName:      {NewQuantityFromString},
+		quantityLowerName: {newQuantityFromValue},
 	}
 )
 
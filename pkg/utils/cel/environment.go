@@ -79,9 +79,14 @@ func NewEnvironment(conf EnvironmentConfig) (*Environment, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
 	}
+	checker, err := cel.NewEnv(cel.Lib(registry))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
 
 	e := &Environment{
 		env:          env,
+		checker:      checker,
 		cacheProgram: map[string]cel.Program{},
 	}
 	return e, nil
@@ -90,6 +95,7 @@ func NewEnvironment(conf EnvironmentConfig) (*Environment, error) {
 // Environment is environment in which cel programs are executed
 type Environment struct {
 	env          *easycel.Environment
+	checker      *cel.Env
 	cacheProgram map[string]cel.Program
 	cacheMut     sync.Mutex
 }
@@ -113,6 +119,15 @@ func (e *Environment) Compile(src string) (cel.Program, error) {
 	return program, nil
 }
 
+// OutputType type-checks a cel expression and returns the type of its result
+func (e *Environment) OutputType(src string) (*cel.Type, error) {
+	ast, issue := e.checker.Compile(src)
+	if issue != nil && issue.Err() != nil {
+		return nil, fmt.Errorf("failed to compile expression: %w", issue.Err())
+	}
+	return ast.OutputType(), nil
+}
+
 // AsFloat64 returns the float64 value of a ref.Val
 func AsFloat64(refVal ref.Val) (float64, error) {
 	switch v := refVal.(type) {
@@ -144,3 +159,12 @@ func AsString(refVal ref.Val) (string, error) {
 	}
 	return string(v), nil
 }
+
+// AsBool returns the bool value of a ref.Val
+func AsBool(refVal ref.Val) (bool, error) {
+	v, ok := refVal.(types.Bool)
+	if !ok {
+		return false, fmt.Errorf("unsupported type: %T", refVal)
+	}
+	return bool(v), nil
+}
@@ -18,12 +18,16 @@ package cel
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
+	"strconv"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
 	"github.com/google/cel-go/common/types/traits"
+	"gopkg.in/inf.v0"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
@@ -37,6 +41,9 @@ var (
 		traits.NegatorType,
 		traits.SubtractorType,
 	)
+
+	// ErrInvalidQuantity is returned when a string cannot be parsed as a quantity
+	ErrInvalidQuantity = fmt.Errorf("invalid quantity")
 )
 
 // Quantity is a wrapper around k8s.io/apimachinery/pkg/api/resource.Quantity
@@ -56,32 +63,65 @@ func NewQuantity(q *resource.Quantity) Quantity {
 func NewQuantityFromString(s string) (Quantity, error) {
 	r, err := resource.ParseQuantity(s)
 	if err != nil {
-		return Quantity{}, err
+		return Quantity{}, fmt.Errorf("%w %q: %w", ErrInvalidQuantity, s, err)
 	}
 	return NewQuantity(&r), nil
 }
 
-func newQuantityFromNanoInt64(v int64) Quantity {
-	r := resource.NewScaledQuantity(v, resource.Nano)
-	return NewQuantity(r)
+// newQuantityFromValue creates a new Quantity from a string or returns the given Quantity,
+// so that fields which are already quantities can be passed as well
+func newQuantityFromValue(v any) (Quantity, error) {
+	switch v := v.(type) {
+	case string:
+		return NewQuantityFromString(v)
+	case Quantity:
+		return v, nil
+	}
+	return Quantity{}, fmt.Errorf("%w: unsupported type %T", ErrInvalidQuantity, v)
 }
 
-func newQuantityFromFloat64(v float64) Quantity {
-	r := resource.NewScaledQuantity(int64(v*10e9), resource.Nano)
+func newQuantityFromDec(d *inf.Dec, format resource.Format) Quantity {
+	r := resource.NewDecimalQuantity(*d, format)
 	return NewQuantity(r)
 }
 
-func (q Quantity) nano() int64 {
-	return q.Quantity.ScaledValue(resource.Nano)
+// newDecFromFloat64 returns the shortest decimal that parses back to the float,
+// so that 0.1 is taken as 0.1 instead of its binary approximation
+func newDecFromFloat64(v float64) (*inf.Dec, ref.Val) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil, types.NewErr("quantity overflow")
+	}
+	d, ok := new(inf.Dec).SetString(strconv.FormatFloat(v, 'f', -1, 64))
+	if !ok {
+		return nil, types.NewErr("invalid float %v", v)
+	}
+	return d, nil
+}
+
+// dec returns a copy of the quantity as an inf.Dec, which never overflows
+func (q Quantity) dec() *inf.Dec {
+	r := q.Quantity.DeepCopy()
+	return r.AsDec()
+}
+
+func (q Quantity) multiply(d *inf.Dec) ref.Val {
+	return newQuantityFromDec(new(inf.Dec).Round(new(inf.Dec).Mul(q.dec(), d), 9, inf.RoundDown), q.Quantity.Format)
 }
 
-func (q Quantity) float() float64 {
-	return q.Quantity.AsApproximateFloat64()
+func (q Quantity) divide(d *inf.Dec) ref.Val {
+	if d.Sign() == 0 {
+		return types.NewErr("division by zero")
+	}
+	return newQuantityFromDec(new(inf.Dec).QuoRound(q.dec(), d, 9, inf.RoundDown), q.Quantity.Format)
 }
 
 // ConvertToNative implements the ref.Val interface.
 func (q Quantity) ConvertToNative(typeDesc reflect.Type) (any, error) {
 	switch typeDesc.Kind() {
+	case reflect.Interface:
+		if reflect.TypeOf(q).Implements(typeDesc) {
+			return q, nil
+		}
 	case reflect.Float32, reflect.Float64:
 		v := q.Quantity.AsApproximateFloat64()
 		return reflect.ValueOf(v).Convert(typeDesc).Interface(), nil
@@ -157,13 +197,20 @@ func (q Quantity) Divide(other ref.Val) ref.Val {
 	switch other.Type() {
 	case types.IntType:
 		otherInt := other.(types.Int)
-		return newQuantityFromNanoInt64(q.nano() / int64(otherInt))
+		return q.divide(inf.NewDec(int64(otherInt), 0))
 	case types.UintType:
 		otherUint := other.(types.Uint)
-		return newQuantityFromNanoInt64(q.nano() / int64(otherUint))
+		return q.divide(inf.NewDecBig(new(big.Int).SetUint64(uint64(otherUint)), 0))
 	case types.DoubleType:
 		otherDouble := other.(types.Double)
-		return newQuantityFromFloat64(q.float() / float64(otherDouble))
+		if otherDouble == 0 {
+			return types.NewErr("division by zero")
+		}
+		d, errVal := newDecFromFloat64(float64(otherDouble))
+		if errVal != nil {
+			return errVal
+		}
+		return q.divide(d)
 	}
 
 	return types.MaybeNoSuchOverloadErr(other)
@@ -174,13 +221,17 @@ func (q Quantity) Multiply(other ref.Val) ref.Val {
 	switch other.Type() {
 	case types.IntType:
 		otherInt := other.(types.Int)
-		return newQuantityFromNanoInt64(q.nano() * int64(otherInt))
+		return q.multiply(inf.NewDec(int64(otherInt), 0))
 	case types.UintType:
 		otherUint := other.(types.Uint)
-		return newQuantityFromNanoInt64(q.nano() * int64(otherUint))
+		return q.multiply(inf.NewDecBig(new(big.Int).SetUint64(uint64(otherUint)), 0))
 	case types.DoubleType:
 		otherDouble := other.(types.Double)
-		return newQuantityFromFloat64(q.float() * float64(otherDouble))
+		d, errVal := newDecFromFloat64(float64(otherDouble))
+		if errVal != nil {
+			return errVal
+		}
+		return q.multiply(d)
 	}
 
 	return types.MaybeNoSuchOverloadErr(other)
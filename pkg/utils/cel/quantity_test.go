@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cel
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/google/cel-go/common/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestQuantityExpressions(t *testing.T) {
+	env, err := NewEnvironment(EnvironmentConfig{
+		Types:       slices.Clone(DefaultTypes),
+		Conversions: slices.Clone(DefaultConversions),
+		Funcs:       maps.Clone(DefaultFuncs),
+		Methods:     maps.Clone(FuncsToMethods(DefaultFuncs)),
+		Vars: map[string]any{
+			"pod": corev1.Pod{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("1"),
+							corev1.ResourceMemory: resource.MustParse("16Gi"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		src     string
+		want    any
+		wantErr bool
+	}{
+		{
+			name: "greater than",
+			src:  `quantity(pod.spec.containers[0].resources.requests["cpu"]) > quantity("500m")`,
+			want: true,
+		},
+		{
+			name: "less than",
+			src:  `pod.spec.containers[0].resources.requests["cpu"] < quantity("500m")`,
+			want: false,
+		},
+		{
+			name: "equal with different units",
+			src:  `quantity("1Ki") == quantity("1024")`,
+			want: true,
+		},
+		{
+			name: "add",
+			src:  `quantity("1") + quantity("500m") == quantity("1500m")`,
+			want: true,
+		},
+		{
+			name: "sub",
+			src:  `quantity("1Gi") - quantity("512Mi") == quantity("512Mi")`,
+			want: true,
+		},
+		{
+			name: "multiply large quantity",
+			src:  `pod.spec.containers[0].resources.requests["memory"] * 4 == quantity("64Gi")`,
+			want: true,
+		},
+		{
+			name: "divide large quantity",
+			src:  `pod.spec.containers[0].resources.requests["memory"] / 2 == quantity("8Gi")`,
+			want: true,
+		},
+		{
+			name: "multiply by float",
+			src:  `quantity("1") * 2.0 == quantity("2")`,
+			want: true,
+		},
+		{
+			name: "divide by float",
+			src:  `quantity("1") / 2.0 == quantity("500m")`,
+			want: true,
+		},
+		{
+			name: "multiply by decimal float",
+			src:  `quantity("100m") * 0.1 == quantity("10m")`,
+			want: true,
+		},
+		{
+			name: "divide by float rounds down to nano",
+			src:  `quantity("1") / 3.0 == quantity("333333333n")`,
+			want: true,
+		},
+		{
+			name: "multiply large quantity by float",
+			src:  `pod.spec.containers[0].resources.requests["memory"] * 1.5 == quantity("24Gi")`,
+			want: true,
+		},
+		{
+			name:    "divide by zero",
+			src:     `quantity("1") / 0`,
+			wantErr: true,
+		},
+		{
+			name:    "divide by zero float",
+			src:     `quantity("1") / 0.0`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid quantity",
+			src:     `quantity("1x") > quantity("500m")`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := env.Compile(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _, err := program.ContextEval(context.Background(), map[string]any{
+				"pod": pod,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ContextEval() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != types.DefaultTypeAdapter.NativeToValue(tt.want) {
+				t.Errorf("ContextEval() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewQuantityFromString(t *testing.T) {
+	q, err := NewQuantityFromString("500m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Quantity.MilliValue() != 500 {
+		t.Errorf("NewQuantityFromString() got = %v, want 500m", q.Quantity)
+	}
+
+	_, err = NewQuantityFromString("500x")
+	if !errors.Is(err, ErrInvalidQuantity) {
+		t.Errorf("NewQuantityFromString() error = %v, want %v", err, ErrInvalidQuantity)
+	}
+}
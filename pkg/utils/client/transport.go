@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+)
+
+// TransportConfig is the configuration for tuning the transport to the apiserver.
+type TransportConfig struct {
+	// MaxIdleConnsPerHost is the maximum number of idle connections kept per host.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost is the maximum number of connections per host, zero means unlimited.
+	MaxConnsPerHost int
+	// KeepAlive is the interval of the TCP keep-alive probes.
+	KeepAlive time.Duration
+	// IdleConnTimeout is how long an idle connection is kept before it is closed.
+	IdleConnTimeout time.Duration
+	// DisableHTTP2 disables HTTP/2, so that the requests are spread over multiple connections.
+	DisableHTTP2 bool
+}
+
+// ConfigureTransport replaces the transport of the rest config with one tuned by the given config.
+// The TLS options of the rest config are moved into the transport,
+// because client-go does not allow them to be used together with a custom transport.
+func ConfigureTransport(restConfig *rest.Config, conf TransportConfig) error {
+	if restConfig.Transport != nil {
+		return fmt.Errorf("the rest config already has a custom transport")
+	}
+
+	tlsConfig, err := rest.TLSConfigFor(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to get TLS config: %w", err)
+	}
+	if conf.DisableHTTP2 && tlsConfig != nil {
+		tlsConfig.NextProtos = []string{"http/1.1"}
+	}
+
+	dial := restConfig.Dial
+	if dial == nil {
+		dial = (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: conf.KeepAlive,
+		}).DialContext
+	}
+
+	transport := &http.Transport{
+		Proxy:               restConfig.Proxy,
+		DialContext:         dial,
+		TLSClientConfig:     tlsConfig,
+		MaxIdleConnsPerHost: conf.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     conf.MaxConnsPerHost,
+		IdleConnTimeout:     conf.IdleConnTimeout,
+	}
+	if conf.DisableHTTP2 {
+		// A non-nil empty map disables HTTP/2 over plain text and TLS.
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	restConfig.Transport = utilnet.SetTransportDefaults(transport)
+
+	restConfig.TLSClientConfig = rest.TLSClientConfig{}
+	restConfig.Dial = nil
+	restConfig.Proxy = nil
+	return nil
+}
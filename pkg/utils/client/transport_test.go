@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestConfigureTransport(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	caData := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: srv.Certificate().Raw,
+	})
+
+	tests := []struct {
+		name      string
+		conf      TransportConfig
+		wantProto string
+	}{
+		{
+			name: "http2",
+			conf: TransportConfig{
+				MaxIdleConnsPerHost: 100,
+				KeepAlive:           30 * time.Second,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			wantProto: "HTTP/2.0",
+		},
+		{
+			name: "disable http2",
+			conf: TransportConfig{
+				MaxIdleConnsPerHost: 100,
+				MaxConnsPerHost:     10,
+				DisableHTTP2:        true,
+			},
+			wantProto: "HTTP/1.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restConfig := &rest.Config{
+				Host: srv.URL,
+				TLSClientConfig: rest.TLSClientConfig{
+					CAData: caData,
+				},
+			}
+			err := ConfigureTransport(restConfig, tt.conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			transport, ok := restConfig.Transport.(*http.Transport)
+			if !ok {
+				t.Fatalf("want *http.Transport, got %T", restConfig.Transport)
+			}
+			if transport.MaxIdleConnsPerHost != tt.conf.MaxIdleConnsPerHost {
+				t.Errorf("want MaxIdleConnsPerHost %d, got %d", tt.conf.MaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+			}
+			if transport.MaxConnsPerHost != tt.conf.MaxConnsPerHost {
+				t.Errorf("want MaxConnsPerHost %d, got %d", tt.conf.MaxConnsPerHost, transport.MaxConnsPerHost)
+			}
+			if len(restConfig.TLSClientConfig.CAData) != 0 {
+				t.Errorf("want TLS options moved into the transport")
+			}
+
+			httpClient, err := rest.HTTPClientFor(restConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := httpClient.Get(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = resp.Body.Close()
+			}()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != tt.wantProto {
+				t.Errorf("want proto %s, got %s", tt.wantProto, body)
+			}
+		})
+	}
+
+	t.Run("custom transport", func(t *testing.T) {
+		restConfig := &rest.Config{
+			Host:      srv.URL,
+			Transport: http.DefaultTransport,
+		}
+		err := ConfigureTransport(restConfig, TransportConfig{})
+		if err == nil {
+			t.Errorf("want error for the rest config with a custom transport")
+		}
+	})
+}
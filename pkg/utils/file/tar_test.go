@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTarGz(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	src := filepath.Join(dir, "dump")
+	files := map[string]string{
+		"config.yaml":                   "kind: KwokctlConfiguration",
+		"components/kube-apiserver.log": "log line",
+	}
+	for name, content := range files {
+		p := filepath.Join(src, name)
+		err := MkdirAll(filepath.Dir(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = Write(p, []byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dest := filepath.Join(dir, "dump.tar.gz")
+	err := TarGz(ctx, src, dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "out")
+	err = untargz(ctx, dest, func(file string) (string, bool) {
+		return filepath.Join(out, file), true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		got, err := os.ReadFile(filepath.Join(out, "dump", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != content {
+			t.Errorf("want %q for %s, got %q", content, name, got)
+		}
+	}
+}
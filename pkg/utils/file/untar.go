@@ -42,6 +42,18 @@ func untar(ctx context.Context, src string, filter func(file string) (string, bo
 	return fmt.Errorf("unsupported archive format: %s", src)
 }
 
+// UntarGz extracts the regular files in the src tarball into the dest directory,
+// the files that would be extracted out of dest are skipped.
+func UntarGz(ctx context.Context, src, dest string) error {
+	return untargz(ctx, src, func(file string) (string, bool) {
+		name := filepath.Join(dest, file)
+		if !strings.HasPrefix(name, filepath.Clean(dest)+string(filepath.Separator)) {
+			return "", false
+		}
+		return name, true
+	})
+}
+
 func unzip(ctx context.Context, src string, filter func(file string) (string, bool)) error {
 	logger := log.FromContext(ctx)
 	r, err := zip.OpenReader(src)
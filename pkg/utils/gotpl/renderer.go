@@ -56,6 +56,17 @@ func NewRenderer(funcMap FuncMap) Renderer {
 	}
 }
 
+// Validate parses the template with the default functions and the given functions,
+// it reports the syntax errors and the undefined functions without rendering.
+func Validate(text string, funcMap FuncMap) error {
+	_, err := template.New("_").
+		Funcs(genericFuncs).
+		Funcs(defaultFuncs).
+		Funcs(funcMap).
+		Parse(strings.TrimSpace(text))
+	return err
+}
+
 func (r *renderer) render(buf *bytes.Buffer, text string, original interface{}) error {
 	text = strings.TrimSpace(text)
 	temp, ok := r.cache.Load(text)
@@ -90,3 +90,44 @@ func TestRenderToJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		funcMap   template.FuncMap
+		templText string
+		wantErr   bool
+	}{
+		{
+			name:      "valid",
+			templText: `phase: {{ .status.phase | Quote }}`,
+		},
+		{
+			name: "with funcMap",
+			funcMap: template.FuncMap{
+				"Foo": func() string {
+					return "foo"
+				},
+			},
+			templText: `foo: {{ Foo }}`,
+		},
+		{
+			name:      "unclosed action",
+			templText: `phase: {{ .status.phase`,
+			wantErr:   true,
+		},
+		{
+			name:      "undefined function",
+			templText: `foo: {{ Foo }}`,
+			wantErr:   true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(tc.templText, tc.funcMap)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
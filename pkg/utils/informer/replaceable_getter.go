@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informer
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ReplaceableGetter is a Getter that delegates to another Getter which can be replaced,
+// so that the watch behind it can be restarted with different options.
+type ReplaceableGetter[T runtime.Object] struct {
+	mut    sync.RWMutex
+	getter Getter[T]
+}
+
+// NewReplaceableGetter returns a new ReplaceableGetter.
+func NewReplaceableGetter[T runtime.Object](getter Getter[T]) *ReplaceableGetter[T] {
+	return &ReplaceableGetter[T]{
+		getter: getter,
+	}
+}
+
+// Replace replaces the underlying getter and returns the previous one.
+func (r *ReplaceableGetter[T]) Replace(getter Getter[T]) Getter[T] {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	old := r.getter
+	r.getter = getter
+	return old
+}
+
+func (r *ReplaceableGetter[T]) current() Getter[T] {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+	return r.getter
+}
+
+// Get returns the resource with the given name.
+func (r *ReplaceableGetter[T]) Get(name string) (T, bool) {
+	return r.current().Get(name)
+}
+
+// GetWithNamespace returns the resource with the given name and namespace.
+func (r *ReplaceableGetter[T]) GetWithNamespace(name, namespace string) (T, bool) {
+	return r.current().GetWithNamespace(name, namespace)
+}
+
+// List returns all resources.
+func (r *ReplaceableGetter[T]) List() []T {
+	return r.current().List()
+}
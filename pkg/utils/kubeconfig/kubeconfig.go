@@ -104,6 +104,17 @@ func RemoveContext(kubeconfigPath, contextName string) error {
 	})
 }
 
+// UseContext sets the current-context in the kubeconfig file
+func UseContext(kubeconfigPath, contextName string) error {
+	return ModifyContext(kubeconfigPath, func(kubeconfig *clientcmdapi.Config) error {
+		if _, ok := kubeconfig.Contexts[contextName]; !ok {
+			return fmt.Errorf("context %q not found in %s", contextName, kubeconfigPath)
+		}
+		kubeconfig.CurrentContext = contextName
+		return nil
+	})
+}
+
 // ModifyContext modifies the kubeconfig file
 func ModifyContext(kubeconfigPath string, fun func(kubeconfig *clientcmdapi.Config) error) error {
 	// load kubeconfig file
@@ -103,3 +103,44 @@ users: null
 		t.Errorf("got %q, want %q", string(got), want)
 	}
 }
+
+func TestUseContext(t *testing.T) {
+	kubeconfigPath := "./test/kubeconfig"
+	defer func() {
+		_ = os.Remove(kubeconfigPath)
+	}()
+	_ = os.WriteFile(kubeconfigPath, []byte(`apiVersion: v1
+clusters:
+- cluster:
+    server: http://127.0.0.1
+  name: test-cluster
+contexts:
+- context:
+    cluster: test-cluster
+    user: ""
+  name: test-cluster
+current-context: ""
+kind: Config
+preferences: {}
+users: null
+`), 0640)
+
+	err := UseContext(kubeconfigPath, "not-found")
+	if err == nil {
+		t.Errorf("want error for context not found")
+	}
+
+	err = UseContext(kubeconfigPath, "test-cluster")
+	if err != nil {
+		t.Errorf("failed to use context: %v", err)
+	}
+
+	want := testKubeconfig
+	got, err := os.ReadFile(kubeconfigPath)
+	if err != nil {
+		t.Errorf("failed to read kubeconfig file: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("got %q, want %q", string(got), want)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Counter counts how many times the stages with a MaxCount are played for each resource,
+// so that the exhausted stages are no longer matched for the resource.
+type Counter struct {
+	mut    sync.Mutex
+	counts map[types.UID]map[string]uint
+}
+
+// NewCounter returns a new Counter.
+func NewCounter() *Counter {
+	return &Counter{
+		counts: map[types.UID]map[string]uint{},
+	}
+}
+
+// Inc records that the stage is played for the resource.
+func (c *Counter) Inc(uid types.UID, stage *Stage) {
+	if stage.maxCount == 0 {
+		return
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	counts, ok := c.counts[uid]
+	if !ok {
+		counts = map[string]uint{}
+		c.counts[uid] = counts
+	}
+	counts[stage.name]++
+}
+
+// Dec reverts a record of Inc, it is called when the stage fails to be played for the resource.
+func (c *Counter) Dec(uid types.UID, stage *Stage) {
+	if stage.maxCount == 0 {
+		return
+	}
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	counts, ok := c.counts[uid]
+	if !ok || counts[stage.name] == 0 {
+		return
+	}
+	counts[stage.name]--
+	if counts[stage.name] == 0 {
+		delete(counts, stage.name)
+	}
+	if len(counts) == 0 {
+		delete(c.counts, uid)
+	}
+}
+
+// Track records that the stage is played for the resource, and returns the function to be deferred
+// which reverts the record if the stage fails to be played, i.e. *err is not nil when the function is called.
+// The stage is counted before it is played, so the events of its own patches already see it exhausted.
+//
+//	defer c.Track(uid, stage, &err)()
+func (c *Counter) Track(uid types.UID, stage *Stage, err *error) func() {
+	c.Inc(uid, stage)
+	return func() {
+		if *err != nil {
+			c.Dec(uid, stage)
+		}
+	}
+}
+
+// Forget drops the counts of the resource, it is called when the resource is deleted.
+func (c *Counter) Forget(uid types.UID) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	delete(c.counts, uid)
+}
+
+// Filter returns the lifecycle without the stages that are exhausted for the resource.
+func (c *Counter) Filter(uid types.UID, lc Lifecycle) Lifecycle {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	counts, ok := c.counts[uid]
+	if !ok {
+		return lc
+	}
+
+	var out Lifecycle
+	for i, stage := range lc {
+		if stage.maxCount == 0 || counts[stage.name] < stage.maxCount {
+			if out != nil {
+				out = append(out, stage)
+			}
+			continue
+		}
+		if out == nil {
+			out = make(Lifecycle, i, len(lc))
+			copy(out, lc[:i])
+		}
+	}
+	if out == nil {
+		return lc
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+)
+
+func TestCounter(t *testing.T) {
+	newStage := func(name string, maxCount int) *internalversion.Stage {
+		return &internalversion.Stage{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: internalversion.StageSpec{
+				Selector: &internalversion.StageSelector{},
+				MaxCount: maxCount,
+			},
+		}
+	}
+	lc, err := NewLifecycle([]*internalversion.Stage{
+		newStage("delete-once", 1),
+		newStage("unlimited", 0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := func(lc Lifecycle) []string {
+		out := []string{}
+		for _, stage := range lc {
+			out = append(out, stage.Name())
+		}
+		return out
+	}
+
+	counter := NewCounter()
+	if got := names(counter.Filter("a", lc)); len(got) != 2 {
+		t.Fatalf("want all stages before playing, got %v", got)
+	}
+
+	counter.Inc("a", lc[0])
+	counter.Inc("a", lc[1])
+	counter.Inc("a", lc[1])
+	if got := names(counter.Filter("a", lc)); len(got) != 1 || got[0] != "unlimited" {
+		t.Errorf("want the exhausted stage filtered out, got %v", got)
+	}
+	if got := names(counter.Filter("b", lc)); len(got) != 2 {
+		t.Errorf("want the stages of another resource kept, got %v", got)
+	}
+	if len(lc) != 2 {
+		t.Errorf("want the lifecycle unchanged, got %v", names(lc))
+	}
+
+	stage, err := counter.Filter("a", lc).Match(context.Background(), nil, nil, map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stage == nil || stage.Name() != "unlimited" {
+		t.Errorf("want the unlimited stage matched, got %v", stage)
+	}
+
+	counter.Dec("a", lc[0])
+	if got := names(counter.Filter("a", lc)); len(got) != 2 {
+		t.Errorf("want the stage back after uncounting the failed play, got %v", got)
+	}
+	counter.Dec("a", lc[0])
+	counter.Inc("a", lc[0])
+	if got := names(counter.Filter("a", lc)); len(got) != 1 || got[0] != "unlimited" {
+		t.Errorf("want the count never below zero, got %v", got)
+	}
+
+	counter.Forget("a")
+	if got := names(counter.Filter("a", lc)); len(got) != 2 {
+		t.Errorf("want all stages after forgetting, got %v", got)
+	}
+
+	play := func(uid types.UID, playErr error) (err error) {
+		defer counter.Track(uid, lc[0], &err)()
+		if got := names(counter.Filter(uid, lc)); len(got) != 1 {
+			t.Errorf("want the stage counted while playing, got %v", got)
+		}
+		return playErr
+	}
+	_ = play("c", errors.New("failed"))
+	if got := names(counter.Filter("c", lc)); len(got) != 2 {
+		t.Errorf("want the stage uncounted after a failed play, got %v", got)
+	}
+	_ = play("c", nil)
+	if got := names(counter.Filter("c", lc)); len(got) != 1 || got[0] != "unlimited" {
+		t.Errorf("want the stage counted after a successful play, got %v", got)
+	}
+}
@@ -19,14 +19,21 @@ package lifecycle
 import (
 	"context"
 	"fmt"
+	"maps"
 	"math/rand"
+	"sync"
 	"time"
 
+	celgo "github.com/google/cel-go/cel"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
 	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/utils/cel"
 	"sigs.k8s.io/kwok/pkg/utils/expression"
 	"sigs.k8s.io/kwok/pkg/utils/format"
+	"sigs.k8s.io/kwok/pkg/utils/gotpl"
+	"sigs.k8s.io/kwok/pkg/utils/slices"
 )
 
 // NewLifecycle returns a new Lifecycle.
@@ -190,6 +197,84 @@ func (s Lifecycle) Match(ctx context.Context, label, annotation labels.Set, data
 	return stages[len(stages)-1], nil
 }
 
+// NewPodConditionsEnvironment returns the CEL environment in which the expressions of the conditions of pods are evaluated
+func NewPodConditionsEnvironment() (*cel.Environment, error) {
+	return cel.NewEnvironment(cel.EnvironmentConfig{
+		Types:       slices.Clone(cel.DefaultTypes),
+		Conversions: slices.Clone(cel.DefaultConversions),
+		Funcs:       maps.Clone(cel.DefaultFuncs),
+		Methods:     maps.Clone(cel.FuncsToMethods(cel.DefaultFuncs)),
+		Vars: map[string]any{
+			"pod": corev1.Pod{},
+		},
+	})
+}
+
+// podConditionsEnvironment is the environment the expressions of the conditions are checked in by ValidateStage
+var podConditionsEnvironment = sync.OnceValues(NewPodConditionsEnvironment)
+
+// ValidateStage parses all templates and expressions of the stage with the given functions,
+// so that a malformed one is reported when the stage is loaded instead of when it is played.
+func ValidateStage(s *internalversion.Stage, funcMap gotpl.FuncMap) error {
+	next := s.Spec.Next
+	if len(next.Conditions) != 0 {
+		err := validateConditions(s.Spec.ResourceRef, next.Conditions)
+		if err != nil {
+			return fmt.Errorf("stage %q: %w", s.Name, err)
+		}
+	}
+	for i, patch := range next.Patches {
+		err := gotpl.Validate(patch.Template, funcMap)
+		if err != nil {
+			return fmt.Errorf("stage %q: invalid template of patches[%d]: %w", s.Name, i, err)
+		}
+	}
+	if webhook := next.Webhook; webhook != nil && webhook.Template != "" {
+		err := gotpl.Validate(webhook.Template, funcMap)
+		if err != nil {
+			return fmt.Errorf("stage %q: invalid template of webhook: %w", s.Name, err)
+		}
+	}
+	if statusMessage := next.StatusMessage; statusMessage != nil {
+		err := gotpl.Validate(statusMessage.Reason, funcMap)
+		if err != nil {
+			return fmt.Errorf("stage %q: invalid template of statusMessage.reason: %w", s.Name, err)
+		}
+		err = gotpl.Validate(statusMessage.Message, funcMap)
+		if err != nil {
+			return fmt.Errorf("stage %q: invalid template of statusMessage.message: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateConditions checks that the conditions are set on pods,
+// and that each of them has a type and an expression evaluating to a bool.
+func validateConditions(ref internalversion.StageResourceRef, conditions []internalversion.StageCondition) error {
+	if ref.Kind != "Pod" || (ref.APIGroup != "" && ref.APIGroup != "v1") {
+		return fmt.Errorf("conditions are only supported for pods, not %s", ref.Kind)
+	}
+
+	env, err := podConditionsEnvironment()
+	if err != nil {
+		return err
+	}
+	for i, condition := range conditions {
+		if condition.Type == "" {
+			return fmt.Errorf("conditions[%d]: type is required", i)
+		}
+		typ, err := env.OutputType(condition.Expression)
+		if err != nil {
+			return fmt.Errorf("conditions[%d]: invalid expression: %w", i, err)
+		}
+		// The type of an expression over a field of dynamic type is only known when it is evaluated.
+		if !typ.IsExactType(celgo.BoolType) && !typ.IsExactType(celgo.DynType) {
+			return fmt.Errorf("conditions[%d]: expression must evaluate to a bool, got %s", i, typ)
+		}
+	}
+	return nil
+}
+
 // NewStage returns a new Stage.
 func NewStage(s *internalversion.Stage) (*Stage, error) {
 	stage := &Stage{
@@ -249,6 +334,22 @@ func NewStage(s *internalversion.Stage) (*Stage, error) {
 		}
 	}
 
+	if backdate := s.Spec.Next.StartTimeBackdate; backdate != nil {
+		var durationFrom *string
+		if backdate.DurationFrom != nil {
+			durationFrom = &backdate.DurationFrom.ExpressionFrom
+		}
+		var backdateDuration *time.Duration
+		if backdate.DurationMilliseconds != nil {
+			backdateDuration = format.Ptr(time.Duration(*backdate.DurationMilliseconds) * time.Millisecond)
+		}
+		startTimeBackdate, err := expression.NewDurationFrom(backdateDuration, durationFrom)
+		if err != nil {
+			return nil, err
+		}
+		stage.startTimeBackdate = startTimeBackdate
+	}
+
 	var weightFrom *string
 	if wf := s.Spec.WeightFrom; wf != nil {
 		weightFrom = &wf.ExpressionFrom
@@ -263,6 +364,10 @@ func NewStage(s *internalversion.Stage) (*Stage, error) {
 
 	stage.immediateNextStage = s.Spec.ImmediateNextStage
 
+	if s.Spec.MaxCount > 0 {
+		stage.maxCount = uint(s.Spec.MaxCount)
+	}
+
 	return stage, nil
 }
 
@@ -279,7 +384,11 @@ type Stage struct {
 	duration       expression.DurationGetter
 	jitterDuration expression.DurationGetter
 
+	startTimeBackdate expression.DurationGetter
+
 	immediateNextStage bool
+
+	maxCount uint
 }
 
 func (s *Stage) match(label, annotation labels.Set, jsonStandard interface{}) (bool, error) {
@@ -368,7 +477,9 @@ func (s *Stage) DelayRangePossible(ctx context.Context, v interface{}, now time.
 
 // Next returns the next of the stage.
 func (s *Stage) Next() *Next {
-	return newNext(s.next)
+	next := newNext(s.next)
+	next.startTimeBackdate = s.startTimeBackdate
+	return next
 }
 
 // Name returns the name of the stage
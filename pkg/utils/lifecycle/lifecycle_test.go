@@ -0,0 +1,229 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/utils/gotpl"
+)
+
+func TestValidateStage(t *testing.T) {
+	podRef := internalversion.StageResourceRef{APIGroup: "v1", Kind: "Pod"}
+	tests := []struct {
+		name        string
+		resourceRef internalversion.StageResourceRef
+		next        internalversion.StageNext
+		funcMap     gotpl.FuncMap
+		wantErr     string
+	}{
+		{
+			name: "valid",
+			next: internalversion.StageNext{
+				Patches: []internalversion.StagePatch{
+					{
+						Template: `phase: {{ .status.phase }}`,
+					},
+				},
+			},
+		},
+		{
+			name: "valid with funcMap",
+			next: internalversion.StageNext{
+				Patches: []internalversion.StagePatch{
+					{
+						Template: `podIP: {{ PodIP }}`,
+					},
+				},
+			},
+			funcMap: gotpl.FuncMap{
+				"PodIP": func() string {
+					return ""
+				},
+			},
+		},
+		{
+			name: "malformed patch template",
+			next: internalversion.StageNext{
+				Patches: []internalversion.StagePatch{
+					{
+						Template: `phase: Running`,
+					},
+					{
+						Template: `phase: {{ .status.phase }`,
+					},
+				},
+			},
+			wantErr: `stage "test": invalid template of patches[1]`,
+		},
+		{
+			name: "malformed webhook template",
+			next: internalversion.StageNext{
+				Webhook: &internalversion.StageWebhook{
+					Template: `{{ if .metadata.name }}`,
+				},
+			},
+			wantErr: `stage "test": invalid template of webhook`,
+		},
+		{
+			name: "valid status message",
+			next: internalversion.StageNext{
+				StatusMessage: &internalversion.StageStatusMessage{
+					Reason:  `Evicted`,
+					Message: `Pod {{ .metadata.name }} was evicted from node {{ .spec.nodeName }}`,
+				},
+			},
+		},
+		{
+			name: "malformed status message reason template",
+			next: internalversion.StageNext{
+				StatusMessage: &internalversion.StageStatusMessage{
+					Reason: `{{ .status.reason `,
+				},
+			},
+			wantErr: `stage "test": invalid template of statusMessage.reason`,
+		},
+		{
+			name: "malformed status message message template",
+			next: internalversion.StageNext{
+				StatusMessage: &internalversion.StageStatusMessage{
+					Reason:  `Evicted`,
+					Message: `Pod {{ range .spec.containers }} was evicted`,
+				},
+			},
+			wantErr: `stage "test": invalid template of statusMessage.message`,
+		},
+		{
+			name: "undefined function",
+			next: internalversion.StageNext{
+				Patches: []internalversion.StagePatch{
+					{
+						Template: `podIP: {{ PodIP }}`,
+					},
+				},
+			},
+			wantErr: `function "PodIP" not defined`,
+		},
+		{
+			name:        "valid conditions",
+			resourceRef: podRef,
+			next: internalversion.StageNext{
+				Conditions: []internalversion.StageCondition{
+					{
+						Type:       "Ready",
+						Expression: `size(pod.spec.containers) > 1`,
+					},
+					{
+						Type:       "Annotated",
+						Expression: `"example.com/ready" in pod.metadata.annotations`,
+					},
+				},
+			},
+		},
+		{
+			name:        "malformed condition expression",
+			resourceRef: podRef,
+			next: internalversion.StageNext{
+				Conditions: []internalversion.StageCondition{
+					{
+						Type:       "Ready",
+						Expression: `size(pod.spec.containers) >`,
+					},
+				},
+			},
+			wantErr: `stage "test": conditions[0]: invalid expression`,
+		},
+		{
+			name:        "condition expression of unknown field",
+			resourceRef: podRef,
+			next: internalversion.StageNext{
+				Conditions: []internalversion.StageCondition{
+					{
+						Type:       "Ready",
+						Expression: `pod.spec.unknown`,
+					},
+				},
+			},
+			wantErr: `stage "test": conditions[0]: invalid expression`,
+		},
+		{
+			name:        "condition expression not evaluating to a bool",
+			resourceRef: podRef,
+			next: internalversion.StageNext{
+				Conditions: []internalversion.StageCondition{
+					{
+						Type:       "Ready",
+						Expression: `size(pod.spec.containers)`,
+					},
+				},
+			},
+			wantErr: `stage "test": conditions[0]: expression must evaluate to a bool`,
+		},
+		{
+			name:        "condition without type",
+			resourceRef: podRef,
+			next: internalversion.StageNext{
+				Conditions: []internalversion.StageCondition{
+					{
+						Expression: `true`,
+					},
+				},
+			},
+			wantErr: `stage "test": conditions[0]: type is required`,
+		},
+		{
+			name:        "conditions of node",
+			resourceRef: internalversion.StageResourceRef{APIGroup: "v1", Kind: "Node"},
+			next: internalversion.StageNext{
+				Conditions: []internalversion.StageCondition{
+					{
+						Type:       "Ready",
+						Expression: `true`,
+					},
+				},
+			},
+			wantErr: `stage "test": conditions are only supported for pods, not Node`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stage := &internalversion.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: internalversion.StageSpec{
+					ResourceRef: tt.resourceRef,
+					Next:        tt.next,
+				},
+			}
+			err := ValidateStage(stage, tt.funcMap)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateStage() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidateStage() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -17,12 +17,18 @@ limitations under the License.
 package lifecycle
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/utils/expression"
 	"sigs.k8s.io/kwok/pkg/utils/format"
 	"sigs.k8s.io/kwok/pkg/utils/gotpl"
 )
@@ -30,6 +36,8 @@ import (
 // Next represents the next step in the lifecycle
 type Next struct {
 	next *internalversion.StageNext
+
+	startTimeBackdate expression.DurationGetter
 }
 
 // newNext creates a new Next from the stage
@@ -69,6 +77,79 @@ func (n *Next) Delete() bool {
 	return n.next.Delete
 }
 
+// Conditions returns the conditions computed from CEL expressions
+func (n *Next) Conditions() []internalversion.StageCondition {
+	return n.next.Conditions
+}
+
+// StatusMessage returns the patch to set the reason and message rendered from the templates in the status
+func (n *Next) StatusMessage(resource any, renderer gotpl.Renderer) (*Patch, error) {
+	if n.next.StatusMessage == nil {
+		return nil, nil
+	}
+
+	status := map[string]string{}
+	if n.next.StatusMessage.Reason != "" {
+		reason, err := renderer.ToText(n.next.StatusMessage.Reason, resource)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render reason: %w", err)
+		}
+		status["reason"] = strings.TrimSpace(string(reason))
+	}
+	if n.next.StatusMessage.Message != "" {
+		message, err := renderer.ToText(n.next.StatusMessage.Message, resource)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render message: %w", err)
+		}
+		status["message"] = strings.TrimSpace(string(message))
+	}
+	if len(status) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"status": status,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Patch{
+		Data:        data,
+		Type:        types.StrategicMergePatchType,
+		Subresource: "status",
+	}, nil
+}
+
+// StartTime returns the patch to set the startTime in the status moved into the past by the backdate duration
+func (n *Next) StartTime(ctx context.Context, resource any, now time.Time) (*Patch, error) {
+	if n.startTimeBackdate == nil {
+		return nil, nil
+	}
+
+	data, err := expression.ToJSONStandard(resource)
+	if err != nil {
+		return nil, err
+	}
+	backdate, ok := n.startTimeBackdate.Get(ctx, data, now)
+	if !ok || backdate <= 0 {
+		return nil, nil
+	}
+
+	patchData, err := json.Marshal(map[string]any{
+		"status": map[string]any{
+			"startTime": metav1.NewTime(now.Add(-backdate)),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Patch{
+		Data:        patchData,
+		Type:        types.StrategicMergePatchType,
+		Subresource: "status",
+	}, nil
+}
+
 // Patches returns the patches for the resource
 func (n *Next) Patches(resource any, renderer gotpl.Renderer) ([]*Patch, error) {
 	patches := make([]*Patch, 0, len(n.next.Patches))
@@ -95,6 +176,56 @@ type Patch struct {
 	Impersonation *internalversion.ImpersonationConfig
 }
 
+// MergeStatusPatches merges the strategic merge patches of the status subresource into one,
+// the nil patches are skipped and the later values win on conflicting fields.
+func MergeStatusPatches(patches ...*Patch) (*Patch, error) {
+	merged := map[string]any{}
+	for _, patch := range patches {
+		if patch == nil {
+			continue
+		}
+		if patch.Type != types.StrategicMergePatchType || patch.Subresource != "status" {
+			return nil, fmt.Errorf("unsupported patch %s of subresource %q", patch.Type, patch.Subresource)
+		}
+		var data map[string]any
+		decoder := json.NewDecoder(bytes.NewReader(patch.Data))
+		decoder.UseNumber()
+		err := decoder.Decode(&data)
+		if err != nil {
+			return nil, err
+		}
+		mergeMaps(merged, data)
+	}
+	if len(merged) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	return &Patch{
+		Data:        data,
+		Type:        types.StrategicMergePatchType,
+		Subresource: "status",
+	}, nil
+}
+
+// mergeMaps merges src into dst recursively
+func mergeMaps(dst, src map[string]any) {
+	for key, value := range src {
+		srcMap, ok := value.(map[string]any)
+		if ok {
+			dstMap, ok := dst[key].(map[string]any)
+			if ok {
+				mergeMaps(dstMap, srcMap)
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
+
 func computePatch(renderer gotpl.Renderer, resource any, patch internalversion.StagePatch) ([]byte, types.PatchType, error) {
 	switch format.ElemOrDefault(patch.Type) {
 	case internalversion.StagePatchTypeJSONPatch:
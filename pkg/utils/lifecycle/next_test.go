@@ -0,0 +1,260 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/utils/format"
+	"sigs.k8s.io/kwok/pkg/utils/gotpl"
+)
+
+func TestNextStatusMessage(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-0",
+			Namespace: "default",
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "node-0",
+		},
+	}
+
+	tests := []struct {
+		name          string
+		statusMessage *internalversion.StageStatusMessage
+		want          *Patch
+		wantErr       bool
+	}{
+		{
+			name: "no status message",
+		},
+		{
+			name:          "empty status message",
+			statusMessage: &internalversion.StageStatusMessage{},
+		},
+		{
+			name: "render with pod fields",
+			statusMessage: &internalversion.StageStatusMessage{
+				Reason:  "Evicted",
+				Message: "Pod {{ .metadata.namespace }}/{{ .metadata.name }} was evicted from node {{ .spec.nodeName }}",
+			},
+			want: &Patch{
+				Data:        []byte(`{"status":{"message":"Pod default/pod-0 was evicted from node node-0","reason":"Evicted"}}`),
+				Type:        types.StrategicMergePatchType,
+				Subresource: "status",
+			},
+		},
+		{
+			name: "only message",
+			statusMessage: &internalversion.StageStatusMessage{
+				Message: "{{ .spec.nodeName }} is gone",
+			},
+			want: &Patch{
+				Data:        []byte(`{"status":{"message":"node-0 is gone"}}`),
+				Type:        types.StrategicMergePatchType,
+				Subresource: "status",
+			},
+		},
+		{
+			name: "invalid template",
+			statusMessage: &internalversion.StageStatusMessage{
+				Message: "{{ .metadata.name ",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := newNext(&internalversion.StageNext{
+				StatusMessage: tt.statusMessage,
+			})
+			got, err := next.StatusMessage(pod, gotpl.NewRenderer(nil))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("StatusMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StatusMessage() got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextStartTime(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-0",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"age": "72h",
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		backdate *internalversion.StageStartTimeBackdate
+		want     *Patch
+	}{
+		{
+			name: "no backdate",
+		},
+		{
+			name:     "zero backdate",
+			backdate: &internalversion.StageStartTimeBackdate{DurationMilliseconds: format.Ptr[int64](0)},
+		},
+		{
+			name:     "backdate with duration",
+			backdate: &internalversion.StageStartTimeBackdate{DurationMilliseconds: format.Ptr[int64](90 * 60 * 1000)},
+			want: &Patch{
+				Data:        []byte(`{"status":{"startTime":"2024-01-02T01:34:05Z"}}`),
+				Type:        types.StrategicMergePatchType,
+				Subresource: "status",
+			},
+		},
+		{
+			name: "backdate from annotation",
+			backdate: &internalversion.StageStartTimeBackdate{
+				DurationMilliseconds: format.Ptr[int64](1000),
+				DurationFrom: &internalversion.ExpressionFromSource{
+					ExpressionFrom: `.metadata.annotations["age"]`,
+				},
+			},
+			want: &Patch{
+				Data:        []byte(`{"status":{"startTime":"2023-12-30T03:04:05Z"}}`),
+				Type:        types.StrategicMergePatchType,
+				Subresource: "status",
+			},
+		},
+		{
+			name: "fallback to duration",
+			backdate: &internalversion.StageStartTimeBackdate{
+				DurationMilliseconds: format.Ptr[int64](5000),
+				DurationFrom: &internalversion.ExpressionFromSource{
+					ExpressionFrom: `.metadata.annotations["missing"]`,
+				},
+			},
+			want: &Patch{
+				Data:        []byte(`{"status":{"startTime":"2024-01-02T03:04:00Z"}}`),
+				Type:        types.StrategicMergePatchType,
+				Subresource: "status",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stage, err := NewStage(&internalversion.Stage{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pod-ready",
+				},
+				Spec: internalversion.StageSpec{
+					Selector: &internalversion.StageSelector{},
+					Next: internalversion.StageNext{
+						StartTimeBackdate: tt.backdate,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("NewStage() error = %v", err)
+			}
+			got, err := stage.Next().StartTime(context.Background(), pod, now)
+			if err != nil {
+				t.Fatalf("StartTime() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StartTime() got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeStatusPatches(t *testing.T) {
+	statusPatch := func(data string) *Patch {
+		return &Patch{
+			Data:        []byte(data),
+			Type:        types.StrategicMergePatchType,
+			Subresource: "status",
+		}
+	}
+
+	tests := []struct {
+		name    string
+		patches []*Patch
+		want    *Patch
+		wantErr bool
+	}{
+		{
+			name:    "no patches",
+			patches: []*Patch{nil, nil},
+		},
+		{
+			name: "one patch",
+			patches: []*Patch{
+				nil,
+				statusPatch(`{"status":{"startTime":"2024-01-02T01:34:05Z"}}`),
+			},
+			want: statusPatch(`{"status":{"startTime":"2024-01-02T01:34:05Z"}}`),
+		},
+		{
+			name: "merge conditions, message and start time",
+			patches: []*Patch{
+				statusPatch(`{"status":{"conditions":[{"status":"True","type":"Ready"}]}}`),
+				statusPatch(`{"status":{"message":"running","reason":"Started"}}`),
+				statusPatch(`{"status":{"startTime":"2024-01-02T01:34:05Z"}}`),
+			},
+			want: statusPatch(`{"status":{"conditions":[{"status":"True","type":"Ready"}],"message":"running","reason":"Started","startTime":"2024-01-02T01:34:05Z"}}`),
+		},
+		{
+			name: "later wins",
+			patches: []*Patch{
+				statusPatch(`{"status":{"reason":"Created"}}`),
+				statusPatch(`{"status":{"reason":"Started"}}`),
+			},
+			want: statusPatch(`{"status":{"reason":"Started"}}`),
+		},
+		{
+			name: "not a status patch",
+			patches: []*Patch{
+				{
+					Data: []byte(`[{"op":"add","path":"/metadata/finalizers","value":["kwok.x-k8s.io/fake"]}]`),
+					Type: types.JSONPatchType,
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergeStatusPatches(tt.patches...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MergeStatusPatches() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeStatusPatches() got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
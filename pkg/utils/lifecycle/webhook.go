@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/utils/format"
+	"sigs.k8s.io/kwok/pkg/utils/gotpl"
+	"sigs.k8s.io/kwok/pkg/utils/wait"
+)
+
+const (
+	defaultWebhookTimeout = 10 * time.Second
+	webhookRetryInterval  = 100 * time.Millisecond
+)
+
+// HasWebhook returns whether the next has a webhook
+func (n *Next) HasWebhook() bool {
+	return n.next.Webhook != nil
+}
+
+// Webhook sends the resource to the webhook and returns the patch returned by the webhook.
+// It returns nil if the webhook returns an empty body.
+func (n *Next) Webhook(ctx context.Context, resource any, renderer gotpl.Renderer, client *http.Client) (*Patch, error) {
+	webhook := n.next.Webhook
+	if webhook == nil {
+		return nil, nil
+	}
+
+	patchType, err := webhookPatchType(webhook.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	if webhook.Template == "" {
+		body, err = json.Marshal(resource)
+	} else {
+		body, err = renderer.ToJSON(webhook.Template, resource)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to render webhook body: %w", err)
+	}
+
+	timeout := defaultWebhookTimeout
+	if webhook.TimeoutMilliseconds != nil && *webhook.TimeoutMilliseconds > 0 {
+		timeout = time.Duration(*webhook.TimeoutMilliseconds) * time.Millisecond
+	}
+
+	backoff := wait.Backoff{
+		Duration: webhookRetryInterval,
+		Factor:   2,
+		Jitter:   0.1,
+		Steps:    webhook.Retries + 1,
+	}
+
+	var (
+		data    []byte
+		lastErr error
+	)
+	err = wait.Poll(ctx, func(ctx context.Context) (bool, error) {
+		data, lastErr = doWebhookRequest(ctx, client, webhook.URL, body, timeout)
+		return lastErr == nil, nil
+	}, wait.WithExponentialBackoff(&backoff))
+	if err != nil {
+		if lastErr != nil {
+			return nil, fmt.Errorf("failed to call webhook %s: %w", webhook.URL, lastErr)
+		}
+		return nil, err
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	return &Patch{
+		Data:        data,
+		Type:        patchType,
+		Subresource: webhook.Subresource,
+	}, nil
+}
+
+func doWebhookRequest(ctx context.Context, client *http.Client, url string, body []byte, timeout time.Duration) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, data)
+	}
+	return data, nil
+}
+
+func webhookPatchType(typ *internalversion.StagePatchType) (types.PatchType, error) {
+	switch format.ElemOrDefault(typ) {
+	case internalversion.StagePatchTypeJSONPatch:
+		return types.JSONPatchType, nil
+	case internalversion.StagePatchTypeStrategicMergePatch:
+		return types.StrategicMergePatchType, nil
+	case internalversion.StagePatchTypeMergePatch, "":
+		return types.MergePatchType, nil
+	}
+	return "", fmt.Errorf("unknown patch type %s", *typ)
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/kwok/pkg/apis/internalversion"
+	"sigs.k8s.io/kwok/pkg/utils/format"
+	"sigs.k8s.io/kwok/pkg/utils/gotpl"
+)
+
+func TestNextWebhook(t *testing.T) {
+	resource := map[string]any{
+		"metadata": map[string]any{
+			"name": "pod",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		webhook  *internalversion.StageWebhook
+		handler  func(failures *int32) http.HandlerFunc
+		wantBody string
+		want     *Patch
+		wantErr  bool
+	}{
+		{
+			name:    "no webhook",
+			webhook: nil,
+		},
+		{
+			name:    "send resource",
+			webhook: &internalversion.StageWebhook{},
+			handler: func(_ *int32) http.HandlerFunc {
+				return func(w http.ResponseWriter, _ *http.Request) {
+					_, _ = w.Write([]byte(`{"status":{"phase":"Running"}}`))
+				}
+			},
+			wantBody: `{"metadata":{"name":"pod"}}`,
+			want: &Patch{
+				Data: []byte(`{"status":{"phase":"Running"}}`),
+				Type: types.MergePatchType,
+			},
+		},
+		{
+			name: "send template",
+			webhook: &internalversion.StageWebhook{
+				Template:    `name: {{ .metadata.name }}`,
+				Subresource: "status",
+				Type:        format.Ptr(internalversion.StagePatchTypeJSONPatch),
+			},
+			handler: func(_ *int32) http.HandlerFunc {
+				return func(w http.ResponseWriter, _ *http.Request) {
+					_, _ = w.Write([]byte(`[{"op":"add","path":"/status/phase","value":"Running"}]`))
+				}
+			},
+			wantBody: `{"name":"pod"}`,
+			want: &Patch{
+				Data:        []byte(`[{"op":"add","path":"/status/phase","value":"Running"}]`),
+				Type:        types.JSONPatchType,
+				Subresource: "status",
+			},
+		},
+		{
+			name: "empty response",
+			webhook: &internalversion.StageWebhook{
+				Retries: 1,
+			},
+			handler: func(_ *int32) http.HandlerFunc {
+				return func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusNoContent)
+				}
+			},
+			wantBody: `{"metadata":{"name":"pod"}}`,
+		},
+		{
+			name: "retry",
+			webhook: &internalversion.StageWebhook{
+				Retries: 2,
+			},
+			handler: func(failures *int32) http.HandlerFunc {
+				return func(w http.ResponseWriter, _ *http.Request) {
+					if atomic.AddInt32(failures, 1) < 3 {
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					_, _ = w.Write([]byte(`{}`))
+				}
+			},
+			wantBody: `{"metadata":{"name":"pod"}}`,
+			want: &Patch{
+				Data: []byte(`{}`),
+				Type: types.MergePatchType,
+			},
+		},
+		{
+			name: "retries exhausted",
+			webhook: &internalversion.StageWebhook{
+				Retries: 1,
+			},
+			handler: func(_ *int32) http.HandlerFunc {
+				return func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			},
+			wantBody: `{"metadata":{"name":"pod"}}`,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBody string
+			if tt.handler != nil {
+				var failures int32
+				handler := tt.handler(&failures)
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					body, _ := io.ReadAll(r.Body)
+					gotBody = string(body)
+					handler(w, r)
+				}))
+				defer server.Close()
+				tt.webhook.URL = server.URL
+			}
+
+			next := newNext(&internalversion.StageNext{
+				Webhook: tt.webhook,
+			})
+			got, err := next.Webhook(context.Background(), resource, gotpl.NewRenderer(nil), http.DefaultClient)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Webhook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.handler != nil && gotBody != tt.wantBody {
+				t.Errorf("Webhook() body = %s, want %s", gotBody, tt.wantBody)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Webhook() got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -18,7 +18,9 @@ package net
 
 import (
 	"encoding/binary"
+	"fmt"
 	"net"
+	"strings"
 )
 
 // GetAllIPs returns all IPs of the host.
@@ -46,6 +48,11 @@ func GetAllIPs() ([]string, error) {
 	return ips, nil
 }
 
+// IsIP returns true if the string is an IP.
+func IsIP(s string) bool {
+	return net.ParseIP(s) != nil
+}
+
 // AddIP adds or subtracts the IP.
 func AddIP(ip net.IP, add uint64) net.IP {
 	if len(ip) < 8 || add == 0 {
@@ -72,6 +79,38 @@ func ParseCIDR(s string) (*net.IPNet, error) {
 	return ipnet, nil
 }
 
+// ParseDualStackCIDRs parses a comma-separated list of CIDRs,
+// which is a single CIDR or a pair of CIDRs with different IP families.
+func ParseDualStackCIDRs(s string) ([]*net.IPNet, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) > 2 {
+		return nil, fmt.Errorf("expected at most 2 CIDRs, got %d", len(parts))
+	}
+
+	cidrs := make([]*net.IPNet, 0, len(parts))
+	for _, part := range parts {
+		_, ipnet, err := net.ParseCIDR(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		cidrs = append(cidrs, ipnet)
+	}
+
+	if len(cidrs) == 2 && (cidrs[0].IP.To4() == nil) == (cidrs[1].IP.To4() == nil) {
+		return nil, fmt.Errorf("expected CIDRs with different IP families, got %q", s)
+	}
+	return cidrs, nil
+}
+
+// JoinCIDRs joins the CIDRs into a comma-separated list.
+func JoinCIDRs(cidrs []*net.IPNet) string {
+	parts := make([]string, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		parts = append(parts, cidr.String())
+	}
+	return strings.Join(parts, ",")
+}
+
 // AddCIDR adds the CIDR.
 func AddCIDR(cidr string, index int) (string, error) {
 	ipnet, err := ParseCIDR(cidr)
@@ -17,6 +17,7 @@ limitations under the License.
 package net
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -85,3 +86,67 @@ func TestAddCIDR(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDualStackCIDRs(t *testing.T) {
+	tests := []struct {
+		name    string
+		cidrs   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "ipv4",
+			cidrs: "10.96.0.0/12",
+			want:  []string{"10.96.0.0/12"},
+		},
+		{
+			name:  "ipv6",
+			cidrs: "fd00:10:96::/112",
+			want:  []string{"fd00:10:96::/112"},
+		},
+		{
+			name:  "dual-stack",
+			cidrs: "10.96.0.0/12,fd00:10:96::/112",
+			want:  []string{"10.96.0.0/12", "fd00:10:96::/112"},
+		},
+		{
+			name:  "dual-stack with spaces",
+			cidrs: "10.96.0.0/12, fd00:10:96::/112",
+			want:  []string{"10.96.0.0/12", "fd00:10:96::/112"},
+		},
+		{
+			name:    "same ip family",
+			cidrs:   "10.96.0.0/12,10.112.0.0/12",
+			wantErr: true,
+		},
+		{
+			name:    "too many",
+			cidrs:   "10.96.0.0/12,fd00:10:96::/112,10.112.0.0/12",
+			wantErr: true,
+		},
+		{
+			name:    "invalid",
+			cidrs:   "10.96.0.0",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDualStackCIDRs(tt.cidrs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDualStackCIDRs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseDualStackCIDRs() got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].String() != tt.want[i] {
+					t.Errorf("ParseDualStackCIDRs() got %v, want %v", got[i], tt.want[i])
+				}
+			}
+			if joined := JoinCIDRs(got); !tt.wantErr && joined != strings.Join(tt.want, ",") {
+				t.Errorf("JoinCIDRs() got %q, want %q", joined, strings.Join(tt.want, ","))
+			}
+		})
+	}
+}
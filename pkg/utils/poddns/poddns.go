@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poddns
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// The limits of the dnsConfig of pods, which are the same as the ones validated by kube-apiserver.
+const (
+	maxDNSNameservers     = 3
+	maxDNSSearchPaths     = 32
+	maxDNSSearchListChars = 2048
+	optionSeparator       = ":"
+)
+
+// Config is the DNS settings set on the pods if they do not have one.
+// It only makes the pods look like the real ones, the names are not resolved.
+type Config struct {
+	Policy      string
+	Nameservers []string
+	Searches    []string
+	Options     []string
+}
+
+// IsEmpty returns whether there is nothing to set on the pods
+func (c Config) IsEmpty() bool {
+	return c.Policy == "" && len(c.Nameservers) == 0 && len(c.Searches) == 0 && len(c.Options) == 0
+}
+
+// Validate returns an error if the DNS settings are not accepted by kube-apiserver
+func (c Config) Validate() error {
+	switch corev1.DNSPolicy(c.Policy) {
+	case "", corev1.DNSClusterFirstWithHostNet, corev1.DNSClusterFirst, corev1.DNSDefault:
+	case corev1.DNSNone:
+		if len(c.Nameservers) == 0 {
+			return fmt.Errorf("at least one nameserver is required when the dns policy is %q", corev1.DNSNone)
+		}
+	default:
+		return fmt.Errorf("unsupported dns policy %q, must be one of %s, %s, %s or %s", c.Policy,
+			corev1.DNSClusterFirstWithHostNet, corev1.DNSClusterFirst, corev1.DNSDefault, corev1.DNSNone)
+	}
+
+	if len(c.Nameservers) > maxDNSNameservers {
+		return fmt.Errorf("must not have more than %d nameservers: %v", maxDNSNameservers, c.Nameservers)
+	}
+	for _, ns := range c.Nameservers {
+		if net.ParseIP(ns) == nil {
+			return fmt.Errorf("nameserver %q must be a valid IP address", ns)
+		}
+	}
+
+	if len(c.Searches) > maxDNSSearchPaths {
+		return fmt.Errorf("must not have more than %d search paths", maxDNSSearchPaths)
+	}
+	if n := len(strings.Join(c.Searches, " ")); n > maxDNSSearchListChars {
+		return fmt.Errorf("must not have more than %d characters (including spaces) in the search list", maxDNSSearchListChars)
+	}
+	for _, search := range c.Searches {
+		// A trailing dot is allowed for the fully qualified domain name.
+		errs := validation.IsDNS1123Subdomain(strings.TrimSuffix(search, "."))
+		if len(errs) != 0 {
+			return fmt.Errorf("search %q is invalid: %s", search, strings.Join(errs, ", "))
+		}
+	}
+
+	for _, option := range c.Options {
+		name, _, _ := strings.Cut(option, optionSeparator)
+		if name == "" {
+			return fmt.Errorf("option %q must have a name", option)
+		}
+	}
+	return nil
+}
+
+// Apply sets the DNS settings on the pod if it does not have a dnsConfig,
+// the dnsPolicy is only replaced if it is unset or the default ClusterFirst,
+// as kube-apiserver defaults it before the pod is admitted.
+// It returns whether the pod is changed.
+func (c Config) Apply(pod *corev1.Pod) bool {
+	if pod.Spec.DNSConfig != nil {
+		return false
+	}
+
+	changed := false
+	if c.Policy != "" &&
+		c.Policy != string(pod.Spec.DNSPolicy) &&
+		(pod.Spec.DNSPolicy == "" || pod.Spec.DNSPolicy == corev1.DNSClusterFirst) {
+		pod.Spec.DNSPolicy = corev1.DNSPolicy(c.Policy)
+		changed = true
+	}
+
+	if len(c.Nameservers) != 0 || len(c.Searches) != 0 || len(c.Options) != 0 {
+		config := &corev1.PodDNSConfig{
+			Nameservers: c.Nameservers,
+			Searches:    c.Searches,
+		}
+		for _, option := range c.Options {
+			name, value, ok := strings.Cut(option, optionSeparator)
+			o := corev1.PodDNSConfigOption{
+				Name: name,
+			}
+			if ok {
+				o.Value = &value
+			}
+			config.Options = append(config.Options, o)
+		}
+		pod.Spec.DNSConfig = config
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poddns
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/kwok/pkg/utils/format"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		dns     Config
+		wantErr bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			dns: Config{
+				Policy:      "None",
+				Nameservers: []string{"10.96.0.10"},
+				Searches:    []string{"default.svc.cluster.local", "cluster.local."},
+				Options:     []string{"ndots:5", "edns0"},
+			},
+		},
+		{
+			name:    "unknown policy",
+			dns:     Config{Policy: "Unknown"},
+			wantErr: true,
+		},
+		{
+			name:    "none policy without nameservers",
+			dns:     Config{Policy: "None"},
+			wantErr: true,
+		},
+		{
+			name:    "too many nameservers",
+			dns:     Config{Nameservers: []string{"1.1.1.1", "1.0.0.1", "8.8.8.8", "8.8.4.4"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid nameserver",
+			dns:     Config{Nameservers: []string{"dns.example.com"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid search",
+			dns:     Config{Searches: []string{"Invalid_Domain"}},
+			wantErr: true,
+		},
+		{
+			name:    "too long search list",
+			dns:     Config{Searches: []string{strings.Repeat("a", 63), strings.Repeat("b.", 1000) + "c"}},
+			wantErr: true,
+		},
+		{
+			name:    "option without name",
+			dns:     Config{Options: []string{":5"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.dns.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigApply(t *testing.T) {
+	dns := Config{
+		Policy:      "None",
+		Nameservers: []string{"10.96.0.10"},
+		Searches:    []string{"cluster.local"},
+		Options:     []string{"ndots:5", "edns0"},
+	}
+	wantConfig := &corev1.PodDNSConfig{
+		Nameservers: []string{"10.96.0.10"},
+		Searches:    []string{"cluster.local"},
+		Options: []corev1.PodDNSConfigOption{
+			{Name: "ndots", Value: format.Ptr("5")},
+			{Name: "edns0"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		spec        corev1.PodSpec
+		wantChanged bool
+		wantSpec    corev1.PodSpec
+	}{
+		{
+			name:        "pod with the default dns",
+			spec:        corev1.PodSpec{DNSPolicy: corev1.DNSClusterFirst},
+			wantChanged: true,
+			wantSpec:    corev1.PodSpec{DNSPolicy: corev1.DNSNone, DNSConfig: wantConfig},
+		},
+		{
+			name: "pod with dns config",
+			spec: corev1.PodSpec{
+				DNSPolicy: corev1.DNSClusterFirst,
+				DNSConfig: &corev1.PodDNSConfig{Searches: []string{"example.com"}},
+			},
+			wantSpec: corev1.PodSpec{
+				DNSPolicy: corev1.DNSClusterFirst,
+				DNSConfig: &corev1.PodDNSConfig{Searches: []string{"example.com"}},
+			},
+		},
+		{
+			name:        "pod with dns policy",
+			spec:        corev1.PodSpec{DNSPolicy: corev1.DNSDefault},
+			wantChanged: true,
+			wantSpec:    corev1.PodSpec{DNSPolicy: corev1.DNSDefault, DNSConfig: wantConfig},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{Spec: tt.spec}
+			changed := dns.Apply(pod)
+			if changed != tt.wantChanged {
+				t.Errorf("want changed %v, got %v", tt.wantChanged, changed)
+			}
+			if !reflect.DeepEqual(pod.Spec, tt.wantSpec) {
+				t.Errorf("want spec %+v, got %+v", tt.wantSpec, pod.Spec)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printers
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteObject(t *testing.T) {
+	obj := map[string]any{
+		"kind": "List",
+		"items": []any{
+			map[string]any{"name": "a"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "yaml",
+			output: OutputYAML,
+			want: `items:
+- name: a
+kind: List
+`,
+		},
+		{
+			name:   "json",
+			output: OutputJSON,
+			want: `{
+  "items": [
+    {
+      "name": "a"
+    }
+  ],
+  "kind": "List"
+}
+`,
+		},
+		{
+			name:    "unknown",
+			output:  "table",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			err := WriteObject(out, tt.output, obj)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteObject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("WriteObject() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -37,6 +37,12 @@ func TestDryrunExportLogs(t *testing.T) {
 	testEnv.Test(t, f0)
 }
 
+func TestDryrunAttach(t *testing.T) {
+	f0 := e2e.CaseDryRunAttach(kwokctlPath, clusterName, runtimeEnv, rootDir, updateTestdata).
+		Feature()
+	testEnv.Test(t, f0)
+}
+
 func TestKwokctlPortForward(t *testing.T) {
 	f0 := e2e.CaseKwokctlPortForward(kwokctlPath, clusterName).
 		Feature()